@@ -0,0 +1,109 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package blob stores the large values offloaded out of the posting lists. Values are content
+// addressed: the key of a value is the hex encoded SHA-256 hash of its bytes, so that putting
+// the same value twice is harmless and a key identifies a single value.
+package blob
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+
+	"github.com/pkg/errors"
+)
+
+// Store is an object store holding the offloaded values. It must be shared by all the Alphas,
+// as the posting lists only keep the keys of the values.
+type Store interface {
+	// Put stores the value under the key.
+	Put(key string, value []byte) error
+	// Get returns the value stored under the key.
+	Get(key string) ([]byte, error)
+}
+
+// store is the store of the offloaded values, nil if offloading isn't enabled.
+var store Store
+
+// Key returns the key of the value in a store.
+func Key(value []byte) string {
+	sum := sha256.Sum256(value)
+	return hex.EncodeToString(sum[:])
+}
+
+// Open returns the store at the URI. Supported URIs are:
+//
+//	file:///path/to/dir or /path/to/dir
+//	s3://s3.amazonaws.com/bucket/prefix?secure=true
+//	minio://localhost:9000/bucket/prefix?secure=false
+func Open(uri string) (Store, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while parsing blob store URI %q", uri)
+	}
+	switch u.Scheme {
+	case "file", "":
+		return newFileStore(u.Path)
+	case "s3", "minio":
+		return newS3Store(u)
+	}
+	return nil, errors.Errorf("Unsupported blob store URI: %q", uri)
+}
+
+// Init opens the store at the URI, which values are offloaded to. An empty URI disables
+// offloading.
+func Init(uri string) error {
+	if uri == "" {
+		store = nil
+		return nil
+	}
+	s, err := Open(uri)
+	if err != nil {
+		return err
+	}
+	store = s
+	return nil
+}
+
+// Enabled tells if values can be offloaded.
+func Enabled() bool {
+	return store != nil
+}
+
+// Put offloads the value and returns its key.
+func Put(value []byte) (string, error) {
+	if store == nil {
+		return "", errors.New("No blob store is configured")
+	}
+	key := Key(value)
+	if err := store.Put(key, value); err != nil {
+		return "", errors.Wrapf(err, "while offloading value %s", key)
+	}
+	return key, nil
+}
+
+// Get returns the offloaded value of the key.
+func Get(key string) ([]byte, error) {
+	if store == nil {
+		return nil, errors.Errorf("Value %s was offloaded but no blob store is configured", key)
+	}
+	value, err := store.Get(key)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading offloaded value %s", key)
+	}
+	return value, nil
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blob
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blob")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	require.NoError(t, Init("file://"+dir))
+	defer Init("")
+	require.True(t, Enabled())
+
+	value := []byte("a large value")
+	key, err := Put(value)
+	require.NoError(t, err)
+	require.Equal(t, Key(value), key)
+	require.Len(t, key, 64)
+
+	// Putting the same value again keeps the same key.
+	key2, err := Put(value)
+	require.NoError(t, err)
+	require.Equal(t, key, key2)
+
+	got, err := Get(key)
+	require.NoError(t, err)
+	require.Equal(t, value, got)
+
+	_, err = Get(Key([]byte("missing")))
+	require.Error(t, err)
+}
+
+func TestNoStore(t *testing.T) {
+	require.NoError(t, Init(""))
+	require.False(t, Enabled())
+	_, err := Put([]byte("value"))
+	require.Error(t, err)
+	_, err = Get(Key([]byte("value")))
+	require.Error(t, err)
+}
+
+func TestOpenInvalid(t *testing.T) {
+	_, err := Open("azure://container/dir")
+	require.Error(t, err)
+	_, err = Open("minio:///bucket")
+	require.Error(t, err)
+}
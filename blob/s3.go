@@ -0,0 +1,102 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package blob
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/dgraph-io/dgraph/x"
+
+	minio "github.com/minio/minio-go"
+	"github.com/minio/minio-go/pkg/credentials"
+	"github.com/pkg/errors"
+)
+
+// s3Store keeps the values in objects of an S3 or Minio bucket. The credentials are read from
+// the environment: AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY for s3, MINIO_ACCESS_KEY and
+// MINIO_SECRET_KEY for minio.
+type s3Store struct {
+	mc     *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3Store(uri *url.URL) (*s3Store, error) {
+	var provider credentials.Provider
+	switch uri.Scheme {
+	case "s3":
+		if !strings.Contains(uri.Host, ".") {
+			uri.Host = "s3.amazonaws.com"
+		}
+		provider = &credentials.EnvAWS{}
+	default:
+		if uri.Host == "" {
+			return nil, errors.New("Minio blob store requires a host")
+		}
+		provider = &credentials.EnvMinio{}
+	}
+	// An error is never returned, an access without credentials is attempted instead.
+	creds, _ := provider.Retrieve()
+
+	parts := strings.SplitN(strings.TrimPrefix(uri.Path, "/"), "/", 2)
+	if parts[0] == "" {
+		return nil, errors.Errorf("Invalid bucket: %q", uri.Path)
+	}
+	s := &s3Store{bucket: parts[0]}
+	if len(parts) > 1 {
+		s.prefix = parts[1]
+	}
+
+	secure := uri.Query().Get("secure") != "false" // secure by default
+	mc, err := minio.New(uri.Host, creds.AccessKeyID, creds.SecretAccessKey, secure)
+	if err != nil {
+		return nil, err
+	}
+	mc.SetAppInfo("Dgraph", x.Version())
+	found, err := mc.BucketExists(s.bucket)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while looking for bucket %s at host %s", s.bucket, uri.Host)
+	}
+	if !found {
+		return nil, errors.Errorf("Bucket was not found: %s", s.bucket)
+	}
+	s.mc = mc
+	return s, nil
+}
+
+func (s *s3Store) object(key string) string {
+	return path.Join(s.prefix, key)
+}
+
+func (s *s3Store) Put(key string, value []byte) error {
+	_, err := s.mc.PutObject(s.bucket, s.object(key), bytes.NewReader(value),
+		int64(len(value)), minio.PutObjectOptions{})
+	return err
+}
+
+func (s *s3Store) Get(key string) ([]byte, error) {
+	obj, err := s.mc.GetObject(s.bucket, s.object(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	return ioutil.ReadAll(obj)
+}
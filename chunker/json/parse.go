@@ -44,6 +44,52 @@ func stripSpaces(str string) string {
 	}, str)
 }
 
+// parseFacet converts the JSON value of the facet key to a facet. The value of a facet deleted
+// with the facets.DeleteOp operator is ignored.
+func parseFacet(key string, facetVal interface{}) (*api.Facet, error) {
+	if op, _ := facets.SplitOp(key); op == facets.DeleteOp {
+		return &api.Facet{Key: key}, nil
+	}
+
+	var jsonValue interface{}
+	var valueType api.Facet_ValType
+	switch v := facetVal.(type) {
+	case string:
+		if t, err := types.ParseTime(v); err == nil {
+			valueType = api.Facet_DATETIME
+			jsonValue = t
+		} else {
+			// the FacetFor function already converts the value to binary
+			// so there is no need for the conversion again after the switch block
+			return facets.FacetFor(key, strconv.Quote(v))
+		}
+	case json.Number:
+		if strings.Contains(v.String(), ".") {
+			jsonFloat, err := v.Float64()
+			if err != nil {
+				return nil, err
+			}
+			jsonValue = jsonFloat
+			valueType = api.Facet_FLOAT
+		} else {
+			jsonInt, err := v.Int64()
+			if err != nil {
+				return nil, err
+			}
+			jsonValue = jsonInt
+			valueType = api.Facet_INT
+		}
+	case bool:
+		jsonValue = v
+		valueType = api.Facet_BOOL
+	default:
+		return nil, errors.Errorf("Facet value for key: %s can only be string/float64/bool.", key)
+	}
+
+	// convert facet val interface{} to binary
+	return facets.ToBinary(key, jsonValue, valueType)
+}
+
 func parseFacets(m map[string]interface{}, prefix string) ([]*api.Facet, error) {
 	// This happens at root.
 	if prefix == "" {
@@ -52,68 +98,61 @@ func parseFacets(m map[string]interface{}, prefix string) ([]*api.Facet, error)
 
 	var facetsForPred []*api.Facet
 	for fname, facetVal := range m {
-		if facetVal == nil {
-			continue
-		}
 		if !strings.HasPrefix(fname, prefix) {
 			continue
 		}
-
 		key := fname[len(prefix):]
-		var jsonValue interface{}
-		var valueType api.Facet_ValType
-		switch v := facetVal.(type) {
-		case string:
-			if t, err := types.ParseTime(v); err == nil {
-				valueType = api.Facet_DATETIME
-				jsonValue = t
-			} else {
-				facet, err := facets.FacetFor(key, strconv.Quote(v))
-				if err != nil {
-					return nil, err
-				}
-
-				// the FacetFor function already converts the value to binary
-				// so there is no need for the conversion again after the switch block
-				facetsForPred = append(facetsForPred, facet)
-				continue
-			}
-		case json.Number:
-			number := facetVal.(json.Number)
-			if strings.Contains(number.String(), ".") {
-				jsonFloat, err := number.Float64()
-				if err != nil {
-					return nil, err
-				}
-				jsonValue = jsonFloat
-				valueType = api.Facet_FLOAT
-			} else {
-				jsonInt, err := number.Int64()
-				if err != nil {
-					return nil, err
-				}
-				jsonValue = jsonInt
-				valueType = api.Facet_INT
-			}
-		case bool:
-			jsonValue = v
-			valueType = api.Facet_BOOL
-		default:
-			return nil, errors.Errorf("Facet value for key: %s can only be string/float64/bool.",
-				fname)
+		if op, _ := facets.SplitOp(key); facetVal == nil && op != facets.DeleteOp {
+			continue
+		}
+		if _, ok := facetVal.(map[string]interface{}); ok {
+			// The facets of the elements of a list are parsed by parseElementFacets.
+			continue
 		}
 
-		// convert facet val interface{} to binary
-		binaryValueFacet, err := facets.ToBinary(key, jsonValue, valueType)
+		facet, err := parseFacet(key, facetVal)
 		if err != nil {
 			return nil, err
 		}
-		facetsForPred = append(facetsForPred, binaryValueFacet)
+		facetsForPred = append(facetsForPred, facet)
 	}
 
 	return facetsForPred, nil
 }
 
+// parseElementFacets returns the facets of the elements of a list of values by their index in
+// the list. The facets of the elements are given as maps from the indices of the elements to
+// the values of the facet, like "pred|facet": {"0": value, "2": value}.
+func parseElementFacets(m map[string]interface{}, prefix string) (map[int][]*api.Facet, error) {
+	var out map[int][]*api.Facet
+	for fname, facetVal := range m {
+		vals, ok := facetVal.(map[string]interface{})
+		if !ok || !strings.HasPrefix(fname, prefix) {
+			continue
+		}
+		key := fname[len(prefix):]
+		for idx, v := range vals {
+			i, err := strconv.Atoi(idx)
+			if err != nil || i < 0 {
+				return nil, errors.Errorf("Invalid index %q of a list element for facet: %s",
+					idx, fname)
+			}
+			if op, _ := facets.SplitOp(key); v == nil && op != facets.DeleteOp {
+				continue
+			}
+			facet, err := parseFacet(key, v)
+			if err != nil {
+				return nil, err
+			}
+			if out == nil {
+				out = make(map[int][]*api.Facet)
+			}
+			out[i] = append(out[i], facet)
+		}
+	}
+	return out, nil
+}
+
 // This is the response for a map[string]interface{} i.e. a struct.
 type mapResponse struct {
 	nquads []*api.NQuad // nquads at this level including the children.
@@ -248,6 +287,9 @@ func mapToNquads(m map[string]interface{}, idx *int, op int, parentPred string)
 				mr.uid = uidVal
 			} else if ok := strings.HasPrefix(s, "uid("); ok {
 				mr.uid = s
+			} else if ok := strings.HasPrefix(uidVal, "x:"); ok {
+				// The node is addressed by its external id, resolved by the server.
+				mr.uid = uidVal
 			} else if u, err := strconv.ParseUint(uidVal, 0, 64); err == nil {
 				uid = u
 			} else {
@@ -305,6 +347,14 @@ func mapToNquads(m map[string]interface{}, idx *int, op int, parentPred string)
 		if err != nil {
 			return mr, err
 		}
+		efts, err := parseElementFacets(m, prefix)
+		if err != nil {
+			return mr, err
+		}
+		if _, ok := v.([]interface{}); !ok && len(efts) > 0 {
+			return mr, errors.Errorf("Facets of list elements given for attr: %s which isn't a list",
+				pred)
+		}
 
 		nq := api.NQuad{
 			Subject:   mr.uid,
@@ -348,10 +398,11 @@ func mapToNquads(m map[string]interface{}, idx *int, op int, parentPred string)
 			// Add the nquads that we got for the connecting entity.
 			mr.nquads = append(mr.nquads, cr.nquads...)
 		case []interface{}:
-			for _, item := range v {
+			for i, item := range v {
 				nq := api.NQuad{
 					Subject:   mr.uid,
 					Predicate: pred,
+					Facets:    efts[i],
 				}
 
 				switch iv := item.(type) {
@@ -371,6 +422,10 @@ func mapToNquads(m map[string]interface{}, idx *int, op int, parentPred string)
 						continue
 					}
 
+					if len(efts[i]) > 0 {
+						return mr, errors.Errorf("Facets of list elements given for attr: %s "+
+							"which is a list of nodes", pred)
+					}
 					cr, err := mapToNquads(iv, idx, op, pred)
 					if err != nil {
 						return mr, err
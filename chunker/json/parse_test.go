@@ -369,6 +369,27 @@ func TestNquadsFromJsonFacets2(t *testing.T) {
 	checkCount(t, nq, "friend", 1)
 }
 
+func TestNquadsFromJsonFacetOps(t *testing.T) {
+	json := `{"uid":"0x1","friend":{"uid":"0x2","friend|+weight":1.5,"friend|-since":null,` +
+		`"friend|&close":true},"name":"Alice","name|-initial":"A"}`
+
+	nq, err := Parse([]byte(json), SetNquads)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(nq))
+	for _, n := range nq {
+		keys := make(map[string]api.Facet_ValType)
+		for _, f := range n.Facets {
+			keys[f.Key] = f.ValType
+		}
+		if n.Predicate == "friend" {
+			require.Equal(t, map[string]api.Facet_ValType{"+weight": api.Facet_FLOAT,
+				"-since": api.Facet_STRING, "&close": api.Facet_BOOL}, keys)
+		} else {
+			require.Equal(t, map[string]api.Facet_ValType{"-initial": api.Facet_STRING}, keys)
+		}
+	}
+}
+
 func TestNquadsFromJsonError1(t *testing.T) {
 	p := Person{
 		Name: "Alice",
@@ -393,6 +414,34 @@ func TestNquadsFromJsonList(t *testing.T) {
 	require.Equal(t, 6, len(nq))
 }
 
+func TestNquadsFromJsonListFacets(t *testing.T) {
+	json := `{"nickname":["Al","Ali","Alice"],"nickname|since":{"0":"2006-01-02T15:04:05Z","2":"2010-01-02T15:04:05Z"},"nickname|close":{"2":true}}`
+
+	nq, err := Parse([]byte(json), SetNquads)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(nq))
+	fcts := make(map[string][]string)
+	for _, n := range nq {
+		for _, f := range n.Facets {
+			fcts[n.ObjectValue.GetStrVal()] = append(fcts[n.ObjectValue.GetStrVal()], f.Key)
+		}
+	}
+	require.Equal(t, []string{"since"}, fcts["Al"])
+	require.Nil(t, fcts["Ali"])
+	require.Len(t, fcts["Alice"], 2)
+}
+
+func TestNquadsFromJsonListFacets_Error(t *testing.T) {
+	_, err := Parse([]byte(`{"name":"Alice","name|since":{"0":"2006-01-02T15:04:05Z"}}`),
+		SetNquads)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "isn't a list")
+
+	_, err = Parse([]byte(`{"nickname":["Al"],"nickname|close":{"first":true}}`), SetNquads)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid index")
+}
+
 func TestNquadsFromJsonDelete(t *testing.T) {
 	json := `{"uid":1000,"friend":[{"uid":1001}]}`
 
@@ -401,6 +450,14 @@ func TestNquadsFromJsonDelete(t *testing.T) {
 	require.Equal(t, nq[0], makeNquadEdge("1000", "friend", "1001"))
 }
 
+func TestNquadsFromJsonXid(t *testing.T) {
+	json := `{"uid":"x:user-1","friend":[{"uid":"x:email:a@b.com"}]}`
+
+	nq, err := Parse([]byte(json), DeleteNquads)
+	require.NoError(t, err)
+	require.Equal(t, nq[0], makeNquadEdge("x:user-1", "friend", "x:email:a@b.com"))
+}
+
 func TestNquadsFromJsonDeleteStar(t *testing.T) {
 	json := `{"uid":1000,"name": null}`
 
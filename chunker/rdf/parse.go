@@ -85,7 +85,12 @@ L:
 
 		case itemObjectFunc:
 			var err error
-			if rnq.ObjectId, err = parseFunction(it); err != nil {
+			if item.Val == "add" || item.Val == "cas" || item.Val == "next" {
+				rnq.ObjectId, err = parseObjectFunc(it)
+			} else {
+				rnq.ObjectId, err = parseFunction(it)
+			}
+			if err != nil {
 				return rnq, err
 			}
 
@@ -202,8 +207,8 @@ L:
 	return rnq, nil
 }
 
-// parseFunction parses uid(<var name>) and returns
-// uid(<var name>) after striping whitespace if any
+// parseFunction parses uid(<var name>) or val(<var name>) and returns
+// the function after striping whitespace if any
 func parseFunction(it *lex.ItemIterator) (string, error) {
 	item := it.Item()
 	s := item.Val
@@ -230,6 +235,32 @@ func parseFunction(it *lex.ItemIterator) (string, error) {
 	return s, nil
 }
 
+// parseObjectFunc parses add(<n>), cas(<expected>, <new>) or next(<sequence>) and returns the
+// function after stripping whitespace if any. Literal arguments are kept quoted.
+func parseObjectFunc(it *lex.ItemIterator) (string, error) {
+	s := it.Item().Val
+
+	it.Next()
+	if item := it.Item(); item.Typ != itemLeftRound {
+		return "", errors.Errorf("Expected '(', found: %s", item.Val)
+	}
+
+	var args []string
+	for it.Next() {
+		item := it.Item()
+		switch item.Typ {
+		case itemLiteral, itemVarName:
+			args = append(args, item.Val)
+		case itemComma:
+		case itemRightRound:
+			return s + "(" + strings.Join(args, ",") + ")", nil
+		default:
+			return "", errors.Errorf("Unexpected %s in %s func", item.Val, s)
+		}
+	}
+	return "", errors.Errorf("Expected ')' after %s func", s)
+}
+
 func parseFacets(it *lex.ItemIterator, rnq *api.NQuad) error {
 	if !it.Next() {
 		return errors.Errorf("Unexpected end of facets.")
@@ -254,6 +285,15 @@ func parseFacets(it *lex.ItemIterator, rnq *api.NQuad) error {
 			return errors.Errorf("Unexpected end of facets.")
 		}
 		item = it.Item()
+		if op, _ := facets.SplitOp(facetKey); op == facets.DeleteOp &&
+			(item.Typ == itemComma || item.Typ == itemRightRound) {
+			// The facet is deleted, it doesn't need a value.
+			rnq.Facets = append(rnq.Facets, &api.Facet{Key: facetKey})
+			if item.Typ == itemRightRound {
+				break
+			}
+			continue
+		}
 		if item.Typ != itemEqual {
 			return errors.Errorf("Expected = after facetKey. Found %v", item.Val)
 		}
@@ -301,6 +341,7 @@ func parseFacets(it *lex.ItemIterator, rnq *api.NQuad) error {
 
 var typeMap = map[string]types.TypeID{
 	"xs:password":        types.PasswordID,
+	"rdf:JSON":           types.JSONID,
 	"xs:string":          types.StringID,
 	"xs:date":            types.DateTimeID,
 	"xs:dateTime":        types.DateTimeID,
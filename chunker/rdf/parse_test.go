@@ -41,6 +41,15 @@ var testNQuads = []struct {
 			ObjectValue: nil,
 		},
 	},
+	{
+		input: `<x:email:a@b.com> <friend> <x:user-123> .`,
+		nq: api.NQuad{
+			Subject:     "x:email:a@b.com",
+			Predicate:   "friend",
+			ObjectId:    "x:user-123",
+			ObjectValue: nil,
+		},
+	},
 	{
 		input: "<some_subject_id>\t<predicate>\t<object_id>\t.",
 		nq: api.NQuad{
@@ -564,6 +573,34 @@ var testNQuads = []struct {
 		shouldIgnore: true,
 	},
 
+	// Facet operators test.
+	{
+		input: `_:alice <knows> _:bob (+weight=2, -since, &close=true) .`,
+		nq: api.NQuad{
+			Subject:   "_:alice",
+			Predicate: "knows",
+			ObjectId:  "_:bob",
+			Facets: []*api.Facet{
+				{
+					Key:     "+weight",
+					Value:   []byte("\002\000\000\000\000\000\000\000"),
+					ValType: facets.ValTypeForTypeID(facets.IntID),
+				},
+				{
+					Key: "-since",
+				},
+				{
+					Key:     "&close",
+					Value:   []byte{1},
+					ValType: facets.ValTypeForTypeID(facets.BoolID),
+				}},
+		},
+		expectedErr: false,
+	},
+	{
+		input:       `_:alice <knows> _:bob (since) .`,
+		expectedErr: true,
+	},
 	// Edge Facets test.
 	{
 		input: `_:alice <knows> "stuff" _:label (key1="val1",key2=13) .`,
@@ -969,6 +1006,84 @@ var testNQuads = []struct {
 		input:       `uid(a)   lives> uid (  )  .`,
 		expectedErr: true,
 	},
+	{
+		input: `uid(v) <name_copy> val(n) .`,
+		nq: api.NQuad{
+			Subject:   "uid(v)",
+			Predicate: "name_copy",
+			ObjectId:  "val(n)",
+		},
+		expectedErr: false,
+	},
+	{
+		input: `uid(v) <name_copy> val (  n  )  .`,
+		nq: api.NQuad{
+			Subject:   "uid(v)",
+			Predicate: "name_copy",
+			ObjectId:  "val(n)",
+		},
+		expectedErr: false,
+	},
+	{
+		input:       `val(n) <name_copy> val(n) .`,
+		expectedErr: true,
+	},
+	{
+		input:       `uid(v) <name_copy> vl(n) .`,
+		expectedErr: true,
+	},
+	{
+		input: `<0x01> <views> add(1) .`,
+		nq: api.NQuad{
+			Subject:   "0x01",
+			Predicate: "views",
+			ObjectId:  "add(1)",
+		},
+		expectedErr: false,
+	},
+	{
+		input: `uid(v) <stock> add ( -2 ) .`,
+		nq: api.NQuad{
+			Subject:   "uid(v)",
+			Predicate: "stock",
+			ObjectId:  "add(-2)",
+		},
+		expectedErr: false,
+	},
+	{
+		input: `<0x01> <status> cas("in stock", "sold, \"thanks\"") .`,
+		nq: api.NQuad{
+			Subject:   "0x01",
+			Predicate: "status",
+			ObjectId:  `cas("in stock","sold, \"thanks\"")`,
+		},
+		expectedErr: false,
+	},
+	{
+		input:       `<0x01> <views> add(1 .`,
+		expectedErr: true,
+	},
+	{
+		input:       `<0x01> <views> add() .`,
+		expectedErr: true,
+	},
+	{
+		input:       `add(1) <views> <0x01> .`,
+		expectedErr: true,
+	},
+	{
+		input: `_:order <number> next("orders") .`,
+		nq: api.NQuad{
+			Subject:   "_:order",
+			Predicate: "number",
+			ObjectId:  `next("orders")`,
+		},
+		expectedErr: false,
+	},
+	{
+		input:       `_:order <number> nxt("orders") .`,
+		expectedErr: true,
+	},
 }
 
 func TestLex(t *testing.T) {
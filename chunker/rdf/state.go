@@ -147,6 +147,24 @@ func lexText(l *lex.Lexer) lex.StateFn {
 			l.Emit(itemText)
 			return lexVariable
 
+		case r == 'v':
+			// val(x) is only allowed as the object, its value depends on the subject.
+			if l.Depth != atObject {
+				return l.Errorf("Unexpected char 'v'")
+			}
+			l.Backup()
+			l.Emit(itemText)
+			return lexVariable
+
+		case r == 'a' || r == 'c' || r == 'n':
+			// add(n), cas(expected, new) and next(sequence) are only allowed as the object.
+			if l.Depth != atObject {
+				return l.Errorf("Unexpected char '%c'", r)
+			}
+			l.Backup()
+			l.Emit(itemText)
+			return lexObjectFunc
+
 		case isSpace(r):
 			continue
 		default:
@@ -425,9 +443,13 @@ func lexVariable(l *lex.Lexer) lex.StateFn {
 	var r rune
 
 	// TODO(Aman): add support for more functions here.
-	for _, c := range "uid" {
+	keyword := "uid"
+	if l.Peek() == 'v' {
+		keyword = "val"
+	}
+	for _, c := range keyword {
 		if r = l.Next(); r != c {
-			return l.Errorf("Unexpected char '%c' when parsing uid keyword", r)
+			return l.Errorf("Unexpected char '%c' when parsing %s keyword", r, keyword)
 		}
 	}
 	if l.Depth == atObject {
@@ -438,7 +460,7 @@ func lexVariable(l *lex.Lexer) lex.StateFn {
 	l.IgnoreRun(isSpace)
 
 	if r = l.Next(); r != '(' {
-		return l.Errorf("Expected '(' after uid keyword, found: '%c'", r)
+		return l.Errorf("Expected '(' after %s keyword, found: '%c'", keyword, r)
 	}
 	l.Emit(itemLeftRound)
 	l.IgnoreRun(isSpace)
@@ -456,7 +478,7 @@ func lexVariable(l *lex.Lexer) lex.StateFn {
 	l.IgnoreRun(isSpace)
 
 	if r = l.Next(); r != ')' {
-		return l.Errorf("Expected ')' while reading uid func, found: '%c'", r)
+		return l.Errorf("Expected ')' while reading %s func, found: '%c'", keyword, r)
 	}
 	l.Emit(itemRightRound)
 	l.Depth++
@@ -464,6 +486,59 @@ func lexVariable(l *lex.Lexer) lex.StateFn {
 	return lexText
 }
 
+// lexObjectFunc lexes add(<n>), cas(<expected>, <new>) and next(<sequence>). The arguments are
+// either literals or bare values, e.g. add(-1) or cas("in stock", "sold").
+func lexObjectFunc(l *lex.Lexer) lex.StateFn {
+	keyword := "add"
+	switch l.Peek() {
+	case 'c':
+		keyword = "cas"
+	case 'n':
+		keyword = "next"
+	}
+	for _, c := range keyword {
+		if r := l.Next(); r != c {
+			return l.Errorf("Unexpected char '%c' when parsing %s keyword", r, keyword)
+		}
+	}
+	l.Emit(itemObjectFunc)
+	l.IgnoreRun(isSpace)
+
+	if r := l.Next(); r != leftRound {
+		return l.Errorf("Expected '(' after %s keyword, found: '%c'", keyword, r)
+	}
+	l.Emit(itemLeftRound)
+
+	acceptArg := func(r rune) bool { return !(isSpace(r) || r == comma || r == rightRound) }
+	for {
+		l.IgnoreRun(isSpace)
+		if r := l.Next(); r == quote {
+			if err := l.LexQuotedString(); err != nil {
+				return l.Errorf(err.Error())
+			}
+			l.Emit(itemLiteral)
+		} else {
+			l.Backup()
+			if _, valid := l.AcceptRun(acceptArg); !valid {
+				return l.Errorf("Expected an argument while reading %s func", keyword)
+			}
+			l.Emit(itemVarName)
+		}
+		l.IgnoreRun(isSpace)
+
+		switch r := l.Next(); r {
+		case comma:
+			l.Emit(itemComma)
+		case rightRound:
+			l.Emit(itemRightRound)
+			l.Depth++
+			return lexText
+		default:
+			return l.Errorf("Expected ',' or ')' while reading %s func, found: '%c'", keyword, r)
+		}
+	}
+}
+
 // isSpace returns true if the rune is a tab or space.
 func isSpace(r rune) bool {
 	return r == '\u0009' || r == '\u0020'
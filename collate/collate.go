@@ -0,0 +1,177 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package collate orders strings the way a language expects rather than bytewise. It follows
+// the multi-level comparison of the Unicode Collation Algorithm: base letters are compared
+// first, then accents and then case, so that "côte" sorts between "cote" and "coter". A few
+// languages tailor the order, e.g. Swedish sorts "å", "ä" and "ö" after "z".
+package collate
+
+import (
+	"bytes"
+	"encoding/binary"
+	"unicode"
+
+	"github.com/pkg/errors"
+	"golang.org/x/text/language"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	// Primary weights are grouped so that punctuation and symbols sort before digits, which
+	// sort before letters.
+	bandSymbol = 1
+	bandDigit  = 2
+	bandLetter = 3
+
+	// Secondary weights are offset so that they never start with the level separator.
+	secondaryCommon = 1 << 16
+
+	tertiaryLower = 1
+	tertiaryUpper = 2
+)
+
+// weight is a tailored position: the letter sorts right after base, k-th of its tailoring.
+type weight struct {
+	base rune
+	k    int
+}
+
+// tailorings maps the base language to the letters it sorts differently from the root order.
+var tailorings = map[string]map[rune]weight{
+	"sv": {'å': {'z', 1}, 'ä': {'z', 2}, 'æ': {'z', 2}, 'ö': {'z', 3}, 'ø': {'z', 3}},
+	"fi": {'å': {'z', 1}, 'ä': {'z', 2}, 'æ': {'z', 2}, 'ö': {'z', 3}, 'ø': {'z', 3}},
+	"da": {'æ': {'z', 1}, 'ä': {'z', 1}, 'ø': {'z', 2}, 'ö': {'z', 2}, 'å': {'z', 3}},
+	"nb": {'æ': {'z', 1}, 'ä': {'z', 1}, 'ø': {'z', 2}, 'ö': {'z', 2}, 'å': {'z', 3}},
+	"nn": {'æ': {'z', 1}, 'ä': {'z', 1}, 'ø': {'z', 2}, 'ö': {'z', 2}, 'å': {'z', 3}},
+	"no": {'æ': {'z', 1}, 'ä': {'z', 1}, 'ø': {'z', 2}, 'ö': {'z', 2}, 'å': {'z', 3}},
+	"es": {'ñ': {'n', 1}},
+}
+
+// root holds the letters that don't decompose into a base letter and accents.
+var root = map[rune]weight{'æ': {'a', 1}, 'œ': {'o', 1}, 'ø': {'o', 2}, 'ł': {'l', 1}}
+
+// expansions holds the letters that sort as a sequence of letters.
+var expansions = map[rune]string{'ß': "ss"}
+
+// Valid returns an error if lang isn't a valid language tag. Languages without a tailoring
+// use the root order.
+func Valid(lang string) error {
+	if _, err := language.Parse(lang); err != nil {
+		return errors.Errorf("Invalid collation %q: %v", lang, err)
+	}
+	return nil
+}
+
+func tailoring(lang string) map[rune]weight {
+	tag, err := language.Parse(lang)
+	if err != nil {
+		return nil
+	}
+	base, _ := tag.Base()
+	return tailorings[base.String()]
+}
+
+// Key returns the sort key of s for the language lang: comparing the keys of two strings
+// bytewise compares the strings in the order of the language. The original string ends the
+// key, so that different strings never have the same key.
+func Key(lang string, s string) []byte {
+	t := tailoring(lang)
+	var primary, secondary, tertiary []byte
+	addPrimary := func(w uint32, upper bool) {
+		primary = appendUint32(primary, w)
+		secondary = appendUint24(secondary, secondaryCommon)
+		if upper {
+			tertiary = append(tertiary, tertiaryUpper)
+		} else {
+			tertiary = append(tertiary, tertiaryLower)
+		}
+	}
+
+	for _, r := range norm.NFC.String(s) {
+		lower := unicode.ToLower(r)
+		upper := lower != r
+		if w, ok := t[lower]; ok {
+			addPrimary(tailored(w), upper)
+			continue
+		}
+		if w, ok := root[lower]; ok {
+			addPrimary(tailored(w), upper)
+			continue
+		}
+		if exp, ok := expansions[lower]; ok {
+			for _, e := range exp {
+				addPrimary(primaryWeight(e), upper)
+			}
+			continue
+		}
+		for _, d := range norm.NFD.String(string(r)) {
+			if unicode.Is(unicode.Mn, d) {
+				// Accents only weigh at the secondary level.
+				secondary = appendUint24(secondary, secondaryCommon+uint32(d))
+				continue
+			}
+			lower := unicode.ToLower(d)
+			addPrimary(primaryWeight(lower), lower != d)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(primary) + len(secondary) + len(tertiary) + len(s) + 3)
+	buf.Write(primary)
+	buf.WriteByte(0)
+	buf.Write(secondary)
+	buf.WriteByte(0)
+	buf.Write(tertiary)
+	buf.WriteByte(0)
+	buf.WriteString(s)
+	return buf.Bytes()
+}
+
+// Compare returns an integer comparing a and b in the order of the language lang. The result
+// is 0 if a == b, -1 if a < b, and +1 if a > b.
+func Compare(lang string, a, b string) int {
+	if a == b {
+		return 0
+	}
+	return bytes.Compare(Key(lang, a), Key(lang, b))
+}
+
+func primaryWeight(r rune) uint32 {
+	band := uint32(bandSymbol)
+	switch {
+	case unicode.IsLetter(r):
+		band = bandLetter
+	case unicode.IsDigit(r):
+		band = bandDigit
+	}
+	// Leave room for up to 7 tailored letters after each rune.
+	return band<<24 | (uint32(r)*8 + 8)
+}
+
+func tailored(w weight) uint32 {
+	return primaryWeight(w.base) + uint32(w.k)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+func appendUint24(b []byte, v uint32) []byte {
+	return append(b, byte(v>>16), byte(v>>8), byte(v))
+}
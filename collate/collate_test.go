@@ -0,0 +1,77 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package collate
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func sorted(lang string, words ...string) []string {
+	sort.Slice(words, func(i, j int) bool {
+		return bytes.Compare(Key(lang, words[i]), Key(lang, words[j])) < 0
+	})
+	return words
+}
+
+func TestRootOrder(t *testing.T) {
+	require.Equal(t,
+		[]string{"Äpfel", "apple", "Apple", "banana", "zebra"},
+		sorted("en", "zebra", "Äpfel", "banana", "Apple", "apple"))
+	require.Equal(t,
+		[]string{"cote", "côte", "coter"},
+		sorted("fr", "coter", "côte", "cote"))
+	require.Equal(t,
+		[]string{"-dash", "10", "9a", "abc"},
+		sorted("en", "abc", "9a", "10", "-dash"))
+	require.Equal(t,
+		[]string{"strasse", "Straße", "strassen"},
+		sorted("de", "strassen", "Straße", "strasse"))
+}
+
+func TestTailoring(t *testing.T) {
+	require.Equal(t,
+		[]string{"apa", "zebra", "åsna", "ängel", "öl"},
+		sorted("sv", "öl", "ängel", "åsna", "zebra", "apa"))
+	require.Equal(t,
+		[]string{"apa", "zebra", "ærlig", "øl", "ål"},
+		sorted("da", "ål", "øl", "ærlig", "zebra", "apa"))
+	require.Equal(t,
+		[]string{"nube", "nudo", "ñame", "oso"},
+		sorted("es", "oso", "ñame", "nudo", "nube"))
+	// Without the tailoring, the accented letters sort with their base letter.
+	require.Equal(t,
+		[]string{"ängel", "apa", "åsna", "öl", "zebra"},
+		sorted("en", "öl", "ängel", "åsna", "zebra", "apa"))
+}
+
+func TestKeyIdentical(t *testing.T) {
+	// Composed and decomposed forms only differ at the last level.
+	require.NotEqual(t, Key("en", "é"), Key("en", "é"))
+	require.Equal(t, 0, Compare("en", "é", "é"))
+	require.Equal(t, -1, Compare("en", "a", "B"))
+	require.Equal(t, 1, Compare("en", "b", "A"))
+}
+
+func TestValid(t *testing.T) {
+	require.NoError(t, Valid("sv"))
+	require.NoError(t, Valid("pt-BR"))
+	require.Error(t, Valid("not a language"))
+}
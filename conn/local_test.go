@@ -0,0 +1,60 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package conn
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+)
+
+func TestLocalListener(t *testing.T) {
+	const addr = "local-test:7080"
+	l, err := ListenLocal(addr)
+	require.NoError(t, err)
+	_, err = ListenLocal(addr)
+	require.Error(t, err)
+
+	s := grpc.NewServer()
+	pb.RegisterRaftServer(s, NewRaftServer(nil))
+	go s.Serve(l)
+	defer s.Stop()
+
+	pool := GetPools().Connect(addr)
+	require.NotNil(t, pool)
+	defer GetPools().remove(addr)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := pb.NewRaftClient(pool.Get()).Heartbeat(ctx, &api.Payload{})
+	require.NoError(t, err)
+	beat, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, "beat", string(beat.Data))
+
+	// The address is free again once the listener is closed.
+	require.NoError(t, l.Close())
+	_, err = l.Accept()
+	require.Equal(t, ErrLocalClosed, err)
+	_, err = dialLocal(addr, time.Second)
+	require.Error(t, err)
+}
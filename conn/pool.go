@@ -34,9 +34,9 @@ import (
 
 var (
 	// ErrNoConnection indicates no connection exists to a node.
-	ErrNoConnection = errors.New("No connection exists")
+	ErrNoConnection = x.WithCode(errors.New("No connection exists"), x.CodeRetriable)
 	// ErrUnhealthyConnection indicates the connection to a node is unhealthy.
-	ErrUnhealthyConnection = errors.New("Unhealthy connection")
+	ErrUnhealthyConnection = x.WithCode(errors.New("Unhealthy connection"), x.CodeRetriable)
 	echoDuration           = 500 * time.Millisecond
 )
 
@@ -152,13 +152,18 @@ func (p *Pools) Connect(addr string) *Pool {
 
 // newPool creates a new "pool" with one gRPC connection, refcount 0.
 func newPool(addr string) (*Pool, error) {
-	conn, err := grpc.Dial(addr,
+	opts := []grpc.DialOption{
 		grpc.WithStatsHandler(&ocgrpc.ClientHandler{}),
 		grpc.WithDefaultCallOptions(
 			grpc.MaxCallRecvMsgSize(x.GrpcMaxSize),
 			grpc.MaxCallSendMsgSize(x.GrpcMaxSize)),
 		grpc.WithBackoffMaxDelay(time.Second),
-		grpc.WithInsecure())
+		grpc.WithInsecure(),
+	}
+	if getLocalListener(addr) != nil {
+		opts = append(opts, grpc.WithDialer(dialLocal))
+	}
+	conn, err := grpc.Dial(addr, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,369 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package decode unmarshals the JSON responses of Dgraph queries into structs. Fields are
+// matched using the dgraph struct tag, or the json tag if there is none, and the values
+// are converted as Dgraph returns them:
+//
+//	type Person struct {
+//		Uid     uint64            `dgraph:"uid"`
+//		Name    string            `dgraph:"name"`
+//		Names   map[string]string `dgraph:"name@*"`
+//		Friends []Person          `dgraph:"friend"`
+//		Since   time.Time         `dgraph:"friend|since"`
+//		Facets  map[string]string `dgraph:"friend|*"`
+//		Owner   uint64            `dgraph:"owner_uid,uid"`
+//	}
+//
+// A uid field, or a field with the uid option, is parsed from its hex string. A pred@* field
+// gets the value of every language of pred, keyed by language, with "" for the untagged
+// value. A pred|* field gets every facet of pred keyed by facet name. A single value is
+// decoded into a slice of one element, and a list of one element into a single value.
+package decode
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Unmarshal decodes the JSON of a query response, like the Json of api.Response, into the
+// value pointed to by v.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.Errorf("Unmarshal requires a non-nil pointer. Got: %T", v)
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var src interface{}
+	if err := dec.Decode(&src); err != nil {
+		return errors.Wrapf(err, "while decoding response")
+	}
+	return assign(rv.Elem(), src, false)
+}
+
+type fieldKind int
+
+const (
+	plainField fieldKind = iota
+	langField
+	facetField
+)
+
+type field struct {
+	index []int
+	key   string
+	kind  fieldKind
+	uid   bool
+}
+
+var fieldCache sync.Map // map[reflect.Type][]field
+
+// fieldsOf returns the fields of the struct type that are decoded, including the ones of
+// embedded structs.
+func fieldsOf(t reflect.Type) []field {
+	if fs, ok := fieldCache.Load(t); ok {
+		return fs.([]field)
+	}
+	var fields []field
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("dgraph")
+		if !ok {
+			tag = sf.Tag.Get("json")
+		}
+		if tag == "-" {
+			continue
+		}
+		if sf.Anonymous && tag == "" && sf.Type.Kind() == reflect.Struct {
+			for _, f := range fieldsOf(sf.Type) {
+				f.index = append([]int{i}, f.index...)
+				fields = append(fields, f)
+			}
+			continue
+		}
+		if sf.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+
+		parts := strings.Split(tag, ",")
+		f := field{index: []int{i}, key: parts[0]}
+		if f.key == "" {
+			f.key = sf.Name
+		}
+		for _, opt := range parts[1:] {
+			if opt == "uid" {
+				f.uid = true
+			}
+		}
+		switch {
+		case f.key == "uid":
+			f.uid = true
+		case strings.HasSuffix(f.key, "@*"):
+			f.kind = langField
+			f.key = strings.TrimSuffix(f.key, "@*")
+		case strings.HasSuffix(f.key, "|*"):
+			f.kind = facetField
+			f.key = strings.TrimSuffix(f.key, "|*")
+		}
+		fields = append(fields, f)
+	}
+	fieldCache.Store(t, fields)
+	return fields
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func assign(dst reflect.Value, src interface{}, uid bool) error {
+	if src == nil {
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Ptr:
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return assign(dst.Elem(), src, uid)
+	case reflect.Interface:
+		if dst.NumMethod() != 0 {
+			return errors.Errorf("Cannot decode into interface %s", dst.Type())
+		}
+		dst.Set(reflect.ValueOf(plain(src)))
+		return nil
+	case reflect.Slice:
+		list, ok := src.([]interface{})
+		if !ok {
+			// A single value is a list of one value.
+			list = []interface{}{src}
+		}
+		out := reflect.MakeSlice(dst.Type(), len(list), len(list))
+		for i, elem := range list {
+			if err := assign(out.Index(i), elem, uid); err != nil {
+				return err
+			}
+		}
+		dst.Set(out)
+		return nil
+	}
+
+	// A non list value may be returned as a list of one value, like uid edges.
+	if list, ok := src.([]interface{}); ok {
+		switch len(list) {
+		case 0:
+			return nil
+		case 1:
+			src = list[0]
+		default:
+			return errors.Errorf("Cannot decode a list of %d values into %s",
+				len(list), dst.Type())
+		}
+	}
+
+	switch dst.Kind() {
+	case reflect.Struct:
+		if dst.Type() == timeType {
+			return assignTime(dst, src)
+		}
+		obj, ok := src.(map[string]interface{})
+		if !ok {
+			return mismatch(dst, src)
+		}
+		return assignStruct(dst, obj)
+	case reflect.Map:
+		obj, ok := src.(map[string]interface{})
+		if !ok || dst.Type().Key().Kind() != reflect.String {
+			return mismatch(dst, src)
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		for k, v := range obj {
+			if err := setMapValue(dst, k, v, false); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.String:
+		s, ok := src.(string)
+		if !ok {
+			return mismatch(dst, src)
+		}
+		dst.SetString(s)
+		return nil
+	case reflect.Bool:
+		b, ok := src.(bool)
+		if !ok {
+			return mismatch(dst, src)
+		}
+		dst.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, ok := src.(json.Number)
+		if !ok {
+			return mismatch(dst, src)
+		}
+		i, err := strconv.ParseInt(n.String(), 10, dst.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "while decoding %s into %s", n, dst.Type())
+		}
+		dst.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var s string
+		switch v := src.(type) {
+		case json.Number:
+			s = v.String()
+		case string:
+			if !uid {
+				return mismatch(dst, src)
+			}
+			s = v
+		default:
+			return mismatch(dst, src)
+		}
+		u, err := strconv.ParseUint(s, 0, dst.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "while decoding %s into %s", s, dst.Type())
+		}
+		dst.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, ok := src.(json.Number)
+		if !ok {
+			return mismatch(dst, src)
+		}
+		f, err := strconv.ParseFloat(n.String(), dst.Type().Bits())
+		if err != nil {
+			return errors.Wrapf(err, "while decoding %s into %s", n, dst.Type())
+		}
+		dst.SetFloat(f)
+		return nil
+	}
+	return errors.Errorf("Cannot decode into %s", dst.Type())
+}
+
+func assignStruct(dst reflect.Value, obj map[string]interface{}) error {
+	for _, f := range fieldsOf(dst.Type()) {
+		fv := dst.FieldByIndex(f.index)
+		var err error
+		switch f.kind {
+		case plainField:
+			err = assign(fv, obj[f.key], f.uid)
+		case langField:
+			// The untagged value is stored under the empty language.
+			err = assignGroup(fv, obj, f, func(k string) (string, bool) {
+				if k == f.key {
+					return "", true
+				}
+				return cutPrefix(k, f.key+"@")
+			})
+		case facetField:
+			err = assignGroup(fv, obj, f, func(k string) (string, bool) {
+				return cutPrefix(k, f.key+"|")
+			})
+		}
+		if err != nil {
+			return errors.Wrapf(err, "while decoding %s", f.key)
+		}
+	}
+	return nil
+}
+
+// assignGroup sets every value of obj whose key is matched by name into the map dst.
+func assignGroup(dst reflect.Value, obj map[string]interface{}, f field,
+	name func(string) (string, bool)) error {
+
+	if dst.Kind() != reflect.Map || dst.Type().Key().Kind() != reflect.String {
+		return errors.Errorf("Field for %s must be a map with string keys. Got: %s",
+			f.key, dst.Type())
+	}
+	for k, v := range obj {
+		n, ok := name(k)
+		if !ok {
+			continue
+		}
+		if dst.IsNil() {
+			dst.Set(reflect.MakeMap(dst.Type()))
+		}
+		if err := setMapValue(dst, n, v, f.uid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func setMapValue(dst reflect.Value, k string, v interface{}, uid bool) error {
+	elem := reflect.New(dst.Type().Elem()).Elem()
+	if err := assign(elem, v, uid); err != nil {
+		return errors.Wrapf(err, "while decoding %s", k)
+	}
+	dst.SetMapIndex(reflect.ValueOf(k).Convert(dst.Type().Key()), elem)
+	return nil
+}
+
+func assignTime(dst reflect.Value, src interface{}) error {
+	s, ok := src.(string)
+	if !ok {
+		return mismatch(dst, src)
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return errors.Wrapf(err, "while decoding %s into time", s)
+	}
+	dst.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// plain converts the numbers in src to int64 or float64 values.
+func plain(src interface{}) interface{} {
+	switch v := src.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	case []interface{}:
+		for i := range v {
+			v[i] = plain(v[i])
+		}
+	case map[string]interface{}:
+		for k := range v {
+			v[k] = plain(v[k])
+		}
+	}
+	return src
+}
+
+func cutPrefix(s, prefix string) (string, bool) {
+	if !strings.HasPrefix(s, prefix) {
+		return "", false
+	}
+	return s[len(prefix):], true
+}
+
+func mismatch(dst reflect.Value, src interface{}) error {
+	return errors.Errorf("Cannot decode %T into %s", plain(src), dst.Type())
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package decode
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type base struct {
+	Uid  uint64   `dgraph:"uid"`
+	Tags []string `json:"tags"`
+}
+
+type person struct {
+	base
+	Name    string            `dgraph:"name"`
+	Names   map[string]string `dgraph:"name@*"`
+	NameHi  string            `dgraph:"name@hi"`
+	Age     *int              `dgraph:"age"`
+	Score   float64           `dgraph:"score"`
+	Alive   bool              `dgraph:"alive"`
+	Dob     time.Time         `dgraph:"dob"`
+	Owner   uint64            `dgraph:"owner,uid"`
+	Best    *person           `dgraph:"best_friend"`
+	Friends []person          `dgraph:"friend"`
+	Since   time.Time         `dgraph:"friend|since"`
+	Facets  map[string]string `dgraph:"friend|*"`
+	Extra   interface{}       `dgraph:"extra"`
+	Skipped string            `dgraph:"-"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	data := `{
+		"q": [{
+			"uid": "0x1a",
+			"tags": "single",
+			"name": "Alice",
+			"name@en": "Alice",
+			"name@hi": "ऐलिस",
+			"age": 29,
+			"score": 4.5,
+			"alive": true,
+			"dob": "1990-01-02T03:04:05Z",
+			"owner": "0x2",
+			"best_friend": [{"uid": "0x2", "name": "Bob"}],
+			"friend": [{
+				"uid": "0x2",
+				"name": "Bob",
+				"friend|since": "2010-01-01T00:00:00Z",
+				"friend|close": "yes"
+			}],
+			"extra": {"n": 1, "f": 1.5},
+			"-": "ignored"
+		}]
+	}`
+	var res struct {
+		Q []person `json:"q"`
+	}
+	require.NoError(t, Unmarshal([]byte(data), &res))
+	require.Len(t, res.Q, 1)
+
+	p := res.Q[0]
+	require.Equal(t, uint64(0x1a), p.Uid)
+	require.Equal(t, []string{"single"}, p.Tags)
+	require.Equal(t, "Alice", p.Name)
+	require.Equal(t, map[string]string{"": "Alice", "en": "Alice", "hi": "ऐलिस"}, p.Names)
+	require.Equal(t, "ऐलिस", p.NameHi)
+	require.Equal(t, 29, *p.Age)
+	require.Equal(t, 4.5, p.Score)
+	require.True(t, p.Alive)
+	require.Equal(t, time.Date(1990, 1, 2, 3, 4, 5, 0, time.UTC), p.Dob)
+	require.Equal(t, uint64(2), p.Owner)
+	require.Equal(t, "Bob", p.Best.Name)
+	require.Equal(t, map[string]interface{}{"n": int64(1), "f": 1.5}, p.Extra)
+	require.Empty(t, p.Skipped)
+
+	require.Len(t, p.Friends, 1)
+	bob := p.Friends[0]
+	require.Equal(t, uint64(2), bob.Uid)
+	require.Equal(t, time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC), bob.Since)
+	require.Equal(t, map[string]string{
+		"since": "2010-01-01T00:00:00Z",
+		"close": "yes",
+	}, bob.Facets)
+}
+
+func TestUnmarshalErrors(t *testing.T) {
+	var p person
+	require.Error(t, Unmarshal([]byte(`{"name": 1}`), &p))
+	require.Error(t, Unmarshal([]byte(`{"age": "old"}`), &p))
+	// Only uid fields are parsed from strings.
+	require.Error(t, Unmarshal([]byte(`{"score": "0x1"}`), &p))
+	require.Error(t, Unmarshal([]byte(`{"best_friend": [{}, {}]}`), &p))
+	require.Error(t, Unmarshal([]byte(`{"name": "a"}`), p))
+	require.Error(t, Unmarshal([]byte(`{`), &p))
+
+	var bad struct {
+		Names []string `dgraph:"name@*"`
+	}
+	require.Error(t, Unmarshal([]byte(`{"name@en": "a"}`), &bad))
+}
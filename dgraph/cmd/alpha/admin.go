@@ -19,16 +19,23 @@ package alpha
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
 	"net/http"
+	"runtime"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/dgraph-io/dgraph/edgraph"
 	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/worker"
 	"github.com/dgraph-io/dgraph/x"
+	"github.com/gogo/protobuf/jsonpb"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
 )
 
 // handlerInit does some standard checks. Returns false if something is wrong.
@@ -86,6 +93,20 @@ func exportHandler(w http.ResponseWriter, r *http.Request) {
 	x.Check2(w.Write([]byte(`{"code": "Success", "message": "Export completed."}`)))
 }
 
+// drainHandler moves the leadership and, if needed, the tablets off this Alpha, so that it can
+// be shut down without downtime.
+func drainHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	if err := worker.Drain(r.Context()); err != nil {
+		x.SetStatus(w, err.Error(), "Drain failed.")
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write([]byte(`{"code": "Success", "message": "Server is drained."}`)))
+}
+
 func memoryLimitHandler(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -130,6 +151,473 @@ func memoryLimitGetHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// debugStateHandler dumps the in-memory state of the Alpha as JSON: the oracle with its pending
+// transactions, the caches and the membership of the groups, to diagnose stuck clusters.
+func debugStateHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+
+	posting.Config.Mu.Lock()
+	lruMB := posting.Config.AllottedMemory
+	posting.Config.Mu.Unlock()
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	oracle := posting.Oracle().State()
+	caches := struct {
+		LruMB            float64 `json:"lru_mb"`
+		HeapInUse        uint64  `json:"heap_inuse"`
+		HeapIdle         uint64  `json:"heap_idle"`
+		TxnDeltas        int     `json:"txn_deltas"`
+		TxnLists         int     `json:"txn_lists"`
+		ParsedQueries    int     `json:"parsed_queries"`
+		PersistedQueries int     `json:"persisted_queries"`
+	}{
+		LruMB:            lruMB,
+		HeapInUse:        ms.HeapInuse + ms.StackInuse,
+		HeapIdle:         ms.HeapIdle - ms.HeapReleased,
+		ParsedQueries:    edgraph.ParseCacheLen(),
+		PersistedQueries: len(edgraph.PersistedQueries()),
+	}
+	for _, txn := range oracle.PendingTxns {
+		caches.TxnDeltas += txn.Deltas
+		caches.TxnLists += txn.Lists
+	}
+
+	membership := json.RawMessage("null")
+	if state := worker.GetMembershipState(); state != nil {
+		var buf bytes.Buffer
+		if err := (&jsonpb.Marshaler{}).Marshal(&buf, state); err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		membership = buf.Bytes()
+	}
+
+	out := struct {
+		Oracle     *posting.OracleState `json:"oracle"`
+		Caches     interface{}          `json:"caches"`
+		Raft       *worker.RaftHealth   `json:"raft"`
+		Membership json.RawMessage      `json:"membership"`
+	}{
+		Oracle:     oracle,
+		Caches:     caches,
+		Raft:       worker.Health(),
+		Membership: membership,
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write(data))
+}
+
+// proposalBatchingHandler returns the limits of the batches of Raft proposals with the number of
+// proposals sent on GET, and changes the limits given in the JSON body on PUT.
+func proposalBatchingHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, r.Method) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		opts := worker.ProposalBatching()
+		stats := worker.ProposalBatchingStats()
+		js, err := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{
+				"max_bytes":   opts.MaxBytes,
+				"max_latency": opts.MaxLatency.String(),
+				"batches":     stats.Batches,
+				"proposals":   stats.Proposals,
+				"bytes":       stats.Bytes,
+			},
+		})
+		if err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		x.Check2(w.Write(js))
+	case http.MethodPut:
+		var req struct {
+			MaxBytes   *int    `json:"max_bytes"`
+			MaxLatency *string `json:"max_latency"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		opts := worker.ProposalBatching()
+		if req.MaxBytes != nil {
+			opts.MaxBytes = *req.MaxBytes
+		}
+		if req.MaxLatency != nil {
+			d, err := time.ParseDuration(*req.MaxLatency)
+			if err != nil {
+				x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+				return
+			}
+			opts.MaxLatency = d
+		}
+		if err := worker.SetProposalBatching(opts); err != nil {
+			x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		x.Check2(w.Write([]byte(`{"code": "Success", "message": "Proposal batching updated."}`)))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// persistedQueriesHandler lists the persisted queries on GET and removes them on DELETE.
+func persistedQueriesHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !handlerInit(w, r, http.MethodGet) {
+			return
+		}
+		js, err := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{"queries": edgraph.PersistedQueries()},
+		})
+		if err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		x.Check2(w.Write(js))
+	case http.MethodDelete:
+		if !handlerInit(w, r, http.MethodDelete) {
+			return
+		}
+		edgraph.ClearPersistedQueries()
+		w.Header().Set("Content-Type", "application/json")
+		x.Check2(w.Write([]byte(`{"code": "Success", "message": "Persisted queries removed."}`)))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// transformsHandler lists the response transforms on GET, registers one on PUT and removes
+// the one given by the name parameter on DELETE.
+func transformsHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, r.Method) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		js, err := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{"transforms": edgraph.Transforms()},
+		})
+		if err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		x.Check2(w.Write(js))
+	case http.MethodPut:
+		var t edgraph.Transform
+		if err := json.NewDecoder(r.Body).Decode(&t); err != nil {
+			x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err := edgraph.RegisterTransform(r.Context(), t.Name, t.Template); err != nil {
+			x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		x.Check2(w.Write([]byte(`{"code": "Success", "message": "Transform registered."}`)))
+	case http.MethodDelete:
+		if err := edgraph.DeleteTransform(r.Context(), r.URL.Query().Get("name")); err != nil {
+			code := x.Error
+			if errors.Cause(err) == edgraph.ErrUnknownTransform {
+				code = x.ErrorInvalidRequest
+			}
+			x.SetStatus(w, code, err.Error())
+			return
+		}
+		x.Check2(w.Write([]byte(`{"code": "Success", "message": "Transform removed."}`)))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+// indexingHandler lists the index builds of this Alpha.
+func indexingHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	js, err := json.Marshal(map[string]interface{}{
+		"data": map[string]interface{}{"builds": worker.IndexBuilds()},
+	})
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write(js))
+}
+
+// indexingPauseHandler pauses the index build of the predicate parameter on this Alpha.
+func indexingPauseHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	if err := worker.PauseIndexBuild(r.URL.Query().Get("predicate")); err != nil {
+		x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write([]byte(`{"code": "Success", "message": "Index build paused."}`)))
+}
+
+// indexingResumeHandler resumes the paused index build of the predicate parameter on this Alpha.
+func indexingResumeHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	if err := worker.ResumeIndexBuild(r.URL.Query().Get("predicate")); err != nil {
+		x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write([]byte(`{"code": "Success", "message": "Index build resumed."}`)))
+}
+
+// indexingCheckHandler compares the indices of the predicate parameter with its data on this
+// Alpha, and repairs them if the repair parameter is true.
+func indexingCheckHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	repair, _ := strconv.ParseBool(r.URL.Query().Get("repair"))
+	report, err := worker.CheckIndex(r.Context(), r.URL.Query().Get("predicate"), repair)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	js, err := json.Marshal(map[string]interface{}{"data": report})
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write(js))
+}
+
+// graphStatsHandler returns the degree distributions of the predicates, the type counts and the
+// supernodes of the cluster.
+func graphStatsHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	threshold, limit := uint64(10000), 100
+	if v := r.URL.Query().Get("threshold"); v != "" {
+		var err error
+		if threshold, err = strconv.ParseUint(v, 0, 64); err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, "Invalid threshold: "+err.Error())
+			return
+		}
+	}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		var err error
+		if limit, err = strconv.Atoi(v); err != nil || limit < 0 {
+			x.SetStatus(w, x.ErrorInvalidRequest, "Invalid limit: "+v)
+			return
+		}
+	}
+	stats, err := worker.ComputeGraphStats(r.Context(), threshold, limit)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	js, err := json.Marshal(map[string]interface{}{"data": stats})
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write(js))
+}
+
+// analyticsHandler lists the analytics jobs on GET. On POST, it runs a graph algorithm over the
+// edges of the predicates, in the background if the results are written to an output predicate,
+// or streaming them as one JSON object per line otherwise.
+func analyticsHandler(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		if !handlerInit(w, r, http.MethodGet) {
+			return
+		}
+		js, err := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{"jobs": edgraph.AnalyticsJobs()},
+		})
+		if err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		x.Check2(w.Write(js))
+	case http.MethodPost:
+		if !handlerInit(w, r, http.MethodPost) {
+			return
+		}
+		analyticsPostHandler(w, r)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func analyticsPostHandler(w http.ResponseWriter, r *http.Request) {
+	params := r.URL.Query()
+	req := &edgraph.AnalyticsRequest{
+		Algorithm: params.Get("algorithm"),
+		Output:    params.Get("output"),
+	}
+	for _, pred := range strings.Split(params.Get("predicates"), ",") {
+		if pred = strings.TrimSpace(pred); pred != "" {
+			req.Predicates = append(req.Predicates, pred)
+		}
+	}
+	var err error
+	if v := params.Get("samples"); v != "" {
+		if req.Samples, err = strconv.Atoi(v); err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, "Invalid samples: "+v)
+			return
+		}
+	}
+	if v := params.Get("iterations"); v != "" {
+		if req.Iterations, err = strconv.Atoi(v); err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, "Invalid iterations: "+v)
+			return
+		}
+	}
+	if v := params.Get("seed"); v != "" {
+		if req.Seed, err = strconv.ParseInt(v, 0, 64); err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, "Invalid seed: "+v)
+			return
+		}
+	}
+	if v := params.Get("max_edges"); v != "" {
+		if req.MaxEdges, err = strconv.ParseUint(v, 0, 64); err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, "Invalid max_edges: "+v)
+			return
+		}
+	}
+
+	if req.Output != "" {
+		job, err := edgraph.StartAnalyticsJob(req)
+		if err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+			return
+		}
+		js, err := json.Marshal(map[string]interface{}{"data": job})
+		if err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		x.Check2(w.Write(js))
+		return
+	}
+
+	flusher, _ := w.(http.Flusher)
+	var n int
+	err = edgraph.RunAnalyticsJob(r.Context(), req, func(res *edgraph.AnalyticsResult) error {
+		if n == 0 {
+			w.Header().Set("Content-Type", "application/x-ndjson")
+		}
+		js, err := json.Marshal(res)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(js, '\n')); err != nil {
+			return err
+		}
+		if n++; n%1000 == 0 && flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	switch {
+	case err != nil && n == 0:
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+	case err != nil:
+		// The status is already sent once the first line is, the error ends the stream.
+		glog.Errorf("While streaming the results of the analytics job: %v", err)
+		js, _ := json.Marshal(map[string]string{"error": err.Error()})
+		fmt.Fprintf(w, "%s\n", js)
+	}
+}
+
+// analyticsCancelHandler cancels the analytics job with the id parameter.
+func analyticsCancelHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	id, err := strconv.ParseUint(r.URL.Query().Get("id"), 0, 64)
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, "Invalid id: "+r.URL.Query().Get("id"))
+		return
+	}
+	if err := edgraph.CancelAnalyticsJob(id); err != nil {
+		x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	x.Check2(w.Write([]byte(`{"code": "Success", "message": "Analytics job cancelled."}`)))
+}
+
+// orphansHandler streams the dangling edges and the orphan nodes of the cluster, one JSON
+// object per line, or as the N-Quads deleting them if the format parameter is rdf.
+func orphansHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, http.MethodGet) {
+		return
+	}
+	rdf := r.URL.Query().Get("format") == "rdf"
+	if rdf {
+		w.Header().Set("Content-Type", "application/rdf")
+	} else {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+	}
+	flusher, _ := w.(http.Flusher)
+
+	var n int
+	err := worker.FindGraphIssues(r.Context(), func(issue *worker.GraphIssue) error {
+		var line []byte
+		switch {
+		case rdf && issue.Kind == worker.GraphOrphan:
+			line = []byte(fmt.Sprintf("<%s> * * .\n", issue.Uid))
+		case rdf:
+			line = []byte(fmt.Sprintf("<%s> <%s> <%s> .\n", issue.Uid, issue.Predicate,
+				issue.Object))
+		default:
+			js, err := json.Marshal(issue)
+			if err != nil {
+				return err
+			}
+			line = append(js, '\n')
+		}
+		if _, err := w.Write(line); err != nil {
+			return err
+		}
+		if n++; n%1000 == 0 && flusher != nil {
+			flusher.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// The status is already sent once the first line is, the error ends the stream.
+		glog.Errorf("While finding orphan nodes and dangling edges: %v", err)
+		if rdf {
+			fmt.Fprintf(w, "# Error: %s\n", err)
+		} else {
+			js, _ := json.Marshal(map[string]string{"error": err.Error()})
+			fmt.Fprintf(w, "%s\n", js)
+		}
+	}
+}
+
 func ipInIPWhitelistRanges(ipString string) bool {
 	ip := net.ParseIP(ipString)
 
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alpha
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dgraph-io/dgraph/conn"
+	"github.com/dgraph-io/dgraph/dgraph/cmd/zero"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/spf13/viper"
+)
+
+// StartEmbedded runs Dgraph in this process without its HTTP and gRPC servers, keeping its data
+// in dir. flags overrides the flags of the alpha command by name. Zero runs in this process too,
+// and talks with Alpha through the local connections of package conn, so nothing listens on the
+// network. It returns without waiting for Alpha to be ready, with a function which stops both.
+func StartEmbedded(dir string, flags map[string]string) (func(), error) {
+	// When run as a library, the root command hasn't bound the flags.
+	if Alpha.Conf == nil {
+		Alpha.Conf = viper.New()
+		x.Check(Alpha.Conf.BindPFlags(Alpha.Cmd.Flags()))
+	}
+	if zero.Zero.Conf == nil {
+		zero.Zero.Conf = viper.New()
+		x.Check(zero.Zero.Conf.BindPFlags(zero.Zero.Cmd.Flags()))
+	}
+	for name, value := range flags {
+		Alpha.Conf.Set(name, value)
+	}
+
+	zeroAddr, stopZero, err := zero.RunLocal(filepath.Join(dir, "zw"))
+	if err != nil {
+		return nil, err
+	}
+	myAddr := fmt.Sprintf("alpha.local:%d", x.PortInternal)
+	ln, err := conn.ListenLocal(myAddr)
+	if err != nil {
+		stopZero()
+		return nil, err
+	}
+	Alpha.Conf.Set("postings", filepath.Join(dir, "p"))
+	Alpha.Conf.Set("wal", filepath.Join(dir, "w"))
+	Alpha.Conf.Set("zero", zeroAddr)
+	Alpha.Conf.Set("my", myAddr)
+	Alpha.Conf.Set("ephemeral", false)
+	// The address of Alpha isn't bound to an interface.
+	bindall = true
+
+	stop := start()
+	go worker.Serve(ln)
+	return func() {
+		// Stopping the worker server closes ln.
+		stop()
+		stopZero()
+	}, nil
+}
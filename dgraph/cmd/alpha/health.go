@@ -0,0 +1,95 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alpha
+
+import (
+	"fmt"
+
+	"github.com/dgraph-io/dgraph/edgraph"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+const (
+	// maxApplyLag is the number of committed Raft entries the Alpha can have left to apply
+	// while still ready, e.g. while it catches up after a restart.
+	maxApplyLag = 1000
+	// minFreeDisk is the fraction of the disk which must be available to the data directories.
+	minFreeDisk = 0.05
+)
+
+type diskHealth struct {
+	Dir        string `json:"dir"`
+	FreeBytes  uint64 `json:"free_bytes"`
+	TotalBytes uint64 `json:"total_bytes"`
+}
+
+// readiness is the detail of the readiness probe: the Alpha is ready to serve requests if
+// nothing is wrong with any of the things it depends on.
+type readiness struct {
+	Ready    bool               `json:"ready"`
+	Problems []string           `json:"problems,omitempty"`
+	Raft     *worker.RaftHealth `json:"raft,omitempty"`
+	Disks    []diskHealth       `json:"disks,omitempty"`
+}
+
+func checkReadiness() *readiness {
+	r := &readiness{Raft: worker.Health()}
+	if err := x.HealthCheck(); err != nil {
+		r.Problems = append(r.Problems, err.Error())
+	}
+	for _, dir := range []string{edgraph.Config.PostingDir, edgraph.Config.WALDir} {
+		free, total, err := x.DiskSpace(dir)
+		if err != nil {
+			// The disk space is unknown on some platforms.
+			continue
+		}
+		r.Disks = append(r.Disks, diskHealth{Dir: dir, FreeBytes: free, TotalBytes: total})
+	}
+	r.check()
+	return r
+}
+
+// check sets the problems found in the detail, and whether the Alpha is ready.
+func (r *readiness) check() {
+	if h := r.Raft; h == nil {
+		r.Problems = append(r.Problems, "Raft group hasn't started yet")
+	} else {
+		if h.LeaderId == 0 {
+			r.Problems = append(r.Problems, fmt.Sprintf("Group %d has no leader", h.GroupId))
+		}
+		if lag := h.ApplyLag(); lag > maxApplyLag {
+			r.Problems = append(r.Problems,
+				fmt.Sprintf("%d committed Raft entries are waiting to be applied", lag))
+		}
+		if h.PendingProposals >= h.MaxPendingProposals {
+			r.Problems = append(r.Problems,
+				fmt.Sprintf("%d proposals are pending, the maximum", h.PendingProposals))
+		}
+		if !h.ZeroConnected {
+			r.Problems = append(r.Problems, "No healthy connection to the Zero leader")
+		}
+	}
+	for _, d := range r.Disks {
+		if float64(d.FreeBytes) < minFreeDisk*float64(d.TotalBytes) {
+			r.Problems = append(r.Problems,
+				fmt.Sprintf("Only %d of %d bytes are free on the disk of %s",
+					d.FreeBytes, d.TotalBytes, d.Dir))
+		}
+	}
+	r.Ready = len(r.Problems) == 0
+}
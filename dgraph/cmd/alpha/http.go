@@ -17,12 +17,17 @@
 package alpha
 
 import (
+	"bufio"
 	"bytes"
 	"compress/gzip"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
+	"math/bits"
 	"net/http"
 	"sort"
 	"strconv"
@@ -169,6 +174,31 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
 		return
 	}
+	dryRun, err := parseBool(r, "dryRun")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	dedupeNodes, err := parseBool(r, "dedupeNodes")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	elementFacets, err := parseBool(r, "elementFacets")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	maxResponseBytes, err := parseUint64(r, "maxResponseBytes")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	moreFlags, err := parseBool(r, "moreFlags")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
 	queryTimeout, err := parseDuration(r, "timeout")
 	if err != nil {
 		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
@@ -179,6 +209,49 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
 		return
 	}
+	var limits query.Limits
+	for name, v := range map[string]*uint64{
+		"maxDepth":  &limits.Depth,
+		"maxNodes":  &limits.Nodes,
+		"maxFanout": &limits.Fanout,
+	} {
+		if *v, err = parseUint64(r, name); err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	floats, err := query.ParseFloatFormat(r.URL.Query().Get("floatPrecision"),
+		r.URL.Query().Get("nonFinite"))
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	binary, err := query.ParseBinaryFormat(r.URL.Query().Get("binaryEncoding"),
+		r.URL.Query().Get("binaryFull"))
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	langs, err := query.ParseLangs(r.URL.Query().Get("lang"))
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	profile, err := query.ParseOutputProfile(r.URL.Query().Get("profile"))
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	// The subgraph blocks of the query can be returned in a graph format instead of JSON.
+	graphFormat := r.URL.Query().Get("format")
+	if graphFormat != "" {
+		if graphFormat = worker.NormalizeGraphFormat(graphFormat); graphFormat == "" {
+			x.SetStatus(w, x.ErrorInvalidRequest, "Invalid format. "+
+				"Supported formats are graphml, gexf and dot")
+			return
+		}
+	}
 
 	body := readRequest(w, r)
 	if body == nil {
@@ -186,8 +259,11 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params struct {
-		Query     string            `json:"query"`
-		Variables map[string]string `json:"variables"`
+		Query      string            `json:"query"`
+		Variables  map[string]string `json:"variables"`
+		Extensions struct {
+			PersistedQuery *persistedQueryExt `json:"persistedQuery"`
+		} `json:"extensions"`
 	}
 	contentType := r.Header.Get("Content-Type")
 	switch strings.ToLower(contentType) {
@@ -206,8 +282,37 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	ctx := context.WithValue(context.Background(), query.DebugKey, isDebugMode)
+	var persistHash string
+	if pq := params.Extensions.PersistedQuery; pq != nil {
+		persistHash, err = resolvePersistedQuery(pq, &params.Query)
+		if err == errPersistedQueryNotFound {
+			x.SetStatus(w, x.ErrorPersistedQueryNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+			return
+		}
+	}
+
+	// The query is cancelled once the client disconnects.
+	ctx := context.WithValue(r.Context(), query.DebugKey, isDebugMode)
+	ctx = context.WithValue(ctx, query.LimitsKey, limits)
+	ctx = context.WithValue(ctx, query.FloatFormatKey, floats)
+	ctx = context.WithValue(ctx, query.BinaryFormatKey, binary)
+	ctx = context.WithValue(ctx, query.LangKey, langs)
+	ctx = context.WithValue(ctx, query.DedupeNodesKey, dedupeNodes)
+	ctx = context.WithValue(ctx, query.ElementFacetsKey, elementFacets)
+	ctx = context.WithValue(ctx, query.OutputProfileKey, profile)
+	ctx = context.WithValue(ctx, query.MaxResponseBytesKey, maxResponseBytes)
+	ctx = context.WithValue(ctx, query.CursorKey, r.URL.Query().Get("cursor"))
+	ctx = context.WithValue(ctx, query.MoreFlagsKey, moreFlags)
 	ctx = attachAccessJwt(ctx, r)
+	ctx = query.WithWarnings(ctx)
+	ctx = query.WithBlockStatus(ctx)
+	ctx = query.WithTruncation(ctx)
+	ctx = query.WithSpill(ctx)
+	ctx = worker.WithQueryMetrics(ctx)
 
 	if queryTimeout != 0 {
 		var cancel context.CancelFunc
@@ -221,6 +326,18 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		StartTs: startTs,
 	}
 
+	if dryRun {
+		// A dry run only estimates the cost of the query, for clients to refuse to run the
+		// queries over their budget.
+		analysis, err := (&edgraph.Server{}).AnalyzeQuery(ctx, &req)
+		if err != nil {
+			x.SetError(w, x.ErrorInvalidRequest, err)
+			return
+		}
+		writeAnalysis(w, r, analysis)
+		return
+	}
+
 	if req.StartTs == 0 {
 		// If be is set, run this as a best-effort query.
 		isBestEffort, err := parseBool(r, "be")
@@ -244,12 +361,84 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Read-only queries don't start a transaction, so a cached response is as good as a new
+	// one as long as the predicates the query reads weren't written since. The version is taken
+	// before the query runs, so that the ETag is never newer than the data of the response.
+	var etag string
+	if req.ReadOnly {
+		version, err := edgraph.QueryVersion(ctx, &req)
+		if err == nil && version != "" {
+			etag = queryETag(req.Query, req.Vars, r.URL.RawQuery,
+				r.Header.Get("X-Dgraph-AccessToken"), version)
+			if etagMatches(r.Header.Get("If-None-Match"), etag) {
+				w.Header().Set("ETag", etag)
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+		}
+	}
+
 	// Core processing happens here.
 	resp, err := (&edgraph.Server{}).Query(ctx, &req)
+	// A response too big to be kept in memory is streamed from its spill file.
+	spilled := query.SpilledJSON(ctx)
+	if spilled != nil {
+		defer spilled.Close()
+	}
+	if _, ok := errors.Cause(err).(*query.LimitError); ok {
+		x.SetErrorWithData(w, x.ErrorLimitExceeded, err)
+		return
+	}
 	if err != nil {
-		x.SetStatusWithData(w, x.ErrorInvalidRequest, err.Error())
+		x.SetErrorWithData(w, x.ErrorInvalidRequest, err)
 		return
 	}
+	if persistHash != "" {
+		// The query is only persisted once it ran, so that invalid queries aren't kept.
+		if err := edgraph.PersistQuery(persistHash, params.Query); err != nil {
+			glog.Warningf("Unable to persist query %s: %v", persistHash, err)
+		}
+	}
+	// The response to a named query is reshaped by the transform of the same name, if any.
+	if transform := edgraph.QueryTransform(&req); transform != "" {
+		if spilled != nil {
+			x.SetStatus(w, x.Error, "Response is too big to be transformed")
+			return
+		}
+		if resp.Json, err = edgraph.ApplyTransform(transform, resp.Json); err != nil {
+			code := x.Error
+			if errors.Cause(err) == edgraph.ErrUnknownTransform {
+				// The transform was removed since the query ran.
+				code = x.ErrorInvalidRequest
+			}
+			x.SetStatus(w, code, err.Error())
+			return
+		}
+	}
+
+	if graphFormat != "" {
+		if spilled != nil {
+			x.SetStatus(w, x.Error, "Response is too big to be written as a graph")
+			return
+		}
+		nodes, edges, err := query.GraphFromJSON(resp.Json)
+		if err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+			return
+		}
+		var out bytes.Buffer
+		if err := worker.EncodeGraph(&out, graphFormat, nodes, edges); err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		w.Header().Set("Content-Type", worker.GraphContentType(graphFormat))
+		x.Check2(writeResponse(w, r, out.Bytes()))
+		return
+	}
+
+	if etag != "" {
+		w.Header().Set("ETag", etag)
+	}
 
 	var out bytes.Buffer
 	writeEntry := func(key string, js []byte) {
@@ -261,15 +450,26 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 
 	e := query.Extensions{
-		Txn:     resp.Txn,
-		Latency: resp.Latency,
+		Txn:      resp.Txn,
+		Latency:  resp.Latency,
+		Warnings: query.Warnings(ctx),
+		Metrics:  worker.QueryMetricsFrom(ctx),
+		Blocks:   query.BlockStatuses(ctx),
 	}
+	e.Cursor, e.Truncated = query.Truncated(ctx)
 	js, err := json.Marshal(e)
 	if err != nil {
 		x.SetStatusWithData(w, x.Error, err.Error())
 		return
 	}
 
+	if spilled != nil {
+		if err := writeSpilledResponse(w, r, spilled, js); err != nil {
+			glog.Warningf("Unable to write spilled response: %v", err)
+		}
+		return
+	}
+
 	out.WriteRune('{')
 	writeEntry("data", resp.Json)
 	out.WriteRune(',')
@@ -279,37 +479,42 @@ func queryHandler(w http.ResponseWriter, r *http.Request) {
 	x.Check2(writeResponse(w, r, out.Bytes()))
 }
 
-func mutationHandler(w http.ResponseWriter, r *http.Request) {
-	if commonHandler(w, r) {
-		return
-	}
+// writeSpilledResponse is like writeResponse for a query response whose data was spilled to
+// disk, streaming the data from its file.
+func writeSpilledResponse(w http.ResponseWriter, r *http.Request, data *query.SpillBuffer,
+	extensions []byte) error {
+	var out io.Writer = w
 
-	commitNow, err := parseBool(r, "commitNow")
-	if err != nil {
-		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
-		return
-	}
-	startTs, err := parseUint64(r, "startTs")
-	if err != nil {
-		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
-		return
-	}
-	body := readRequest(w, r)
-	if body == nil {
-		return
+	if strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+		w.Header().Set("Content-Encoding", "gzip")
+		gzw := gzip.NewWriter(w)
+		defer gzw.Close()
+		out = gzw
 	}
 
-	// start parsing the query
-	parseStart := time.Now()
+	bw := bufio.NewWriter(out)
+	bw.WriteString(`{"data":`)
+	if _, err := data.WriteTo(bw); err != nil {
+		return err
+	}
+	bw.WriteString(`,"extensions":`)
+	bw.Write(extensions)
+	bw.WriteByte('}')
+	return bw.Flush()
+}
 
+// readMutation parses the mutation of the body of a request, in the format of its Content-Type.
+// It writes the error and returns nil if the mutation can't be parsed.
+func readMutation(w http.ResponseWriter, r *http.Request, body []byte) *api.Mutation {
 	var mu *api.Mutation
+	var err error
 	contentType := r.Header.Get("Content-Type")
 	switch strings.ToLower(contentType) {
 	case "application/json":
 		ms := make(map[string]*skipJSONUnmarshal)
 		if err := json.Unmarshal(body, &ms); err != nil {
 			x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
-			return
+			return nil
 		}
 
 		mu = &api.Mutation{}
@@ -323,14 +528,14 @@ func mutationHandler(w http.ResponseWriter, r *http.Request) {
 			mu.Query, err = strconv.Unquote(string(queryText.bs))
 			if err != nil {
 				x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
-				return
+				return nil
 			}
 		}
 		if condText, ok := ms["cond"]; ok && condText != nil {
 			mu.Cond, err = strconv.Unquote(string(condText.bs))
 			if err != nil {
 				x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
-				return
+				return nil
 			}
 		}
 
@@ -339,12 +544,46 @@ func mutationHandler(w http.ResponseWriter, r *http.Request) {
 		mu, err = gql.ParseMutation(string(body))
 		if err != nil {
 			x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
-			return
+			return nil
 		}
 
 	default:
 		x.SetStatus(w, x.ErrorInvalidRequest, "Unsupported Content-Type. "+
 			"Supported content types are application/json, application/rdf")
+		return nil
+	}
+	return mu
+}
+
+func mutationHandler(w http.ResponseWriter, r *http.Request) {
+	if commonHandler(w, r) {
+		return
+	}
+
+	commitNow, err := parseBool(r, "commitNow")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	async, err := parseBool(r, "async")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	startTs, err := parseUint64(r, "startTs")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	body := readRequest(w, r)
+	if body == nil {
+		return
+	}
+
+	// start parsing the query
+	parseStart := time.Now()
+	mu := readMutation(w, r, body)
+	if mu == nil {
 		return
 	}
 
@@ -354,10 +593,17 @@ func mutationHandler(w http.ResponseWriter, r *http.Request) {
 	mu.StartTs = startTs
 	mu.CommitNow = commitNow
 
-	ctx := attachAccessJwt(context.Background(), r)
-	resp, err := (&edgraph.Server{}).Mutate(ctx, mu)
+	ctx := attachIdempotencyKey(attachAccessJwt(context.Background(), r), r)
+	var resp *api.Assigned
+	var labels *query.UidLabels
+	var ticket string
+	if async {
+		resp, labels, ticket, err = (&edgraph.Server{}).MutateAsync(ctx, mu)
+	} else {
+		resp, labels, err = (&edgraph.Server{}).MutateWithLabels(ctx, mu)
+	}
 	if err != nil {
-		x.SetStatusWithData(w, x.ErrorInvalidRequest, err.Error())
+		x.SetErrorWithData(w, x.ErrorInvalidRequest, err)
 		return
 	}
 
@@ -366,6 +612,9 @@ func mutationHandler(w http.ResponseWriter, r *http.Request) {
 		Txn:     resp.Context,
 		Latency: resp.Latency,
 	}
+	if len(labels.BlankNodes) > 0 || len(labels.Vars) > 0 {
+		e.Uids = labels
+	}
 	sort.Strings(e.Txn.Keys)
 	sort.Strings(e.Txn.Preds)
 
@@ -374,6 +623,48 @@ func mutationHandler(w http.ResponseWriter, r *http.Request) {
 		e.Txn.Keys = e.Txn.Keys[:0]
 	}
 
+	response := map[string]interface{}{}
+	response["extensions"] = e
+	mp := map[string]interface{}{}
+	mp["code"] = x.Success
+	mp["message"] = "Done"
+	mp["uids"] = resp.Uids
+	if async {
+		mp["message"] = "Logged"
+		mp["ticket"] = ticket
+	}
+	response["data"] = mp
+
+	js, err := json.Marshal(response)
+	if err != nil {
+		x.SetStatusWithData(w, x.Error, err.Error())
+		return
+	}
+
+	_, _ = writeResponse(w, r, js)
+}
+
+// awaitHandler waits for the async mutation of the ticket parameter to be committed, and
+// returns its result like mutationHandler.
+func awaitHandler(w http.ResponseWriter, r *http.Request) {
+	if commonHandler(w, r) {
+		return
+	}
+
+	ticket := r.URL.Query().Get("ticket")
+	if ticket == "" {
+		x.SetStatus(w, x.ErrorInvalidRequest, "ticket parameter is mandatory")
+		return
+	}
+	resp, err := edgraph.AwaitMutation(r.Context(), ticket)
+	if err != nil {
+		x.SetErrorWithData(w, x.ErrorInvalidRequest, err)
+		return
+	}
+
+	e := query.Extensions{Txn: resp.Context}
+	e.Txn.Keys = e.Txn.Keys[:0]
+	sort.Strings(e.Txn.Preds)
 	response := map[string]interface{}{}
 	response["extensions"] = e
 	mp := map[string]interface{}{}
@@ -426,7 +717,7 @@ func commitHandler(w http.ResponseWriter, r *http.Request) {
 		response, err = handleCommit(startTs, reqText)
 	}
 	if err != nil {
-		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		x.SetError(w, x.ErrorInvalidRequest, err)
 		return
 	}
 
@@ -446,7 +737,7 @@ func handleAbort(startTs uint64) (map[string]interface{}, error) {
 	}
 
 	_, err := worker.CommitOverNetwork(context.Background(), tc)
-	switch err {
+	switch errors.Cause(err) {
 	case y.ErrAborted:
 		return map[string]interface{}{
 			"code":    x.Success,
@@ -504,6 +795,21 @@ func handleCommit(startTs uint64, reqText []byte) (map[string]interface{}, error
 	return response, nil
 }
 
+// attachIdempotencyKey attaches the Idempotency-Key header of the request to the context, as
+// the idempotency-key metadata of gRPC requests.
+func attachIdempotencyKey(ctx context.Context, r *http.Request) context.Context {
+	if key := r.Header.Get("Idempotency-Key"); key != "" {
+		md, ok := metadata.FromIncomingContext(ctx)
+		if !ok {
+			md = metadata.New(nil)
+		}
+
+		md.Append("idempotency-key", key)
+		ctx = metadata.NewIncomingContext(ctx, md)
+	}
+	return ctx
+}
+
 func attachAccessJwt(ctx context.Context, r *http.Request) context.Context {
 	if accessJwt := r.Header.Get("X-Dgraph-AccessToken"); accessJwt != "" {
 		md, ok := metadata.FromIncomingContext(ctx)
@@ -527,6 +833,12 @@ func alterHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	background, err := parseBool(r, "runInBackground")
+	if err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+
 	op := &api.Operation{}
 	if err := jsonpb.UnmarshalString(string(b), op); err != nil {
 		op.Schema = string(b)
@@ -541,10 +853,13 @@ func alterHandler(w http.ResponseWriter, r *http.Request) {
 	md := metadata.New(nil)
 	// Pass in an auth token, if present.
 	md.Append("auth-token", r.Header.Get("X-Dgraph-AuthToken"))
+	if background {
+		md.Append("run_in_background", "true")
+	}
 	ctx := metadata.NewIncomingContext(context.Background(), md)
 	ctx = attachAccessJwt(ctx, r)
 	if _, err := (&edgraph.Server{}).Alter(ctx, op); err != nil {
-		x.SetStatus(w, x.Error, err.Error())
+		x.SetError(w, x.Error, err)
 		return
 	}
 
@@ -563,6 +878,351 @@ func alterHandler(w http.ResponseWriter, r *http.Request) {
 	_, _ = writeResponse(w, r, js)
 }
 
+// xidsHandler resolves a batch of external ids of an @xid predicate to uids. It's meant for
+// loaders that need the uids before sending the mutations.
+func xidsHandler(w http.ResponseWriter, r *http.Request) {
+	if commonHandler(w, r) {
+		return
+	}
+
+	b := readRequest(w, r)
+	if b == nil {
+		return
+	}
+
+	var req struct {
+		Predicate string   `json:"predicate"`
+		Xids      []string `json:"xids"`
+		Create    bool     `json:"create"`
+	}
+	if err := json.Unmarshal(b, &req); err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+
+	ctx := attachAccessJwt(context.Background(), r)
+	uids, err := (&edgraph.Server{}).ResolveXids(ctx, req.Predicate, req.Xids, req.Create)
+	if err != nil {
+		x.SetError(w, x.Error, err)
+		return
+	}
+
+	res := map[string]interface{}{}
+	res["data"] = map[string]interface{}{
+		"code":    x.Success,
+		"message": "Done",
+		"uids":    uids,
+	}
+
+	js, err := json.Marshal(res)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+
+	_, _ = writeResponse(w, r, js)
+}
+
+// existsHandler checks which nodes of a batch of uids, or of external ids of an @xid predicate,
+// exist. It's meant for loaders and sync jobs which only need to know if the nodes are there.
+func existsHandler(w http.ResponseWriter, r *http.Request) {
+	if commonHandler(w, r) {
+		return
+	}
+
+	b := readRequest(w, r)
+	if b == nil {
+		return
+	}
+
+	var req struct {
+		Uids      []string `json:"uids"`
+		Predicate string   `json:"predicate"`
+		Xids      []string `json:"xids"`
+	}
+	if err := json.Unmarshal(b, &req); err != nil {
+		x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+		return
+	}
+	if len(req.Uids) > 0 && len(req.Xids) > 0 {
+		x.SetStatus(w, x.ErrorInvalidRequest, "Only one of uids and xids can be checked at once")
+		return
+	}
+
+	ctx := attachAccessJwt(context.Background(), r)
+	var bitmap []byte
+	var err error
+	if len(req.Xids) > 0 {
+		bitmap, err = (&edgraph.Server{}).XidsExist(ctx, req.Predicate, req.Xids)
+	} else {
+		uids := make([]uint64, len(req.Uids))
+		for i, s := range req.Uids {
+			if uids[i], err = gql.ParseUid(s); err != nil {
+				x.SetStatus(w, x.ErrorInvalidRequest, fmt.Sprintf("Invalid uid %q: %v", s, err))
+				return
+			}
+		}
+		bitmap, err = (&edgraph.Server{}).UidsExist(ctx, uids)
+	}
+	if err != nil {
+		x.SetError(w, x.Error, err)
+		return
+	}
+
+	var count int
+	for _, b := range bitmap {
+		count += bits.OnesCount8(b)
+	}
+	res := map[string]interface{}{}
+	res["data"] = map[string]interface{}{
+		"code":    x.Success,
+		"message": "Done",
+		"exists":  bitmap,
+		"count":   count,
+	}
+
+	js, err := json.Marshal(res)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+
+	_, _ = writeResponse(w, r, js)
+}
+
+// validateHandler checks a mutation against the schema without running it, as in strict mode:
+// it returns the n-quads of predicates which aren't declared, and the values which don't fit the
+// type of their predicate. It's meant for loaders to check their data before loading it.
+func validateHandler(w http.ResponseWriter, r *http.Request) {
+	if commonHandler(w, r) {
+		return
+	}
+
+	body := readRequest(w, r)
+	if body == nil {
+		return
+	}
+	mu := readMutation(w, r, body)
+	if mu == nil {
+		return
+	}
+
+	ctx := attachAccessJwt(context.Background(), r)
+	violations, err := (&edgraph.Server{}).ValidateMutation(ctx, mu)
+	if err != nil {
+		x.SetError(w, x.ErrorInvalidRequest, err)
+		return
+	}
+	if violations == nil {
+		violations = []edgraph.SchemaViolation{}
+	}
+
+	res := map[string]interface{}{}
+	res["data"] = map[string]interface{}{
+		"code":       x.Success,
+		"message":    "Done",
+		"valid":      len(violations) == 0,
+		"violations": violations,
+	}
+
+	js, err := json.Marshal(res)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+
+	_, _ = writeResponse(w, r, js)
+}
+
+// analyzeHandler parses a query and checks it against the schema without running it. It returns
+// the structure of the query, the issues it would fail or run with, such as functions on
+// predicates missing their index or unbounded recursions, and an estimate of its cost, so that the
+// queries of applications can be checked before they're deployed.
+func analyzeHandler(w http.ResponseWriter, r *http.Request) {
+	if commonHandler(w, r) {
+		return
+	}
+
+	body := readRequest(w, r)
+	if body == nil {
+		return
+	}
+	var params struct {
+		Query     string            `json:"query"`
+		Variables map[string]string `json:"variables"`
+	}
+	switch strings.ToLower(r.Header.Get("Content-Type")) {
+	case "application/json":
+		if err := json.Unmarshal(body, &params); err != nil {
+			x.SetStatus(w, x.ErrorInvalidRequest, err.Error())
+			return
+		}
+	case "application/graphql+-":
+		params.Query = string(body)
+	default:
+		x.SetStatus(w, x.ErrorInvalidRequest, "Unsupported Content-Type. "+
+			"Supported content types are application/json, application/graphql+-")
+		return
+	}
+
+	ctx := attachAccessJwt(context.Background(), r)
+	analysis, err := (&edgraph.Server{}).AnalyzeQuery(ctx,
+		&api.Request{Query: params.Query, Vars: params.Variables})
+	if err != nil {
+		x.SetError(w, x.ErrorInvalidRequest, err)
+		return
+	}
+	writeAnalysis(w, r, analysis)
+}
+
+// writeAnalysis writes the analysis of a query, which is valid if it has no errors.
+func writeAnalysis(w http.ResponseWriter, r *http.Request, analysis *edgraph.QueryAnalysis) {
+	if analysis.Errors == nil {
+		analysis.Errors = []string{}
+	}
+	if analysis.Warnings == nil {
+		analysis.Warnings = []string{}
+	}
+
+	res := map[string]interface{}{}
+	res["data"] = map[string]interface{}{
+		"code":     x.Success,
+		"message":  "Done",
+		"valid":    len(analysis.Errors) == 0,
+		"blocks":   analysis.Blocks,
+		"errors":   analysis.Errors,
+		"warnings": analysis.Warnings,
+		"cost":     analysis.Cost,
+		"estimate": analysis.Estimate,
+	}
+
+	js, err := json.Marshal(res)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+
+	_, _ = writeResponse(w, r, js)
+}
+
+// jsonSchemaHandler returns the JSON Schema of the types, or with format=openapi the schemas of
+// OpenAPI components, so that REST layers and clients can generate their models and validators
+// from them. The types are given as a comma separated list in types, or are all of them.
+func jsonSchemaHandler(w http.ResponseWriter, r *http.Request) {
+	x.AddCorsHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+		return
+	}
+
+	var names []string
+	if list := r.URL.Query().Get("types"); list != "" {
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	var openAPI bool
+	switch format := r.URL.Query().Get("format"); format {
+	case "", "jsonschema":
+	case "openapi":
+		openAPI = true
+	default:
+		x.SetStatus(w, x.ErrorInvalidRequest, fmt.Sprintf(
+			"Invalid format %q. Supported formats are jsonschema and openapi", format))
+		return
+	}
+
+	ctx := attachAccessJwt(context.Background(), r)
+	res, err := (&edgraph.Server{}).TypesSchema(ctx, names, openAPI)
+	if err != nil {
+		x.SetError(w, x.ErrorInvalidRequest, err)
+		return
+	}
+	js, err := json.Marshal(res)
+	if err != nil {
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+
+	_, _ = writeResponse(w, r, js)
+}
+
+// queryETag returns the ETag of the result of a query, derived from the query with its
+// variables, the options in the URL, the access token and the version of the result.
+func queryETag(query string, vars map[string]string, options, token, version string) string {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := sha256.New()
+	h.Write([]byte(query))
+	for _, k := range keys {
+		h.Write([]byte{0})
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(vars[k]))
+	}
+	for _, s := range []string{options, token, version} {
+		h.Write([]byte{0})
+		h.Write([]byte(s))
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// etagMatches tells if the If-None-Match header lists the ETag. Weak ETags are compared
+// like strong ones.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// persistedQueryExt is the extension sent by clients using automatic persisted queries.
+type persistedQueryExt struct {
+	Version    int    `json:"version"`
+	Sha256Hash string `json:"sha256Hash"`
+}
+
+// errPersistedQueryNotFound is the message clients look for to send the query text along
+// with its hash.
+var errPersistedQueryNotFound = errors.New(x.ErrorPersistedQueryNotFound)
+
+// resolvePersistedQuery fills in the text of a query sent only by its hash. If the text was
+// sent, it returns the hash under which to persist the query once it ran.
+func resolvePersistedQuery(pq *persistedQueryExt, query *string) (string, error) {
+	if x.Config.PersistedQueries <= 0 {
+		return "", errors.New("Persisted queries are disabled")
+	}
+	if pq.Version != 1 {
+		return "", errors.Errorf("Unsupported persisted query version: %d", pq.Version)
+	}
+	if pq.Sha256Hash == "" {
+		return "", errors.New("Persisted query requires a sha256Hash")
+	}
+	if *query == "" {
+		q, ok := edgraph.LookupPersistedQuery(pq.Sha256Hash)
+		if !ok {
+			return "", errPersistedQueryNotFound
+		}
+		*query = q
+		return "", nil
+	}
+	if edgraph.QueryHash(*query) != strings.ToLower(pq.Sha256Hash) {
+		return "", errors.New("Provided sha256Hash does not match the query")
+	}
+	return pq.Sha256Hash, nil
+}
+
 // skipJSONUnmarshal stores the raw bytes as is while JSON unmarshaling.
 type skipJSONUnmarshal struct {
 	bs []byte
@@ -673,3 +673,109 @@ func TestHealth(t *testing.T) {
 	require.Equal(t, "alpha", info.Instance)
 	require.True(t, info.Uptime > time.Duration(1))
 }
+
+func TestQueryETag(t *testing.T) {
+	q := `query q($name: string) { q(func: eq(name, $name)) { name } }`
+	vars := map[string]string{"$name": "Alice"}
+	etag := queryETag(q, vars, "ro=true", "", "1-0-5")
+	require.Equal(t, etag, queryETag(q, map[string]string{"$name": "Alice"}, "ro=true", "",
+		"1-0-5"))
+	require.NotEqual(t, etag, queryETag(q, map[string]string{"$name": "Bob"}, "ro=true", "",
+		"1-0-5"))
+	require.NotEqual(t, etag, queryETag(q, vars, "ro=true&lang=fr", "", "1-0-5"))
+	require.NotEqual(t, etag, queryETag(q, vars, "ro=true", "token", "1-0-5"))
+	require.NotEqual(t, etag, queryETag(q, vars, "ro=true", "", "1-0-6"))
+
+	require.True(t, etagMatches(etag, etag))
+	require.True(t, etagMatches(`"abc", W/`+etag, etag))
+	require.True(t, etagMatches("*", etag))
+	require.False(t, etagMatches("", etag))
+	require.False(t, etagMatches(`"abc"`, etag))
+}
+
+func TestQueryNotModified(t *testing.T) {
+	send := func(q, etag string) (int, string) {
+		req, err := http.NewRequest("POST", addr+"/query?ro=true", bytes.NewBufferString(q))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/graphql+-")
+		if etag != "" {
+			req.Header.Set("If-None-Match", etag)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode, resp.Header.Get("ETag")
+	}
+
+	q := `{ q(func: has(etag_name)) { etag_name } }`
+	other := `{ q(func: uid(0x1)) { uid } }`
+	// The ETag of a predicate no alpha serves yet changes with every query.
+	require.NoError(t, runMutation(`{ set { _:a <etag_name> "Bob" . } }`))
+	send(q, "")
+	_, etag := send(q, "")
+	require.NotEmpty(t, etag)
+	_, otherETag := send(other, "")
+	require.NotEmpty(t, otherETag)
+
+	status, _ := send(q, etag)
+	require.Equal(t, http.StatusNotModified, status)
+
+	// A write to a predicate of the query changes its ETag, but not the one of other queries.
+	require.NoError(t, runMutation(`{ set { _:a <etag_name> "Alice" . } }`))
+	status, _ = send(q, etag)
+	require.Equal(t, http.StatusOK, status)
+	_, newETag := send(q, "")
+	require.NotEqual(t, etag, newETag)
+	status, _ = send(q, newETag)
+	require.Equal(t, http.StatusNotModified, status)
+	status, _ = send(other, otherETag)
+	require.Equal(t, http.StatusNotModified, status)
+}
+
+func TestQueryTransform(t *testing.T) {
+	getData := func(body []byte) []byte {
+		var r struct {
+			Data json.RawMessage `json:"data"`
+		}
+		require.NoError(t, json.Unmarshal(body, &r))
+		return r.Data
+	}
+
+	require.NoError(t, runMutation(`{ set { _:a <transform_name> "Alice" . } }`))
+	_, _, err := runWithRetries("PUT", "application/json", addr+"/admin/transforms",
+		`{"name": "transform_legacy", "template": "{\"user\": {{json (index .q 0).transform_name}}}"}`)
+	require.NoError(t, err)
+
+	// The transform applies to the queries of the same name only.
+	q := `query transform_legacy { q(func: has(transform_name)) { transform_name } }`
+	_, body, err := runWithRetries("POST", "application/graphql+-", addr+"/query", q)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"user": "Alice"}`, string(getData(body)))
+	q = `query other { q(func: has(transform_name)) { transform_name } }`
+	_, body, err = runWithRetries("POST", "application/graphql+-", addr+"/query", q)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"q": [{"transform_name": "Alice"}]}`, string(getData(body)))
+
+	_, body, err = runWithRetries("GET", "", addr+"/admin/transforms", "")
+	require.NoError(t, err)
+	require.Contains(t, string(body), `"name":"transform_legacy"`)
+
+	_, _, err = runWithRetries("DELETE", "", addr+"/admin/transforms?name=transform_legacy", "")
+	require.NoError(t, err)
+	q = `query transform_legacy { q(func: has(transform_name)) { transform_name } }`
+	_, body, err = runWithRetries("POST", "application/graphql+-", addr+"/query", q)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"q": [{"transform_name": "Alice"}]}`, string(getData(body)))
+
+	// Removing a transform that isn't registered is an invalid request.
+	req, err := createRequest("DELETE", "", addr+"/admin/transforms?name=transform_legacy", "")
+	require.NoError(t, err)
+	req.Header.Set("X-Dgraph-AccessToken", grootAccessJwt)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	var qr x.QueryResWithData
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&qr))
+	require.Len(t, qr.Errors, 1)
+	require.Equal(t, x.ErrorInvalidRequest, qr.Errors[0].Extensions["code"])
+}
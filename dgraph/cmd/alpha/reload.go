@@ -0,0 +1,329 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package alpha
+
+import (
+	"encoding/json"
+	goflag "flag"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"github.com/spf13/cast"
+
+	"github.com/dgraph-io/dgraph/edgraph"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// reloadableFlag is a flag of the Alpha which can be changed while it runs.
+type reloadableFlag struct {
+	// get returns the current value.
+	get func() string
+	// parse returns the canonical form of the value, or an error if it's invalid.
+	parse func(v string) (string, error)
+	// set applies a value returned by parse.
+	set func(v string)
+}
+
+// configChange is an entry of the audit trail of the flags changed at runtime.
+type configChange struct {
+	Time   time.Time `json:"time"`
+	Flag   string    `json:"flag"`
+	Old    string    `json:"old"`
+	New    string    `json:"new"`
+	Source string    `json:"source"`
+}
+
+// maxConfigChanges is the number of changes kept in the audit trail. All of them are logged.
+const maxConfigChanges = 100
+
+var configChanges struct {
+	sync.Mutex
+	log []configChange
+}
+
+func uint64Flag(p *uint64) reloadableFlag {
+	return reloadableFlag{
+		get: func() string {
+			x.ConfigMu.RLock()
+			defer x.ConfigMu.RUnlock()
+			return strconv.FormatUint(*p, 10)
+		},
+		parse: func(v string) (string, error) {
+			n, err := cast.ToUint64E(v)
+			return strconv.FormatUint(n, 10), err
+		},
+		set: func(v string) {
+			x.ConfigMu.Lock()
+			defer x.ConfigMu.Unlock()
+			*p = cast.ToUint64(v)
+		},
+	}
+}
+
+func intFlag(p *int) reloadableFlag {
+	return reloadableFlag{
+		get: func() string {
+			x.ConfigMu.RLock()
+			defer x.ConfigMu.RUnlock()
+			return strconv.Itoa(*p)
+		},
+		parse: func(v string) (string, error) {
+			n, err := cast.ToIntE(v)
+			if err == nil && n < 0 {
+				err = errors.Errorf("Invalid negative value %d", n)
+			}
+			return strconv.Itoa(n), err
+		},
+		set: func(v string) {
+			x.ConfigMu.Lock()
+			defer x.ConfigMu.Unlock()
+			*p = cast.ToInt(v)
+		},
+	}
+}
+
+func durationFlag(p *time.Duration) reloadableFlag {
+	return reloadableFlag{
+		get: func() string {
+			x.ConfigMu.RLock()
+			defer x.ConfigMu.RUnlock()
+			return p.String()
+		},
+		parse: func(v string) (string, error) {
+			d, err := time.ParseDuration(v)
+			if err == nil && d < 0 {
+				err = errors.Errorf("Invalid negative duration %s", d)
+			}
+			return d.String(), err
+		},
+		set: func(v string) {
+			d, _ := time.ParseDuration(v)
+			x.ConfigMu.Lock()
+			defer x.ConfigMu.Unlock()
+			*p = d
+		},
+	}
+}
+
+// reloadableFlags are the flags which can be changed on /admin/config, or in the config file
+// followed by a SIGHUP.
+var reloadableFlags = map[string]reloadableFlag{
+	"query_edge_limit":        uint64Flag(&x.Config.QueryEdgeLimit),
+	"query_depth_limit":       uint64Flag(&x.Config.QueryDepthLimit),
+	"query_node_limit":        uint64Flag(&x.Config.QueryNodeLimit),
+	"query_fanout_limit":      uint64Flag(&x.Config.QueryFanoutLimit),
+	"normalize_node_limit":    intFlag(&x.Config.NormalizeNodeLimit),
+	"query_timeout":           durationFlag(&x.Config.QueryTimeout),
+	"custom_resolver_timeout": durationFlag(&x.Config.CustomResolverTimeout),
+	"index_build_rate":        uint64Flag(&x.Config.IndexBuildRate),
+	"lru_mb": {
+		get: func() string {
+			posting.Config.Mu.Lock()
+			defer posting.Config.Mu.Unlock()
+			return strconv.FormatFloat(posting.Config.AllottedMemory, 'f', -1, 64)
+		},
+		parse: func(v string) (string, error) {
+			mb, err := cast.ToFloat64E(v)
+			if err == nil && mb < edgraph.MinAllottedMemory {
+				err = errors.Errorf("lru_mb must be at least %.0f", edgraph.MinAllottedMemory)
+			}
+			return strconv.FormatFloat(mb, 'f', -1, 64), err
+		},
+		set: func(v string) {
+			posting.Config.Mu.Lock()
+			defer posting.Config.Mu.Unlock()
+			posting.Config.AllottedMemory = cast.ToFloat64(v)
+		},
+	},
+	"proposal_batch_bytes": {
+		get: func() string { return strconv.Itoa(worker.ProposalBatching().MaxBytes) },
+		parse: func(v string) (string, error) {
+			opts := worker.ProposalBatching()
+			n, err := cast.ToIntE(v)
+			if err != nil {
+				return "", err
+			}
+			opts.MaxBytes = n
+			return strconv.Itoa(n), opts.Validate()
+		},
+		set: func(v string) {
+			opts := worker.ProposalBatching()
+			opts.MaxBytes = cast.ToInt(v)
+			x.Check(worker.SetProposalBatching(opts))
+		},
+	},
+	"proposal_batch_latency": {
+		get: func() string { return worker.ProposalBatching().MaxLatency.String() },
+		parse: func(v string) (string, error) {
+			opts := worker.ProposalBatching()
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return "", err
+			}
+			opts.MaxLatency = d
+			return d.String(), opts.Validate()
+		},
+		set: func(v string) {
+			opts := worker.ProposalBatching()
+			opts.MaxLatency, _ = time.ParseDuration(v)
+			x.Check(worker.SetProposalBatching(opts))
+		},
+	},
+	// The verbosity of the logs.
+	"v": {
+		get: func() string { return goflag.Lookup("v").Value.String() },
+		parse: func(v string) (string, error) {
+			n, err := cast.ToInt32E(v)
+			return strconv.Itoa(int(n)), err
+		},
+		set: func(v string) { x.Check(goflag.Lookup("v").Value.Set(v)) },
+	},
+}
+
+// currentFlags returns the current values of the reloadable flags.
+func currentFlags() map[string]string {
+	values := make(map[string]string)
+	for name, f := range reloadableFlags {
+		values[name] = f.get()
+	}
+	return values
+}
+
+// setFlags changes the reloadable flags to the values, and records the changes in the audit
+// trail. No flag is changed if any of the values is invalid.
+func setFlags(values map[string]string, source string) error {
+	parsed := make(map[string]string)
+	for name, v := range values {
+		f, ok := reloadableFlags[name]
+		if !ok {
+			return errors.Errorf("Flag %s can't be changed at runtime", name)
+		}
+		norm, err := f.parse(v)
+		if err != nil {
+			return errors.Wrapf(err, "while parsing flag %s", name)
+		}
+		parsed[name] = norm
+	}
+
+	names := make([]string, 0, len(parsed))
+	for name := range parsed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	configChanges.Lock()
+	defer configChanges.Unlock()
+	for _, name := range names {
+		f := reloadableFlags[name]
+		change := configChange{
+			Time:   time.Now(),
+			Flag:   name,
+			Old:    f.get(),
+			New:    parsed[name],
+			Source: source,
+		}
+		if change.Old == change.New {
+			continue
+		}
+		f.set(change.New)
+		glog.Infof("Flag %s changed from %s to %s by %s", name, change.Old, change.New, source)
+		configChanges.log = append(configChanges.log, change)
+		if len(configChanges.log) > maxConfigChanges {
+			configChanges.log = configChanges.log[len(configChanges.log)-maxConfigChanges:]
+		}
+	}
+	return nil
+}
+
+// reloadConfigFile reads the config file again, and changes the reloadable flags set in it.
+// The flags given on the command line take precedence over the file, as they do on startup.
+func reloadConfigFile() error {
+	file := Alpha.Conf.ConfigFileUsed()
+	if file == "" {
+		return errors.Errorf("No config file to reload, start the Alpha with --config")
+	}
+	if err := Alpha.Conf.ReadInConfig(); err != nil {
+		return errors.Wrapf(err, "while reading config file %s", file)
+	}
+	values := make(map[string]string)
+	for name := range reloadableFlags {
+		if !Alpha.Conf.IsSet(name) {
+			continue
+		}
+		v, err := cast.ToStringE(Alpha.Conf.Get(name))
+		if err != nil {
+			return errors.Wrapf(err, "while reading flag %s", name)
+		}
+		values[name] = v
+	}
+	return setFlags(values, "config file "+file)
+}
+
+// configHandler returns the reloadable flags and the audit trail of their changes on GET, and
+// changes the flags given in the JSON body on PUT.
+func configHandler(w http.ResponseWriter, r *http.Request) {
+	if !handlerInit(w, r, r.Method) {
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	switch r.Method {
+	case http.MethodGet:
+		configChanges.Lock()
+		changes := append([]configChange{}, configChanges.log...)
+		configChanges.Unlock()
+		js, err := json.Marshal(map[string]interface{}{
+			"data": map[string]interface{}{
+				"flags":   currentFlags(),
+				"changes": changes,
+			},
+		})
+		if err != nil {
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		x.Check2(w.Write(js))
+	case http.MethodPut:
+		dec := json.NewDecoder(r.Body)
+		dec.UseNumber()
+		var req map[string]interface{}
+		if err := dec.Decode(&req); err != nil {
+			x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		values := make(map[string]string)
+		for name, v := range req {
+			values[name] = fmt.Sprint(v)
+		}
+		ip, _, _ := net.SplitHostPort(r.RemoteAddr)
+		if err := setFlags(values, "admin request from "+ip); err != nil {
+			x.SetHttpStatus(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		x.Check2(w.Write([]byte(`{"code": "Success", "message": "Config updated."}`)))
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
@@ -26,8 +26,10 @@ import (
 	"net"
 	"net/http"
 	_ "net/http/pprof" // http profiler
+	"net/url"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
 	"syscall"
@@ -35,8 +37,11 @@ import (
 
 	"github.com/dgraph-io/badger/y"
 	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/blob"
+	"github.com/dgraph-io/dgraph/dgraph/cmd/zero"
 	"github.com/dgraph-io/dgraph/edgraph"
 	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/tok"
 	"github.com/dgraph-io/dgraph/worker"
@@ -49,6 +54,7 @@ import (
 	"go.opencensus.io/plugin/ocgrpc"
 	otrace "go.opencensus.io/trace"
 	"go.opencensus.io/zpages"
+	"golang.org/x/crypto/bcrypt"
 	"golang.org/x/net/context"
 	"golang.org/x/net/trace"
 	"google.golang.org/grpc"
@@ -96,6 +102,10 @@ they form a Raft group and provide synchronous replication.
 	flag.Bool("enterprise_features", false, "Enable Dgraph enterprise features. "+
 		"If you set this to true, you agree to the Dgraph Community License.")
 	flag.StringP("postings", "p", "p", "Directory to store posting lists.")
+	flag.Bool("ephemeral", false,
+		"Run a single node cluster with Zero in this process, keeping all the data in a"+
+			" temporary directory removed on shutdown. The postings, wal and zero options"+
+			" are ignored. Meant for tests.")
 
 	// Options around how to set up Badger.
 	flag.String("badger.tables", "mmap",
@@ -130,6 +140,13 @@ they form a Raft group and provide synchronous replication.
 	flag.String("export", "export", "Folder in which to store exports.")
 	flag.Int("pending_proposals", 256,
 		"Number of pending mutation proposals. Useful for rate limiting.")
+	flag.Int("proposal_batch_bytes", 1<<20,
+		"Size in bytes over which a batch of Raft proposals is sent without waiting for more"+
+			" proposals.")
+	flag.Duration("proposal_batch_latency", 0,
+		"How long a Raft proposal waits for more proposals to batch with. Higher values"+
+			" increase the throughput of ingest-heavy workloads, at the cost of latency."+
+			" 0 only batches the proposals which are already waiting.")
 	flag.String("my", "",
 		"IP_ADDRESS:PORT of this Dgraph Alpha, so other Dgraph Alphas can talk to this.")
 	flag.StringP("zero", "z", fmt.Sprintf("localhost:%d", x.PortZeroGrpc),
@@ -157,7 +174,9 @@ they form a Raft group and provide synchronous replication.
 		"Estimated memory the LRU cache can take. "+
 			"Actual usage by the process would be more than specified here.")
 	flag.String("mutations", "allow",
-		"Set mutation mode to allow, disallow, or strict.")
+		"Set mutation mode to allow, disallow, or strict. In strict mode, the mutations of"+
+			" predicates which aren't in the schema, or with values which don't fit their"+
+			" type, are rejected instead of creating the predicates.")
 
 	// Useful for running multiple servers on the same machine.
 	flag.IntP("port_offset", "o", 0,
@@ -166,6 +185,15 @@ they form a Raft group and provide synchronous replication.
 	flag.Uint64("query_edge_limit", 1e6,
 		"Limit for the maximum number of edges that can be returned in a query."+
 			" This applies to shortest path and recursive queries.")
+	flag.Uint64("query_depth_limit", 0,
+		"Limit for the maximum number of nested levels in the result of a query. 0 means no limit.")
+	flag.Uint64("query_node_limit", 0,
+		"Limit for the maximum number of nodes in the result of a query. 0 means no limit.")
+	flag.Uint64("query_fanout_limit", 0,
+		"Limit for the maximum number of nodes a node can have for one predicate in the result"+
+			" of a query. 0 means no limit.")
+	flag.Duration("query_timeout", 0,
+		"Maximum duration of a query. 0 means no limit.")
 	flag.Uint64("normalize_node_limit", 1e4,
 		"Limit for the maximum number of nodes that can be returned in a query that uses the "+
 			"normalize directive.")
@@ -178,6 +206,57 @@ they form a Raft group and provide synchronous replication.
 	//Custom plugins.
 	flag.String("custom_tokenizers", "",
 		"Comma separated list of tokenizer plugins")
+	flag.String("custom_resolvers", "",
+		"Comma separated list of name=url pairs of the HTTP services resolving custom() fields")
+	flag.Duration("custom_resolver_timeout", 10*time.Second,
+		"Maximum duration of a call to a custom resolver.")
+	flag.Int("custom_resolver_batch", 1000,
+		"Maximum number of nodes sent in one call to a custom resolver.")
+	flag.Int("persisted_queries", 10000,
+		"Maximum number of persisted queries kept by the /query endpoint. 0 disables them.")
+	flag.Int("query_cache_size", 1000,
+		"Maximum number of parsed queries kept to skip parsing them again. 0 disables the cache.")
+	flag.Int("response_spill_size", 1<<30,
+		"Size in bytes over which the JSON of a response is moved to a temporary file while it's"+
+			" built. 0 keeps responses in memory.")
+	flag.String("response_spill_dir", "",
+		"Directory of the files holding spilled responses. Defaults to the temporary directory.")
+	flag.Int("binary_summary_size", 1<<20,
+		"Size in bytes over which only the size and SHA-256 hash of binary values are returned,"+
+			" unless a request asks for the full values. 0 always returns values.")
+
+	// Passwords.
+	flag.String("password_hash", "bcrypt",
+		"Hash of new passwords, bcrypt or argon2id.")
+	flag.Int("bcrypt_cost", bcrypt.DefaultCost,
+		"Cost of the bcrypt hash of passwords.")
+	flag.Uint32("argon2_time", 1,
+		"Number of passes over memory of the argon2id hash of passwords.")
+	flag.Uint32("argon2_memory", 64*1024,
+		"Memory in KiB used by the argon2id hash of passwords.")
+	flag.Float64("password_min_entropy", 0,
+		"Minimum estimated entropy in bits of the passwords set by mutations. 0 disables the check.")
+	flag.Int("password_max_failures", 5,
+		"Number of consecutive failed checkpwdlock calls after which a password is locked out."+
+			" 0 never locks passwords out.")
+	flag.Duration("password_lockout", 5*time.Minute,
+		"Duration for which a password is locked out after too many failed checkpwdlock calls.")
+
+	// Large values.
+	flag.String("blob_store", "",
+		"URI of the object store large values are offloaded to, shared by all the Alphas:"+
+			" file:///path, s3://host/bucket/prefix or minio://host/bucket/prefix."+
+			" Values are never offloaded if empty.")
+	flag.Int("blob_offload_size", 1<<20,
+		"Size in bytes over which string and binary values of non-indexed, non-list predicates"+
+			" are offloaded to the blob store. 0 never offloads values.")
+
+	flag.Int("sequence_lease", 100,
+		"Number of values of a sequence leased from Zero at once. Each Alpha hands out the"+
+			" values it leased, 1 keeps the values handed out by all the Alphas in order.")
+	flag.Uint64("index_build_rate", 0,
+		"Maximum number of posting lists read per second by the background index builds."+
+			" 0 means no limit.")
 
 	// By default Go GRPC traces all requests.
 	grpc.EnableTracing = false
@@ -193,6 +272,28 @@ func setupCustomTokenizers() {
 	}
 }
 
+// getCustomResolvers parses a comma-delimited list of name=url pairs.
+//
+// e.g. "score=http://localhost:9000/score,tier=https://tiers.example.org/"
+func getCustomResolvers(str string) (map[string]string, error) {
+	resolvers := make(map[string]string)
+	if str == "" {
+		return resolvers, nil
+	}
+	for _, pair := range strings.Split(str, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errors.Errorf("invalid custom resolver: %q", pair)
+		}
+		u, err := url.Parse(kv[1])
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			return nil, errors.Errorf("invalid URL for custom resolver %s: %q", kv[0], kv[1])
+		}
+		resolvers[kv[0]] = kv[1]
+	}
+	return resolvers, nil
+}
+
 // Parses a comma-delimited list of IP addresses, IP ranges, CIDR blocks, or hostnames
 // and returns a slice of []IPRange.
 //
@@ -271,9 +372,12 @@ func grpcPort() int {
 	return x.Config.PortOffset + x.PortGrpc
 }
 
+// healthCheck returns whether the Alpha is running, along with the detail of its readiness.
+// With ready=true, it returns whether the Alpha is ready to serve requests instead.
 func healthCheck(w http.ResponseWriter, r *http.Request) {
 	x.AddCorsHeaders(w)
-	if err := x.HealthCheck(); err != nil {
+	checkReady := r.URL.Query().Get("ready") == "true"
+	if err := x.HealthCheck(); err != nil && !checkReady {
 		w.WriteHeader(http.StatusServiceUnavailable)
 		return
 	}
@@ -282,15 +386,21 @@ func healthCheck(w http.ResponseWriter, r *http.Request) {
 		Version  string        `json:"version"`
 		Instance string        `json:"instance"`
 		Uptime   time.Duration `json:"uptime"`
+		readiness
 	}{
-		Version:  x.Version(),
-		Instance: "alpha",
-		Uptime:   time.Since(beginTime),
+		Version:   x.Version(),
+		Instance:  "alpha",
+		Uptime:    time.Since(beginTime),
+		readiness: *checkReadiness(),
 	}
 	data, _ := json.Marshal(info)
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
+	if checkReady && !info.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	} else {
+		w.WriteHeader(http.StatusOK)
+	}
 	_, _ = w.Write(data)
 }
 
@@ -317,6 +427,8 @@ func serveGRPC(l net.Listener, tlsCfg *tls.Config, wg *sync.WaitGroup) {
 		grpc.MaxSendMsgSize(x.GrpcMaxSize),
 		grpc.MaxConcurrentStreams(1000),
 		grpc.StatsHandler(&ocgrpc.ServerHandler{}),
+		grpc.UnaryInterceptor(x.UnaryErrorCodes),
+		grpc.StreamInterceptor(x.StreamErrorCodes),
 	}
 	if tlsCfg != nil {
 		opt = append(opt, grpc.Creds(credentials.NewTLS(tlsCfg)))
@@ -324,6 +436,7 @@ func serveGRPC(l net.Listener, tlsCfg *tls.Config, wg *sync.WaitGroup) {
 
 	s := grpc.NewServer(opt...)
 	api.RegisterDgraphServer(s, &edgraph.Server{})
+	pb.RegisterImportServer(s, &edgraph.Server{})
 	hapi.RegisterHealthServer(s, health.NewServer())
 	err := s.Serve(l)
 	glog.Errorf("GRPC listener canceled: %v\n", err)
@@ -381,7 +494,13 @@ func setupServer() {
 	http.HandleFunc("/mutate", mutationHandler)
 	http.HandleFunc("/mutate/", mutationHandler)
 	http.HandleFunc("/commit", commitHandler)
+	http.HandleFunc("/await", awaitHandler)
 	http.HandleFunc("/alter", alterHandler)
+	http.HandleFunc("/xids", xidsHandler)
+	http.HandleFunc("/exists", existsHandler)
+	http.HandleFunc("/validate", validateHandler)
+	http.HandleFunc("/analyze", analyzeHandler)
+	http.HandleFunc("/jsonschema", jsonSchemaHandler)
 	http.HandleFunc("/health", healthCheck)
 
 	// TODO: Figure out what this is for?
@@ -389,7 +508,21 @@ func setupServer() {
 
 	http.HandleFunc("/admin/shutdown", shutDownHandler)
 	http.HandleFunc("/admin/export", exportHandler)
+	http.HandleFunc("/admin/drain", drainHandler)
+	http.HandleFunc("/admin/debug/state", debugStateHandler)
 	http.HandleFunc("/admin/config/lru_mb", memoryLimitHandler)
+	http.HandleFunc("/admin/config/proposal_batching", proposalBatchingHandler)
+	http.HandleFunc("/admin/config", configHandler)
+	http.HandleFunc("/admin/persisted_queries", persistedQueriesHandler)
+	http.HandleFunc("/admin/transforms", transformsHandler)
+	http.HandleFunc("/admin/indexing", indexingHandler)
+	http.HandleFunc("/admin/indexing/pause", indexingPauseHandler)
+	http.HandleFunc("/admin/indexing/resume", indexingResumeHandler)
+	http.HandleFunc("/admin/indexing/check", indexingCheckHandler)
+	http.HandleFunc("/admin/orphans", orphansHandler)
+	http.HandleFunc("/admin/stats", graphStatsHandler)
+	http.HandleFunc("/admin/analytics", analyticsHandler)
+	http.HandleFunc("/admin/analytics/cancel", analyticsCancelHandler)
 
 	// Add OpenCensus z-pages.
 	zpages.Handle(http.DefaultServeMux, "/z")
@@ -418,9 +551,81 @@ func setupServer() {
 
 var shutdownCh chan struct{}
 
+// startEphemeral creates a temporary directory for the posting lists and the WALs, in /dev/shm
+// when it exists so that they're kept in memory, and runs Zero in this process. It returns the
+// address of Zero and a function which stops it and removes the directory.
+func startEphemeral(opts *edgraph.Options) (string, func()) {
+	var base string
+	if fi, err := os.Stat("/dev/shm"); err == nil && fi.IsDir() {
+		base = "/dev/shm"
+	}
+	dir, err := ioutil.TempDir(base, "dgraph")
+	x.Checkf(err, "Error while creating the ephemeral directory")
+	glog.Infof("Running ephemeral cluster in %s", dir)
+	opts.PostingDir = filepath.Join(dir, "p")
+	opts.WALDir = filepath.Join(dir, "w")
+
+	zeroAddr, stopZero := zero.RunEphemeral(filepath.Join(dir, "zw"),
+		Alpha.Conf.GetInt("port_offset"))
+	return zeroAddr, func() {
+		stopZero()
+		if err := os.RemoveAll(dir); err != nil {
+			glog.Errorf("While removing ephemeral directory %s: %v", dir, err)
+		}
+	}
+}
+
 func run() {
 	bindall = Alpha.Conf.GetBool("bindall")
+	stop := start()
+	defer stop()
+
+	// setup shutdown os signal handler
+	sdCh := make(chan os.Signal, 3)
+	shutdownCh = make(chan struct{})
+
+	defer func() {
+		signal.Stop(sdCh)
+		close(sdCh)
+	}()
+	// sigint : Ctrl-C, sigterm : kill command.
+	signal.Notify(sdCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		var numShutDownSig int
+		for range sdCh {
+			select {
+			case <-shutdownCh:
+			default:
+				close(shutdownCh)
+			}
+			numShutDownSig++
+			glog.Infoln("Caught Ctrl-C. Terminating now (this may take a few seconds)...")
+			if numShutDownSig == 3 {
+				glog.Infoln("Signaled thrice. Aborting!")
+				os.Exit(1)
+			}
+		}
+	}()
+
+	// sighup : reload the flags which can be changed at runtime from the config file.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	go func() {
+		for range hupCh {
+			if err := reloadConfigFile(); err != nil {
+				glog.Errorf("While reloading config: %v", err)
+			}
+		}
+	}()
+
+	setupServer()
+	glog.Infoln("GRPC and HTTP stopped.")
+}
 
+// start configures Dgraph from Alpha.Conf, opens its stores and starts the Raft node of its group
+// in the background. It returns a function which stops everything it started.
+func start() func() {
 	opts := edgraph.Options{
 		BadgerTables: Alpha.Conf.GetString("badger.tables"),
 		BadgerVlog:   Alpha.Conf.GetString("badger.vlog"),
@@ -433,6 +638,12 @@ func run() {
 		AllottedMemory: Alpha.Conf.GetFloat64("lru_mb"),
 	}
 
+	zeroAddr := Alpha.Conf.GetString("zero")
+	stopEphemeral := func() {}
+	if Alpha.Conf.GetBool("ephemeral") {
+		zeroAddr, stopEphemeral = startEphemeral(&opts)
+	}
+
 	secretFile := Alpha.Conf.GetString("acl_secret_file")
 	if secretFile != "" {
 		if !Alpha.Conf.GetBool("enterprise_features") {
@@ -481,7 +692,7 @@ func run() {
 		NumPendingProposals: Alpha.Conf.GetInt("pending_proposals"),
 		Tracing:             Alpha.Conf.GetFloat64("trace"),
 		MyAddr:              Alpha.Conf.GetString("my"),
-		ZeroAddr:            Alpha.Conf.GetString("zero"),
+		ZeroAddr:            zeroAddr,
 		RaftId:              cast.ToUint64(Alpha.Conf.GetString("idx")),
 		WhiteListedIPRanges: ips,
 		MaxRetries:          Alpha.Conf.GetInt("max_retries"),
@@ -496,6 +707,43 @@ func run() {
 	x.Config.PortOffset = Alpha.Conf.GetInt("port_offset")
 	x.Config.QueryEdgeLimit = cast.ToUint64(Alpha.Conf.GetString("query_edge_limit"))
 	x.Config.NormalizeNodeLimit = cast.ToInt(Alpha.Conf.GetString("normalize_node_limit"))
+	x.Config.QueryDepthLimit = cast.ToUint64(Alpha.Conf.GetString("query_depth_limit"))
+	x.Config.QueryNodeLimit = cast.ToUint64(Alpha.Conf.GetString("query_node_limit"))
+	x.Config.QueryFanoutLimit = cast.ToUint64(Alpha.Conf.GetString("query_fanout_limit"))
+	x.Config.QueryTimeout = Alpha.Conf.GetDuration("query_timeout")
+	x.Config.CustomResolverTimeout = Alpha.Conf.GetDuration("custom_resolver_timeout")
+	x.Config.CustomResolverBatch = Alpha.Conf.GetInt("custom_resolver_batch")
+	x.Config.PersistedQueries = Alpha.Conf.GetInt("persisted_queries")
+	x.Config.QueryCacheSize = Alpha.Conf.GetInt("query_cache_size")
+	x.Config.ResponseSpillSize = Alpha.Conf.GetInt("response_spill_size")
+	x.Config.ResponseSpillDir = Alpha.Conf.GetString("response_spill_dir")
+	x.Config.BinarySummarySize = Alpha.Conf.GetInt("binary_summary_size")
+	x.Config.PasswordHash = Alpha.Conf.GetString("password_hash")
+	x.AssertTruef(x.Config.PasswordHash == "bcrypt" || x.Config.PasswordHash == "argon2id",
+		"Invalid password hash %q: expected bcrypt or argon2id", x.Config.PasswordHash)
+	x.Config.BcryptCost = Alpha.Conf.GetInt("bcrypt_cost")
+	x.AssertTruef(x.Config.BcryptCost >= bcrypt.MinCost && x.Config.BcryptCost <= bcrypt.MaxCost,
+		"Invalid bcrypt cost %d: expected %d to %d", x.Config.BcryptCost, bcrypt.MinCost,
+		bcrypt.MaxCost)
+	x.Config.Argon2Time = cast.ToUint32(Alpha.Conf.GetString("argon2_time"))
+	x.Config.Argon2Memory = cast.ToUint32(Alpha.Conf.GetString("argon2_memory"))
+	x.Config.PasswordMinEntropy = Alpha.Conf.GetFloat64("password_min_entropy")
+	x.Config.PasswordMaxFailures = Alpha.Conf.GetInt("password_max_failures")
+	x.Config.PasswordLockout = Alpha.Conf.GetDuration("password_lockout")
+	x.Config.BlobOffloadSize = Alpha.Conf.GetInt("blob_offload_size")
+	x.AssertTruef(x.Config.BlobOffloadSize >= 0, "Invalid blob_offload_size %d",
+		x.Config.BlobOffloadSize)
+	x.Check(blob.Init(Alpha.Conf.GetString("blob_store")))
+	x.Config.SequenceLease = Alpha.Conf.GetInt("sequence_lease")
+	x.AssertTruef(x.Config.SequenceLease > 0, "Invalid sequence_lease %d",
+		x.Config.SequenceLease)
+	x.Config.IndexBuildRate = cast.ToUint64(Alpha.Conf.GetString("index_build_rate"))
+	x.Check(worker.SetProposalBatching(worker.ProposalBatchOptions{
+		MaxBytes:   Alpha.Conf.GetInt("proposal_batch_bytes"),
+		MaxLatency: Alpha.Conf.GetDuration("proposal_batch_latency"),
+	}))
+	x.Config.CustomResolvers, err = getCustomResolvers(Alpha.Conf.GetString("custom_resolvers"))
+	x.Check(err)
 
 	x.PrintVersion()
 
@@ -504,10 +752,6 @@ func run() {
 	glog.Infof("edgraph.Config: %s", edgraph.Config)
 
 	edgraph.InitServerState()
-	defer func() {
-		edgraph.State.Dispose()
-		glog.Info("Finished disposing server state.")
-	}()
 
 	if Alpha.Conf.GetBool("expose_trace") {
 		// TODO: Remove this once we get rid of event logs.
@@ -524,49 +768,28 @@ func run() {
 	// schema before calling posting.Init().
 	schema.Init(edgraph.State.Pstore)
 	posting.Init(edgraph.State.Pstore)
-	defer posting.Cleanup()
 	worker.Init(edgraph.State.Pstore)
 
-	// setup shutdown os signal handler
-	sdCh := make(chan os.Signal, 3)
-	shutdownCh = make(chan struct{})
-
-	defer func() {
-		signal.Stop(sdCh)
-		close(sdCh)
-	}()
-	// sigint : Ctrl-C, sigterm : kill command.
-	signal.Notify(sdCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-	go func() {
-		var numShutDownSig int
-		for range sdCh {
-			select {
-			case <-shutdownCh:
-			default:
-				close(shutdownCh)
-			}
-			numShutDownSig++
-			glog.Infoln("Caught Ctrl-C. Terminating now (this may take a few seconds)...")
-			if numShutDownSig == 3 {
-				glog.Infoln("Signaled thrice. Aborting!")
-				os.Exit(1)
-			}
-		}
-	}()
-
 	// Setup external communication.
 	aclCloser := y.NewCloser(1)
+	transformCloser := y.NewCloser(1)
 	go func() {
 		worker.StartRaftNodes(edgraph.State.WALstore, bindall)
 		// initialization of the admin account can only be done after raft nodes are running
 		// and health check passes
 		edgraph.ResetAcl()
+		go edgraph.RefreshTransforms(transformCloser)
 		edgraph.RefreshAcls(aclCloser)
 	}()
 
-	setupServer()
-	glog.Infoln("GRPC and HTTP stopped.")
-	aclCloser.SignalAndWait()
-	worker.BlockingStop()
-	glog.Infoln("Server shutdown. Bye!")
+	return func() {
+		aclCloser.SignalAndWait()
+		transformCloser.SignalAndWait()
+		worker.BlockingStop()
+		glog.Infoln("Server shutdown. Bye!")
+		posting.Cleanup()
+		edgraph.State.Dispose()
+		glog.Info("Finished disposing server state.")
+		stopEphemeral()
+	}
 }
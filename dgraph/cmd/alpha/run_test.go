@@ -1593,6 +1593,27 @@ func TestIPStringParsing(t *testing.T) {
 	require.NotEqual(t, addrRange[0].Lower, addrRange[0].Upper)
 }
 
+func TestGetCustomResolvers(t *testing.T) {
+	resolvers, err := getCustomResolvers("")
+	require.NoError(t, err)
+	require.Empty(t, resolvers)
+
+	resolvers, err = getCustomResolvers("tiers=http://localhost:8000/tiers," +
+		"geo=https://example.org/geo?v=2")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"tiers": "http://localhost:8000/tiers",
+		"geo":   "https://example.org/geo?v=2",
+	}, resolvers)
+
+	_, err = getCustomResolvers("tiers")
+	require.Error(t, err)
+	_, err = getCustomResolvers("=http://localhost:8000")
+	require.Error(t, err)
+	_, err = getCustomResolvers("tiers=localhost:8000")
+	require.Error(t, err)
+}
+
 func TestJSONQueryWithVariables(t *testing.T) {
 	schema.ParseBytes([]byte(""), 1)
 	m := `
@@ -157,6 +157,17 @@ func (m *mapper) run(inputFormat chunker.InputFormat) {
 						x.Check(err)
 					}
 				}
+				if facets.HasOps(nq.Facets) {
+					// The edges have no facets yet for the operators to update.
+					fs, err := facets.ApplyOps(nil, nq.Facets)
+					if err != nil {
+						atomic.AddInt64(&m.prog.errCount, 1)
+						if !m.opt.IgnoreErrors {
+							x.Check(err)
+						}
+					}
+					nq.Facets = fs
+				}
 
 				m.processNQuad(gql.NQuad{NQuad: nq})
 				atomic.AddInt64(&m.prog.nquadCount, 1)
@@ -307,6 +318,7 @@ func (m *mapper) addIndexMapEntries(nq gql.NQuad, de *pb.DirectedEdge) {
 		if !ok {
 			log.Fatalf("unknown tokenizer %q", tokerName)
 		}
+		toker = tok.GetCollatedTokenizer(toker, sch.GetCollation())
 
 		// Create storage value.
 		storageVal := types.Val{
@@ -0,0 +1,255 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// predSchema is the part of the schema of a predicate needed to read and compare its values.
+type predSchema struct {
+	Predicate string `json:"predicate"`
+	Type      string `json:"type"`
+	List      bool   `json:"list"`
+	Lang      bool   `json:"lang"`
+}
+
+func (s *predSchema) typeID() (types.TypeID, error) {
+	tid, ok := types.TypeForName(s.Type)
+	if !ok {
+		return tid, errors.Errorf("Unknown type %q of predicate %s", s.Type, s.Predicate)
+	}
+	if tid == types.PasswordID {
+		return tid, errors.Errorf("Can't compare the password predicate %s", s.Predicate)
+	}
+	return tid, nil
+}
+
+// triple is an edge of a predicate, whose value is in the canonical string form of the type of
+// the predicate so that the triples read from a cluster and from an export can be compared.
+type triple struct {
+	subject   string
+	predicate string
+	objectID  string
+	value     string
+	lang      string
+}
+
+// String returns the triple as an N-Quad, which identifies it.
+func (t triple) String() string {
+	if len(t.objectID) > 0 {
+		return fmt.Sprintf("<%s> <%s> <%s> .", t.subject, t.predicate, t.objectID)
+	}
+	if len(t.lang) > 0 {
+		return fmt.Sprintf("<%s> <%s> %s@%s .", t.subject, t.predicate, strconv.Quote(t.value),
+			t.lang)
+	}
+	return fmt.Sprintf("<%s> <%s> %s .", t.subject, t.predicate, strconv.Quote(t.value))
+}
+
+func (t triple) nquad() *api.NQuad {
+	nq := &api.NQuad{Subject: t.subject, Predicate: t.predicate, Lang: t.lang}
+	if len(t.objectID) > 0 {
+		nq.ObjectId = t.objectID
+	} else {
+		// The target converts the value to the type of the predicate.
+		nq.ObjectValue = &api.Value{Val: &api.Value_DefaultVal{DefaultVal: t.value}}
+	}
+	return nq
+}
+
+// tripleSet is the set of triples of a predicate, by their N-Quad.
+type tripleSet map[string]triple
+
+func canonicalUid(uid string) (string, error) {
+	u, err := strconv.ParseUint(uid, 0, 64)
+	if err != nil {
+		return "", errors.Wrapf(err, "while parsing uid %q", uid)
+	}
+	return fmt.Sprintf("%#x", u), nil
+}
+
+// canonicalValue converts the value to the type of the predicate and back to a string, so that
+// e.g. 1.5 and "1.5E+00" are the same float.
+func canonicalValue(v interface{}, tid types.TypeID) (string, error) {
+	var str string
+	switch v := v.(type) {
+	case string:
+		str = v
+	case json.Number:
+		str = v.String()
+	case bool:
+		str = strconv.FormatBool(v)
+	default:
+		// Geo values are objects in the results of queries.
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		str = string(b)
+	}
+	if tid == types.StringID || tid == types.DefaultID {
+		return str, nil
+	}
+	val, err := types.Convert(types.Val{Tid: types.StringID, Value: []byte(str)}, tid)
+	if err != nil {
+		return "", err
+	}
+	out := types.Val{Tid: types.StringID}
+	if err := types.Marshal(val, &out); err != nil {
+		return "", err
+	}
+	return out.Value.(string), nil
+}
+
+// addValue adds the triples of the subject for the value of a key of a JSON node, which is
+// either a value, an object with a uid, or a list of those.
+func (s tripleSet) addValue(subject, key string, v interface{},
+	schema map[string]*predSchema) error {
+
+	pred, lang := key, ""
+	if i := strings.IndexByte(key, '@'); i >= 0 {
+		pred, lang = key[:i], key[i+1:]
+	}
+	ps, ok := schema[pred]
+	if !ok {
+		// Facets and predicates which aren't compared.
+		return nil
+	}
+	tid, err := ps.typeID()
+	if err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case []interface{}:
+		for _, e := range v {
+			if err := s.addValue(subject, key, e, schema); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]interface{}:
+		if uid, ok := v["uid"].(string); ok && tid == types.UidID {
+			objectID, err := canonicalUid(uid)
+			if err != nil {
+				return err
+			}
+			t := triple{subject: subject, predicate: pred, objectID: objectID}
+			s[t.String()] = t
+			return nil
+		}
+	}
+	if tid == types.UidID {
+		return errors.Errorf("Invalid value %v of uid predicate %s", v, pred)
+	}
+	value, err := canonicalValue(v, tid)
+	if err != nil {
+		return errors.Wrapf(err, "while reading value %v of predicate %s", v, pred)
+	}
+	t := triple{subject: subject, predicate: pred, value: value, lang: lang}
+	s[t.String()] = t
+	return nil
+}
+
+// addNode adds the triples of a node of a query result or an export.
+func (s tripleSet) addNode(node map[string]interface{}, schema map[string]*predSchema) error {
+	uid, ok := node["uid"].(string)
+	if !ok {
+		return errors.Errorf("Missing uid in node %v", node)
+	}
+	subject, err := canonicalUid(uid)
+	if err != nil {
+		return err
+	}
+	for key, v := range node {
+		if key == "uid" {
+			continue
+		}
+		if err := s.addValue(subject, key, v, schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readExport reads the triples of the predicates in the schema from a JSON export, which is a
+// list of nodes with one predicate each.
+func readExport(r io.Reader, schema map[string]*predSchema) (map[string]tripleSet, error) {
+	sets := make(map[string]tripleSet)
+	for pred := range schema {
+		sets[pred] = make(tripleSet)
+	}
+
+	dec := json.NewDecoder(bufio.NewReader(r))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil {
+		return nil, errors.Wrapf(err, "while reading export")
+	} else if tok != json.Delim('[') {
+		return nil, errors.Errorf("Invalid JSON export: expected a list of nodes")
+	}
+	for dec.More() {
+		var node map[string]interface{}
+		if err := dec.Decode(&node); err != nil {
+			return nil, errors.Wrapf(err, "while reading export")
+		}
+		for key := range node {
+			pred := strings.SplitN(key, "@", 2)[0]
+			if set, ok := sets[pred]; ok {
+				if err := set.addNode(node, schema); err != nil {
+					return nil, err
+				}
+				break
+			}
+		}
+	}
+	return sets, nil
+}
+
+// diffTriples returns the triples of the source missing from the target, and the triples of
+// the target missing from the source, sorted by N-Quad.
+func diffTriples(source, target tripleSet) (add, del []triple) {
+	return missing(source, target), missing(target, source)
+}
+
+func missing(from, in tripleSet) []triple {
+	var keys []string
+	for key := range from {
+		if _, ok := in[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	ts := make([]triple, 0, len(keys))
+	for _, key := range keys {
+		ts = append(ts, from[key])
+	}
+	return ts
+}
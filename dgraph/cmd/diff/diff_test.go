@@ -0,0 +1,124 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = map[string]*predSchema{
+	"name":   {Predicate: "name", Type: "string", Lang: true},
+	"age":    {Predicate: "age", Type: "int"},
+	"score":  {Predicate: "score", Type: "float"},
+	"alive":  {Predicate: "alive", Type: "bool"},
+	"friend": {Predicate: "friend", Type: "uid", List: true},
+}
+
+func queryTriples(t *testing.T, js string) tripleSet {
+	dec := json.NewDecoder(strings.NewReader(js))
+	dec.UseNumber()
+	var nodes []map[string]interface{}
+	require.NoError(t, dec.Decode(&nodes))
+	set := make(tripleSet)
+	for _, node := range nodes {
+		require.NoError(t, set.addNode(node, testSchema))
+	}
+	return set
+}
+
+func filter(set tripleSet, pred string) tripleSet {
+	out := make(tripleSet)
+	for key, t := range set {
+		if t.predicate == pred {
+			out[key] = t
+		}
+	}
+	return out
+}
+
+func strs(ts []triple) []string {
+	var out []string
+	for _, t := range ts {
+		out = append(out, t.String())
+	}
+	return out
+}
+
+func TestDiffExportAndQuery(t *testing.T) {
+	export := `[
+  {"uid":"0x1","name":"Alice"},
+  {"uid":"0x1","name@fr":"Alice"},
+  {"uid":"0x1","age":30},
+  {"uid":"0x1","score":1.5E+00},
+  {"uid":"0x1","alive":"true"},
+  {"uid":"0x1","friend":[{"uid":"0x2"}]},
+  {"uid":"0x1","friend":[{"uid":"0x3"}]},
+  {"uid":"0x2","name":"Bob"}
+]`
+	sets, err := readExport(bytes.NewBufferString(export), testSchema)
+	require.NoError(t, err)
+
+	// The same values, as returned by queries.
+	target := queryTriples(t, `[
+		{"uid":"0x1","name":"Alice","name@fr":"Alice","age":30,"score":1.5,"alive":true,
+		 "friend":[{"uid":"0x2"},{"uid":"0x4"}]},
+		{"uid":"0x02","name":"Robert"}
+	]`)
+
+	var add, del []string
+	for _, pred := range []string{"age", "alive", "friend", "name", "score"} {
+		a, d := diffTriples(sets[pred], filter(target, pred))
+		add = append(add, strs(a)...)
+		del = append(del, strs(d)...)
+	}
+	require.Equal(t, []string{
+		`<0x1> <friend> <0x3> .`,
+		`<0x2> <name> "Bob" .`,
+	}, add)
+	require.Equal(t, []string{
+		`<0x1> <friend> <0x4> .`,
+		`<0x2> <name> "Robert" .`,
+	}, del)
+}
+
+func TestDiffLangAndNQuads(t *testing.T) {
+	source := queryTriples(t, `[{"uid":"0x1","name@en":"a \"b\""}]`)
+	target := queryTriples(t, `[{"uid":"0x1","name":"a \"b\""}]`)
+	add, del := diffTriples(source, target)
+	require.Equal(t, []string{`<0x1> <name> "a \"b\""@en .`}, strs(add))
+	require.Equal(t, []string{`<0x1> <name> "a \"b\"" .`}, strs(del))
+
+	nq := add[0].nquad()
+	require.Equal(t, "en", nq.Lang)
+	require.Equal(t, `a "b"`, nq.ObjectValue.GetDefaultVal())
+}
+
+func TestDiffInvalidValues(t *testing.T) {
+	set := make(tripleSet)
+	require.Error(t, set.addNode(map[string]interface{}{"uid": "0x1", "age": "ten"}, testSchema))
+	require.Error(t, set.addNode(map[string]interface{}{"uid": "0x1", "friend": "0x2"},
+		testSchema))
+	require.Error(t, set.addNode(map[string]interface{}{"name": "Alice"}, testSchema))
+
+	_, err := readExport(bytes.NewBufferString(`{"uid":"0x1"}`), testSchema)
+	require.Error(t, err)
+}
@@ -0,0 +1,310 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package diff builds a tool that compares the triples of predicates between two clusters, or
+// a JSON export and a cluster, and can apply the difference to the target cluster to bring it
+// in sync with the source.
+package diff
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/dgraph-io/dgo"
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/dgraph-io/dgraph/chunker"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// Diff is the sub-command invoked when calling "dgraph diff".
+var Diff x.SubCommand
+
+func init() {
+	Diff.Cmd = &cobra.Command{
+		Use:   "diff",
+		Short: "Compare and sync the triples of predicates between two Dgraph clusters",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := run(Diff.Conf); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+	Diff.EnvPrefix = "DGRAPH_DIFF"
+
+	flag := Diff.Cmd.Flags()
+	flag.String("source", "", "Address of a Dgraph Alpha of the source cluster.")
+	flag.String("source_export", "",
+		"Path of a JSON export to use as the source instead of a cluster.")
+	flag.String("target", "localhost:9080", "Address of a Dgraph Alpha of the target cluster.")
+	flag.StringP("predicates", "p", "", "Comma separated list of the predicates to compare.")
+	flag.Bool("apply", false,
+		"Apply the difference to the target, so that it has the same triples as the source.")
+	flag.Int("batch", 1000,
+		"Number of nodes read per query, and of N-Quads applied per transaction.")
+	flag.StringP("out", "o", "", "File to write the difference to, instead of stdout.")
+	flag.String("user", "", "Username if login is required.")
+	flag.String("password", "", "Password of the user.")
+	// TLS configuration
+	x.RegisterClientTLSFlags(flag)
+}
+
+// connect returns a client of the cluster of the Alpha, logged in if a user is set.
+func connect(conf *viper.Viper, addr string, tlsCfg *tls.Config) (*dgo.Dgraph, func(), error) {
+	conn, err := x.SetupConnection(addr, tlsCfg, false)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "while connecting to %s", addr)
+	}
+	dg := dgo.NewDgraphClient(api.NewDgraphClient(conn))
+	if user := conf.GetString("user"); len(user) > 0 {
+		err := x.GetPassAndLogin(dg, &x.CredOpt{Conf: conf, UserID: user, PasswordOpt: "password"})
+		if err != nil {
+			conn.Close()
+			return nil, nil, err
+		}
+	}
+	return dg, func() { conn.Close() }, nil
+}
+
+// readSchema returns the schema of the predicates in the cluster.
+func readSchema(ctx context.Context, dg *dgo.Dgraph,
+	preds []string) (map[string]*predSchema, error) {
+
+	q := fmt.Sprintf("schema(pred: [%s]) { type list lang }", strings.Join(preds, ", "))
+	resp, err := dg.NewReadOnlyTxn().Query(ctx, q)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while reading schema")
+	}
+	var res struct {
+		Schema []*predSchema `json:"schema"`
+	}
+	if err := json.Unmarshal(resp.Json, &res); err != nil {
+		return nil, err
+	}
+	schema := make(map[string]*predSchema)
+	for _, s := range res.Schema {
+		if _, err := s.typeID(); err != nil {
+			return nil, err
+		}
+		schema[s.Predicate] = s
+	}
+	for _, pred := range preds {
+		if _, ok := schema[pred]; !ok {
+			return nil, errors.Errorf("Predicate %s isn't in the schema", pred)
+		}
+	}
+	return schema, nil
+}
+
+// readTriples reads the triples of the predicate from the cluster, a page of nodes at a time.
+// All the pages are read at the same timestamp.
+func readTriples(ctx context.Context, txn *dgo.Txn, ps *predSchema,
+	schema map[string]*predSchema, pageSize int) (tripleSet, error) {
+
+	selection := fmt.Sprintf("<%s>", ps.Predicate)
+	switch {
+	case ps.Type == types.UidID.Name():
+		selection += " { uid }"
+	case ps.Lang:
+		selection += "@*"
+	}
+
+	set := make(tripleSet)
+	after := ""
+	for {
+		q := fmt.Sprintf("{ q(func: has(<%s>), first: %d%s) { uid %s } }",
+			ps.Predicate, pageSize, after, selection)
+		resp, err := txn.Query(ctx, q)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while reading predicate %s", ps.Predicate)
+		}
+		dec := json.NewDecoder(bytes.NewReader(resp.Json))
+		dec.UseNumber()
+		var res struct {
+			Q []map[string]interface{} `json:"q"`
+		}
+		if err := dec.Decode(&res); err != nil {
+			return nil, err
+		}
+		for _, node := range res.Q {
+			if err := set.addNode(node, schema); err != nil {
+				return nil, err
+			}
+		}
+		if len(res.Q) < pageSize {
+			return set, nil
+		}
+		after = fmt.Sprintf(", after: %s", res.Q[len(res.Q)-1]["uid"])
+	}
+}
+
+// apply deletes the extra triples from the target and then sets the missing ones, in
+// transactions of at most batch N-Quads.
+func apply(ctx context.Context, dg *dgo.Dgraph, add, del []triple, batch int) error {
+	for _, op := range []struct {
+		triples []triple
+		set     bool
+	}{{del, false}, {add, true}} {
+		for start := 0; start < len(op.triples); start += batch {
+			end := start + batch
+			if end > len(op.triples) {
+				end = len(op.triples)
+			}
+			var nqs []*api.NQuad
+			for _, t := range op.triples[start:end] {
+				nqs = append(nqs, t.nquad())
+			}
+			mu := &api.Mutation{CommitNow: true}
+			if op.set {
+				mu.Set = nqs
+			} else {
+				mu.Del = nqs
+			}
+			if _, err := dg.NewTxn().Mutate(ctx, mu); err != nil {
+				return errors.Wrapf(err, "while applying the difference to the target")
+			}
+		}
+	}
+	return nil
+}
+
+func run(conf *viper.Viper) error {
+	var preds []string
+	for _, pred := range strings.Split(conf.GetString("predicates"), ",") {
+		if pred = strings.TrimSpace(pred); len(pred) > 0 {
+			preds = append(preds, pred)
+		}
+	}
+	if len(preds) == 0 {
+		return errors.Errorf("The --predicates option must be set")
+	}
+	source, export := conf.GetString("source"), conf.GetString("source_export")
+	if (len(source) == 0) == (len(export) == 0) {
+		return errors.Errorf("Exactly one of --source and --source_export must be set")
+	}
+	batch := conf.GetInt("batch")
+	if batch <= 0 {
+		return errors.Errorf("Invalid batch size %d", batch)
+	}
+
+	var out io.Writer = os.Stdout
+	if path := conf.GetString("out"); len(path) > 0 {
+		f, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		out = f
+	}
+	w := bufio.NewWriter(out)
+	defer w.Flush()
+
+	tlsCfg, err := x.LoadClientTLSConfig(conf)
+	if err != nil {
+		return errors.Wrapf(err, "while loading TLS configuration")
+	}
+	ctx := context.Background()
+	targetDg, closeTarget, err := connect(conf, conf.GetString("target"), tlsCfg)
+	if err != nil {
+		return err
+	}
+	defer closeTarget()
+	schema, err := readSchema(ctx, targetDg, preds)
+	if err != nil {
+		return err
+	}
+
+	// The source is either read a predicate at a time from a cluster, or all at once from an
+	// export.
+	var readSource func(ps *predSchema) (tripleSet, error)
+	if len(source) > 0 {
+		sourceDg, closeSource, err := connect(conf, source, tlsCfg)
+		if err != nil {
+			return err
+		}
+		defer closeSource()
+		sourceSchema, err := readSchema(ctx, sourceDg, preds)
+		if err != nil {
+			return err
+		}
+		for _, pred := range preds {
+			if s, t := sourceSchema[pred], schema[pred]; *s != *t {
+				return errors.Errorf("Predicate %s has schema %+v in the source and %+v in the target",
+					pred, *s, *t)
+			}
+		}
+		txn := sourceDg.NewReadOnlyTxn()
+		readSource = func(ps *predSchema) (tripleSet, error) {
+			return readTriples(ctx, txn, ps, schema, batch)
+		}
+	} else {
+		rd, cleanup := chunker.FileReader(export)
+		sets, err := readExport(rd, schema)
+		cleanup()
+		if err != nil {
+			return err
+		}
+		readSource = func(ps *predSchema) (tripleSet, error) {
+			return sets[ps.Predicate], nil
+		}
+	}
+
+	targetTxn := targetDg.NewReadOnlyTxn()
+	var added, deleted int
+	for _, pred := range preds {
+		ps := schema[pred]
+		sourceSet, err := readSource(ps)
+		if err != nil {
+			return err
+		}
+		targetSet, err := readTriples(ctx, targetTxn, ps, schema, batch)
+		if err != nil {
+			return err
+		}
+		add, del := diffTriples(sourceSet, targetSet)
+		for _, t := range del {
+			fmt.Fprintf(w, "- %s\n", t)
+		}
+		for _, t := range add {
+			fmt.Fprintf(w, "+ %s\n", t)
+		}
+		if conf.GetBool("apply") {
+			if err := apply(ctx, targetDg, add, del, batch); err != nil {
+				return err
+			}
+		}
+		added += len(add)
+		deleted += len(del)
+	}
+
+	fmt.Fprintf(os.Stderr, "Found %d triples to add and %d triples to delete.\n", added, deleted)
+	if conf.GetBool("apply") {
+		fmt.Fprintln(os.Stderr, "Applied the difference to the target.")
+	}
+	return nil
+}
@@ -120,6 +120,11 @@ func (s *Server) lease(ctx context.Context, num *pb.Num, txn bool) (*pb.Assigned
 		proposal.MaxTxnTs = maxLease + howMany
 	} else {
 		maxLease = s.maxLeaseId()
+		if maxLease < opts.uidSeed {
+			// The uids of a new cluster start after the seed.
+			maxLease = opts.uidSeed
+			s.nextLeaseId = maxLease + 1
+		}
 		available = maxLease - s.nextLeaseId + 1
 		proposal.MaxLeaseId = maxLease + howMany
 	}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zero
+
+import (
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// freezePredicate freezes the predicate in the mode, on all the Alphas of the cluster once they
+// get the new membership state. The mode 0 unfreezes it.
+func (s *Server) freezePredicate(ctx context.Context, predicate string, mode uint32) error {
+	if !s.Node.AmLeader() {
+		return errors.Errorf("Freezing predicates is only allowed on leader.")
+	}
+	if x.IsReservedPredicate(predicate) {
+		return errors.Errorf("Unable to freeze reserved predicate %s", predicate)
+	}
+	if mode == 0 && s.frozenMode(predicate) == 0 {
+		return errors.Errorf("Predicate %s isn't frozen", predicate)
+	}
+	p := &pb.ZeroProposal{FreezePredicate: predicate, FreezeMode: mode}
+	if err := s.Node.proposeAndWait(ctx, p); err != nil {
+		return err
+	}
+	glog.Infof("Predicate %s is now %s", predicate, x.FreezeModeName(mode))
+	return nil
+}
+
+// frozenMode returns the mode the predicate is frozen in, or 0 if it isn't frozen.
+func (s *Server) frozenMode(predicate string) uint32 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.state.FrozenPredicates[predicate]
+}
@@ -18,6 +18,7 @@ package zero
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
@@ -186,6 +187,13 @@ func (st *state) moveTablet(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if pinned := st.zero.pinnedGroup(tablet); pinned != 0 && pinned != dstGroup {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidRequest,
+			fmt.Sprintf("Tablet: [%s] is pinned to group: [%d]", tablet, pinned))
+		return
+	}
+
 	srcGroup := tab.GroupId
 	if srcGroup == dstGroup {
 		w.WriteHeader(http.StatusInternalServerError)
@@ -208,6 +216,264 @@ func (st *state) moveTablet(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// pinTablet pins a tablet to a group, moving it there if it's served by another group. The
+// balancer doesn't move pinned tablets. It takes in tablet and group as argument.
+func (st *state) pinTablet(w http.ResponseWriter, r *http.Request) {
+	x.AddCorsHeaders(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+		return
+	}
+
+	tablet := r.URL.Query().Get("tablet")
+	if len(tablet) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidRequest, "tablet is a mandatory query parameter")
+		return
+	}
+	groupId, ok := intFromQueryParam(w, r, "group")
+	if !ok {
+		return
+	}
+
+	if err := st.zero.pinTablet(context.Background(), tablet, uint32(groupId)); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	_, err := fmt.Fprintf(w, "Predicate: [%s] pinned to group [%d]", tablet, groupId)
+	if err != nil {
+		glog.Warningf("Error while writing response: %+v", err)
+	}
+}
+
+// unpinTablet lets the balancer move a pinned tablet again. It takes in tablet as argument.
+func (st *state) unpinTablet(w http.ResponseWriter, r *http.Request) {
+	x.AddCorsHeaders(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+		return
+	}
+
+	tablet := r.URL.Query().Get("tablet")
+	if len(tablet) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidRequest, "tablet is a mandatory query parameter")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := st.zero.unpinTablet(ctx, tablet); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	_, err := fmt.Fprintf(w, "Predicate: [%s] unpinned", tablet)
+	if err != nil {
+		glog.Warningf("Error while writing response: %+v", err)
+	}
+}
+
+// excludeGroup returns the handler which excludes a group from the placement of new tablets,
+// or includes it again. It takes in group as argument.
+func (st *state) excludeGroup(exclude bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		x.AddCorsHeaders(w)
+		if r.Method == "OPTIONS" {
+			return
+		}
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusBadRequest)
+			x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+			return
+		}
+
+		groupId, ok := intFromQueryParam(w, r, "group")
+		if !ok {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := st.zero.excludeGroup(ctx, uint32(groupId), exclude); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			x.SetStatus(w, x.Error, err.Error())
+			return
+		}
+		verb := "included in"
+		if exclude {
+			verb = "excluded from"
+		}
+		_, err := fmt.Fprintf(w, "Group: [%d] %s the placement of new tablets", groupId, verb)
+		if err != nil {
+			glog.Warningf("Error while writing response: %+v", err)
+		}
+	}
+}
+
+// freezePredicate rejects the mutations of a predicate cluster-wide, and its queries too if
+// mode is frozen. It takes in predicate and mode (read_only by default) as arguments.
+func (st *state) freezePredicate(w http.ResponseWriter, r *http.Request) {
+	x.AddCorsHeaders(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+		return
+	}
+
+	predicate := r.URL.Query().Get("predicate")
+	if len(predicate) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidRequest, "predicate is a mandatory query parameter")
+		return
+	}
+	name := r.URL.Query().Get("mode")
+	if len(name) == 0 {
+		name = "read_only"
+	}
+	mode, ok := x.FreezeModes[name]
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidRequest,
+			fmt.Sprintf("Invalid mode %q, it must be read_only or frozen", name))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := st.zero.freezePredicate(ctx, predicate, mode); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	_, err := fmt.Fprintf(w, "Predicate: [%s] is now %s", predicate, name)
+	if err != nil {
+		glog.Warningf("Error while writing response: %+v", err)
+	}
+}
+
+// unfreezePredicate accepts the queries and mutations of a frozen predicate again. It takes in
+// predicate as argument.
+func (st *state) unfreezePredicate(w http.ResponseWriter, r *http.Request) {
+	x.AddCorsHeaders(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+		return
+	}
+
+	predicate := r.URL.Query().Get("predicate")
+	if len(predicate) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidRequest, "predicate is a mandatory query parameter")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := st.zero.freezePredicate(ctx, predicate, 0); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	_, err := fmt.Fprintf(w, "Predicate: [%s] unfrozen", predicate)
+	if err != nil {
+		glog.Warningf("Error while writing response: %+v", err)
+	}
+}
+
+// balancerPreview returns the next moves of the balancer, without doing them. It optionally
+// takes in the max number of moves as limit, 10 by default.
+func (st *state) balancerPreview(w http.ResponseWriter, r *http.Request) {
+	x.AddCorsHeaders(w)
+	w.Header().Set("Content-Type", "application/json")
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+		return
+	}
+	limit := uint64(10)
+	if len(r.URL.Query().Get("limit")) > 0 {
+		var ok bool
+		if limit, ok = intFromQueryParam(w, r, "limit"); !ok {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := st.node.WaitLinearizableRead(ctx); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	moves := st.zero.balancerPreview(int(limit))
+	if moves == nil {
+		moves = []tabletMove{}
+	}
+	if err := json.NewEncoder(w).Encode(map[string]interface{}{"moves": moves}); err != nil {
+		glog.Warningf("Error while writing response: %+v", err)
+	}
+}
+
+// createSequence creates a sequence whose values are handed out by next() in mutations. It takes
+// in the name of the sequence and optionally its first value, 1 by default.
+func (st *state) createSequence(w http.ResponseWriter, r *http.Request) {
+	x.AddCorsHeaders(w)
+	if r.Method == "OPTIONS" {
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidMethod, "Invalid method")
+		return
+	}
+
+	name := r.URL.Query().Get("name")
+	if len(name) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.ErrorInvalidRequest, "name is a mandatory query parameter")
+		return
+	}
+	start := uint64(1)
+	if len(r.URL.Query().Get("start")) > 0 {
+		var ok bool
+		if start, ok = intFromQueryParam(w, r, "start"); !ok {
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := st.zero.createSequence(ctx, name, start); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		x.SetStatus(w, x.Error, err.Error())
+		return
+	}
+	_, err := fmt.Fprintf(w, "Sequence: [%s] created, starting at [%d]", name, start)
+	if err != nil {
+		glog.Warningf("Error while writing response: %+v", err)
+	}
+}
+
 func (st *state) getState(w http.ResponseWriter, r *http.Request) {
 	x.AddCorsHeaders(w)
 	w.Header().Set("Content-Type", "application/json")
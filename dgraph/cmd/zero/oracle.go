@@ -17,6 +17,7 @@
 package zero
 
 import (
+	"fmt"
 	"math/rand"
 	"strconv"
 	"strings"
@@ -28,8 +29,12 @@ import (
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
+	ostats "go.opencensus.io/stats"
+	"go.opencensus.io/tag"
 	otrace "go.opencensus.io/trace"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type syncMark struct {
@@ -47,7 +52,7 @@ type Oracle struct {
 
 	// timestamp at the time of start of server or when it became leader. Used to detect conflicts.
 	tmax uint64
-	// All transactions with startTs < startTxnTs return true for hasConflict.
+	// All transactions with startTs < startTxnTs conflict in hasConflict.
 	startTxnTs  uint64
 	subscribers map[int]chan pb.OracleDelta
 	updates     chan *pb.OracleDelta
@@ -72,18 +77,47 @@ func (o *Oracle) updateStartTxnTs(ts uint64) {
 	o.keyCommit = make(map[string]uint64)
 }
 
+// txnConflict describes why a transaction conflicts with the transactions committed after it
+// started.
+type txnConflict struct {
+	startTs uint64
+	// key is the conflict key written by both transactions, which is empty if the transaction
+	// was started before this Zero became the leader.
+	key      string
+	pred     string
+	commitTs uint64
+}
+
+func (c *txnConflict) Error() string {
+	if c.key == "" {
+		return fmt.Sprintf("Transaction started at ts %d, before Zero became the leader", c.startTs)
+	}
+	return fmt.Sprintf("Transaction started at ts %d conflicts on predicate %q (key %s) "+
+		"with the transaction committed at ts %d", c.startTs, c.pred, c.key, c.commitTs)
+}
+
+// conflictPred returns the predicate of a conflict key, which is formatted as <fp>-<predicate>
+// by the alphas.
+func conflictPred(key string) string {
+	if idx := strings.IndexByte(key, '-'); idx >= 0 {
+		return key[idx+1:]
+	}
+	return ""
+}
+
 // TODO: This should be done during proposal application for Txn status.
-func (o *Oracle) hasConflict(src *api.TxnContext) bool {
+func (o *Oracle) hasConflict(src *api.TxnContext) *txnConflict {
 	// This transaction was started before I became leader.
 	if src.StartTs < o.startTxnTs {
-		return true
+		return &txnConflict{startTs: src.StartTs}
 	}
 	for _, k := range src.Keys {
 		if last := o.keyCommit[k]; last > src.StartTs {
-			return true
+			return &txnConflict{startTs: src.StartTs, key: k, pred: conflictPred(k),
+				commitTs: last}
 		}
 	}
-	return false
+	return nil
 }
 
 func (o *Oracle) purgeBelow(minTs uint64) {
@@ -109,12 +143,12 @@ func (o *Oracle) purgeBelow(minTs uint64) {
 		minTs, len(o.commits), len(o.keyCommit))
 }
 
-func (o *Oracle) commit(src *api.TxnContext) error {
+func (o *Oracle) commit(src *api.TxnContext) *txnConflict {
 	o.Lock()
 	defer o.Unlock()
 
-	if o.hasConflict(src) {
-		return errConflict
+	if conflict := o.hasConflict(src); conflict != nil {
+		return conflict
 	}
 	for _, k := range src.Keys {
 		o.keyCommit[k] = src.CommitTs // CommitTs is handed out before calling this func.
@@ -283,8 +317,6 @@ func (o *Oracle) MaxPending() uint64 {
 	return o.maxAssigned
 }
 
-var errConflict = errors.New("Transaction conflict")
-
 // proposeTxn proposes a txn update, and then updates src to reflect the state
 // of the commit after proposal is run.
 func (s *Server) proposeTxn(ctx context.Context, src *api.TxnContext) error {
@@ -329,15 +361,23 @@ func (s *Server) commit(ctx context.Context, src *api.TxnContext) error {
 		return s.proposeTxn(ctx, src)
 	}
 
+	// abortOnConflict aborts the transaction and returns the conflict, so that it can be reported.
+	abortOnConflict := func(conflict *txnConflict) error {
+		src.Aborted = true
+		if err := s.proposeTxn(ctx, src); err != nil {
+			return err
+		}
+		return conflict
+	}
+
 	// Use the start timestamp to check if we have a conflict, before we need to assign a commit ts.
 	s.orc.RLock()
 	conflict := s.orc.hasConflict(src)
 	s.orc.RUnlock()
-	if conflict {
+	if conflict != nil {
 		span.Annotate([]otrace.Attribute{otrace.BoolAttribute("abort", true)},
-			"Oracle found conflict")
-		src.Aborted = true
-		return s.proposeTxn(ctx, src)
+			"Oracle found conflict: "+conflict.Error())
+		return abortOnConflict(conflict)
 	}
 
 	checkPreds := func() error {
@@ -388,9 +428,9 @@ func (s *Server) commit(ctx context.Context, src *api.TxnContext) error {
 	span.Annotatef([]otrace.Attribute{otrace.Int64Attribute("commitTs", int64(src.CommitTs))},
 		"Node Id: %d. Proposing TxnContext: %+v", s.Node.Id, src)
 
-	if err := s.orc.commit(src); err != nil {
-		span.Annotatef(nil, "Found a conflict. Aborting.")
-		src.Aborted = true
+	if conflict := s.orc.commit(src); conflict != nil {
+		span.Annotatef(nil, "Found a conflict. Aborting. %v", conflict)
+		return abortOnConflict(conflict)
 	}
 	if err := ctx.Err(); err != nil {
 		span.Annotatef(nil, "Aborting txn due to context timing out.")
@@ -404,6 +444,8 @@ func (s *Server) commit(ctx context.Context, src *api.TxnContext) error {
 // The abortion can happen under the following conditions
 // 1) the api.TxnContext.Aborted flag is set in the src argument
 // 2) if there's an error (e.g server is not the leader or there's a conflicting transaction)
+// A transaction aborted due to a conflict returns an error with the codes.Aborted status, which
+// describes the conflict.
 func (s *Server) CommitOrAbort(ctx context.Context, src *api.TxnContext) (*api.TxnContext, error) {
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -415,6 +457,11 @@ func (s *Server) CommitOrAbort(ctx context.Context, src *api.TxnContext) (*api.T
 		return nil, errors.Errorf("Only leader can decide to commit or abort")
 	}
 	err := s.commit(ctx, src)
+	if conflict, ok := err.(*txnConflict); ok {
+		cctx, _ := tag.New(ctx, tag.Upsert(x.KeyPredicate, conflict.pred))
+		ostats.Record(cctx, x.TxnConflicts.M(1))
+		return nil, status.Error(codes.Aborted, conflict.Error())
+	}
 	if err != nil {
 		span.Annotate([]otrace.Attribute{otrace.BoolAttribute("error", true)}, err.Error())
 	}
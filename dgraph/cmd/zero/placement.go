@@ -0,0 +1,135 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zero
+
+import (
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+)
+
+func (s *Server) checkGroup(gid uint32) error {
+	s.RLock()
+	defer s.RUnlock()
+	if _, ok := s.state.Groups[gid]; !ok {
+		return errors.Errorf("Group: [%d] is not a known group.", gid)
+	}
+	return nil
+}
+
+// pinTablet pins the predicate to the group: it's placed in the group if it isn't served yet,
+// moved to the group otherwise, and the balancer doesn't move it out of the group.
+func (s *Server) pinTablet(ctx context.Context, predicate string, gid uint32) error {
+	if !s.Node.AmLeader() {
+		return errors.Errorf("Pinning tablets is only allowed on leader.")
+	}
+	if x.IsReservedPredicate(predicate) {
+		return errors.Errorf("Unable to pin reserved predicate %s", predicate)
+	}
+	if err := s.checkGroup(gid); err != nil {
+		return err
+	}
+	p := &pb.ZeroProposal{PinPredicate: predicate, PinGroupId: gid}
+	if err := s.Node.proposeAndWait(ctx, p); err != nil {
+		return err
+	}
+
+	tab := s.ServingTablet(predicate)
+	if tab == nil || tab.GroupId == gid {
+		return nil
+	}
+	// If the move fails, the balancer moves the tablet to the group later on.
+	return errors.Wrapf(s.movePredicate(predicate, tab.GroupId, gid),
+		"while moving pinned predicate %s to group %d", predicate, gid)
+}
+
+// unpinTablet lets the balancer move the predicate again.
+func (s *Server) unpinTablet(ctx context.Context, predicate string) error {
+	if !s.Node.AmLeader() {
+		return errors.Errorf("Unpinning tablets is only allowed on leader.")
+	}
+	if s.pinnedGroup(predicate) == 0 {
+		return errors.Errorf("Predicate %s isn't pinned", predicate)
+	}
+	return s.Node.proposeAndWait(ctx, &pb.ZeroProposal{PinPredicate: predicate})
+}
+
+// pinnedGroup returns the group the predicate is pinned to, or 0 if it isn't pinned.
+func (s *Server) pinnedGroup(predicate string) uint32 {
+	s.RLock()
+	defer s.RUnlock()
+	return s.state.PinnedTablets[predicate]
+}
+
+// excludeGroup sets whether the group is excluded from the new tablets, which are then placed
+// in the smallest group which isn't excluded. The balancer doesn't move tablets to an excluded
+// group either.
+func (s *Server) excludeGroup(ctx context.Context, gid uint32, exclude bool) error {
+	if !s.Node.AmLeader() {
+		return errors.Errorf("Excluding groups is only allowed on leader.")
+	}
+	if err := s.checkGroup(gid); err != nil {
+		return err
+	}
+	p := &pb.ZeroProposal{IncludeGroupId: gid}
+	if exclude {
+		p = &pb.ZeroProposal{ExcludeGroupId: gid}
+	}
+	return s.Node.proposeAndWait(ctx, p)
+}
+
+// placeTablet returns the group which should serve the new tablet asked for by the group gid:
+// the group it's pinned to, or if gid is excluded, the smallest group which isn't.
+func (s *Server) placeTablet(predicate string, gid uint32) uint32 {
+	s.RLock()
+	defer s.RUnlock()
+	if pinned, ok := s.state.PinnedTablets[predicate]; ok {
+		return pinned
+	}
+	if group, ok := s.state.Groups[gid]; !ok || !group.Excluded {
+		return gid
+	}
+
+	var smallest uint32
+	var smallestSize int64
+	for id, group := range s.state.Groups {
+		if group.Excluded {
+			continue
+		}
+		size := int64(0)
+		for _, tab := range group.Tablets {
+			size += tab.Space
+		}
+		if smallest == 0 || size < smallestSize || (size == smallestSize && id < smallest) {
+			smallest, smallestSize = id, size
+		}
+	}
+	if smallest == 0 {
+		// All the groups are excluded.
+		return gid
+	}
+	return smallest
+}
+
+// balancerPreview returns the next moves the balancer would make, at most limit.
+func (s *Server) balancerPreview(limit int) []tabletMove {
+	s.RLock()
+	defer s.RUnlock()
+	return planMoves(s.state, limit)
+}
@@ -367,6 +367,50 @@ func (n *node) applyProposal(e raftpb.Entry) (string, error) {
 		glog.Infof("Could not apply proposal, ignoring: p.MaxLeaseId=%v, p.MaxTxnTs=%v maxLeaseId=%d"+
 			" maxTxnTs=%d\n", p.MaxLeaseId, p.MaxTxnTs, state.MaxLeaseId, state.MaxTxnTs)
 	}
+	if len(p.Sequence) > 0 {
+		// The max leased value of a sequence only goes up once it's created.
+		if max, ok := state.Sequences[p.Sequence]; ok && p.MaxSequenceId <= max {
+			return p.Key, errInvalidProposal
+		}
+		if state.Sequences == nil {
+			state.Sequences = make(map[string]uint64)
+		}
+		state.Sequences[p.Sequence] = p.MaxSequenceId
+	}
+	if len(p.PinPredicate) > 0 {
+		if p.PinGroupId == 0 {
+			delete(state.PinnedTablets, p.PinPredicate)
+		} else {
+			if state.PinnedTablets == nil {
+				state.PinnedTablets = make(map[string]uint32)
+			}
+			state.PinnedTablets[p.PinPredicate] = p.PinGroupId
+		}
+	}
+	if len(p.FreezePredicate) > 0 {
+		if p.FreezeMode == 0 {
+			delete(state.FrozenPredicates, p.FreezePredicate)
+		} else {
+			if state.FrozenPredicates == nil {
+				state.FrozenPredicates = make(map[string]uint32)
+			}
+			state.FrozenPredicates[p.FreezePredicate] = p.FreezeMode
+		}
+	}
+	if p.ExcludeGroupId > 0 || p.IncludeGroupId > 0 {
+		gid := p.ExcludeGroupId
+		if gid == 0 {
+			gid = p.IncludeGroupId
+		}
+		group, ok := state.Groups[gid]
+		if !ok {
+			return p.Key, errors.Errorf("Unknown group %d", gid)
+		}
+		group.Excluded = p.ExcludeGroupId > 0
+	}
+	if p.FeatureVersion > state.FeatureVersion {
+		state.FeatureVersion = p.FeatureVersion
+	}
 	if p.Txn != nil {
 		n.server.orc.updateCommitStatus(e.Index, p.Txn)
 	}
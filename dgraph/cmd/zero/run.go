@@ -40,6 +40,7 @@ import (
 	"github.com/dgraph-io/dgraph/raftwal"
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/golang/glog"
+	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
 )
 
@@ -52,6 +53,7 @@ type options struct {
 	peer              string
 	w                 string
 	rebalanceInterval time.Duration
+	uidSeed           uint64
 }
 
 var opts options
@@ -86,6 +88,8 @@ instances to achieve high-availability.
 	flag.String("peer", "", "Address of another dgraphzero server.")
 	flag.StringP("wal", "w", "zw", "Directory storing WAL.")
 	flag.Duration("rebalance_interval", 8*time.Minute, "Interval for trying a predicate move.")
+	flag.Uint64("uid_seed", 0, "The uids of a new cluster start after this value, so that the"+
+		" same mutations get the same uids in every run. Useful for golden-file tests.")
 	flag.Bool("telemetry", true, "Send anonymous telemetry data to Dgraph devs.")
 
 	// OpenCensus flags.
@@ -168,6 +172,7 @@ func run() {
 		peer:              Zero.Conf.GetString("peer"),
 		w:                 Zero.Conf.GetString("wal"),
 		rebalanceInterval: Zero.Conf.GetDuration("rebalance_interval"),
+		uidSeed:           cast.ToUint64(Zero.Conf.GetString("uid_seed")),
 	}
 
 	if opts.numReplicas < 0 || opts.numReplicas%2 == 0 {
@@ -185,6 +190,35 @@ func run() {
 	otrace.ApplyConfig(otrace.Config{
 		DefaultSampler: otrace.ProbabilitySampler(Zero.Conf.GetFloat64("trace"))})
 
+	st, kv := serve(listen())
+	defer kv.Close()
+	zpages.Handle(http.DefaultServeMux, "/z")
+
+	if Zero.Conf.GetBool("telemetry") {
+		go st.zero.periodicallyPostTelemetry()
+	}
+
+	sdCh := make(chan os.Signal, 1)
+	signal.Notify(sdCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+
+	// handle signals
+	go func() {
+		for sig := range sdCh {
+			glog.Infof("--- Received %s signal", sig)
+			signal.Stop(sdCh)
+			st.zero.closer.Signal()
+		}
+	}()
+
+	glog.Infoln("Running Dgraph Zero...")
+	st.zero.closer.Wait()
+	signal.Stop(sdCh)
+	close(sdCh)
+	glog.Infoln("All done.")
+}
+
+// listen sets up the gRPC and HTTP listeners of Zero, as configured by opts.
+func listen() (net.Listener, net.Listener) {
 	addr := "localhost"
 	if opts.bindall {
 		addr = "0.0.0.0"
@@ -200,65 +234,107 @@ func run() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	return grpcListener, httpListener
+}
 
+// serve opens the WAL and starts the servers and the Raft node of Zero, as configured by opts.
+// Without httpListener, Zero has no HTTP endpoints. Zero shuts down when st.zero.closer is
+// signalled. The WAL must be closed once it's done.
+func serve(grpcListener, httpListener net.Listener) (*state, *badger.DB) {
 	// Open raft write-ahead log and initialize raft node.
 	x.Checkf(os.MkdirAll(opts.w, 0700), "Error while creating WAL dir.")
 	kvOpt := badger.LSMOnlyOptions(opts.w).WithSyncWrites(false).WithTruncate(true).
 		WithValueLogFileSize(64 << 20)
 	kv, err := badger.Open(kvOpt)
 	x.Checkf(err, "Error while opening WAL store")
-	defer kv.Close()
 	store := raftwal.Init(kv, opts.nodeId, 0)
 
 	// Initialize the servers.
-	var st state
+	st := &state{}
 	st.serveGRPC(grpcListener, store)
-	st.serveHTTP(httpListener)
-
-	http.HandleFunc("/state", st.getState)
-	http.HandleFunc("/removeNode", st.removeNode)
-	http.HandleFunc("/moveTablet", st.moveTablet)
-	http.HandleFunc("/assign", st.assign)
-	zpages.Handle(http.DefaultServeMux, "/z")
+	if httpListener != nil {
+		st.serveHTTP(httpListener)
+
+		http.HandleFunc("/state", st.getState)
+		http.HandleFunc("/removeNode", st.removeNode)
+		http.HandleFunc("/moveTablet", st.moveTablet)
+		http.HandleFunc("/pinTablet", st.pinTablet)
+		http.HandleFunc("/unpinTablet", st.unpinTablet)
+		http.HandleFunc("/excludeGroup", st.excludeGroup(true))
+		http.HandleFunc("/includeGroup", st.excludeGroup(false))
+		http.HandleFunc("/balancerPreview", st.balancerPreview)
+		http.HandleFunc("/freezePredicate", st.freezePredicate)
+		http.HandleFunc("/unfreezePredicate", st.unfreezePredicate)
+		http.HandleFunc("/assign", st.assign)
+		http.HandleFunc("/createSequence", st.createSequence)
+	} else {
+		// Match the Done of the HTTP server.
+		st.zero.closer.Done()
+	}
 
 	// This must be here. It does not work if placed before Grpc init.
 	x.Check(st.node.initAndStartNode())
 
-	if Zero.Conf.GetBool("telemetry") {
-		go st.zero.periodicallyPostTelemetry()
-	}
-
-	sdCh := make(chan os.Signal, 1)
-	signal.Notify(sdCh, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
-
-	// handle signals
-	go func() {
-		for sig := range sdCh {
-			glog.Infof("--- Received %s signal", sig)
-			signal.Stop(sdCh)
-			st.zero.closer.Signal()
-		}
-	}()
-
 	st.zero.closer.AddRunning(1)
 
 	go func() {
 		defer st.zero.closer.Done()
 		<-st.zero.closer.HasBeenClosed()
 		glog.Infoln("Shutting down...")
-		close(sdCh)
 		// Close doesn't close already opened connections.
 
 		// Stop all HTTP requests.
-		httpListener.Close()
+		if httpListener != nil {
+			httpListener.Close()
+		}
 		// Stop Raft.
 		st.node.closer.SignalAndWait()
 		// Stop all internal requests.
 		grpcListener.Close()
 		st.node.trySnapshot(0)
 	}()
+	return st, kv
+}
 
-	glog.Infoln("Running Dgraph Zero...")
-	st.zero.closer.Wait()
-	glog.Infoln("All done.")
+// RunEphemeral runs a single Zero in this process, keeping its WAL in dir. It's used by Alpha
+// to run a whole cluster in one process. It returns the gRPC address of Zero, and a function
+// which shuts it down.
+func RunEphemeral(dir string, portOffset int) (string, func()) {
+	opts = inProcessOptions(dir)
+	opts.portOffset = portOffset
+	return runInProcess(listen())
+}
+
+// RunLocal runs a single Zero in this process, keeping its WAL in dir, without listening on the
+// network: it's reached at the returned address through the local connections of package conn.
+// It has no HTTP endpoints. It also returns a function which shuts it down.
+func RunLocal(dir string) (string, func(), error) {
+	opts = inProcessOptions(dir)
+	opts.myAddr = fmt.Sprintf("zero.local:%d", x.PortZeroGrpc)
+	l, err := conn.ListenLocal(opts.myAddr)
+	if err != nil {
+		return "", nil, err
+	}
+	addr, stop := runInProcess(l, nil)
+	return addr, stop, nil
+}
+
+func inProcessOptions(dir string) options {
+	return options{
+		nodeId:            1,
+		numReplicas:       1,
+		w:                 dir,
+		rebalanceInterval: 8 * time.Minute,
+	}
+}
+
+func runInProcess(grpcListener, httpListener net.Listener) (string, func()) {
+	st, kv := serve(grpcListener, httpListener)
+	glog.Infof("Running Dgraph Zero in this process at %s", opts.myAddr)
+	return opts.myAddr, func() {
+		st.zero.closer.SignalAndWait()
+		if err := kv.Close(); err != nil {
+			glog.Errorf("While closing the WAL of Zero: %v", err)
+		}
+	}
 }
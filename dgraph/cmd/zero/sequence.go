@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zero
+
+import (
+	"math"
+
+	otrace "go.opencensus.io/trace"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/pkg/errors"
+)
+
+// createSequence creates the sequence with the given name, whose first value is start.
+func (s *Server) createSequence(ctx context.Context, name string, start uint64) error {
+	if !s.Node.AmLeader() {
+		return errors.Errorf("Creating sequences is only allowed on leader.")
+	}
+	if len(name) == 0 {
+		return errors.Errorf("Sequence name must not be empty")
+	}
+	if start == 0 {
+		return errors.Errorf("Start of sequence %q must be greater than zero", name)
+	}
+
+	s.seqLock.Lock()
+	defer s.seqLock.Unlock()
+
+	s.RLock()
+	_, ok := s.state.Sequences[name]
+	s.RUnlock()
+	if ok {
+		return errors.Errorf("Sequence %q already exists", name)
+	}
+	return s.Node.proposeAndWait(ctx, &pb.ZeroProposal{Sequence: name, MaxSequenceId: start - 1})
+}
+
+// leaseSequence leases the next num.Val values of the sequence num.Sequence.
+func (s *Server) leaseSequence(ctx context.Context, num *pb.Num) (*pb.AssignedIds, error) {
+	if num.Val == 0 {
+		return &emptyAssignedIds, errors.Errorf("Nothing to be leased")
+	}
+
+	s.seqLock.Lock()
+	defer s.seqLock.Unlock()
+
+	s.RLock()
+	max, ok := s.state.Sequences[num.Sequence]
+	s.RUnlock()
+	if !ok {
+		return &emptyAssignedIds, errors.Errorf("Sequence %q doesn't exist", num.Sequence)
+	}
+	if max > math.MaxUint64-num.Val {
+		return &emptyAssignedIds, errors.Errorf("Sequence %q is exhausted", num.Sequence)
+	}
+	proposal := &pb.ZeroProposal{Sequence: num.Sequence, MaxSequenceId: max + num.Val}
+	if err := s.Node.proposeAndWait(ctx, proposal); err != nil {
+		return &emptyAssignedIds, err
+	}
+	return &pb.AssignedIds{StartId: max + 1, EndId: max + num.Val}, nil
+}
+
+// LeaseSequence leases the next values of a sequence on the leader. The Alphas hand out the
+// values they leased one by one, so the values of a sequence are unique, but the order in which
+// they're handed out only holds for each Alpha.
+func (s *Server) LeaseSequence(ctx context.Context, num *pb.Num) (*pb.AssignedIds, error) {
+	if ctx.Err() != nil {
+		return &emptyAssignedIds, ctx.Err()
+	}
+	ctx, span := otrace.StartSpan(ctx, "Zero.LeaseSequence")
+	defer span.End()
+
+	if s.Node.AmLeader() {
+		span.Annotatef(nil, "Zero leader leasing %d values of %s", num.Val, num.Sequence)
+		return s.leaseSequence(ctx, num)
+	}
+	if num.Forwarded {
+		return &emptyAssignedIds, errors.Errorf(
+			"Invalid Zero received LeaseSequence request forward. Please retry")
+	}
+	pl := s.Leader(0)
+	if pl == nil {
+		return &emptyAssignedIds, errors.Errorf("No healthy connection found to Leader of group zero")
+	}
+	span.Annotatef(nil, "Sending request to %v", pl.Addr)
+	num.Forwarded = true
+	return pb.NewZeroClient(pl.Get()).LeaseSequence(ctx, num)
+}
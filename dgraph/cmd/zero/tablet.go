@@ -173,67 +173,134 @@ func (s *Server) movePredicate(predicate string, srcGroup, dstGroup uint32) erro
 func (s *Server) chooseTablet() (predicate string, srcGroup uint32, dstGroup uint32) {
 	s.RLock()
 	defer s.RUnlock()
-	if s.state == nil {
+	if s.state == nil || !s.Node.AmLeader() {
 		return
 	}
-	numGroups := len(s.state.Groups)
-	if !s.Node.AmLeader() || numGroups <= 1 {
+	moves := planMoves(s.state, 1)
+	if len(moves) == 0 {
 		return
 	}
+	move := moves[0]
+	// Don't move a node unless you receive atleast one update regarding tablet size.
+	// Tablet size would have come up with leader update.
+	if !s.hasLeader(move.DstGroup) {
+		return
+	}
+	glog.Infof("Balancer chose move: %+v", move)
+	return move.Predicate, move.SrcGroup, move.DstGroup
+}
+
+// tabletMove is a move of a tablet decided by the balancer.
+type tabletMove struct {
+	Predicate string `json:"predicate"`
+	SrcGroup  uint32 `json:"src_group"`
+	DstGroup  uint32 `json:"dst_group"`
+	Space     int64  `json:"space"`
+	// Reason is "pinned" for a tablet moved to the group it's pinned to, or "balance".
+	Reason string `json:"reason"`
+}
+
+// planMoves returns the next moves of the balancer for the state, at most limit. The tablets
+// served outside of the group they're pinned to are moved first, then the tablets which bring
+// the sizes of the groups closer. Each move is planned as if the previous ones were done.
+func planMoves(state *pb.MembershipState, limit int) []tabletMove {
+	groups := make(map[uint32]map[string]int64)
+	excluded := make(map[uint32]bool)
+	serving := make(map[string]uint32)
+	for gid, group := range state.Groups {
+		groups[gid] = make(map[string]int64)
+		for pred, tab := range group.Tablets {
+			groups[gid][pred] = tab.Space
+			serving[pred] = gid
+		}
+		excluded[gid] = group.Excluded
+	}
+	var moves []tabletMove
+	move := func(pred string, src, dst uint32, reason string) {
+		space := groups[src][pred]
+		delete(groups[src], pred)
+		groups[dst][pred] = space
+		moves = append(moves, tabletMove{Predicate: pred, SrcGroup: src, DstGroup: dst,
+			Space: space, Reason: reason})
+	}
 
-	// Sort all groups by their sizes.
-	type kv struct {
-		gid  uint32
-		size int64 // in bytes
+	var pinned []string
+	for pred := range state.PinnedTablets {
+		pinned = append(pinned, pred)
 	}
-	var groups []kv
-	for k, v := range s.state.Groups {
-		space := int64(0)
-		for _, tab := range v.Tablets {
-			space += tab.Space
+	sort.Strings(pinned)
+	for _, pred := range pinned {
+		if len(moves) >= limit {
+			return moves
 		}
-		groups = append(groups, kv{k, space})
-	}
-	sort.Slice(groups, func(i, j int) bool {
-		return groups[i].size < groups[j].size
-	})
-
-	glog.Infof("\n\nGroups sorted by size: %+v\n\n", groups)
-	for lastGroup := numGroups - 1; lastGroup > 0; lastGroup-- {
-		srcGroup = groups[lastGroup].gid
-		dstGroup = groups[0].gid
-		sizeDiff := groups[lastGroup].size - groups[0].size
-		glog.Infof("size_diff %v\n", sizeDiff)
-		// Don't move a node unless you receive atleast one update regarding tablet size.
-		// Tablet size would have come up with leader update.
-		if !s.hasLeader(dstGroup) {
-			return
+		src, dst := serving[pred], state.PinnedTablets[pred]
+		if _, ok := groups[dst]; ok && src != 0 && src != dst {
+			move(pred, src, dst, "pinned")
 		}
-		// We move the predicate only if the difference between size of both machines is
-		// atleast 10% of src group.
-		if float64(sizeDiff) < 0.1*float64(groups[0].size) {
-			continue
+	}
+
+	for len(moves) < limit {
+		// Sort all groups by their sizes.
+		type kv struct {
+			gid  uint32
+			size int64 // in bytes
+		}
+		var sizes []kv
+		for gid, tablets := range groups {
+			space := int64(0)
+			for _, s := range tablets {
+				space += s
+			}
+			sizes = append(sizes, kv{gid, space})
 		}
+		sort.Slice(sizes, func(i, j int) bool {
+			if sizes[i].size != sizes[j].size {
+				return sizes[i].size < sizes[j].size
+			}
+			return sizes[i].gid < sizes[j].gid
+		})
 
-		// Try to find a predicate which we can move.
-		size := int64(0)
-		group := s.state.Groups[srcGroup]
-		for _, tab := range group.Tablets {
-			// Reserved predicates should always be in group 1 so do not re-balance them.
-			if x.IsReservedPredicate(tab.Predicate) {
+		// Tablets are moved to the smallest group which isn't excluded.
+		first := 0
+		for first < len(sizes) && excluded[sizes[first].gid] {
+			first++
+		}
+		var predicate string
+		var srcGroup uint32
+		for last := len(sizes) - 1; last > first && len(predicate) == 0; last-- {
+			srcGroup = sizes[last].gid
+			sizeDiff := sizes[last].size - sizes[first].size
+			// We move the predicate only if the difference between size of both machines is
+			// atleast 10% of src group.
+			if float64(sizeDiff) < 0.1*float64(sizes[first].size) {
 				continue
 			}
 
-			// Finds a tablet as big a possible such that on moving it dstGroup's size is
-			// less than or equal to srcGroup.
-			if tab.Space <= sizeDiff/2 && tab.Space > size {
-				predicate = tab.Predicate
-				size = tab.Space
+			// Try to find a predicate which we can move.
+			size := int64(0)
+			for pred, space := range groups[srcGroup] {
+				// Reserved predicates should always be in group 1 so do not re-balance them.
+				// Pinned predicates stay in their group.
+				if x.IsReservedPredicate(pred) {
+					continue
+				}
+				if _, ok := state.PinnedTablets[pred]; ok {
+					continue
+				}
+
+				// Finds a tablet as big a possible such that on moving it dstGroup's size is
+				// less than or equal to srcGroup.
+				if space <= sizeDiff/2 &&
+					(space > size || (space == size && len(predicate) > 0 && pred < predicate)) {
+					predicate = pred
+					size = space
+				}
 			}
 		}
-		if len(predicate) > 0 {
-			return
+		if len(predicate) == 0 {
+			break
 		}
+		move(predicate, srcGroup, sizes[first].gid, "balance")
 	}
-	return
+	return moves
 }
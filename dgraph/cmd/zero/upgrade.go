@@ -0,0 +1,137 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package zero
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// supportedFeatureVersion returns the highest feature version supported by all the Alphas of
+// the state and this Zero. The Alphas which are down still count, until they're removed.
+func supportedFeatureVersion(state *pb.MembershipState) uint32 {
+	version := x.FeatureVersion
+	for _, group := range state.Groups {
+		for _, m := range group.Members {
+			if m.FeatureVersion < version {
+				version = m.FeatureVersion
+			}
+		}
+	}
+	return version
+}
+
+// checkFeatureVersion returns an error if the member doesn't support the features enabled in
+// the cluster, e.g. because it's been downgraded.
+func checkFeatureVersion(state *pb.MembershipState, m *pb.Member) error {
+	if m.FeatureVersion < state.FeatureVersion {
+		return errors.Errorf("VERSION_SKEW: Member %#x supports feature version %d, but the"+
+			" cluster has enabled version %d. Upgrade it to a newer build.",
+			m.Id, m.FeatureVersion, state.FeatureVersion)
+	}
+	return nil
+}
+
+// updateFeatureVersion enables the features of the version supported by all the members of
+// the cluster. The enabled version only goes up.
+func (s *Server) updateFeatureVersion() {
+	ticker := time.NewTicker(10 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !s.Node.AmLeader() {
+			continue
+		}
+		s.RLock()
+		enabled, supported := s.state.FeatureVersion, supportedFeatureVersion(s.state)
+		s.RUnlock()
+		if supported <= enabled {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		err := s.Node.proposeAndWait(ctx, &pb.ZeroProposal{FeatureVersion: supported})
+		cancel()
+		if err != nil {
+			glog.Errorf("While enabling feature version %d: %v", supported, err)
+			continue
+		}
+		glog.Infof("Enabled feature version %d, supported by all the members", supported)
+	}
+}
+
+// DrainNode prepares the member to be shut down. If it's the only member of its group, the
+// group is excluded from new tablets and its tablets are moved to the other groups. The Alpha
+// moves the leadership of its group to another member itself.
+func (s *Server) DrainNode(ctx context.Context, m *pb.Member) (*api.Payload, error) {
+	if !s.Node.AmLeader() {
+		return nil, errors.Errorf("Draining nodes is only allowed on leader.")
+	}
+
+	s.RLock()
+	group, ok := s.state.Groups[m.GroupId]
+	var members int
+	var preds []string
+	if ok {
+		if _, ok = group.Members[m.Id]; ok {
+			members = len(group.Members)
+			for pred := range group.Tablets {
+				preds = append(preds, pred)
+			}
+		}
+	}
+	s.RUnlock()
+	if !ok {
+		return nil, errors.Errorf("No node with id %#x found in group %d", m.Id, m.GroupId)
+	}
+	if members > 1 {
+		// The other members keep serving the group.
+		return &api.Payload{Data: []byte("OK")}, nil
+	}
+
+	sort.Strings(preds)
+	for _, pred := range preds {
+		if x.IsReservedPredicate(pred) {
+			return nil, errors.Errorf("Group %d serves the reserved predicate %s, add another"+
+				" member to the group before draining node %#x", m.GroupId, pred, m.Id)
+		}
+		if s.pinnedGroup(pred) == m.GroupId {
+			return nil, errors.Errorf("Predicate %s is pinned to group %d, unpin it before"+
+				" draining node %#x", pred, m.GroupId, m.Id)
+		}
+	}
+	if err := s.excludeGroup(ctx, m.GroupId, true); err != nil {
+		return nil, err
+	}
+	for _, pred := range preds {
+		dst := s.placeTablet(pred, m.GroupId)
+		if dst == m.GroupId {
+			return nil, errors.Errorf("No other group to move predicate %s to", pred)
+		}
+		if err := s.movePredicate(pred, m.GroupId, dst); err != nil {
+			return nil, errors.Wrapf(err, "while draining node %#x", m.Id)
+		}
+	}
+	return &api.Payload{Data: []byte("OK")}, nil
+}
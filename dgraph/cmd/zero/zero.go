@@ -52,6 +52,7 @@ type Server struct {
 	nextTxnTs   uint64
 	readOnlyTs  uint64
 	leaseLock   sync.Mutex // protects nextLeaseId, nextTxnTs and corresponding proposals.
+	seqLock     sync.Mutex // serializes the proposals creating and leasing sequences.
 
 	// groupMap    map[uint32]*Group
 	nextGroup      uint32
@@ -82,6 +83,7 @@ func (s *Server) Init() {
 	s.blockCommitsOn = new(sync.Map)
 	s.moveOngoing = make(chan struct{}, 1)
 	go s.rebalanceTablets()
+	go s.updateFeatureVersion()
 }
 
 func (s *Server) periodicallyPostTelemetry() {
@@ -333,8 +335,12 @@ func (s *Server) createProposals(dst *pb.Group) ([]*pb.ZeroProposal, error) {
 		if !has {
 			return res, errors.Errorf("Unknown member: %+v", dstMember)
 		}
+		if err := checkFeatureVersion(s.state, dstMember); err != nil {
+			return res, err
+		}
 		if srcMember.Addr != dstMember.Addr ||
-			srcMember.Leader != dstMember.Leader {
+			srcMember.Leader != dstMember.Leader ||
+			srcMember.FeatureVersion != dstMember.FeatureVersion {
 
 			proposal := &pb.ZeroProposal{
 				Member: dstMember,
@@ -426,6 +432,9 @@ func (s *Server) Connect(ctx context.Context,
 	if len(m.Addr) == 0 {
 		return &emptyConnectionState, errors.Errorf("NO_ADDR: No address provided: %+v", m)
 	}
+	if err := checkFeatureVersion(ms, m); err != nil {
+		return &emptyConnectionState, err
+	}
 
 	for _, member := range ms.Removed {
 		// It is not recommended to reuse RAFT ids.
@@ -566,6 +575,8 @@ func (s *Server) ShouldServe(
 	var proposal pb.ZeroProposal
 	// Multiple Groups might be assigned to same tablet, so during proposal we will check again.
 	tablet.Force = false
+	// The tablet goes to the group it's pinned to, and not to an excluded group.
+	tablet.GroupId = s.placeTablet(tablet.Predicate, tablet.GroupId)
 	if x.IsReservedPredicate(tablet.Predicate) {
 		// Force all the reserved predicates to be allocated to group 1.
 		// This is to make it easier to stream ACL updates to all alpha servers
@@ -20,7 +20,9 @@ import (
 	"context"
 	"testing"
 
+	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
 	"github.com/stretchr/testify/require"
 )
 
@@ -35,3 +37,96 @@ func TestRemoveNode(t *testing.T) {
 	err = server.removeNode(context.TODO(), 1, 2)
 	require.Error(t, err)
 }
+
+func TestOracleConflict(t *testing.T) {
+	var o Oracle
+	o.keyCommit = map[string]uint64{"3k7-friend": 20}
+	o.startTxnTs = 5
+
+	conflict := o.commit(&api.TxnContext{StartTs: 10, CommitTs: 25,
+		Keys: []string{"1a-name", "3k7-friend"}})
+	require.NotNil(t, conflict)
+	require.Equal(t, "friend", conflict.pred)
+	require.Equal(t, uint64(20), conflict.commitTs)
+	require.Contains(t, conflict.Error(), `predicate "friend" (key 3k7-friend)`)
+
+	require.NotNil(t, o.commit(&api.TxnContext{StartTs: 4, CommitTs: 25}))
+	require.Nil(t, o.commit(&api.TxnContext{StartTs: 21, CommitTs: 25,
+		Keys: []string{"1a-name", "3k7-friend"}}))
+	require.Equal(t, uint64(25), o.keyCommit["1a-name"])
+}
+
+func testPlacementState() *pb.MembershipState {
+	tablets := func(spaces map[string]int64) map[string]*pb.Tablet {
+		tabs := make(map[string]*pb.Tablet)
+		for pred, space := range spaces {
+			tabs[pred] = &pb.Tablet{Predicate: pred, Space: space}
+		}
+		return tabs
+	}
+	return &pb.MembershipState{
+		Groups: map[uint32]*pb.Group{
+			1: {Tablets: tablets(map[string]int64{"dgraph.xid": 10, "name": 100, "age": 50,
+				"friend": 40})},
+			2: {Tablets: tablets(map[string]int64{"email": 20})},
+			3: {Tablets: tablets(map[string]int64{"city": 5}), Excluded: true},
+		},
+		PinnedTablets: map[string]uint32{"city": 2},
+	}
+}
+
+func TestPlanMoves(t *testing.T) {
+	state := testPlacementState()
+	moves := planMoves(state, 10)
+	require.Equal(t, []tabletMove{
+		{Predicate: "city", SrcGroup: 3, DstGroup: 2, Space: 5, Reason: "pinned"},
+		{Predicate: "age", SrcGroup: 1, DstGroup: 2, Space: 50, Reason: "balance"},
+	}, moves)
+	require.Equal(t, moves[:1], planMoves(state, 1))
+
+	// The pinned tablets aren't moved by the balancer.
+	state.PinnedTablets["age"] = 1
+	moves = planMoves(state, 10)
+	require.Equal(t, tabletMove{Predicate: "friend", SrcGroup: 1, DstGroup: 2, Space: 40,
+		Reason: "balance"}, moves[1])
+
+	// Nothing is moved to an excluded group.
+	state.Groups[2].Excluded = true
+	require.Empty(t, planMoves(state, 10)[1:])
+}
+
+func TestPlaceTablet(t *testing.T) {
+	server := &Server{state: testPlacementState()}
+	require.Equal(t, uint32(2), server.placeTablet("city", 1))
+	require.Equal(t, uint32(1), server.placeTablet("new", 1))
+	require.Equal(t, uint32(2), server.placeTablet("new", 3))
+
+	for _, group := range server.state.Groups {
+		group.Excluded = true
+	}
+	require.Equal(t, uint32(3), server.placeTablet("new", 3))
+}
+
+func TestFeatureVersion(t *testing.T) {
+	state := &pb.MembershipState{
+		Groups: map[uint32]*pb.Group{
+			1: {Members: map[uint64]*pb.Member{
+				1: {Id: 1, FeatureVersion: x.FeatureVersion},
+				2: {Id: 2},
+			}},
+		},
+	}
+	// The old member holds back the new features.
+	require.Equal(t, uint32(0), supportedFeatureVersion(state))
+	require.NoError(t, checkFeatureVersion(state, state.Groups[1].Members[2]))
+
+	state.Groups[1].Members[2].FeatureVersion = x.FeatureVersion
+	require.Equal(t, x.FeatureVersion, supportedFeatureVersion(state))
+
+	// Once the features are enabled, the old builds can't join back.
+	state.FeatureVersion = x.FeatureVersion
+	require.NoError(t, checkFeatureVersion(state, state.Groups[1].Members[1]))
+	err := checkFeatureVersion(state, &pb.Member{Id: 3})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "VERSION_SKEW")
+}
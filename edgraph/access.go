@@ -24,6 +24,7 @@ import (
 	"github.com/dgraph-io/badger/y"
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/query"
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/golang/glog"
 )
@@ -49,6 +50,12 @@ func RefreshAcls(closer *y.Closer) {
 	closer.Done()
 }
 
+// withMasking returns a context masking the values of all the @masked predicates, as they can
+// only be unmasked through ACL rules.
+func withMasking(ctx context.Context) context.Context {
+	return context.WithValue(ctx, query.MaskKey, query.Unmasker(nil))
+}
+
 func authorizeAlter(ctx context.Context, op *api.Operation) error {
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgraph/ee/acl"
 	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/query"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/x"
 	jwt "github.com/dgrijalva/jwt-go"
@@ -379,7 +380,7 @@ func ResetAcl() {
 			Set:       createUserNQuads,
 		}
 
-		if _, err := (&Server{}).doMutate(context.Background(), mu, false); err != nil {
+		if _, err := (&Server{}).doMutate(context.Background(), mu, false, nil, nil); err != nil {
 			return err
 		}
 		glog.Infof("Successfully upserted the groot account")
@@ -415,6 +416,23 @@ func extractUserAndGroups(ctx context.Context) ([]string, error) {
 	return validateToken(accessJwt[0])
 }
 
+// withMasking returns a context masking the values of the @masked predicates which the user
+// of the request isn't allowed to unmask. Only groot can unmask without an ACL rule.
+func withMasking(ctx context.Context) context.Context {
+	var unmask query.Unmasker
+	if len(Config.HmacSecret) > 0 {
+		if userData, err := extractUserAndGroups(ctx); err == nil {
+			groupIds := userData[1:]
+			if userData[0] == x.GrootId {
+				unmask = func(string) bool { return true }
+			} else {
+				unmask = func(pred string) bool { return aclCachePtr.canUnmask(groupIds, pred) }
+			}
+		}
+	}
+	return context.WithValue(ctx, query.MaskKey, unmask)
+}
+
 // authorizeAlter parses the Schema in the operation and authorizes the operation
 // using the aclCachePtr
 func authorizeAlter(ctx context.Context, op *api.Operation) error {
@@ -154,6 +154,26 @@ func (cache *aclCache) authorizePredicate(groups []string, predicate string,
 	return nil
 }
 
+// canUnmask checks if any group in the passed in groups is allowed to read the raw values of
+// the masked predicate. Unlike the other operations, it's denied when no rule is defined.
+func (cache *aclCache) canUnmask(groups []string, predicate string) bool {
+	aclCachePtr.RLock()
+	predPerms, predRegexRules := aclCachePtr.predPerms, aclCachePtr.predRegexRules
+	aclCachePtr.RUnlock()
+
+	if groupPerms, found := predPerms[predicate]; found &&
+		hasRequiredAccess(groupPerms, groups, acl.Unmask) {
+		return true
+	}
+	for _, predRegexRule := range predRegexRules {
+		if predRegexRule.predRegex.MatchString(predicate) &&
+			hasRequiredAccess(predRegexRule.groupPerms, groups, acl.Unmask) {
+			return true
+		}
+	}
+	return false
+}
+
 // hasRequiredAccess checks if any group in the passed in groups is allowed to perform the operation
 // according to the acl rules stored in groupPerms
 func hasRequiredAccess(groupPerms map[string]int32, groups []string,
@@ -78,3 +78,36 @@ func TestAclCache(t *testing.T) {
 	require.NoError(t, aclCachePtr.authorizePredicate([]string{group}, predicate, acl.Read),
 		"the user with group authorized should have access")
 }
+
+func TestAclCacheUnmask(t *testing.T) {
+	aclCachePtr = &aclCache{
+		predPerms:      make(map[string]map[string]int32),
+		predRegexRules: make([]*predRegexRule, 0),
+	}
+
+	group := "support"
+	require.False(t, aclCachePtr.canUnmask([]string{group}, "email"),
+		"values should stay masked when no acl is defined")
+
+	acls := []acl.Acl{
+		{
+			Predicate: "email",
+			Perm:      4 | 8,
+		},
+		{
+			Regex: "^ssn",
+			Perm:  8,
+		},
+		{
+			Predicate: "phone",
+			Perm:      4,
+		},
+	}
+	aclBytes, _ := json.Marshal(acls)
+	aclCachePtr.update([]acl.Group{{GroupID: group, Acls: string(aclBytes)}})
+	require.True(t, aclCachePtr.canUnmask([]string{group}, "email"))
+	require.True(t, aclCachePtr.canUnmask([]string{group}, "ssn.last4"))
+	require.False(t, aclCachePtr.canUnmask([]string{group}, "phone"),
+		"read access shouldn't unmask values")
+	require.False(t, aclCachePtr.canUnmask([]string{"dev"}, "email"))
+}
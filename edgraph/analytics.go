@@ -0,0 +1,398 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// Graph algorithms of the analytics jobs.
+const (
+	AnalyticsBetweenness = "betweenness"
+	AnalyticsCloseness   = "closeness"
+	AnalyticsTriangles   = "triangles"
+	// The community detection algorithms write the smallest uid of the community of the nodes.
+	AnalyticsLabelPropagation = "labelpropagation"
+	AnalyticsLouvain          = "louvain"
+)
+
+// Statuses of the analytics jobs.
+const (
+	analyticsQueued    = "queued"
+	analyticsRunning   = "running"
+	analyticsDone      = "done"
+	analyticsFailed    = "failed"
+	analyticsCancelled = "cancelled"
+)
+
+// Phases of the running analytics jobs.
+const (
+	analyticsLoading   = "loading"
+	analyticsComputing = "computing"
+	analyticsWriting   = "writing"
+)
+
+const (
+	// DefaultAnalyticsMaxEdges is the number of edges an analytics job loads at most, unless
+	// the request sets another limit.
+	DefaultAnalyticsMaxEdges = 10000000
+	// maxFinishedAnalyticsJobs is the number of finished jobs kept to be listed.
+	maxFinishedAnalyticsJobs = 20
+	// analyticsWriteBatch is the number of results written back per transaction.
+	analyticsWriteBatch = 1000
+	// defaultAnalyticsIterations is the number of passes over the nodes the community detection
+	// algorithms make at most, unless the request sets another limit.
+	defaultAnalyticsIterations = 20
+)
+
+// AnalyticsRequest asks to run a graph algorithm over the uid edges of some predicates.
+type AnalyticsRequest struct {
+	Algorithm  string
+	Predicates []string
+	// Output is the predicate the results are written to. They're streamed back instead if
+	// it's empty.
+	Output string
+	// Samples is the number of random sources the centralities are estimated from, or 0 to
+	// compute them exactly from all the nodes.
+	Samples int
+	// Iterations is the number of passes over the nodes the community detection algorithms
+	// make at most.
+	Iterations int
+	Seed       int64
+	// MaxEdges is the number of edges loaded at most, which bounds the memory of the job.
+	MaxEdges uint64
+}
+
+// AnalyticsJob is the status of a graph algorithm run by this Alpha.
+type AnalyticsJob struct {
+	Id         uint64   `json:"id"`
+	Algorithm  string   `json:"algorithm"`
+	Predicates []string `json:"predicates"`
+	Output     string   `json:"output,omitempty"`
+	Status     string   `json:"status"`
+	// Phase is loading the edges, computing, or writing the results. Done and Total are its
+	// progress: the edges loaded, the steps of the algorithm, or the results written.
+	Phase  string `json:"phase,omitempty"`
+	Done   uint64 `json:"done"`
+	Total  uint64 `json:"total,omitempty"`
+	ReadTs uint64 `json:"read_ts,omitempty"`
+	Nodes  uint64 `json:"nodes"`
+	Edges  uint64 `json:"edges"`
+	// Communities and Modularity are the number of communities found by a community detection
+	// algorithm, and their modularity.
+	Communities uint64    `json:"communities,omitempty"`
+	Modularity  float64   `json:"modularity,omitempty"`
+	Started     time.Time `json:"started,omitempty"`
+	Finished    time.Time `json:"finished,omitempty"`
+	Error       string    `json:"error,omitempty"`
+}
+
+// AnalyticsResult is the result of a graph algorithm for a node.
+type AnalyticsResult struct {
+	Uid   string      `json:"uid"`
+	Value interface{} `json:"value"`
+}
+
+// analyticsJob runs a graph algorithm. Its status is guarded by analyticsJobs.
+type analyticsJob struct {
+	status    AnalyticsJob
+	req       AnalyticsRequest
+	cancel    context.CancelFunc
+	cancelled bool
+}
+
+var analyticsJobs = struct {
+	sync.Mutex
+	lastId   uint64
+	active   map[uint64]*analyticsJob
+	finished []*analyticsJob
+	// running allows one job to run at once, as each of them holds a graph in memory.
+	running chan struct{}
+}{
+	active:  make(map[uint64]*analyticsJob),
+	running: make(chan struct{}, 1),
+}
+
+func validateAnalytics(req *AnalyticsRequest) error {
+	switch req.Algorithm {
+	case AnalyticsBetweenness, AnalyticsCloseness, AnalyticsTriangles,
+		AnalyticsLabelPropagation, AnalyticsLouvain:
+	default:
+		return errors.Errorf("Invalid algorithm %q: expected betweenness, closeness, triangles,"+
+			" labelpropagation or louvain", req.Algorithm)
+	}
+	if len(req.Predicates) == 0 {
+		return errors.New("The predicates of the edges are required")
+	}
+	for _, pred := range req.Predicates {
+		if pred == req.Output {
+			return errors.Errorf("Predicate %s is both an edge and the output", pred)
+		}
+	}
+	if req.Samples < 0 {
+		return errors.Errorf("Invalid number of samples: %d", req.Samples)
+	}
+	if req.Iterations < 0 {
+		return errors.Errorf("Invalid number of iterations: %d", req.Iterations)
+	}
+	if req.Iterations == 0 {
+		req.Iterations = defaultAnalyticsIterations
+	}
+	if req.MaxEdges == 0 {
+		req.MaxEdges = DefaultAnalyticsMaxEdges
+	}
+	return nil
+}
+
+func newAnalyticsJob(ctx context.Context, req *AnalyticsRequest) (context.Context,
+	*analyticsJob, error) {
+	if err := validateAnalytics(req); err != nil {
+		return nil, nil, err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	analyticsJobs.Lock()
+	defer analyticsJobs.Unlock()
+	analyticsJobs.lastId++
+	j := &analyticsJob{
+		status: AnalyticsJob{
+			Id:         analyticsJobs.lastId,
+			Algorithm:  req.Algorithm,
+			Predicates: req.Predicates,
+			Output:     req.Output,
+			Status:     analyticsQueued,
+		},
+		req:    *req,
+		cancel: cancel,
+	}
+	analyticsJobs.active[j.status.Id] = j
+	return ctx, j, nil
+}
+
+// StartAnalyticsJob runs the graph algorithm in the background, and writes its results to the
+// output predicate of the request.
+func StartAnalyticsJob(req *AnalyticsRequest) (AnalyticsJob, error) {
+	if req.Output == "" {
+		return AnalyticsJob{}, errors.New("The output predicate is required")
+	}
+	ctx, j, err := newAnalyticsJob(context.Background(), req)
+	if err != nil {
+		return AnalyticsJob{}, err
+	}
+	glog.Infof("Queued analytics job %d: %s of %v", j.status.Id, req.Algorithm, req.Predicates)
+	go func() {
+		if err := j.run(ctx, nil); err != nil {
+			glog.Errorf("Analytics job %d failed: %v", j.status.Id, err)
+		}
+	}()
+	analyticsJobs.Lock()
+	defer analyticsJobs.Unlock()
+	return j.status, nil
+}
+
+// RunAnalyticsJob runs the graph algorithm, and calls fn with the result of each node instead
+// of writing them. The job stops if ctx is cancelled.
+func RunAnalyticsJob(ctx context.Context, req *AnalyticsRequest,
+	fn func(*AnalyticsResult) error) error {
+	req.Output = ""
+	ctx, j, err := newAnalyticsJob(ctx, req)
+	if err != nil {
+		return err
+	}
+	return j.run(ctx, fn)
+}
+
+// AnalyticsJobs returns the status of the analytics jobs of this Alpha, the ones which haven't
+// finished first.
+func AnalyticsJobs() []AnalyticsJob {
+	analyticsJobs.Lock()
+	defer analyticsJobs.Unlock()
+	jobs := []AnalyticsJob{}
+	for _, j := range analyticsJobs.active {
+		jobs = append(jobs, j.status)
+	}
+	sort.Slice(jobs, func(i, k int) bool { return jobs[i].Id < jobs[k].Id })
+	for i := len(analyticsJobs.finished) - 1; i >= 0; i-- {
+		jobs = append(jobs, analyticsJobs.finished[i].status)
+	}
+	return jobs
+}
+
+// CancelAnalyticsJob stops the analytics job. The results already written are kept.
+func CancelAnalyticsJob(id uint64) error {
+	analyticsJobs.Lock()
+	defer analyticsJobs.Unlock()
+	j, ok := analyticsJobs.active[id]
+	if !ok {
+		return errors.Errorf("No analytics job %d is running", id)
+	}
+	j.cancelled = true
+	j.cancel()
+	return nil
+}
+
+func (j *analyticsJob) progress(phase string, done, total uint64) {
+	analyticsJobs.Lock()
+	defer analyticsJobs.Unlock()
+	j.status.Phase, j.status.Done, j.status.Total = phase, done, total
+}
+
+func (j *analyticsJob) finish(err error) {
+	analyticsJobs.Lock()
+	defer analyticsJobs.Unlock()
+	j.cancel()
+	j.status.Finished = time.Now()
+	switch {
+	case j.cancelled:
+		j.status.Status = analyticsCancelled
+	case err != nil:
+		j.status.Status = analyticsFailed
+		j.status.Error = err.Error()
+	default:
+		j.status.Status = analyticsDone
+		j.status.Phase = ""
+	}
+	delete(analyticsJobs.active, j.status.Id)
+	analyticsJobs.finished = append(analyticsJobs.finished, j)
+	if len(analyticsJobs.finished) > maxFinishedAnalyticsJobs {
+		analyticsJobs.finished = analyticsJobs.finished[1:]
+	}
+}
+
+// analyticsScores are the results of an algorithm, by node.
+type analyticsScores struct {
+	floats []float64
+	ints   []uint64
+}
+
+func (s *analyticsScores) value(i int) interface{} {
+	if s.floats != nil {
+		return s.floats[i]
+	}
+	return s.ints[i]
+}
+
+func (s *analyticsScores) apiValue(i int) *api.Value {
+	if s.floats != nil {
+		return &api.Value{Val: &api.Value_DoubleVal{DoubleVal: s.floats[i]}}
+	}
+	return &api.Value{Val: &api.Value_IntVal{IntVal: int64(s.ints[i])}}
+}
+
+// run loads the graph at a new read timestamp, runs the algorithm, and then writes the results
+// or calls fn with them.
+func (j *analyticsJob) run(ctx context.Context, fn func(*AnalyticsResult) error) (rerr error) {
+	defer func() { j.finish(rerr) }()
+
+	select {
+	case analyticsJobs.running <- struct{}{}:
+		defer func() { <-analyticsJobs.running }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ts, err := worker.Timestamps(ctx, &pb.Num{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	analyticsJobs.Lock()
+	j.status.Status, j.status.Started, j.status.ReadTs = analyticsRunning, time.Now(), ts.ReadOnly
+	analyticsJobs.Unlock()
+
+	g, err := worker.LoadAnalyticsGraph(ctx, j.req.Predicates, ts.ReadOnly, j.req.MaxEdges,
+		func(edges uint64) { j.progress(analyticsLoading, edges, 0) })
+	if err != nil {
+		return err
+	}
+	analyticsJobs.Lock()
+	j.status.Nodes, j.status.Edges = uint64(len(g.Uids)), g.NumEdges()
+	analyticsJobs.Unlock()
+
+	computing := func(done, total uint64) { j.progress(analyticsComputing, done, total) }
+	var scores analyticsScores
+	switch j.req.Algorithm {
+	case AnalyticsBetweenness:
+		scores.floats, err = g.Betweenness(ctx, j.req.Samples, j.req.Seed, computing)
+	case AnalyticsCloseness:
+		scores.floats, err = g.Closeness(ctx, j.req.Samples, j.req.Seed, computing)
+	case AnalyticsTriangles:
+		scores.ints, err = g.Triangles(ctx, computing)
+	case AnalyticsLabelPropagation:
+		scores.ints, err = g.LabelPropagation(ctx, j.req.Iterations, j.req.Seed, computing)
+	case AnalyticsLouvain:
+		scores.ints, err = g.Louvain(ctx, j.req.Iterations, j.req.Seed, computing)
+	}
+	if err != nil {
+		return err
+	}
+	if j.req.Algorithm == AnalyticsLabelPropagation || j.req.Algorithm == AnalyticsLouvain {
+		communities := make(map[uint64]struct{})
+		for _, c := range scores.ints {
+			communities[c] = struct{}{}
+		}
+		modularity := g.Modularity(scores.ints)
+		analyticsJobs.Lock()
+		j.status.Communities, j.status.Modularity = uint64(len(communities)), modularity
+		analyticsJobs.Unlock()
+	}
+
+	total := uint64(len(g.Uids))
+	if fn != nil {
+		for i, uid := range g.Uids {
+			if err := fn(&AnalyticsResult{
+				Uid:   fmt.Sprintf("%#x", uid),
+				Value: scores.value(i),
+			}); err != nil {
+				return err
+			}
+			j.progress(analyticsWriting, uint64(i+1), total)
+		}
+		return nil
+	}
+
+	// Each batch is committed on its own, so that a large job doesn't conflict with the
+	// mutations running meanwhile.
+	var batch []*api.NQuad
+	for i, uid := range g.Uids {
+		batch = append(batch, &api.NQuad{
+			Subject:     fmt.Sprintf("%#x", uid),
+			Predicate:   j.req.Output,
+			ObjectValue: scores.apiValue(i),
+		})
+		if len(batch) < analyticsWriteBatch && i < len(g.Uids)-1 {
+			continue
+		}
+		if err := (&Server{}).commitNQuads(ctx, batch, false, func() {}); err != nil {
+			return err
+		}
+		batch = nil
+		j.progress(analyticsWriting, uint64(i+1), total)
+	}
+	glog.Infof("Analytics job %d wrote the %s of %d nodes to %s", j.status.Id,
+		j.req.Algorithm, total, j.req.Output)
+	return nil
+}
@@ -0,0 +1,63 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateAnalytics(t *testing.T) {
+	req := &AnalyticsRequest{Algorithm: AnalyticsTriangles, Predicates: []string{"friend"}}
+	require.NoError(t, validateAnalytics(req))
+	require.Equal(t, uint64(DefaultAnalyticsMaxEdges), req.MaxEdges)
+	require.Equal(t, defaultAnalyticsIterations, req.Iterations)
+
+	for _, req := range []*AnalyticsRequest{
+		{Algorithm: "pagerank", Predicates: []string{"friend"}},
+		{Algorithm: AnalyticsBetweenness},
+		{Algorithm: AnalyticsCloseness, Predicates: []string{"friend"}, Output: "friend"},
+		{Algorithm: AnalyticsCloseness, Predicates: []string{"friend"}, Samples: -1},
+		{Algorithm: AnalyticsLouvain, Predicates: []string{"friend"}, Iterations: -1},
+	} {
+		require.Error(t, validateAnalytics(req), "%+v", req)
+	}
+	_, err := StartAnalyticsJob(&AnalyticsRequest{Algorithm: AnalyticsTriangles,
+		Predicates: []string{"friend"}})
+	require.Error(t, err)
+}
+
+func TestCancelAnalyticsJob(t *testing.T) {
+	// The job stays queued while another one runs.
+	analyticsJobs.running <- struct{}{}
+	defer func() { <-analyticsJobs.running }()
+
+	job, err := StartAnalyticsJob(&AnalyticsRequest{Algorithm: AnalyticsBetweenness,
+		Predicates: []string{"friend"}, Output: "score"})
+	require.NoError(t, err)
+	require.Equal(t, analyticsQueued, job.Status)
+	require.Equal(t, analyticsQueued, AnalyticsJobs()[0].Status)
+
+	require.NoError(t, CancelAnalyticsJob(job.Id))
+	for i := 0; i < 100 && AnalyticsJobs()[0].Status == analyticsQueued; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Equal(t, analyticsCancelled, AnalyticsJobs()[0].Status)
+	require.Error(t, CancelAnalyticsJob(job.Id))
+}
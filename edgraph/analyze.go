@@ -0,0 +1,592 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// The cost of a query is estimated from rough guesses of the sizes of the data, which are only
+// meant to make the costs of different queries, or versions of a query, comparable.
+const (
+	// costMatches is the number of nodes a function reading an index is assumed to match.
+	costMatches = 100
+	// costScanned is the number of nodes has() is assumed to match.
+	costScanned = 10000
+	// costFanout is the number of edges of a uid predicate a node is assumed to have.
+	costFanout = 10
+)
+
+// QueryAnalysis is the result of the static analysis of a query, which is parsed and checked
+// against the schema without being run.
+type QueryAnalysis struct {
+	Blocks []*BlockAnalysis `json:"blocks,omitempty"`
+	// Errors lists the issues the query would fail with, Warnings the ones it would run with.
+	Errors   []string `json:"errors,omitempty"`
+	Warnings []string `json:"warnings,omitempty"`
+	// Cost is the estimated number of posting lists the query reads.
+	Cost     uint64        `json:"cost"`
+	Estimate QueryEstimate `json:"estimate"`
+}
+
+// QueryEstimate breaks down the estimated cost of a query.
+type QueryEstimate struct {
+	// Uids is the number of nodes the query touches.
+	Uids uint64 `json:"uids"`
+	// IndexSeeks is the number of times the query reads the index of a predicate.
+	IndexSeeks uint64 `json:"index_seeks"`
+	// RemoteCalls is the number of tasks the query sends to the other groups of the cluster.
+	RemoteCalls uint64 `json:"remote_calls"`
+}
+
+// BlockAnalysis is the structure of a query block, or of one of its fields.
+type BlockAnalysis struct {
+	Name       string            `json:"name"`
+	Predicate  string            `json:"predicate,omitempty"`
+	Func       string            `json:"func,omitempty"`
+	Filter     string            `json:"filter,omitempty"`
+	Args       map[string]string `json:"args,omitempty"`
+	Var        string            `json:"var,omitempty"`
+	Directives []string          `json:"directives,omitempty"`
+	Fields     []*BlockAnalysis  `json:"fields,omitempty"`
+	Cost       uint64            `json:"cost"`
+}
+
+// funcString returns the function as it's written in a query.
+func funcString(f *gql.Function) string {
+	var args []string
+	switch {
+	case f.IsCount:
+		args = append(args, "count("+f.Attr+")")
+	case f.IsValueVar:
+		args = append(args, "val("+f.Attr+")")
+	case f.IsLenVar:
+		args = append(args, "len("+f.Attr+")")
+	case f.Attr != "" && f.Lang != "":
+		args = append(args, f.Attr+"@"+f.Lang)
+	case f.Attr != "":
+		args = append(args, f.Attr)
+	}
+	for _, uid := range f.UID {
+		args = append(args, fmt.Sprintf("%#x", uid))
+	}
+	for _, v := range f.NeedsVar {
+		switch {
+		case f.Name == "uid":
+			args = append(args, v.Name)
+		case f.Attr == "" && len(f.Args) == 0:
+			// The aggregations of the values of a variable.
+			args = append(args, "val("+v.Name+")")
+		}
+	}
+	for _, arg := range f.Args {
+		if arg.IsValueVar {
+			args = append(args, "val("+arg.Value+")")
+		} else if arg.IsOuter {
+			args = append(args, "outer("+arg.Value+")")
+		} else {
+			args = append(args, strconv.Quote(arg.Value))
+		}
+	}
+	return f.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+// filterString returns the filter as it's written in a query.
+func filterString(ft *gql.FilterTree) string {
+	if ft.Func != nil {
+		s := funcString(ft.Func)
+		for _, child := range ft.Child {
+			// The filter of has_edge is one of its arguments.
+			s = strings.TrimSuffix(s, ")") + ", @filter(" + filterString(child) + "))"
+		}
+		return s
+	}
+	children := make([]string, 0, len(ft.Child))
+	for _, child := range ft.Child {
+		children = append(children, filterString(child))
+	}
+	if ft.Op == "not" && len(children) == 1 {
+		return "NOT " + children[0]
+	}
+	return "(" + strings.Join(children, " "+strings.ToUpper(ft.Op)+" ") + ")"
+}
+
+// directives returns the directives of the block, other than @filter.
+func directives(gq *gql.GraphQuery) []string {
+	var ds []string
+	if gq.Recurse {
+		ds = append(ds, fmt.Sprintf("recurse(depth: %d, loop: %t)",
+			gq.RecurseArgs.Depth, gq.RecurseArgs.AllowLoop))
+	}
+	for _, d := range []struct {
+		name string
+		set  bool
+	}{
+		{"normalize", gq.Normalize},
+		{"cascade", gq.Cascade},
+		{"ignorereflex", gq.IgnoreReflex},
+		{"groupby", gq.IsGroupby},
+		{"facets", gq.Facets != nil},
+	} {
+		if d.set {
+			ds = append(ds, d.name)
+		}
+	}
+	if gq.At != "" {
+		ds = append(ds, "at("+gq.At+")")
+	}
+	switch {
+	case gq.Budget > 0:
+		ds = append(ds, "budget("+gq.Budget.String()+")")
+	case gq.BestEffort:
+		ds = append(ds, "besteffort")
+	}
+	return ds
+}
+
+// queryPredicates returns the predicates the query blocks read.
+func queryPredicates(gqs []*gql.GraphQuery) []string {
+	seen := make(map[string]bool)
+	var preds []string
+	add := func(attr string) {
+		attr = strings.TrimPrefix(attr, "~")
+		if attr != "" && !seen[attr] {
+			seen[attr] = true
+			preds = append(preds, attr)
+		}
+	}
+	addFunc := func(f *gql.Function) {
+		if f == nil || f.IsValueVar || f.IsLenVar {
+			return
+		}
+		add(f.Attr)
+		for _, arg := range f.Args {
+			if arg.IsOuter {
+				add(arg.Value)
+			}
+		}
+	}
+	var addFilter func(ft *gql.FilterTree)
+	addFilter = func(ft *gql.FilterTree) {
+		addFunc(ft.Func)
+		for _, child := range ft.Child {
+			addFilter(child)
+		}
+	}
+	var addBlock func(gq *gql.GraphQuery)
+	addBlock = func(gq *gql.GraphQuery) {
+		if !gq.IsInternal && gq.Attr != "uid" {
+			add(gq.Attr)
+		}
+		addFunc(gq.Func)
+		if gq.Filter != nil {
+			addFilter(gq.Filter)
+		}
+		for _, order := range gq.Order {
+			// Orders by val(a) have the name of the variable as their attribute.
+			isVar := false
+			for _, v := range gq.NeedsVar {
+				isVar = isVar || v.Name == order.Attr
+			}
+			if !isVar {
+				add(order.Attr)
+			}
+		}
+		for _, attr := range gq.GroupbyAttrs {
+			add(attr.Attr)
+		}
+		for _, child := range gq.Children {
+			addBlock(child)
+		}
+	}
+	for _, gq := range gqs {
+		addBlock(gq)
+	}
+	return preds
+}
+
+// limit returns the number of nodes left out of n by the first argument of the block.
+func limit(gq *gql.GraphQuery, n float64) float64 {
+	first, err := strconv.ParseInt(gq.Args["first"], 0, 64)
+	if err != nil || first == 0 {
+		return n
+	}
+	if first < 0 {
+		first = -first
+	}
+	return math.Min(n, float64(first))
+}
+
+// toCost rounds the estimated cost, which may overflow for deep queries.
+func toCost(cost float64) uint64 {
+	if cost >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(cost)
+}
+
+// estimate is the estimated cost of a part of a query.
+type estimate struct {
+	postings, uids, seeks, calls float64
+}
+
+func (e *estimate) add(o estimate) {
+	e.postings += o.postings
+	e.uids += o.uids
+	e.seeks += o.seeks
+	e.calls += o.calls
+}
+
+func (e estimate) scale(f float64) estimate {
+	return estimate{e.postings * f, e.uids * f, e.seeks * f, e.calls * f}
+}
+
+type queryAnalyzer struct {
+	schema map[string]*api.SchemaNode
+	// remote tells which predicates are served by another group than the one of this alpha.
+	remote    map[string]bool
+	edgeLimit uint64
+	errors    []string
+	warnings  []string
+}
+
+func (a *queryAnalyzer) errorf(path, format string, args ...interface{}) {
+	a.errors = append(a.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (a *queryAnalyzer) warnf(path, format string, args ...interface{}) {
+	a.warnings = append(a.warnings, path+": "+fmt.Sprintf(format, args...))
+}
+
+// call returns the number of calls to another group reading the predicate takes.
+func (a *queryAnalyzer) call(attr string) float64 {
+	if a.remote[attr] {
+		return 1
+	}
+	return 0
+}
+
+// checkFunc reports the function if its predicate isn't in the schema, or doesn't have the index
+// it needs. It returns the estimated cost of running the function over the given number of
+// posting lists.
+func (a *queryAnalyzer) checkFunc(f *gql.Function, path string, atRoot bool,
+	postings float64) estimate {
+
+	e := estimate{postings: postings}
+	if f.Attr == "" || f.IsValueVar || f.IsLenVar || f.Name == "uid" {
+		return e
+	}
+	if f.Name == "has" && strings.ContainsAny(f.Attr, "*?") {
+		// The predicates matching the pattern of has are only known when it runs.
+		return e
+	}
+	su, ok := a.schema[f.Attr]
+	if !ok {
+		a.warnf(path, "Predicate %s of %s isn't in the schema", f.Attr, f.Name)
+		return e
+	}
+	args := make([]string, 0, len(f.Args))
+	for _, arg := range f.Args {
+		args = append(args, arg.Value)
+	}
+	if err := worker.CheckFuncIndex(f.Name, f.Attr, args, f.IsCount, atRoot,
+		su.Tokenizer); err != nil {
+		a.errorf(path, "%s", err)
+	}
+	if worker.FuncReadsIndex(f.Name, f.IsCount) {
+		e.seeks = 1
+	}
+	e.calls = a.call(f.Attr)
+	return e
+}
+
+// checkFilter checks the functions of the filter, and returns the estimated cost of running
+// them over the given number of nodes.
+func (a *queryAnalyzer) checkFilter(ft *gql.FilterTree, path string, nodes float64) estimate {
+	if ft.Func != nil && ft.Func.Name == "has_edge" {
+		// The edges of the nodes are read, and the filter of has_edge runs on the nodes they
+		// lead to.
+		e := estimate{postings: nodes, calls: a.call(ft.Func.Attr)}
+		if _, ok := a.schema[strings.TrimPrefix(ft.Func.Attr, "~")]; !ok {
+			a.warnf(path, "Predicate %s of has_edge isn't in the schema", ft.Func.Attr)
+		}
+		for _, child := range ft.Child {
+			e.add(a.checkFilter(child, path, nodes*costFanout))
+		}
+		return e
+	}
+	if ft.Func != nil {
+		return a.checkFunc(ft.Func, path, false, nodes)
+	}
+	var e estimate
+	for _, child := range ft.Child {
+		e.add(a.checkFilter(child, path, nodes))
+	}
+	return e
+}
+
+// root checks the function of the query block, and returns the estimated number of nodes it
+// starts from, and the cost of getting them.
+func (a *queryAnalyzer) root(gq *gql.GraphQuery, path string) (float64, estimate) {
+	var nodes float64
+	switch {
+	case len(gq.UID) > 0:
+		nodes = float64(len(gq.UID))
+	case gq.ShortestPathArgs.From != nil:
+		nodes = 1
+	case gq.Func == nil:
+		// The blocks without a function only aggregate the values of variables.
+	case gq.Func.Name == "uid" && len(gq.Func.NeedsVar) == 0:
+		nodes = float64(len(gq.Func.UID))
+	case gq.Func.Name == "has":
+		nodes = costScanned
+	default:
+		nodes = costMatches
+	}
+	e := estimate{postings: nodes}
+	if gq.Func != nil && len(gq.UID) == 0 {
+		e = a.checkFunc(gq.Func, path, true, nodes)
+	}
+	e.uids = nodes
+	return nodes, e
+}
+
+// block analyzes the query block, or one of its fields, given the estimated cost of getting to
+// its nodes.
+func (a *queryAnalyzer) block(gq *gql.GraphQuery, name, path string, self estimate,
+	nodes float64) (*BlockAnalysis, estimate) {
+
+	b := &BlockAnalysis{
+		Name:       name,
+		Var:        gq.Var,
+		Directives: directives(gq),
+	}
+	if !gq.IsInternal {
+		b.Predicate = gq.Attr
+	}
+	if len(gq.Args) > 0 {
+		b.Args = gq.Args
+	}
+	if gq.Func != nil {
+		b.Func = funcString(gq.Func)
+	}
+	e := self
+	if gq.Filter != nil {
+		b.Filter = filterString(gq.Filter)
+		e.add(a.checkFilter(gq.Filter, path, nodes))
+	}
+
+	if gq.UidCount {
+		name := gq.UidCountAlias
+		if name == "" {
+			name = "count(uid)"
+		}
+		b.Fields = append(b.Fields, &BlockAnalysis{Name: name})
+	}
+	var fields estimate
+	var fanout float64 = 1
+	for _, child := range gq.Children {
+		field, fe, isUid := a.field(child, path, nodes)
+		b.Fields = append(b.Fields, field)
+		fields.add(fe)
+		if isUid {
+			fanout = costFanout
+		}
+	}
+
+	if gq.Recurse {
+		depth := gq.RecurseArgs.Depth
+		switch {
+		case depth == 0 && gq.RecurseArgs.AllowLoop:
+			a.errorf(path, "Depth must be > 0 when loop is true for recurse query")
+		case depth == 0:
+			a.warnf(path, "@recurse has no depth, it follows the edges until it runs out of"+
+				" new nodes or reaches the limit of %d edges", a.edgeLimit)
+			// The recursion is assumed to go on until it reaches the edge limit.
+			if fields.postings > 0 {
+				fields = fields.scale(float64(a.edgeLimit) / fields.postings)
+			}
+		default:
+			// Each level of the recursion reads the fields of the nodes of the previous one.
+			var levels float64
+			for i := uint64(0); i < depth; i++ {
+				levels += math.Pow(fanout, float64(i))
+			}
+			fields = fields.scale(levels)
+		}
+	}
+	e.add(fields)
+	b.Cost = toCost(e.postings)
+	return b, e
+}
+
+// field analyzes a field of the block, read for the given number of nodes. It also returns
+// whether it follows the edges of a uid predicate to other nodes.
+func (a *queryAnalyzer) field(gq *gql.GraphQuery, parent string, nodes float64) (
+	*BlockAnalysis, estimate, bool) {
+
+	name := gq.Alias
+	switch {
+	case name != "":
+	case gq.Expand != "":
+		name = "expand(" + gq.Expand + ")"
+	case gq.IsCount:
+		name = "count(" + gq.Attr + ")"
+	default:
+		name = gq.Attr
+	}
+	path := parent + "." + name
+
+	// The values of variables, maths, aggregations and expand() don't read a predicate of
+	// their own.
+	if gq.IsInternal || gq.Attr == "uid" {
+		b, e := a.block(gq, name, path, estimate{}, nodes)
+		return b, e, false
+	}
+	if gq.Func != nil {
+		a.checkFunc(gq.Func, path, false, 0)
+	}
+	attr := strings.TrimPrefix(gq.Attr, "~")
+	self := estimate{postings: nodes, calls: a.call(attr)}
+	su, ok := a.schema[attr]
+	switch {
+	case !ok:
+		a.warnf(path, "Predicate %s isn't in the schema", attr)
+		b, e := a.block(gq, name, path, self, 0)
+		return b, e, false
+	case attr != gq.Attr && !su.Reverse:
+		a.errorf(path, "Predicate %s doesn't have reverse edge", attr)
+	case gq.IsCount || (attr == gq.Attr && su.Type != "uid"):
+		b, e := a.block(gq, name, path, self, 0)
+		return b, e, false
+	}
+	self.uids = nodes * limit(gq, costFanout)
+	b, e := a.block(gq, name, path, self, self.uids)
+	return b, e, true
+}
+
+// analyzeQuery returns the structure, issues and estimated cost of the parsed query, given the
+// schema of the predicates it reads, and the ones served by other groups.
+func analyzeQuery(res *gql.Result, schema map[string]*api.SchemaNode, remote map[string]bool,
+	edgeLimit uint64) *QueryAnalysis {
+
+	a := &queryAnalyzer{schema: schema, remote: remote, edgeLimit: edgeLimit}
+	a.warnings = append(a.warnings, res.Warnings...)
+	analysis := &QueryAnalysis{}
+	var total estimate
+	for _, gq := range res.Query {
+		nodes, self := a.root(gq, gq.Alias)
+		b, e := a.block(gq, gq.Alias, gq.Alias, self, limit(gq, nodes))
+		analysis.Blocks = append(analysis.Blocks, b)
+		total.add(e)
+	}
+	analysis.Errors, analysis.Warnings = a.errors, a.warnings
+	analysis.Cost = toCost(total.postings)
+	analysis.Estimate = QueryEstimate{
+		Uids:        toCost(total.uids),
+		IndexSeeks:  toCost(total.seeks),
+		RemoteCalls: toCost(total.calls),
+	}
+	return analysis
+}
+
+// AnalyzeQuery returns the structure of the query, the issues it would fail or run with, and an
+// estimate of its cost, without running it. It's meant for the queries of applications to be
+// checked against the schema of the cluster before they're deployed. The query not parsing is
+// reported as an issue rather than an error.
+func (s *Server) AnalyzeQuery(ctx context.Context, req *api.Request) (*QueryAnalysis, error) {
+	if err := x.HealthCheck(); err != nil {
+		return nil, err
+	}
+	if len(req.Query) == 0 {
+		return nil, x.WithCode(errors.Errorf("Empty query"), x.CodeInvalidQuery)
+	}
+	parsedReq, err := parseQuery(gql.Request{
+		Str:       req.Query,
+		Variables: req.Vars,
+	})
+	if err == nil {
+		err = validateQuery(parsedReq.Query)
+	}
+	if err != nil {
+		return &QueryAnalysis{Errors: []string{err.Error()}}, nil
+	}
+	if err := authorizeQuery(ctx, req); err != nil {
+		return nil, err
+	}
+
+	schema := make(map[string]*api.SchemaNode)
+	preds := queryPredicates(parsedReq.Query)
+	remote, err := worker.RemotePredicates(preds)
+	if err != nil {
+		return nil, err
+	}
+	if len(preds) > 0 {
+		nodes, err := worker.GetSchemaOverNetwork(ctx, &pb.SchemaRequest{
+			Predicates: preds,
+			Fields:     []string{"type", "index", "tokenizer", "reverse"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, node := range nodes {
+			schema[node.Predicate] = node
+		}
+	}
+	x.ConfigMu.RLock()
+	edgeLimit := x.Config.QueryEdgeLimit
+	x.ConfigMu.RUnlock()
+	return analyzeQuery(&parsedReq, schema, remote, edgeLimit), nil
+}
+
+// isDryRun tells if the dry-run metadata of the request asks for the query to be analyzed
+// instead of being run.
+func isDryRun(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	vals := md.Get("dry-run")
+	return len(vals) > 0 && vals[0] == "true"
+}
+
+// dryRunQuery returns the analysis of the query as the JSON of the response, for clients to
+// check its estimated cost and refuse to run the queries over their budget.
+func (s *Server) dryRunQuery(ctx context.Context, req *api.Request) (*api.Response, error) {
+	analysis, err := s.AnalyzeQuery(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	js, err := json.Marshal(analysis)
+	if err != nil {
+		return nil, err
+	}
+	return &api.Response{Json: js}, nil
+}
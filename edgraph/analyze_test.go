@@ -0,0 +1,162 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+)
+
+var analyzeSchema = map[string]*api.SchemaNode{
+	"name":   {Predicate: "name", Type: "string", Index: true, Tokenizer: []string{"hash"}},
+	"age":    {Predicate: "age", Type: "int"},
+	"friend": {Predicate: "friend", Type: "uid", Reverse: true},
+	"boss":   {Predicate: "boss", Type: "uid"},
+}
+
+func analyze(t *testing.T, query string) *QueryAnalysis {
+	res, err := gql.Parse(gql.Request{Str: query})
+	require.NoError(t, err)
+	return analyzeQuery(&res, analyzeSchema, nil, 1000000)
+}
+
+func TestAnalyzeQuery(t *testing.T) {
+	analysis := analyze(t, `
+	{
+		me(func: eq(name, "alice"), first: 5) {
+			name
+			friend(first: 2) @filter(ge(age, 20)) {
+				name
+			}
+		}
+	}`)
+	require.Equal(t, []string{"me.friend: Predicate age is not indexed"}, analysis.Errors)
+	require.Empty(t, analysis.Warnings)
+
+	res, err := gql.Parse(gql.Request{Str: `{ me(func: eq(name, "alice")) { friend { age } } }`})
+	require.NoError(t, err)
+	require.Equal(t, []string{"name", "friend", "age"}, queryPredicates(res.Query))
+
+	require.Len(t, analysis.Blocks, 1)
+	me := analysis.Blocks[0]
+	require.Equal(t, "me", me.Name)
+	require.Equal(t, `eq(name, "alice")`, me.Func)
+	require.Equal(t, map[string]string{"first": "5"}, me.Args)
+	require.Len(t, me.Fields, 2)
+	friend := me.Fields[1]
+	require.Equal(t, "friend", friend.Predicate)
+	require.Equal(t, `ge(age, "20")`, friend.Filter)
+
+	// The 100 nodes matching the index, the names and friends of the first 5 of them, the ages
+	// and names of their first 2 friends each.
+	require.Equal(t, uint64(5+10+10), friend.Cost)
+	require.Equal(t, uint64(100+5+5+20), me.Cost)
+	require.Equal(t, me.Cost, analysis.Cost)
+}
+
+func TestAnalyzeQueryIssues(t *testing.T) {
+	analysis := analyze(t, `
+	{
+		me(func: uid(0x1)) @recurse {
+			name
+			~friend
+			~boss
+		}
+	}`)
+	require.Equal(t, []string{"me.~boss: Predicate boss doesn't have reverse edge"},
+		analysis.Errors)
+	require.Equal(t, []string{"me: @recurse has no depth, it follows the edges until it runs" +
+		" out of new nodes or reaches the limit of 1000000 edges"}, analysis.Warnings)
+	require.Equal(t, []string{"recurse(depth: 0, loop: false)"},
+		analysis.Blocks[0].Directives)
+	require.Equal(t, uint64(1+1000000), analysis.Cost)
+
+	analysis = analyze(t, `
+	{
+		me(func: uid(0x1)) @recurse(depth: 3) {
+			friend
+		}
+	}`)
+	require.Empty(t, analysis.Errors)
+	require.Empty(t, analysis.Warnings)
+	// A node, then 10 and 100 friends, whose friends are read.
+	require.Equal(t, uint64(1+1+10+100), analysis.Cost)
+
+	res, err := gql.Parse(gql.Request{Str: `
+	query q($limit: int = 2) {
+		me(func: regexp(name, /^a/)) @filter(anyofterms(name, "a b")) {
+			nick
+		}
+	}`})
+	require.NoError(t, err)
+	analysis = analyzeQuery(&res, analyzeSchema, nil, 1000000)
+	require.Equal(t, []string{
+		"me: Attribute name does not have trigram index for regex matching. Please add a" +
+			" trigram index or use has/uid function with regexp() as filter.",
+		"me: Attribute name is not indexed with type term",
+	}, analysis.Errors)
+	require.Equal(t, []string{
+		"Variable $limit is declared but not used",
+		"me.nick: Predicate nick isn't in the schema",
+	}, analysis.Warnings)
+	require.Equal(t, `(regexp(name, "^a", "") AND anyofterms(name, "a b"))`,
+		filterString(&gql.FilterTree{Op: "and", Child: []*gql.FilterTree{
+			{Func: res.Query[0].Func}, res.Query[0].Filter}}))
+
+	res, err = gql.Parse(gql.Request{Str: `{
+		me(func: eq(name, "alice")) @filter(not has_edge(friend, @filter(ge(age, 20)))) {
+			name
+		}
+	}`})
+	require.NoError(t, err)
+	analysis = analyzeQuery(&res, analyzeSchema, nil, 1000000)
+	// The filter of has_edge is checked too.
+	require.Equal(t, []string{"me: Predicate age is not indexed"}, analysis.Errors)
+	require.Equal(t, `NOT has_edge(friend, @filter(ge(age, "20")))`,
+		filterString(res.Query[0].Filter))
+}
+
+func TestAnalyzeQueryEstimate(t *testing.T) {
+	res, err := gql.Parse(gql.Request{Str: `
+	{
+		me(func: eq(name, "alice"), first: 5) {
+			name
+			friend(first: 2) @filter(ge(age, 20)) {
+				name
+			}
+		}
+	}`})
+	require.NoError(t, err)
+	analysis := analyzeQuery(&res, analyzeSchema, map[string]bool{"friend": true, "age": true},
+		1000000)
+	require.Equal(t, uint64(130), analysis.Cost)
+	// The 100 nodes matching the index and the 10 friends of the first 5 of them, found with
+	// the index of name and filtered with the index of age, on the group serving friend and age.
+	require.Equal(t, QueryEstimate{Uids: 110, IndexSeeks: 2, RemoteCalls: 2}, analysis.Estimate)
+}
+
+func TestIsDryRun(t *testing.T) {
+	require.False(t, isDryRun(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("dry-run", "true"))
+	require.True(t, isDryRun(ctx))
+}
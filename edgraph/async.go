@@ -0,0 +1,152 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// asyncResultTTL is how long the result of an async mutation is kept if it isn't awaited.
+const asyncResultTTL = 10 * time.Minute
+
+type asyncMutation struct {
+	done     chan struct{}
+	resp     *api.Assigned
+	err      error
+	finished time.Time
+}
+
+var asyncMutations = struct {
+	sync.Mutex
+	m map[string]*asyncMutation
+}{m: make(map[string]*asyncMutation)}
+
+// isAsync returns whether the async metadata of the request is true.
+func isAsync(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	vals := md.Get("async")
+	return len(vals) > 0 && vals[0] == "true"
+}
+
+func validateAsync(mu *api.Mutation) error {
+	if !mu.CommitNow {
+		return errors.Errorf("Async mutations must be committed immediately")
+	}
+	if mu.Query != "" {
+		return errors.Errorf("Async mutations can't have an upsert query")
+	}
+	return nil
+}
+
+// addAsyncMutation registers the mutation and returns its ticket. The results which haven't
+// been awaited in time are dropped.
+func addAsyncMutation(am *asyncMutation, startTs uint64) string {
+	asyncMutations.Lock()
+	defer asyncMutations.Unlock()
+
+	for ticket, other := range asyncMutations.m {
+		select {
+		case <-other.done:
+			if time.Since(other.finished) > asyncResultTTL {
+				delete(asyncMutations.m, ticket)
+			}
+		default:
+		}
+	}
+	ticket := fmt.Sprintf("%x-%x", startTs, rand.Uint32())
+	asyncMutations.m[ticket] = am
+	return ticket
+}
+
+func removeAsyncMutation(ticket string) {
+	asyncMutations.Lock()
+	defer asyncMutations.Unlock()
+	delete(asyncMutations.m, ticket)
+}
+
+// applyAsync applies and commits the mutations in the background. It returns the ticket of the
+// mutations once their proposals are in the Raft logs, or the error if they failed before. The
+// conflicts are added to the keys of the transaction.
+func applyAsync(ctx context.Context, m *pb.Mutations, conflicts []string,
+	resp *api.Assigned) (string, error) {
+	am := &asyncMutation{done: make(chan struct{})}
+	ticket := addAsyncMutation(am, m.StartTs)
+
+	logged := make(chan struct{})
+	var once sync.Once
+	// The mutation outlives the request, so it doesn't use the context of the request.
+	actx := worker.WithProposalsLogged(context.Background(), func() {
+		once.Do(func() { close(logged) })
+	})
+	go func() {
+		res := &api.Assigned{Uids: resp.Uids}
+		var err error
+		res.Context, err = query.ApplyMutations(actx, m)
+		addConflictKeys(res.Context, conflicts)
+		err = commitImmediately(actx, res, m.StartTs, err)
+		am.resp, am.err, am.finished = res, err, time.Now()
+		close(am.done)
+	}()
+
+	resp.Context = &api.TxnContext{StartTs: m.StartTs}
+	select {
+	case <-logged:
+		return ticket, nil
+	case <-am.done:
+		if am.err != nil {
+			removeAsyncMutation(ticket)
+			return "", am.err
+		}
+		return ticket, nil
+	case <-ctx.Done():
+		return ticket, ctx.Err()
+	}
+}
+
+// AwaitMutation waits for the async mutation of the ticket to be applied and committed, and
+// returns its result. The result of a mutation can only be returned once.
+func AwaitMutation(ctx context.Context, ticket string) (*api.Assigned, error) {
+	asyncMutations.Lock()
+	am, ok := asyncMutations.m[ticket]
+	asyncMutations.Unlock()
+	if !ok {
+		return nil, errors.Errorf("Unknown or expired ticket %q", ticket)
+	}
+
+	select {
+	case <-am.done:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	removeAsyncMutation(ticket)
+	return am.resp, am.err
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestIsAsync(t *testing.T) {
+	require.False(t, isAsync(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("async", "true"))
+	require.True(t, isAsync(ctx))
+
+	require.NoError(t, validateAsync(&api.Mutation{CommitNow: true}))
+	require.Error(t, validateAsync(&api.Mutation{}))
+	require.Error(t, validateAsync(&api.Mutation{CommitNow: true, Query: "{ q(func: uid(1)) }"}))
+}
+
+func TestAwaitMutation(t *testing.T) {
+	am := &asyncMutation{done: make(chan struct{})}
+	ticket := addAsyncMutation(am, 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := AwaitMutation(ctx, ticket)
+	require.Equal(t, context.DeadlineExceeded, err)
+
+	am.resp = &api.Assigned{Context: &api.TxnContext{StartTs: 10, CommitTs: 11}}
+	am.finished = time.Now()
+	close(am.done)
+	resp, err := AwaitMutation(context.Background(), ticket)
+	require.NoError(t, err)
+	require.Equal(t, uint64(11), resp.Context.CommitTs)
+
+	// The result is only returned once.
+	_, err = AwaitMutation(context.Background(), ticket)
+	require.Error(t, err)
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// existenceBitmap returns the bitmap of n items, where the bit i%8 of the byte i/8 is set if
+// the item i exists.
+func existenceBitmap(n int, exists func(i int) bool) []byte {
+	bitmap := make([]byte, (n+7)/8)
+	for i := 0; i < n; i++ {
+		if exists(i) {
+			bitmap[i/8] |= 1 << uint(i%8)
+		}
+	}
+	return bitmap
+}
+
+// UidsExist returns the bitmap of the uids which are nodes with any predicate, see
+// existenceBitmap. It reads the posting lists of the uids directly, rather than running a query.
+func (s *Server) UidsExist(ctx context.Context, uids []uint64) ([]byte, error) {
+	all := append([]uint64{}, uids...)
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	sorted := &pb.List{}
+	for i, uid := range all {
+		if uid != 0 && (i == 0 || all[i-1] != uid) {
+			sorted.Uids = append(sorted.Uids, uid)
+		}
+	}
+
+	var existing *pb.List
+	if len(sorted.Uids) > 0 {
+		var err error
+		existing, err = worker.ExistingUids(ctx, sorted, State.getTimestamp(true))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return existenceBitmap(len(uids), func(i int) bool {
+		return existing != nil && algo.IndexOf(existing, uids[i]) >= 0
+	}), nil
+}
+
+// XidsExist returns the bitmap of the external ids of pred which exist, see existenceBitmap. If
+// pred is empty, the single predicate declared with @xid is used.
+func (s *Server) XidsExist(ctx context.Context, pred string, xids []string) ([]byte, error) {
+	pred, err := xidPredicate(ctx, pred)
+	if err != nil {
+		return nil, err
+	}
+	var found map[string]uint64
+	if len(xids) > 0 {
+		if found, err = lookupXids(ctx, pred, xids, State.getTimestamp(true)); err != nil {
+			return nil, err
+		}
+	}
+	return existenceBitmap(len(xids), func(i int) bool {
+		_, ok := found[xids[i]]
+		return ok
+	}), nil
+}
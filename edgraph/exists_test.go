@@ -0,0 +1,30 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExistenceBitmap(t *testing.T) {
+	exists := []bool{true, false, false, true, false, false, false, false, true}
+	bitmap := existenceBitmap(len(exists), func(i int) bool { return exists[i] })
+	require.Equal(t, []byte{0x9, 0x1}, bitmap)
+	require.Empty(t, existenceBitmap(0, nil))
+}
@@ -0,0 +1,179 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgryski/go-farm"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// idempotencyTTL is how long the result of a mutation is kept for the retries with its
+// idempotency key.
+const idempotencyTTL = 10 * time.Minute
+
+// maxIdempotencyKeyLen is the maximum length of an idempotency key.
+const maxIdempotencyKeyLen = 256
+
+type idempotentMutation struct {
+	// fp is the fingerprint of the mutation and of the user who sent it.
+	fp       uint64
+	done     chan struct{}
+	resp     *api.Assigned
+	labels   *query.UidLabels
+	err      error
+	finished time.Time
+}
+
+var idempotentMutations = struct {
+	sync.Mutex
+	m map[string]*idempotentMutation
+}{m: make(map[string]*idempotentMutation)}
+
+// idempotencyKey returns the idempotency-key metadata of the request, if any.
+func idempotencyKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	vals := md.Get("idempotency-key")
+	if len(vals) == 0 {
+		return ""
+	}
+	return vals[0]
+}
+
+// mutationFingerprint returns the fingerprint of the mutation and of the access JWT it was sent
+// with, so that a key can't be reused for another mutation, nor by another user.
+func mutationFingerprint(ctx context.Context, mu *api.Mutation) (uint64, error) {
+	data, err := mu.Marshal()
+	if err != nil {
+		return 0, err
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		for _, jwt := range md.Get("accessJwt") {
+			data = append(data, jwt...)
+		}
+	}
+	return farm.Fingerprint64(data), nil
+}
+
+// mutateOnce applies the mutation unless a mutation with the same idempotency key was applied
+// recently, in which case it returns the result of that mutation instead. Mutations which fail
+// aren't remembered, so that they can be retried.
+func (s *Server) mutateOnce(ctx context.Context, mu *api.Mutation, labels *query.UidLabels,
+	ticket *string) (*api.Assigned, error) {
+
+	key := idempotencyKey(ctx)
+	if key == "" {
+		return s.doMutate(ctx, mu, true, labels, ticket)
+	}
+	if len(key) > maxIdempotencyKeyLen {
+		return &api.Assigned{}, x.WithCode(errors.Errorf(
+			"Idempotency key is longer than %d bytes", maxIdempotencyKeyLen), x.CodeInvalidQuery)
+	}
+	if ticket != nil {
+		return &api.Assigned{}, x.WithCode(errors.Errorf(
+			"Idempotency keys can't be used with async mutations"), x.CodeInvalidQuery)
+	}
+	if !mu.CommitNow {
+		return &api.Assigned{}, x.WithCode(errors.Errorf(
+			"Mutations with an idempotency key must be committed immediately"), x.CodeInvalidQuery)
+	}
+	fp, err := mutationFingerprint(ctx, mu)
+	if err != nil {
+		return &api.Assigned{}, err
+	}
+	return applyOnce(ctx, key, fp, labels, func(labels *query.UidLabels) (*api.Assigned, error) {
+		return s.doMutate(ctx, mu, true, labels, nil)
+	})
+}
+
+// applyOnce calls apply, or returns the result of the call for the same key and fingerprint.
+func applyOnce(ctx context.Context, key string, fp uint64, labels *query.UidLabels,
+	apply func(labels *query.UidLabels) (*api.Assigned, error)) (*api.Assigned, error) {
+
+	var im *idempotentMutation
+	for {
+		var ok bool
+		idempotentMutations.Lock()
+		im, ok = idempotentMutations.m[key]
+		if !ok {
+			im = &idempotentMutation{fp: fp, done: make(chan struct{})}
+			addIdempotentMutation(key, im)
+			idempotentMutations.Unlock()
+			break
+		}
+		idempotentMutations.Unlock()
+
+		if im.fp != fp {
+			return &api.Assigned{}, x.WithCode(errors.Errorf(
+				"Idempotency key %q was used for another mutation", key), x.CodeInvalidQuery)
+		}
+		// The mutation may still be running, for a client which retried too early.
+		select {
+		case <-im.done:
+		case <-ctx.Done():
+			return &api.Assigned{}, ctx.Err()
+		}
+		if im.err == nil {
+			if labels != nil {
+				*labels = *im.labels
+			}
+			return im.resp, nil
+		}
+		// The mutation failed and was forgotten, try to apply it again.
+	}
+
+	var ml query.UidLabels
+	resp, err := apply(&ml)
+	if labels != nil {
+		*labels = ml
+	}
+
+	idempotentMutations.Lock()
+	im.resp, im.labels, im.err, im.finished = resp, &ml, err, time.Now()
+	if err != nil {
+		delete(idempotentMutations.m, key)
+	}
+	idempotentMutations.Unlock()
+	close(im.done)
+	return resp, err
+}
+
+// addIdempotentMutation registers the mutation under its key, and drops the results which
+// expired. It must be called with the lock held.
+func addIdempotentMutation(key string, im *idempotentMutation) {
+	for other, om := range idempotentMutations.m {
+		select {
+		case <-om.done:
+			if time.Since(om.finished) > idempotencyTTL {
+				delete(idempotentMutations.m, other)
+			}
+		default:
+		}
+	}
+	idempotentMutations.m[key] = im
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dgraph-io/dgraph/query"
+)
+
+func TestIdempotencyKey(t *testing.T) {
+	require.Equal(t, "", idempotencyKey(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("idempotency-key", "msg-1"))
+	require.Equal(t, "msg-1", idempotencyKey(ctx))
+
+	mu := &api.Mutation{SetNquads: []byte(`_:a <name> "A" .`), CommitNow: true}
+	fp, err := mutationFingerprint(ctx, mu)
+	require.NoError(t, err)
+	other, err := mutationFingerprint(metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("idempotency-key", "msg-1", "accessJwt", "jwt")), mu)
+	require.NoError(t, err)
+	require.NotEqual(t, fp, other)
+
+	_, err = (&Server{}).mutateOnce(ctx, &api.Mutation{}, nil, nil)
+	require.Error(t, err)
+	_, err = (&Server{}).mutateOnce(ctx, mu, nil, new(string))
+	require.Error(t, err)
+}
+
+func TestApplyOnce(t *testing.T) {
+	ctx := context.Background()
+	var calls int
+	apply := func(labels *query.UidLabels) (*api.Assigned, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("failed")
+		}
+		labels.BlankNodes = map[string]string{"a": "0x1"}
+		return &api.Assigned{Uids: map[string]string{"a": "0x1"}}, nil
+	}
+
+	// Failed mutations aren't remembered.
+	_, err := applyOnce(ctx, "apply-once", 1, nil, apply)
+	require.Error(t, err)
+	resp, err := applyOnce(ctx, "apply-once", 1, nil, apply)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+
+	var labels query.UidLabels
+	replayed, err := applyOnce(ctx, "apply-once", 1, &labels, apply)
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, resp, replayed)
+	require.Equal(t, "0x1", labels.BlankNodes["a"])
+
+	// The key can't be used for another mutation.
+	_, err = applyOnce(ctx, "apply-once", 2, nil, apply)
+	require.Error(t, err)
+	require.Equal(t, 2, calls)
+}
@@ -0,0 +1,301 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgo/y"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	nqjson "github.com/dgraph-io/dgraph/chunker/json"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+const (
+	// importConcurrency is the number of transactions of an import committed at once.
+	importConcurrency = 8
+	// defaultImportBatchSize is the number of N-Quads per transaction if the client doesn't
+	// set it.
+	defaultImportBatchSize = 1000
+)
+
+// importer turns the chunks of an import stream into batches of N-Quads, whose blank nodes
+// are replaced with uids which are the same for the whole stream.
+type importer struct {
+	ctx       context.Context
+	json      bool
+	batchSize int
+	// assign leases n uids and returns the first one.
+	assign func(ctx context.Context, n uint64) (uint64, error)
+
+	rest    []byte // The RDF data after the last complete line.
+	chunks  int
+	uids    map[string]string
+	batch   []*api.NQuad
+	batches chan []*api.NQuad
+
+	nquads, txns, aborts uint64
+}
+
+func newImporter(ctx context.Context, req *pb.ImportRequest) (*importer, error) {
+	im := &importer{
+		ctx:       ctx,
+		batchSize: int(req.BatchSize),
+		assign: func(ctx context.Context, n uint64) (uint64, error) {
+			ids, err := worker.AssignUidsOverNetwork(ctx, &pb.Num{Val: n})
+			if err != nil {
+				return 0, err
+			}
+			return ids.StartId, nil
+		},
+		uids:    make(map[string]string),
+		batches: make(chan []*api.NQuad, importConcurrency),
+	}
+	switch strings.ToLower(req.Format) {
+	case "rdf":
+	case "json":
+		im.json = true
+	default:
+		return nil, errors.Errorf("Invalid import format %q: expected rdf or json", req.Format)
+	}
+	if im.batchSize == 0 {
+		im.batchSize = defaultImportBatchSize
+	}
+	return im, nil
+}
+
+// add parses the chunk of data and sends the full batches of N-Quads.
+func (im *importer) add(data []byte) error {
+	im.chunks++
+	if im.json {
+		return im.parse(data)
+	}
+	data = append(im.rest, data...)
+	i := bytes.LastIndexByte(data, '\n')
+	im.rest = append([]byte{}, data[i+1:]...)
+	return im.parse(data[:i+1])
+}
+
+// finish parses the remaining data and sends the last batch.
+func (im *importer) finish() error {
+	if err := im.parse(im.rest); err != nil {
+		return err
+	}
+	im.rest = nil
+	if len(im.batch) == 0 {
+		return nil
+	}
+	return im.send()
+}
+
+func (im *importer) parse(data []byte) error {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil
+	}
+	var nqs []*api.NQuad
+	var err error
+	if im.json {
+		nqs, err = nqjson.Parse(data, nqjson.SetNquads)
+	} else {
+		nqs, err = parseNQuads(data)
+	}
+	if err != nil {
+		return errors.Wrapf(err, "while parsing chunk %d", im.chunks)
+	}
+
+	for _, nq := range nqs {
+		if im.json {
+			// The blank nodes generated for the objects without uid are only unique within
+			// a chunk.
+			nq.Subject = chunkBlankNode(nq.Subject, im.chunks)
+			nq.ObjectId = chunkBlankNode(nq.ObjectId, im.chunks)
+		}
+		im.batch = append(im.batch, nq)
+		if len(im.batch) >= im.batchSize {
+			if err := im.send(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func chunkBlankNode(id string, chunk int) string {
+	if !strings.HasPrefix(id, "_:blank-") {
+		return id
+	}
+	return fmt.Sprintf("%s.%d", id, chunk)
+}
+
+// send replaces the blank nodes of the batch with their uids, and waits for the batch to be
+// picked up, which pushes back on the client while all the transactions are busy.
+func (im *importer) send() error {
+	var missing []string
+	for _, nq := range im.batch {
+		for _, id := range []string{nq.Subject, nq.ObjectId} {
+			if _, ok := im.uids[id]; !ok && strings.HasPrefix(id, "_:") {
+				im.uids[id] = ""
+				missing = append(missing, id)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		start, err := im.assign(im.ctx, uint64(len(missing)))
+		if err != nil {
+			return err
+		}
+		for i, id := range missing {
+			im.uids[id] = fmt.Sprintf("%#x", start+uint64(i))
+		}
+	}
+	for _, nq := range im.batch {
+		if uid, ok := im.uids[nq.Subject]; ok {
+			nq.Subject = uid
+		}
+		if uid, ok := im.uids[nq.ObjectId]; ok {
+			nq.ObjectId = uid
+		}
+	}
+
+	select {
+	case im.batches <- im.batch:
+	case <-im.ctx.Done():
+		return im.ctx.Err()
+	}
+	im.batch = nil
+	return nil
+}
+
+func isAbortedErr(err error) bool {
+	cause := errors.Cause(err)
+	return cause == y.ErrAborted || cause == y.ErrConflict || status.Code(err) == codes.Aborted
+}
+
+// commitImportBatch commits the batch in its own transaction, which is retried if it's aborted.
+func (s *Server) commitImportBatch(ctx context.Context, im *importer, nqs []*api.NQuad) error {
+	err := s.commitNQuads(ctx, nqs, true, func() { atomic.AddUint64(&im.aborts, 1) })
+	if err != nil {
+		return err
+	}
+	atomic.AddUint64(&im.txns, 1)
+	atomic.AddUint64(&im.nquads, uint64(len(nqs)))
+	return nil
+}
+
+// commitNQuads sets the N-Quads in their own transaction, which is retried if it's aborted.
+// aborted is called after each abort.
+func (s *Server) commitNQuads(ctx context.Context, nqs []*api.NQuad, authorize bool,
+	aborted func()) error {
+	backoff := 10 * time.Millisecond
+	for i := 0; ; i++ {
+		_, err := s.doMutate(ctx, &api.Mutation{Set: nqs, CommitNow: true}, authorize, nil, nil)
+		if err == nil {
+			return nil
+		}
+		if !isAbortedErr(err) ||
+			(x.WorkerConfig.MaxRetries >= 0 && i >= x.WorkerConfig.MaxRetries) {
+			return err
+		}
+		aborted()
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		if backoff < time.Second {
+			backoff *= 2
+		}
+	}
+}
+
+// Import loads the RDF or JSON data streamed by the client, like the live loader. The N-Quads
+// are committed in transactions of the batch size, which are retried when they're aborted.
+// The blank nodes get the same uid in the whole stream.
+func (s *Server) Import(stream pb.Import_ImportServer) error {
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+
+	req, err := stream.Recv()
+	if err == io.EOF {
+		return stream.SendAndClose(&pb.ImportResponse{})
+	}
+	if err != nil {
+		return err
+	}
+	im, err := newImporter(ctx, req)
+	if err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	errCh := make(chan error, importConcurrency)
+	for i := 0; i < importConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for nqs := range im.batches {
+				if err := s.commitImportBatch(ctx, im, nqs); err != nil {
+					errCh <- err
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	err = func() error {
+		for {
+			if err := im.add(req.Data); err != nil {
+				return err
+			}
+			if req, err = stream.Recv(); err == io.EOF {
+				return im.finish()
+			} else if err != nil {
+				return err
+			}
+		}
+	}()
+	close(im.batches)
+	wg.Wait()
+
+	// The error of a transaction is the cause of the other errors.
+	select {
+	case err = <-errCh:
+	default:
+	}
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(&pb.ImportResponse{
+		Nquads: atomic.LoadUint64(&im.nquads),
+		Txns:   atomic.LoadUint64(&im.txns),
+		Aborts: atomic.LoadUint64(&im.aborts),
+	})
+}
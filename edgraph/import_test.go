@@ -0,0 +1,97 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+func newTestImporter(t *testing.T, format string, batchSize uint32) *importer {
+	im, err := newImporter(context.Background(),
+		&pb.ImportRequest{Format: format, BatchSize: batchSize})
+	require.NoError(t, err)
+	next := uint64(100)
+	im.assign = func(ctx context.Context, n uint64) (uint64, error) {
+		start := next
+		next += n
+		return start, nil
+	}
+	im.batches = make(chan []*api.NQuad, 10)
+	return im
+}
+
+func TestImporterRDF(t *testing.T) {
+	im := newTestImporter(t, "rdf", 2)
+
+	// The chunks can end in the middle of a line.
+	require.NoError(t, im.add([]byte("_:a <name> \"Alice\" .\n_:a <friend> _:")))
+	require.NoError(t, im.add([]byte("b .\n_:b <name> \"Bob\" .\n<0x1> <fr")))
+	require.NoError(t, im.add([]byte("iend> _:a .")))
+	require.NoError(t, im.finish())
+	close(im.batches)
+
+	var nqs []*api.NQuad
+	for batch := range im.batches {
+		require.True(t, len(batch) <= 2)
+		nqs = append(nqs, batch...)
+	}
+	require.Equal(t, 4, len(nqs))
+	// The blank nodes get the same uid in all the batches.
+	require.Equal(t, "0x64", nqs[0].Subject)
+	require.Equal(t, "0x64", nqs[1].Subject)
+	require.Equal(t, "0x65", nqs[1].ObjectId)
+	require.Equal(t, "0x65", nqs[2].Subject)
+	require.Equal(t, "0x1", nqs[3].Subject)
+	require.Equal(t, "0x64", nqs[3].ObjectId)
+
+	require.Error(t, im.add([]byte("invalid rdf\n")))
+}
+
+func TestImporterJSON(t *testing.T) {
+	im := newTestImporter(t, "json", 10)
+
+	// The objects without uid are new nodes in every chunk.
+	require.NoError(t, im.add([]byte(`[{"name": "Alice"}, {"uid": "_:bob", "name": "Bob"}]`)))
+	require.NoError(t, im.add([]byte(`{"name": "Carol", "friend": {"uid": "_:bob"}}`)))
+	require.NoError(t, im.finish())
+	close(im.batches)
+
+	subjects := make(map[string]string)
+	for batch := range im.batches {
+		for _, nq := range batch {
+			if nq.Predicate == "name" {
+				subjects[nq.ObjectValue.GetStrVal()] = nq.Subject
+			} else {
+				require.Equal(t, "friend", nq.Predicate)
+				require.Equal(t, subjects["Bob"], nq.ObjectId)
+			}
+		}
+	}
+	require.Equal(t, 3, len(subjects))
+	require.NotEqual(t, subjects["Alice"], subjects["Carol"])
+}
+
+func TestImporterFormat(t *testing.T) {
+	_, err := newImporter(context.Background(), &pb.ImportRequest{Format: "csv"})
+	require.Error(t, err)
+}
@@ -0,0 +1,172 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+const jsonSchemaVersion = "http://json-schema.org/draft-07/schema#"
+
+// uidSchema is the schema of the uid of a node in the JSON results.
+var uidSchema = map[string]interface{}{
+	"type":    "string",
+	"pattern": "^0x[0-9a-f]+$",
+}
+
+// scalarSchema returns the schema of the JSON values of a scalar type.
+func scalarSchema(tid types.TypeID) map[string]interface{} {
+	switch tid {
+	case types.IntID:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case types.FloatID:
+		return map[string]interface{}{"type": "number", "format": "double"}
+	case types.BoolID:
+		return map[string]interface{}{"type": "boolean"}
+	case types.DateTimeID:
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	case types.GeoID:
+		return map[string]interface{}{"type": "object", "description": "GeoJSON geometry"}
+	case types.BinaryID:
+		return map[string]interface{}{"type": "string", "format": "byte"}
+	case types.PasswordID:
+		// Passwords can be set, but are never returned by the queries.
+		return map[string]interface{}{"type": "string", "writeOnly": true}
+	case types.UidID:
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"uid": uidSchema},
+		}
+	default:
+		return map[string]interface{}{"type": "string"}
+	}
+}
+
+// typesSchema returns the schemas of the types, as JSON Schema definitions or as the schemas of
+// OpenAPI components. The edges to another type refer to its schema, which must be among them.
+func typesSchema(typs []*pb.TypeUpdate, openAPI bool) map[string]interface{} {
+	refPrefix := "#/definitions/"
+	if openAPI {
+		refPrefix = "#/components/schemas/"
+	}
+	known := make(map[string]bool, len(typs))
+	for _, typ := range typs {
+		known[typ.TypeName] = true
+	}
+
+	defs := make(map[string]interface{}, len(typs))
+	for _, typ := range typs {
+		props := map[string]interface{}{
+			"uid": uidSchema,
+			"dgraph.type": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		}
+		var fields []string
+		for _, field := range typ.Fields {
+			var prop map[string]interface{}
+			if field.ValueType == pb.Posting_OBJECT && known[field.ObjectTypeName] {
+				prop = map[string]interface{}{"$ref": refPrefix + field.ObjectTypeName}
+			} else if field.ValueType == pb.Posting_OBJECT {
+				prop = scalarSchema(types.UidID)
+			} else {
+				prop = scalarSchema(types.TypeID(field.ValueType))
+			}
+			if field.List {
+				prop = map[string]interface{}{"type": "array", "items": prop}
+			}
+			props[field.Predicate] = prop
+			if required(field) {
+				fields = append(fields, field.Predicate)
+			}
+		}
+
+		def := map[string]interface{}{
+			"type":       "object",
+			"properties": props,
+		}
+		if len(fields) > 0 {
+			def["required"] = fields
+		}
+		if typ.Strict {
+			// The nodes of a strict type can only have the fields of their types.
+			def["additionalProperties"] = false
+		}
+		defs[typ.TypeName] = def
+	}
+
+	if openAPI {
+		return map[string]interface{}{
+			"components": map[string]interface{}{"schemas": defs},
+		}
+	}
+	return map[string]interface{}{
+		"$schema":     jsonSchemaVersion,
+		"definitions": defs,
+	}
+}
+
+// TypesSchema returns the schemas of the JSON objects of the types, as JSON Schema definitions
+// or as the schemas of OpenAPI components, so that clients can generate their models and
+// validators from them. All the types are returned if names is empty, and the types their
+// fields refer to are added to the ones asked for.
+func (s *Server) TypesSchema(ctx context.Context, names []string, openAPI bool) (
+	map[string]interface{}, error) {
+
+	if err := x.HealthCheck(); err != nil {
+		return nil, err
+	}
+	typs, err := worker.GetTypes(ctx, &pb.SchemaRequest{Types: names})
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]bool, len(typs))
+	for _, typ := range typs {
+		found[typ.TypeName] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			return nil, errors.Errorf("Type %s doesn't exist", name)
+		}
+	}
+
+	// The types the fields refer to are added until all the references are known.
+	for i := 0; i < len(typs); i++ {
+		var refs []string
+		for _, field := range typs[i].Fields {
+			if field.ValueType == pb.Posting_OBJECT && !found[field.ObjectTypeName] {
+				found[field.ObjectTypeName] = true
+				refs = append(refs, field.ObjectTypeName)
+			}
+		}
+		if len(refs) == 0 {
+			continue
+		}
+		more, err := worker.GetTypes(ctx, &pb.SchemaRequest{Types: refs})
+		if err != nil {
+			return nil, err
+		}
+		typs = append(typs, more...)
+	}
+	return typesSchema(typs, openAPI), nil
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/schema"
+)
+
+func TestTypesSchema(t *testing.T) {
+	result, err := schema.Parse(`
+		type Person @strict {
+			name: string!
+			age: int
+			score: float
+			born: datetime
+			home: geo
+			secret: password
+			friends: [Person]
+			pets: [Animal!]!
+			tags: [string]
+			links: [uid]
+		}
+		type Animal {
+			name: string
+			owner: Owner
+		}
+	`)
+	require.NoError(t, err)
+
+	js, err := json.Marshal(typesSchema(result.Types, false))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"definitions": {
+			"Person": {
+				"type": "object",
+				"properties": {
+					"uid": {"type": "string", "pattern": "^0x[0-9a-f]+$"},
+					"dgraph.type": {"type": "array", "items": {"type": "string"}},
+					"name": {"type": "string"},
+					"age": {"type": "integer", "format": "int64"},
+					"score": {"type": "number", "format": "double"},
+					"born": {"type": "string", "format": "date-time"},
+					"home": {"type": "object", "description": "GeoJSON geometry"},
+					"secret": {"type": "string", "writeOnly": true},
+					"friends": {"type": "array", "items": {"$ref": "#/definitions/Person"}},
+					"pets": {"type": "array", "items": {"$ref": "#/definitions/Animal"}},
+					"tags": {"type": "array", "items": {"type": "string"}},
+					"links": {"type": "array", "items": {"type": "object",
+						"properties": {"uid": {"type": "string", "pattern": "^0x[0-9a-f]+$"}}}}
+				},
+				"required": ["name", "pets"],
+				"additionalProperties": false
+			},
+			"Animal": {
+				"type": "object",
+				"properties": {
+					"uid": {"type": "string", "pattern": "^0x[0-9a-f]+$"},
+					"dgraph.type": {"type": "array", "items": {"type": "string"}},
+					"name": {"type": "string"},
+					"owner": {"type": "object",
+						"properties": {"uid": {"type": "string", "pattern": "^0x[0-9a-f]+$"}}}
+				}
+			}
+		}
+	}`, string(js))
+
+	js, err = json.Marshal(typesSchema(result.Types[1:], true))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"components": {
+			"schemas": {
+				"Animal": {
+					"type": "object",
+					"properties": {
+						"uid": {"type": "string", "pattern": "^0x[0-9a-f]+$"},
+						"dgraph.type": {"type": "array", "items": {"type": "string"}},
+						"name": {"type": "string"},
+						"owner": {"type": "object",
+							"properties": {"uid": {"type": "string", "pattern": "^0x[0-9a-f]+$"}}}
+					}
+				}
+			}
+		}
+	}`, string(js))
+
+	// The references point to the components in OpenAPI.
+	js, err = json.Marshal(typesSchema(result.Types, true))
+	require.NoError(t, err)
+	require.Contains(t, string(js), `"friends":{"items":{"$ref":"#/components/schemas/Person"}`)
+}
@@ -0,0 +1,183 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"container/list"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/x"
+	farm "github.com/dgryski/go-farm"
+)
+
+// parsedQuery is a parsed query kept by the parse cache. The result is shared by all the
+// requests using it, so it must not be modified.
+type parsedQuery struct {
+	fingerprint uint64
+	key         string
+	schema      uint64
+	result      gql.Result
+}
+
+// parseCache keeps the most recently parsed queries, up to x.Config.QueryCacheSize, so that
+// hot queries skip the lexer and the parser. Variables are substituted while parsing, so they
+// are part of the key.
+type parseCache struct {
+	sync.Mutex
+	lru     *list.List
+	entries map[uint64]*list.Element
+}
+
+var pCache = &parseCache{
+	lru:     list.New(),
+	entries: make(map[uint64]*list.Element),
+}
+
+// normalizeQuery drops the comments of the query and collapses its whitespace outside of
+// strings, so that queries only differing in their formatting share the same key.
+func normalizeQuery(q string) string {
+	var b strings.Builder
+	b.Grow(len(q))
+	space := false
+	for i := 0; i < len(q); i++ {
+		switch c := q[i]; c {
+		case ' ', '\t', '\r', '\n':
+			space = true
+		case '#':
+			for i < len(q) && q[i] != '\n' {
+				i++
+			}
+			space = true
+		default:
+			if space && b.Len() > 0 {
+				b.WriteByte(' ')
+			}
+			space = false
+			end := i + 1
+			if c == '"' {
+				for end < len(q) && q[end] != '"' {
+					if q[end] == '\\' {
+						end++
+					}
+					end++
+				}
+				end++
+			}
+			if end > len(q) {
+				end = len(q)
+			}
+			b.WriteString(q[i:end])
+			i = end - 1
+		}
+	}
+	return b.String()
+}
+
+// parseCacheKey returns the key of the request in the parse cache.
+func parseCacheKey(r gql.Request) string {
+	key := normalizeQuery(r.Str)
+	if len(r.Variables) == 0 {
+		return key
+	}
+	names := make([]string, 0, len(r.Variables))
+	for name := range r.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(key)
+	for _, name := range names {
+		b.WriteByte('\n')
+		b.WriteString(strconv.Quote(name))
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(r.Variables[name]))
+	}
+	return b.String()
+}
+
+func schemaVersion() uint64 {
+	if schema.State() == nil {
+		return 0
+	}
+	return schema.State().Version()
+}
+
+// parseQuery parses the request, reusing the result of a previous parse of the same query
+// with the same variables when the schema didn't change since.
+func parseQuery(r gql.Request) (gql.Result, error) {
+	if x.Config.QueryCacheSize <= 0 {
+		return gql.Parse(r)
+	}
+	key := parseCacheKey(r)
+	fp := farm.Fingerprint64([]byte(key))
+	version := schemaVersion()
+
+	pCache.Lock()
+	if e, ok := pCache.entries[fp]; ok {
+		pq := e.Value.(*parsedQuery)
+		if pq.key == key && pq.schema == version {
+			pCache.lru.MoveToFront(e)
+			pCache.Unlock()
+			return pq.result, nil
+		}
+		pCache.lru.Remove(e)
+		delete(pCache.entries, fp)
+	}
+	pCache.Unlock()
+
+	res, err := gql.Parse(r)
+	if err != nil || res.Schema != nil {
+		// Schema queries are cheap to parse and not worth a slot.
+		return res, err
+	}
+
+	pCache.Lock()
+	defer pCache.Unlock()
+	if _, ok := pCache.entries[fp]; ok {
+		// Another request parsed the same query meanwhile.
+		return res, nil
+	}
+	pq := &parsedQuery{fingerprint: fp, key: key, schema: version, result: res}
+	pCache.entries[fp] = pCache.lru.PushFront(pq)
+	for pCache.lru.Len() > x.Config.QueryCacheSize {
+		oldest := pCache.lru.Back()
+		pCache.lru.Remove(oldest)
+		delete(pCache.entries, oldest.Value.(*parsedQuery).fingerprint)
+	}
+	return res, nil
+}
+
+// clearParseCache removes all the parsed queries.
+func clearParseCache() {
+	pCache.Lock()
+	defer pCache.Unlock()
+	pCache.lru.Init()
+	pCache.entries = make(map[uint64]*list.Element)
+}
+
+// ParseCacheLen returns the number of parsed queries in the parse cache.
+func ParseCacheLen() int {
+	pCache.Lock()
+	defer pCache.Unlock()
+	return pCache.lru.Len()
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeQuery(t *testing.T) {
+	q := "{\n  q(func: eq(name, \"a  b\")) {   # two  spaces\n\t\tname\n  }\n}\n"
+	require.Equal(t, `{ q(func: eq(name, "a  b")) { name } }`, normalizeQuery(q))
+	require.Equal(t, `"a \" # b" c`, normalizeQuery(`"a \" # b"   c`))
+}
+
+func TestParseCache(t *testing.T) {
+	defer func(n int) { x.Config.QueryCacheSize = n }(x.Config.QueryCacheSize)
+	defer clearParseCache()
+	x.Config.QueryCacheSize = 2
+
+	r1 := gql.Request{Str: `{ q(func: uid(1)) { name } }`}
+	res1, err := parseQuery(r1)
+	require.NoError(t, err)
+	cached, err := parseQuery(gql.Request{Str: "{\n  q(func: uid(1)) {\n    name\n  }\n}"})
+	require.NoError(t, err)
+	require.True(t, res1.Query[0] == cached.Query[0])
+
+	// Variables are part of the key.
+	r2 := gql.Request{
+		Str:       `query q($a: string) { q(func: uid($a)) { name } }`,
+		Variables: map[string]string{"$a": "0x2"},
+	}
+	res2, err := parseQuery(r2)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2}, res2.Query[0].UID)
+	r2.Variables = map[string]string{"$a": "0x3"}
+	res3, err := parseQuery(r2)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3}, res3.Query[0].UID)
+
+	// r1 is now the least recently used query, so it's the one dropped.
+	require.Equal(t, 2, pCache.lru.Len())
+	again, err := parseQuery(r1)
+	require.NoError(t, err)
+	require.False(t, res1.Query[0] == again.Query[0])
+
+	x.Config.QueryCacheSize = 0
+	again, err = parseQuery(r1)
+	require.NoError(t, err)
+	require.False(t, res1.Query[0] == again.Query[0])
+}
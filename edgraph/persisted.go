@@ -0,0 +1,121 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+)
+
+// PersistedQuery is a query stored by its SHA-256 hash, so that clients can send the hash
+// instead of the query text.
+type PersistedQuery struct {
+	Hash     string    `json:"hash"`
+	Query    string    `json:"query"`
+	Hits     uint64    `json:"hits"`
+	Created  time.Time `json:"created"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// persistedQueries keeps the most recently used queries, up to x.Config.PersistedQueries.
+type persistedQueries struct {
+	sync.Mutex
+	lru     *list.List
+	entries map[string]*list.Element
+}
+
+var pqCache = &persistedQueries{
+	lru:     list.New(),
+	entries: make(map[string]*list.Element),
+}
+
+// QueryHash returns the hex encoded SHA-256 hash of the query, as used by PersistQuery.
+func QueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// PersistQuery stores the query under its hash, which must be the SHA-256 hash of the query.
+// The least recently used query is dropped once x.Config.PersistedQueries are stored.
+func PersistQuery(hash, query string) error {
+	if x.Config.PersistedQueries <= 0 {
+		return errors.New("Persisted queries are disabled")
+	}
+	hash = strings.ToLower(hash)
+	if QueryHash(query) != hash {
+		return errors.Errorf("Hash %s doesn't match the SHA-256 hash of the query", hash)
+	}
+	pqCache.Lock()
+	defer pqCache.Unlock()
+	if e, ok := pqCache.entries[hash]; ok {
+		pqCache.lru.MoveToFront(e)
+		return nil
+	}
+	now := time.Now()
+	pq := &PersistedQuery{Hash: hash, Query: query, Created: now, LastUsed: now}
+	pqCache.entries[hash] = pqCache.lru.PushFront(pq)
+	for pqCache.lru.Len() > x.Config.PersistedQueries {
+		oldest := pqCache.lru.Back()
+		pqCache.lru.Remove(oldest)
+		delete(pqCache.entries, oldest.Value.(*PersistedQuery).Hash)
+	}
+	return nil
+}
+
+// LookupPersistedQuery returns the query stored under the hash, if any.
+func LookupPersistedQuery(hash string) (string, bool) {
+	pqCache.Lock()
+	defer pqCache.Unlock()
+	e, ok := pqCache.entries[strings.ToLower(hash)]
+	if !ok {
+		return "", false
+	}
+	pqCache.lru.MoveToFront(e)
+	pq := e.Value.(*PersistedQuery)
+	pq.Hits++
+	pq.LastUsed = time.Now()
+	return pq.Query, true
+}
+
+// PersistedQueries returns a copy of the stored queries, most used first.
+func PersistedQueries() []PersistedQuery {
+	pqCache.Lock()
+	out := make([]PersistedQuery, 0, pqCache.lru.Len())
+	for e := pqCache.lru.Front(); e != nil; e = e.Next() {
+		out = append(out, *e.Value.(*PersistedQuery))
+	}
+	pqCache.Unlock()
+
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Hits > out[j].Hits })
+	return out
+}
+
+// ClearPersistedQueries removes all the stored queries.
+func ClearPersistedQueries() {
+	pqCache.Lock()
+	defer pqCache.Unlock()
+	pqCache.lru.Init()
+	pqCache.entries = make(map[string]*list.Element)
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistQuery(t *testing.T) {
+	defer func(n int) { x.Config.PersistedQueries = n }(x.Config.PersistedQueries)
+	defer ClearPersistedQueries()
+	x.Config.PersistedQueries = 2
+
+	q1, q2, q3 := `{ q(func: uid(1)) { name } }`, `{ q(func: uid(2)) { name } }`,
+		`{ q(func: uid(3)) { name } }`
+	require.Error(t, PersistQuery(QueryHash(q2), q1))
+	require.NoError(t, PersistQuery(QueryHash(q1), q1))
+	require.NoError(t, PersistQuery(QueryHash(q2), q2))
+
+	q, ok := LookupPersistedQuery(QueryHash(q1))
+	require.True(t, ok)
+	require.Equal(t, q1, q)
+
+	// q2 is now the least recently used query, so it's the one dropped.
+	require.NoError(t, PersistQuery(QueryHash(q3), q3))
+	_, ok = LookupPersistedQuery(QueryHash(q2))
+	require.False(t, ok)
+
+	pqs := PersistedQueries()
+	require.Len(t, pqs, 2)
+	require.Equal(t, QueryHash(q1), pqs[0].Hash)
+	require.Equal(t, uint64(1), pqs[0].Hits)
+	require.Equal(t, QueryHash(q3), pqs[1].Hash)
+
+	ClearPersistedQueries()
+	require.Empty(t, PersistedQueries())
+}
+
+func TestPersistQueryDisabled(t *testing.T) {
+	defer func(n int) { x.Config.PersistedQueries = n }(x.Config.PersistedQueries)
+	x.Config.PersistedQueries = 0
+
+	q := `{ q(func: uid(1)) { name } }`
+	require.EqualError(t, PersistQuery(QueryHash(q), q), "Persisted queries are disabled")
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/pkg/errors"
+)
+
+// fillSequences replaces every next("name") object in the set nquads of the mutation with the
+// next value of the sequence, as an int. The values of a sequence are taken in a single call
+// to next, in the order the nquads appear in the mutation.
+func fillSequences(gmu *gql.Mutation, next func(name string, n int) ([]uint64, error)) error {
+	for _, nq := range gmu.Del {
+		if gql.IsNextFunc(nq.ObjectId) {
+			return errors.Errorf("next() can't be used in a delete mutation, found for predicate %s",
+				nq.Predicate)
+		}
+	}
+
+	var names []string
+	bySeq := make(map[string][]*api.NQuad)
+	for _, nq := range gmu.Set {
+		if !gql.IsNextFunc(nq.ObjectId) {
+			continue
+		}
+		name, err := gql.SequenceName(nq.ObjectId)
+		if err != nil {
+			return err
+		}
+		if _, ok := bySeq[name]; !ok {
+			names = append(names, name)
+		}
+		bySeq[name] = append(bySeq[name], nq)
+	}
+
+	for _, name := range names {
+		nqs := bySeq[name]
+		vals, err := next(name, len(nqs))
+		if err != nil {
+			return errors.Wrapf(err, "while getting the next values of sequence %q", name)
+		}
+		for i, nq := range nqs {
+			nq.ObjectId = ""
+			nq.ObjectValue = &api.Value{Val: &api.Value_IntVal{IntVal: int64(vals[i])}}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+)
+
+func TestFillSequences(t *testing.T) {
+	counters := make(map[string]uint64)
+	next := func(name string, n int) ([]uint64, error) {
+		var vals []uint64
+		for i := 0; i < n; i++ {
+			counters[name]++
+			vals = append(vals, counters[name])
+		}
+		return vals, nil
+	}
+
+	gmu := &gql.Mutation{Set: []*api.NQuad{
+		{Subject: "_:a", Predicate: "number", ObjectId: `next("orders")`},
+		{Subject: "_:a", Predicate: "friend", ObjectId: "_:b"},
+		{Subject: "_:b", Predicate: "number", ObjectId: `next("orders")`},
+		{Subject: "_:b", Predicate: "ticket", ObjectId: `next("tickets")`},
+	}}
+	require.NoError(t, fillSequences(gmu, next))
+	require.Equal(t, int64(1), gmu.Set[0].ObjectValue.GetIntVal())
+	require.Equal(t, "", gmu.Set[0].ObjectId)
+	require.Equal(t, "_:b", gmu.Set[1].ObjectId)
+	require.Equal(t, int64(2), gmu.Set[2].ObjectValue.GetIntVal())
+	require.Equal(t, int64(1), gmu.Set[3].ObjectValue.GetIntVal())
+
+	gmu = &gql.Mutation{Del: []*api.NQuad{
+		{Subject: "_:a", Predicate: "number", ObjectId: `next("orders")`},
+	}}
+	require.Error(t, fillSequences(gmu, next))
+}
@@ -49,6 +49,7 @@ import (
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
@@ -62,6 +63,9 @@ import (
 const (
 	methodMutate = "Server.Mutate"
 	methodQuery  = "Server.Query"
+
+	// upsertBatchSize is the maximum number of edges proposed at once for an upsert.
+	upsertBatchSize = 10000
 )
 
 // ServerState holds the state of the Dgraph server.
@@ -410,25 +414,79 @@ func (s *Server) Alter(ctx context.Context, op *api.Operation) (*api.Payload, er
 		}
 	}
 
+	if err := checkTypeKeyIndexes(ctx, result); err != nil {
+		return nil, err
+	}
+
 	glog.Infof("Got schema: %+v\n", result)
 	// TODO: Maybe add some checks about the schema.
 	m.Schema = result.Preds
 	m.Types = result.Types
+	m.RunInBackground = runInBackground(ctx)
 	_, err = query.ApplyMutations(ctx, m)
 	return empty, err
 }
 
+// runInBackground returns whether the run_in_background metadata of the request is true, in
+// which case the indices of the schema update are built after Alter returns.
+func runInBackground(ctx context.Context) bool {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return false
+	}
+	vals := md.Get("run_in_background")
+	return len(vals) > 0 && vals[0] == "true"
+}
+
 func annotateStartTs(span *otrace.Span, ts uint64) {
 	span.Annotate([]otrace.Attribute{otrace.Int64Attribute("startTs", int64(ts))}, "")
 }
 
-// Mutate handles requests to perform mutations.
+// Mutate handles requests to perform mutations. If the async metadata is true, the mutation is
+// applied asynchronously and the ticket to await it is sent in the ticket header. If the
+// idempotency-key metadata is set, retries of the mutation with the same key return the result
+// of the mutation instead of applying it again.
 func (s *Server) Mutate(ctx context.Context, mu *api.Mutation) (*api.Assigned, error) {
-	return s.doMutate(ctx, mu, true)
+	if !isAsync(ctx) {
+		return s.mutateOnce(ctx, mu, nil, nil)
+	}
+	var ticket string
+	resp, err := s.mutateOnce(ctx, mu, nil, &ticket)
+	if ticket != "" {
+		if herr := grpc.SetHeader(ctx, metadata.Pairs("ticket", ticket)); herr != nil {
+			glog.Warningf("Unable to send the ticket of an async mutation: %v", herr)
+		}
+	}
+	return resp, err
 }
 
-func (s *Server) doMutate(ctx context.Context, mu *api.Mutation, authorize bool) (
-	resp *api.Assigned, rerr error) {
+// MutateWithLabels is like Mutate, but also returns the uids of the blank nodes and of the
+// upsert query variables used in the mutation.
+func (s *Server) MutateWithLabels(ctx context.Context, mu *api.Mutation) (
+	*api.Assigned, *query.UidLabels, error) {
+
+	labels := &query.UidLabels{}
+	resp, err := s.mutateOnce(ctx, mu, labels, nil)
+	return resp, labels, err
+}
+
+// MutateAsync is like MutateWithLabels, but returns once the mutation is in the Raft logs,
+// before it's applied and committed. The returned ticket is passed to AwaitMutation to get the
+// result of the mutation.
+func (s *Server) MutateAsync(ctx context.Context, mu *api.Mutation) (
+	*api.Assigned, *query.UidLabels, string, error) {
+
+	labels := &query.UidLabels{}
+	var ticket string
+	resp, err := s.mutateOnce(ctx, mu, labels, &ticket)
+	return resp, labels, ticket, err
+}
+
+// doMutate applies the mutation. If labels isn't nil, it's filled with the uids of the
+// blank nodes and variables used in the mutation. If ticket isn't nil, the mutation is applied
+// asynchronously and ticket is set to the ticket of the mutation.
+func (s *Server) doMutate(ctx context.Context, mu *api.Mutation, authorize bool,
+	labels *query.UidLabels, ticket *string) (resp *api.Assigned, rerr error) {
 
 	if ctx.Err() != nil {
 		return nil, ctx.Err()
@@ -481,7 +539,7 @@ func (s *Server) doMutate(ctx context.Context, mu *api.Mutation, authorize bool)
 	startParsingTime := time.Now()
 	gmu, err := parseMutationObject(mu)
 	if err != nil {
-		return resp, err
+		return resp, x.WithCode(err, x.CodeInvalidQuery)
 	}
 	parsingTime += time.Since(startParsingTime)
 
@@ -493,7 +551,12 @@ func (s *Server) doMutate(ctx context.Context, mu *api.Mutation, authorize bool)
 
 	if len(gmu.Set) == 0 && len(gmu.Del) == 0 {
 		span.Annotate(nil, "Empty mutation")
-		return resp, errors.Errorf("Empty mutation")
+		return resp, x.WithCode(errors.Errorf("Empty mutation"), x.CodeInvalidQuery)
+	}
+	if ticket != nil {
+		if err := validateAsync(mu); err != nil {
+			return resp, err
+		}
 	}
 
 	if mu.StartTs == 0 {
@@ -501,25 +564,63 @@ func (s *Server) doMutate(ctx context.Context, mu *api.Mutation, authorize bool)
 	}
 	annotateStartTs(span, mu.StartTs)
 
-	l, err := doQueryInUpsert(ctx, mu, gmu)
+	usedVars := findVars(gmu)
+	l, varToUID, err := doQueryInUpsert(ctx, mu, gmu)
 	if err != nil {
 		return resp, err
 	}
 	parsingTime += l.Parsing
 
+	if err := checkStrictMutation(ctx, gmu); err != nil {
+		return resp, x.WithCode(err, x.CodeInvalidQuery)
+	}
+	if err := resolveXids(ctx, gmu, mu.StartTs); err != nil {
+		return resp, err
+	}
+	err = fillSequences(gmu, func(name string, n int) ([]uint64, error) {
+		return worker.NextSequenceValues(ctx, name, n)
+	})
+	if err != nil {
+		return resp, err
+	}
+
 	newUids, err := query.AssignUids(ctx, gmu.Set)
 	if err != nil {
 		return resp, err
 	}
 	resp.Uids = query.UidsToHex(query.StripBlankNode(newUids))
+	if labels != nil {
+		fillUidLabels(labels, usedVars, varToUID, resp.Uids)
+	}
 	edges, err := query.ToDirectedEdges(gmu, newUids)
 	if err != nil {
 		return resp, err
 	}
+	keyConflicts, err := checkTypeKeys(ctx, edges, mu.StartTs)
+	if err != nil {
+		return resp, err
+	}
+	if err := checkStrictTypes(ctx, edges, newUids, mu.StartTs); err != nil {
+		return resp, err
+	}
 
-	m := &pb.Mutations{Edges: edges, StartTs: mu.StartTs}
-	span.Annotatef(nil, "Applying mutations: %+v", m)
-	resp.Context, err = query.ApplyMutations(ctx, m)
+	if ticket != nil {
+		m := &pb.Mutations{Edges: edges, StartTs: mu.StartTs}
+		span.Annotatef(nil, "Applying async mutations: %+v", m)
+		*ticket, err = applyAsync(ctx, m, keyConflicts, resp)
+		return resp, err
+	}
+
+	if mu.Query != "" && len(edges) > upsertBatchSize {
+		// A mutation over the results of a query can expand into a lot of edges, propose
+		// them in batches within the same transaction.
+		resp.Context, err = applyMutationsInBatches(ctx, edges, mu.StartTs)
+	} else {
+		m := &pb.Mutations{Edges: edges, StartTs: mu.StartTs}
+		span.Annotatef(nil, "Applying mutations: %+v", m)
+		resp.Context, err = query.ApplyMutations(ctx, m)
+	}
+	addConflictKeys(resp.Context, keyConflicts)
 	span.Annotatef(nil, "Txn Context: %+v. Err=%v", resp.Context, err)
 	if !mu.CommitNow {
 		if err == y.ErrConflict {
@@ -529,13 +630,19 @@ func (s *Server) doMutate(ctx context.Context, mu *api.Mutation, authorize bool)
 		return resp, err
 	}
 
-	// The following logic is for committing immediately.
+	return resp, commitImmediately(ctx, resp, mu.StartTs, err)
+}
+
+// commitImmediately commits the transaction of the mutations applied with the given error,
+// or aborts it if they failed. The commit ts is set in the context of resp.
+func commitImmediately(ctx context.Context, resp *api.Assigned, startTs uint64, err error) error {
+	span := otrace.FromContext(ctx)
 	if err != nil {
 		// ApplyMutations failed. We now want to abort the transaction,
 		// ignoring any error that might occur during the abort (the user would
 		// care more about the previous error).
 		if resp.Context == nil {
-			resp.Context = &api.TxnContext{StartTs: mu.StartTs}
+			resp.Context = &api.TxnContext{StartTs: startTs}
 		}
 
 		resp.Context.Aborted = true
@@ -543,40 +650,46 @@ func (s *Server) doMutate(ctx context.Context, mu *api.Mutation, authorize bool)
 
 		if err == y.ErrConflict {
 			// We have already aborted the transaction, so the error message should reflect that.
-			return resp, y.ErrAborted
+			return y.ErrAborted
 		}
 
-		return resp, err
+		return err
 	}
 
 	span.Annotatef(nil, "Prewrites err: %v. Attempting to commit/abort immediately.", err)
 	ctxn := resp.Context
+	if len(ctxn.Keys) == 0 && len(ctxn.Preds) == 0 {
+		// Nothing was written by the transaction, e.g. when the mutation only added values to
+		// append-only predicates, which are already committed. Skip the commit in Zero.
+		span.Annotate(nil, "Nothing to commit")
+		return nil
+	}
 	// zero would assign the CommitTs
 	cts, err := worker.CommitOverNetwork(ctx, ctxn)
 	span.Annotatef(nil, "Status of commit at ts: %d: %v", ctxn.StartTs, err)
 	if err != nil {
-		if err == y.ErrAborted {
+		if errors.Cause(err) == y.ErrAborted {
 			err = status.Errorf(codes.Aborted, err.Error())
 			resp.Context.Aborted = true
 		}
 
-		return resp, err
+		return err
 	}
 
 	// CommitNow was true, no need to send keys.
 	resp.Context.Keys = resp.Context.Keys[:0]
 	resp.Context.CommitTs = cts
 
-	return resp, nil
+	return nil
 }
 
 // doQueryInUpsert processes the query in upsert block.
 func doQueryInUpsert(ctx context.Context, mu *api.Mutation, gmu *gql.Mutation) (
-	*query.Latency, error) {
+	*query.Latency, map[string][]string, error) {
 
 	l := &query.Latency{}
 	if mu.Query == "" {
-		return l, nil
+		return l, nil, nil
 	}
 
 	upsertQuery := mu.Query
@@ -615,24 +728,31 @@ func doQueryInUpsert(ctx context.Context, mu *api.Mutation, gmu *gql.Mutation) (
 	}, needVars)
 	l.Parsing += time.Since(startParsingTime)
 	if err != nil {
-		return nil, errors.Wrapf(err, "while parsing query: %q", upsertQuery)
+		return nil, nil, x.WithCode(errors.Wrapf(err, "while parsing query: %q", upsertQuery),
+			x.CodeInvalidQuery)
 	}
 	if err := validateQuery(parsedReq.Query); err != nil {
-		return nil, errors.Wrapf(err, "while validating query: %q", upsertQuery)
+		return nil, nil, x.WithCode(errors.Wrapf(err, "while validating query: %q", upsertQuery),
+			x.CodeInvalidQuery)
 	}
 
 	qr := query.Request{Latency: l, GqlQuery: &parsedReq, ReadTs: mu.StartTs}
-	if err := qr.ProcessQuery(ctx); err != nil {
-		return nil, errors.Wrapf(err, "while processing query: %q", upsertQuery)
+	// Masked values copied by val() are written masked.
+	if err := qr.ProcessQuery(withMasking(ctx)); err != nil {
+		return nil, nil, errors.Wrapf(err, "while processing query: %q", upsertQuery)
 	}
 
 	if len(qr.Vars) <= 0 {
-		return nil, errors.Errorf("upsert query block has no variables")
+		return nil, nil, errors.Errorf("upsert query block has no variables")
 	}
 
 	// If a variable doesn't have any UID, we generate one ourselves later.
 	varToUID := make(map[string][]string)
+	varToVal := make(map[string]map[uint64]types.Val)
 	for name, v := range qr.Vars {
+		if len(v.Vals) > 0 {
+			varToVal[name] = v.Vals
+		}
 		if v.Uids == nil || len(v.Uids.Uids) <= 0 {
 			continue
 		}
@@ -651,19 +771,44 @@ func doQueryInUpsert(ctx context.Context, mu *api.Mutation, gmu *gql.Mutation) (
 		if !isMut {
 			gmu.Set = nil
 			gmu.Del = nil
-			return l, nil
+			return l, varToUID, nil
 		}
 	}
 
-	updateMutations(gmu, varToUID)
-	return l, nil
+	if err := updateMutations(gmu, varToUID, varToVal); err != nil {
+		return nil, nil, err
+	}
+	return l, varToUID, nil
+}
+
+// applyMutationsInBatches applies the edges upsertBatchSize at a time at the given start
+// timestamp and returns the combined transaction context.
+func applyMutationsInBatches(ctx context.Context, edges []*pb.DirectedEdge,
+	startTs uint64) (*api.TxnContext, error) {
+	tctx := &api.TxnContext{StartTs: startTs}
+	for start := 0; start < len(edges); start += upsertBatchSize {
+		end := start + upsertBatchSize
+		if end > len(edges) {
+			end = len(edges)
+		}
+		m := &pb.Mutations{Edges: edges[start:end], StartTs: startTs}
+		bctx, err := query.ApplyMutations(ctx, m)
+		if bctx != nil {
+			tctx.Keys = append(tctx.Keys, bctx.Keys...)
+			tctx.Preds = append(tctx.Preds, bctx.Preds...)
+		}
+		if err != nil {
+			return tctx, err
+		}
+	}
+	return tctx, nil
 }
 
 // findVars finds all the variables used in mutation block
 func findVars(gmu *gql.Mutation) []string {
 	vars := make(map[string]struct{})
 	updateVars := func(s string) {
-		if strings.HasPrefix(s, "uid(") {
+		if strings.HasPrefix(s, "uid(") || strings.HasPrefix(s, "val(") {
 			varName := s[4 : len(s)-1]
 			vars[varName] = struct{}{}
 		}
@@ -688,10 +833,50 @@ func findVars(gmu *gql.Mutation) []string {
 	return varsList
 }
 
+// fillUidLabels sets the uids of the blank nodes and the variables used in the mutation.
+// The uids of a variable come from the upsert query, or were assigned to it if the query
+// didn't match any node.
+func fillUidLabels(labels *query.UidLabels, vars []string, varToUID map[string][]string,
+	assigned map[string]string) {
+
+	for name, uid := range assigned {
+		if strings.HasPrefix(name, "uid(") {
+			continue
+		}
+		if labels.BlankNodes == nil {
+			labels.BlankNodes = make(map[string]string)
+		}
+		labels.BlankNodes[name] = uid
+	}
+
+	for _, v := range vars {
+		var uids []string
+		if decUids, ok := varToUID[v]; ok {
+			for _, u := range decUids {
+				uid, err := strconv.ParseUint(u, 10, 64)
+				x.Check(err)
+				uids = append(uids, fmt.Sprintf("%#x", uid))
+			}
+		} else if uid, ok := assigned["uid("+v+")"]; ok {
+			uids = []string{uid}
+		} else {
+			continue
+		}
+		if labels.Vars == nil {
+			labels.Vars = make(map[string][]string)
+		}
+		labels.Vars[v] = uids
+	}
+}
+
 // updateMutations does following transformations:
 //   * uid(v) -> 0x123     -- If v is defined in query block
 //   * uid(v) -> _:uid(v)  -- Otherwise
-func updateMutations(gmu *gql.Mutation, varToUID map[string][]string) {
+//   * val(x) -> value of x for the subject of the NQuad, which makes the mutation
+//     apply to each uid matched by the query with that node's own value. NQuads for
+//     subjects which don't have a value are dropped.
+func updateMutations(gmu *gql.Mutation, varToUID map[string][]string,
+	varToVal map[string]map[uint64]types.Val) error {
 	getNewVals := func(s string) []string {
 		if strings.HasPrefix(s, "uid(") {
 			varName := s[4 : len(s)-1]
@@ -705,14 +890,31 @@ func updateMutations(gmu *gql.Mutation, varToUID map[string][]string) {
 		return []string{s}
 	}
 
-	getNewNQuad := func(nq *api.NQuad, s, o string) *api.NQuad {
+	getNewNQuad := func(nq *api.NQuad, s, o string) (*api.NQuad, error) {
 		// The following copy is fine because we only modify Subject and ObjectId.
 		// The pointer values are not modified across different copies of NQuad.
 		n := *nq
 
 		n.Subject = s
 		n.ObjectId = o
-		return &n
+		if !strings.HasPrefix(o, "val(") {
+			return &n, nil
+		}
+
+		// The subject is either a uid from the query or a blank node which can't have a value.
+		uid, err := strconv.ParseUint(s, 0, 64)
+		if err != nil {
+			return nil, nil
+		}
+		val, ok := varToVal[o[4:len(o)-1]][uid]
+		if !ok || val.Value == nil {
+			return nil, nil
+		}
+		n.ObjectId = ""
+		if n.ObjectValue, err = types.ObjectValue(val.Tid, val.Value); err != nil {
+			return nil, errors.Wrapf(err, "while substituting %s for uid %#x", o, uid)
+		}
+		return &n, nil
 	}
 
 	// Remove the mutations from gmu.Del when no UID was found.
@@ -731,7 +933,13 @@ func updateMutations(gmu *gql.Mutation, varToUID map[string][]string) {
 					continue
 				}
 
-				gmuDel = append(gmuDel, getNewNQuad(nq, s, o))
+				n, err := getNewNQuad(nq, s, o)
+				if err != nil {
+					return err
+				}
+				if n != nil {
+					gmuDel = append(gmuDel, n)
+				}
 			}
 		}
 	}
@@ -745,15 +953,26 @@ func updateMutations(gmu *gql.Mutation, varToUID map[string][]string) {
 
 		for _, s := range newSubs {
 			for _, o := range newObs {
-				gmuSet = append(gmuSet, getNewNQuad(nq, s, o))
+				n, err := getNewNQuad(nq, s, o)
+				if err != nil {
+					return err
+				}
+				if n != nil {
+					gmuSet = append(gmuSet, n)
+				}
 			}
 		}
 	}
 	gmu.Set = gmuSet
+	return nil
 }
 
-// Query handles queries and returns the data.
+// Query handles queries and returns the data. If the dry-run metadata is true, the query isn't
+// run and the JSON of the response holds its analysis and estimated cost instead.
 func (s *Server) Query(ctx context.Context, req *api.Request) (*api.Response, error) {
+	if isDryRun(ctx) {
+		return s.dryRunQuery(ctx, req)
+	}
 	if err := authorizeQuery(ctx, req); err != nil {
 		return nil, err
 	}
@@ -761,7 +980,7 @@ func (s *Server) Query(ctx context.Context, req *api.Request) (*api.Response, er
 		glog.Infof("Got a query: %+v", req)
 	}
 
-	return s.doQuery(ctx, req)
+	return s.doQuery(withMasking(ctx), req)
 }
 
 // This method is used to execute the query and return the response to the
@@ -775,6 +994,10 @@ func (s *Server) doQuery(ctx context.Context, req *api.Request) (resp *api.Respo
 	var measurements []ostats.Measurement
 	ctx, span := otrace.StartSpan(ctx, methodQuery)
 	ctx = x.WithMethod(ctx, methodQuery)
+	if worker.QueryMetricsFrom(ctx) == nil {
+		ctx = worker.WithQueryMetrics(ctx)
+	}
+	ctx = query.WithTruncation(ctx)
 	defer func() {
 		span.End()
 		v := x.TagValueStatusOK
@@ -784,12 +1007,26 @@ func (s *Server) doQuery(ctx context.Context, req *api.Request) (resp *api.Respo
 		ctx, _ = tag.New(ctx, tag.Upsert(x.KeyStatus, v))
 		timeSpentMs := x.SinceMs(startTime)
 		measurements = append(measurements, x.LatencyMs.M(timeSpentMs))
+		m := worker.QueryMetricsFrom(ctx)
+		measurements = append(measurements,
+			x.NumKeysRead.M(int64(m.KeysRead)),
+			x.BytesRead.M(int64(m.BytesRead)),
+			x.NetworkBytes.M(int64(m.NetworkBytes)),
+			x.ResponseBytes.M(int64(m.ResponseBytes)))
 		ostats.Record(ctx, measurements...)
 	}()
 
 	if err := x.HealthCheck(); err != nil {
 		return nil, err
 	}
+	x.ConfigMu.RLock()
+	timeout := x.Config.QueryTimeout
+	x.ConfigMu.RUnlock()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
 	ostats.Record(ctx, x.PendingQueries.M(1), x.NumQueries.M(1))
 	defer func() {
@@ -799,23 +1036,26 @@ func (s *Server) doQuery(ctx context.Context, req *api.Request) (resp *api.Respo
 	resp = &api.Response{}
 	if len(req.Query) == 0 {
 		span.Annotate(nil, "Empty query")
-		return resp, errors.Errorf("Empty query")
+		return resp, x.WithCode(errors.Errorf("Empty query"), x.CodeInvalidQuery)
 	}
 
 	var l query.Latency
 	l.Start = time.Now()
 	span.Annotatef(nil, "Query received: %v", req)
 
-	parsedReq, err := gql.Parse(gql.Request{
+	parsedReq, err := parseQuery(gql.Request{
 		Str:       req.Query,
 		Variables: req.Vars,
 	})
 	if err != nil {
-		return resp, err
+		return resp, x.WithCode(err, x.CodeInvalidQuery)
+	}
+	for _, w := range parsedReq.Warnings {
+		query.AddWarning(ctx, "%s", w)
 	}
 
 	if err = validateQuery(parsedReq.Query); err != nil {
-		return resp, err
+		return resp, x.WithCode(err, x.CodeInvalidQuery)
 	}
 
 	var queryRequest = query.Request{
@@ -875,12 +1115,25 @@ func (s *Server) doQuery(ctx context.Context, req *api.Request) (resp *api.Respo
 		}
 		js, err = json.Marshal(respMap)
 	} else {
-		js, err = query.ToJson(&l, er.Subgraphs)
+		js, err = query.ToJsonWithSpill(ctx, &l, er.Subgraphs)
 	}
 	if err != nil {
 		return resp, err
 	}
 	resp.Json = js
+	cursor, truncated := query.Truncated(ctx)
+	if truncated && grpc.ServerTransportStreamFromContext(ctx) != nil {
+		// gRPC clients get the cursor to continue a truncated response from in the headers.
+		md := metadata.Pairs("truncated", "true", "cursor", cursor)
+		if herr := grpc.SetHeader(ctx, md); herr != nil {
+			glog.Warningf("Unable to send the cursor of a truncated response: %v", herr)
+		}
+	}
+	if spilled := query.SpilledJSON(ctx); spilled != nil {
+		worker.AddResponseBytes(ctx, int(spilled.Len()))
+	} else {
+		worker.AddResponseBytes(ctx, len(js))
+	}
 	span.Annotatef(nil, "Response = %s", js)
 
 	// TODO(martinmr): Include Transport as part of the latency. Need to do this separately
@@ -913,7 +1166,7 @@ func (s *Server) CommitOrAbort(ctx context.Context, tc *api.TxnContext) (*api.Tx
 
 	span.Annotatef(nil, "Txn Context received: %+v", tc)
 	commitTs, err := worker.CommitOverNetwork(ctx, tc)
-	if err == y.ErrAborted {
+	if errors.Cause(err) == y.ErrAborted {
 		tctx.Aborted = true
 		return tctx, status.Errorf(codes.Aborted, err.Error())
 	}
@@ -1188,6 +1441,12 @@ func formatTypes(types []*pb.TypeUpdate) []map[string]interface{} {
 			fieldMap := formatField(field)
 			typeMap["fields"] = append(typeMap["fields"].([]map[string]string), fieldMap)
 		}
+		if len(typ.Key) > 0 {
+			typeMap["key"] = typ.Key
+		}
+		if typ.Strict {
+			typeMap["strict"] = true
+		}
 
 		res = append(res, typeMap)
 	}
@@ -17,11 +17,17 @@
 package edgraph
 
 import (
+	"context"
+	"sort"
 	"testing"
 
 	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
 )
 
 func makeNquad(sub, pred string, val *api.Value) *api.NQuad {
@@ -75,6 +81,54 @@ func TestParseNQuadsDelete(t *testing.T) {
 	}, nqs)
 }
 
+func TestUpdateMutationsWithValVars(t *testing.T) {
+	nqs, err := parseNQuads([]byte(`
+		uid(v) <name_copy> val(n) .
+		uid(v) <score> val(s) .
+		uid(v) <tag> "copied" .
+	`))
+	require.NoError(t, err)
+	gmu := &gql.Mutation{Set: nqs}
+	vars := findVars(gmu)
+	sort.Strings(vars)
+	require.Equal(t, []string{"n", "s", "v"}, vars)
+
+	varToUID := map[string][]string{"v": {"1", "2"}}
+	varToVal := map[string]map[uint64]types.Val{
+		"n": {
+			1: {Tid: types.StringID, Value: "alice"},
+			2: {Tid: types.StringID, Value: "bob"},
+		},
+		// Uid 2 has no score, so no score is set for it.
+		"s": {1: {Tid: types.FloatID, Value: 4.5}},
+	}
+	require.NoError(t, updateMutations(gmu, varToUID, varToVal))
+	require.Equal(t, []*api.NQuad{
+		makeNquad("1", "name_copy", &api.Value{Val: &api.Value_StrVal{StrVal: "alice"}}),
+		makeNquad("2", "name_copy", &api.Value{Val: &api.Value_StrVal{StrVal: "bob"}}),
+		makeNquad("1", "score", &api.Value{Val: &api.Value_DoubleVal{DoubleVal: 4.5}}),
+		makeNquad("1", "tag", &api.Value{Val: &api.Value_DefaultVal{DefaultVal: "copied"}}),
+		makeNquad("2", "tag", &api.Value{Val: &api.Value_DefaultVal{DefaultVal: "copied"}}),
+	}, gmu.Set)
+}
+
+func TestFillUidLabels(t *testing.T) {
+	labels := &query.UidLabels{}
+	varToUID := map[string][]string{"v": {"10", "26"}}
+	assigned := map[string]string{"alice": "0x1", "uid(w)": "0x2"}
+	fillUidLabels(labels, []string{"v", "w", "n"}, varToUID, assigned)
+	require.Equal(t, map[string]string{"alice": "0x1"}, labels.BlankNodes)
+	require.Equal(t, map[string][]string{
+		"v": {"0xa", "0x1a"},
+		"w": {"0x2"},
+	}, labels.Vars)
+
+	labels = &query.UidLabels{}
+	fillUidLabels(labels, nil, nil, nil)
+	require.Nil(t, labels.BlankNodes)
+	require.Nil(t, labels.Vars)
+}
+
 func TestValidateKeys(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -112,3 +166,13 @@ func TestValidateKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestRunInBackground(t *testing.T) {
+	require.False(t, runInBackground(context.Background()))
+	ctx := metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("run_in_background", "true"))
+	require.True(t, runInBackground(ctx))
+	ctx = metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("run_in_background", "false"))
+	require.False(t, runInBackground(ctx))
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// maxViolationsInError is the number of violations listed in the error of a mutation rejected
+// because it doesn't fit the schema or the types of its nodes.
+const maxViolationsInError = 10
+
+// SchemaViolation is an n-quad of a mutation which doesn't fit the schema, or a field of a node
+// which doesn't fit its type.
+type SchemaViolation struct {
+	Subject   string `json:"subject"`
+	Predicate string `json:"predicate"`
+	// Type is the type whose field is violated, for the nodes of a type declared with @strict.
+	Type   string `json:"type,omitempty"`
+	Delete bool   `json:"delete,omitempty"`
+	Error  string `json:"error"`
+}
+
+func (v SchemaViolation) String() string {
+	if v.Type != "" {
+		return fmt.Sprintf("<%s> %s.%s: %s", v.Subject, v.Type, v.Predicate, v.Error)
+	}
+	return fmt.Sprintf("<%s> <%s>: %s", v.Subject, v.Predicate, v.Error)
+}
+
+// violationsError returns the error of the violations of a mutation, listing the first
+// maxViolationsInError of them.
+func violationsError(msg string, violations []SchemaViolation) error {
+	if len(violations) == 0 {
+		return nil
+	}
+	msgs := make([]string, 0, maxViolationsInError+1)
+	for i, v := range violations {
+		if i == maxViolationsInError {
+			msgs = append(msgs, fmt.Sprintf("and %d more", len(violations)-i))
+			break
+		}
+		msgs = append(msgs, v.String())
+	}
+	return errors.Errorf("%s: %s", msg, strings.Join(msgs, "; "))
+}
+
+// schemaEdge returns the edge of the n-quad, from a placeholder subject, to check its value
+// against the schema. It returns nil if the object is only known once the mutation runs.
+func schemaEdge(nq gql.NQuad) (*pb.DirectedEdge, error) {
+	switch {
+	case gql.IsValueOp(nq.ObjectId):
+		return nq.CreateValueOpEdge(1)
+	case gql.IsNextFunc(nq.ObjectId) || strings.HasPrefix(nq.ObjectId, "val("):
+		return nil, nil
+	case nq.ObjectValue == nil && len(nq.ObjectId) > 0:
+		return nq.CreateUidEdge(1, 1), nil
+	default:
+		return nq.CreateValueEdge(1)
+	}
+}
+
+// checkMutationSchema returns the n-quads of the mutation which don't fit the schema of the
+// cluster: the ones of predicates which aren't declared, and the values which don't convert to
+// the declared type of their predicate.
+func checkMutationSchema(ctx context.Context, gmu *gql.Mutation) ([]SchemaViolation, error) {
+	var preds []string
+	seen := make(map[string]bool)
+	for _, nqs := range [][]*api.NQuad{gmu.Set, gmu.Del} {
+		for _, nq := range nqs {
+			if nq.Predicate != x.Star && !seen[nq.Predicate] {
+				seen[nq.Predicate] = true
+				preds = append(preds, nq.Predicate)
+			}
+		}
+	}
+	if len(preds) == 0 {
+		return nil, nil
+	}
+
+	declared := make(map[string]*pb.SchemaUpdate)
+	for _, su := range schema.InitialSchema() {
+		declared[su.Predicate] = su
+	}
+	nodes, err := worker.GetSchemaOverNetwork(ctx, &pb.SchemaRequest{
+		Predicates: preds,
+		Fields:     []string{"type", "list", "lang"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		tid, ok := types.TypeForName(node.Type)
+		if !ok {
+			return nil, errors.Errorf("Unknown type %s of predicate %s", node.Type, node.Predicate)
+		}
+		declared[node.Predicate] = &pb.SchemaUpdate{
+			Predicate: node.Predicate,
+			ValueType: tid.Enum(),
+			List:      node.List,
+			Lang:      node.Lang,
+		}
+	}
+
+	var violations []SchemaViolation
+	check := func(nq *api.NQuad, del bool) {
+		if nq.Predicate == x.Star {
+			return
+		}
+		violation := SchemaViolation{Subject: nq.Subject, Predicate: nq.Predicate, Delete: del}
+		su, ok := declared[nq.Predicate]
+		if !ok {
+			violation.Error = "predicate is not declared in the schema"
+			violations = append(violations, violation)
+			return
+		}
+		edge, err := schemaEdge(gql.NQuad{NQuad: nq})
+		if err == nil && edge != nil {
+			err = worker.ValidateAndConvert(edge, su)
+		}
+		if err != nil {
+			violation.Error = err.Error()
+			violations = append(violations, violation)
+		}
+	}
+	for _, nq := range gmu.Set {
+		check(nq, false)
+	}
+	for _, nq := range gmu.Del {
+		check(nq, true)
+	}
+	return violations, nil
+}
+
+// checkStrictMutation rejects the mutation if it doesn't fit the schema, when the mutations are
+// in strict mode. The whole mutation is checked before any of it is proposed.
+func checkStrictMutation(ctx context.Context, gmu *gql.Mutation) error {
+	if Config.MutationsMode != StrictMutations {
+		return nil
+	}
+	violations, err := checkMutationSchema(ctx, gmu)
+	if err != nil {
+		return err
+	}
+	return violationsError("Mutation doesn't fit the schema", violations)
+}
+
+// ValidateMutation returns the n-quads of the mutation which don't fit the schema, as a mutation
+// in strict mode would, without running it. It's meant for loaders to check their data before
+// sending it. The query of an upsert block isn't run, so the values copied with val() aren't
+// checked.
+func (s *Server) ValidateMutation(ctx context.Context, mu *api.Mutation) (
+	[]SchemaViolation, error) {
+
+	if err := x.HealthCheck(); err != nil {
+		return nil, err
+	}
+	gmu, err := parseMutationObject(mu)
+	if err != nil {
+		return nil, x.WithCode(err, x.CodeInvalidQuery)
+	}
+	if err := authorizeMutation(ctx, gmu); err != nil {
+		return nil, err
+	}
+	return checkMutationSchema(ctx, gmu)
+}
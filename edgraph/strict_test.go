@@ -0,0 +1,84 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+func TestSchemaEdge(t *testing.T) {
+	intSchema := &pb.SchemaUpdate{Predicate: "age", ValueType: pb.Posting_INT}
+	uidSchema := &pb.SchemaUpdate{Predicate: "friend", ValueType: pb.Posting_UID, List: true}
+
+	check := func(nq *api.NQuad, su *pb.SchemaUpdate) error {
+		edge, err := schemaEdge(gql.NQuad{NQuad: nq})
+		require.NoError(t, err)
+		require.NotNil(t, edge)
+		return worker.ValidateAndConvert(edge, su)
+	}
+	str := func(s string) *api.Value {
+		return &api.Value{Val: &api.Value_DefaultVal{DefaultVal: s}}
+	}
+
+	require.NoError(t, check(&api.NQuad{Subject: "_:a", Predicate: "age", ObjectValue: str("30")},
+		intSchema))
+	require.Error(t, check(&api.NQuad{Subject: "_:a", Predicate: "age", ObjectValue: str("x")},
+		intSchema))
+	require.Error(t, check(&api.NQuad{Subject: "_:a", Predicate: "age", ObjectId: "_:b"},
+		intSchema))
+	require.NoError(t, check(&api.NQuad{Subject: "_:a", Predicate: "friend", ObjectId: "_:b"},
+		uidSchema))
+	require.Error(t, check(&api.NQuad{Subject: "_:a", Predicate: "friend", ObjectValue: str("b")},
+		uidSchema))
+
+	// The objects only known once the mutation runs aren't checked.
+	for _, obj := range []string{"next(seq)", "val(v)"} {
+		edge, err := schemaEdge(gql.NQuad{NQuad: &api.NQuad{Subject: "_:a", Predicate: "age",
+			ObjectId: obj}})
+		require.NoError(t, err)
+		require.Nil(t, edge)
+	}
+}
+
+func TestSchemaViolationString(t *testing.T) {
+	v := SchemaViolation{Subject: "_:a", Predicate: "nick",
+		Error: "predicate is not declared in the schema"}
+	require.Equal(t, "<_:a> <nick>: predicate is not declared in the schema", v.String())
+}
+
+func TestSchemaViolationOfType(t *testing.T) {
+	v := SchemaViolation{Subject: "_:a", Predicate: "email", Type: "Person",
+		Error: "non-nullable field isn't set"}
+	require.Equal(t, "<_:a> Person.email: non-nullable field isn't set", v.String())
+
+	var violations []SchemaViolation
+	require.NoError(t, violationsError("Mutation doesn't fit", violations))
+	for i := 0; i < maxViolationsInError+2; i++ {
+		violations = append(violations, v)
+	}
+	err := violationsError("Mutation doesn't fit", violations)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Mutation doesn't fit: <_:a> Person.email")
+	require.Contains(t, err.Error(), "; and 2 more")
+}
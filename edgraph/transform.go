@@ -0,0 +1,325 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/dgraph-io/badger/y"
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// Transform reshapes the data of the responses to the queries of the same name with a Go
+// template, so that clients expecting a fixed JSON layout can be served directly. The template
+// is executed with the decoded data and has to produce valid JSON.
+type Transform struct {
+	Name     string `json:"name"`
+	Template string `json:"template"`
+
+	tmpl *template.Template
+}
+
+// ErrUnknownTransform is returned for names no transform is registered under.
+var ErrUnknownTransform = errors.New("Transform is not registered")
+
+// transformRefreshInterval is how often the transforms are read back from the cluster, which
+// is how the transforms registered on other Alphas reach this one.
+const transformRefreshInterval = 5 * time.Second
+
+// transforms caches the transforms stored in the cluster. The version changes whenever the
+// cached transforms do, as the responses to the queries change with them.
+var transforms struct {
+	sync.RWMutex
+	m       map[string]*Transform
+	version uint64
+}
+
+var transformFuncs = template.FuncMap{
+	// json encodes a value, as needed to write nested objects in the output.
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+}
+
+func parseTransform(name, text string) (*Transform, error) {
+	if name == "" {
+		return nil, errors.New("Transform requires a name")
+	}
+	tmpl, err := template.New(name).Funcs(transformFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while parsing transform %s", name)
+	}
+	return &Transform{Name: name, Template: text, tmpl: tmpl}, nil
+}
+
+// setTransforms replaces the cached transforms, bumping their version if any changed.
+func setTransforms(ts []*Transform) {
+	m := make(map[string]*Transform, len(ts))
+	for _, t := range ts {
+		m[t.Name] = t
+	}
+
+	transforms.Lock()
+	defer transforms.Unlock()
+	changed := len(m) != len(transforms.m)
+	for name, t := range m {
+		if old, ok := transforms.m[name]; !ok || old.Template != t.Template {
+			changed = true
+		}
+	}
+	if changed {
+		transforms.m = m
+		transforms.version++
+	}
+}
+
+func transformsVersion() uint64 {
+	transforms.RLock()
+	defer transforms.RUnlock()
+	return transforms.version
+}
+
+const queryTransforms = `
+{
+  transforms(func: has(dgraph.transform.name)) {
+    uid
+    dgraph.transform.name
+    dgraph.transform.template
+  }
+}
+`
+
+type storedTransform struct {
+	Uid      string `json:"uid"`
+	Name     string `json:"dgraph.transform.name"`
+	Template string `json:"dgraph.transform.template"`
+}
+
+// readTransforms returns the transforms stored in the cluster, along with the start ts of the
+// query reading them.
+func readTransforms(ctx context.Context) ([]storedTransform, uint64, error) {
+	resp, err := (&Server{}).doQuery(ctx, &api.Request{Query: queryTransforms})
+	if err != nil {
+		return nil, 0, errors.Wrapf(err, "while reading transforms")
+	}
+	var data struct {
+		Transforms []storedTransform `json:"transforms"`
+	}
+	if err := json.Unmarshal(resp.Json, &data); err != nil {
+		return nil, 0, errors.Wrapf(err, "while decoding transforms")
+	}
+	return data.Transforms, resp.GetTxn().GetStartTs(), nil
+}
+
+// refreshTransforms reads the transforms from the cluster into the cache. Stored transforms
+// that don't parse are skipped, as they can only have been written bypassing RegisterTransform.
+func refreshTransforms(ctx context.Context) error {
+	stored, _, err := readTransforms(ctx)
+	if err != nil {
+		return err
+	}
+	ts := make([]*Transform, 0, len(stored))
+	for _, st := range stored {
+		t, err := parseTransform(st.Name, st.Template)
+		if err != nil {
+			glog.Warningf("Skipping stored transform: %v", err)
+			continue
+		}
+		ts = append(ts, t)
+	}
+	setTransforms(ts)
+	return nil
+}
+
+// RefreshTransforms reads the transforms stored in the cluster periodically, until the closer
+// is signaled.
+func RefreshTransforms(closer *y.Closer) {
+	defer closer.Done()
+
+	ticker := time.NewTicker(transformRefreshInterval)
+	defer ticker.Stop()
+	for {
+		if err := refreshTransforms(context.Background()); err != nil {
+			glog.Errorf("Error while refreshing transforms: %v", err)
+		}
+		select {
+		case <-closer.HasBeenClosed():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ensureTransformSchema defines the predicates transforms are stored in, unless they already
+// are, so that transforms can be stored in strict mode.
+func ensureTransformSchema(ctx context.Context) error {
+	preds := []string{"dgraph.transform.name", "dgraph.transform.template"}
+	nodes, err := worker.GetSchemaOverNetwork(ctx, &pb.SchemaRequest{Predicates: preds})
+	if err != nil {
+		return err
+	}
+	if len(nodes) == len(preds) {
+		return nil
+	}
+	m := &pb.Mutations{
+		StartTs: State.getTimestamp(false),
+		Schema: []*pb.SchemaUpdate{
+			{
+				Predicate: "dgraph.transform.name",
+				ValueType: pb.Posting_STRING,
+				Directive: pb.SchemaUpdate_INDEX,
+				Tokenizer: []string{"exact"},
+				Upsert:    true,
+			},
+			{
+				Predicate: "dgraph.transform.template",
+				ValueType: pb.Posting_STRING,
+			},
+		},
+	}
+	_, err = query.ApplyMutations(ctx, m)
+	return err
+}
+
+// RegisterTransform parses the template and stores it in the cluster under the name, replacing
+// any transform registered with the same name. The transform applies to the queries of the same
+// name.
+func RegisterTransform(ctx context.Context, name, text string) error {
+	if _, err := parseTransform(name, text); err != nil {
+		return err
+	}
+	if err := ensureTransformSchema(ctx); err != nil {
+		return errors.Wrapf(err, "while defining the transform predicates")
+	}
+	stored, startTs, err := readTransforms(ctx)
+	if err != nil {
+		return err
+	}
+	uid := "_:transform"
+	for _, st := range stored {
+		if st.Name == name {
+			uid = st.Uid
+		}
+	}
+	setJson, err := json.Marshal(map[string]string{
+		"uid":                       uid,
+		"dgraph.transform.name":     name,
+		"dgraph.transform.template": text,
+	})
+	if err != nil {
+		return err
+	}
+	mu := &api.Mutation{StartTs: startTs, CommitNow: true, SetJson: setJson}
+	if _, err := (&Server{}).doMutate(ctx, mu, false, nil, nil); err != nil {
+		return errors.Wrapf(err, "while storing transform %s", name)
+	}
+	return refreshTransforms(ctx)
+}
+
+// DeleteTransform removes the transform with the given name from the cluster.
+func DeleteTransform(ctx context.Context, name string) error {
+	stored, startTs, err := readTransforms(ctx)
+	if err != nil {
+		return err
+	}
+	var delJson []byte
+	for _, st := range stored {
+		if st.Name != name {
+			continue
+		}
+		if delJson, err = json.Marshal(map[string]interface{}{
+			"uid":                       st.Uid,
+			"dgraph.transform.name":     nil,
+			"dgraph.transform.template": nil,
+		}); err != nil {
+			return err
+		}
+	}
+	if delJson == nil {
+		return errors.Wrapf(ErrUnknownTransform, "while deleting transform %s", name)
+	}
+	mu := &api.Mutation{StartTs: startTs, CommitNow: true, DeleteJson: delJson}
+	if _, err := (&Server{}).doMutate(ctx, mu, false, nil, nil); err != nil {
+		return errors.Wrapf(err, "while deleting transform %s", name)
+	}
+	return refreshTransforms(ctx)
+}
+
+// Transforms returns the registered transforms sorted by name.
+func Transforms() []Transform {
+	transforms.RLock()
+	defer transforms.RUnlock()
+	out := make([]Transform, 0, len(transforms.m))
+	for _, t := range transforms.m {
+		out = append(out, Transform{Name: t.Name, Template: t.Template})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// QueryTransform returns the name of the transform bound to the query of the request, or an
+// empty string if the query is unnamed or no transform is registered under its name.
+func QueryTransform(req *api.Request) string {
+	parsed, err := parseQuery(gql.Request{Str: req.Query, Variables: req.Vars})
+	if err != nil || parsed.Name == "" {
+		return ""
+	}
+	transforms.RLock()
+	defer transforms.RUnlock()
+	if _, ok := transforms.m[parsed.Name]; !ok {
+		return ""
+	}
+	return parsed.Name
+}
+
+// ApplyTransform reshapes the JSON data of a query response with the named transform.
+func ApplyTransform(name string, data []byte) ([]byte, error) {
+	transforms.RLock()
+	t, ok := transforms.m[name]
+	transforms.RUnlock()
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownTransform, "while applying transform %s", name)
+	}
+
+	var v interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return nil, errors.Wrapf(err, "while decoding the data for transform %s", name)
+	}
+	var out bytes.Buffer
+	if err := t.tmpl.Execute(&out, v); err != nil {
+		return nil, errors.Wrapf(err, "while applying transform %s", name)
+	}
+	if !json.Valid(out.Bytes()) {
+		return nil, errors.Errorf("Transform %s didn't produce valid JSON", name)
+	}
+	return out.Bytes(), nil
+}
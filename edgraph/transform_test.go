@@ -0,0 +1,90 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+func mustSetTransforms(t *testing.T, texts map[string]string) {
+	var ts []*Transform
+	for name, text := range texts {
+		tr, err := parseTransform(name, text)
+		require.NoError(t, err)
+		ts = append(ts, tr)
+	}
+	setTransforms(ts)
+}
+
+func TestApplyTransform(t *testing.T) {
+	defer setTransforms(nil)
+	mustSetTransforms(t, map[string]string{
+		"legacy": `{"user": {{json (index .me 0).name}}, "friends": {{json (index .me 0).friend}}}`,
+	})
+
+	out, err := ApplyTransform("legacy",
+		[]byte(`{"me":[{"name":"Alice","friend":[{"name":"Bob","age":32}]}]}`))
+	require.NoError(t, err)
+	require.JSONEq(t, `{"user":"Alice","friends":[{"name":"Bob","age":32}]}`, string(out))
+
+	require.Equal(t, []Transform{{Name: "legacy", Template: `{"user": {{json (index .me 0).name}}, ` +
+		`"friends": {{json (index .me 0).friend}}}`}}, Transforms())
+}
+
+func TestApplyTransformErrors(t *testing.T) {
+	defer setTransforms(nil)
+	_, err := parseTransform("", `{}`)
+	require.Error(t, err)
+	_, err = parseTransform("broken", `{{.me`)
+	require.Error(t, err)
+
+	_, err = ApplyTransform("missing", []byte(`{}`))
+	require.Equal(t, ErrUnknownTransform, errors.Cause(err))
+
+	mustSetTransforms(t, map[string]string{"broken": `{"name": {{.me}}`})
+	_, err = ApplyTransform("broken", []byte(`{"me":"Alice"}`))
+	require.EqualError(t, err, "Transform broken didn't produce valid JSON")
+}
+
+func TestQueryTransform(t *testing.T) {
+	defer setTransforms(nil)
+	mustSetTransforms(t, map[string]string{"legacy": `{}`})
+
+	transform := func(query string) string {
+		return QueryTransform(&api.Request{Query: query})
+	}
+	require.Equal(t, "legacy", transform(`query legacy { me(func: uid(1)) { name } }`))
+	require.Equal(t, "", transform(`query other { me(func: uid(1)) { name } }`))
+	require.Equal(t, "", transform(`{ me(func: uid(1)) { name } }`))
+}
+
+func TestTransformsVersion(t *testing.T) {
+	defer setTransforms(nil)
+	version := transformsVersion()
+	mustSetTransforms(t, map[string]string{"legacy": `{}`})
+	require.NotEqual(t, version, transformsVersion())
+
+	version = transformsVersion()
+	mustSetTransforms(t, map[string]string{"legacy": `{}`})
+	require.Equal(t, version, transformsVersion())
+	mustSetTransforms(t, map[string]string{"legacy": `[]`})
+	require.NotEqual(t, version, transformsVersion())
+}
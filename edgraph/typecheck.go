@@ -0,0 +1,246 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/x"
+	"golang.org/x/net/context"
+)
+
+// nodeTypes returns the types of the nodes at readTs.
+func nodeTypes(ctx context.Context, uids []uint64, readTs uint64) (
+	map[uint64]map[string]bool, error) {
+
+	vals, err := query.PredicateValues(ctx, "dgraph.type", uids, readTs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uint64]map[string]bool, len(uids))
+	for i, uid := range uids {
+		res[uid] = make(map[string]bool)
+		for _, val := range vals[i] {
+			if name, ok := val.Value.(string); ok {
+				res[uid][name] = true
+			}
+		}
+	}
+	return res, nil
+}
+
+// withTypeEdges returns the types of the nodes once the dgraph.type edges of a mutation are
+// applied to them.
+func withTypeEdges(types map[uint64]map[string]bool,
+	edges []*pb.DirectedEdge) map[uint64]map[string]bool {
+
+	res := make(map[uint64]map[string]bool, len(types))
+	for uid, names := range types {
+		res[uid] = make(map[string]bool, len(names))
+		for name := range names {
+			res[uid][name] = true
+		}
+	}
+	for _, edge := range edges {
+		names, ok := res[edge.Entity]
+		if !ok || edge.Attr != "dgraph.type" {
+			continue
+		}
+		switch {
+		case edge.Op != pb.DirectedEdge_DEL:
+			names[string(edge.Value)] = true
+		case bytes.Equal(edge.Value, []byte(x.Star)):
+			res[edge.Entity] = make(map[string]bool)
+		default:
+			delete(names, string(edge.Value))
+		}
+	}
+	return res
+}
+
+// required returns whether the field of a type is non-nullable: [T]! for a list, or T!.
+func required(field *pb.SchemaUpdate) bool {
+	if field.List {
+		return field.NonNullableList
+	}
+	return field.NonNullable
+}
+
+// checkStrictTypes checks the nodes of a mutation which have a type declared with @strict once
+// it's applied: the predicates it sets on them must be fields of one of their types, and the
+// non-nullable fields of the strict types they are given must be set. newUids are the uids of
+// the blank nodes, which name the new nodes in the errors.
+func checkStrictTypes(ctx context.Context, edges []*pb.DirectedEdge, newUids map[string]uint64,
+	readTs uint64) error {
+
+	strict := make(map[string]bool)
+	for _, name := range schema.State().Types() {
+		if typ, ok := schema.State().GetType(name); ok && typ.Strict {
+			strict[name] = true
+		}
+	}
+	if len(strict) == 0 {
+		return nil
+	}
+
+	var uids []uint64
+	seen := make(map[uint64]bool)
+	for _, edge := range edges {
+		if edge.Op != pb.DirectedEdge_DEL && !seen[edge.Entity] {
+			seen[edge.Entity] = true
+			uids = append(uids, edge.Entity)
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	stored, err := nodeTypes(ctx, uids, readTs)
+	if err != nil {
+		return err
+	}
+	types := withTypeEdges(stored, edges)
+
+	names := make(map[uint64]string, len(newUids))
+	for name, uid := range newUids {
+		names[uid] = name
+	}
+	subject := func(uid uint64) string {
+		if name, ok := names[uid]; ok {
+			return name
+		}
+		return fmt.Sprintf("%#x", uid)
+	}
+
+	// The fields of all the types of a node can be set on it, if one of them is strict.
+	var checked []uint64
+	strictTypes := make(map[uint64][]string)
+	fields := make(map[uint64]map[string]bool)
+	for _, uid := range uids {
+		for name := range types[uid] {
+			if strict[name] {
+				strictTypes[uid] = append(strictTypes[uid], name)
+			}
+		}
+		if len(strictTypes[uid]) == 0 {
+			continue
+		}
+		sort.Strings(strictTypes[uid])
+		checked = append(checked, uid)
+		fields[uid] = make(map[string]bool)
+		for name := range types[uid] {
+			typ, ok := schema.State().GetType(name)
+			if !ok {
+				continue
+			}
+			for _, field := range typ.Fields {
+				fields[uid][field.Predicate] = true
+			}
+		}
+	}
+	if len(checked) == 0 {
+		return nil
+	}
+
+	var violations []SchemaViolation
+	set := make(map[uint64]map[string]bool)
+	cleared := make(map[uint64]map[string]bool)
+	for _, edge := range edges {
+		if _, ok := fields[edge.Entity]; !ok {
+			continue
+		}
+		if edge.Op == pb.DirectedEdge_DEL {
+			if bytes.Equal(edge.Value, []byte(x.Star)) {
+				if cleared[edge.Entity] == nil {
+					cleared[edge.Entity] = make(map[string]bool)
+				}
+				cleared[edge.Entity][edge.Attr] = true
+			}
+			continue
+		}
+		if set[edge.Entity] == nil {
+			set[edge.Entity] = make(map[string]bool)
+		}
+		if set[edge.Entity][edge.Attr] {
+			continue
+		}
+		set[edge.Entity][edge.Attr] = true
+		if !fields[edge.Entity][edge.Attr] && !x.IsReservedPredicate(edge.Attr) {
+			violations = append(violations, SchemaViolation{
+				Subject:   subject(edge.Entity),
+				Predicate: edge.Attr,
+				Type:      strictTypes[edge.Entity][0],
+				Error:     "not a field of the types of the node",
+			})
+		}
+	}
+
+	// The non-nullable fields must be set once the node is given the type, by the mutation or
+	// before it.
+	missing := make(map[string][]uint64)
+	for _, uid := range checked {
+		for _, name := range strictTypes[uid] {
+			if stored[uid][name] {
+				continue
+			}
+			typ, _ := schema.State().GetType(name)
+			for _, field := range typ.Fields {
+				if required(field) && !set[uid][field.Predicate] {
+					missing[field.Predicate] = append(missing[field.Predicate], uid)
+				}
+			}
+		}
+	}
+	preds := make([]string, 0, len(missing))
+	for pred := range missing {
+		preds = append(preds, pred)
+	}
+	sort.Strings(preds)
+	present := make(map[string]map[uint64]bool)
+	for _, pred := range preds {
+		vals, err := query.PredicateValues(ctx, pred, missing[pred], readTs)
+		if err != nil {
+			return err
+		}
+		present[pred] = make(map[uint64]bool)
+		for i, uid := range missing[pred] {
+			present[pred][uid] = len(vals[i]) > 0 && !cleared[uid][pred]
+		}
+	}
+	for _, uid := range checked {
+		for _, name := range strictTypes[uid] {
+			if stored[uid][name] {
+				continue
+			}
+			typ, _ := schema.State().GetType(name)
+			for _, field := range typ.Fields {
+				pred := field.Predicate
+				if required(field) && !set[uid][pred] && !present[pred][uid] {
+					violations = append(violations, SchemaViolation{
+						Subject:   subject(uid),
+						Predicate: pred,
+						Type:      name,
+						Error:     "non-nullable field isn't set",
+					})
+				}
+			}
+		}
+	}
+	return violationsError("Mutation doesn't fit the types of its nodes", violations)
+}
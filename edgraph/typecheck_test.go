@@ -0,0 +1,59 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestWithTypeEdges(t *testing.T) {
+	stored := map[uint64]map[string]bool{
+		1: {"Person": true, "Admin": true},
+		2: {"Pet": true},
+		3: {},
+	}
+	edges := []*pb.DirectedEdge{
+		{Entity: 1, Attr: "dgraph.type", Value: []byte("Admin"), Op: pb.DirectedEdge_DEL},
+		{Entity: 1, Attr: "name", Value: []byte("Ann"), Op: pb.DirectedEdge_SET},
+		{Entity: 2, Attr: "dgraph.type", Value: []byte(x.Star), Op: pb.DirectedEdge_DEL},
+		{Entity: 2, Attr: "dgraph.type", Value: []byte("Toy"), Op: pb.DirectedEdge_SET},
+		{Entity: 3, Attr: "dgraph.type", Value: []byte("Person"), Op: pb.DirectedEdge_SET},
+		{Entity: 4, Attr: "dgraph.type", Value: []byte("Person"), Op: pb.DirectedEdge_SET},
+	}
+	require.Equal(t, map[uint64]map[string]bool{
+		1: {"Person": true},
+		2: {"Toy": true},
+		3: {"Person": true},
+	}, withTypeEdges(stored, edges))
+
+	// The types at readTs are left as they were.
+	require.True(t, stored[1]["Admin"])
+	require.Empty(t, stored[3])
+}
+
+func TestRequiredField(t *testing.T) {
+	require.True(t, required(&pb.SchemaUpdate{NonNullable: true}))
+	require.False(t, required(&pb.SchemaUpdate{}))
+	// [T!] only tells that the items aren't null, while [T]! requires the list.
+	require.False(t, required(&pb.SchemaUpdate{List: true, NonNullable: true}))
+	require.True(t, required(&pb.SchemaUpdate{List: true, NonNullableList: true}))
+}
@@ -0,0 +1,305 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/dgryski/go-farm"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// checkTypeKeyIndexes checks that the key predicates of the types are indexed, by the schema
+// being altered or the current one, so that the nodes can be looked up by their key.
+func checkTypeKeyIndexes(ctx context.Context, result *schema.ParsedSchema) error {
+	var preds []string
+	for _, typ := range result.Types {
+		preds = append(preds, typ.Key...)
+	}
+	if len(preds) == 0 {
+		return nil
+	}
+
+	indexed := make(map[string]bool)
+	for _, update := range result.Preds {
+		indexed[update.Predicate] = update.Directive == pb.SchemaUpdate_INDEX
+	}
+	nodes, err := worker.GetSchemaOverNetwork(ctx, &pb.SchemaRequest{
+		Predicates: preds,
+		Fields:     []string{"index"},
+	})
+	if err != nil {
+		return err
+	}
+	for _, node := range nodes {
+		if _, ok := indexed[node.Predicate]; !ok {
+			indexed[node.Predicate] = node.Index
+		}
+	}
+	for _, typ := range result.Types {
+		for _, pred := range typ.Key {
+			if !indexed[pred] {
+				return errors.Errorf("Key predicate %s of type %s must be indexed",
+					pred, typ.TypeName)
+			}
+		}
+	}
+	return nil
+}
+
+// keyedNode is the state of a node changed by a mutation, after the mutation.
+type keyedNode struct {
+	types map[string]bool
+	vals  map[string]string
+	// added are the key predicates changed by an ADD edge, whose value isn't known.
+	added map[string]bool
+}
+
+// edgeString returns the value of the edge as a string, once converted to the type of the
+// predicate.
+func edgeString(edge *pb.DirectedEdge, tid types.TypeID) (string, error) {
+	val, err := types.Convert(types.Val{Tid: types.TypeID(edge.ValueType), Value: edge.Value}, tid)
+	if err != nil {
+		return "", err
+	}
+	return valString(val)
+}
+
+// valString returns the value as a string.
+func valString(val types.Val) (string, error) {
+	str := types.ValueForType(types.StringID)
+	if err := types.Marshal(val, &str); err != nil {
+		return "", err
+	}
+	return str.Value.(string), nil
+}
+
+// lookupKey returns the nodes of the type whose key predicates have the values at readTs.
+func lookupKey(ctx context.Context, typeName string, key, vals []string,
+	readTs uint64) ([]uint64, error) {
+
+	filters := []string{fmt.Sprintf("type(<%s>)", typeName)}
+	for i := 1; i < len(key); i++ {
+		filters = append(filters, fmt.Sprintf("eq(<%s>, %s)", key[i], strconv.Quote(vals[i])))
+	}
+	q := fmt.Sprintf("{ q(func: eq(<%s>, %s)) @filter(%s) { v as uid } }",
+		key[0], strconv.Quote(vals[0]), strings.Join(filters, " AND "))
+	parsed, err := gql.ParseWithNeedVars(gql.Request{
+		Str:       q,
+		Variables: make(map[string]string),
+	}, []string{"v"})
+	if err != nil {
+		return nil, errors.Wrapf(err, "while looking up the key of %s", typeName)
+	}
+	qr := query.Request{Latency: &query.Latency{}, GqlQuery: &parsed, ReadTs: readTs}
+	if err := qr.ProcessQuery(ctx); err != nil {
+		return nil, errors.Wrapf(err, "while looking up the key of %s", typeName)
+	}
+	if qr.Vars["v"].Uids == nil {
+		return nil, nil
+	}
+	return qr.Vars["v"].Uids.Uids, nil
+}
+
+// keyConflictKey returns the conflict key of a key tuple of a type, so that two transactions
+// giving the tuple to nodes conflict, even though neither sees the node of the other at its
+// readTs. It's formatted as the conflict keys of the posting lists, <fp>-<predicate>.
+func keyConflictKey(tuple, pred string) string {
+	return strconv.FormatUint(farm.Fingerprint64([]byte(tuple)), 36) + "-" + pred
+}
+
+// addConflictKeys adds the conflict keys to the context of a transaction, unless its mutations
+// failed before it was set.
+func addConflictKeys(tctx *api.TxnContext, keys []string) {
+	if tctx == nil {
+		return
+	}
+	for _, key := range keys {
+		if !x.HasString(tctx.Keys, key) {
+			tctx.Keys = append(tctx.Keys, key)
+		}
+	}
+}
+
+// checkTypeKeys checks that the edges of a mutation don't give two nodes of a type declared
+// with @key the same values of its key predicates. Only the nodes whose key predicates or types
+// are set by the mutation are checked, against the nodes existing at readTs and the other nodes
+// of the mutation. It returns the conflict keys of the key tuples checked, to be added to the
+// ones of the transaction.
+func checkTypeKeys(ctx context.Context, edges []*pb.DirectedEdge,
+	readTs uint64) ([]string, error) {
+	keys := make(map[string]pb.TypeUpdate)
+	keyPreds := make(map[string]types.TypeID)
+	for _, name := range schema.State().Types() {
+		typ, ok := schema.State().GetType(name)
+		if !ok || len(typ.Key) == 0 {
+			continue
+		}
+		keys[name] = typ
+		for _, field := range typ.Fields {
+			for _, pred := range typ.Key {
+				if field.Predicate == pred {
+					keyPreds[pred] = types.TypeID(field.ValueType)
+				}
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	touched := make(map[uint64]bool)
+	for _, edge := range edges {
+		if edge.Op == pb.DirectedEdge_DEL {
+			continue
+		}
+		_, isKey := keyPreds[edge.Attr]
+		_, isKeyed := keys[string(edge.Value)]
+		if isKey || edge.Attr == "dgraph.type" && isKeyed {
+			touched[edge.Entity] = true
+		}
+	}
+	if len(touched) == 0 {
+		return nil, nil
+	}
+	uids := make([]uint64, 0, len(touched))
+	for uid := range touched {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	// The nodes start from their state at readTs, and the edges of the mutation are applied.
+	stored, err := nodeTypes(ctx, uids, readTs)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make(map[uint64]*keyedNode, len(uids))
+	for uid, types := range withTypeEdges(stored, edges) {
+		nodes[uid] = &keyedNode{
+			types: types,
+			vals:  make(map[string]string),
+			added: make(map[string]bool),
+		}
+	}
+	for pred := range keyPreds {
+		predVals, err := query.PredicateValues(ctx, pred, uids, readTs)
+		if err != nil {
+			return nil, err
+		}
+		for i, vals := range predVals {
+			if len(vals) == 0 {
+				continue
+			}
+			if nodes[uids[i]].vals[pred], err = valString(vals[0]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, edge := range edges {
+		node, ok := nodes[edge.Entity]
+		if !ok {
+			continue
+		}
+		tid, ok := keyPreds[edge.Attr]
+		if !ok {
+			continue
+		}
+		switch edge.Op {
+		case pb.DirectedEdge_DEL:
+			if bytes.Equal(edge.Value, []byte(x.Star)) {
+				delete(node.vals, edge.Attr)
+				continue
+			}
+			if val, err := edgeString(edge, tid); err == nil && val == node.vals[edge.Attr] {
+				delete(node.vals, edge.Attr)
+			}
+		case pb.DirectedEdge_ADD:
+			node.added[edge.Attr] = true
+		default:
+			if node.vals[edge.Attr], err = edgeString(edge, tid); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	owners := make(map[string]uint64)
+	var conflicts []string
+	for _, uid := range uids {
+		node := nodes[uid]
+		var names []string
+		for name := range node.types {
+			if _, ok := keys[name]; ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	Types:
+		for _, name := range names {
+			key := keys[name].Key
+			vals := make([]string, len(key))
+			for i, pred := range key {
+				if node.added[pred] {
+					return nil, errors.Errorf("Key predicate %s of type %s can't be changed by"+
+						" an addition", pred, name)
+				}
+				val, ok := node.vals[pred]
+				if !ok {
+					// The key is only enforced once all of its predicates are set.
+					continue Types
+				}
+				vals[i] = val
+			}
+			quoted := make([]string, len(vals))
+			for i, val := range vals {
+				quoted[i] = strconv.Quote(val)
+			}
+			tuple := fmt.Sprintf("%s(%s)", name, strings.Join(quoted, ", "))
+			if other, ok := owners[tuple]; ok {
+				return nil, errors.Errorf("Key %s is given to both nodes %#x and %#x",
+					tuple, other, uid)
+			}
+			owners[tuple] = uid
+			conflicts = append(conflicts, keyConflictKey(tuple, key[0]))
+
+			existing, err := lookupKey(ctx, name, key, vals, readTs)
+			if err != nil {
+				return nil, err
+			}
+			for _, other := range existing {
+				// The nodes changed by the mutation are checked with their new key.
+				if !touched[other] {
+					return nil, errors.Errorf("Key %s of node %#x is already used by node %#x",
+						tuple, uid, other)
+				}
+			}
+		}
+	}
+	return conflicts, nil
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/dgraph-io/dgraph/x"
+	"golang.org/x/net/context"
+)
+
+// bootID tells apart the query versions of two runs of this Alpha, as the commit ts of the
+// predicates are only kept in memory.
+var bootID = time.Now().UnixNano()
+
+// unversionedQuery tells if the result of the query may change without a commit to the
+// predicates it names: either it reads predicates it doesn't name, like expand(_all_), or its
+// result is random, like a sample without a seed.
+func unversionedQuery(gqs []*gql.GraphQuery) (unnamed, random bool) {
+	checkFunc := func(f *gql.Function) {
+		if f == nil {
+			return
+		}
+		// A pattern matches several predicates, and custom functions call out to a resolver.
+		unnamed = unnamed || strings.ContainsAny(f.Attr, "*?")
+		random = random || f.Name == "custom"
+	}
+	var checkFilter func(ft *gql.FilterTree)
+	checkFilter = func(ft *gql.FilterTree) {
+		if ft == nil {
+			return
+		}
+		checkFunc(ft.Func)
+		for _, child := range ft.Child {
+			checkFilter(child)
+		}
+	}
+	var walk func(gq *gql.GraphQuery)
+	walk = func(gq *gql.GraphQuery) {
+		unnamed = unnamed || gq.Expand != ""
+		checkFunc(gq.Func)
+		checkFilter(gq.Filter)
+		if gq.Args["orderrandom"] == "true" ||
+			(gq.SampleArgs.Count > 0 || gq.SampleArgs.Percent > 0) && !gq.SampleArgs.HasSeed {
+			random = true
+		}
+		for _, child := range gq.Children {
+			walk(child)
+		}
+	}
+	for _, gq := range gqs {
+		walk(gq)
+	}
+	return unnamed, random
+}
+
+// QueryVersion returns a version of the result of the query, which changes whenever a commit,
+// a schema change or a change of the transforms may have changed the result. The version is
+// derived from the commit ts of the predicates the query reads, so the query doesn't need to
+// run. It's empty for queries reading predicates they don't name, or whose result may change
+// without a commit, like samples without a seed. The read ts of the request is picked if it has
+// none, so that the query reads the data the version stands for.
+func QueryVersion(ctx context.Context, req *api.Request) (string, error) {
+	parsed, err := parseQuery(gql.Request{Str: req.Query, Variables: req.Vars})
+	if err != nil {
+		return "", err
+	}
+	if unnamed, random := unversionedQuery(parsed.Query); unnamed || random {
+		return "", nil
+	}
+
+	if req.StartTs == 0 {
+		if req.BestEffort {
+			req.StartTs = posting.Oracle().MaxAssigned()
+		} else {
+			req.StartTs = State.getTimestamp(req.ReadOnly)
+		}
+	}
+	// Once the commits up to the read ts are applied, their commit ts are all noted.
+	if err := posting.Oracle().WaitForTs(ctx, req.StartTs); err != nil {
+		return "", err
+	}
+	generation := posting.SchemaGeneration()
+
+	preds := append(queryPredicates(parsed.Query), "dgraph.type")
+	if x.WorkerConfig.AclEnabled {
+		// The ACL rules change the result of any query.
+		preds = append(preds, "dgraph.group.acl", "dgraph.user.group")
+	}
+	var ts uint64
+	for _, pred := range preds {
+		ts = x.Max(ts, worker.PredicateVersion(pred, req.StartTs))
+	}
+	return fmt.Sprintf("%x-%d-%d-%d", bootID, generation, transformsVersion(), ts), nil
+}
@@ -0,0 +1,54 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnversionedQuery(t *testing.T) {
+	for _, tc := range []struct {
+		query           string
+		unnamed, random bool
+	}{
+		{`{ q(func: has(name)) { name friend { age } } }`, false, false},
+		{`{ q(func: has(name)) { expand(_all_) } }`, true, false},
+		{`{ q(func: has("nam*")) { uid } }`, true, false},
+		{`{ q(func: has(name), orderrandom: true) { name } }`, false, true},
+		{`{ q(func: has(name), orderrandom: 7) { name } }`, false, false},
+	} {
+		res, err := gql.Parse(gql.Request{Str: tc.query})
+		require.NoError(t, err, tc.query)
+		unnamed, random := unversionedQuery(res.Query)
+		require.Equal(t, tc.unnamed, unnamed, tc.query)
+		require.Equal(t, tc.random, random, tc.query)
+	}
+
+	res, err := gql.Parse(gql.Request{Str: `{
+		var(func: has(name)) { a as age }
+		q(func: uid(a), orderasc: val(a)) @filter(eq(val(a), 3)) { name score: math(a + 1) }
+		r(func: has(name), orderdesc: age) { count(uid) }
+	}`})
+	require.NoError(t, err)
+	preds := queryPredicates(res.Query)
+	sort.Strings(preds)
+	require.Equal(t, []string{"age", "name"}, preds)
+}
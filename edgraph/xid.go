@@ -0,0 +1,295 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/query"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+// xidPrefix marks a node in a mutation that is addressed by its external id, either as
+// <x:pred:xid> or <x:xid> when a single predicate is declared with @xid.
+const xidPrefix = "x:"
+
+type xidRef struct {
+	pred string
+	xid  string
+}
+
+// parseXidRef splits a node reference that starts with xidPrefix into the @xid predicate
+// and the external id.
+func parseXidRef(ref string, preds []string) (xidRef, error) {
+	rest := strings.TrimPrefix(ref, xidPrefix)
+	if idx := strings.IndexByte(rest, ':'); idx > 0 {
+		for _, pred := range preds {
+			if pred == rest[:idx] {
+				return xidRef{pred: pred, xid: rest[idx+1:]}, nil
+			}
+		}
+	}
+	pred, err := query.DefaultXidPredicate(preds)
+	if err != nil {
+		return xidRef{}, errors.Wrapf(err, "while resolving %q", ref)
+	}
+	if rest == "" {
+		return xidRef{}, errors.Errorf("Empty external id in %q", ref)
+	}
+	return xidRef{pred: pred, xid: rest}, nil
+}
+
+// collectXidRefs returns all the nodes of the mutation addressed by an external id.
+func collectXidRefs(gmu *gql.Mutation, preds []string) (map[string]xidRef, error) {
+	refs := make(map[string]xidRef)
+	add := func(s string) error {
+		if !strings.HasPrefix(s, xidPrefix) {
+			return nil
+		}
+		if _, ok := refs[s]; ok {
+			return nil
+		}
+		ref, err := parseXidRef(s, preds)
+		if err != nil {
+			return err
+		}
+		refs[s] = ref
+		return nil
+	}
+	for _, nquads := range [][]*api.NQuad{gmu.Set, gmu.Del} {
+		for _, nq := range nquads {
+			if err := add(nq.Subject); err != nil {
+				return nil, err
+			}
+			if err := add(nq.ObjectId); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return refs, nil
+}
+
+// lookupXids returns the uid of the xids of pred that already exist at readTs.
+func lookupXids(ctx context.Context, pred string, xids []string,
+	readTs uint64) (map[string]uint64, error) {
+
+	// The xids are passed as variables, one per xid, so that they're never parsed as DQL.
+	decls := make([]string, len(xids))
+	names := make([]string, len(xids))
+	vars := make(map[string]string, len(xids))
+	for i, xid := range xids {
+		names[i] = fmt.Sprintf("$xid%d", i)
+		decls[i] = names[i] + ": string"
+		vars[names[i]] = xid
+	}
+	q := fmt.Sprintf("query xids(%s) { q(func: eq(<%s>, [%s])) { v as <%s> } }",
+		strings.Join(decls, ", "), pred, strings.Join(names, ", "), pred)
+	parsed, err := gql.ParseWithNeedVars(gql.Request{
+		Str:       q,
+		Variables: vars,
+	}, []string{"v"})
+	if err != nil {
+		return nil, errors.Wrapf(err, "while looking up xids of %s", pred)
+	}
+	qr := query.Request{Latency: &query.Latency{}, GqlQuery: &parsed, ReadTs: readTs}
+	if err := qr.ProcessQuery(ctx); err != nil {
+		return nil, errors.Wrapf(err, "while looking up xids of %s", pred)
+	}
+
+	uids := make(map[string]uint64, len(xids))
+	for uid, val := range qr.Vars["v"].Vals {
+		xid, ok := val.Value.(string)
+		if !ok {
+			continue
+		}
+		// The @upsert directive keeps xids unique, pick the lowest uid in case
+		// duplicates were loaded before the predicate was declared with @xid.
+		if prev, ok := uids[xid]; !ok || uid < prev {
+			uids[xid] = uid
+		}
+	}
+	return uids, nil
+}
+
+// applyXidRefs replaces the nodes addressed by an external id with their uid. Nodes that
+// don't exist yet become blank nodes, and the xid is set on them so that the next mutation
+// finds them. N-Quads deleting a node that doesn't exist are dropped.
+func applyXidRefs(gmu *gql.Mutation, refs map[string]xidRef, uids map[string]string) {
+	created := make(map[string]bool)
+	node := func(s string) (string, bool) {
+		if _, ok := refs[s]; !ok {
+			return s, true
+		}
+		if uid, ok := uids[s]; ok {
+			return uid, true
+		}
+		return "_:" + s, false
+	}
+
+	set := gmu.Set[:0]
+	var newNodes []*api.NQuad
+	for _, nq := range gmu.Set {
+		for _, s := range []string{nq.Subject, nq.ObjectId} {
+			if _, found := node(s); found || created[s] {
+				continue
+			}
+			created[s] = true
+			ref := refs[s]
+			newNodes = append(newNodes, &api.NQuad{
+				Subject:     "_:" + s,
+				Predicate:   ref.pred,
+				ObjectValue: &api.Value{Val: &api.Value_StrVal{StrVal: ref.xid}},
+			})
+		}
+		nq.Subject, _ = node(nq.Subject)
+		nq.ObjectId, _ = node(nq.ObjectId)
+		set = append(set, nq)
+	}
+	gmu.Set = append(set, newNodes...)
+
+	del := gmu.Del[:0]
+	for _, nq := range gmu.Del {
+		s, sok := node(nq.Subject)
+		o, ook := node(nq.ObjectId)
+		if !sok || !ook {
+			continue
+		}
+		nq.Subject, nq.ObjectId = s, o
+		del = append(del, nq)
+	}
+	gmu.Del = del
+}
+
+// resolveXids resolves the nodes of the mutation addressed by an external id.
+func resolveXids(ctx context.Context, gmu *gql.Mutation, readTs uint64) error {
+	var hasRefs bool
+	for _, nquads := range [][]*api.NQuad{gmu.Set, gmu.Del} {
+		for _, nq := range nquads {
+			if strings.HasPrefix(nq.Subject, xidPrefix) ||
+				strings.HasPrefix(nq.ObjectId, xidPrefix) {
+				hasRefs = true
+			}
+		}
+	}
+	if !hasRefs {
+		return nil
+	}
+
+	preds, err := query.XidPredicates(ctx)
+	if err != nil {
+		return err
+	}
+	refs, err := collectXidRefs(gmu, preds)
+	if err != nil {
+		return err
+	}
+
+	byPred := make(map[string][]string)
+	for _, ref := range refs {
+		byPred[ref.pred] = append(byPred[ref.pred], ref.xid)
+	}
+	found := make(map[xidRef]uint64)
+	for pred, xids := range byPred {
+		res, err := lookupXids(ctx, pred, xids, readTs)
+		if err != nil {
+			return err
+		}
+		for xid, uid := range res {
+			found[xidRef{pred: pred, xid: xid}] = uid
+		}
+	}
+
+	uids := make(map[string]string, len(found))
+	for s, ref := range refs {
+		if uid, ok := found[ref]; ok {
+			uids[s] = strconv.FormatUint(uid, 10)
+		}
+	}
+	applyXidRefs(gmu, refs, uids)
+	return nil
+}
+
+// xidPredicate checks that pred is declared with @xid. If pred is empty, it returns the single
+// predicate declared with @xid.
+func xidPredicate(ctx context.Context, pred string) (string, error) {
+	preds, err := query.XidPredicates(ctx)
+	if err != nil {
+		return "", err
+	}
+	if pred == "" {
+		return query.DefaultXidPredicate(preds)
+	}
+	for _, p := range preds {
+		if p == pred {
+			return pred, nil
+		}
+	}
+	return "", errors.Errorf("Predicate %s is not declared with @xid", pred)
+}
+
+// ResolveXids returns the uid, in hex, of each of the external ids of pred. If pred is
+// empty, the single predicate declared with @xid is used. When create is set, the missing
+// xids are assigned new uids in one transaction, otherwise they are left out of the result.
+// Concurrent creation of the same xid aborts one of the transactions, which can be retried.
+func (s *Server) ResolveXids(ctx context.Context, pred string, xids []string,
+	create bool) (map[string]string, error) {
+
+	pred, err := xidPredicate(ctx, pred)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(map[string]string, len(xids))
+	if len(xids) == 0 {
+		return res, nil
+	}
+	uids, err := lookupXids(ctx, pred, xids, State.getTimestamp(true))
+	if err != nil {
+		return nil, err
+	}
+	var missing []*api.NQuad
+	seen := make(map[string]bool)
+	for _, xid := range xids {
+		if uid, ok := uids[xid]; ok {
+			res[xid] = fmt.Sprintf("%#x", uid)
+		} else if create && !seen[xid] {
+			seen[xid] = true
+			missing = append(missing, &api.NQuad{
+				Subject:     "_:" + xid,
+				Predicate:   pred,
+				ObjectValue: &api.Value{Val: &api.Value_StrVal{StrVal: xid}},
+			})
+		}
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+
+	resp, err := s.doMutate(ctx, &api.Mutation{Set: missing, CommitNow: true}, true, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while creating xids of %s", pred)
+	}
+	for xid, uid := range resp.Uids {
+		res[xid] = uid
+	}
+	return res, nil
+}
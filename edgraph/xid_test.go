@@ -0,0 +1,73 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package edgraph
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseXidRef(t *testing.T) {
+	preds := []string{"email", "username"}
+	ref, err := parseXidRef("x:email:a@b.com", preds)
+	require.NoError(t, err)
+	require.Equal(t, xidRef{pred: "email", xid: "a@b.com"}, ref)
+
+	_, err = parseXidRef("x:user-1", preds)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "More than one predicate is declared with @xid")
+
+	ref, err = parseXidRef("x:urn:isbn:123", []string{"username"})
+	require.NoError(t, err)
+	require.Equal(t, xidRef{pred: "username", xid: "urn:isbn:123"}, ref)
+
+	_, err = parseXidRef("x:user-1", nil)
+	require.Error(t, err)
+	_, err = parseXidRef("x:", []string{"username"})
+	require.Error(t, err)
+}
+
+func TestApplyXidRefs(t *testing.T) {
+	gmu := &gql.Mutation{
+		Set: []*api.NQuad{
+			makeNquad("x:user-1", "name", &api.Value{Val: &api.Value_StrVal{StrVal: "Alice"}}),
+			makeNquadEdge("x:user-1", "friend", "x:user-2"),
+			makeNquadEdge("x:user-3", "friend", "x:user-2"),
+		},
+		Del: []*api.NQuad{
+			makeNquadEdge("x:user-1", "friend", "x:user-3"),
+			makeNquadEdge("x:user-2", "friend", "x:user-1"),
+		},
+	}
+	refs, err := collectXidRefs(gmu, []string{"username"})
+	require.NoError(t, err)
+	require.Len(t, refs, 3)
+	require.Equal(t, xidRef{pred: "username", xid: "user-2"}, refs["x:user-2"])
+
+	applyXidRefs(gmu, refs, map[string]string{"x:user-1": "10", "x:user-2": "11"})
+	require.Equal(t, []*api.NQuad{
+		makeNquad("10", "name", &api.Value{Val: &api.Value_StrVal{StrVal: "Alice"}}),
+		makeNquadEdge("10", "friend", "11"),
+		makeNquadEdge("_:x:user-3", "friend", "11"),
+		makeNquad("_:x:user-3", "username", &api.Value{Val: &api.Value_StrVal{StrVal: "user-3"}}),
+	}, gmu.Set)
+	// user-3 doesn't exist yet, so it has no edges to delete.
+	require.Equal(t, []*api.NQuad{makeNquadEdge("11", "friend", "10")}, gmu.Del)
+}
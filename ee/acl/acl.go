@@ -421,8 +421,8 @@ func chMod(conf *viper.Viper) error {
 		return errors.Errorf("one of --pred or --pred_regex must be specified, but not both")
 	case len(predicate) == 0 && len(predRegex) == 0:
 		return errors.Errorf("one of --pred or --pred_regex must be specified, but not both")
-	case perm > 7:
-		return errors.Errorf("the perm value must be less than or equal to 7, "+
+	case perm > 15:
+		return errors.Errorf("the perm value must be less than or equal to 15, "+
 			"the provided value is %d", perm)
 	case len(predRegex) > 0:
 		// make sure the predRegex can be compiled as a regex
@@ -114,7 +114,8 @@ func initSubcommands() []*x.SubCommand {
 	modFlags.StringP("pred_regex", "P", "", "The regular expression specifying predicates"+
 		" whose acls are to be changed")
 	modFlags.IntP("perm", "m", 0, "The acl represented using "+
-		"an integer: 4 for read, 2 for write, and 1 for modify. Use a negative value to remove a "+
+		"an integer: 4 for read, 2 for write, 1 for modify and 8 to unmask the values of a "+
+		"@masked predicate. Use a negative value to remove a "+
 		"predicate from the group")
 
 	var cmdInfo x.SubCommand
@@ -59,6 +59,11 @@ var (
 		Code: 1,
 		Name: "Modify",
 	}
+	// Unmask is used when reading the raw values of a @masked predicate.
+	Unmask = &Operation{
+		Code: 8,
+		Name: "Unmask",
+	}
 )
 
 // User represents a user in the ACL system.
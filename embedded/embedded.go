@@ -0,0 +1,135 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package embedded runs Dgraph inside a Go program, like Badger: queries, mutations and schema
+// changes are function calls, and nothing listens on the network.
+package embedded
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/dgraph/cmd/alpha"
+	"github.com/dgraph-io/dgraph/edgraph"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+)
+
+// Options are the options of an embedded Dgraph.
+type Options struct {
+	// Dir is the directory storing the data. It's created if it doesn't exist.
+	Dir string
+	// Flags overrides the options of dgraph alpha by flag name, e.g. "lru_mb": "1024".
+	Flags map[string]string
+}
+
+// DB is a Dgraph instance running in this process. Only one can be open at a time.
+type DB struct {
+	server edgraph.Server
+	stop   func()
+}
+
+var open struct {
+	sync.Mutex
+	db *DB
+}
+
+// Open starts Dgraph in this process with its data in opts.Dir, and waits until it's ready to
+// serve requests or ctx is done.
+func Open(ctx context.Context, opts Options) (*DB, error) {
+	open.Lock()
+	defer open.Unlock()
+	if open.db != nil {
+		return nil, errors.Errorf("A Dgraph instance is already open in this process")
+	}
+	if len(opts.Dir) == 0 {
+		return nil, errors.Errorf("The directory of the data is required")
+	}
+	if err := os.MkdirAll(opts.Dir, 0700); err != nil {
+		return nil, errors.Wrapf(err, "while creating directory %s", opts.Dir)
+	}
+	if _, ok := opts.Flags["lru_mb"]; !ok {
+		flags := map[string]string{"lru_mb": "1024"}
+		for name, value := range opts.Flags {
+			flags[name] = value
+		}
+		opts.Flags = flags
+	}
+
+	stop, err := alpha.StartEmbedded(opts.Dir, opts.Flags)
+	if err != nil {
+		return nil, err
+	}
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for x.HealthCheck() != nil {
+		select {
+		case <-ctx.Done():
+			stop()
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	open.db = &DB{stop: stop}
+	return open.db, nil
+}
+
+// Query runs the query of req, and the mutations of req for upserts.
+func (db *DB) Query(ctx context.Context, req *api.Request) (*api.Response, error) {
+	return db.server.Query(ctx, req)
+}
+
+// Mutate runs the mutation. The transaction is committed if mu.CommitNow is set, or else by
+// CommitOrAbort.
+func (db *DB) Mutate(ctx context.Context, mu *api.Mutation) (*api.Assigned, error) {
+	return db.server.Mutate(ctx, mu)
+}
+
+// CommitOrAbort commits the transaction, or aborts it if tc.Aborted is set.
+func (db *DB) CommitOrAbort(ctx context.Context, tc *api.TxnContext) (*api.TxnContext, error) {
+	return db.server.CommitOrAbort(ctx, tc)
+}
+
+// Alter changes the schema, or drops data.
+func (db *DB) Alter(ctx context.Context, op *api.Operation) error {
+	_, err := db.server.Alter(ctx, op)
+	return err
+}
+
+// UidsExist returns the bitmap of the uids which are nodes with any predicate: the bit i%8 of
+// the byte i/8 is set if uids[i] exists.
+func (db *DB) UidsExist(ctx context.Context, uids []uint64) ([]byte, error) {
+	return db.server.UidsExist(ctx, uids)
+}
+
+// XidsExist returns the bitmap of the external ids of pred which exist, like UidsExist.
+func (db *DB) XidsExist(ctx context.Context, pred string, xids []string) ([]byte, error) {
+	return db.server.XidsExist(ctx, pred, xids)
+}
+
+// Close stops Dgraph, once the pending requests are done. Another DB can be opened after.
+func (db *DB) Close() {
+	open.Lock()
+	defer open.Unlock()
+	if open.db != db {
+		return
+	}
+	db.stop()
+	open.db = nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmbedded(t *testing.T) {
+	dir, err := ioutil.TempDir("", "embedded")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	db, err := Open(ctx, Options{Dir: dir})
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = Open(ctx, Options{Dir: dir})
+	require.Error(t, err)
+
+	require.NoError(t, db.Alter(ctx, &api.Operation{Schema: "name: string @index(exact) ."}))
+	assigned, err := db.Mutate(ctx, &api.Mutation{
+		SetNquads: []byte(`
+			_:c <name> "Carol" .
+			_:a <name> "Alice" .
+			_:b <name> "Bob" .
+			_:a <friend> _:c .`),
+		CommitNow: true,
+	})
+	require.NoError(t, err)
+	// The uids are assigned in the order the blank nodes show up, from the first uid.
+	require.Equal(t, map[string]string{"c": "0x1", "a": "0x2", "b": "0x3"}, assigned.Uids)
+
+	resp, err := db.Query(ctx, &api.Request{
+		Query: `{ q(func: eq(name, "Alice")) { name } }`,
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"q": [{"name": "Alice"}]}`, string(resp.Json))
+
+	// The first count is done by the worker, the second one filters the uids.
+	resp, err = db.Query(ctx, &api.Request{Query: `{
+		all(func: has(name)) { count(uid) }
+		bob(func: has(name)) @filter(eq(name, "Bob")) { n: count(uid) }
+		approx(func: eq(name, ["Alice", "Bob"])) @approximate { count(uid) }
+		sample(func: has(name)) @sample(n: 2, seed: 1) { count(uid) }
+	}`})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"all": [{"count": 3}], "bob": [{"n": 1}], "approx": [{"count": 2}],
+		"sample": [{"count": 2}]}`, string(resp.Json))
+
+	resp, err = db.Query(ctx, &api.Request{Query: `{
+		subgraph(from: 0x2, depth: 2) { friend name }
+	}`})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"subgraph": {
+		"nodes": [{"uid": "0x2", "name": "Alice"}, {"uid": "0x1", "name": "Carol"}],
+		"edges": [{"from": "0x2", "to": "0x1", "predicate": "friend"}]}}`, string(resp.Json))
+
+	// A seeded random order is the same for each query.
+	random := func() string {
+		resp, err := db.Query(ctx, &api.Request{Query: `{
+			q(func: has(name), orderrandom: 7, first: 2) { name }
+		}`})
+		require.NoError(t, err)
+		var r struct{ Q []struct{ Name string } }
+		require.NoError(t, json.Unmarshal(resp.Json, &r))
+		require.Len(t, r.Q, 2)
+		return string(resp.Json)
+	}
+	require.Equal(t, random(), random())
+
+	distinct := func(expected string) {
+		resp, err := db.Query(ctx, &api.Request{Query: `{
+			var(func: has(name)) { n as name }
+			names() { approx_count_distinct(val(n)) }
+			q(func: has(name)) @approximate { approx_count_distinct(name) }
+		}`})
+		require.NoError(t, err)
+		require.JSONEq(t, expected, string(resp.Json))
+	}
+	distinct(`{"names": [{"approx_count_distinct(val(n))": 3}],
+		"q": [{"approx_count_distinct(name)": 3}]}`)
+	// The sketch of the predicate is updated by the mutations.
+	_, err = db.Mutate(ctx, &api.Mutation{
+		SetNquads: []byte(`
+			_:d <name> "Alice" .
+			_:e <name> "Erin" .`),
+		CommitNow: true,
+	})
+	require.NoError(t, err)
+	distinct(`{"names": [{"approx_count_distinct(val(n))": 4}],
+		"q": [{"approx_count_distinct(name)": 4}]}`)
+
+	// 0x1 to 0x3 exist, but not 0x0 and 0x10.
+	exists, err := db.UidsExist(ctx, []uint64{0x3, 0x10, 0x1, 0x0, 0x2, 0x3})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x35}, exists)
+
+	require.NoError(t, db.Alter(ctx, &api.Operation{
+		Schema: "name: string @index(exact) @upsert @xid ."}))
+	exists, err = db.XidsExist(ctx, "", []string{"Bob", "Dave", "Alice"})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x5}, exists)
+
+	// The xids are matched as they are, even when they look like DQL.
+	xid := `Eve\"]) { uid } } { all(func: has(name)`
+	setJson, err := json.Marshal(map[string]string{"name": xid})
+	require.NoError(t, err)
+	_, err = db.Mutate(ctx, &api.Mutation{SetJson: setJson, CommitNow: true})
+	require.NoError(t, err)
+	exists, err = db.XidsExist(ctx, "", []string{"Eve", xid})
+	require.NoError(t, err)
+	require.Equal(t, []byte{0x2}, exists)
+}
@@ -20,6 +20,7 @@ import (
 	"bytes"
 	"strconv"
 	"strings"
+	"unicode"
 
 	"github.com/dgraph-io/dgraph/lex"
 	"github.com/dgraph-io/dgraph/types"
@@ -58,14 +59,23 @@ func (s *mathTreeStack) peek() *MathTree {
 type MathTree struct {
 	Fn    string
 	Var   string
-	Const types.Val // This will always be parsed as a float value
+	Const types.Val // This is parsed as a float value, unless it's a quoted string.
 	Val   map[uint64]types.Val
 	Child []*MathTree
+
+	// nargs is the number of arguments passed to a function written as fn(a, b, ...).
+	nargs int
 }
 
 func isUnary(f string) bool {
 	return f == "exp" || f == "ln" || f == "u-" || f == "sqrt" ||
-		f == "floor" || f == "ceil" || f == "since"
+		f == "floor" || f == "ceil" || f == "since" || f == "abs" || f == "round" ||
+		f == "lower" || f == "upper" || f == "trim" || f == "len"
+}
+
+func isStringFunc(f string) bool {
+	return f == "concat" || f == "substr" || f == "lower" || f == "upper" ||
+		f == "trim" || f == "len"
 }
 
 func isBinaryMath(f string) bool {
@@ -76,6 +86,49 @@ func isTernary(f string) bool {
 	return f == "cond"
 }
 
+// mathArity returns the number of arguments a math function takes by default.
+func mathArity(f string) int {
+	switch {
+	case isUnary(f):
+		return 1
+	case isTernary(f):
+		return 3
+	}
+	return 2
+}
+
+// checkMathArgs verifies the number of arguments passed to functions which accept a
+// variable number of them: floor, ceil and round take an optional precision, min and
+// max take two or more values, cond takes a chain of condition, value pairs followed
+// by a default value, concat takes any number of strings and substr an optional length.
+func checkMathArgs(f string, n int) error {
+	switch f {
+	case "floor", "ceil", "round":
+		if n == 2 {
+			return nil
+		}
+	case "concat":
+		if n >= 1 {
+			return nil
+		}
+	case "substr":
+		if n == 3 {
+			return nil
+		}
+	case "min", "max":
+		if n >= 2 {
+			return nil
+		}
+	case "cond":
+		if n >= 3 && n%2 == 1 {
+			return nil
+		}
+		return errors.Errorf("Function cond expects an odd number of arguments (at least 3)."+
+			" But got: %d", n)
+	}
+	return errors.Errorf("Function %v expects %d arguments. But got: %d", f, mathArity(f), n)
+}
+
 func isZero(f string, rval types.Val) bool {
 	if rval.Tid != types.FloatID {
 		return false
@@ -100,7 +153,18 @@ func evalMathStack(opStack, valueStack *mathTreeStack) error {
 	if err != nil {
 		return errors.Errorf("Invalid Math expression")
 	}
-	if isUnary(topOp.Fn) {
+	if n := topOp.nargs; n > 0 && n != mathArity(topOp.Fn) {
+		if err := checkMathArgs(topOp.Fn, n); err != nil {
+			return err
+		}
+		if valueStack.size() < n {
+			return errors.Errorf("Invalid Math expression. Expected %d operands", n)
+		}
+		topOp.Child = make([]*MathTree, n)
+		for i := n - 1; i >= 0; i-- {
+			topOp.Child[i] = valueStack.popAssert()
+		}
+	} else if isUnary(topOp.Fn) {
 		// Since "not" is a unary operator, just pop one value.
 		topVal, err := valueStack.pop()
 		if err != nil {
@@ -148,7 +212,7 @@ func isMathFunc(f string) bool {
 		f == "==" || f == "!=" ||
 		f == "min" || f == "max" || f == "sqrt" ||
 		f == "pow" || f == "logbase" || f == "floor" || f == "ceil" ||
-		f == "since"
+		f == "since" || f == "abs" || f == "round" || isStringFunc(f)
 }
 
 func parseMathFunc(it *lex.ItemIterator, again bool) (*MathTree, bool, error) {
@@ -199,16 +263,23 @@ func parseMathFunc(it *lex.ItemIterator, again bool) (*MathTree, bool, error) {
 			if peekIt[0].Typ == itemLeftRound {
 				again := false
 				var child *MathTree
+				nargs := 0
 				for {
 					child, again, err = parseMathFunc(it, again)
 					if err != nil {
 						return nil, false, err
 					}
 					valueStack.push(child)
+					nargs++
 					if !again {
 						break
 					}
 				}
+				if unicode.IsLetter(rune(op[0])) {
+					// Only named functions take a list of arguments, for operators the
+					// parentheses just group an expression.
+					opStack.peek().nargs = nargs
+				}
 			}
 		} else if item.Typ == itemName { // Value.
 			peekIt, err := it.Peek(1)
@@ -235,6 +306,15 @@ func parseMathFunc(it *lex.ItemIterator, again bool) (*MathTree, bool, error) {
 			}
 			// Try to parse it as a constant.
 			child := &MathTree{}
+			if len(item.Val) > 0 && item.Val[0] == quote {
+				str, err := unquoteIfQuoted(item.Val)
+				if err != nil {
+					return nil, false, err
+				}
+				child.Const = types.Val{Tid: types.StringID, Value: str}
+				valueStack.push(child)
+				continue
+			}
 			v, err := strconv.ParseFloat(item.Val, 64)
 			if err != nil {
 				child.Var = item.Val
@@ -339,6 +419,10 @@ func (t *MathTree) stringHelper(buf *bytes.Buffer) {
 	}
 	if t.Const.Value != nil {
 		// Leaf node.
+		if str, ok := t.Const.Value.(string); ok {
+			buf.WriteString(strconv.Quote(str))
+			return
+		}
 		buf.WriteString(strconv.FormatFloat(t.Const.Value.(float64), 'E', -1, 64))
 		return
 	}
@@ -347,7 +431,8 @@ func (t *MathTree) stringHelper(buf *bytes.Buffer) {
 	switch t.Fn {
 	case "+", "-", "/", "*", "%", "exp", "ln", "cond", "min",
 		"sqrt", "max", "<", ">", "<=", ">=", "==", "!=", "u-",
-		"logbase", "pow":
+		"logbase", "pow", "floor", "ceil", "round", "abs", "since",
+		"concat", "substr", "lower", "upper", "trim", "len":
 		buf.WriteString(t.Fn)
 	default:
 		x.Fatalf("Unknown operator: %q", t.Fn)
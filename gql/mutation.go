@@ -18,6 +18,7 @@ package gql
 
 import (
 	"strconv"
+	"strings"
 
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgraph/protos/pb"
@@ -140,6 +141,105 @@ func (nq NQuad) CreateValueEdge(subjectUid uint64) (*pb.DirectedEdge, error) {
 	return out, nil
 }
 
+// IsValueOp returns true if the object is an operation on the current value of the predicate
+// instead of a node, i.e. add(<n>) or cas(<expected>, <new>).
+func IsValueOp(objectId string) bool {
+	return (strings.HasPrefix(objectId, "add(") || strings.HasPrefix(objectId, "cas(")) &&
+		strings.HasSuffix(objectId, ")")
+}
+
+// parseObjectFuncArgs returns the arguments of a function used as the object of an N-Quad,
+// e.g. cas("in stock", "sold"). The arguments are either quoted strings or bare values.
+func parseObjectFuncArgs(objectId string) ([]string, error) {
+	var args []string
+	for rest := objectId[strings.IndexByte(objectId, '(')+1 : len(objectId)-1]; ; {
+		rest = strings.TrimSpace(rest)
+		var arg string
+		if strings.HasPrefix(rest, `"`) {
+			end := 1
+			for ; end < len(rest) && rest[end] != '"'; end++ {
+				if rest[end] == '\\' {
+					end++
+				}
+			}
+			if end >= len(rest) {
+				return nil, errors.Errorf("Unterminated string in %s", objectId)
+			}
+			var err error
+			if arg, err = strconv.Unquote(rest[:end+1]); err != nil {
+				return nil, errors.Wrapf(err, "while parsing %s", objectId)
+			}
+			rest = strings.TrimSpace(rest[end+1:])
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			arg, rest = strings.TrimSpace(rest[:end]), rest[end:]
+		}
+		args = append(args, arg)
+		if rest == "" {
+			return args, nil
+		}
+		if rest[0] != ',' {
+			return nil, errors.Errorf("Expected ',' after %q in %s", arg, objectId)
+		}
+		rest = rest[1:]
+	}
+}
+
+// parseValueOp returns the operation and the arguments of add(<n>) or cas(<expected>, <new>).
+func parseValueOp(objectId string) (pb.DirectedEdge_Op, []string, error) {
+	op, numArgs := pb.DirectedEdge_ADD, 1
+	if strings.HasPrefix(objectId, "cas(") {
+		op, numArgs = pb.DirectedEdge_CAS, 2
+	}
+	args, err := parseObjectFuncArgs(objectId)
+	if err != nil {
+		return op, nil, err
+	}
+	if len(args) != numArgs {
+		return op, nil, errors.Errorf("%s expects %d argument(s), got %d",
+			objectId[:3], numArgs, len(args))
+	}
+	return op, args, nil
+}
+
+// IsNextFunc returns true if the object is the next value of a sequence, i.e. next(<name>).
+func IsNextFunc(objectId string) bool {
+	return strings.HasPrefix(objectId, "next(") && strings.HasSuffix(objectId, ")")
+}
+
+// SequenceName returns the name of the sequence of next(<name>).
+func SequenceName(objectId string) (string, error) {
+	args, err := parseObjectFuncArgs(objectId)
+	if err != nil {
+		return "", err
+	}
+	if len(args) != 1 || args[0] == "" {
+		return "", errors.Errorf("next expects the name of a sequence, got %s", objectId)
+	}
+	return args[0], nil
+}
+
+// CreateValueOpEdge returns a DirectedEdge which applies the add(<n>) or cas(<expected>, <new>)
+// operation of the NQuad to the current value of the predicate of the given subject. The values
+// are converted to the type of the predicate when the mutation is applied.
+func (nq NQuad) CreateValueOpEdge(subjectUid uint64) (*pb.DirectedEdge, error) {
+	op, args, err := parseValueOp(nq.ObjectId)
+	if err != nil {
+		return &emptyEdge, err
+	}
+	out := nq.createEdgePrototype(subjectUid)
+	out.Op = op
+	out.Value = []byte(args[len(args)-1])
+	out.ValueType = pb.Posting_DEFAULT
+	if op == pb.DirectedEdge_CAS {
+		out.Expected = []byte(args[0])
+	}
+	return out, nil
+}
+
 // ToDeletePredEdge takes an NQuad of the form '* p *' and returns the equivalent
 // directed edge. Returns an error if the NQuad does not have the expected form.
 func (nq NQuad) ToDeletePredEdge() (*pb.DirectedEdge, error) {
@@ -177,6 +277,10 @@ func (nq NQuad) ToEdgeUsing(newToUid map[string]uint64) (*pb.DirectedEdge, error
 		return nil, errors.Errorf("Subject should be > 0 for nquad: %+v", nq)
 	}
 
+	if IsValueOp(nq.ObjectId) {
+		return nq.CreateValueOpEdge(sUid)
+	}
+
 	switch nq.valueType() {
 	case x.ValueUid:
 		oUid, err := toUid(nq.ObjectId, newToUid)
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package gql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+func TestValueOpEdge(t *testing.T) {
+	nq := NQuad{&api.NQuad{Subject: "0x1", Predicate: "views", ObjectId: "add(-1)"}}
+	require.True(t, IsValueOp(nq.ObjectId))
+	edge, err := nq.ToEdgeUsing(nil)
+	require.NoError(t, err)
+	require.Equal(t, pb.DirectedEdge_ADD, edge.Op)
+	require.Equal(t, "-1", string(edge.Value))
+	require.Equal(t, pb.Posting_DEFAULT, edge.ValueType)
+
+	nq.ObjectId = `cas("in stock","sold, \"thanks\"")`
+	edge, err = nq.ToEdgeUsing(nil)
+	require.NoError(t, err)
+	require.Equal(t, pb.DirectedEdge_CAS, edge.Op)
+	require.Equal(t, "in stock", string(edge.Expected))
+	require.Equal(t, `sold, "thanks"`, string(edge.Value))
+
+	nq.ObjectId = "cas(10, 9)"
+	edge, err = nq.ToEdgeUsing(nil)
+	require.NoError(t, err)
+	require.Equal(t, "10", string(edge.Expected))
+	require.Equal(t, "9", string(edge.Value))
+
+	for _, objectId := range []string{"add(1,2)", "cas(1)", `cas("1,2)`, `cas("1" 2)`} {
+		nq.ObjectId = objectId
+		_, err = nq.ToEdgeUsing(nil)
+		require.Error(t, err, objectId)
+	}
+	require.False(t, IsValueOp("0x2"))
+	require.False(t, IsValueOp("val(x)"))
+}
+
+func TestSequenceName(t *testing.T) {
+	require.True(t, IsNextFunc(`next("orders")`))
+	require.False(t, IsNextFunc("add(1)"))
+	name, err := SequenceName(`next("orders")`)
+	require.NoError(t, err)
+	require.Equal(t, "orders", name)
+	name, err = SequenceName("next(orders)")
+	require.NoError(t, err)
+	require.Equal(t, "orders", name)
+
+	for _, objectId := range []string{"next()", `next("a","b")`} {
+		_, err = SequenceName(objectId)
+		require.Error(t, err, objectId)
+	}
+}
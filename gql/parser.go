@@ -22,7 +22,9 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/dgraph-io/dgraph/collate"
 	"github.com/dgraph-io/dgraph/lex"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/x"
@@ -31,11 +33,21 @@ import (
 )
 
 const (
-	uidFunc   = "uid"
-	valueFunc = "val"
-	typFunc   = "type"
-	lenFunc   = "len"
-	countFunc = "count"
+	uidFunc                 = "uid"
+	subgraphAlias           = "subgraph"
+	valueFunc               = "val"
+	typFunc                 = "type"
+	xidFunc                 = "xid"
+	keyFunc                 = "key"
+	lenFunc                 = "len"
+	countFunc               = "count"
+	customFunc              = "custom"
+	approxCountDistinctFunc = "approx_count_distinct"
+	rollupArg               = "rollup"
+	hasEdgeFunc             = "has_edge"
+	outerFunc               = "outer"
+	histogramFunc           = "histogram"
+	bucketsArg              = "buckets"
 )
 
 // GraphQuery stores the parsed Query in a tree format. This gets converted to
@@ -51,7 +63,7 @@ type GraphQuery struct {
 	Var        string
 	NeedsVar   []VarContext
 	Func       *Function
-	Expand     string // Which variable to expand with.
+	Expand     string // Which variable to expand with, or the quoted pattern of predicates.
 
 	Args map[string]string
 	// Query can have multiple sort parameters.
@@ -60,11 +72,16 @@ type GraphQuery struct {
 	Filter           *FilterTree
 	MathExp          *MathTree
 	Normalize        bool
+	NormalizeArgs    NormalizeArgs
 	Recurse          bool
 	RecurseArgs      RecurseArgs
+	SampleArgs       SampleArgs
 	ShortestPathArgs ShortestPathArgs
 	Cascade          bool
 	IgnoreReflex     bool
+	Typed            bool
+	Approximate      bool
+	At               string // Time at which the uid edges of the block must be valid.
 	Facets           *pb.FacetParams
 	FacetsFilter     *FilterTree
 	GroupbyAttrs     []GroupByAttr
@@ -72,10 +89,20 @@ type GraphQuery struct {
 	FacetOrder       string
 	FacetDesc        bool
 
+	// BestEffort blocks return no results instead of failing the query, which they also do
+	// once they run for longer than their Budget, if it's set.
+	BestEffort bool
+	Budget     time.Duration
+
 	// Internal fields below.
 	// If gq.fragment is nonempty, then it is a fragment reference / spread.
 	fragment string
 
+	// skipIf and includeIf are the conditions of the @skip and @include directives, a bool or
+	// a variable. The skipped blocks are pruned once the variables are substituted.
+	skipIf    string
+	includeIf string
+
 	// Indicates whether count of uids is requested as a child node. If there
 	// is an alias, then UidCountAlias will be set (otherwise it will be the
 	// empty string).
@@ -85,6 +112,14 @@ type GraphQuery struct {
 	// True for blocks that don't have a starting function and hence no starting nodes. They are
 	// used to aggregate and get variables defined in another block.
 	IsEmpty bool
+
+	// True for the subgraph(from: ..., depth: ...) blocks, which recurse from their root and
+	// return the nodes and the edges they reach as two lists.
+	Subgraph bool
+
+	// JoinArgs is set for the join blocks, which pair the nodes of two value variables with
+	// equal values.
+	JoinArgs JoinArgs
 }
 
 // RecurseArgs stores the arguments needed to process the @recurse directive.
@@ -93,6 +128,29 @@ type RecurseArgs struct {
 	AllowLoop bool
 }
 
+// SampleArgs stores the arguments of the @sample directive, which samples the nodes of the root
+// of a block. Either Count or Percent is set.
+type SampleArgs struct {
+	Count   uint64
+	Percent float64
+	Seed    int64
+	HasSeed bool
+}
+
+// NormalizeArgs stores the arguments needed to flatten the results of a @normalize block.
+type NormalizeArgs struct {
+	// KeepOrder keeps the keys in the order they were traversed instead of sorting them.
+	KeepOrder bool
+	// Suffix renames the keys that were already returned by a parent level to key_1, key_2...
+	// instead of merging their values into a list.
+	Suffix bool
+	// Dedupe drops the repeated pairs of key and scalar value.
+	Dedupe bool
+	// Prefix prefixes the keys of each level with the path of edges leading to it, like
+	// company.name.
+	Prefix bool
+}
+
 // SHortestPathArgs stores the arguments needed to process the shortest path query.
 type ShortestPathArgs struct {
 	// From, To can have a uid or a uid function as the argument.
@@ -101,6 +159,20 @@ type ShortestPathArgs struct {
 	// 3. from: uid(p) // a variable
 	From *Function
 	To   *Function
+	// Weight is the expression of the weight of the edges, written as weight: math(expr), whose
+	// names are facets of the edges or predicates of the nodes they lead to.
+	Weight *MathTree
+	// Through are the nodes the path must pass through, in order, and Avoid the ones it must
+	// not pass through. Both are uid functions, and Avoid can take uid variables.
+	Through *Function
+	Avoid   *Function
+}
+
+// JoinArgs stores the value variables of a join block, written as
+// pairs(left: val(a), right: val(b)).
+type JoinArgs struct {
+	Left  string
+	Right string
 }
 
 // GroupByAttr stores the arguments needed to process the @groupby directive.
@@ -144,6 +216,8 @@ type VarContext struct {
 type varInfo struct {
 	Value string
 	Type  string
+	// used is set once the variable is substituted somewhere in the query.
+	used bool
 }
 
 // varMap is a map with key as GQL variable name.
@@ -151,7 +225,8 @@ type varMap map[string]varInfo
 
 // FilterTree is the result of parsing the filter directive.
 // Either you can have `Op and Children` on non-leaf nodes
-// Or Func at leaf nodes.
+// Or Func at leaf nodes. The only leaves with a child are the has_edge functions, whose child
+// filters the nodes their edges lead to.
 type FilterTree struct {
 	Op    string
 	Child []*FilterTree
@@ -163,6 +238,9 @@ type Arg struct {
 	Value        string
 	IsValueVar   bool // If argument is val(a), e.g. eq(name, val(a))
 	IsGraphQLVar bool
+	// IsOuter is set if the argument is outer(pred) in the filter of has_edge, which stands for
+	// the value of pred of the node the edge leaves, e.g. gt(salary, outer(salary)).
+	IsOuter bool
 }
 
 // Function holds the information about gql functions.
@@ -186,6 +264,14 @@ var filterOpPrecedence = map[string]int{
 }
 var mathOpPrecedence = map[string]int{
 	"u-":      500,
+	"concat":  113,
+	"substr":  112,
+	"lower":   111,
+	"upper":   110,
+	"trim":    109,
+	"len":     108,
+	"abs":     107,
+	"round":   106,
 	"floor":   105,
 	"ceil":    104,
 	"since":   103,
@@ -217,9 +303,34 @@ func (f *Function) IsAggregator() bool {
 	return isAggregator(f.Name)
 }
 
-// IsPasswordVerifier returns true if the function name is "checkpwd".
+// IsCustomFunc returns true if the function is resolved by an external service.
+func (f *Function) IsCustomFunc() bool {
+	return f.Name == customFunc
+}
+
+// IsWindowFunc returns true if the function name is a window function such as rank.
+func (f *Function) IsWindowFunc() bool {
+	return IsWindowFunc(f.Name)
+}
+
+// IsPasswordVerifier returns true if the function name is "checkpwd" or "checkpwdlock".
 func (f *Function) IsPasswordVerifier() bool {
-	return f.Name == "checkpwd"
+	return f.Name == "checkpwd" || f.Name == "checkpwdlock"
+}
+
+// IsEditDistance returns true if the function name is "editdistance".
+func (f *Function) IsEditDistance() bool {
+	return f.Name == "editdistance"
+}
+
+// IsJSONPath returns true if the function name is "jsonpath".
+func (f *Function) IsJSONPath() bool {
+	return f.Name == "jsonpath"
+}
+
+// IsHistogram returns true if the function name is "histogram".
+func (f *Function) IsHistogram() bool {
+	return f.Name == histogramFunc
 }
 
 // DebugPrint is useful for debugging.
@@ -346,12 +457,23 @@ func substituteVar(f string, res *string, vmap varMap) error {
 		if !ok || va.Type == "" {
 			return errors.Errorf("Variable not defined %v", f)
 		}
+		va.used = true
+		vmap[f] = va
 		*res = va.Value
 	}
 	return nil
 }
 
 func substituteVariables(gq *GraphQuery, vmap varMap) error {
+	for _, cond := range []*string{&gq.skipIf, &gq.includeIf} {
+		v := *cond
+		if err := substituteVar(v, cond, vmap); err != nil {
+			return err
+		}
+		if v != "" && *cond == "" {
+			return errors.Errorf("Variable %v of a condition has no value", v)
+		}
+	}
 	for k, v := range gq.Args {
 		// v won't be empty as its handled in parseGqlVariables.
 		val := gq.Args[k]
@@ -432,6 +554,8 @@ func substituteVariablesFilter(f *FilterTree, vmap varMap) error {
 				if idVal.Value == "" {
 					return errors.Errorf("Id can't be empty")
 				}
+				idVal.used = true
+				vmap[v.Value] = idVal
 				uids, err := parseID(idVal.Value)
 				if err != nil {
 					return err
@@ -464,9 +588,13 @@ type Vars struct {
 // Result struct contains the Query list, its corresponding variable use list
 // and the mutation block.
 type Result struct {
+	// Name is the name of the query block, if it has one.
+	Name      string
 	Query     []*GraphQuery
 	QueryVars []*Vars
 	Schema    *pb.SchemaRequest
+	// Warnings lists the issues found in the query that don't prevent running it.
+	Warnings []string
 }
 
 // Parse initializes and runs the lexer. It also constructs the GraphQuery subgraph
@@ -538,9 +666,13 @@ func ParseWithNeedVars(r Request, needVars []string) (res Result, rerr error) {
 				if res.Schema != nil {
 					return res, item.Errorf("Schema block is not allowed with query block")
 				}
-				if qu, rerr = getVariablesAndQuery(it, vmap); rerr != nil {
+				var name string
+				if name, qu, rerr = getVariablesAndQuery(it, vmap); rerr != nil {
 					return res, rerr
 				}
+				if res.Name == "" {
+					res.Name = name
+				}
 				res.Query = append(res.Query, qu)
 			}
 		case itemLeftCurl:
@@ -558,9 +690,8 @@ func ParseWithNeedVars(r Request, needVars []string) (res Result, rerr error) {
 	}
 
 	if len(res.Query) != 0 {
-		res.QueryVars = make([]*Vars, 0, len(res.Query))
-		for i := 0; i < len(res.Query); i++ {
-			qu := res.Query[i]
+		var queries []*GraphQuery
+		for _, qu := range res.Query {
 			// Try expanding fragments using fragment map.
 			if err := qu.expandFragments(fmap); err != nil {
 				return res, err
@@ -571,10 +702,43 @@ func ParseWithNeedVars(r Request, needVars []string) (res Result, rerr error) {
 				return res, err
 			}
 
+			// The skipped blocks are pruned before the variables are collected, so that the
+			// variables they define are undefined for the other blocks.
+			skip, err := qu.skipped()
+			if err != nil {
+				return res, err
+			}
+			if skip {
+				continue
+			}
+			if err := qu.pruneSkipped(); err != nil {
+				return res, err
+			}
+			queries = append(queries, qu)
+		}
+		res.Query = queries
+
+		res.QueryVars = make([]*Vars, 0, len(res.Query))
+		for i, qu := range res.Query {
 			res.QueryVars = append(res.QueryVars, &Vars{})
 			// Collect vars used and defined in Result struct.
 			qu.collectVars(res.QueryVars[i])
 		}
+		res.Warnings = append(res.Warnings, unusedVariableWarnings(vmap)...)
+
+		// len() of a name that isn't a variable is the length of a list predicate.
+		defined := make(map[string]bool)
+		for _, v := range res.QueryVars {
+			for _, name := range v.Defines {
+				defined[name] = true
+			}
+		}
+		for i, qu := range res.Query {
+			if qu.resolveLenOfPredicates(defined) {
+				res.QueryVars[i] = &Vars{}
+				qu.collectVars(res.QueryVars[i])
+			}
+		}
 
 		allVars := res.QueryVars
 		// Add the variables that are needed outside the query block.
@@ -606,9 +770,101 @@ func validateResult(res *Result) error {
 		}
 		seenQueryAliases[q.Alias] = true
 	}
+	for _, q := range res.Query {
+		if err := checkAliases(q); err != nil {
+			return err
+		}
+		if q.Normalize && !q.NormalizeArgs.Suffix && !q.NormalizeArgs.Prefix {
+			res.Warnings = append(res.Warnings, normalizeAliasWarnings(q, q.Alias,
+				make(map[string]bool))...)
+		}
+	}
+	return nil
+}
+
+// fieldName returns the key under which a child is returned, if it can be told from the
+// query alone.
+func (gq *GraphQuery) fieldName() string {
+	if gq.Alias != "" {
+		return gq.Alias
+	}
+	if gq.Func != nil || gq.IsCount || gq.MathExp != nil || gq.Expand != "" ||
+		len(gq.Langs) > 0 || gq.Attr == "val" || gq.fragment != "" {
+		return ""
+	}
+	return gq.Attr
+}
+
+// checkAliases returns an error if an alias is used for more than one field of the same
+// node, which would make them share the same key in the result.
+func checkAliases(gq *GraphQuery) error {
+	aliases := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, child := range gq.Children {
+		if child.Alias == "" {
+			if name := child.fieldName(); name != "" {
+				names[name] = true
+			}
+			continue
+		}
+		if aliases[child.Alias] {
+			return errors.Errorf("Alias %s collides with another field in %s", child.Alias,
+				gq.Alias+gq.Attr)
+		}
+		aliases[child.Alias] = true
+	}
+	for _, child := range gq.Children {
+		if child.Alias != "" && names[child.Alias] {
+			return errors.Errorf("Alias %s collides with another field in %s", child.Alias,
+				gq.Alias+gq.Attr)
+		}
+	}
+	for _, child := range gq.Children {
+		if err := checkAliases(child); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+// normalizeAliasWarnings warns about the aliases that are used more than once in a
+// @normalize block, as the flattened results would have them repeated.
+func normalizeAliasWarnings(gq *GraphQuery, block string, seen map[string]bool) []string {
+	var warnings []string
+	for _, child := range gq.Children {
+		if child.Alias == "" {
+			continue
+		}
+		if seen[child.Alias] {
+			warnings = append(warnings, fmt.Sprintf("Alias %s is used more than once in"+
+				" @normalize block %s and its values will be merged into a list",
+				child.Alias, block))
+		}
+		seen[child.Alias] = true
+	}
+	for _, child := range gq.Children {
+		warnings = append(warnings, normalizeAliasWarnings(child, block, seen)...)
+	}
+	return warnings
+}
+
+// unusedVariableWarnings warns about the GraphQL variables which are declared by the query but
+// aren't used by any of its blocks.
+func unusedVariableWarnings(vmap varMap) []string {
+	var unused []string
+	for name, v := range vmap {
+		if v.Type != "" && !v.used {
+			unused = append(unused, name)
+		}
+	}
+	sort.Strings(unused)
+	warnings := make([]string, 0, len(unused))
+	for _, name := range unused {
+		warnings = append(warnings, fmt.Sprintf("Variable %s is declared but not used", name))
+	}
+	return warnings
+}
+
 func flatten(vl []*Vars) (needs []string, defines []string) {
 	needs, defines = make([]string, 0, 10), make([]string, 0, 10)
 	for _, it := range vl {
@@ -677,6 +933,11 @@ func (gq *GraphQuery) collectVars(v *Vars) {
 	if shortestPathTo != nil && len(shortestPathTo.NeedsVar) > 0 {
 		v.Needs = append(v.Needs, shortestPathTo.NeedsVar[0].Name)
 	}
+	if avoid := gq.ShortestPathArgs.Avoid; avoid != nil {
+		for _, nv := range avoid.NeedsVar {
+			v.Needs = append(v.Needs, nv.Name)
+		}
+	}
 }
 
 func (f *MathTree) collectVars(v *Vars) {
@@ -703,6 +964,55 @@ func (f *FilterTree) collectVars(v *Vars) {
 	}
 }
 
+// resolveLenOfPredicates turns len(pred) into count(pred) in the functions of the query when
+// pred isn't a defined variable, e.g. gt(len(tags), 2) compares the number of values of the
+// list predicate tags. Returns true if any function was changed.
+func (gq *GraphQuery) resolveLenOfPredicates(defined map[string]bool) bool {
+	changed := false
+	if gq.Func.lenOfPredicate(defined) {
+		gq.NeedsVar = removeVar(gq.NeedsVar, gq.Func.Attr)
+		changed = true
+	}
+	if gq.Filter != nil && gq.Filter.resolveLenOfPredicates(defined) {
+		changed = true
+	}
+	for _, ch := range gq.Children {
+		if ch.resolveLenOfPredicates(defined) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (f *FilterTree) resolveLenOfPredicates(defined map[string]bool) bool {
+	changed := f.Func.lenOfPredicate(defined)
+	for _, fch := range f.Child {
+		if fch.resolveLenOfPredicates(defined) {
+			changed = true
+		}
+	}
+	return changed
+}
+
+func (f *Function) lenOfPredicate(defined map[string]bool) bool {
+	if f == nil || !f.IsLenVar || defined[f.Attr] {
+		return false
+	}
+	f.IsLenVar, f.IsCount = false, true
+	f.NeedsVar = removeVar(f.NeedsVar, f.Attr)
+	return true
+}
+
+func removeVar(vars []VarContext, name string) []VarContext {
+	var out []VarContext
+	for _, v := range vars {
+		if v.Name != name {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func (f *FilterTree) hasVars() bool {
 	if (f.Func != nil) && (len(f.Func.NeedsVar) > 0) {
 		return true
@@ -717,39 +1027,40 @@ func (f *FilterTree) hasVars() bool {
 
 // getVariablesAndQuery checks if the query has a variable list and stores it in
 // vmap. For variable list to be present, the query should have a name which is
-// also checked for. It also calls getQuery to create the GraphQuery object tree.
-func getVariablesAndQuery(it *lex.ItemIterator, vmap varMap) (gq *GraphQuery, rerr error) {
-	var name string
+// also checked for. It also calls getQuery to create the GraphQuery object tree, and returns it
+// along with the name of the query.
+func getVariablesAndQuery(it *lex.ItemIterator, vmap varMap) (
+	name string, gq *GraphQuery, rerr error) {
 L2:
 	for it.Next() {
 		item := it.Item()
 		switch item.Typ {
 		case itemName:
 			if name != "" {
-				return nil, item.Errorf("Multiple word query name not allowed.")
+				return "", nil, item.Errorf("Multiple word query name not allowed.")
 			}
 			name = item.Val
 		case itemLeftRound:
 			if name == "" {
-				return nil, item.Errorf("Variables can be defined only in named queries.")
+				return "", nil, item.Errorf("Variables can be defined only in named queries.")
 			}
 
 			if rerr = parseGqlVariables(it, vmap); rerr != nil {
-				return nil, rerr
+				return "", nil, rerr
 			}
 
 			if rerr = checkValueType(vmap); rerr != nil {
-				return nil, rerr
+				return "", nil, rerr
 			}
 		case itemLeftCurl:
 			if gq, rerr = getQuery(it); rerr != nil {
-				return nil, rerr
+				return "", nil, rerr
 			}
 			break L2
 		}
 	}
 
-	return gq, nil
+	return name, gq, nil
 }
 
 func parseRecurseArgs(it *lex.ItemIterator, gq *GraphQuery) error {
@@ -804,6 +1115,263 @@ func parseRecurseArgs(it *lex.ItemIterator, gq *GraphQuery) error {
 	return nil
 }
 
+func parseSampleArgs(it *lex.ItemIterator, gq *GraphQuery) error {
+	if ok := trySkipItemTyp(it, itemLeftRound); !ok {
+		return it.Errorf("Expected arguments n or percent for @sample")
+	}
+
+	var key, val string
+	var ok bool
+	for it.Next() {
+		item := it.Item()
+		if item.Typ != itemName {
+			return item.Errorf("Expected key inside @sample()")
+		}
+		key = strings.ToLower(item.Val)
+
+		if ok := trySkipItemTyp(it, itemColon); !ok {
+			return it.Errorf("Expected colon(:) after %s", key)
+		}
+
+		if item, ok = tryParseItemType(it, itemName); !ok {
+			return item.Errorf("Expected value inside @sample() for key: %s", key)
+		}
+		val = item.Val
+
+		switch key {
+		case "n":
+			n, err := strconv.ParseUint(val, 0, 64)
+			if err != nil || n == 0 {
+				return item.Errorf("Expected a positive number of nodes in @sample, got: %s", val)
+			}
+			gq.SampleArgs.Count = n
+		case "percent":
+			percent, err := strconv.ParseFloat(val, 64)
+			if err != nil || !(percent > 0 && percent <= 100) {
+				return item.Errorf("Expected a percent between 0 and 100 in @sample, got: %s", val)
+			}
+			gq.SampleArgs.Percent = percent
+		case "seed":
+			seed, err := strconv.ParseInt(val, 0, 64)
+			if err != nil {
+				return item.Errorf("Expected an integer seed in @sample, got: %s", val)
+			}
+			gq.SampleArgs.Seed, gq.SampleArgs.HasSeed = seed, true
+		default:
+			return item.Errorf("Unexpected key: [%s] inside @sample block", key)
+		}
+
+		if _, ok := tryParseItemType(it, itemRightRound); ok {
+			break
+		}
+
+		if _, ok := tryParseItemType(it, itemComma); !ok {
+			return it.Errorf("Expected comma after value: %s inside sample block", val)
+		}
+	}
+	if (gq.SampleArgs.Count > 0) == (gq.SampleArgs.Percent > 0) {
+		return it.Errorf("Expected one of n or percent in @sample")
+	}
+	return nil
+}
+
+func parseNormalizeArgs(it *lex.ItemIterator, gq *GraphQuery) error {
+	if ok := trySkipItemTyp(it, itemLeftRound); !ok {
+		// We don't have a (, we can return.
+		return nil
+	}
+
+	var key, val string
+	var ok bool
+	for it.Next() {
+		item := it.Item()
+		if item.Typ != itemName {
+			return item.Errorf("Expected key inside @normalize()")
+		}
+		key = strings.ToLower(item.Val)
+
+		if ok := trySkipItemTyp(it, itemColon); !ok {
+			return it.Errorf("Expected colon(:) after %s", key)
+		}
+
+		if item, ok = tryParseItemType(it, itemName); !ok {
+			return item.Errorf("Expected value inside @normalize() for key: %s", key)
+		}
+		val = item.Val
+
+		switch key {
+		case "order":
+			switch val {
+			case "sorted":
+				gq.NormalizeArgs.KeepOrder = false
+			case "traversal":
+				gq.NormalizeArgs.KeepOrder = true
+			default:
+				return item.Errorf("Expected sorted or traversal for order inside @normalize."+
+					" Got: %s", val)
+			}
+		case "duplicates":
+			switch val {
+			case "list":
+				gq.NormalizeArgs.Suffix = false
+			case "suffix":
+				gq.NormalizeArgs.Suffix = true
+			default:
+				return item.Errorf("Expected list or suffix for duplicates inside @normalize."+
+					" Got: %s", val)
+			}
+		case "dedupe":
+			dedupe, err := strconv.ParseBool(val)
+			if err != nil {
+				return err
+			}
+			gq.NormalizeArgs.Dedupe = dedupe
+		case "prefix":
+			prefix, err := strconv.ParseBool(val)
+			if err != nil {
+				return err
+			}
+			gq.NormalizeArgs.Prefix = prefix
+		default:
+			return item.Errorf("Unexpected key: [%s] inside @normalize block", key)
+		}
+
+		if _, ok := tryParseItemType(it, itemRightRound); ok {
+			return nil
+		}
+
+		if _, ok := tryParseItemType(it, itemComma); !ok {
+			return it.Errorf("Expected comma after value: %s inside normalize block", val)
+		}
+	}
+	return nil
+}
+
+// parseAtArgs parses the time of the @at directive, a datetime or unix seconds.
+func parseAtArgs(it *lex.ItemIterator, gq *GraphQuery) error {
+	if ok := trySkipItemTyp(it, itemLeftRound); !ok {
+		return it.Errorf("Expected a time for @at")
+	}
+	item, ok := tryParseItemType(it, itemName)
+	if !ok {
+		return item.Errorf("Expected a time inside @at()")
+	}
+	val, err := unquoteIfQuoted(item.Val)
+	if err != nil {
+		return err
+	}
+	if ok := trySkipItemTyp(it, itemRightRound); !ok {
+		return it.Errorf("Expected a single time inside @at()")
+	}
+	gq.At = val
+	return nil
+}
+
+func parseBudgetArgs(it *lex.ItemIterator, gq *GraphQuery) error {
+	if ok := trySkipItemTyp(it, itemLeftRound); !ok {
+		return it.Errorf("Expected a duration for @budget")
+	}
+	item, ok := tryParseItemType(it, itemName)
+	if !ok {
+		return item.Errorf("Expected a duration inside @budget()")
+	}
+	val, err := unquoteIfQuoted(item.Val)
+	if err != nil {
+		return err
+	}
+	budget, err := time.ParseDuration(val)
+	if err != nil || budget <= 0 {
+		return item.Errorf("Expected a positive duration in @budget, got: %s", val)
+	}
+	if ok := trySkipItemTyp(it, itemRightRound); !ok {
+		return it.Errorf("Expected a single duration inside @budget()")
+	}
+	gq.Budget = budget
+	return nil
+}
+
+// parseConditionArgs parses the condition of the @skip or @include directive of gq, a bool or a
+// variable of the query.
+func parseConditionArgs(it *lex.ItemIterator, gq *GraphQuery, name string) error {
+	cond := &gq.skipIf
+	if name == "include" {
+		cond = &gq.includeIf
+	}
+	if *cond != "" {
+		return it.Errorf("Repeated @%s", name)
+	}
+	if ok := trySkipItemTyp(it, itemLeftRound); !ok {
+		return it.Errorf("Expected an if argument for @%s", name)
+	}
+	if item, ok := tryParseItemType(it, itemName); !ok || item.Val != "if" {
+		return item.Errorf("Expected an if argument inside @%s()", name)
+	}
+	if ok := trySkipItemTyp(it, itemColon); !ok {
+		return it.Errorf("Expected colon(:) after if inside @%s()", name)
+	}
+	isDollar := trySkipItemTyp(it, itemDollar)
+	item, ok := tryParseItemType(it, itemName)
+	if !ok {
+		return item.Errorf("Expected a bool or a variable inside @%s()", name)
+	}
+	if isDollar {
+		*cond = "$" + item.Val
+	} else {
+		if _, err := parseCondition(name, item.Val); err != nil {
+			return item.Errorf(err.Error())
+		}
+		*cond = item.Val
+	}
+	if ok := trySkipItemTyp(it, itemRightRound); !ok {
+		return it.Errorf("Expected a single condition inside @%s()", name)
+	}
+	return nil
+}
+
+func parseCondition(name, val string) (bool, error) {
+	b, err := strconv.ParseBool(val)
+	if err != nil {
+		return false, errors.Errorf("Expected a bool in @%s, got: %s", name, val)
+	}
+	return b, nil
+}
+
+// skipped tells if gq is skipped by its @skip and @include directives.
+func (gq *GraphQuery) skipped() (bool, error) {
+	if gq.skipIf != "" {
+		skip, err := parseCondition("skip", gq.skipIf)
+		if err != nil || skip {
+			return skip, err
+		}
+	}
+	if gq.includeIf != "" {
+		include, err := parseCondition("include", gq.includeIf)
+		return !include, err
+	}
+	return false, nil
+}
+
+// pruneSkipped removes the children of gq skipped by their @skip and @include directives, at
+// every level.
+func (gq *GraphQuery) pruneSkipped() error {
+	var children []*GraphQuery
+	for _, child := range gq.Children {
+		skip, err := child.skipped()
+		if err != nil {
+			return err
+		}
+		if skip {
+			continue
+		}
+		if err := child.pruneSkipped(); err != nil {
+			return err
+		}
+		children = append(children, child)
+	}
+	gq.Children = children
+	return nil
+}
+
 // getQuery creates a GraphQuery object tree by calling getRoot
 // and goDeep functions by looking at '{'.
 func getQuery(it *lex.ItemIterator) (gq *GraphQuery, rerr error) {
@@ -825,6 +1393,9 @@ L:
 		if rerr = godeep(it, gq); rerr != nil {
 			return nil, rerr
 		}
+		if gq.Subgraph {
+			addSubgraphTypes(gq)
+		}
 	} else if item.Typ == itemAt {
 		it.Next()
 		item := it.Item()
@@ -843,6 +1414,9 @@ L:
 
 			case "normalize":
 				gq.Normalize = true
+				if err := parseNormalizeArgs(it, gq); err != nil {
+					return nil, err
+				}
 			case "cascade":
 				gq.Cascade = true
 			case "groupby":
@@ -852,7 +1426,33 @@ L:
 				}
 			case "ignorereflex":
 				gq.IgnoreReflex = true
+			case "typed":
+				gq.Typed = true
+			case "approximate":
+				gq.Approximate = true
+			case "sample":
+				if err := parseSampleArgs(it, gq); err != nil {
+					return nil, err
+				}
+			case "at":
+				if err := parseAtArgs(it, gq); err != nil {
+					return nil, err
+				}
+			case "besteffort":
+				gq.BestEffort = true
+			case "budget":
+				gq.BestEffort = true
+				if err := parseBudgetArgs(it, gq); err != nil {
+					return nil, err
+				}
+			case "skip", "include":
+				if err := parseConditionArgs(it, gq, strings.ToLower(item.Val)); err != nil {
+					return nil, err
+				}
 			case "recurse":
+				if gq.Subgraph {
+					return nil, item.Errorf("subgraph can't be used with @recurse")
+				}
 				gq.Recurse = true
 				if err := parseRecurseArgs(it, gq); err != nil {
 					return nil, err
@@ -1284,17 +1884,23 @@ func (f *FilterTree) stringHelper(buf *bytes.Buffer) {
 			for _, arg := range f.Func.Args {
 				if arg.IsValueVar {
 					buf.WriteString(" val(")
+				} else if arg.IsOuter {
+					buf.WriteString(" outer(")
 				} else {
 					buf.WriteString(" \"")
 				}
 				buf.WriteString(arg.Value)
-				if arg.IsValueVar {
+				if arg.IsValueVar || arg.IsOuter {
 					buf.WriteRune(')')
 				} else {
 					buf.WriteRune('"')
 				}
 			}
 		}
+		for _, c := range f.Child {
+			buf.WriteRune(' ')
+			c.stringHelper(buf)
+		}
 		buf.WriteRune(')')
 		return
 	}
@@ -1479,7 +2085,8 @@ func validFuncName(name string) bool {
 
 	switch name {
 	case "regexp", "anyofterms", "allofterms", "alloftext", "anyoftext",
-		"has", "uid", "uid_in", "anyof", "allof", "type", "match":
+		"has", "uid", "uid_in", "anyof", "allof", "type", "match", "xid", "key",
+		"prefix", "suffix", "containsany", "containsall", "distinct":
 		return true
 	}
 	return false
@@ -1583,6 +2190,13 @@ L:
 				} else if nestedFunc.Name == countFunc {
 					function.Attr = nestedFunc.Attr
 					function.IsCount = true
+				} else if nestedFunc.Name == outerFunc {
+					if len(function.Attr) == 0 || nestedFunc.Attr == "" {
+						return nil, itemInFunc.Errorf("outer of a predicate is only allowed" +
+							" as the value a predicate is compared with")
+					}
+					function.Args = append(function.Args,
+						Arg{Value: nestedFunc.Attr, IsOuter: true})
 				} else {
 					return nil, itemInFunc.Errorf("Only val/count/len allowed as function "+
 						"within another. Got: %s", nestedFunc.Name)
@@ -1621,7 +2235,7 @@ L:
 				case isGeoFunc(function.Name):
 					err = parseGeoArgs(it, function)
 
-				case isInequalityFn(function.Name):
+				case isInequalityFn(function.Name) || isContainsFn(function.Name):
 					err = parseIneqArgs(it, function)
 
 				default:
@@ -1685,10 +2299,13 @@ L:
 			}
 
 			// Unlike other functions, uid function has no attribute, everything is args.
+			// The attribute of xid is optional, a quoted first argument is already an xid.
 			if len(function.Attr) == 0 && function.Name != uidFunc &&
-				function.Name != typFunc {
+				function.Name != typFunc &&
+				!(function.Name == xidFunc && strings.ContainsRune(itemInFunc.Val, '"')) {
 
-				if strings.ContainsRune(itemInFunc.Val, '"') {
+				// The predicate of has can be a quoted pattern, like has("address.*").
+				if strings.ContainsRune(itemInFunc.Val, '"') && function.Name != "has" {
 					return nil, itemInFunc.Errorf("Attribute in function"+
 						" must not be quoted with \": %s", itemInFunc.Val)
 				}
@@ -1752,10 +2369,20 @@ L:
 		}
 	}
 
-	if function.Name != uidFunc && function.Name != typFunc && len(function.Attr) == 0 {
+	if function.Name != uidFunc && function.Name != typFunc && function.Name != xidFunc &&
+		len(function.Attr) == 0 {
 		return nil, it.Errorf("Got empty attr for function: [%s]", function.Name)
 	}
 
+	if function.Name == xidFunc && len(function.Args) == 0 {
+		return nil, it.Errorf("xid function requires at least one external id")
+	}
+
+	if function.Name == keyFunc && len(function.Args) == 0 {
+		return nil, it.Errorf("key function requires the values of the key of type %s",
+			function.Attr)
+	}
+
 	if function.Name == typFunc && len(function.Args) != 1 {
 		return nil, it.Errorf("type function only supports one argument. Got: %v", function.Args)
 	}
@@ -2082,6 +2709,12 @@ func parseFilter(it *lex.ItemIterator) (*FilterTree, error) {
 				}
 			}
 			opStack.push(&FilterTree{Op: op}) // Push current operator.
+		} else if item.Typ == itemName && lval == hasEdgeFunc {
+			leaf, err := parseHasEdge(it)
+			if err != nil {
+				return nil, err
+			}
+			valueStack.push(leaf)
 		} else if item.Typ == itemName { // Value.
 			it.Prev()
 			f, err := parseFunction(it, nil)
@@ -2139,6 +2772,38 @@ func parseFilter(it *lex.ItemIterator) (*FilterTree, error) {
 	return valueStack.pop()
 }
 
+// parseHasEdge parses has_edge(pred) and has_edge(pred, @filter(...)), which keep the nodes with
+// an edge of pred leading to a node matching the filter.
+func parseHasEdge(it *lex.ItemIterator) (*FilterTree, error) {
+	if _, ok := tryParseItemType(it, itemLeftRound); !ok {
+		return nil, it.Errorf("Expected ( after func name [%s]", hasEdgeFunc)
+	}
+	item, ok := tryParseItemType(it, itemName)
+	if !ok || strings.ContainsRune(item.Val, '"') {
+		return nil, it.Errorf("Expected a predicate in %s", hasEdgeFunc)
+	}
+	leaf := &FilterTree{Func: &Function{Name: hasEdgeFunc, Attr: collectName(it, item.Val)}}
+	if _, ok := tryParseItemType(it, itemComma); ok {
+		if _, ok := tryParseItemType(it, itemAt); !ok {
+			return nil, it.Errorf("Expected @filter after the predicate of %s", hasEdgeFunc)
+		}
+		if item, ok := tryParseItemType(it, itemName); !ok || item.Val != "filter" {
+			return nil, it.Errorf("Expected @filter after the predicate of %s", hasEdgeFunc)
+		}
+		filter, err := parseFilter(it)
+		if err != nil {
+			return nil, err
+		}
+		if filter != nil {
+			leaf.Child = append(leaf.Child, filter)
+		}
+	}
+	if _, ok := tryParseItemType(it, itemRightRound); !ok {
+		return nil, it.Errorf("Expected ) at the end of %s", hasEdgeFunc)
+	}
+	return leaf, nil
+}
+
 // Parses ID list. Only used for GraphQL variables.
 // TODO - Maybe get rid of this by lexing individual IDs.
 func parseID(val string) ([]uint64, error) {
@@ -2286,6 +2951,10 @@ func parseDirective(it *lex.ItemIterator, curp *GraphQuery) error {
 			if err != nil {
 				return err
 			}
+		case "skip", "include":
+			if err := parseConditionArgs(it, curp, item.Val); err != nil {
+				return err
+			}
 		default:
 			return item.Errorf("Unknown directive [%s]", item.Val)
 		}
@@ -2309,7 +2978,7 @@ func parseLanguageList(it *lex.ItemIterator) ([]string, error) {
 	for ; item.Typ == itemName || item.Typ == itemPeriod; item = it.Item() {
 		langs = append(langs, item.Val)
 		it.Next()
-		if it.Item().Typ == itemColon {
+		if it.Item().Typ == itemColon || (it.Item().Typ == itemComma && isLangChain(it)) {
 			it.Next()
 		} else {
 			break
@@ -2337,15 +3006,41 @@ func parseLanguageList(it *lex.ItemIterator) ([]string, error) {
 	return langs, nil
 }
 
+// isLangChain tells if the comma at the current item separates the languages of a fallback
+// chain, like in name@en,fr,. Such a chain must end with the wildcard, otherwise the comma
+// separates predicates, like in name@en, age.
+func isLangChain(it *lex.ItemIterator) bool {
+	for i := 1; ; i += 2 {
+		items, err := it.Peek(i)
+		if err != nil {
+			return false
+		}
+		item := items[i-1]
+		if item.Typ == itemPeriod || (item.Typ == itemName && item.Val == ".") {
+			return true
+		}
+		if item.Typ != itemName {
+			return false
+		}
+		if items, err = it.Peek(i + 1); err != nil || items[i].Typ != itemComma {
+			return false
+		}
+	}
+}
+
 func validKeyAtRoot(k string) bool {
 	switch k {
-	case "func", "orderasc", "orderdesc", "first", "offset", "after":
+	case "func", "orderasc", "orderdesc", "orderrandom", "collation", "first", "offset", "after":
 		return true
-	case "from", "to", "numpaths", "minweight", "maxweight":
+	case "from", "to", "numpaths", "minweight", "maxweight", "weight", "bidirectional", "astar",
+		"through", "avoid":
 		// Specific to shortest path
 		return true
 	case "depth":
 		return true
+	case "left", "right":
+		// Specific to join blocks
+		return true
 	}
 	return false
 }
@@ -2353,7 +3048,10 @@ func validKeyAtRoot(k string) bool {
 // Check for validity of key at non-root nodes.
 func validKey(k string) bool {
 	switch k {
-	case "orderasc", "orderdesc", "first", "offset", "after":
+	case "orderasc", "orderdesc", "orderrandom", "collation", "first", "offset", "after":
+		return true
+	case "maxedges":
+		// Specific to the predicates of shortest path
 		return true
 	}
 	return false
@@ -2464,6 +3162,12 @@ func getRoot(it *lex.ItemIterator) (gq *GraphQuery, rerr error) {
 			gq.Func = gen
 			gq.NeedsVar = append(gq.NeedsVar, gen.NeedsVar...)
 		case "from", "to":
+			if gq.Alias == subgraphAlias && key == "from" {
+				if err := parseSubgraphFrom(it, gq); err != nil {
+					return nil, err
+				}
+				continue
+			}
 			if gq.Alias != "shortest" {
 				return gq, item.Errorf("from/to only allowed for shortest path queries")
 			}
@@ -2511,6 +3215,63 @@ func getRoot(it *lex.ItemIterator) (gq *GraphQuery, rerr error) {
 			}
 			assignShortestPathFn(fn, key)
 
+		case "through", "avoid":
+			if gq.Alias != "shortest" {
+				return gq, item.Errorf("%s only allowed for shortest path queries", key)
+			}
+			peekIt, err := it.Peek(1)
+			if err != nil || peekIt[0].Val != uidFunc {
+				return nil, item.Errorf("%s in shortest path can only accept a uid function", key)
+			}
+			// The uids are kept in the function, instead of the ones of the block.
+			fn, err := parseFunction(it, nil)
+			if err != nil {
+				return nil, err
+			}
+			if key == "through" {
+				if len(fn.NeedsVar) > 0 {
+					return nil, item.Errorf("through in shortest path only accepts uids, in the"+
+						" order the path passes through them. Got: %s", fn.NeedsVar[0].Name)
+				}
+				gq.ShortestPathArgs.Through = fn
+			} else {
+				gq.ShortestPathArgs.Avoid = fn
+			}
+
+		case "left", "right":
+			if !it.Next() || it.Item().Val != valueFunc {
+				return nil, item.Errorf("%s of a join only accepts a value variable", key)
+			}
+			count, err := parseVarList(it, gq)
+			if err != nil {
+				return nil, err
+			}
+			if count != 1 {
+				return nil, item.Errorf("Expected only one variable in %s but got: %d", key, count)
+			}
+			gq.NeedsVar[len(gq.NeedsVar)-1].Typ = ValueVar
+			if key == "left" {
+				gq.JoinArgs.Left = gq.NeedsVar[len(gq.NeedsVar)-1].Name
+			} else {
+				gq.JoinArgs.Right = gq.NeedsVar[len(gq.NeedsVar)-1].Name
+			}
+
+		case "weight":
+			if gq.Alias != "shortest" {
+				return gq, item.Errorf("weight only allowed for shortest path queries")
+			}
+			if !it.Next() || !isMathBlock(strings.ToLower(it.Item().Val)) {
+				return nil, it.Errorf("Expected math() as the weight of shortest path")
+			}
+			mathTree, again, err := parseMathFunc(it, false)
+			if err != nil {
+				return nil, err
+			}
+			if again {
+				return nil, it.Errorf("Comma encountered in math() at unexpected place.")
+			}
+			gq.ShortestPathArgs.Weight = mathTree
+
 		default:
 			var val string
 			if !it.Next() {
@@ -2552,6 +3313,13 @@ func getRoot(it *lex.ItemIterator) (gq *GraphQuery, rerr error) {
 				val = collectName(it, val+item.Val)
 				// Get language list, if present
 				items, err := it.Peek(1)
+				if err == nil && items[0].Typ == itemLeftRound && isSortkey(key) &&
+					val == countFunc {
+					if err := parseCountOrder(it, gq, key == "orderdesc"); err != nil {
+						return nil, err
+					}
+					continue
+				}
 				if err == nil && items[0].Typ == itemLeftRound {
 					if (key == "orderasc" || key == "orderdesc") && val != valueFunc {
 						return nil, it.Errorf("Expected val(). Got %s() with order.", val)
@@ -2583,6 +3351,9 @@ func getRoot(it *lex.ItemIterator) (gq *GraphQuery, rerr error) {
 				}
 			}
 			if isSortkey(key) {
+				if len(gq.Order) > 0 && gq.Order[0].Count {
+					return nil, it.Errorf("Sorting by a count can't be done with other orders")
+				}
 				if order[val] {
 					return nil, it.Errorf("Sorting by an attribute: [%s] can only be done once", val)
 				}
@@ -2600,14 +3371,143 @@ func getRoot(it *lex.ItemIterator) (gq *GraphQuery, rerr error) {
 			gq.Args[key] = val
 		}
 	}
+	if err := setCollation(gq); err != nil {
+		return nil, it.Errorf("%v", err)
+	}
+	if gq.Alias == subgraphAlias {
+		if err := setSubgraphArgs(gq); err != nil {
+			return nil, it.Errorf("%v", err)
+		}
+	}
+	if join := gq.JoinArgs; join.Left != "" || join.Right != "" {
+		if join.Left == "" || join.Right == "" {
+			return nil, it.Errorf("A join needs both a left and a right value variable")
+		}
+		if gq.Func != nil || len(gq.UID) > 0 {
+			return nil, it.Errorf("A join can't have a function at root")
+		}
+	}
 
 	return gq, nil
 }
 
+// parseSubgraphFrom parses the from argument of a subgraph block, a uid or a uid function, as
+// the function of its root.
+func parseSubgraphFrom(it *lex.ItemIterator, gq *GraphQuery) error {
+	if gq.Func != nil {
+		return it.Errorf("Only one of func and from allowed in subgraph")
+	}
+	peekIt, err := it.Peek(1)
+	if err != nil {
+		return it.Errorf("Invalid query")
+	}
+	if peekIt[0].Val == uidFunc {
+		gen, err := parseFunction(it, gq)
+		if err != nil {
+			return err
+		}
+		gq.Func = gen
+		gq.NeedsVar = append(gq.NeedsVar, gen.NeedsVar...)
+		return nil
+	}
+
+	it.Next()
+	item := it.Item()
+	val := collectName(it, item.Val)
+	uid, err := strconv.ParseUint(val, 0, 64)
+	if err != nil {
+		return item.Errorf("from in subgraph can only accept uid function or an uid. Got: %s",
+			val)
+	}
+	gq.Func = &Function{Name: uidFunc}
+	gq.UID = append(gq.UID, uid)
+	return nil
+}
+
+// setSubgraphArgs turns a subgraph block into a @recurse block from its root, one level deeper
+// than its depth argument so that the values of the nodes at that depth are read.
+func setSubgraphArgs(gq *GraphQuery) error {
+	if gq.Func == nil {
+		return errors.Errorf("subgraph requires from")
+	}
+	depth := uint64(1)
+	if v, ok := gq.Args["depth"]; ok {
+		var err error
+		if depth, err = strconv.ParseUint(v, 0, 64); err != nil {
+			return errors.Errorf("Invalid depth in subgraph: %s", v)
+		}
+		delete(gq.Args, "depth")
+	}
+	gq.Subgraph = true
+	gq.Recurse = true
+	gq.RecurseArgs = RecurseArgs{Depth: depth + 1}
+	return nil
+}
+
+// addSubgraphTypes requests the types of the nodes of a subgraph block, if they aren't already.
+func addSubgraphTypes(gq *GraphQuery) {
+	for _, child := range gq.Children {
+		if child.Attr == "dgraph.type" {
+			return
+		}
+	}
+	gq.Children = append(gq.Children, &GraphQuery{
+		Attr: "dgraph.type",
+		Args: make(map[string]string),
+	})
+}
+
+// setCollation sets the collation argument of the block, if any, on its sort orders.
+func setCollation(gq *GraphQuery) error {
+	c, ok := gq.Args["collation"]
+	if !ok {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(c); err == nil {
+		c = unquoted
+	}
+	if len(gq.Order) == 0 {
+		return errors.Errorf("Collation %q given without orderasc or orderdesc", c)
+	}
+	if err := collate.Valid(c); err != nil {
+		return err
+	}
+	gq.Args["collation"] = c
+	for _, o := range gq.Order {
+		o.Collation = c
+	}
+	return nil
+}
+
 func isSortkey(k string) bool {
 	return k == "orderasc" || k == "orderdesc"
 }
 
+// parseCountOrder parses the count(pred) or count(~pred) which the nodes at root are sorted by.
+// The nodes are sorted from the count index of pred, so it's the only order of the block.
+func parseCountOrder(it *lex.ItemIterator, gq *GraphQuery, desc bool) error {
+	if len(gq.Order) > 0 {
+		return it.Errorf("Sorting by a count can't be done with other orders")
+	}
+	it.Next() // Consume the left round bracket.
+	if !it.Next() || it.Item().Typ != itemName {
+		return it.Errorf("Expected a predicate in count() with order")
+	}
+	attr := collectName(it, it.Item().Val)
+	if !it.Next() || it.Item().Typ != itemRightRound {
+		return it.Errorf("Expected ) after the predicate of count() with order")
+	}
+	order := &pb.Order{Attr: attr, Desc: desc, Count: true}
+	if strings.HasPrefix(attr, "~") {
+		order.Attr, order.Reverse = attr[1:], true
+	}
+	if order.Attr == "" {
+		return it.Errorf("Expected a predicate in count() with order")
+	}
+	gq.Order = append(gq.Order, order)
+	return nil
+}
+
 type countType int
 
 const (
@@ -2710,7 +3610,8 @@ func godeep(it *lex.ItemIterator, gq *GraphQuery) error {
 				}
 			}
 
-			if valLower == "checkpwd" {
+			if valLower == "checkpwd" || valLower == "checkpwdlock" ||
+				valLower == "editdistance" || valLower == "jsonpath" {
 				child := &GraphQuery{
 					Args:  make(map[string]string),
 					Var:   varName,
@@ -2726,7 +3627,27 @@ func godeep(it *lex.ItemIterator, gq *GraphQuery) error {
 				gq.Children = append(gq.Children, child)
 				curp = nil
 				continue
-			} else if isAggregator(valLower) {
+			} else if valLower == histogramFunc {
+				if varName != "" {
+					return it.Errorf("histogram can't be used with a variable")
+				}
+				child := &GraphQuery{
+					Args:  make(map[string]string),
+					Alias: alias,
+				}
+				alias = ""
+				it.Next()
+				if it.Item().Typ != itemLeftRound {
+					it.Prev()
+					goto Fall
+				}
+				if err := parseHistogram(it, child); err != nil {
+					return err
+				}
+				gq.Children = append(gq.Children, child)
+				curp = nil
+				continue
+			} else if valLower == customFunc {
 				child := &GraphQuery{
 					Attr:       valueFunc,
 					Args:       make(map[string]string),
@@ -2740,8 +3661,38 @@ func godeep(it *lex.ItemIterator, gq *GraphQuery) error {
 					it.Prev()
 					goto Fall
 				}
-				it.Next()
 				if gq.IsGroupby {
+					return it.Errorf("Function custom not allowed inside @groupby")
+				}
+				if err := parseCustomFunc(it, child); err != nil {
+					return err
+				}
+				gq.Children = append(gq.Children, child)
+				curp = nil
+				continue
+			} else if isAggregator(valLower) || IsWindowFunc(valLower) {
+				child := &GraphQuery{
+					Attr:       valueFunc,
+					Args:       make(map[string]string),
+					Var:        varName,
+					IsInternal: true,
+					Alias:      alias,
+				}
+				varName, alias = "", ""
+				it.Next()
+				if it.Item().Typ != itemLeftRound {
+					it.Prev()
+					goto Fall
+				}
+				it.Next()
+				if gq.IsGroupby && IsWindowFunc(valLower) {
+					return it.Errorf("Window function %v not allowed inside @groupby", valLower)
+				}
+				// The distinct values of a predicate are counted from the sketch of the predicate
+				// in @approximate blocks.
+				distinctAttr := gq.Approximate && valLower == approxCountDistinctFunc &&
+					it.Item().Val != valueFunc
+				if gq.IsGroupby || distinctAttr {
 					item = it.Item()
 					attr := collectName(it, item.Val)
 					// Get language list, if present
@@ -2774,6 +3725,16 @@ func godeep(it *lex.ItemIterator, gq *GraphQuery) error {
 					Name:     valLower,
 					NeedsVar: child.NeedsVar,
 				}
+				if IsWindowFunc(valLower) {
+					// Window functions accept an optional sort order after the variable.
+					if err := parseWindowOrder(it, child.Func); err != nil {
+						return err
+					}
+				} else if !gq.IsGroupby && !distinctAttr {
+					if err := parseRollup(it, child.Func); err != nil {
+						return err
+					}
+				}
 				it.Next() // Skip the closing ')'
 				gq.Children = append(gq.Children, child)
 				curp = nil
@@ -2838,7 +3799,12 @@ func godeep(it *lex.ItemIterator, gq *GraphQuery) error {
 				case "_reverse_":
 					child.Expand = "_reverse_"
 				default:
-					return item.Errorf("Invalid argument %v in expand()", item.Val)
+					// expand("address.*") expands the predicates matching the pattern.
+					pattern, err := unquoteIfQuoted(item.Val)
+					if err != nil || pattern == item.Val || pattern == "" {
+						return item.Errorf("Invalid argument %v in expand()", item.Val)
+					}
+					child.Expand = strconv.Quote(pattern)
 				}
 				it.Next() // Consume ')'
 				gq.Children = append(gq.Children, child)
@@ -3000,6 +3966,9 @@ func godeep(it *lex.ItemIterator, gq *GraphQuery) error {
 
 				curp.Args[p.Key] = p.Val
 			}
+			if err := setCollation(curp); err != nil {
+				return it.Errorf("%v", err)
+			}
 		case itemAt:
 			err := parseDirective(it, curp)
 			if err != nil {
@@ -3017,7 +3986,148 @@ func godeep(it *lex.ItemIterator, gq *GraphQuery) error {
 }
 
 func isAggregator(fname string) bool {
-	return fname == "min" || fname == "max" || fname == "sum" || fname == "avg"
+	return fname == "min" || fname == "max" || fname == "sum" || fname == "avg" ||
+		fname == approxCountDistinctFunc
+}
+
+// IsWindowFunc returns true if fname is the name of a window function, which computes its value
+// over the values of the other nodes of the block, such as rank.
+func IsWindowFunc(fname string) bool {
+	switch fname {
+	case "rank", "dense_rank", "row_number", "cumsum":
+		return true
+	}
+	return false
+}
+
+// parseWindowOrder parses the optional ", asc" or ", desc" argument of a window function.
+func parseWindowOrder(it *lex.ItemIterator, f *Function) error {
+	items, err := it.Peek(1)
+	if err != nil || items[0].Typ != itemComma {
+		return nil
+	}
+	it.Next() // Consume the comma.
+	if !it.Next() {
+		return it.Errorf("Expected sort order in %v", f.Name)
+	}
+	item := it.Item()
+	order := strings.ToLower(item.Val)
+	if item.Typ != itemName || (order != "asc" && order != "desc") {
+		return item.Errorf("Expected asc or desc in %v. Got: %v", f.Name, item.Val)
+	}
+	f.Args = append(f.Args, Arg{Value: order})
+	return nil
+}
+
+// parseRollup parses the optional rollup option after the variable of an aggregation.
+func parseRollup(it *lex.ItemIterator, f *Function) error {
+	items, err := it.Peek(1)
+	if err != nil || items[0].Typ != itemComma {
+		return nil
+	}
+	it.Next() // Consume the comma.
+	if !it.Next() {
+		return it.Errorf("Expected rollup in %v", f.Name)
+	}
+	item := it.Item()
+	if item.Typ != itemName || strings.ToLower(item.Val) != rollupArg {
+		return item.Errorf("Expected rollup in %v. Got: %v", f.Name, item.Val)
+	}
+	f.Args = append(f.Args, Arg{Value: rollupArg})
+	return nil
+}
+
+// parseHistogram parses the arguments of histogram(predicate, buckets: [b1, b2, ...]), the
+// iterator being on its opening parenthesis. The boundaries of the buckets are the arguments of
+// the function.
+func parseHistogram(it *lex.ItemIterator, gq *GraphQuery) error {
+	if !it.Next() || it.Item().Typ != itemName {
+		return it.Errorf("Expected a predicate in histogram")
+	}
+	gq.Attr = collectName(it, it.Item().Val)
+	gq.Func = &Function{Name: histogramFunc, Attr: gq.Attr}
+	if !trySkipItemTyp(it, itemComma) || !trySkipItemVal(it, bucketsArg) ||
+		!trySkipItemTyp(it, itemColon) || !trySkipItemTyp(it, itemLeftSquare) {
+		return it.Errorf("Expected %s: [...] after the predicate of histogram", bucketsArg)
+	}
+
+	expectArg := true
+	for it.Next() {
+		item := it.Item()
+		switch {
+		case item.Typ == itemRightSquare && !expectArg:
+			if len(gq.Func.Args) < 2 {
+				return item.Errorf("histogram needs at least two bucket boundaries")
+			}
+			if !trySkipItemTyp(it, itemRightRound) {
+				return it.Errorf("Expected ) after the buckets of histogram")
+			}
+			return nil
+		case item.Typ == itemComma && !expectArg:
+			expectArg = true
+		case (item.Typ == itemName || item.Val == "-") && expectArg:
+			val := item.Val
+			if val == "-" {
+				if !it.Next() || it.Item().Typ != itemName {
+					return it.Errorf("Expected a number after - in the buckets of histogram")
+				}
+				val += it.Item().Val
+			}
+			val, err := unquoteIfQuoted(val)
+			if err != nil {
+				return err
+			}
+			gq.Func.Args = append(gq.Func.Args, Arg{Value: val})
+			expectArg = false
+		default:
+			return item.Errorf("Unexpected %v in the buckets of histogram", item.Val)
+		}
+	}
+	return it.Errorf("Expected ] after the buckets of histogram")
+}
+
+// parseCustomFunc parses the arguments of custom(resolver, val(a), ...), the iterator being
+// on its opening parenthesis.
+func parseCustomFunc(it *lex.ItemIterator, gq *GraphQuery) error {
+	it.Next()
+	item := it.Item()
+	if item.Typ != itemName || strings.ContainsRune(item.Val, '"') {
+		return item.Errorf("Expected the name of a resolver in custom. Got: %v", item.Val)
+	}
+	resolver := item.Val
+	for {
+		if !it.Next() {
+			return it.Errorf("Unexpected end of custom")
+		}
+		item = it.Item()
+		if item.Typ == itemRightRound {
+			break
+		}
+		if item.Typ != itemComma {
+			return item.Errorf("Expected comma in custom. Got: %v", item.Val)
+		}
+		it.Next()
+		if item = it.Item(); item.Val != valueFunc {
+			return item.Errorf("Only variables allowed in custom. Got: %v", item.Val)
+		}
+		count, err := parseVarList(it, gq)
+		if err != nil {
+			return err
+		}
+		if count != 1 {
+			return item.Errorf("Expected one variable inside val() of custom but got %v", count)
+		}
+		gq.NeedsVar[len(gq.NeedsVar)-1].Typ = ValueVar
+	}
+	if len(gq.NeedsVar) == 0 {
+		return item.Errorf("Function custom requires at least one value variable")
+	}
+	gq.Func = &Function{
+		Name:     customFunc,
+		Args:     []Arg{{Value: resolver}},
+		NeedsVar: gq.NeedsVar,
+	}
+	return nil
 }
 
 func isExpandFunc(name string) bool {
@@ -3040,6 +4150,11 @@ func isInequalityFn(name string) bool {
 	return false
 }
 
+// isContainsFn returns true for the functions matching the values of a list predicate.
+func isContainsFn(name string) bool {
+	return name == "containsany" || name == "containsall"
+}
+
 // Name can have dashes or alphanumeric characters. Lexer lexes them as separate items.
 // We put it back together here.
 func collectName(it *lex.ItemIterator, val string) string {
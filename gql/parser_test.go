@@ -18,9 +18,12 @@ package gql
 
 import (
 	"bytes"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"testing"
+	"time"
 
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgraph/chunker/rdf"
@@ -624,6 +627,68 @@ func TestParseQueryWithLevelAgg(t *testing.T) {
 	require.Equal(t, "s", res.Query[0].Children[1].Var)
 }
 
+func TestParseMathFuncVariadic(t *testing.T) {
+	query := `
+	{
+		me(func: uid(0x0a)) {
+			friends {
+				a as age
+				b as count(friend)
+				c as score
+				d: math(max(a, b, c) + 1)
+				e: math(round(a / b, 2) * abs(c))
+				f: math(cond(a > 50, 1, b > 10, 2, 3))
+				g: math(floor(c, -1) + ceil(c))
+			}
+		}
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	friends := res.Query[0].Children[0]
+	require.Equal(t, "(+ (max a b c) 1E+00)", friends.Children[3].MathExp.debugString())
+	require.Equal(t, "(* (round (/ a b) 2E+00) (abs c))",
+		friends.Children[4].MathExp.debugString())
+	require.Equal(t, "(cond (> a 5E+01) 1E+00 (> b 1E+01) 2E+00 3E+00)",
+		friends.Children[5].MathExp.debugString())
+	require.Equal(t, "(+ (floor c (u- 1E+00)) (ceil c))",
+		friends.Children[6].MathExp.debugString())
+}
+
+func TestParseMathStringFunc(t *testing.T) {
+	query := `
+	{
+		me(func: uid(0x0a)) {
+			n as name
+			s as surname
+			full: math(concat(lower(n), " ", trim(s)))
+			short: math(substr(n, 0, 3))
+			size: math(len(n) + 1)
+		}
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	children := res.Query[0].Children
+	require.Equal(t, `(concat (lower n) " " (trim s))`, children[2].MathExp.debugString())
+	require.Equal(t, "(substr n 0E+00 3E+00)", children[3].MathExp.debugString())
+	require.Equal(t, "(+ (len n) 1E+00)", children[4].MathExp.debugString())
+}
+
+func TestParseMathFuncWrongArgs(t *testing.T) {
+	tests := []string{
+		`{f(func: uid(1)) { a as age x: math(ln(a, 2)) }}`,
+		`{f(func: uid(1)) { a as age x: math(max(a)) }}`,
+		`{f(func: uid(1)) { a as age x: math(cond(a > 1, 1, a > 2, 2)) }}`,
+		`{f(func: uid(1)) { a as age x: math(round(a, 1, 2)) }}`,
+		`{f(func: uid(1)) { a as age x: math(substr(a, 1, 2, 3)) }}`,
+	}
+	for _, q := range tests {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
 func TestParseQueryWithVarValAggCombination(t *testing.T) {
 	query := `
 	{
@@ -696,6 +761,140 @@ func TestParseQueryWithVarValAgg(t *testing.T) {
 	require.Equal(t, "min", res.Query[1].Children[1].Func.Name)
 }
 
+func TestParseQueryWithWindowFunc(t *testing.T) {
+	query := `
+	{
+		var(func: anyofterms(name, "alice bob")) {
+			s as score
+		}
+
+		me(func: uid(s), orderdesc: val(s)) {
+			name
+			r as rank(val(s))
+			dense_rank(val(s))
+			pos: row_number(val(s), asc)
+			cumsum(val(s))
+			val(r)
+		}
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res.Query))
+	children := res.Query[1].Children
+	require.Equal(t, "r", children[1].Var)
+	require.Equal(t, "rank", children[1].Func.Name)
+	require.True(t, children[1].IsInternal)
+	require.Equal(t, "s", children[1].NeedsVar[0].Name)
+	require.Equal(t, ValueVar, children[1].NeedsVar[0].Typ)
+	require.Empty(t, children[1].Func.Args)
+	require.Equal(t, "dense_rank", children[2].Func.Name)
+	require.Equal(t, "pos", children[3].Alias)
+	require.Equal(t, "row_number", children[3].Func.Name)
+	require.Equal(t, []Arg{{Value: "asc"}}, children[3].Func.Args)
+	require.Equal(t, "cumsum", children[4].Func.Name)
+}
+
+func TestParseQueryWithWindowFuncBadOrder(t *testing.T) {
+	query := `
+	{
+		var(func: anyofterms(name, "alice bob")) {
+			s as score
+		}
+
+		me(func: uid(s)) {
+			rank(val(s), sideways)
+		}
+	}
+`
+	_, err := Parse(Request{Str: query})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected asc or desc")
+}
+
+func TestParseAggregationRollup(t *testing.T) {
+	query := `
+	{
+		me(func: uid(1)) {
+			category {
+				product {
+					r as revenue
+				}
+			}
+			total: sum(val(r), rollup)
+			max(val(r))
+		}
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	children := res.Query[0].Children
+	require.Equal(t, "total", children[1].Alias)
+	require.Equal(t, "sum", children[1].Func.Name)
+	require.Equal(t, []Arg{{Value: "rollup"}}, children[1].Func.Args)
+	require.Equal(t, "r", children[1].NeedsVar[0].Name)
+	require.Empty(t, children[2].Func.Args)
+
+	_, err = Parse(Request{Str: `{ me(func: uid(1)) { a as age sum(val(a), subtotal) } }`})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected rollup in sum")
+}
+
+func TestParseQueryWithCustomFunc(t *testing.T) {
+	query := `
+	{
+		var(func: anyofterms(name, "alice bob")) {
+			s as score
+			a as age
+		}
+
+		me(func: uid(s)) {
+			name
+			tier: custom(tiers, val(s), val(a))
+			c as custom(tiers, val(s))
+			val(c)
+		}
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	children := res.Query[1].Children
+	require.Equal(t, "tier", children[1].Alias)
+	require.True(t, children[1].IsInternal)
+	require.Equal(t, "custom", children[1].Func.Name)
+	require.Equal(t, []Arg{{Value: "tiers"}}, children[1].Func.Args)
+	require.Equal(t, []VarContext{{Name: "s", Typ: ValueVar}, {Name: "a", Typ: ValueVar}},
+		children[1].NeedsVar)
+	require.Equal(t, "c", children[2].Var)
+	require.Equal(t, "custom", children[2].Func.Name)
+}
+
+func TestParseQueryWithCustomFuncError(t *testing.T) {
+	tests := map[string]string{
+		"custom(tiers)":            "requires at least one value variable",
+		"custom(tiers, score)":     "Only variables allowed in custom",
+		"custom(tiers, val(s, s))": "Expected one variable",
+		"custom(tiers val(s))":     "Expected comma in custom",
+		`custom("tiers", val(s))`:  "Expected the name of a resolver",
+	}
+	for fn, msg := range tests {
+		query := `
+	{
+		var(func: anyofterms(name, "alice bob")) {
+			s as score
+		}
+
+		me(func: uid(s)) {
+			` + fn + `
+		}
+	}
+`
+		_, err := Parse(Request{Str: query})
+		require.Error(t, err, fn)
+		require.Contains(t, err.Error(), msg, fn)
+	}
+}
+
 func TestParseQueryWithVarValAggError(t *testing.T) {
 	query := `
 	{
@@ -1198,6 +1397,68 @@ func TestParseShortestPathWithUidVars(t *testing.T) {
 	require.Equal(t, 1, len(q.ShortestPathArgs.To.NeedsVar))
 }
 
+func TestParseShortestPathWeight(t *testing.T) {
+	query := `{
+		shortest(from: 0x1, to: 0x2, weight: math(dist / speed + 1), maxweight: 10) {
+			road @facets(dist)
+		}
+	}`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	q := res.Query[0]
+	require.NotNil(t, q.ShortestPathArgs.Weight)
+	require.Equal(t, "(+ (/ dist speed) 1E+00)", q.ShortestPathArgs.Weight.debugString())
+	require.Equal(t, "10", q.Args["maxweight"])
+}
+
+func TestParseShortestPathWeightError(t *testing.T) {
+	query := `{
+		shortest(from: 0x1, to: 0x2, weight: dist) {
+			road @facets(dist)
+		}
+	}`
+	_, err := Parse(Request{Str: query})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected math()")
+
+	query = `{
+		me(func: uid(0x1), weight: math(dist)) {
+			road
+		}
+	}`
+	_, err = Parse(Request{Str: query})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "weight only allowed for shortest path queries")
+}
+
+func TestParseShortestPathConstraints(t *testing.T) {
+	query := `{
+		blocked as var(func: eq(name, "Bob"))
+
+		shortest(from: 0x1, to: 0x2, through: uid(0x7, 0x5), avoid: uid(0x3, blocked)) {
+			friend(maxedges: 2)
+			road
+		}
+	}`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	q := res.Query[1]
+	require.Equal(t, []uint64{0x7, 0x5}, q.ShortestPathArgs.Through.UID)
+	require.Equal(t, []uint64{0x3}, q.ShortestPathArgs.Avoid.UID)
+	require.Equal(t, "blocked", q.ShortestPathArgs.Avoid.NeedsVar[0].Name)
+	require.Equal(t, "2", q.Children[0].Args["maxedges"])
+
+	query = `{
+		a as var(func: uid(0x7))
+		shortest(from: 0x1, to: 0x2, through: uid(a)) {
+			friend
+		}
+	}`
+	_, err = Parse(Request{Str: query})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "through in shortest path only accepts uids")
+}
+
 func TestParseShortestPathInvalidFnError(t *testing.T) {
 	query := `{
 		shortest(from: eq(a), to: uid(b)) {
@@ -2602,6 +2863,104 @@ func TestParseCheckPwd(t *testing.T) {
 	require.Equal(t, "password", gq.Query[0].Children[0].Attr)
 }
 
+func TestParseCheckPwdLock(t *testing.T) {
+	query := `{
+		me(func: uid(1)) {
+			match: checkpwdlock(password, "123456")
+		}
+	}
+`
+	gq, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, "checkpwdlock", gq.Query[0].Children[0].Func.Name)
+	require.True(t, gq.Query[0].Children[0].Func.IsPasswordVerifier())
+	require.Equal(t, "match", gq.Query[0].Children[0].Alias)
+	require.Equal(t, "password", gq.Query[0].Children[0].Attr)
+}
+
+func TestParseEditDistance(t *testing.T) {
+	query := `{
+		me(func: match(name, "Stephen", auto, transpositions)) {
+			d as editdistance(name@en, "Stephen", transpositions)
+		}
+		sorted(func: uid(d), orderasc: val(d)) {
+			name
+		}
+	}
+`
+	gq, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, "match", gq.Query[0].Func.Name)
+	require.Equal(t, 3, len(gq.Query[0].Func.Args))
+	require.Equal(t, "transpositions", gq.Query[0].Func.Args[2].Value)
+	child := gq.Query[0].Children[0]
+	require.True(t, child.Func.IsEditDistance())
+	require.Equal(t, "d", child.Var)
+	require.Equal(t, "name", child.Attr)
+	require.Equal(t, "en", child.Func.Lang)
+	require.Equal(t, []Arg{{Value: "Stephen"}, {Value: "transpositions"}, {Value: "name"}},
+		child.Func.Args)
+}
+
+func TestParseJSONPath(t *testing.T) {
+	query := `{
+		me(func: uid(1)) {
+			theme: jsonpath(settings, "$.ui.theme")
+			jsonpath(settings, "$.langs[0]")
+		}
+	}
+`
+	gq, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	children := gq.Query[0].Children
+	require.True(t, children[0].Func.IsJSONPath())
+	require.Equal(t, "theme", children[0].Alias)
+	require.Equal(t, "settings", children[0].Attr)
+	require.Equal(t, "$.ui.theme", children[0].Func.Args[0].Value)
+	require.Equal(t, "$.langs[0]", children[1].Func.Args[0].Value)
+}
+
+func TestParseContainsFunctions(t *testing.T) {
+	query := `{
+		me(func: containsall(tags, ["go", "graph"])) @filter(containsany(tags, "db", "kv")) {
+			name
+		}
+	}
+`
+	gq, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, "containsall", gq.Query[0].Func.Name)
+	require.Equal(t, []Arg{{Value: "go"}, {Value: "graph"}}, gq.Query[0].Func.Args)
+	require.Equal(t, "containsany", gq.Query[0].Filter.Func.Name)
+	require.Equal(t, []Arg{{Value: "db"}, {Value: "kv"}}, gq.Query[0].Filter.Func.Args)
+}
+
+func TestParseLenOfPredicate(t *testing.T) {
+	query := `{
+		var(func: has(tags)) {
+			l as friend
+		}
+		me(func: gt(len(tags), 2)) @filter(lt(len(l), 5) AND eq(len(scores), 1)) {
+			name
+		}
+	}
+`
+	gq, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	root := gq.Query[1]
+	require.True(t, root.Func.IsCount)
+	require.False(t, root.Func.IsLenVar)
+	require.Equal(t, "tags", root.Func.Attr)
+	require.Empty(t, root.NeedsVar)
+
+	// A variable keeps being the length of the variable.
+	require.True(t, root.Filter.Child[0].Func.IsLenVar)
+	require.Equal(t, "l", root.Filter.Child[0].Func.Attr)
+	require.True(t, root.Filter.Child[1].Func.IsCount)
+	require.Equal(t, "scores", root.Filter.Child[1].Func.Attr)
+	require.Equal(t, []string{"l"}, gq.QueryVars[1].Needs)
+}
+
 func TestParseComments(t *testing.T) {
 	query := `
 	# Something
@@ -2731,6 +3090,33 @@ func TestLangs(t *testing.T) {
 	require.Equal(t, []string{"en", "ru", "hu"}, gq.Query[0].Children[1].Langs)
 }
 
+func TestLangsChain(t *testing.T) {
+	query := `
+	query {
+		me(func: uid(1)) {
+			name@en,fr,.
+			bio@en,.,age
+			nick@en,nick@.
+		}
+	}
+	`
+
+	gq, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	children := gq.Query[0].Children
+	require.Equal(t, 5, len(children))
+	require.Equal(t, "name", children[0].Attr)
+	require.Equal(t, []string{"en", "fr", "."}, children[0].Langs)
+	require.Equal(t, "bio", children[1].Attr)
+	require.Equal(t, []string{"en", "."}, children[1].Langs)
+	require.Equal(t, "age", children[2].Attr)
+	require.Empty(t, children[2].Langs)
+	require.Equal(t, "nick", children[3].Attr)
+	require.Equal(t, []string{"en"}, children[3].Langs)
+	require.Equal(t, "nick", children[4].Attr)
+	require.Equal(t, []string{"."}, children[4].Langs)
+}
+
 func TestAllLangs(t *testing.T) {
 	query := `
 	query {
@@ -2992,6 +3378,464 @@ func TestParseNormalize(t *testing.T) {
 	require.True(t, res.Query[0].Normalize)
 }
 
+func TestParseTyped(t *testing.T) {
+	query := `
+	query {
+		me(func: uid( 0x3)) @typed @normalize {
+			friends {
+				name
+			}
+			gender
+		}
+}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.NotNil(t, res.Query[0])
+	require.True(t, res.Query[0].Typed)
+	require.True(t, res.Query[0].Normalize)
+}
+
+func TestParseApproximate(t *testing.T) {
+	res, err := Parse(Request{Str: `{ me(func: has(name)) @approximate { count(uid) } }`})
+	require.NoError(t, err)
+	require.True(t, res.Query[0].Approximate)
+	require.True(t, res.Query[0].UidCount)
+}
+
+func TestParseSample(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		a(func: has(name)) @sample(n: 20) { name }
+		b(func: has(name)) @filter(has(age)) @sample(percent: 0.5, seed: 42) { name }
+	}`})
+	require.NoError(t, err)
+	require.Equal(t, SampleArgs{Count: 20}, res.Query[0].SampleArgs)
+	require.Equal(t, SampleArgs{Percent: 0.5, Seed: 42, HasSeed: true}, res.Query[1].SampleArgs)
+
+	for _, q := range []string{
+		`{ q(func: has(name)) @sample { name } }`,
+		`{ q(func: has(name)) @sample(seed: 1) { name } }`,
+		`{ q(func: has(name)) @sample(n: 0) { name } }`,
+		`{ q(func: has(name)) @sample(percent: 150) { name } }`,
+		`{ q(func: has(name)) @sample(n: 1, percent: 10) { name } }`,
+		`{ q(func: has(name)) @sample(size: 1) { name } }`,
+	} {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseAt(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		a(func: has(name)) @at("2019-05-01T00:00:00Z") { name friend { name } }
+		b(func: has(name)) @filter(has(age)) @at(1556668800) { name }
+	}`})
+	require.NoError(t, err)
+	require.Equal(t, "2019-05-01T00:00:00Z", res.Query[0].At)
+	require.Equal(t, "1556668800", res.Query[1].At)
+
+	for _, q := range []string{
+		`{ q(func: has(name)) @at { name } }`,
+		`{ q(func: has(name)) @at() { name } }`,
+		`{ q(func: has(name)) @at(1, 2) { name } }`,
+	} {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseBudget(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		main(func: has(name)) { name }
+		side(func: has(name)) @besteffort { name }
+		recent(func: has(name)) @filter(has(age)) @budget(50ms) { name }
+	}`})
+	require.NoError(t, err)
+	require.False(t, res.Query[0].BestEffort)
+	require.True(t, res.Query[1].BestEffort)
+	require.Equal(t, time.Duration(0), res.Query[1].Budget)
+	// A block with a budget is also best effort.
+	require.True(t, res.Query[2].BestEffort)
+	require.Equal(t, 50*time.Millisecond, res.Query[2].Budget)
+
+	for _, q := range []string{
+		`{ q(func: has(name)) @budget { name } }`,
+		`{ q(func: has(name)) @budget(fast) { name } }`,
+		`{ q(func: has(name)) @budget(-1s) { name } }`,
+		`{ q(func: has(name)) @budget(1s, 2s) { name } }`,
+	} {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseSkipInclude(t *testing.T) {
+	query := `query q($details: bool = false, $friends: bool = true) {
+		me(func: uid(1)) {
+			name
+			age @include(if: $details)
+			friend @skip(if: $details) @include(if: $friends) {
+				name
+				dob @include(if: true)
+				alias @skip(if: true)
+			}
+		}
+		other(func: uid(2)) @include(if: $details) {
+			name
+		}
+	}`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Len(t, res.Query, 1)
+	require.Equal(t, []string{"name", "friend"}, childAttrs(res.Query[0]))
+	require.Equal(t, []string{"name", "dob"}, childAttrs(res.Query[0].Children[1]))
+
+	res, err = Parse(Request{Str: query, Variables: map[string]string{
+		"$details": "true", "$friends": "false"}})
+	require.NoError(t, err)
+	require.Len(t, res.Query, 2)
+	require.Equal(t, []string{"name", "age"}, childAttrs(res.Query[0]))
+
+	// The variables of a skipped block aren't defined.
+	_, err = Parse(Request{Str: `{
+		a as var(func: uid(1)) @skip(if: true)
+		me(func: uid(a)) { name }
+	}`})
+	require.Error(t, err)
+
+	for _, q := range []string{
+		`{ q(func: uid(1)) @skip { name } }`,
+		`{ q(func: uid(1)) @skip(true) { name } }`,
+		`{ q(func: uid(1)) @skip(if: maybe) { name } }`,
+		`{ q(func: uid(1)) @skip(if: $a) { name } }`,
+		`{ q(func: uid(1)) @skip(if: true) @skip(if: false) { name } }`,
+		`query q($a: bool) { q(func: uid(1)) { name @include(if: $a) } }`,
+		`query q($a: string = "yes") { q(func: uid(1)) { name @include(if: $a) } }`,
+	} {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseKeyFunc(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		q(func: key(User, "acme", "ann@x")) @filter(key(User, "acme", "bob@x")) { name }
+	}`})
+	require.NoError(t, err)
+	require.Equal(t, "key", res.Query[0].Func.Name)
+	require.Equal(t, "User", res.Query[0].Func.Attr)
+	require.Equal(t, 2, len(res.Query[0].Func.Args))
+	require.Equal(t, "acme", res.Query[0].Func.Args[0].Value)
+	require.Equal(t, "ann@x", res.Query[0].Func.Args[1].Value)
+	require.Equal(t, "key", res.Query[0].Filter.Func.Name)
+	require.Equal(t, "bob@x", res.Query[0].Filter.Func.Args[1].Value)
+
+	_, err = Parse(Request{Str: `{ q(func: key(User)) { name } }`})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "key function requires the values of the key of type User")
+}
+
+func TestParseOrderRandom(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		q(func: has(name), orderrandom: true, first: 20) {
+			friend(orderrandom: 42, first: 2) { name }
+		}
+	}`})
+	require.NoError(t, err)
+	require.Equal(t, "true", res.Query[0].Args["orderrandom"])
+	require.Equal(t, "42", res.Query[0].Children[0].Args["orderrandom"])
+}
+
+func TestParseSubgraph(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		subgraph(from: 0x1, depth: 2) { friend name }
+	}`})
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.True(t, gq.Subgraph)
+	require.Equal(t, []uint64{1}, gq.UID)
+	require.Equal(t, "uid", gq.Func.Name)
+	require.Equal(t, RecurseArgs{Depth: 3}, gq.RecurseArgs)
+	require.Empty(t, gq.Args)
+	require.Equal(t, []string{"friend", "name", "dgraph.type"}, childAttrs(gq))
+
+	res, err = Parse(Request{Str: `{
+		var(func: has(name)) { f as friend }
+		subgraph(from: uid(f)) { friend dgraph.type }
+	}`})
+	require.NoError(t, err)
+	gq = res.Query[1]
+	require.Equal(t, RecurseArgs{Depth: 2}, gq.RecurseArgs)
+	require.Equal(t, []VarContext{{Name: "f", Typ: UidVar}}, gq.NeedsVar)
+	require.Equal(t, []string{"friend", "dgraph.type"}, childAttrs(gq))
+
+	for _, q := range []string{
+		`{ subgraph(depth: 2) { friend } }`,
+		`{ subgraph(from: 0x1, depth: a) { friend } }`,
+		`{ subgraph(from: 0x1, to: 0x2) { friend } }`,
+		`{ subgraph(from: 0x1) @recurse(depth: 2) { friend } }`,
+		`{ q(from: 0x1) { friend } }`,
+	} {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseJoin(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		var(func: has(email)) { ue as email }
+		var(func: has(sent)) { ie as email }
+		pairs(left: val(ue), right: val(ie)) {
+			left { name }
+			right { sent }
+		}
+	}`})
+	require.NoError(t, err)
+	gq := res.Query[2]
+	require.Equal(t, JoinArgs{Left: "ue", Right: "ie"}, gq.JoinArgs)
+	require.Nil(t, gq.Func)
+	require.Equal(t, []VarContext{{Name: "ue", Typ: ValueVar}, {Name: "ie", Typ: ValueVar}},
+		gq.NeedsVar)
+	require.Equal(t, []string{"left", "right"}, childAttrs(gq))
+
+	for _, q := range []string{
+		`{ pairs(left: val(a)) { left { name } } }`,
+		`{ pairs(left: uid(a), right: val(b)) { left { name } } }`,
+		`{ pairs(left: val(a, c), right: val(b)) { left { name } } }`,
+		`{ pairs(func: has(name), left: val(a), right: val(b)) { left { name } } }`,
+	} {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseHasEdge(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		var(func: eq(name, "Alice")) { a as uid }
+		me(func: has(name)) @filter(not has_edge(friend, @filter(uid(a) or eq(age, 20)))) {
+			name
+			friend @filter(has_edge(~manager)) { name }
+		}
+	}`})
+	require.NoError(t, err)
+	require.Equal(t, `(NOT (has_edge friend (OR (uid) (eq age "20"))))`,
+		res.Query[1].Filter.debugString())
+	require.Equal(t, `(has_edge ~manager)`, res.Query[1].Children[1].Filter.debugString())
+
+	for _, q := range []string{
+		`{ me(func: has(name)) @filter(has_edge()) { name } }`,
+		`{ me(func: has(name)) @filter(has_edge(friend, eq(age, 20))) { name } }`,
+		`{ me(func: has(name)) @filter(has_edge(friend, @cascade(eq(age, 20)))) { name } }`,
+		`{ me(func: has(name)) @filter(has_edge(friend, @filter(eq(age, 20))) { name } }`,
+	} {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseHasEdgeOuter(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		me(func: has(salary)) @filter(has_edge(manager, @filter(lt(salary, outer(salary))))) {
+			name
+		}
+	}`})
+	require.NoError(t, err)
+	require.Equal(t, `(has_edge manager (lt salary outer(salary)))`,
+		res.Query[0].Filter.debugString())
+	require.Equal(t, []Arg{{Value: "salary", IsOuter: true}},
+		res.Query[0].Filter.Child[0].Func.Args)
+
+	_, err = Parse(Request{Str: `{
+		me(func: has(salary)) @filter(has_edge(manager, @filter(lt(outer(salary), 10)))) {
+			name
+		}
+	}`})
+	require.Error(t, err)
+}
+
+func TestParsePredicatePattern(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		me(func: has("address.*")) {
+			name
+			expand("address.*")
+		}
+	}`})
+	require.NoError(t, err)
+	require.Equal(t, "address.*", res.Query[0].Func.Attr)
+	require.Equal(t, `"address.*"`, res.Query[0].Children[1].Expand)
+
+	_, err = Parse(Request{Str: `{ me(func: has(name)) { expand("") } }`})
+	require.Error(t, err)
+}
+
+func TestParseOrderByCount(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		me(func: has(~follows), orderdesc: count(~follows), first: 100) {
+			name
+		}
+	}`})
+	require.NoError(t, err)
+	require.Len(t, res.Query[0].Order, 1)
+	order := res.Query[0].Order[0]
+	require.Equal(t, "follows", order.Attr)
+	require.True(t, order.Desc && order.Count && order.Reverse)
+
+	_, err = Parse(Request{Str: `{
+		me(func: has(follows), orderasc: count(follows), orderdesc: name) {
+			name
+		}
+	}`})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Sorting by a count can't be done with other orders")
+}
+
+func TestParseApproxCountDistinct(t *testing.T) {
+	res, err := Parse(Request{Str: `{
+		var(func: has(name)) { n as name }
+		q() { approx_count_distinct(val(n)) }
+		names(func: has(name)) @approximate { d: approx_count_distinct(name) }
+	}`})
+	require.NoError(t, err)
+	agg := res.Query[1].Children[0]
+	require.Equal(t, "approx_count_distinct", agg.Func.Name)
+	require.Equal(t, "n", agg.NeedsVar[0].Name)
+	agg = res.Query[2].Children[0]
+	require.Equal(t, "approx_count_distinct", agg.Func.Name)
+	require.Equal(t, "name", agg.Attr)
+	require.Equal(t, "d", agg.Alias)
+
+	// The distinct values of predicates are only counted in @approximate blocks.
+	_, err = Parse(Request{Str: `{ q(func: has(name)) { approx_count_distinct(name) } }`})
+	require.Error(t, err)
+}
+
+func TestParseDistinct(t *testing.T) {
+	res, err := Parse(Request{Str: `{ colors(func: distinct(color), first: 10) { n: count(uid) } }`})
+	require.NoError(t, err)
+	gq := res.Query[0]
+	require.Equal(t, "distinct", gq.Func.Name)
+	require.Equal(t, "color", gq.Func.Attr)
+	require.Equal(t, "10", gq.Args["first"])
+	require.True(t, gq.UidCount)
+	require.Equal(t, "n", gq.UidCountAlias)
+}
+
+func TestParseHistogram(t *testing.T) {
+	res, err := Parse(Request{Str: `{ me(func: has(age)) {
+		ages: histogram(age, buckets: [-10, 0, 18, 65])
+		dob: histogram(dob, buckets: ["1980-01-01", "2000-01-01"])
+	} }`})
+	require.NoError(t, err)
+	ages := res.Query[0].Children[0]
+	require.Equal(t, "histogram", ages.Func.Name)
+	require.Equal(t, "age", ages.Attr)
+	require.Equal(t, "ages", ages.Alias)
+	require.Equal(t, []Arg{{Value: "-10"}, {Value: "0"}, {Value: "18"}, {Value: "65"}},
+		ages.Func.Args)
+	dob := res.Query[0].Children[1]
+	require.Equal(t, []Arg{{Value: "1980-01-01"}, {Value: "2000-01-01"}}, dob.Func.Args)
+
+	tests := []string{
+		`{ me(func: has(age)) { histogram(age, buckets: [1]) } }`,
+		`{ me(func: has(age)) { histogram(age) } }`,
+		`{ me(func: has(age)) { histogram(age, buckets: [1, 2) } }`,
+		`{ me(func: has(age)) { histogram(age, buckets: [1 2]) } }`,
+	}
+	for _, q := range tests {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+	}
+}
+
+func TestParseAliasCollision(t *testing.T) {
+	tests := []string{
+		`{ me(func: uid(1)) { name: age name: alias } }`,
+		`{ me(func: uid(1)) { name name: alias } }`,
+		`{ me(func: uid(1)) { friend { name: age name } } }`,
+	}
+	for _, q := range tests {
+		_, err := Parse(Request{Str: q})
+		require.Error(t, err, q)
+		require.Contains(t, err.Error(), "Alias name collides with another field")
+	}
+
+	res, err := Parse(Request{Str: `{ me(func: uid(1)) { name: alias name@en n: name } }`})
+	require.NoError(t, err)
+	require.Empty(t, res.Warnings)
+}
+
+func TestParseNormalizeAliasWarning(t *testing.T) {
+	query := `
+	{
+		me(func: uid(1)) @normalize {
+			n: name
+			friend {
+				n: name
+				a: age
+			}
+		}
+	}`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Alias n is used more than once in @normalize block me" +
+		" and its values will be merged into a list"}, res.Warnings)
+}
+
+func TestParseUnusedVariableWarning(t *testing.T) {
+	query := `
+	query q($a: string, $b: int = 2, $id: string) {
+		me(func: uid($id)) @filter(eq(name, $a)) {
+			name
+		}
+	}`
+	res, err := Parse(Request{Str: query, Variables: map[string]string{"$id": "0x1"}})
+	require.NoError(t, err)
+	require.Equal(t, []string{"Variable $b is declared but not used"}, res.Warnings)
+
+	query = `query q($b: int = 2) { me(func: uid(0x1), first: $b) { name } }`
+	res, err = Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Empty(t, res.Warnings)
+}
+
+func TestParseNormalizeArgs(t *testing.T) {
+	query := `
+	{
+		me(func: uid(1)) @normalize(order: traversal, duplicates: suffix, dedupe: true) {
+			n: name
+		}
+	}`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.True(t, res.Query[0].Normalize)
+	require.Equal(t, NormalizeArgs{KeepOrder: true, Suffix: true, Dedupe: true},
+		res.Query[0].NormalizeArgs)
+
+	query = `
+	{
+		me(func: uid(1)) @normalize(prefix: true) {
+			n: name
+			friend {
+				n: name
+			}
+		}
+	}`
+	res, err = Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, NormalizeArgs{Prefix: true}, res.Query[0].NormalizeArgs)
+	require.Empty(t, res.Warnings)
+
+	query = `{ me(func: uid(1)) @normalize(order: random) { n: name } }`
+	_, err = Parse(Request{Str: query})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected sorted or traversal for order")
+
+	query = `{ me(func: uid(1)) @normalize(depth: 2) { n: name } }`
+	_, err = Parse(Request{Str: query})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Unexpected key: [depth] inside @normalize block")
+}
+
 func TestParseGroupbyRoot(t *testing.T) {
 	query := `
 	query {
@@ -3680,6 +4524,36 @@ func TestParseQueryWithAttrLang(t *testing.T) {
 	require.Equal(t, []string{"en", "fr"}, res.Query[0].Children[1].Order[0].Langs)
 }
 
+func TestParseOrderCollation(t *testing.T) {
+	query := `
+	{
+		me(func: uid(0x1), orderasc: name@sv, orderdesc: age, collation: "sv") {
+			name
+			friend(first:5, orderasc: name, collation: de) {
+				name
+			}
+		}
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Query))
+	root := res.Query[0]
+	require.Equal(t, 2, len(root.Order))
+	require.Equal(t, "sv", root.Order[0].Collation)
+	require.Equal(t, "sv", root.Order[1].Collation)
+	require.Equal(t, "sv", root.Args["collation"])
+	require.Equal(t, "de", root.Children[1].Order[0].Collation)
+
+	_, err = Parse(Request{Str: `{ me(func: uid(0x1), collation: "sv") { name } }`})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "without orderasc or orderdesc")
+
+	_, err = Parse(Request{Str: `{ me(func: uid(0x1), orderasc: name, collation: "x y") { name } }`})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Invalid collation")
+}
+
 func TestParseQueryWithAttrLang2(t *testing.T) {
 	query := `
 	{
@@ -3698,6 +4572,29 @@ func TestParseQueryWithAttrLang2(t *testing.T) {
 	require.Equal(t, []string{"en"}, res.Query[0].Order[0].Langs)
 }
 
+func TestParsePrefixSuffix(t *testing.T) {
+	query := `
+	{
+	  me(func: suffix(email, "@dgraph.io")) {
+	    name
+	    friend @filter(prefix(name@en, "Al")) {
+	      name@en
+	    }
+	  }
+	}
+`
+	res, err := Parse(Request{Str: query})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Query))
+	require.Equal(t, "suffix", res.Query[0].Func.Name)
+	require.Equal(t, "email", res.Query[0].Func.Attr)
+	require.Equal(t, "@dgraph.io", res.Query[0].Func.Args[0].Value)
+	filter := res.Query[0].Children[1].Filter.Func
+	require.Equal(t, "prefix", filter.Name)
+	require.Equal(t, "en", filter.Lang)
+	require.Equal(t, "Al", filter.Args[0].Value)
+}
+
 func TestParseRegexp1(t *testing.T) {
 	query := `
 	{
@@ -4025,6 +4922,27 @@ func TestParserFuzz(t *testing.T) {
 	}
 }
 
+// TestParserFuzzCorpus replays the corpus of the fuzzer, so that the inputs it found keep being
+// parsed without panics.
+func TestParserFuzzCorpus(t *testing.T) {
+	files, err := filepath.Glob("fuzz-data/corpus/*")
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		in, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("parser panic caused by %s, input: %q: %v\n%s", file, in, r,
+						debug.Stack())
+				}
+			}()
+			Parse(Request{Str: string(in)})
+		}()
+	}
+}
+
 func TestParseEqArg2(t *testing.T) {
 	query := `
 	{
@@ -4722,6 +5640,41 @@ func TestTypeFunctionError1(t *testing.T) {
 	require.Contains(t, err.Error(), "type function only supports one argument")
 }
 
+func TestXidFunction(t *testing.T) {
+	q := `
+	query {
+		me(func: xid("user-1", "user-2")) {
+			name
+			friend @filter(xid(email, "a@b.com")) {
+				name
+			}
+		}
+	}`
+	gq, err := Parse(Request{Str: q})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(gq.Query))
+	require.Equal(t, "xid", gq.Query[0].Func.Name)
+	require.Equal(t, "", gq.Query[0].Func.Attr)
+	require.Equal(t, []Arg{{Value: "user-1"}, {Value: "user-2"}}, gq.Query[0].Func.Args)
+
+	ft := gq.Query[0].Children[1].Filter
+	require.Equal(t, "xid", ft.Func.Name)
+	require.Equal(t, "email", ft.Func.Attr)
+	require.Equal(t, []Arg{{Value: "a@b.com"}}, ft.Func.Args)
+}
+
+func TestXidFunctionError(t *testing.T) {
+	q := `
+	query {
+		me(func: xid(email)) {
+			name
+		}
+	}`
+	_, err := Parse(Request{Str: q})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "xid function requires at least one external id")
+}
+
 func TestTypeInFilter(t *testing.T) {
 	q := `
 	query {
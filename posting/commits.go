@@ -0,0 +1,84 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package posting
+
+import (
+	"sync"
+
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// commits keeps the highest commit ts of the predicates written on this Alpha, so that the
+// result of a query can be told unchanged without running it again.
+var commits = struct {
+	sync.RWMutex
+	ts map[string]uint64
+	// generation changes when predicates are dropped or their schema changes, which changes
+	// the results without a commit.
+	generation uint64
+}{ts: make(map[string]uint64)}
+
+// Predicates returns the predicates written by the transaction.
+func (txn *Txn) Predicates() []string {
+	txn.cache.RLock()
+	defer txn.cache.RUnlock()
+	var preds []string
+	for key := range txn.cache.deltas {
+		pk := x.Parse([]byte(key))
+		if pk == nil || len(pk.Attr) == 0 || x.HasString(preds, pk.Attr) {
+			continue
+		}
+		preds = append(preds, pk.Attr)
+	}
+	return preds
+}
+
+// NoteCommit records that the predicates were written at commitTs. It must be called before
+// the max assigned ts reaches commitTs, so that the commit is noted once queries can read it.
+func NoteCommit(preds []string, commitTs uint64) {
+	commits.Lock()
+	defer commits.Unlock()
+	for _, pred := range preds {
+		if commits.ts[pred] < commitTs {
+			commits.ts[pred] = commitTs
+		}
+	}
+}
+
+// NoteSchemaChange records that predicates were dropped or that their schema changed. It must be
+// called once the change is applied, so that no query reads the old state after it.
+func NoteSchemaChange() {
+	commits.Lock()
+	defer commits.Unlock()
+	commits.generation++
+}
+
+// PredicateCommitTs returns the highest commit ts of the predicate on this Alpha, or 0 if it
+// wasn't written since the Alpha started.
+func PredicateCommitTs(pred string) uint64 {
+	commits.RLock()
+	defer commits.RUnlock()
+	return commits.ts[pred]
+}
+
+// SchemaGeneration returns the number of times predicates were dropped or their schema changed
+// since the Alpha started.
+func SchemaGeneration() uint64 {
+	commits.RLock()
+	defer commits.RUnlock()
+	return commits.generation
+}
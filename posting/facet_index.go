@@ -0,0 +1,210 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package posting
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/golang/glog"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/types/facets"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// The facet index of a predicate maps the tokens of the values of its indexed facets to the
+// nodes with an edge of the predicate having the facet. Its entries are kept with the index of
+// the predicate, under the tokens built by tok.FacetToken.
+
+// indexedFacets returns the facets declared with an index in the schema.
+func indexedFacets(su *pb.SchemaUpdate) []*pb.SchemaUpdate {
+	var out []*pb.SchemaUpdate
+	for _, f := range su.GetFacets() {
+		if len(f.Tokenizer) > 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// facetIndexTokens returns the tokens of the facet index for the facets of a posting. The
+// values which can't be converted to the declared type of their facet aren't indexed.
+func facetIndexTokens(decls []*pb.SchemaUpdate, fcs []*api.Facet) []string {
+	var tokens []string
+	for _, decl := range decls {
+		for _, f := range fcs {
+			if f.Key != decl.Predicate {
+				continue
+			}
+			cf, err := facets.ConvertTo(f, types.TypeID(decl.ValueType))
+			if err != nil {
+				break
+			}
+			val, err := facets.ValFor(cf)
+			if err != nil {
+				break
+			}
+			tokenizers, err := tok.GetTokenizers(decl.Tokenizer)
+			if err != nil {
+				break
+			}
+			for _, t := range tokenizers {
+				toks, err := tok.BuildTokens(val.Value, t)
+				if err != nil {
+					continue
+				}
+				for _, token := range toks {
+					tokens = append(tokens, tok.FacetToken(decl.Predicate, token))
+				}
+			}
+			break
+		}
+	}
+	return tokens
+}
+
+// facetTokens returns the tokens of the facet index for all the postings of the list.
+func (l *List) facetTokens(readTs uint64,
+	decls []*pb.SchemaUpdate) (map[string]struct{}, error) {
+	tokens := make(map[string]struct{})
+	err := l.Iterate(readTs, 0, func(p *pb.Posting) error {
+		for _, token := range facetIndexTokens(decls, p.Facets) {
+			tokens[token] = struct{}{}
+		}
+		return nil
+	})
+	return tokens, err
+}
+
+// updateFacetIndex updates the entries of the entity in the facet index of the predicate, from
+// the tokens it had before a mutation to the ones its list has now.
+func (txn *Txn) updateFacetIndex(ctx context.Context, l *List, attr string, entity uint64,
+	decls []*pb.SchemaUpdate, before map[string]struct{}) error {
+	after, err := l.facetTokens(txn.StartTs, decls)
+	if err != nil {
+		return err
+	}
+	added, deleted := x.Diff(after, before)
+	edge := &pb.DirectedEdge{ValueId: entity, Attr: attr, Op: pb.DirectedEdge_DEL}
+	for _, token := range deleted {
+		if err := txn.addIndexMutation(ctx, edge, token); err != nil {
+			return err
+		}
+	}
+	edge.Op = pb.DirectedEdge_SET
+	for _, token := range added {
+		if err := txn.addIndexMutation(ctx, edge, token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteFacetIndex deletes the facet index of the predicate.
+func deleteFacetIndex(attr string) error {
+	pk := x.ParsedKey{Attr: attr}
+	prefix := append(pk.IndexPrefix(), tok.IdentFacet)
+	if err := pstore.DropPrefix(prefix); err != nil {
+		return err
+	}
+
+	// Also delete all the parts of any list that has been split into multiple parts.
+	// Such keys have a different prefix (the last byte is set to 1).
+	prefix = pk.IndexPrefix()
+	prefix[len(prefix)-1] = x.ByteSplit
+	prefix = append(prefix, tok.IdentFacet)
+	return pstore.DropPrefix(prefix)
+}
+
+// facetIndexSpec returns a description of the indexed facets of the schema, which changes when
+// the facet index must be rebuilt.
+func facetIndexSpec(su *pb.SchemaUpdate) string {
+	var b strings.Builder
+	for _, f := range indexedFacets(su) {
+		fmt.Fprintf(&b, "%s:%d:%s;", f.Predicate, f.ValueType, strings.Join(f.Tokenizer, ","))
+	}
+	return b.String()
+}
+
+// needsFacetIndexRebuild tells what to do with the facet index when the schema changes. The
+// whole index is rebuilt when the declaration of any indexed facet changes.
+func (rb *IndexRebuild) needsFacetIndexRebuild() indexOp {
+	x.AssertTruef(rb.CurrentSchema != nil, "Current schema cannot be nil.")
+
+	prev, curr := facetIndexSpec(rb.OldSchema), facetIndexSpec(rb.CurrentSchema)
+	switch {
+	case prev == curr:
+		return indexNoop
+	case curr == "":
+		return indexDelete
+	default:
+		return indexRebuild
+	}
+}
+
+// prepareFacetIndex deletes the facet index, and returns its build if it's rebuilt.
+func prepareFacetIndex(rb *IndexRebuild) (*IndexBuild, error) {
+	op := rb.needsFacetIndexRebuild()
+	if op == indexNoop {
+		return nil, nil
+	}
+
+	glog.Infof("Deleting facet index for %s", rb.Attr)
+	if err := deleteFacetIndex(rb.Attr); err != nil {
+		return nil, err
+	}
+	if op == indexDelete {
+		return nil, nil
+	}
+
+	glog.Infof("Rebuilding facet index for %s", rb.Attr)
+	decls := indexedFacets(rb.CurrentSchema)
+	pk := x.ParsedKey{Attr: rb.Attr}
+	b := &IndexBuild{
+		Attr:     rb.Attr,
+		Kind:     "facets",
+		StartTs:  rb.StartTs,
+		prefixes: [][]byte{pk.DataPrefix()},
+	}
+	b.fn = func(ctx context.Context, _ []byte) func(uint64, *List, *Txn) error {
+		return func(uid uint64, pl *List, txn *Txn) error {
+			tokens, err := pl.facetTokens(txn.StartTs, decls)
+			if err != nil {
+				return err
+			}
+			edge := &pb.DirectedEdge{ValueId: uid, Attr: rb.Attr, Op: pb.DirectedEdge_SET}
+			for token := range tokens {
+				err := txn.addIndexMutation(ctx, edge, token)
+				for err == ErrRetry {
+					time.Sleep(10 * time.Millisecond)
+					err = txn.addIndexMutation(ctx, edge, token)
+				}
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+	return b, nil
+}
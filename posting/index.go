@@ -30,6 +30,7 @@ import (
 
 	"github.com/dgraph-io/badger"
 	bpb "github.com/dgraph-io/badger/pb"
+	"github.com/dgraph-io/dgraph/blob"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/tok"
@@ -209,6 +210,19 @@ func (l *List) handleDeleteAll(ctx context.Context, edge *pb.DirectedEdge,
 		Op:     edge.Op,
 		Entity: edge.Entity,
 	}
+	if decls := schema.State().IndexedFacets(edge.Attr); len(decls) > 0 {
+		// The entity has no facet left once its edges are deleted.
+		tokens, err := l.facetTokens(txn.StartTs, decls)
+		if err != nil {
+			return err
+		}
+		delFacet := &pb.DirectedEdge{ValueId: edge.Entity, Attr: edge.Attr, Op: edge.Op}
+		for token := range tokens {
+			if err := txn.addIndexMutation(ctx, delFacet, token); err != nil {
+				return err
+			}
+		}
+	}
 	// To calculate length of posting list. Used for deletion of count index.
 	var plen int
 	err := l.Iterate(txn.StartTs, 0, func(p *pb.Posting) error {
@@ -220,9 +234,9 @@ func (l *List) handleDeleteAll(ctx context.Context, edge *pb.DirectedEdge,
 			return txn.addReverseMutation(ctx, delEdge)
 		case isIndexed:
 			// Delete index edge of each posting.
-			val := types.Val{
-				Tid:   types.TypeID(p.ValType),
-				Value: p.Value,
+			val, err := ResolveValue(p)
+			if err != nil {
+				return err
 			}
 			return txn.addIndexMutations(ctx, &indexMutationInfo{
 				tokenizers: schema.State().Tokenizer(edge.Attr),
@@ -329,7 +343,12 @@ func (txn *Txn) addMutationHelper(ctx context.Context, l *List, doUpdateIndex bo
 			return val, found, emptyCountParams, err
 		}
 
-		if pFound && !(bytes.Equal(currPost.Value, newPost.Value) &&
+		newValue := newPost.Value
+		if pFound && currPost.Blob && !newPost.Blob {
+			// Offloaded values are stored by their key, compare the keys instead.
+			newValue = []byte(blob.Key(newPost.Value))
+		}
+		if pFound && !(bytes.Equal(currPost.Value, newValue) &&
 			types.TypeID(currPost.ValType) == types.TypeID(newPost.ValType)) {
 			return val, found, emptyCountParams, err
 		}
@@ -375,10 +394,27 @@ func (l *List) AddMutationWithIndex(ctx context.Context, edge *pb.DirectedEdge,
 
 	doUpdateIndex := pstore != nil && schema.State().IsIndexed(edge.Attr)
 	hasCountIndex := schema.State().HasCount(edge.Attr)
+	var facetDecls []*pb.SchemaUpdate
+	var facetsBefore map[string]struct{}
+	if pstore != nil {
+		facetDecls = schema.State().IndexedFacets(edge.Attr)
+	}
+	if len(facetDecls) > 0 {
+		var err error
+		if facetsBefore, err = l.facetTokens(txn.StartTs, facetDecls); err != nil {
+			return err
+		}
+	}
 	val, found, cp, err := txn.addMutationHelper(ctx, l, doUpdateIndex, hasCountIndex, edge)
 	if err != nil {
 		return err
 	}
+	if len(facetDecls) > 0 {
+		if err := txn.updateFacetIndex(ctx, l, edge.Attr, edge.Entity, facetDecls,
+			facetsBefore); err != nil {
+			return err
+		}
+	}
 	ostats.Record(ctx, x.NumEdges.M(1))
 	if hasCountIndex && cp.countAfter != cp.countBefore {
 		if err := txn.updateCount(ctx, cp); err != nil {
@@ -489,6 +525,9 @@ type rebuilder struct {
 	// The posting list passed here is the on disk version. It is not coming
 	// from the LRU cache.
 	fn func(uid uint64, pl *List, txn *Txn) error
+
+	// throttle, if set, is called before each posting list is read.
+	throttle func(ctx context.Context) error
 }
 
 func (r *rebuilder) Run(ctx context.Context) error {
@@ -511,6 +550,12 @@ func (r *rebuilder) Run(ctx context.Context) error {
 		default:
 		}
 
+		if r.throttle != nil {
+			if err := r.throttle(ctx); err != nil {
+				return nil, err
+			}
+		}
+
 		pk := x.Parse(key)
 		if pk == nil {
 			return nil, errors.Errorf("could not parse key %s", hex.Dump(key))
@@ -541,7 +586,24 @@ func (r *rebuilder) Run(ctx context.Context) error {
 	// Convert data into deltas.
 	txn.Update()
 
-	// Now we write all the created posting lists to disk.
+	// The writes are blocked while a prefix is dropped, which the schema updates of the other
+	// predicates do while the builds run in the background. They're written again once it's done.
+	for {
+		err := r.write(txn)
+		if errors.Cause(err) != badger.ErrBlockedWrites {
+			return err
+		}
+		glog.Infof("Rebuild: Writes of the index of %s are blocked, retrying.", r.attr)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// write writes the posting lists built in txn to disk.
+func (r *rebuilder) write(txn *Txn) error {
 	writer := NewTxnWriter(pstore)
 	for key, delta := range txn.cache.deltas {
 		if len(delta) == 0 {
@@ -576,16 +638,110 @@ const (
 
 // Run rebuilds all indices that need it.
 func (rb *IndexRebuild) Run(ctx context.Context) error {
-	if err := rebuildListType(ctx, rb); err != nil {
+	builds, err := rb.Prepare(ctx)
+	if err != nil {
 		return err
 	}
-	if err := rebuildIndex(ctx, rb); err != nil {
-		return err
+	for _, b := range builds {
+		if err := b.Run(ctx); err != nil {
+			return err
+		}
 	}
-	if err := rebuildReverseEdges(ctx, rb); err != nil {
-		return err
+	return nil
+}
+
+// Prepare converts the values of the predicate if it became a list, deletes the indices which
+// are removed or rebuilt, and returns the builds of the indices to rebuild. The builds read the
+// data at StartTs, so they can run in the background: the mutations applied after Prepare
+// already maintain the new indices.
+func (rb *IndexRebuild) Prepare(ctx context.Context) ([]*IndexBuild, error) {
+	if err := rebuildListType(ctx, rb); err != nil {
+		return nil, err
+	}
+	var builds []*IndexBuild
+	for _, prepare := range []func(*IndexRebuild) (*IndexBuild, error){
+		prepareIndex, prepareReverseEdges, prepareCountIndex, prepareFacetIndex,
+	} {
+		b, err := prepare(rb)
+		if err != nil {
+			return nil, err
+		}
+		if b != nil {
+			builds = append(builds, b)
+		}
+	}
+	return builds, nil
+}
+
+// IndexBuild builds an index of a predicate from its data, once the previous index is deleted.
+type IndexBuild struct {
+	Attr string
+	// Kind is the kind of the index: index, reverse, count or facets.
+	Kind string
+	// Tokenizers are the tokenizers of the index, if Kind is index.
+	Tokenizers []string
+	StartTs    uint64
+	// Throttle, if set, is called before each posting list is read. The build waits while it
+	// blocks, and stops if it returns an error.
+	Throttle func(ctx context.Context) error
+
+	prefixes [][]byte
+	// fn returns the function adding the index entries of a posting list read under prefix.
+	fn func(ctx context.Context, prefix []byte) func(uid uint64, pl *List, txn *Txn) error
+}
+
+// Run builds the index.
+func (b *IndexBuild) Run(ctx context.Context) error {
+	for _, prefix := range b.prefixes {
+		builder := rebuilder{
+			attr:     b.Attr,
+			prefix:   prefix,
+			startTs:  b.StartTs,
+			fn:       b.fn(ctx, prefix),
+			throttle: b.Throttle,
+		}
+		if err := builder.Run(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the entries written by the build, to drop an index whose build was stopped.
+func (b *IndexBuild) Delete() error {
+	switch b.Kind {
+	case "reverse":
+		return deleteReverseEdges(b.Attr)
+	case "count":
+		return deleteCountIndex(b.Attr)
+	case "facets":
+		return deleteFacetIndex(b.Attr)
+	}
+	for _, tokenizer := range b.Tokenizers {
+		if err := deleteTokensFor(b.Attr, tokenizer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountKeys returns the number of posting lists the build reads.
+func (b *IndexBuild) CountKeys() (uint64, error) {
+	txn := pstore.NewTransactionAt(b.StartTs, false)
+	defer txn.Discard()
+
+	var n uint64
+	for _, prefix := range b.prefixes {
+		iterOpt := badger.DefaultIteratorOptions
+		iterOpt.PrefetchValues = false
+		iterOpt.Prefix = prefix
+		itr := txn.NewIterator(iterOpt)
+		for itr.Rewind(); itr.Valid(); itr.Next() {
+			n++
+		}
+		itr.Close()
 	}
-	return rebuildCountIndex(ctx, rb)
+	return n, nil
 }
 
 type indexRebuildInfo struct {
@@ -648,6 +804,15 @@ func (rb *IndexRebuild) needsIndexRebuild() indexRebuildInfo {
 
 	newTokenizers, deletedTokenizers := x.Diff(currTokens, prevTokens)
 
+	// The exact index holds collation keys, so it's rebuilt when the collation changes.
+	exact := tok.ExactTokenizer{}.Name()
+	_, currExact := currTokens[exact]
+	_, prevExact := prevTokens[exact]
+	if currExact && prevExact && rb.CurrentSchema.Collation != old.Collation {
+		newTokenizers = append(newTokenizers, exact)
+		deletedTokenizers = append(deletedTokenizers, exact)
+	}
+
 	// If the tokenizers are the same, nothing needs to be done.
 	if len(newTokenizers) == 0 && len(deletedTokenizers) == 0 {
 		return indexRebuildInfo{
@@ -665,29 +830,39 @@ func (rb *IndexRebuild) needsIndexRebuild() indexRebuildInfo {
 // rebuildIndex rebuilds index for a given attribute.
 // We commit mutations with startTs and ignore the errors.
 func rebuildIndex(ctx context.Context, rb *IndexRebuild) error {
+	b, err := prepareIndex(rb)
+	if err != nil || b == nil {
+		return err
+	}
+	return b.Run(ctx)
+}
+
+// prepareIndex deletes the tokenizers of the index which are removed or rebuilt, and returns
+// the build of the rebuilt ones, if any.
+func prepareIndex(rb *IndexRebuild) (*IndexBuild, error) {
 	// Exit early if indices do not need to be rebuilt.
 	rebuildInfo := rb.needsIndexRebuild()
 
 	if rebuildInfo.op == indexNoop {
-		return nil
+		return nil, nil
 	}
 
 	glog.Infof("Deleting index for attr %s and tokenizers %s", rb.Attr,
 		rebuildInfo.tokenizersToDelete)
 	for _, tokenizer := range rebuildInfo.tokenizersToDelete {
 		if err := deleteTokensFor(rb.Attr, tokenizer); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	// Exit early if the index only need to be deleted and not rebuilt.
 	if rebuildInfo.op == indexDelete {
-		return nil
+		return nil, nil
 	}
 
 	// Exit early if there are no tokenizers to rebuild.
 	if len(rebuildInfo.tokenizersToRebuild) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	glog.Infof("Rebuilding index for attr %s and tokenizers %s", rb.Attr,
@@ -695,43 +870,54 @@ func rebuildIndex(ctx context.Context, rb *IndexRebuild) error {
 	// Before rebuilding, the existing index needs to be deleted.
 	for _, tokenizer := range rebuildInfo.tokenizersToRebuild {
 		if err := deleteTokensFor(rb.Attr, tokenizer); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
 	tokenizers, err := tok.GetTokenizers(rebuildInfo.tokenizersToRebuild)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	for i, t := range tokenizers {
+		tokenizers[i] = tok.GetCollatedTokenizer(t, rb.CurrentSchema.Collation)
 	}
 
 	pk := x.ParsedKey{Attr: rb.Attr}
-	builder := rebuilder{attr: rb.Attr, prefix: pk.DataPrefix(), startTs: rb.StartTs}
-	builder.fn = func(uid uint64, pl *List, txn *Txn) error {
-		edge := pb.DirectedEdge{Attr: rb.Attr, Entity: uid}
-		return pl.Iterate(txn.StartTs, 0, func(p *pb.Posting) error {
-			// Add index entries based on p.
-			val := types.Val{
-				Value: p.Value,
-				Tid:   types.TypeID(p.ValType),
-			}
-
-			for {
-				err := txn.addIndexMutations(ctx, &indexMutationInfo{
-					tokenizers: tokenizers,
-					edge:       &edge,
-					val:        val,
-					op:         pb.DirectedEdge_SET,
-				})
-				switch err {
-				case ErrRetry:
-					time.Sleep(10 * time.Millisecond)
-				default:
+	b := &IndexBuild{
+		Attr:       rb.Attr,
+		Kind:       "index",
+		Tokenizers: rebuildInfo.tokenizersToRebuild,
+		StartTs:    rb.StartTs,
+		prefixes:   [][]byte{pk.DataPrefix()},
+	}
+	b.fn = func(ctx context.Context, _ []byte) func(uint64, *List, *Txn) error {
+		return func(uid uint64, pl *List, txn *Txn) error {
+			edge := pb.DirectedEdge{Attr: rb.Attr, Entity: uid}
+			return pl.Iterate(txn.StartTs, 0, func(p *pb.Posting) error {
+				// Add index entries based on p.
+				val, err := ResolveValue(p)
+				if err != nil {
 					return err
 				}
-			}
-		})
+
+				for {
+					err := txn.addIndexMutations(ctx, &indexMutationInfo{
+						tokenizers: tokenizers,
+						edge:       &edge,
+						val:        val,
+						op:         pb.DirectedEdge_SET,
+					})
+					switch err {
+					case ErrRetry:
+						time.Sleep(10 * time.Millisecond)
+					default:
+						return err
+					}
+				}
+			})
+		}
 	}
-	return builder.Run(ctx)
+	return b, nil
 }
 
 func (rb *IndexRebuild) needsCountIndexRebuild() indexOp {
@@ -761,60 +947,65 @@ func (rb *IndexRebuild) needsCountIndexRebuild() indexOp {
 
 // rebuildCountIndex rebuilds the count index for a given attribute.
 func rebuildCountIndex(ctx context.Context, rb *IndexRebuild) error {
+	b, err := prepareCountIndex(rb)
+	if err != nil || b == nil {
+		return err
+	}
+	return b.Run(ctx)
+}
+
+// prepareCountIndex deletes the count index, and returns its build if it's rebuilt.
+func prepareCountIndex(rb *IndexRebuild) (*IndexBuild, error) {
 	op := rb.needsCountIndexRebuild()
 	if op == indexNoop {
-		return nil
+		return nil, nil
 	}
 
 	glog.Infof("Deleting count index for %s", rb.Attr)
 	if err := deleteCountIndex(rb.Attr); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Exit early if attribute is index only needed to be deleted.
 	if op == indexDelete {
-		return nil
+		return nil, nil
 	}
 
 	glog.Infof("Rebuilding count index for %s", rb.Attr)
-	var reverse bool
-	fn := func(uid uint64, pl *List, txn *Txn) error {
-		t := &pb.DirectedEdge{
-			ValueId: uid,
-			Attr:    rb.Attr,
-			Op:      pb.DirectedEdge_SET,
-		}
-		sz := pl.Length(rb.StartTs, 0)
-		if sz == -1 {
-			return nil
-		}
-		for {
-			err := txn.addCountMutation(ctx, t, uint32(sz), reverse)
-			switch err {
-			case ErrRetry:
-				time.Sleep(10 * time.Millisecond)
-			default:
-				return err
+	// The forward index is created first. The count reverse index is created if this
+	// predicate has both a count and reverse directive in the schema. It's safe
+	// to read the reverse prefix even if that's not the case as it will be empty.
+	pk := x.ParsedKey{Attr: rb.Attr}
+	b := &IndexBuild{
+		Attr:     rb.Attr,
+		Kind:     "count",
+		StartTs:  rb.StartTs,
+		prefixes: [][]byte{pk.DataPrefix(), pk.ReversePrefix()},
+	}
+	b.fn = func(ctx context.Context, prefix []byte) func(uint64, *List, *Txn) error {
+		reverse := bytes.Equal(prefix, pk.ReversePrefix())
+		return func(uid uint64, pl *List, txn *Txn) error {
+			t := &pb.DirectedEdge{
+				ValueId: uid,
+				Attr:    rb.Attr,
+				Op:      pb.DirectedEdge_SET,
+			}
+			sz := pl.Length(rb.StartTs, 0)
+			if sz == -1 {
+				return nil
+			}
+			for {
+				err := txn.addCountMutation(ctx, t, uint32(sz), reverse)
+				switch err {
+				case ErrRetry:
+					time.Sleep(10 * time.Millisecond)
+				default:
+					return err
+				}
 			}
 		}
 	}
-
-	// Create the forward index.
-	pk := x.ParsedKey{Attr: rb.Attr}
-	builder := rebuilder{attr: rb.Attr, prefix: pk.DataPrefix(), startTs: rb.StartTs}
-	builder.fn = fn
-	if err := builder.Run(ctx); err != nil {
-		return err
-	}
-
-	// Create the reverse index. The count reverse index is created if this
-	// predicate has both a count and reverse directive in the schema. It's safe
-	// to call builder.Run even if that's not the case as the reverse prefix
-	// will be empty.
-	reverse = true
-	builder = rebuilder{attr: rb.Attr, prefix: pk.ReversePrefix(), startTs: rb.StartTs}
-	builder.fn = fn
-	return builder.Run(ctx)
+	return b, nil
 }
 
 func (rb *IndexRebuild) needsReverseEdgesRebuild() indexOp {
@@ -845,46 +1036,62 @@ func (rb *IndexRebuild) needsReverseEdgesRebuild() indexOp {
 
 // rebuildReverseEdges rebuilds the reverse edges for a given attribute.
 func rebuildReverseEdges(ctx context.Context, rb *IndexRebuild) error {
+	b, err := prepareReverseEdges(rb)
+	if err != nil || b == nil {
+		return err
+	}
+	return b.Run(ctx)
+}
+
+// prepareReverseEdges deletes the reverse edges, and returns their build if they're rebuilt.
+func prepareReverseEdges(rb *IndexRebuild) (*IndexBuild, error) {
 	op := rb.needsReverseEdgesRebuild()
 	if op == indexNoop {
-		return nil
+		return nil, nil
 	}
 
 	glog.Infof("Deleting reverse index for %s", rb.Attr)
 	if err := deleteReverseEdges(rb.Attr); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Exit early if index only needed to be deleted.
 	if op == indexDelete {
-		return nil
+		return nil, nil
 	}
 
 	glog.Infof("Rebuilding reverse index for %s", rb.Attr)
 	pk := x.ParsedKey{Attr: rb.Attr}
-	builder := rebuilder{attr: rb.Attr, prefix: pk.DataPrefix(), startTs: rb.StartTs}
-	builder.fn = func(uid uint64, pl *List, txn *Txn) error {
-		edge := pb.DirectedEdge{Attr: rb.Attr, Entity: uid}
-		return pl.Iterate(txn.StartTs, 0, func(pp *pb.Posting) error {
-			puid := pp.Uid
-			// Add reverse entries based on p.
-			edge.ValueId = puid
-			edge.Op = pb.DirectedEdge_SET
-			edge.Facets = pp.Facets
-			edge.Label = pp.Label
-
-			for {
-				err := txn.addReverseMutation(ctx, &edge)
-				switch err {
-				case ErrRetry:
-					time.Sleep(10 * time.Millisecond)
-				default:
-					return err
+	b := &IndexBuild{
+		Attr:     rb.Attr,
+		Kind:     "reverse",
+		StartTs:  rb.StartTs,
+		prefixes: [][]byte{pk.DataPrefix()},
+	}
+	b.fn = func(ctx context.Context, _ []byte) func(uint64, *List, *Txn) error {
+		return func(uid uint64, pl *List, txn *Txn) error {
+			edge := pb.DirectedEdge{Attr: rb.Attr, Entity: uid}
+			return pl.Iterate(txn.StartTs, 0, func(pp *pb.Posting) error {
+				puid := pp.Uid
+				// Add reverse entries based on p.
+				edge.ValueId = puid
+				edge.Op = pb.DirectedEdge_SET
+				edge.Facets = pp.Facets
+				edge.Label = pp.Label
+
+				for {
+					err := txn.addReverseMutation(ctx, &edge)
+					switch err {
+					case ErrRetry:
+						time.Sleep(10 * time.Millisecond)
+					default:
+						return err
+					}
 				}
-			}
-		})
+			})
+		}
 	}
-	return builder.Run(ctx)
+	return b, nil
 }
 
 // needsListTypeRebuild returns true if the schema changed from a scalar to a
@@ -944,10 +1151,15 @@ func rebuildListType(ctx context.Context, rb *IndexRebuild) error {
 		if err := pl.addMutation(ctx, txn, t); err != nil {
 			return err
 		}
-		// Add the new edge with the fingerprinted value id.
+		// Add the new edge with the fingerprinted value id. Values of lists aren't offloaded,
+		// so they're fingerprinted by their content.
+		val, err := ResolveValue(mpost)
+		if err != nil {
+			return err
+		}
 		newEdge := &pb.DirectedEdge{
 			Attr:      rb.Attr,
-			Value:     mpost.Value,
+			Value:     val.Value.([]byte),
 			ValueType: mpost.ValType,
 			Op:        pb.DirectedEdge_SET,
 			Label:     mpost.Label,
@@ -0,0 +1,299 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package posting
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/golang/glog"
+
+	"github.com/dgraph-io/badger"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+)
+
+// maxReportedEntries is the number of missing and orphan entries listed in an IndexReport.
+// All of them are counted.
+const maxReportedEntries = 100
+
+// IndexEntry is an entry of the index or of the reverse edges of a predicate.
+type IndexEntry struct {
+	// Index is the tokenizer of the index, or reverse.
+	Index string `json:"index"`
+	// Token is the token of the index entry, or the object of the reverse edge.
+	Token string `json:"token"`
+	// Uid is the subject of the entry.
+	Uid string `json:"uid"`
+
+	key []byte
+	uid uint64
+}
+
+// IndexReport lists the differences between the index and the reverse edges of a predicate,
+// and the ones its data should have.
+type IndexReport struct {
+	Predicate string `json:"predicate"`
+	ReadTs    uint64 `json:"read_ts"`
+	// DataKeys and IndexKeys are the number of posting lists of data and of indices read.
+	DataKeys  uint64 `json:"data_keys"`
+	IndexKeys uint64 `json:"index_keys"`
+	// NumMissing is the number of entries the data should have in the indices, which are missing.
+	NumMissing uint64       `json:"num_missing"`
+	Missing    []IndexEntry `json:"missing,omitempty"`
+	// NumOrphans is the number of entries of the indices which don't match the data.
+	NumOrphans uint64       `json:"num_orphans"`
+	Orphans    []IndexEntry `json:"orphans,omitempty"`
+	// Repaired is the number of entries added or deleted by Repair.
+	Repaired uint64 `json:"repaired"`
+
+	missing []IndexEntry
+	orphans []IndexEntry
+}
+
+// CheckIndex compares the index and the reverse edges of the predicate with the entries its
+// data should have at readTs. The entries of the indices the schema doesn't have are orphans.
+func CheckIndex(ctx context.Context, attr string, readTs uint64) (*IndexReport, error) {
+	r := &IndexReport{Predicate: attr, ReadTs: readTs}
+	txn := pstore.NewTransactionAt(readTs, false)
+	defer txn.Discard()
+
+	// The entries the data should have, by key of the index.
+	expected := make(map[string]map[uint64]struct{})
+	expect := func(key []byte, uid uint64) {
+		uids, ok := expected[string(key)]
+		if !ok {
+			uids = make(map[uint64]struct{})
+			expected[string(key)] = uids
+		}
+		uids[uid] = struct{}{}
+	}
+
+	var tokenizers []tok.Tokenizer
+	if schema.State().IsIndexed(attr) {
+		tokenizers = schema.State().Tokenizer(attr)
+	}
+	reversed := schema.State().IsReversed(attr)
+	facetDecls := schema.State().IndexedFacets(attr)
+	pk := x.ParsedKey{Attr: attr}
+	err := iteratePostingLists(ctx, txn, pk.DataPrefix(), func(key []byte, l *List) error {
+		r.DataKeys++
+		uid := x.Parse(key).Uid
+		return l.Iterate(readTs, 0, func(p *pb.Posting) error {
+			if reversed && p.PostingType == pb.Posting_REF {
+				expect(x.ReverseKey(attr, p.Uid), uid)
+			}
+			for _, token := range facetIndexTokens(facetDecls, p.Facets) {
+				expect(x.IndexKey(attr, token), uid)
+			}
+			if len(tokenizers) == 0 || p.PostingType == pb.Posting_REF {
+				return nil
+			}
+			val, err := ResolveValue(p)
+			if err != nil {
+				return err
+			}
+			tokens, err := indexTokens(&indexMutationInfo{
+				tokenizers: tokenizers,
+				edge:       &pb.DirectedEdge{Attr: attr, Entity: uid, Lang: string(p.LangTag)},
+				val:        val,
+			})
+			if err != nil {
+				// The value isn't indexable, as when it's added.
+				return nil
+			}
+			for _, token := range tokens {
+				expect(x.IndexKey(attr, token), uid)
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, prefix := range [][]byte{pk.IndexPrefix(), pk.ReversePrefix()} {
+		err := iteratePostingLists(ctx, txn, prefix, func(key []byte, l *List) error {
+			r.IndexKeys++
+			want := expected[string(key)]
+			delete(expected, string(key))
+			got := make(map[uint64]struct{})
+			err := l.Iterate(readTs, 0, func(p *pb.Posting) error {
+				got[p.Uid] = struct{}{}
+				if _, ok := want[p.Uid]; !ok {
+					r.orphans = append(r.orphans, newIndexEntry(key, p.Uid))
+				}
+				return nil
+			})
+			for uid := range want {
+				if _, ok := got[uid]; !ok {
+					r.missing = append(r.missing, newIndexEntry(key, uid))
+				}
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	for key, uids := range expected {
+		for uid := range uids {
+			r.missing = append(r.missing, newIndexEntry([]byte(key), uid))
+		}
+	}
+
+	r.NumMissing = uint64(len(r.missing))
+	r.NumOrphans = uint64(len(r.orphans))
+	r.Missing = reportedEntries(r.missing)
+	r.Orphans = reportedEntries(r.orphans)
+	return r, nil
+}
+
+// iteratePostingLists calls fn with each posting list under the prefix.
+func iteratePostingLists(ctx context.Context, txn *badger.Txn, prefix []byte,
+	fn func(key []byte, l *List) error) error {
+	iterOpt := badger.DefaultIteratorOptions
+	iterOpt.AllVersions = true
+	iterOpt.Prefix = prefix
+	it := txn.NewIterator(iterOpt)
+	defer it.Close()
+
+	for it.Rewind(); it.Valid(); {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		key := it.Item().KeyCopy(nil)
+		l, err := ReadPostingList(key, it)
+		if err != nil {
+			return err
+		}
+		if err := fn(key, l); err != nil {
+			return err
+		}
+		// Skip the versions of the key ReadPostingList didn't need.
+		for it.Valid() && bytes.Equal(it.Item().Key(), key) {
+			it.Next()
+		}
+	}
+	return nil
+}
+
+func newIndexEntry(key []byte, uid uint64) IndexEntry {
+	e := IndexEntry{Uid: fmt.Sprintf("%#x", uid), key: key, uid: uid}
+	pk := x.Parse(key)
+	switch {
+	case pk == nil:
+	case pk.IsReverse():
+		e.Index = "reverse"
+		e.Token = fmt.Sprintf("%#x", pk.Uid)
+	case len(pk.Term) > 0:
+		e.Index = fmt.Sprintf("%#x", pk.Term[0])
+		if t, ok := tok.GetTokenizerByID(pk.Term[0]); ok {
+			e.Index = t.Name()
+		} else if pk.Term[0] == tok.IdentFacet {
+			e.Index = "facets"
+		}
+		e.Token = fmt.Sprintf("%q", pk.Term[1:])
+	}
+	return e
+}
+
+// reportedEntries returns the first entries, sorted by index and token.
+func reportedEntries(entries []IndexEntry) []IndexEntry {
+	sort.Slice(entries, func(i, j int) bool {
+		if c := bytes.Compare(entries[i].key, entries[j].key); c != 0 {
+			return c < 0
+		}
+		return entries[i].uid < entries[j].uid
+	})
+	if len(entries) > maxReportedEntries {
+		return entries[:maxReportedEntries]
+	}
+	return entries
+}
+
+// Repair adds the missing entries and deletes the orphan ones, writing them at ts. The entries
+// of the subjects whose data changed since the check are skipped, they're checked again on the
+// next run.
+func (r *IndexReport) Repair(ctx context.Context, ts uint64) error {
+	if ts <= r.ReadTs {
+		return errors.Errorf("Repair timestamp %d must be after the check at %d", ts, r.ReadTs)
+	}
+	read := pstore.NewTransactionAt(math.MaxUint64, false)
+	defer read.Discard()
+	changed := func(uid uint64) (bool, error) {
+		item, err := read.Get(x.DataKey(r.Predicate, uid))
+		switch {
+		case err == badger.ErrKeyNotFound:
+			return false, nil
+		case err != nil:
+			return false, err
+		}
+		return item.Version() > r.ReadTs, nil
+	}
+
+	txn := NewTxn(ts)
+	fix := func(entries []IndexEntry, op pb.DirectedEdge_Op) error {
+		for _, e := range entries {
+			skip, err := changed(e.uid)
+			if err != nil {
+				return err
+			}
+			if skip {
+				glog.V(2).Infof("Skipping repair of %s %s, data of %s changed",
+					e.Index, e.Token, e.Uid)
+				continue
+			}
+			plist, err := txn.cache.GetFromDelta(e.key)
+			if err != nil {
+				return err
+			}
+			edge := &pb.DirectedEdge{ValueId: e.uid, Attr: r.Predicate, Op: op}
+			if err := plist.addMutation(ctx, txn, edge); err != nil {
+				return err
+			}
+			r.Repaired++
+		}
+		return nil
+	}
+	if err := fix(r.missing, pb.DirectedEdge_SET); err != nil {
+		return err
+	}
+	if err := fix(r.orphans, pb.DirectedEdge_DEL); err != nil {
+		return err
+	}
+	txn.Update()
+
+	writer := NewTxnWriter(pstore)
+	for key, delta := range txn.cache.deltas {
+		if len(delta) == 0 {
+			continue
+		}
+		if err := writer.SetAt([]byte(key), delta, BitDeltaPosting, ts); err != nil {
+			return err
+		}
+	}
+	glog.Infof("Repaired %d entries of the indices of predicate %s at ts %d",
+		r.Repaired, r.Predicate, ts)
+	return writer.Flush()
+}
@@ -360,6 +360,144 @@ func TestRebuildIndexWithDeletion(t *testing.T) {
 	require.Len(t, idxVals, 0)
 }
 
+func TestIndexBuildCatchUp(t *testing.T) {
+	addEdgeToValue(t, "name3", 91, "Michonne", uint64(1), uint64(2))
+	addEdgeToValue(t, "name3", 92, "David", uint64(3), uint64(4))
+
+	require.NoError(t, schema.ParseBytes([]byte("name3: string @index(term) ."), 1))
+	currentSchema, _ := schema.State().Get("name3")
+	rb := IndexRebuild{
+		Attr:          "name3",
+		StartTs:       5,
+		OldSchema:     nil,
+		CurrentSchema: &currentSchema,
+	}
+	builds, err := rb.Prepare(context.Background())
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+	require.Equal(t, "index", builds[0].Kind)
+	require.Equal(t, []string{"term"}, builds[0].Tokenizers)
+	n, err := builds[0].CountKeys()
+	require.NoError(t, err)
+	require.EqualValues(t, 2, n)
+
+	// A mutation applied after the schema update, before the index is built, maintains it.
+	l, err := GetNoStore(x.DataKey("name3", 92))
+	require.NoError(t, err)
+	edge := &pb.DirectedEdge{Value: []byte("Glenn"), Attr: "name3", Entity: 92}
+	addMutation(t, l, edge, Set, 6, 7, true)
+
+	var keys int
+	builds[0].Throttle = func(ctx context.Context) error {
+		keys++
+		return nil
+	}
+	require.NoError(t, builds[0].Run(context.Background()))
+	require.Equal(t, 2, keys)
+
+	// The entry of the build for the old value is deleted by the later mutation.
+	for token, want := range map[string][]uint64{
+		"\x01michonne": {91},
+		"\x01david":    nil,
+		"\x01glenn":    {92},
+	} {
+		l, err := GetNoStore(x.IndexKey("name3", token))
+		require.NoError(t, err)
+		got := uids(l, 8)
+		if len(want) == 0 {
+			require.Empty(t, got, token)
+			continue
+		}
+		require.Equal(t, want, got, token)
+	}
+
+	// A build stops when its throttle fails.
+	require.NoError(t, builds[0].Delete())
+	builds[0].Throttle = func(ctx context.Context) error {
+		return context.Canceled
+	}
+	require.Error(t, builds[0].Run(context.Background()))
+}
+
+func TestCheckIndex(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte("name4: string @index(exact) ."), 1))
+	token := func(val string) string {
+		tokens, err := indexTokensForTest("name4", "",
+			types.Val{Tid: types.StringID, Value: []byte(val)})
+		require.NoError(t, err)
+		return tokens[0]
+	}
+
+	// Michonne isn't indexed, David is, and Glenn is indexed without being in the data.
+	addEdgeToValue(t, "name4", 91, "Michonne", uint64(1), uint64(2))
+	l, err := GetNoStore(x.DataKey("name4", 92))
+	require.NoError(t, err)
+	edge := &pb.DirectedEdge{Value: []byte("David"), Attr: "name4", Entity: 92}
+	addMutation(t, l, edge, Set, 3, 4, true)
+	l, err = GetNoStore(x.IndexKey("name4", token("Glenn")))
+	require.NoError(t, err)
+	addMutation(t, l, &pb.DirectedEdge{ValueId: 93, Attr: "name4"}, Set, 5, 6, false)
+
+	r, err := CheckIndex(context.Background(), "name4", 7)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, r.DataKeys)
+	require.EqualValues(t, 2, r.IndexKeys)
+	require.EqualValues(t, 1, r.NumMissing)
+	require.Equal(t, "exact", r.Missing[0].Index)
+	require.Equal(t, `"Michonne"`, r.Missing[0].Token)
+	require.Equal(t, "0x5b", r.Missing[0].Uid)
+	require.EqualValues(t, 1, r.NumOrphans)
+	require.Equal(t, `"Glenn"`, r.Orphans[0].Token)
+	require.Equal(t, "0x5d", r.Orphans[0].Uid)
+
+	require.Error(t, r.Repair(context.Background(), 7))
+	require.NoError(t, r.Repair(context.Background(), 8))
+	require.EqualValues(t, 2, r.Repaired)
+
+	r, err = CheckIndex(context.Background(), "name4", 9)
+	require.NoError(t, err)
+	require.EqualValues(t, 3, r.IndexKeys)
+	require.Zero(t, r.NumMissing)
+	require.Zero(t, r.NumOrphans)
+}
+
+func TestIndexBuildDropPrefix(t *testing.T) {
+	addEdgeToValue(t, "name4", 93, "Carol", uint64(1), uint64(2))
+	addEdgeToValue(t, "name4", 94, "Daryl", uint64(3), uint64(4))
+
+	require.NoError(t, schema.ParseBytes([]byte("name4: string @index(exact) ."), 1))
+	currentSchema, _ := schema.State().Get("name4")
+	rb := IndexRebuild{
+		Attr:          "name4",
+		StartTs:       5,
+		CurrentSchema: &currentSchema,
+	}
+	builds, err := rb.Prepare(context.Background())
+	require.NoError(t, err)
+	require.Len(t, builds, 1)
+
+	// The writes of the build are blocked while the prefix of another predicate is dropped.
+	dropped := make(chan struct{})
+	go func() {
+		defer close(dropped)
+		for i := 0; i < 3; i++ {
+			require.NoError(t, pstore.DropPrefix(x.PredicatePrefix("name5")))
+		}
+	}()
+	for running := true; running; {
+		select {
+		case <-dropped:
+			running = false
+		default:
+		}
+		require.NoError(t, builds[0].Run(context.Background()))
+	}
+
+	l, err := GetNoStore(x.IndexKey("name4", "\x02Carol"))
+	require.NoError(t, err)
+	require.Equal(t, []uint64{93}, uids(l, 6))
+}
+
 func TestRebuildReverseEdges(t *testing.T) {
 	addEdgeToUID(t, "friend", 1, 23, uint64(10), uint64(11))
 	addEdgeToUID(t, "friend", 1, 24, uint64(12), uint64(13))
@@ -458,6 +596,16 @@ func TestNeedsIndexRebuild(t *testing.T) {
 	require.Equal(t, []string{"exact"}, rebuildInfo.tokenizersToDelete)
 	require.Equal(t, []string{"exact"}, rebuildInfo.tokenizersToRebuild)
 
+	rb.OldSchema = &pb.SchemaUpdate{ValueType: pb.Posting_STRING, Directive: pb.SchemaUpdate_INDEX,
+		Tokenizer: []string{"exact", "term"}}
+	rb.CurrentSchema = &pb.SchemaUpdate{ValueType: pb.Posting_STRING,
+		Directive: pb.SchemaUpdate_INDEX,
+		Tokenizer: []string{"exact", "term"}, Collation: "sv"}
+	rebuildInfo = rb.needsIndexRebuild()
+	require.Equal(t, indexOp(indexRebuild), rebuildInfo.op)
+	require.Equal(t, []string{"exact"}, rebuildInfo.tokenizersToDelete)
+	require.Equal(t, []string{"exact"}, rebuildInfo.tokenizersToRebuild)
+
 	rb.OldSchema = &pb.SchemaUpdate{ValueType: pb.Posting_STRING, Directive: pb.SchemaUpdate_INDEX,
 		Tokenizer: []string{"exact"}}
 	rb.CurrentSchema = &pb.SchemaUpdate{ValueType: pb.Posting_FLOAT,
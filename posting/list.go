@@ -30,6 +30,7 @@ import (
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgo/y"
 	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/blob"
 	"github.com/dgraph-io/dgraph/codec"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/schema"
@@ -42,7 +43,7 @@ import (
 var (
 	// ErrRetry can be triggered if the posting list got deleted from memory due to a hard commit.
 	// In such a case, retry.
-	ErrRetry = errors.New("Temporary error. Please retry")
+	ErrRetry = x.WithCode(errors.New("Temporary error. Please retry"), x.CodeRetriable)
 	// ErrNoValue would be returned if no value was found in the posting list.
 	ErrNoValue       = errors.New("No value found")
 	errStopIteration = errors.New("Stop iteration")
@@ -74,6 +75,12 @@ type List struct {
 	mutationMap map[uint64]*pb.PostingList
 	minTs       uint64 // commit timestamp of immutable layer, reject reads before this ts.
 	maxTs       uint64 // max commit timestamp seen for this list.
+	readBytes   uint64 // size of the versions read from disk to build this list.
+}
+
+// ReadBytes returns the number of bytes read from Badger to build the list.
+func (l *List) ReadBytes() uint64 {
+	return l.readBytes
 }
 
 func (l *List) maxVersion() uint64 {
@@ -289,6 +296,7 @@ func NewPosting(t *pb.DirectedEdge) *pb.Posting {
 		Label:       t.Label,
 		Op:          op,
 		Facets:      t.Facets,
+		Blob:        t.Blob,
 	}
 	return p
 }
@@ -442,6 +450,7 @@ func (l *List) addMutationInternal(ctx context.Context, txn *Txn, t *pb.Directed
 	// stream from Zero, instead of in goroutines.
 	var conflictKey uint64
 	pk := x.Parse(l.key)
+	conflict := schema.State().Conflict(t.Attr)
 	switch {
 	case schema.State().HasUpsert(t.Attr):
 		// Consider checking to see if a email id is unique. A user adds:
@@ -453,6 +462,25 @@ func (l *List) addMutationInternal(ctx context.Context, txn *Txn, t *pb.Directed
 		// that two users don't set the same email id.
 		conflictKey = getKey(l.key, 0)
 
+	// The @conflict directive sets the granularity of the conflicts of the predicate, instead
+	// of the default ones below.
+	case conflict == "none":
+		// Transactions never conflict on this predicate, e.g. for append-only data.
+	case conflict == "predicate":
+		// Any two transactions mutating the predicate conflict.
+		conflictKey = farm.Fingerprint64([]byte(t.Attr))
+	case pk.IsData() && conflict == "uid":
+		// Transactions mutating the predicate of the same node conflict, even for lists.
+		conflictKey = getKey(l.key, 0)
+	case pk.IsData() && conflict == "value":
+		// Transactions setting the same value for the same node conflict, even if the
+		// predicate isn't a list.
+		id := t.ValueId
+		if mpost.PostingType != pb.Posting_REF {
+			id = farm.Fingerprint64(t.Value)
+		}
+		conflictKey = getKey(l.key, id)
+
 	case pk.IsData() && schema.State().IsList(t.Attr):
 		// Data keys, irrespective of whether they are UID or values, should be judged based on
 		// whether they are lists or not. For UID, t.ValueId = UID. For value, t.ValueId =
@@ -481,7 +509,7 @@ func (l *List) addMutationInternal(ctx context.Context, txn *Txn, t *pb.Directed
 	default:
 		// Don't assign a conflictKey.
 	}
-	txn.addConflictKey(conflictKey)
+	txn.addConflictKey(conflictKey, t.Attr)
 	return nil
 }
 
@@ -940,10 +968,11 @@ func (l *List) AllUntaggedValues(readTs uint64) ([]types.Val, error) {
 	var vals []types.Val
 	err := l.iterate(readTs, 0, func(p *pb.Posting) error {
 		if len(p.LangTag) == 0 {
-			vals = append(vals, types.Val{
-				Tid:   types.TypeID(p.ValType),
-				Value: p.Value,
-			})
+			val, err := ResolveValue(p)
+			if err != nil {
+				return err
+			}
+			vals = append(vals, val)
 		}
 		return nil
 	})
@@ -957,10 +986,11 @@ func (l *List) AllValues(readTs uint64) ([]types.Val, error) {
 
 	var vals []types.Val
 	err := l.iterate(readTs, 0, func(p *pb.Posting) error {
-		vals = append(vals, types.Val{
-			Tid:   types.TypeID(p.ValType),
-			Value: p.Value,
-		})
+		val, err := ResolveValue(p)
+		if err != nil {
+			return err
+		}
+		vals = append(vals, val)
 		return nil
 	})
 	return vals, err
@@ -995,10 +1025,10 @@ func (l *List) Value(readTs uint64) (rval types.Val, rerr error) {
 }
 
 // ValueFor returns a value from posting list, according to preferred language list.
-// If list is empty, value without language is returned; if such value is not
-// available, value with smallest UID is returned.
-// If list consists of one or more languages, first available value is returned.
-// If no language from the list matches the values, processing is the same as for empty list.
+// If list is empty, value without language is returned.
+// Otherwise the languages are tried in order and the first available value is returned. An
+// empty language stands for the value without language, and the wildcard "." for the value
+// without language followed by the value with the smallest language UID.
 func (l *List) ValueFor(readTs uint64, langs []string) (rval types.Val, rerr error) {
 	l.RLock() // All public methods should acquire locks, while private ones should assert them.
 	defer l.RUnlock()
@@ -1006,7 +1036,7 @@ func (l *List) ValueFor(readTs uint64, langs []string) (rval types.Val, rerr err
 	if err != nil {
 		return rval, err
 	}
-	return valueToTypesVal(p), nil
+	return ResolveValue(p)
 }
 
 func (l *List) postingFor(readTs uint64, langs []string) (p *pb.Posting, rerr error) {
@@ -1022,7 +1052,7 @@ func (l *List) ValueForTag(readTs uint64, tag string) (rval types.Val, rerr erro
 	if err != nil {
 		return rval, err
 	}
-	return valueToTypesVal(p), nil
+	return ResolveValue(p)
 }
 
 func valueToTypesVal(p *pb.Posting) (rval types.Val) {
@@ -1033,6 +1063,21 @@ func valueToTypesVal(p *pb.Posting) (rval types.Val) {
 	return
 }
 
+// ResolveValue returns the value of the posting, reading it from the blob store if it was
+// offloaded.
+func ResolveValue(p *pb.Posting) (types.Val, error) {
+	val := valueToTypesVal(p)
+	if !p.Blob {
+		return val, nil
+	}
+	data, err := blob.Get(string(p.Value))
+	if err != nil {
+		return val, err
+	}
+	val.Value = data
+	return val, nil
+}
+
 func (l *List) postingForLangs(readTs uint64, langs []string) (pos *pb.Posting, rerr error) {
 	l.AssertRLock()
 
@@ -1043,6 +1088,13 @@ func (l *List) postingForLangs(readTs uint64, langs []string) (pos *pb.Posting,
 			any = true
 			break
 		}
+		if lang == "" {
+			// The value without language is a step of the chain.
+			if found, pos, err := l.findPosting(readTs, math.MaxUint64); err != nil || found {
+				return pos, err
+			}
+			continue
+		}
 		pos, rerr = l.postingForTag(readTs, lang)
 		if rerr == nil {
 			return pos, nil
@@ -1102,7 +1154,8 @@ func (l *List) findValue(readTs, uid uint64) (rval types.Val, found bool, err er
 		return rval, found, err
 	}
 
-	return valueToTypesVal(p), true, nil
+	rval, err = ResolveValue(p)
+	return rval, err == nil, err
 }
 
 func (l *List) findPosting(readTs uint64, uid uint64) (found bool, pos *pb.Posting, err error) {
@@ -1118,6 +1171,22 @@ func (l *List) findPosting(readTs uint64, uid uint64) (found bool, pos *pb.Posti
 	return found, pos, err
 }
 
+// EdgeFacets returns the facets of the posting which the edge would replace, or nil if there's
+// none.
+func (l *List) EdgeFacets(readTs uint64, edge *pb.DirectedEdge) ([]*api.Facet, error) {
+	l.RLock()
+	defer l.RUnlock()
+	uid := edge.ValueId
+	if len(edge.Lang) > 0 || edge.ValueId == 0 {
+		uid = fingerprintEdge(edge)
+	}
+	found, p, err := l.findPosting(readTs, uid)
+	if err != nil || !found {
+		return nil, err
+	}
+	return p.Facets, nil
+}
+
 // Facets gives facets for the posting representing value.
 func (l *List) Facets(readTs uint64, param *pb.FacetParams, langs []string) (fs []*api.Facet,
 	ferr error) {
@@ -1130,6 +1199,22 @@ func (l *List) Facets(readTs uint64, param *pb.FacetParams, langs []string) (fs
 	return facets.CopyFacets(p.Facets, param), nil
 }
 
+// AllUntaggedFacets returns the facets of all the untagged values in the posting list, in the
+// order of the values returned by AllUntaggedValues.
+func (l *List) AllUntaggedFacets(readTs uint64, param *pb.FacetParams) ([]*pb.Facets, error) {
+	l.RLock()
+	defer l.RUnlock()
+
+	var fcts []*pb.Facets
+	err := l.iterate(readTs, 0, func(p *pb.Posting) error {
+		if len(p.LangTag) == 0 {
+			fcts = append(fcts, &pb.Facets{Facets: facets.CopyFacets(p.Facets, param)})
+		}
+		return nil
+	})
+	return fcts, err
+}
+
 func (l *List) readListPart(startUid uint64) (*pb.PostingList, error) {
 	key := x.GetSplitKey(l.key, startUid)
 	txn := pstore.NewTransactionAt(l.minTs, false)
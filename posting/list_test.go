@@ -28,8 +28,11 @@ import (
 
 	"github.com/dgraph-io/badger"
 	bpb "github.com/dgraph-io/badger/pb"
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgryski/go-farm"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dgraph-io/dgraph/blob"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/x"
@@ -190,6 +193,123 @@ func TestAddMutation_Value(t *testing.T) {
 	checkValue(t, ol, "119", txn.StartTs)
 }
 
+func TestAddMutation_Blob(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blob")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, blob.Init(dir))
+	defer blob.Init("")
+
+	key, err := blob.Put([]byte("a value too large to be kept inline"))
+	require.NoError(t, err)
+	ol, err := getNew(x.DataKey("value", 11), ps)
+	require.NoError(t, err)
+	edge := &pb.DirectedEdge{
+		Value: []byte(key),
+		Blob:  true,
+	}
+	txn := &Txn{StartTs: 1}
+	addMutationHelper(t, ol, edge, Set, txn)
+	ol.commitMutation(txn.StartTs, txn.StartTs+1)
+	require.True(t, getFirst(ol, 3).Blob)
+	val, err := ol.Value(3)
+	require.NoError(t, err)
+	require.Equal(t, []byte("a value too large to be kept inline"), val.Value)
+
+	vals, err := ol.AllValues(3)
+	require.NoError(t, err)
+	require.Len(t, vals, 1)
+	require.Equal(t, []byte("a value too large to be kept inline"), vals[0].Value)
+
+	// The value can't be read once the store is gone.
+	require.NoError(t, blob.Init(""))
+	_, err = ol.Value(3)
+	require.Error(t, err)
+}
+
+func TestAddMutation_ConflictGranularity(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte(`
+		visit: [string] @conflict(none) .
+		tag: [string] @conflict(uid) .
+		counter: int @conflict(predicate) .
+		status: string @conflict(value) .
+		title: string .
+	`), 1))
+	conflicts := func(attr string, uid uint64, vals ...string) map[uint64]string {
+		ol, err := getNew(x.DataKey(attr, uid), ps)
+		require.NoError(t, err)
+		txn := &Txn{StartTs: 1}
+		for _, val := range vals {
+			addMutationHelper(t, ol, &pb.DirectedEdge{Attr: attr, Value: []byte(val)}, Set, txn)
+		}
+		return txn.conflicts
+	}
+
+	require.Len(t, conflicts("visit", 1, "a", "b"), 0)
+	require.Len(t, conflicts("tag", 1, "a", "b"), 1)
+	require.Len(t, conflicts("status", 1, "a", "b"), 2)
+	require.Len(t, conflicts("title", 1, "a", "b"), 1)
+	require.Equal(t, conflicts("counter", 1, "1"), conflicts("counter", 2, "2"))
+	require.Equal(t, map[uint64]string{farm.Fingerprint64([]byte("counter")): "counter"},
+		conflicts("counter", 1, "1"))
+}
+
+func TestValueForLangChain(t *testing.T) {
+	ol, err := getNew(x.DataKey("value", 13), ps)
+	require.NoError(t, err)
+	txn := &Txn{StartTs: 1}
+	addMutationHelper(t, ol, &pb.DirectedEdge{Value: []byte("bonjour"), Lang: "fr"}, Set, txn)
+	addMutationHelper(t, ol, &pb.DirectedEdge{Value: []byte("hello")}, Set, txn)
+	ol.commitMutation(txn.StartTs, txn.StartTs+1)
+
+	check := func(langs []string, expected string) {
+		val, err := ol.ValueFor(3, langs)
+		if expected == "" {
+			require.Equal(t, ErrNoValue, err, "%v", langs)
+			return
+		}
+		require.NoError(t, err)
+		require.Equal(t, expected, string(val.Value.([]byte)), "%v", langs)
+	}
+	check(nil, "hello")
+	check([]string{"de"}, "")
+	check([]string{"de", "fr"}, "bonjour")
+	check([]string{"de", ""}, "hello")
+	check([]string{"", "fr"}, "hello")
+	check([]string{"de", "."}, "hello")
+}
+
+func TestAllUntaggedFacets(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte("nick: [string] ."), 1))
+	ol, err := getNew(x.DataKey("nick", 14), ps)
+	require.NoError(t, err)
+	txn := &Txn{StartTs: 1}
+	for _, nick := range []string{"Al", "Ali", "Alice"} {
+		edge := &pb.DirectedEdge{Value: []byte(nick), ValueType: pb.Posting_STRING}
+		if nick != "Ali" {
+			edge.Facets = []*api.Facet{{Key: "since", Value: []byte(nick)}}
+		}
+		addMutationHelper(t, ol, edge, Set, txn)
+	}
+	addMutationHelper(t, ol, &pb.DirectedEdge{Value: []byte("Alain"), Lang: "fr",
+		Facets: []*api.Facet{{Key: "since", Value: []byte("fr")}}}, Set, txn)
+	ol.commitMutation(txn.StartTs, txn.StartTs+1)
+
+	// Each value keeps its own facets, in the order of the values.
+	vals, err := ol.AllUntaggedValues(3)
+	require.NoError(t, err)
+	fcts, err := ol.AllUntaggedFacets(3, &pb.FacetParams{AllKeys: true})
+	require.NoError(t, err)
+	require.Equal(t, len(vals), len(fcts))
+	for i, val := range vals {
+		if nick := string(val.Value.([]byte)); nick == "Ali" {
+			require.Empty(t, fcts[i].Facets)
+		} else {
+			require.Equal(t, nick, string(fcts[i].Facets[0].Value))
+		}
+	}
+}
+
 func TestAddMutation_jchiu1(t *testing.T) {
 	key := x.DataKey("value", 12)
 	ol, err := GetNoStore(key)
@@ -43,14 +43,14 @@ func (txn *Txn) ShouldAbort() bool {
 	return atomic.LoadUint32(&txn.shouldAbort) > 0
 }
 
-func (txn *Txn) addConflictKey(conflictKey uint64) {
+func (txn *Txn) addConflictKey(conflictKey uint64, attr string) {
 	txn.Lock()
 	defer txn.Unlock()
 	if txn.conflicts == nil {
-		txn.conflicts = make(map[uint64]struct{})
+		txn.conflicts = make(map[uint64]string)
 	}
 	if conflictKey > 0 {
-		txn.conflicts[conflictKey] = struct{}{}
+		txn.conflicts[conflictKey] = attr
 	}
 }
 
@@ -58,11 +58,13 @@ func (txn *Txn) addConflictKey(conflictKey uint64) {
 func (txn *Txn) FillContext(ctx *api.TxnContext, gid uint32) {
 	txn.Lock()
 	ctx.StartTs = txn.StartTs
-	for key := range txn.conflicts {
+	for key, attr := range txn.conflicts {
 		// We don'txn need to send the whole conflict key to Zero. Solving #2338
 		// should be done by sending a list of mutating predicates to Zero,
 		// along with the keys to be used for conflict detection.
-		fps := strconv.FormatUint(key, 36)
+		// The predicate follows the fingerprint, so that Zero can report on which
+		// predicate a transaction conflicted.
+		fps := strconv.FormatUint(key, 36) + "-" + attr
 		if !x.HasString(ctx.Keys, fps) {
 			ctx.Keys = append(ctx.Keys, fps)
 		}
@@ -158,6 +160,8 @@ func ReadPostingList(key []byte, it *badger.Iterator) (*List, error) {
 			break
 		}
 
+		l.readBytes += uint64(item.ValueSize())
+
 		switch item.UserMeta() {
 		case BitEmptyPosting:
 			l.minTs = item.Version()
@@ -96,3 +96,42 @@ func TestPostingListRead(t *testing.T) {
 	addEdgeToUID(t, "emptypl", 1, 7, 15, 16)
 	assertLength(17, 3)
 }
+
+func TestListReadBytes(t *testing.T) {
+	key := x.DataKey("readbytes", 1)
+	addEdgeToUID(t, "readbytes", 1, 2, 1, 2)
+	l, err := getNew(key, pstore)
+	require.NoError(t, err)
+	first := l.ReadBytes()
+	require.True(t, first > 0)
+
+	addEdgeToUID(t, "readbytes", 1, 3, 3, 4)
+	l, err = getNew(key, pstore)
+	require.NoError(t, err)
+	require.True(t, l.ReadBytes() > first)
+}
+
+func TestOracleState(t *testing.T) {
+	orc := new(oracle)
+	orc.init()
+	orc.RegisterStartTs(7)
+	txn := orc.RegisterStartTs(3)
+	txn.cache.deltas["key"] = []byte{}
+	_, ok := orc.addToWaiters(5)
+	require.True(t, ok)
+
+	st := orc.State()
+	require.Equal(t, uint64(0), st.MaxAssigned)
+	require.Equal(t, 1, st.Waiters)
+	require.Len(t, st.PendingTxns, 2)
+	require.Equal(t, uint64(3), st.PendingTxns[0].StartTs)
+	require.Equal(t, 1, st.PendingTxns[0].Deltas)
+	require.Equal(t, uint64(7), st.PendingTxns[1].StartTs)
+
+	orc.ProcessDelta(&pb.OracleDelta{MaxAssigned: 8,
+		Txns: []*pb.TxnStatus{{StartTs: 3, CommitTs: 8}, {StartTs: 7}}})
+	st = orc.State()
+	require.Equal(t, uint64(8), st.MaxAssigned)
+	require.Zero(t, st.Waiters)
+	require.Empty(t, st.PendingTxns)
+}
@@ -19,6 +19,7 @@ package posting
 import (
 	"context"
 	"math"
+	"sort"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -53,8 +54,8 @@ type Txn struct {
 	sync.Mutex
 
 	// Keeps track of conflict keys that should be used to determine if this
-	// transaction conflicts with another.
-	conflicts map[uint64]struct{}
+	// transaction conflicts with another, along with the predicate of each key.
+	conflicts map[uint64]string
 
 	// Keeps track of last update wall clock. We use this fact later to
 	// determine unhealthy, stale txns.
@@ -170,6 +171,51 @@ func (o *oracle) TxnOlderThan(dur time.Duration) (res []uint64) {
 	return res
 }
 
+// PendingTxn describes a transaction waiting for a commit or abort decision.
+type PendingTxn struct {
+	StartTs     uint64    `json:"start_ts"`
+	LastUpdate  time.Time `json:"last_update"`
+	ShouldAbort bool      `json:"should_abort"`
+	// Deltas is the number of posting lists modified by the transaction, and Lists the number
+	// of posting lists it holds in memory.
+	Deltas int `json:"deltas"`
+	Lists  int `json:"lists"`
+}
+
+// OracleState is a snapshot of the state of the oracle, used for debugging.
+type OracleState struct {
+	MaxAssigned uint64       `json:"max_assigned"`
+	Waiters     int          `json:"waiters"`
+	PendingTxns []PendingTxn `json:"pending_txns"`
+}
+
+// State returns a snapshot of the state of the oracle, with the pending transactions sorted by
+// start ts.
+func (o *oracle) State() *OracleState {
+	o.RLock()
+	defer o.RUnlock()
+
+	st := &OracleState{MaxAssigned: o.MaxAssigned(), PendingTxns: []PendingTxn{}}
+	for _, chs := range o.waiters {
+		st.Waiters += len(chs)
+	}
+	for startTs, txn := range o.pendingTxns {
+		pt := PendingTxn{
+			StartTs:     startTs,
+			LastUpdate:  txn.lastUpdate,
+			ShouldAbort: txn.ShouldAbort(),
+		}
+		txn.cache.RLock()
+		pt.Deltas, pt.Lists = len(txn.cache.deltas), len(txn.cache.plists)
+		txn.cache.RUnlock()
+		st.PendingTxns = append(st.PendingTxns, pt)
+	}
+	sort.Slice(st.PendingTxns, func(i, j int) bool {
+		return st.PendingTxns[i].StartTs < st.PendingTxns[j].StartTs
+	})
+	return st
+}
+
 func (o *oracle) addToWaiters(startTs uint64) (chan struct{}, bool) {
 	if startTs <= o.MaxAssigned() {
 		return nil, false
@@ -34,16 +34,22 @@ type DirectedEdge_Op int32
 const (
 	DirectedEdge_SET DirectedEdge_Op = 0
 	DirectedEdge_DEL DirectedEdge_Op = 1
+	DirectedEdge_ADD DirectedEdge_Op = 2
+	DirectedEdge_CAS DirectedEdge_Op = 3
 )
 
 var DirectedEdge_Op_name = map[int32]string{
 	0: "SET",
 	1: "DEL",
+	2: "ADD",
+	3: "CAS",
 }
 
 var DirectedEdge_Op_value = map[string]int32{
 	"SET": 0,
 	"DEL": 1,
+	"ADD": 2,
+	"CAS": 3,
 }
 
 func (x DirectedEdge_Op) String() string {
@@ -99,6 +105,7 @@ const (
 	Posting_PASSWORD Posting_ValType = 8
 	Posting_STRING   Posting_ValType = 9
 	Posting_OBJECT   Posting_ValType = 10
+	Posting_JSON     Posting_ValType = 11
 )
 
 var Posting_ValType_name = map[int32]string{
@@ -113,6 +120,7 @@ var Posting_ValType_name = map[int32]string{
 	8:  "PASSWORD",
 	9:  "STRING",
 	10: "OBJECT",
+	11: "JSON",
 }
 
 var Posting_ValType_value = map[string]int32{
@@ -127,6 +135,7 @@ var Posting_ValType_value = map[string]int32{
 	"PASSWORD": 8,
 	"STRING":   9,
 	"OBJECT":   10,
+	"JSON":     11,
 }
 
 func (x Posting_ValType) String() string {
@@ -419,6 +428,7 @@ type Query struct {
 	ExpandAll            bool         `protobuf:"varint,10,opt,name=expand_all,json=expandAll,proto3" json:"expand_all,omitempty"`
 	ReadTs               uint64       `protobuf:"varint,13,opt,name=read_ts,json=readTs,proto3" json:"read_ts,omitempty"`
 	Cache                int32        `protobuf:"varint,14,opt,name=cache,proto3" json:"cache,omitempty"`
+	ValidAt              []byte       `protobuf:"bytes,15,opt,name=valid_at,json=validAt,proto3" json:"valid_at,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
 	XXX_unrecognized     []byte       `json:"-"`
 	XXX_sizecache        int32        `json:"-"`
@@ -541,6 +551,13 @@ func (m *Query) GetCache() int32 {
 	return 0
 }
 
+func (m *Query) GetValidAt() []byte {
+	if m != nil {
+		return m.ValidAt
+	}
+	return nil
+}
+
 type ValueList struct {
 	Values               []*TaskValue `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
@@ -643,6 +660,8 @@ type Result struct {
 	FacetMatrix          []*FacetsList `protobuf:"bytes,5,rep,name=facet_matrix,json=facetMatrix,proto3" json:"facet_matrix,omitempty"`
 	LangMatrix           []*LangList   `protobuf:"bytes,6,rep,name=lang_matrix,json=langMatrix,proto3" json:"lang_matrix,omitempty"`
 	List                 bool          `protobuf:"varint,7,opt,name=list,proto3" json:"list,omitempty"`
+	KeysRead             uint64        `protobuf:"varint,8,opt,name=keys_read,json=keysRead,proto3" json:"keys_read,omitempty"`
+	BytesRead            uint64        `protobuf:"varint,9,opt,name=bytes_read,json=bytesRead,proto3" json:"bytes_read,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}      `json:"-"`
 	XXX_unrecognized     []byte        `json:"-"`
 	XXX_sizecache        int32         `json:"-"`
@@ -730,10 +749,29 @@ func (m *Result) GetList() bool {
 	return false
 }
 
+func (m *Result) GetKeysRead() uint64 {
+	if m != nil {
+		return m.KeysRead
+	}
+	return 0
+}
+
+func (m *Result) GetBytesRead() uint64 {
+	if m != nil {
+		return m.BytesRead
+	}
+	return 0
+}
+
 type Order struct {
 	Attr                 string   `protobuf:"bytes,1,opt,name=attr,proto3" json:"attr,omitempty"`
 	Desc                 bool     `protobuf:"varint,2,opt,name=desc,proto3" json:"desc,omitempty"`
 	Langs                []string `protobuf:"bytes,3,rep,name=langs,proto3" json:"langs,omitempty"`
+	Collation            string   `protobuf:"bytes,4,opt,name=collation,proto3" json:"collation,omitempty"`
+	Random               bool     `protobuf:"varint,5,opt,name=random,proto3" json:"random,omitempty"`
+	Seed                 int64    `protobuf:"varint,6,opt,name=seed,proto3" json:"seed,omitempty"`
+	Count                bool     `protobuf:"varint,7,opt,name=count,proto3" json:"count,omitempty"`
+	Reverse              bool     `protobuf:"varint,8,opt,name=reverse,proto3" json:"reverse,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -793,6 +831,41 @@ func (m *Order) GetLangs() []string {
 	return nil
 }
 
+func (m *Order) GetCollation() string {
+	if m != nil {
+		return m.Collation
+	}
+	return ""
+}
+
+func (m *Order) GetRandom() bool {
+	if m != nil {
+		return m.Random
+	}
+	return false
+}
+
+func (m *Order) GetSeed() int64 {
+	if m != nil {
+		return m.Seed
+	}
+	return 0
+}
+
+func (m *Order) GetCount() bool {
+	if m != nil {
+		return m.Count
+	}
+	return false
+}
+
+func (m *Order) GetReverse() bool {
+	if m != nil {
+		return m.Reverse
+	}
+	return false
+}
+
 type SortMessage struct {
 	Order                []*Order `protobuf:"bytes,1,rep,name=order,proto3" json:"order,omitempty"`
 	UidMatrix            []*List  `protobuf:"bytes,2,rep,name=uid_matrix,json=uidMatrix,proto3" json:"uid_matrix,omitempty"`
@@ -1001,6 +1074,7 @@ type Member struct {
 	AmDead               bool     `protobuf:"varint,5,opt,name=am_dead,json=amDead,proto3" json:"am_dead,omitempty"`
 	LastUpdate           uint64   `protobuf:"varint,6,opt,name=last_update,json=lastUpdate,proto3" json:"last_update,omitempty"`
 	ClusterInfoOnly      bool     `protobuf:"varint,13,opt,name=cluster_info_only,json=clusterInfoOnly,proto3" json:"cluster_info_only,omitempty"`
+	FeatureVersion       uint32   `protobuf:"varint,14,opt,name=feature_version,json=featureVersion,proto3" json:"feature_version,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -1088,11 +1162,19 @@ func (m *Member) GetClusterInfoOnly() bool {
 	return false
 }
 
+func (m *Member) GetFeatureVersion() uint32 {
+	if m != nil {
+		return m.FeatureVersion
+	}
+	return 0
+}
+
 type Group struct {
 	Members              map[uint64]*Member `protobuf:"bytes,1,rep,name=members,proto3" json:"members,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	Tablets              map[string]*Tablet `protobuf:"bytes,2,rep,name=tablets,proto3" json:"tablets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 	SnapshotTs           uint64             `protobuf:"varint,3,opt,name=snapshot_ts,json=snapshotTs,proto3" json:"snapshot_ts,omitempty"`
 	Checksum             uint64             `protobuf:"varint,4,opt,name=checksum,proto3" json:"checksum,omitempty"`
+	Excluded             bool               `protobuf:"varint,5,opt,name=excluded,proto3" json:"excluded,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
 	XXX_sizecache        int32              `json:"-"`
@@ -1159,6 +1241,13 @@ func (m *Group) GetChecksum() uint64 {
 	return 0
 }
 
+func (m *Group) GetExcluded() bool {
+	if m != nil {
+		return m.Excluded
+	}
+	return false
+}
+
 type ZeroProposal struct {
 	SnapshotTs           map[uint32]uint64 `protobuf:"bytes,1,rep,name=snapshot_ts,json=snapshotTs,proto3" json:"snapshot_ts,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	Member               *Member           `protobuf:"bytes,2,opt,name=member,proto3" json:"member,omitempty"`
@@ -1169,6 +1258,15 @@ type ZeroProposal struct {
 	Txn                  *api.TxnContext   `protobuf:"bytes,7,opt,name=txn,proto3" json:"txn,omitempty"`
 	Key                  string            `protobuf:"bytes,8,opt,name=key,proto3" json:"key,omitempty"`
 	Cid                  string            `protobuf:"bytes,9,opt,name=cid,proto3" json:"cid,omitempty"`
+	Sequence             string            `protobuf:"bytes,10,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	MaxSequenceId        uint64            `protobuf:"varint,11,opt,name=maxSequenceId,proto3" json:"maxSequenceId,omitempty"`
+	PinPredicate         string            `protobuf:"bytes,12,opt,name=pinPredicate,proto3" json:"pinPredicate,omitempty"`
+	PinGroupId           uint32            `protobuf:"varint,13,opt,name=pinGroupId,proto3" json:"pinGroupId,omitempty"`
+	ExcludeGroupId       uint32            `protobuf:"varint,14,opt,name=excludeGroupId,proto3" json:"excludeGroupId,omitempty"`
+	IncludeGroupId       uint32            `protobuf:"varint,15,opt,name=includeGroupId,proto3" json:"includeGroupId,omitempty"`
+	FeatureVersion       uint32            `protobuf:"varint,16,opt,name=featureVersion,proto3" json:"featureVersion,omitempty"`
+	FreezePredicate      string            `protobuf:"bytes,17,opt,name=freezePredicate,proto3" json:"freezePredicate,omitempty"`
+	FreezeMode           uint32            `protobuf:"varint,18,opt,name=freezeMode,proto3" json:"freezeMode,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
 	XXX_unrecognized     []byte            `json:"-"`
 	XXX_sizecache        int32             `json:"-"`
@@ -1270,6 +1368,69 @@ func (m *ZeroProposal) GetCid() string {
 	return ""
 }
 
+func (m *ZeroProposal) GetSequence() string {
+	if m != nil {
+		return m.Sequence
+	}
+	return ""
+}
+
+func (m *ZeroProposal) GetMaxSequenceId() uint64 {
+	if m != nil {
+		return m.MaxSequenceId
+	}
+	return 0
+}
+
+func (m *ZeroProposal) GetPinPredicate() string {
+	if m != nil {
+		return m.PinPredicate
+	}
+	return ""
+}
+
+func (m *ZeroProposal) GetPinGroupId() uint32 {
+	if m != nil {
+		return m.PinGroupId
+	}
+	return 0
+}
+
+func (m *ZeroProposal) GetExcludeGroupId() uint32 {
+	if m != nil {
+		return m.ExcludeGroupId
+	}
+	return 0
+}
+
+func (m *ZeroProposal) GetIncludeGroupId() uint32 {
+	if m != nil {
+		return m.IncludeGroupId
+	}
+	return 0
+}
+
+func (m *ZeroProposal) GetFeatureVersion() uint32 {
+	if m != nil {
+		return m.FeatureVersion
+	}
+	return 0
+}
+
+func (m *ZeroProposal) GetFreezePredicate() string {
+	if m != nil {
+		return m.FreezePredicate
+	}
+	return ""
+}
+
+func (m *ZeroProposal) GetFreezeMode() uint32 {
+	if m != nil {
+		return m.FreezeMode
+	}
+	return 0
+}
+
 // MembershipState is used to pack together the current membership state of all the nodes
 // in the caller server; and the membership updates recorded by the callee server since
 // the provided lastUpdate.
@@ -1282,6 +1443,10 @@ type MembershipState struct {
 	MaxRaftId            uint64             `protobuf:"varint,6,opt,name=maxRaftId,proto3" json:"maxRaftId,omitempty"`
 	Removed              []*Member          `protobuf:"bytes,7,rep,name=removed,proto3" json:"removed,omitempty"`
 	Cid                  string             `protobuf:"bytes,8,opt,name=cid,proto3" json:"cid,omitempty"`
+	Sequences            map[string]uint64  `protobuf:"bytes,9,rep,name=sequences,proto3" json:"sequences,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	PinnedTablets        map[string]uint32  `protobuf:"bytes,10,rep,name=pinned_tablets,json=pinnedTablets,proto3" json:"pinned_tablets,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
+	FeatureVersion       uint32             `protobuf:"varint,11,opt,name=feature_version,json=featureVersion,proto3" json:"feature_version,omitempty"`
+	FrozenPredicates     map[string]uint32  `protobuf:"bytes,12,rep,name=frozen_predicates,json=frozenPredicates,proto3" json:"frozen_predicates,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"varint,2,opt,name=value,proto3"`
 	XXX_NoUnkeyedLiteral struct{}           `json:"-"`
 	XXX_unrecognized     []byte             `json:"-"`
 	XXX_sizecache        int32              `json:"-"`
@@ -1376,6 +1541,34 @@ func (m *MembershipState) GetCid() string {
 	return ""
 }
 
+func (m *MembershipState) GetSequences() map[string]uint64 {
+	if m != nil {
+		return m.Sequences
+	}
+	return nil
+}
+
+func (m *MembershipState) GetPinnedTablets() map[string]uint32 {
+	if m != nil {
+		return m.PinnedTablets
+	}
+	return nil
+}
+
+func (m *MembershipState) GetFeatureVersion() uint32 {
+	if m != nil {
+		return m.FeatureVersion
+	}
+	return 0
+}
+
+func (m *MembershipState) GetFrozenPredicates() map[string]uint32 {
+	if m != nil {
+		return m.FrozenPredicates
+	}
+	return nil
+}
+
 type ConnectionState struct {
 	Member               *Member          `protobuf:"bytes,1,opt,name=member,proto3" json:"member,omitempty"`
 	State                *MembershipState `protobuf:"bytes,2,opt,name=state,proto3" json:"state,omitempty"`
@@ -1536,6 +1729,8 @@ type DirectedEdge struct {
 	Lang                 string          `protobuf:"bytes,7,opt,name=lang,proto3" json:"lang,omitempty"`
 	Op                   DirectedEdge_Op `protobuf:"varint,8,opt,name=op,proto3,enum=pb.DirectedEdge_Op" json:"op,omitempty"`
 	Facets               []*api.Facet    `protobuf:"bytes,9,rep,name=facets,proto3" json:"facets,omitempty"`
+	Blob                 bool            `protobuf:"varint,10,opt,name=blob,proto3" json:"blob,omitempty"`
+	Expected             []byte          `protobuf:"bytes,11,opt,name=expected,proto3" json:"expected,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -1637,18 +1832,40 @@ func (m *DirectedEdge) GetFacets() []*api.Facet {
 	return nil
 }
 
+func (m *DirectedEdge) GetBlob() bool {
+	if m != nil {
+		return m.Blob
+	}
+	return false
+}
+
+func (m *DirectedEdge) GetExpected() []byte {
+	if m != nil {
+		return m.Expected
+	}
+	return nil
+}
+
 type Mutations struct {
-	GroupId              uint32           `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
-	StartTs              uint64           `protobuf:"varint,2,opt,name=start_ts,json=startTs,proto3" json:"start_ts,omitempty"`
-	Edges                []*DirectedEdge  `protobuf:"bytes,3,rep,name=edges,proto3" json:"edges,omitempty"`
-	Schema               []*SchemaUpdate  `protobuf:"bytes,4,rep,name=schema,proto3" json:"schema,omitempty"`
-	IgnoreIndexConflict  bool             `protobuf:"varint,5,opt,name=ignore_index_conflict,json=ignoreIndexConflict,proto3" json:"ignore_index_conflict,omitempty"`
-	Types                []*TypeUpdate    `protobuf:"bytes,6,rep,name=types,proto3" json:"types,omitempty"`
-	DropOp               Mutations_DropOp `protobuf:"varint,7,opt,name=drop_op,json=dropOp,proto3,enum=pb.Mutations_DropOp" json:"drop_op,omitempty"`
-	DropValue            string           `protobuf:"bytes,8,opt,name=drop_value,json=dropValue,proto3" json:"drop_value,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
-	XXX_unrecognized     []byte           `json:"-"`
-	XXX_sizecache        int32            `json:"-"`
+	GroupId             uint32           `protobuf:"varint,1,opt,name=group_id,json=groupId,proto3" json:"group_id,omitempty"`
+	StartTs             uint64           `protobuf:"varint,2,opt,name=start_ts,json=startTs,proto3" json:"start_ts,omitempty"`
+	Edges               []*DirectedEdge  `protobuf:"bytes,3,rep,name=edges,proto3" json:"edges,omitempty"`
+	Schema              []*SchemaUpdate  `protobuf:"bytes,4,rep,name=schema,proto3" json:"schema,omitempty"`
+	IgnoreIndexConflict bool             `protobuf:"varint,5,opt,name=ignore_index_conflict,json=ignoreIndexConflict,proto3" json:"ignore_index_conflict,omitempty"`
+	Types               []*TypeUpdate    `protobuf:"bytes,6,rep,name=types,proto3" json:"types,omitempty"`
+	DropOp              Mutations_DropOp `protobuf:"varint,7,opt,name=drop_op,json=dropOp,proto3,enum=pb.Mutations_DropOp" json:"drop_op,omitempty"`
+	DropValue           string           `protobuf:"bytes,8,opt,name=drop_value,json=dropValue,proto3" json:"drop_value,omitempty"`
+	// The edges of append-only predicates are committed as soon as they're applied.
+	AppendOnly bool `protobuf:"varint,9,opt,name=append_only,json=appendOnly,proto3" json:"append_only,omitempty"`
+	// The indices of the schema updates are built in the background, instead of before the
+	// updates are done.
+	RunInBackground bool `protobuf:"varint,10,opt,name=run_in_background,json=runInBackground,proto3" json:"run_in_background,omitempty"`
+	// The version at which the edges of an append-only mutation are committed, assigned by Zero
+	// before the mutation is proposed.
+	CommitTs             uint64   `protobuf:"varint,11,opt,name=commit_ts,json=commitTs,proto3" json:"commit_ts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Mutations) Reset()         { *m = Mutations{} }
@@ -1740,6 +1957,27 @@ func (m *Mutations) GetDropValue() string {
 	return ""
 }
 
+func (m *Mutations) GetAppendOnly() bool {
+	if m != nil {
+		return m.AppendOnly
+	}
+	return false
+}
+
+func (m *Mutations) GetRunInBackground() bool {
+	if m != nil {
+		return m.RunInBackground
+	}
+	return false
+}
+
+func (m *Mutations) GetCommitTs() uint64 {
+	if m != nil {
+		return m.CommitTs
+	}
+	return 0
+}
+
 type Snapshot struct {
 	Context *RaftContext `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
 	Index   uint64       `protobuf:"varint,2,opt,name=index,proto3" json:"index,omitempty"`
@@ -1990,9 +2228,11 @@ type Posting struct {
 	Label       string              `protobuf:"bytes,6,opt,name=label,proto3" json:"label,omitempty"`
 	Facets      []*api.Facet        `protobuf:"bytes,9,rep,name=facets,proto3" json:"facets,omitempty"`
 	// TODO: op is only used temporarily. See if we can remove it from here.
-	Op                   uint32   `protobuf:"varint,12,opt,name=op,proto3" json:"op,omitempty"`
-	StartTs              uint64   `protobuf:"varint,13,opt,name=start_ts,json=startTs,proto3" json:"start_ts,omitempty"`
-	CommitTs             uint64   `protobuf:"varint,14,opt,name=commit_ts,json=commitTs,proto3" json:"commit_ts,omitempty"`
+	Op       uint32 `protobuf:"varint,12,opt,name=op,proto3" json:"op,omitempty"`
+	StartTs  uint64 `protobuf:"varint,13,opt,name=start_ts,json=startTs,proto3" json:"start_ts,omitempty"`
+	CommitTs uint64 `protobuf:"varint,14,opt,name=commit_ts,json=commitTs,proto3" json:"commit_ts,omitempty"`
+	// Tells if value is the key of the value in the blob store.
+	Blob                 bool     `protobuf:"varint,15,opt,name=blob,proto3" json:"blob,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -2101,6 +2341,13 @@ func (m *Posting) GetCommitTs() uint64 {
 	return 0
 }
 
+func (m *Posting) GetBlob() bool {
+	if m != nil {
+		return m.Blob
+	}
+	return false
+}
+
 type UidBlock struct {
 	Base uint64 `protobuf:"varint,1,opt,name=base,proto3" json:"base,omitempty"`
 	// deltas contains the deltas encoded with Varints. We don't store deltas as a list of integers,
@@ -2763,10 +3010,26 @@ type SchemaUpdate struct {
 	NonNullableList bool `protobuf:"varint,11,opt,name=non_nullable_list,json=nonNullableList,proto3" json:"non_nullable_list,omitempty"`
 	// If value_type is OBJECT, then this represents an object type with a
 	// custom name. This field stores said name.
-	ObjectTypeName       string   `protobuf:"bytes,12,opt,name=object_type_name,json=objectTypeName,proto3" json:"object_type_name,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	ObjectTypeName string `protobuf:"bytes,12,opt,name=object_type_name,json=objectTypeName,proto3" json:"object_type_name,omitempty"`
+	// Masking policy of the values, if any: hash, partial or drop.
+	Masked string `protobuf:"bytes,13,opt,name=masked,proto3" json:"masked,omitempty"`
+	// Maximum size in bytes of the values, if not zero.
+	MaxSize uint64 `protobuf:"varint,14,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`
+	// Language whose collation orders the values in the exact index, if any.
+	Collation string `protobuf:"bytes,15,opt,name=collation,proto3" json:"collation,omitempty"`
+	// Granularity of the conflict detection of the predicate, if not the default one:
+	// predicate, uid, value or none.
+	Conflict string `protobuf:"bytes,16,opt,name=conflict,proto3" json:"conflict,omitempty"`
+	// Whether values are only added to the predicate, without transactions.
+	AppendOnly bool `protobuf:"varint,17,opt,name=append_only,json=appendOnly,proto3" json:"append_only,omitempty"`
+	// The schema the indices of the predicate are being rebuilt from, until they're built.
+	IndexBase *SchemaUpdate `protobuf:"bytes,18,opt,name=index_base,json=indexBase,proto3" json:"index_base,omitempty"`
+	// The facets declared by the @facets directive, each with the name of the facet as the
+	// predicate, its value type and its index, if any.
+	Facets               []*SchemaUpdate `protobuf:"bytes,19,rep,name=facets,proto3" json:"facets,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
 }
 
 func (m *SchemaUpdate) Reset()         { *m = SchemaUpdate{} }
@@ -2879,9 +3142,60 @@ func (m *SchemaUpdate) GetObjectTypeName() string {
 	return ""
 }
 
+func (m *SchemaUpdate) GetMasked() string {
+	if m != nil {
+		return m.Masked
+	}
+	return ""
+}
+
+func (m *SchemaUpdate) GetMaxSize() uint64 {
+	if m != nil {
+		return m.MaxSize
+	}
+	return 0
+}
+
+func (m *SchemaUpdate) GetCollation() string {
+	if m != nil {
+		return m.Collation
+	}
+	return ""
+}
+
+func (m *SchemaUpdate) GetConflict() string {
+	if m != nil {
+		return m.Conflict
+	}
+	return ""
+}
+
+func (m *SchemaUpdate) GetAppendOnly() bool {
+	if m != nil {
+		return m.AppendOnly
+	}
+	return false
+}
+
+func (m *SchemaUpdate) GetIndexBase() *SchemaUpdate {
+	if m != nil {
+		return m.IndexBase
+	}
+	return nil
+}
+
+func (m *SchemaUpdate) GetFacets() []*SchemaUpdate {
+	if m != nil {
+		return m.Facets
+	}
+	return nil
+}
+
 type TypeUpdate struct {
 	TypeName             string          `protobuf:"bytes,1,opt,name=type_name,json=typeName,proto3" json:"type_name,omitempty"`
 	Fields               []*SchemaUpdate `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+	Key                  []string        `protobuf:"bytes,3,rep,name=key,proto3" json:"key,omitempty"`
+	Strict               bool            `protobuf:"varint,4,opt,name=strict,proto3" json:"strict,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
 	XXX_unrecognized     []byte          `json:"-"`
 	XXX_sizecache        int32           `json:"-"`
@@ -2934,6 +3248,20 @@ func (m *TypeUpdate) GetFields() []*SchemaUpdate {
 	return nil
 }
 
+func (m *TypeUpdate) GetKey() []string {
+	if m != nil {
+		return m.Key
+	}
+	return nil
+}
+
+func (m *TypeUpdate) GetStrict() bool {
+	if m != nil {
+		return m.Strict
+	}
+	return false
+}
+
 // Bulk loader proto.
 type MapEntry struct {
 	Key []byte `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
@@ -3341,6 +3669,7 @@ type Num struct {
 	Val                  uint64   `protobuf:"varint,1,opt,name=val,proto3" json:"val,omitempty"`
 	ReadOnly             bool     `protobuf:"varint,2,opt,name=read_only,json=readOnly,proto3" json:"read_only,omitempty"`
 	Forwarded            bool     `protobuf:"varint,3,opt,name=forwarded,proto3" json:"forwarded,omitempty"`
+	Sequence             string   `protobuf:"bytes,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -3400,6 +3729,13 @@ func (m *Num) GetForwarded() bool {
 	return false
 }
 
+func (m *Num) GetSequence() string {
+	if m != nil {
+		return m.Sequence
+	}
+	return ""
+}
+
 type AssignedIds struct {
 	StartId uint64 `protobuf:"varint,1,opt,name=startId,proto3" json:"startId,omitempty"`
 	EndId   uint64 `protobuf:"varint,2,opt,name=endId,proto3" json:"endId,omitempty"`
@@ -3930,50 +4266,183 @@ func (m *BackupPostingList) GetSplits() []uint64 {
 	return nil
 }
 
-func init() {
-	proto.RegisterEnum("pb.DirectedEdge_Op", DirectedEdge_Op_name, DirectedEdge_Op_value)
-	proto.RegisterEnum("pb.Mutations_DropOp", Mutations_DropOp_name, Mutations_DropOp_value)
-	proto.RegisterEnum("pb.Posting_ValType", Posting_ValType_name, Posting_ValType_value)
-	proto.RegisterEnum("pb.Posting_PostingType", Posting_PostingType_name, Posting_PostingType_value)
-	proto.RegisterEnum("pb.SchemaUpdate_Directive", SchemaUpdate_Directive_name, SchemaUpdate_Directive_value)
-	proto.RegisterEnum("pb.BackupKey_KeyType", BackupKey_KeyType_name, BackupKey_KeyType_value)
-	proto.RegisterType((*List)(nil), "pb.List")
-	proto.RegisterType((*TaskValue)(nil), "pb.TaskValue")
-	proto.RegisterType((*SrcFunction)(nil), "pb.SrcFunction")
-	proto.RegisterType((*Query)(nil), "pb.Query")
-	proto.RegisterType((*ValueList)(nil), "pb.ValueList")
-	proto.RegisterType((*LangList)(nil), "pb.LangList")
-	proto.RegisterType((*Result)(nil), "pb.Result")
-	proto.RegisterType((*Order)(nil), "pb.Order")
-	proto.RegisterType((*SortMessage)(nil), "pb.SortMessage")
-	proto.RegisterType((*SortResult)(nil), "pb.SortResult")
-	proto.RegisterType((*RaftContext)(nil), "pb.RaftContext")
-	proto.RegisterType((*Member)(nil), "pb.Member")
-	proto.RegisterType((*Group)(nil), "pb.Group")
-	proto.RegisterMapType((map[uint64]*Member)(nil), "pb.Group.MembersEntry")
-	proto.RegisterMapType((map[string]*Tablet)(nil), "pb.Group.TabletsEntry")
-	proto.RegisterType((*ZeroProposal)(nil), "pb.ZeroProposal")
-	proto.RegisterMapType((map[uint32]uint64)(nil), "pb.ZeroProposal.SnapshotTsEntry")
-	proto.RegisterType((*MembershipState)(nil), "pb.MembershipState")
-	proto.RegisterMapType((map[uint32]*Group)(nil), "pb.MembershipState.GroupsEntry")
-	proto.RegisterMapType((map[uint64]*Member)(nil), "pb.MembershipState.ZerosEntry")
-	proto.RegisterType((*ConnectionState)(nil), "pb.ConnectionState")
-	proto.RegisterType((*Tablet)(nil), "pb.Tablet")
-	proto.RegisterType((*DirectedEdge)(nil), "pb.DirectedEdge")
-	proto.RegisterType((*Mutations)(nil), "pb.Mutations")
-	proto.RegisterType((*Snapshot)(nil), "pb.Snapshot")
-	proto.RegisterType((*Proposal)(nil), "pb.Proposal")
-	proto.RegisterType((*KVS)(nil), "pb.KVS")
-	proto.RegisterType((*Posting)(nil), "pb.Posting")
-	proto.RegisterType((*UidBlock)(nil), "pb.UidBlock")
-	proto.RegisterType((*UidPack)(nil), "pb.UidPack")
-	proto.RegisterType((*PostingList)(nil), "pb.PostingList")
-	proto.RegisterType((*FacetParam)(nil), "pb.FacetParam")
-	proto.RegisterType((*FacetParams)(nil), "pb.FacetParams")
-	proto.RegisterType((*Facets)(nil), "pb.Facets")
-	proto.RegisterType((*FacetsList)(nil), "pb.FacetsList")
-	proto.RegisterType((*Function)(nil), "pb.Function")
-	proto.RegisterType((*FilterTree)(nil), "pb.FilterTree")
+type ImportRequest struct {
+	// A chunk of the data. The chunks of RDF data can end in the middle of a line, each chunk
+	// of JSON data is a JSON object or array.
+	Data []byte `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	// Format of the data, rdf or json. Only read from the first request of the stream.
+	Format string `protobuf:"bytes,2,opt,name=format,proto3" json:"format,omitempty"`
+	// Number of N-Quads per transaction. Only read from the first request of the stream.
+	BatchSize            uint32   `protobuf:"varint,3,opt,name=batch_size,json=batchSize,proto3" json:"batch_size,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportRequest) Reset()         { *m = ImportRequest{} }
+func (m *ImportRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportRequest) ProtoMessage()    {}
+func (*ImportRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f80abaa17e25ccc8, []int{51}
+}
+func (m *ImportRequest) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ImportRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ImportRequest.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ImportRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportRequest.Merge(m, src)
+}
+func (m *ImportRequest) XXX_Size() int {
+	return m.Size()
+}
+func (m *ImportRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportRequest proto.InternalMessageInfo
+
+func (m *ImportRequest) GetData() []byte {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *ImportRequest) GetFormat() string {
+	if m != nil {
+		return m.Format
+	}
+	return ""
+}
+
+func (m *ImportRequest) GetBatchSize() uint32 {
+	if m != nil {
+		return m.BatchSize
+	}
+	return 0
+}
+
+type ImportResponse struct {
+	Nquads               uint64   `protobuf:"varint,1,opt,name=nquads,proto3" json:"nquads,omitempty"`
+	Txns                 uint64   `protobuf:"varint,2,opt,name=txns,proto3" json:"txns,omitempty"`
+	Aborts               uint64   `protobuf:"varint,3,opt,name=aborts,proto3" json:"aborts,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ImportResponse) Reset()         { *m = ImportResponse{} }
+func (m *ImportResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportResponse) ProtoMessage()    {}
+func (*ImportResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_f80abaa17e25ccc8, []int{52}
+}
+func (m *ImportResponse) XXX_Unmarshal(b []byte) error {
+	return m.Unmarshal(b)
+}
+func (m *ImportResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	if deterministic {
+		return xxx_messageInfo_ImportResponse.Marshal(b, m, deterministic)
+	} else {
+		b = b[:cap(b)]
+		n, err := m.MarshalToSizedBuffer(b)
+		if err != nil {
+			return nil, err
+		}
+		return b[:n], nil
+	}
+}
+func (m *ImportResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ImportResponse.Merge(m, src)
+}
+func (m *ImportResponse) XXX_Size() int {
+	return m.Size()
+}
+func (m *ImportResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_ImportResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ImportResponse proto.InternalMessageInfo
+
+func (m *ImportResponse) GetNquads() uint64 {
+	if m != nil {
+		return m.Nquads
+	}
+	return 0
+}
+
+func (m *ImportResponse) GetTxns() uint64 {
+	if m != nil {
+		return m.Txns
+	}
+	return 0
+}
+
+func (m *ImportResponse) GetAborts() uint64 {
+	if m != nil {
+		return m.Aborts
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterEnum("pb.DirectedEdge_Op", DirectedEdge_Op_name, DirectedEdge_Op_value)
+	proto.RegisterEnum("pb.Mutations_DropOp", Mutations_DropOp_name, Mutations_DropOp_value)
+	proto.RegisterEnum("pb.Posting_ValType", Posting_ValType_name, Posting_ValType_value)
+	proto.RegisterEnum("pb.Posting_PostingType", Posting_PostingType_name, Posting_PostingType_value)
+	proto.RegisterEnum("pb.SchemaUpdate_Directive", SchemaUpdate_Directive_name, SchemaUpdate_Directive_value)
+	proto.RegisterEnum("pb.BackupKey_KeyType", BackupKey_KeyType_name, BackupKey_KeyType_value)
+	proto.RegisterType((*List)(nil), "pb.List")
+	proto.RegisterType((*TaskValue)(nil), "pb.TaskValue")
+	proto.RegisterType((*SrcFunction)(nil), "pb.SrcFunction")
+	proto.RegisterType((*Query)(nil), "pb.Query")
+	proto.RegisterType((*ValueList)(nil), "pb.ValueList")
+	proto.RegisterType((*LangList)(nil), "pb.LangList")
+	proto.RegisterType((*Result)(nil), "pb.Result")
+	proto.RegisterType((*Order)(nil), "pb.Order")
+	proto.RegisterType((*SortMessage)(nil), "pb.SortMessage")
+	proto.RegisterType((*SortResult)(nil), "pb.SortResult")
+	proto.RegisterType((*RaftContext)(nil), "pb.RaftContext")
+	proto.RegisterType((*Member)(nil), "pb.Member")
+	proto.RegisterType((*Group)(nil), "pb.Group")
+	proto.RegisterMapType((map[uint64]*Member)(nil), "pb.Group.MembersEntry")
+	proto.RegisterMapType((map[string]*Tablet)(nil), "pb.Group.TabletsEntry")
+	proto.RegisterType((*ZeroProposal)(nil), "pb.ZeroProposal")
+	proto.RegisterMapType((map[uint32]uint64)(nil), "pb.ZeroProposal.SnapshotTsEntry")
+	proto.RegisterType((*MembershipState)(nil), "pb.MembershipState")
+	proto.RegisterMapType((map[uint32]*Group)(nil), "pb.MembershipState.GroupsEntry")
+	proto.RegisterMapType((map[uint64]*Member)(nil), "pb.MembershipState.ZerosEntry")
+	proto.RegisterMapType((map[string]uint64)(nil), "pb.MembershipState.SequencesEntry")
+	proto.RegisterMapType((map[string]uint32)(nil), "pb.MembershipState.PinnedTabletsEntry")
+	proto.RegisterMapType((map[string]uint32)(nil), "pb.MembershipState.FrozenPredicatesEntry")
+	proto.RegisterType((*ConnectionState)(nil), "pb.ConnectionState")
+	proto.RegisterType((*Tablet)(nil), "pb.Tablet")
+	proto.RegisterType((*DirectedEdge)(nil), "pb.DirectedEdge")
+	proto.RegisterType((*Mutations)(nil), "pb.Mutations")
+	proto.RegisterType((*Snapshot)(nil), "pb.Snapshot")
+	proto.RegisterType((*Proposal)(nil), "pb.Proposal")
+	proto.RegisterType((*KVS)(nil), "pb.KVS")
+	proto.RegisterType((*Posting)(nil), "pb.Posting")
+	proto.RegisterType((*UidBlock)(nil), "pb.UidBlock")
+	proto.RegisterType((*UidPack)(nil), "pb.UidPack")
+	proto.RegisterType((*PostingList)(nil), "pb.PostingList")
+	proto.RegisterType((*FacetParam)(nil), "pb.FacetParam")
+	proto.RegisterType((*FacetParams)(nil), "pb.FacetParams")
+	proto.RegisterType((*Facets)(nil), "pb.Facets")
+	proto.RegisterType((*FacetsList)(nil), "pb.FacetsList")
+	proto.RegisterType((*Function)(nil), "pb.Function")
+	proto.RegisterType((*FilterTree)(nil), "pb.FilterTree")
 	proto.RegisterType((*SchemaRequest)(nil), "pb.SchemaRequest")
 	proto.RegisterType((*SchemaResult)(nil), "pb.SchemaResult")
 	proto.RegisterType((*SchemaUpdate)(nil), "pb.SchemaUpdate")
@@ -3994,244 +4463,281 @@ func init() {
 	proto.RegisterType((*ExportRequest)(nil), "pb.ExportRequest")
 	proto.RegisterType((*BackupKey)(nil), "pb.BackupKey")
 	proto.RegisterType((*BackupPostingList)(nil), "pb.BackupPostingList")
+	proto.RegisterType((*ImportRequest)(nil), "pb.ImportRequest")
+	proto.RegisterType((*ImportResponse)(nil), "pb.ImportResponse")
 }
 
 func init() { proto.RegisterFile("pb.proto", fileDescriptor_f80abaa17e25ccc8) }
 
 var fileDescriptor_f80abaa17e25ccc8 = []byte{
-	// 3702 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb4, 0x5a, 0xcd, 0x6f, 0xe3, 0x48,
-	0x76, 0x6f, 0x52, 0x12, 0x45, 0x3e, 0xc9, 0x6e, 0x4d, 0xcd, 0x4c, 0x8f, 0xc6, 0xb3, 0xdb, 0xed,
-	0xe1, 0x7c, 0x79, 0xba, 0xb7, 0xdd, 0x3d, 0x9e, 0x0d, 0xb2, 0xb3, 0x41, 0x0e, 0x6e, 0x5b, 0xdd,
-	0xeb, 0x69, 0x5b, 0xf6, 0x96, 0xe4, 0x9e, 0xec, 0x1c, 0x22, 0xd0, 0x64, 0x59, 0xe6, 0x9a, 0x22,
-	0x19, 0x16, 0xe5, 0xc8, 0x73, 0xcb, 0x21, 0x01, 0x02, 0x24, 0xa7, 0x5c, 0xf6, 0x10, 0xe4, 0x10,
-	0x20, 0x97, 0x5c, 0x72, 0x5d, 0xe4, 0x18, 0x20, 0x40, 0x8e, 0x41, 0xfe, 0x82, 0x60, 0x92, 0x63,
-	0xce, 0x01, 0x72, 0x0b, 0xde, 0xab, 0xa2, 0x48, 0xaa, 0xdd, 0x3d, 0x3b, 0x0b, 0xec, 0x49, 0xf5,
-	0x3e, 0xea, 0xeb, 0x57, 0xaf, 0xde, 0x7b, 0xf5, 0x28, 0xb0, 0xd3, 0xb3, 0xed, 0x34, 0x4b, 0xf2,
-	0x84, 0x99, 0xe9, 0xd9, 0x86, 0xe3, 0xa5, 0xa1, 0x22, 0x37, 0x3e, 0x99, 0x86, 0xf9, 0xc5, 0xfc,
-	0x6c, 0xdb, 0x4f, 0x66, 0x8f, 0x82, 0x69, 0xe6, 0xa5, 0x17, 0x0f, 0xc3, 0xe4, 0xd1, 0x99, 0x17,
-	0x4c, 0x45, 0xf6, 0x28, 0x3d, 0x7b, 0x54, 0xf4, 0x73, 0x37, 0xa0, 0x79, 0x18, 0xca, 0x9c, 0x31,
-	0x68, 0xce, 0xc3, 0x40, 0xf6, 0x8d, 0xcd, 0xc6, 0x96, 0xc5, 0xa9, 0xed, 0x1e, 0x81, 0x33, 0xf6,
-	0xe4, 0xe5, 0x0b, 0x2f, 0x9a, 0x0b, 0xd6, 0x83, 0xc6, 0x95, 0x17, 0xf5, 0x8d, 0x4d, 0x63, 0xab,
-	0xcb, 0xb1, 0xc9, 0xb6, 0xc1, 0xbe, 0xf2, 0xa2, 0x49, 0x7e, 0x9d, 0x8a, 0xbe, 0xb9, 0x69, 0x6c,
-	0xad, 0xef, 0xbc, 0xb9, 0x9d, 0x9e, 0x6d, 0x9f, 0x24, 0x32, 0x0f, 0xe3, 0xe9, 0xf6, 0x0b, 0x2f,
-	0x1a, 0x5f, 0xa7, 0x82, 0xb7, 0xaf, 0x54, 0xc3, 0x3d, 0x86, 0xce, 0x28, 0xf3, 0x9f, 0xce, 0x63,
-	0x3f, 0x0f, 0x93, 0x18, 0x67, 0x8c, 0xbd, 0x99, 0xa0, 0x11, 0x1d, 0x4e, 0x6d, 0xe4, 0x79, 0xd9,
-	0x54, 0xf6, 0x1b, 0x9b, 0x0d, 0xe4, 0x61, 0x9b, 0xf5, 0xa1, 0x1d, 0xca, 0xbd, 0x64, 0x1e, 0xe7,
-	0xfd, 0xe6, 0xa6, 0xb1, 0x65, 0xf3, 0x82, 0x74, 0xff, 0xb2, 0x01, 0xad, 0x9f, 0xcf, 0x45, 0x76,
-	0x4d, 0xfd, 0xf2, 0x3c, 0x2b, 0xc6, 0xc2, 0x36, 0x7b, 0x0b, 0x5a, 0x91, 0x17, 0x4f, 0x65, 0xdf,
-	0xa4, 0xc1, 0x14, 0xc1, 0xde, 0x03, 0xc7, 0x3b, 0xcf, 0x45, 0x36, 0x99, 0x87, 0x41, 0xbf, 0xb1,
-	0x69, 0x6c, 0x59, 0xdc, 0x26, 0xc6, 0x69, 0x18, 0xb0, 0x77, 0xc1, 0x0e, 0x92, 0x89, 0x5f, 0x9d,
-	0x2b, 0x48, 0x68, 0x2e, 0xf6, 0x01, 0xd8, 0xf3, 0x30, 0x98, 0x44, 0xa1, 0xcc, 0xfb, 0xad, 0x4d,
-	0x63, 0xab, 0xb3, 0x63, 0xe3, 0x66, 0x11, 0x3b, 0xde, 0x9e, 0x87, 0x01, 0x81, 0x78, 0x1f, 0x6c,
-	0x99, 0xf9, 0x93, 0xf3, 0x79, 0xec, 0xf7, 0x2d, 0x52, 0xba, 0x8d, 0x4a, 0x95, 0x5d, 0xf3, 0xb6,
-	0x54, 0x04, 0x6e, 0x2b, 0x13, 0x57, 0x22, 0x93, 0xa2, 0xdf, 0x56, 0x53, 0x69, 0x92, 0x3d, 0x86,
-	0xce, 0xb9, 0xe7, 0x8b, 0x7c, 0x92, 0x7a, 0x99, 0x37, 0xeb, 0xdb, 0xe5, 0x40, 0x4f, 0x91, 0x7d,
-	0x82, 0x5c, 0xc9, 0xe1, 0x7c, 0x49, 0xb0, 0xcf, 0x61, 0x8d, 0x28, 0x39, 0x39, 0x0f, 0xa3, 0x5c,
-	0x64, 0x7d, 0x87, 0xfa, 0xac, 0x53, 0x1f, 0xe2, 0x8c, 0x33, 0x21, 0x78, 0x57, 0x29, 0x29, 0x0e,
-	0xfb, 0x21, 0x80, 0x58, 0xa4, 0x5e, 0x1c, 0x4c, 0xbc, 0x28, 0xea, 0x03, 0xad, 0xc1, 0x51, 0x9c,
-	0xdd, 0x28, 0x62, 0xef, 0xe0, 0xfa, 0xbc, 0x60, 0x92, 0xcb, 0xfe, 0xda, 0xa6, 0xb1, 0xd5, 0xe4,
-	0x16, 0x92, 0x63, 0x89, 0xb8, 0xfa, 0x9e, 0x7f, 0x21, 0xfa, 0xeb, 0x9b, 0xc6, 0x56, 0x8b, 0x2b,
-	0xc2, 0xdd, 0x01, 0x87, 0xec, 0x84, 0x70, 0xf8, 0x08, 0xac, 0x2b, 0x24, 0x94, 0x39, 0x75, 0x76,
-	0xd6, 0x70, 0x21, 0x4b, 0x53, 0xe2, 0x5a, 0xe8, 0xde, 0x05, 0xfb, 0xd0, 0x8b, 0xa7, 0x85, 0xfd,
-	0xe1, 0x01, 0x51, 0x07, 0x87, 0x53, 0xdb, 0xfd, 0x95, 0x09, 0x16, 0x17, 0x72, 0x1e, 0xe5, 0xec,
-	0x13, 0x00, 0x84, 0x7f, 0xe6, 0xe5, 0x59, 0xb8, 0xd0, 0xa3, 0x96, 0x07, 0xe0, 0xcc, 0xc3, 0xe0,
-	0x88, 0x44, 0xec, 0x31, 0x74, 0x69, 0xf4, 0x42, 0xd5, 0x2c, 0x17, 0xb0, 0x5c, 0x1f, 0xef, 0x90,
-	0x8a, 0xee, 0x71, 0x07, 0x2c, 0x3a, 0x71, 0x65, 0x75, 0x6b, 0x5c, 0x53, 0xec, 0x23, 0x58, 0x0f,
-	0xe3, 0x1c, 0x4f, 0xc4, 0xcf, 0x27, 0x81, 0x90, 0x85, 0x49, 0xac, 0x2d, 0xb9, 0xfb, 0x42, 0xe6,
-	0xec, 0x33, 0x50, 0xb0, 0x16, 0x13, 0xb6, 0x68, 0xc2, 0xf5, 0xe5, 0x71, 0x49, 0x35, 0x23, 0xe9,
-	0xe8, 0x19, 0x1f, 0x42, 0x07, 0xf7, 0x57, 0xf4, 0xb0, 0xa8, 0x47, 0x97, 0x76, 0xa3, 0xe1, 0xe0,
-	0x80, 0x0a, 0x5a, 0x1d, 0xa1, 0x41, 0xb3, 0x53, 0x66, 0x42, 0x6d, 0x77, 0x00, 0xad, 0xe3, 0x2c,
-	0x10, 0xd9, 0x8d, 0x96, 0xcf, 0xa0, 0x19, 0x08, 0xe9, 0xd3, 0xa5, 0xb4, 0x39, 0xb5, 0xcb, 0xdb,
-	0xd0, 0xa8, 0xdc, 0x06, 0xf7, 0xef, 0x0c, 0xe8, 0x8c, 0x92, 0x2c, 0x3f, 0x12, 0x52, 0x7a, 0x53,
-	0xc1, 0xee, 0x41, 0x2b, 0xc1, 0x61, 0x35, 0xc2, 0x0e, 0xae, 0x89, 0xe6, 0xe1, 0x8a, 0xbf, 0x72,
-	0x0e, 0xe6, 0xab, 0xcf, 0x01, 0xad, 0x84, 0xee, 0x51, 0x43, 0x5b, 0x09, 0xdd, 0xa2, 0x3b, 0x60,
-	0x25, 0xe7, 0xe7, 0x52, 0x28, 0x2c, 0x5b, 0x5c, 0x53, 0xaf, 0x34, 0x36, 0xf7, 0xf7, 0x00, 0x70,
-	0x7d, 0xdf, 0xd3, 0x0a, 0xdc, 0x0b, 0xe8, 0x70, 0xef, 0x3c, 0xdf, 0x4b, 0xe2, 0x5c, 0x2c, 0x72,
-	0xb6, 0x0e, 0x66, 0x18, 0x10, 0x44, 0x16, 0x37, 0xc3, 0x00, 0x17, 0x37, 0xcd, 0x92, 0x79, 0x4a,
-	0x08, 0xad, 0x71, 0x45, 0x10, 0x94, 0x41, 0x90, 0xd1, 0x8a, 0x11, 0xca, 0x20, 0xc8, 0xd8, 0x3d,
-	0xe8, 0xc8, 0xd8, 0x4b, 0xe5, 0x45, 0x92, 0xe3, 0xe2, 0x9a, 0xb4, 0x38, 0x28, 0x58, 0x63, 0xe9,
-	0xfe, 0xab, 0x01, 0xd6, 0x91, 0x98, 0x9d, 0x89, 0xec, 0xa5, 0x59, 0xde, 0x05, 0x9b, 0x06, 0x9e,
-	0x84, 0x81, 0x9e, 0xa8, 0x4d, 0xf4, 0x41, 0x70, 0xe3, 0x54, 0x77, 0xc0, 0x8a, 0x84, 0x87, 0xe0,
-	0x2b, 0x3b, 0xd3, 0x14, 0x62, 0xe3, 0xcd, 0x26, 0x81, 0xf0, 0x02, 0x72, 0x3c, 0x36, 0xb7, 0xbc,
-	0xd9, 0xbe, 0xf0, 0x02, 0x5c, 0x5b, 0xe4, 0xc9, 0x7c, 0x32, 0x4f, 0x03, 0x2f, 0x17, 0xe4, 0x70,
-	0x9a, 0x68, 0x38, 0x32, 0x3f, 0x25, 0x0e, 0xbb, 0x0f, 0x6f, 0xf8, 0xd1, 0x5c, 0xa2, 0xb7, 0x0b,
-	0xe3, 0xf3, 0x64, 0x92, 0xc4, 0xd1, 0x35, 0xe1, 0x6b, 0xf3, 0xdb, 0x5a, 0x70, 0x10, 0x9f, 0x27,
-	0xc7, 0x71, 0x74, 0xed, 0xfe, 0xda, 0x84, 0xd6, 0x33, 0x82, 0xe1, 0x31, 0xb4, 0x67, 0xb4, 0xa1,
-	0xe2, 0xf6, 0xde, 0x41, 0x84, 0x49, 0xb6, 0xad, 0x76, 0x2a, 0x07, 0x71, 0x9e, 0x5d, 0xf3, 0x42,
-	0x0d, 0x7b, 0xe4, 0xde, 0x59, 0x24, 0x72, 0xa9, 0x2d, 0xa2, 0xd2, 0x63, 0xac, 0x04, 0xba, 0x87,
-	0x56, 0x5b, 0x85, 0xb5, 0xb1, 0x0a, 0x2b, 0xdb, 0x00, 0xdb, 0xbf, 0x10, 0xfe, 0xa5, 0x9c, 0xcf,
-	0x34, 0xe8, 0x4b, 0x7a, 0xe3, 0x29, 0x74, 0xab, 0xeb, 0xc0, 0xc8, 0x74, 0x29, 0xae, 0x09, 0xf8,
-	0x26, 0xc7, 0x26, 0xdb, 0x84, 0x16, 0xdd, 0x70, 0x82, 0xbd, 0xb3, 0x03, 0xb8, 0x1c, 0xd5, 0x85,
-	0x2b, 0xc1, 0x4f, 0xcd, 0x9f, 0x18, 0x38, 0x4e, 0x75, 0x75, 0xd5, 0x71, 0x9c, 0x57, 0x8f, 0xa3,
-	0xba, 0x54, 0xc6, 0x71, 0xff, 0xcf, 0x84, 0xee, 0xd7, 0x22, 0x4b, 0x4e, 0xb2, 0x24, 0x4d, 0xa4,
-	0x17, 0xb1, 0xdd, 0xfa, 0xee, 0x14, 0x8a, 0x9b, 0xd8, 0xb9, 0xaa, 0xb6, 0x3d, 0x5a, 0x6e, 0x57,
-	0xa1, 0x53, 0xdd, 0xbf, 0x0b, 0x96, 0x42, 0xf7, 0x86, 0x2d, 0x68, 0x09, 0xea, 0x28, 0x3c, 0x09,
-	0xbf, 0xfa, 0xf2, 0xb4, 0x84, 0xdd, 0x05, 0x98, 0x79, 0x8b, 0x43, 0xe1, 0x49, 0x71, 0x10, 0x14,
-	0xe6, 0x5b, 0x72, 0x10, 0xe7, 0x99, 0xb7, 0x18, 0x2f, 0xe2, 0xb1, 0x24, 0xeb, 0x6a, 0xf2, 0x25,
-	0xcd, 0x7e, 0x00, 0xce, 0xcc, 0x5b, 0xe0, 0x3d, 0x3a, 0x08, 0xb4, 0x75, 0x95, 0x0c, 0xf6, 0x3e,
-	0x34, 0xf2, 0x45, 0x4c, 0x4e, 0x09, 0xa3, 0x13, 0xa6, 0x1e, 0xe3, 0x45, 0xac, 0x6f, 0x1c, 0x47,
-	0x59, 0x01, 0xa8, 0x5d, 0x02, 0xda, 0x83, 0x86, 0x1f, 0x06, 0x14, 0x9e, 0x1c, 0x8e, 0xcd, 0x8d,
-	0x3f, 0x84, 0xdb, 0x2b, 0x38, 0x54, 0xcf, 0x61, 0x4d, 0x75, 0x7b, 0xab, 0x7a, 0x0e, 0xcd, 0x2a,
-	0xf6, 0xbf, 0x6e, 0xc0, 0x6d, 0x6d, 0x0c, 0x17, 0x61, 0x3a, 0xca, 0xd1, 0xec, 0xfb, 0xd0, 0x26,
-	0x6f, 0x23, 0x32, 0x6d, 0x13, 0x05, 0xc9, 0x7e, 0x1f, 0x2c, 0xba, 0x81, 0x85, 0x9d, 0xde, 0x2b,
-	0x51, 0x5d, 0x76, 0x57, 0x76, 0xab, 0x8f, 0x44, 0xab, 0xb3, 0x1f, 0x43, 0xeb, 0x1b, 0x91, 0x25,
-	0xca, 0x7b, 0x76, 0x76, 0xee, 0xde, 0xd4, 0x0f, 0xcf, 0x56, 0x77, 0x53, 0xca, 0xbf, 0x43, 0xf0,
-	0x3f, 0x44, 0x7f, 0x39, 0x4b, 0xae, 0x44, 0xd0, 0x6f, 0xd3, 0x8a, 0xaa, 0xf6, 0x51, 0x88, 0x0a,
-	0xb4, 0xed, 0x12, 0xed, 0x7d, 0xe8, 0x54, 0xb6, 0x77, 0x03, 0xd2, 0xf7, 0xea, 0x16, 0xef, 0x2c,
-	0x2f, 0x72, 0xf5, 0xe2, 0xec, 0x03, 0x94, 0x9b, 0xfd, 0x6d, 0xaf, 0x9f, 0xfb, 0x67, 0x06, 0xdc,
-	0xde, 0x4b, 0xe2, 0x58, 0x50, 0x62, 0xa4, 0x8e, 0xae, 0x34, 0x7b, 0xe3, 0x95, 0x66, 0xff, 0x29,
-	0xb4, 0x24, 0x2a, 0xeb, 0xd1, 0xdf, 0xbc, 0xe1, 0x2c, 0xb8, 0xd2, 0x40, 0x37, 0x33, 0xf3, 0x16,
-	0x93, 0x54, 0xc4, 0x41, 0x18, 0x4f, 0x0b, 0x37, 0x33, 0xf3, 0x16, 0x27, 0x8a, 0xe3, 0xfe, 0xbd,
-	0x01, 0x96, 0xba, 0x31, 0x35, 0x6f, 0x6d, 0xd4, 0xbd, 0xf5, 0x0f, 0xc0, 0x49, 0x33, 0x11, 0x84,
-	0x7e, 0x31, 0xab, 0xc3, 0x4b, 0x06, 0x1a, 0xe7, 0x79, 0x92, 0xf9, 0x82, 0x86, 0xb7, 0xb9, 0x22,
-	0x90, 0x2b, 0x53, 0xcf, 0x57, 0xc9, 0x5d, 0x83, 0x2b, 0x02, 0x7d, 0xbc, 0x3a, 0x1c, 0x3a, 0x14,
-	0x9b, 0x6b, 0x0a, 0xb3, 0x52, 0x8a, 0x7f, 0xe4, 0xa1, 0x1d, 0x12, 0xd9, 0xc8, 0x20, 0xd7, 0xfc,
-	0x8f, 0x26, 0x74, 0xf7, 0xc3, 0x4c, 0xf8, 0xb9, 0x08, 0x06, 0xc1, 0x94, 0x46, 0x11, 0x71, 0x1e,
-	0xe6, 0xd7, 0x3a, 0xd8, 0x68, 0x6a, 0x99, 0x0b, 0x98, 0xf5, 0x2c, 0x58, 0x9d, 0x45, 0x83, 0x12,
-	0x77, 0x45, 0xb0, 0x1d, 0x00, 0x95, 0x25, 0x51, 0xf2, 0xde, 0x7c, 0x75, 0xf2, 0xee, 0x90, 0x1a,
-	0x36, 0x11, 0x20, 0xd5, 0x27, 0x54, 0x81, 0xc8, 0xa2, 0xcc, 0x7e, 0x8e, 0x86, 0x4c, 0xc9, 0xc5,
-	0x99, 0x88, 0xc8, 0x50, 0x29, 0xb9, 0x38, 0x13, 0xd1, 0x32, 0xa5, 0x6b, 0xab, 0xe5, 0x60, 0x9b,
-	0x7d, 0x00, 0x66, 0x92, 0xd2, 0xe6, 0xf5, 0x84, 0xd5, 0x8d, 0x6d, 0x1f, 0xa7, 0xdc, 0x4c, 0x52,
-	0xb4, 0x02, 0x95, 0xa9, 0xf6, 0x1d, 0x6d, 0xdc, 0xe8, 0x5d, 0x28, 0x9b, 0xe2, 0x5a, 0xe2, 0xde,
-	0x01, 0xf3, 0x38, 0x65, 0x6d, 0x68, 0x8c, 0x06, 0xe3, 0xde, 0x2d, 0x6c, 0xec, 0x0f, 0x0e, 0x7b,
-	0x86, 0xfb, 0x3f, 0x26, 0x38, 0x47, 0xf3, 0xdc, 0x43, 0x9b, 0x92, 0xaf, 0x3b, 0xd4, 0x77, 0xc1,
-	0x96, 0xb9, 0x97, 0x91, 0x87, 0x56, 0x6e, 0xa5, 0x4d, 0xf4, 0x58, 0xb2, 0x8f, 0xa1, 0x25, 0x82,
-	0xa9, 0x28, 0x6e, 0x7b, 0x6f, 0x75, 0x9d, 0x5c, 0x89, 0xd9, 0x16, 0x58, 0xd2, 0xbf, 0x10, 0x33,
-	0xaf, 0xdf, 0x2c, 0x15, 0x47, 0xc4, 0x51, 0x11, 0x98, 0x6b, 0x39, 0xdb, 0x81, 0xb7, 0xc3, 0x69,
-	0x9c, 0x64, 0x62, 0x12, 0xc6, 0x81, 0x58, 0x4c, 0xfc, 0x24, 0x3e, 0x8f, 0x42, 0x3f, 0xd7, 0x11,
-	0xfd, 0x4d, 0x25, 0x3c, 0x40, 0xd9, 0x9e, 0x16, 0xb1, 0x0f, 0xa1, 0x85, 0xa7, 0x23, 0x75, 0x7e,
-	0x48, 0x19, 0x25, 0x1e, 0x84, 0x1e, 0x5a, 0x09, 0xd9, 0x43, 0x68, 0x07, 0x59, 0x92, 0x4e, 0x92,
-	0x94, 0x70, 0x5e, 0xdf, 0x79, 0x8b, 0xee, 0x43, 0x81, 0xc0, 0xf6, 0x7e, 0x96, 0xa4, 0xc7, 0x29,
-	0xb7, 0x02, 0xfa, 0xc5, 0xa4, 0x9f, 0xd4, 0x95, 0x4d, 0x28, 0xcf, 0xe0, 0x20, 0x87, 0x92, 0x63,
-	0xf7, 0x11, 0x58, 0xaa, 0x03, 0xb3, 0xa1, 0x39, 0x3c, 0x1e, 0x0e, 0x14, 0xb4, 0xbb, 0x87, 0x87,
-	0x3d, 0x03, 0x59, 0xfb, 0xbb, 0xe3, 0xdd, 0x9e, 0x89, 0xad, 0xf1, 0x2f, 0x4e, 0x06, 0xbd, 0x86,
-	0xfb, 0x37, 0x06, 0xd8, 0x85, 0xff, 0x66, 0x9f, 0xa2, 0xe3, 0x25, 0xff, 0xaf, 0xaf, 0x2f, 0x3d,
-	0x5a, 0x2a, 0x89, 0x18, 0x2f, 0xe4, 0x68, 0x31, 0x84, 0x44, 0xe1, 0xd1, 0x89, 0xa8, 0xa6, 0x81,
-	0x8d, 0xda, 0x9b, 0x03, 0x33, 0xda, 0x24, 0x16, 0x3a, 0x33, 0xa2, 0x36, 0x1d, 0x60, 0x18, 0xfb,
-	0x02, 0xb5, 0x5b, 0xfa, 0x00, 0x91, 0x1e, 0x4b, 0xf7, 0x6f, 0x4d, 0xb0, 0x97, 0xd1, 0xf8, 0x01,
-	0x38, 0xb3, 0x02, 0x0e, 0xed, 0x33, 0xd6, 0x6a, 0x18, 0xf1, 0x52, 0xce, 0xee, 0x80, 0x79, 0x79,
-	0xa5, 0x8f, 0xd3, 0x42, 0xad, 0xe7, 0x2f, 0xb8, 0x79, 0x79, 0x55, 0x3a, 0x9d, 0xd6, 0x77, 0x3a,
-	0x9d, 0x4f, 0xe0, 0xb6, 0x1f, 0x09, 0x2f, 0x9e, 0x94, 0x3e, 0x43, 0x5d, 0x8b, 0x75, 0x62, 0x9f,
-	0x2c, 0x1d, 0x87, 0x76, 0x9c, 0xed, 0x32, 0x3c, 0x7e, 0x04, 0xad, 0x40, 0x44, 0xb9, 0x57, 0x7d,
-	0xf3, 0x1d, 0x67, 0x9e, 0x1f, 0x89, 0x7d, 0x64, 0x73, 0x25, 0x65, 0x5b, 0x60, 0x17, 0xa9, 0x82,
-	0x7e, 0xe9, 0xd1, 0xe3, 0xa1, 0x38, 0x07, 0xbe, 0x94, 0x96, 0x30, 0x43, 0x05, 0x66, 0xf7, 0x33,
-	0x68, 0x3c, 0x7f, 0x31, 0xd2, 0x7b, 0x35, 0x5e, 0xda, 0x6b, 0x01, 0xb6, 0x59, 0x82, 0xed, 0xfe,
-	0x6f, 0x03, 0xda, 0xda, 0x37, 0xe0, 0xba, 0xe7, 0xcb, 0x44, 0x17, 0x9b, 0xf5, 0xf8, 0xbc, 0x74,
-	0x32, 0xd5, 0xfa, 0x40, 0xe3, 0xbb, 0xeb, 0x03, 0xec, 0xa7, 0xd0, 0x4d, 0x95, 0xac, 0xea, 0x96,
-	0xde, 0xa9, 0xf6, 0xd1, 0xbf, 0xd4, 0xaf, 0x93, 0x96, 0x04, 0x1a, 0x03, 0x3d, 0xa9, 0x72, 0x6f,
-	0x4a, 0x47, 0xd4, 0xe5, 0x6d, 0xa4, 0xc7, 0xde, 0xf4, 0x15, 0xce, 0xe9, 0x37, 0xf0, 0x31, 0x98,
-	0xd0, 0x27, 0x69, 0xbf, 0x4b, 0x7e, 0x03, 0xfd, 0x52, 0xd5, 0x65, 0xac, 0xd5, 0x5d, 0xc6, 0x7b,
-	0xe0, 0xf8, 0xc9, 0x6c, 0x16, 0x92, 0x6c, 0x5d, 0x27, 0xac, 0xc4, 0x18, 0x4b, 0xf7, 0x2f, 0x0c,
-	0x68, 0xeb, 0xdd, 0xb2, 0x0e, 0xb4, 0xf7, 0x07, 0x4f, 0x77, 0x4f, 0x0f, 0xd1, 0x6b, 0x01, 0x58,
-	0x4f, 0x0e, 0x86, 0xbb, 0xfc, 0x17, 0x3d, 0x03, 0xaf, 0xd9, 0xc1, 0x70, 0xdc, 0x33, 0x99, 0x03,
-	0xad, 0xa7, 0x87, 0xc7, 0xbb, 0xe3, 0x5e, 0x03, 0xef, 0xd9, 0x93, 0xe3, 0xe3, 0xc3, 0x5e, 0x93,
-	0x75, 0xc1, 0xde, 0xdf, 0x1d, 0x0f, 0xc6, 0x07, 0x47, 0x83, 0x5e, 0x0b, 0x75, 0x9f, 0x0d, 0x8e,
-	0x7b, 0x16, 0x36, 0x4e, 0x0f, 0xf6, 0x7b, 0x6d, 0x94, 0x9f, 0xec, 0x8e, 0x46, 0x5f, 0x1d, 0xf3,
-	0xfd, 0x9e, 0x8d, 0xe3, 0x8e, 0xc6, 0xfc, 0x60, 0xf8, 0xac, 0xe7, 0x60, 0xfb, 0xf8, 0xc9, 0x97,
-	0x83, 0xbd, 0x71, 0x0f, 0xdc, 0xcf, 0xa0, 0x53, 0x41, 0x10, 0x7b, 0xf3, 0xc1, 0xd3, 0xde, 0x2d,
-	0x9c, 0xf2, 0xc5, 0xee, 0xe1, 0xe9, 0xa0, 0x67, 0xb0, 0x75, 0x00, 0x6a, 0x4e, 0x0e, 0x77, 0x87,
-	0xcf, 0x7a, 0xa6, 0xfb, 0x73, 0xb0, 0x4f, 0xc3, 0xe0, 0x49, 0x94, 0xf8, 0x97, 0x68, 0x18, 0x67,
-	0x9e, 0x14, 0x3a, 0xd4, 0x53, 0x1b, 0x63, 0x11, 0x19, 0xa5, 0xd4, 0x67, 0xaf, 0x29, 0xc4, 0x2a,
-	0x9e, 0xcf, 0x26, 0x54, 0x53, 0x6a, 0x28, 0xcf, 0x1b, 0xcf, 0x67, 0xa7, 0x61, 0x20, 0xdd, 0x21,
-	0xb4, 0x4f, 0xc3, 0xe0, 0xc4, 0xf3, 0x2f, 0xd1, 0x1d, 0x9d, 0xe1, 0xd0, 0x13, 0x19, 0x7e, 0x23,
-	0xb4, 0x87, 0x76, 0x88, 0x33, 0x0a, 0xbf, 0x11, 0xec, 0x43, 0xb0, 0x88, 0x28, 0xf2, 0x35, 0x32,
-	0xf3, 0x62, 0x39, 0x5c, 0xcb, 0xdc, 0xbf, 0x32, 0x96, 0xdb, 0xa2, 0x52, 0xc2, 0x3d, 0x68, 0xa6,
-	0x9e, 0x7f, 0xa9, 0x7d, 0x50, 0x47, 0xf7, 0xc1, 0xf9, 0x38, 0x09, 0xd8, 0x27, 0x60, 0x6b, 0xdb,
-	0x29, 0x06, 0xee, 0x54, 0x8c, 0x8c, 0x2f, 0x85, 0xf5, 0x53, 0x6d, 0xd4, 0x4f, 0x15, 0x77, 0x2e,
-	0xd3, 0x28, 0xa4, 0x57, 0x61, 0x03, 0x7d, 0x95, 0xa2, 0xdc, 0x1f, 0x03, 0x94, 0x75, 0x9a, 0x1b,
-	0x1e, 0x15, 0x6f, 0x41, 0xcb, 0x8b, 0x42, 0x0d, 0x98, 0xc3, 0x15, 0xe1, 0x0e, 0xa1, 0x53, 0xa9,
-	0xee, 0x20, 0x7c, 0x5e, 0x14, 0x4d, 0x2e, 0xc5, 0xb5, 0xa4, 0xbe, 0x36, 0x6f, 0x7b, 0x51, 0xf4,
-	0x5c, 0x5c, 0x4b, 0x8c, 0x0b, 0xaa, 0x30, 0x64, 0xae, 0x54, 0x1a, 0xa8, 0x2b, 0x57, 0x42, 0xf7,
-	0x47, 0x60, 0xa9, 0xf2, 0x43, 0xc5, 0xd2, 0x8d, 0x57, 0x46, 0xd3, 0x2f, 0xf4, 0x9a, 0xa9, 0x58,
-	0xc1, 0x1e, 0xe8, 0x02, 0x94, 0x54, 0xe5, 0x2e, 0xa3, 0xcc, 0x30, 0x95, 0x92, 0xae, 0x3d, 0x91,
-	0xb2, 0xbb, 0x0f, 0xf6, 0x6b, 0x4b, 0x7a, 0x1a, 0x00, 0xb3, 0x04, 0xe0, 0x86, 0x22, 0x9f, 0xfb,
-	0x4b, 0x80, 0xb2, 0x50, 0xa5, 0x2f, 0x9e, 0x1a, 0x05, 0x2f, 0xde, 0x7d, 0x7c, 0x0d, 0x86, 0x51,
-	0x90, 0x89, 0xb8, 0xb6, 0xeb, 0xb2, 0xb4, 0xb5, 0x94, 0xb3, 0x4d, 0x68, 0x52, 0xfd, 0xad, 0x51,
-	0x3a, 0xc6, 0x65, 0xf1, 0x8d, 0x24, 0xee, 0x02, 0xd6, 0x54, 0x90, 0xe6, 0xe2, 0x4f, 0xe6, 0x42,
-	0xbe, 0x36, 0xf5, 0xbb, 0x0b, 0xb0, 0x74, 0xe3, 0x45, 0x25, 0xb1, 0xc2, 0x41, 0x23, 0x38, 0x0f,
-	0x45, 0x14, 0x14, 0xbb, 0xd1, 0x14, 0x1e, 0xb2, 0x0a, 0xde, 0x4d, 0x55, 0x6e, 0x21, 0xc2, 0xfd,
-	0x03, 0xe8, 0x16, 0x33, 0x53, 0x3d, 0xe3, 0xc1, 0x32, 0x81, 0x50, 0x18, 0xab, 0x67, 0x94, 0x52,
-	0x19, 0x26, 0x81, 0x78, 0x62, 0xf6, 0x8d, 0x22, 0x87, 0x70, 0xff, 0xa3, 0x51, 0xf4, 0xd6, 0xcf,
-	0xfb, 0x5a, 0x5a, 0x6a, 0xac, 0xa6, 0xa5, 0xf5, 0x14, 0xcf, 0xfc, 0x8d, 0x52, 0xbc, 0x9f, 0x80,
-	0x13, 0x50, 0x9e, 0x13, 0x5e, 0x15, 0x2e, 0x7b, 0x63, 0x35, 0xa7, 0xd1, 0x99, 0x50, 0x78, 0x25,
-	0x78, 0xa9, 0x8c, 0x6b, 0xc9, 0x93, 0x4b, 0x11, 0x87, 0xdf, 0x50, 0xfd, 0x02, 0xf7, 0x5c, 0x32,
-	0xca, 0x62, 0x90, 0x4a, 0x77, 0x74, 0x31, 0xa8, 0xa8, 0x6b, 0x59, 0x65, 0x5d, 0x0b, 0xf1, 0x9c,
-	0xa7, 0x52, 0x64, 0x79, 0x91, 0x20, 0x2b, 0x6a, 0x99, 0x4b, 0x3a, 0x5a, 0x17, 0x73, 0xc9, 0xf7,
-	0xa1, 0x1b, 0x27, 0xf1, 0x24, 0x9e, 0x47, 0x11, 0xa6, 0xf0, 0xba, 0x84, 0xd9, 0x89, 0x93, 0x78,
-	0xa8, 0x59, 0xec, 0x3e, 0xbc, 0x51, 0x55, 0x51, 0xf6, 0xdc, 0x51, 0x15, 0x90, 0x8a, 0x1e, 0x59,
-	0xfd, 0x16, 0xf4, 0x92, 0xb3, 0x5f, 0x0a, 0x3f, 0x27, 0xc4, 0x26, 0x64, 0xc8, 0x5d, 0x15, 0xb8,
-	0x15, 0x1f, 0x21, 0x1a, 0x7a, 0x33, 0xe1, 0x7e, 0x01, 0xce, 0x12, 0x84, 0x4a, 0xa2, 0xe4, 0x40,
-	0xeb, 0x60, 0xb8, 0x3f, 0xf8, 0xa3, 0x9e, 0x81, 0x5e, 0x9e, 0x0f, 0x5e, 0x0c, 0xf8, 0x68, 0xd0,
-	0x33, 0xd1, 0x03, 0xef, 0x0f, 0x0e, 0x07, 0xe3, 0x41, 0xaf, 0xf1, 0x65, 0xd3, 0x6e, 0xf7, 0x6c,
-	0x6e, 0x8b, 0x45, 0x1a, 0x85, 0x7e, 0x98, 0xbb, 0x23, 0x80, 0x32, 0xa7, 0x43, 0x7f, 0x53, 0xce,
-	0xad, 0x4e, 0xd4, 0xce, 0xf5, 0xac, 0x98, 0x6d, 0x6a, 0x53, 0x33, 0x5f, 0x95, 0x6d, 0x2a, 0xb9,
-	0x7b, 0x0a, 0xf6, 0x91, 0x97, 0xbe, 0xf4, 0x3a, 0xeb, 0x2e, 0xdf, 0xe0, 0x73, 0x5d, 0x91, 0xd2,
-	0xe1, 0xfb, 0x23, 0x68, 0x6b, 0x97, 0xa7, 0x6f, 0x4d, 0xcd, 0x1d, 0x16, 0x32, 0xf7, 0xcf, 0x0d,
-	0x78, 0xeb, 0x28, 0xb9, 0x12, 0xcb, 0x0c, 0xe6, 0xc4, 0xbb, 0x8e, 0x12, 0x2f, 0xf8, 0x0e, 0x43,
-	0xfc, 0x21, 0x80, 0x4c, 0xe6, 0x99, 0x2f, 0x26, 0xd3, 0x65, 0x21, 0xcc, 0x51, 0x9c, 0x67, 0xba,
-	0xe6, 0x2e, 0x64, 0x4e, 0x42, 0x1d, 0x28, 0x90, 0x46, 0xd1, 0xdb, 0x60, 0xe5, 0x8b, 0xb8, 0xac,
-	0xbb, 0xb5, 0x72, 0x7c, 0x1a, 0xbb, 0x7b, 0xe0, 0x8c, 0x17, 0xf4, 0x60, 0x9c, 0xcb, 0x5a, 0x4c,
-	0x36, 0x5e, 0x13, 0x93, 0xcd, 0x95, 0x98, 0xfc, 0xdf, 0x06, 0x74, 0x2a, 0xa9, 0x15, 0x7b, 0x1f,
-	0x9a, 0xf9, 0x22, 0xae, 0x17, 0xac, 0x8b, 0x49, 0x38, 0x89, 0xd0, 0xde, 0xf0, 0x35, 0xe9, 0x49,
-	0x19, 0x4e, 0x63, 0x11, 0xe8, 0x21, 0xf1, 0x85, 0xb9, 0xab, 0x59, 0xec, 0x10, 0x6e, 0x2b, 0x4f,
-	0x52, 0x14, 0xab, 0x8a, 0x37, 0xc4, 0x07, 0x2b, 0xa9, 0x9c, 0x7a, 0x54, 0xef, 0x15, 0x5a, 0xaa,
-	0x6c, 0xb0, 0x3e, 0xad, 0x31, 0x37, 0x76, 0xe1, 0xcd, 0x1b, 0xd4, 0xbe, 0x57, 0x7d, 0xe4, 0x1e,
-	0xac, 0x8d, 0x17, 0xf1, 0x38, 0x9c, 0x09, 0x99, 0x7b, 0xb3, 0x94, 0x72, 0x1a, 0x1d, 0x09, 0x9a,
-	0xdc, 0xcc, 0xa5, 0xfb, 0x31, 0x74, 0x4f, 0x84, 0xc8, 0xb8, 0x90, 0x69, 0x12, 0xab, 0x78, 0x2e,
-	0x69, 0xd3, 0x3a, 0xec, 0x68, 0xca, 0xfd, 0x63, 0x70, 0x30, 0x91, 0x7f, 0xe2, 0xe5, 0xfe, 0xc5,
-	0xf7, 0x49, 0xf4, 0x3f, 0x86, 0x76, 0xaa, 0xcc, 0x44, 0xe7, 0xde, 0x5d, 0xf2, 0x71, 0xda, 0x74,
-	0x78, 0x21, 0x74, 0x39, 0x34, 0x86, 0xf3, 0x59, 0xf5, 0x2b, 0x53, 0x53, 0x7d, 0x65, 0xaa, 0x3d,
-	0x8d, 0xcd, 0xfa, 0xd3, 0x18, 0x2d, 0xef, 0x3c, 0xc9, 0xfe, 0xd4, 0xcb, 0x02, 0x11, 0xe8, 0xf7,
-	0x77, 0xc9, 0x70, 0xbf, 0x86, 0x4e, 0x71, 0x32, 0x07, 0x01, 0x7d, 0x48, 0x22, 0xd3, 0x38, 0x08,
-	0x6a, 0x96, 0xa2, 0xde, 0xaf, 0x22, 0x0e, 0x0e, 0x8a, 0x23, 0x55, 0x44, 0x7d, 0x66, 0x5d, 0x9f,
-	0x59, 0x3e, 0xca, 0x9f, 0x42, 0xb7, 0xc8, 0xb7, 0x8f, 0x44, 0xee, 0x91, 0xb1, 0x45, 0xa1, 0x88,
-	0x2b, 0x86, 0x68, 0x2b, 0xc6, 0x58, 0xbe, 0xa6, 0x12, 0xec, 0x6e, 0x83, 0xa5, 0x2d, 0x99, 0x41,
-	0xd3, 0x4f, 0x02, 0x75, 0x81, 0x5a, 0x9c, 0xda, 0x08, 0xc7, 0x4c, 0x4e, 0x8b, 0xe0, 0x39, 0x93,
-	0x53, 0xf7, 0x9f, 0x4d, 0x58, 0x7b, 0xe2, 0xf9, 0x97, 0xf3, 0xb4, 0x88, 0x5e, 0x95, 0x47, 0x93,
-	0x51, 0x7b, 0x34, 0x55, 0x1f, 0x48, 0x66, 0xed, 0x81, 0x54, 0x5b, 0x50, 0xa3, 0x1e, 0xf1, 0xde,
-	0x81, 0xf6, 0x3c, 0x0e, 0x17, 0xc5, 0xad, 0x73, 0xb8, 0x85, 0xe4, 0x58, 0xb2, 0x4d, 0xe8, 0xe0,
-	0xc5, 0x0c, 0x63, 0x7a, 0x2a, 0x11, 0x20, 0x0e, 0xaf, 0xb2, 0xf0, 0xa6, 0x7b, 0xbe, 0x2f, 0xa4,
-	0xc4, 0xbc, 0x45, 0xa7, 0xdb, 0x8e, 0xe2, 0x3c, 0x17, 0xd7, 0xe4, 0x08, 0x84, 0x9f, 0x89, 0x7c,
-	0x52, 0x3e, 0x7b, 0x1c, 0xc5, 0x41, 0xf1, 0x07, 0xb0, 0x26, 0x85, 0x94, 0x61, 0x12, 0x4f, 0x28,
-	0x72, 0xe8, 0xd7, 0x69, 0x57, 0x33, 0xc7, 0xc8, 0xc3, 0x03, 0xf7, 0xe2, 0x24, 0xbe, 0x9e, 0x25,
-	0x73, 0xa9, 0x83, 0x41, 0xc9, 0x58, 0x89, 0xd6, 0xb0, 0x1a, 0xad, 0xdd, 0x1c, 0xd6, 0x06, 0x8b,
-	0x94, 0xbe, 0x27, 0x7c, 0x67, 0xe4, 0xaf, 0xc0, 0x6a, 0xd6, 0x60, 0xad, 0x00, 0xd4, 0xa0, 0xda,
-	0x4e, 0x01, 0x10, 0xe6, 0x02, 0x49, 0x36, 0xf3, 0xf2, 0x02, 0x38, 0x45, 0xb9, 0x7f, 0x6d, 0x82,
-	0xa3, 0x8e, 0x0c, 0xb7, 0xf9, 0x29, 0x34, 0x29, 0x22, 0x1b, 0x14, 0x5e, 0xdf, 0xc6, 0x8b, 0xb3,
-	0x14, 0x6e, 0x3f, 0x17, 0xd7, 0x14, 0x93, 0x49, 0xe5, 0xc6, 0x7a, 0x8e, 0xf6, 0xde, 0x2a, 0x19,
-	0x25, 0xef, 0xfd, 0x1e, 0x38, 0xca, 0x03, 0x22, 0x5f, 0xd7, 0xca, 0x89, 0x71, 0x1a, 0xd2, 0x87,
-	0x86, 0x5c, 0x64, 0x33, 0x7d, 0x5a, 0xd4, 0x2e, 0xa3, 0xb1, 0xa5, 0xbe, 0x7e, 0x10, 0xe1, 0x5e,
-	0x40, 0x5b, 0xcf, 0x8e, 0xd1, 0xeb, 0x74, 0xf8, 0x7c, 0x78, 0xfc, 0xd5, 0xb0, 0x77, 0x6b, 0xf9,
-	0xea, 0x37, 0xca, 0xf8, 0x66, 0x56, 0xe3, 0x5b, 0x03, 0xf9, 0x7b, 0xc7, 0xa7, 0xc3, 0x71, 0xaf,
-	0xc9, 0xd6, 0xc0, 0xa1, 0xe6, 0x84, 0x0f, 0x5e, 0xf4, 0x5a, 0xf4, 0x0e, 0xd9, 0xfb, 0xd9, 0xe0,
-	0x68, 0xb7, 0x67, 0x2d, 0x6b, 0x06, 0x6d, 0x8c, 0x23, 0x6f, 0xa8, 0x2d, 0x57, 0xb3, 0xf6, 0xea,
-	0x07, 0xe8, 0xa6, 0xfa, 0x00, 0xfd, 0xbb, 0x4d, 0xd4, 0x77, 0xfe, 0xc5, 0x80, 0x26, 0xfa, 0x2c,
-	0xf6, 0x00, 0x9c, 0x9f, 0x09, 0x2f, 0xcb, 0xcf, 0x84, 0x97, 0xb3, 0x9a, 0x7f, 0xda, 0xa8, 0x51,
-	0xee, 0xad, 0xc7, 0x06, 0xdb, 0x56, 0x9f, 0x96, 0x8a, 0x2f, 0x66, 0x6b, 0x85, 0xe7, 0x23, 0xcf,
-	0xb8, 0xaa, 0xbf, 0x45, 0xfa, 0x5f, 0x26, 0x61, 0xbc, 0xa7, 0xbe, 0xb7, 0xb0, 0x55, 0x4f, 0xb9,
-	0xda, 0x83, 0x3d, 0x04, 0xeb, 0x40, 0xa2, 0x4b, 0x7e, 0x59, 0x95, 0x22, 0x7e, 0xd5, 0x5b, 0xbb,
-	0xb7, 0x76, 0xfe, 0xa9, 0x01, 0xcd, 0xaf, 0x45, 0x96, 0xb0, 0x1f, 0x41, 0x5b, 0x57, 0x53, 0x59,
-	0xa5, 0x6a, 0xba, 0x41, 0x29, 0xdf, 0x4a, 0x99, 0x95, 0x66, 0xe9, 0xa9, 0xa4, 0xa1, 0x2c, 0x62,
-	0xb0, 0xb2, 0xd8, 0xfb, 0xd2, 0xa2, 0xbe, 0x80, 0xde, 0x28, 0xcf, 0x84, 0x37, 0xab, 0xa8, 0xd7,
-	0x81, 0xba, 0xa9, 0x22, 0x42, 0x78, 0x3d, 0x00, 0x4b, 0xc5, 0xbd, 0x95, 0x0e, 0xab, 0xc5, 0x0d,
-	0x52, 0xfe, 0x04, 0x3a, 0xa3, 0x8b, 0x64, 0x1e, 0x05, 0x23, 0x91, 0x5d, 0x09, 0x56, 0xf9, 0xa2,
-	0xb1, 0x51, 0x69, 0xbb, 0xb7, 0xd8, 0x16, 0x80, 0x72, 0xed, 0xf8, 0xa2, 0x64, 0x6d, 0x94, 0x0d,
-	0xe7, 0x33, 0x35, 0x68, 0xc5, 0xe7, 0x2b, 0xcd, 0x4a, 0xf8, 0x7b, 0x9d, 0xe6, 0xe7, 0xb0, 0xb6,
-	0x47, 0x36, 0x73, 0x9c, 0xed, 0x9e, 0x25, 0x59, 0xce, 0x56, 0xbf, 0x6a, 0x6c, 0xac, 0x32, 0xdc,
-	0x5b, 0xec, 0x31, 0xd8, 0xe3, 0xec, 0x5a, 0xe9, 0xbf, 0xa1, 0xb3, 0x86, 0x72, 0xbe, 0x1b, 0x76,
-	0xb9, 0xf3, 0x0f, 0x0d, 0xb0, 0xbe, 0x4a, 0xb2, 0x4b, 0x91, 0xb1, 0xfb, 0x60, 0x51, 0x15, 0x4a,
-	0x9b, 0xd1, 0xb2, 0x22, 0x75, 0xd3, 0x44, 0x1f, 0x82, 0x43, 0xa0, 0x8c, 0x3d, 0x79, 0xa9, 0x8e,
-	0x8a, 0xfe, 0xfa, 0xa0, 0x70, 0x51, 0xef, 0x09, 0x3a, 0xd7, 0x75, 0x75, 0x50, 0xcb, 0xa2, 0x5c,
-	0xad, 0x34, 0xb4, 0xd1, 0x56, 0x75, 0x9e, 0x11, 0x9a, 0xe6, 0x63, 0x03, 0x9d, 0xd1, 0x48, 0xed,
-	0x14, 0x95, 0xca, 0x0f, 0xc1, 0x1b, 0xeb, 0x05, 0x63, 0x39, 0xf2, 0x23, 0xb0, 0x54, 0xb2, 0xa9,
-	0xb6, 0x59, 0x7b, 0x41, 0x6d, 0xf4, 0xaa, 0x2c, 0xdd, 0xe1, 0x53, 0xb0, 0xd4, 0x2d, 0x57, 0x1d,
-	0x6a, 0x41, 0x4b, 0xad, 0x5a, 0x05, 0x3e, 0xa5, 0xaa, 0xfc, 0xb2, 0x52, 0xad, 0xf9, 0xe8, 0x15,
-	0xd5, 0x87, 0xd0, 0xe3, 0xc2, 0x17, 0x61, 0x25, 0x0d, 0x65, 0xc5, 0xa6, 0x6e, 0xb8, 0x7d, 0x5f,
-	0xc0, 0x5a, 0x2d, 0x65, 0x65, 0x7d, 0x02, 0xfa, 0x86, 0x2c, 0x76, 0xb5, 0xf3, 0x93, 0xde, 0xbf,
-	0x7d, 0x7b, 0xd7, 0xf8, 0xf7, 0x6f, 0xef, 0x1a, 0xff, 0xf9, 0xed, 0x5d, 0xe3, 0x57, 0xff, 0x75,
-	0xf7, 0xd6, 0x99, 0x45, 0x7f, 0x99, 0xf9, 0xfc, 0xff, 0x03, 0x00, 0x00, 0xff, 0xff, 0x9a, 0x86,
-	0x2f, 0xa5, 0x76, 0x23, 0x00, 0x00,
+	// 4272 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xb5, 0x3a, 0xcb, 0x72, 0x23, 0xd7,
+	0x75, 0xc2, 0x1b, 0x7d, 0xf0, 0x20, 0xa6, 0x47, 0xb2, 0x11, 0x3a, 0x99, 0x91, 0x5b, 0xd2, 0x88,
+	0x92, 0x2d, 0x8e, 0x4c, 0x39, 0x15, 0xcb, 0x89, 0xab, 0x0c, 0x92, 0x98, 0x31, 0x25, 0xbe, 0xdc,
+	0x00, 0x47, 0xb1, 0x16, 0x41, 0x35, 0xd1, 0x97, 0x64, 0x9b, 0x40, 0x37, 0xdc, 0xdd, 0x98, 0x90,
+	0xaa, 0xca, 0x22, 0x0b, 0xef, 0x92, 0x4a, 0xaa, 0x92, 0x45, 0x16, 0x29, 0x2f, 0x52, 0xf1, 0x47,
+	0x38, 0x59, 0xb8, 0x9c, 0x55, 0x96, 0x59, 0xe4, 0x03, 0x5c, 0x4e, 0x96, 0xa9, 0x7c, 0x43, 0xce,
+	0xe3, 0xf6, 0x0b, 0x03, 0xce, 0x58, 0xa9, 0xf2, 0x82, 0xc5, 0x7b, 0x1e, 0xf7, 0x75, 0xee, 0x79,
+	0x37, 0xa0, 0xb9, 0x38, 0xdf, 0x5e, 0x84, 0x41, 0x1c, 0x98, 0xe5, 0xc5, 0xf9, 0xa6, 0xe1, 0x2c,
+	0x3c, 0x01, 0x37, 0xdf, 0xbd, 0xf4, 0xe2, 0xab, 0xe5, 0xf9, 0xf6, 0x34, 0x98, 0x3f, 0x76, 0x2f,
+	0x43, 0x67, 0x71, 0xf5, 0x81, 0x17, 0x3c, 0x3e, 0x77, 0xdc, 0x4b, 0x15, 0x3e, 0x5e, 0x9c, 0x3f,
+	0x4e, 0xe6, 0x59, 0x9b, 0x50, 0x3d, 0xf4, 0xa2, 0xd8, 0x34, 0xa1, 0xba, 0xf4, 0xdc, 0xa8, 0x5f,
+	0x7a, 0xb3, 0xb2, 0x55, 0xb7, 0x79, 0x6c, 0x1d, 0x81, 0x31, 0x76, 0xa2, 0xeb, 0x67, 0xce, 0x6c,
+	0xa9, 0xcc, 0x1e, 0x54, 0x9e, 0x3b, 0x33, 0xa4, 0x97, 0xb6, 0xda, 0x36, 0x0d, 0xcd, 0x6d, 0x68,
+	0xe2, 0xbf, 0x49, 0x7c, 0xbb, 0x50, 0xfd, 0x32, 0xa2, 0xbb, 0x3b, 0xf7, 0xb7, 0x71, 0xdd, 0xd3,
+	0x20, 0x8a, 0x3d, 0xff, 0x72, 0x1b, 0xa7, 0x8d, 0x91, 0x64, 0x37, 0x9e, 0xcb, 0xc0, 0x3a, 0x81,
+	0xd6, 0x28, 0x9c, 0x3e, 0x59, 0xfa, 0xd3, 0xd8, 0x0b, 0x7c, 0xda, 0xd1, 0x77, 0xe6, 0x8a, 0x57,
+	0x34, 0x6c, 0x1e, 0x13, 0xce, 0x09, 0x2f, 0xa3, 0x7e, 0x05, 0x4f, 0x81, 0x38, 0x1a, 0x9b, 0x7d,
+	0x68, 0x78, 0xd1, 0x5e, 0xb0, 0xf4, 0xe3, 0x7e, 0x15, 0x59, 0x9b, 0x76, 0x02, 0x5a, 0xff, 0x5c,
+	0x81, 0xda, 0x0f, 0x97, 0x2a, 0xbc, 0xe5, 0x79, 0x71, 0x1c, 0x26, 0x6b, 0xd1, 0xd8, 0x7c, 0x1d,
+	0x6a, 0x33, 0xc7, 0xc7, 0xc5, 0xca, 0xbc, 0x98, 0x00, 0xe6, 0xd7, 0xc0, 0x70, 0x2e, 0x62, 0x15,
+	0x4e, 0xf0, 0x86, 0xb8, 0x4d, 0x09, 0x2f, 0xdb, 0x64, 0xc4, 0x99, 0xe7, 0x9a, 0xbf, 0x07, 0x4d,
+	0x37, 0x98, 0x4c, 0xf3, 0x7b, 0xb9, 0x01, 0xef, 0x65, 0xbe, 0x05, 0x4d, 0x9c, 0x31, 0x99, 0xa1,
+	0xac, 0xfa, 0x35, 0x24, 0xb5, 0x76, 0x9a, 0x74, 0x59, 0x92, 0x9d, 0xdd, 0x40, 0x0a, 0x0b, 0xf1,
+	0x7d, 0x68, 0x46, 0xe1, 0x74, 0x72, 0x81, 0x57, 0xec, 0xd7, 0x99, 0x69, 0x83, 0x98, 0x72, 0xb7,
+	0xb6, 0x1b, 0x91, 0x00, 0x74, 0xad, 0x50, 0x3d, 0x57, 0x61, 0xa4, 0xfa, 0x0d, 0xd9, 0x4a, 0x83,
+	0xe6, 0x87, 0xd0, 0xba, 0x70, 0xa6, 0x2a, 0x9e, 0x2c, 0x9c, 0xd0, 0x99, 0xf7, 0x9b, 0xd9, 0x42,
+	0x4f, 0x08, 0x7d, 0x4a, 0xd8, 0xc8, 0x86, 0x8b, 0x14, 0x30, 0x3f, 0x82, 0x0e, 0x43, 0xd1, 0xe4,
+	0xc2, 0x9b, 0xe1, 0x5d, 0xfa, 0x06, 0xcf, 0xe9, 0xf2, 0x1c, 0xc6, 0x8c, 0x43, 0xa5, 0xec, 0xb6,
+	0x30, 0x09, 0xc6, 0xfc, 0x03, 0x00, 0x75, 0xb3, 0x70, 0x7c, 0x77, 0xe2, 0xcc, 0x66, 0x7d, 0xe0,
+	0x33, 0x18, 0x82, 0x19, 0xcc, 0x66, 0xe6, 0x57, 0xe9, 0x7c, 0x8e, 0x3b, 0x89, 0xa3, 0x7e, 0x07,
+	0x69, 0x55, 0xbb, 0x4e, 0xe0, 0x38, 0x22, 0xb9, 0x4e, 0x9d, 0xe9, 0x95, 0xea, 0x77, 0x11, 0x5d,
+	0xb3, 0x05, 0x20, 0xd1, 0xe1, 0x3b, 0xa3, 0x84, 0x9c, 0xb8, 0xbf, 0xc1, 0x3a, 0xd2, 0x60, 0x78,
+	0x10, 0x5b, 0x3b, 0x60, 0xb0, 0x0a, 0xb1, 0x88, 0xde, 0x81, 0xfa, 0x73, 0x02, 0x44, 0xd3, 0x5a,
+	0x3b, 0x1d, 0x3a, 0x63, 0xaa, 0x65, 0xb6, 0x26, 0x5a, 0x0f, 0xa0, 0x79, 0x88, 0xef, 0x95, 0xa8,
+	0x26, 0xbd, 0x1d, 0x4f, 0xc0, 0xc7, 0xa5, 0xb1, 0xf5, 0x9f, 0x65, 0xa8, 0xdb, 0x2a, 0x5a, 0xce,
+	0x62, 0xf3, 0x5d, 0x00, 0x7a, 0x99, 0xb9, 0x13, 0x87, 0xde, 0x8d, 0x5e, 0x35, 0x7b, 0x1b, 0x03,
+	0x69, 0x47, 0x4c, 0x42, 0xb9, 0xb6, 0x79, 0xf5, 0x84, 0xb5, 0x9c, 0x1d, 0x20, 0x3d, 0x9f, 0xdd,
+	0x62, 0x16, 0x3d, 0xe3, 0x2b, 0x50, 0x67, 0x65, 0x10, 0x85, 0xec, 0xd8, 0x1a, 0xc2, 0x4b, 0x74,
+	0x3d, 0x3f, 0xa6, 0xc7, 0x9a, 0xc6, 0x13, 0x57, 0x45, 0x89, 0xb6, 0x74, 0x52, 0xec, 0x3e, 0x22,
+	0xcd, 0x6f, 0x81, 0x48, 0x3c, 0xd9, 0xb0, 0xc6, 0x1b, 0x76, 0xd3, 0x97, 0x8c, 0x64, 0x47, 0xe6,
+	0xd1, 0x3b, 0x7e, 0x00, 0x2d, 0xba, 0x5f, 0x32, 0xa3, 0xce, 0x33, 0xda, 0x7c, 0x1b, 0x2d, 0x0e,
+	0x1b, 0x88, 0x41, 0xb3, 0x93, 0x68, 0x48, 0x23, 0x45, 0x83, 0x78, 0x4c, 0x1a, 0x7e, 0xad, 0x6e,
+	0xa3, 0x09, 0x3d, 0x17, 0x2b, 0x4f, 0xd5, 0x6e, 0x12, 0xc2, 0x46, 0x98, 0x1e, 0xfd, 0xfc, 0x36,
+	0x56, 0x9a, 0x6a, 0x30, 0xd5, 0x60, 0x0c, 0x91, 0xad, 0x5f, 0x96, 0xa0, 0x76, 0x12, 0xba, 0xa8,
+	0x1d, 0xeb, 0x2c, 0x0a, 0x71, 0x78, 0xd9, 0x29, 0x1b, 0x3b, 0xee, 0x46, 0xe3, 0xcc, 0xca, 0x2a,
+	0x79, 0x2b, 0xfb, 0x7d, 0x30, 0xa6, 0xc1, 0x6c, 0xe6, 0x90, 0xca, 0xb3, 0x6c, 0x0c, 0x3b, 0x43,
+	0x90, 0x58, 0x43, 0xd4, 0xb2, 0x60, 0xce, 0x96, 0xd4, 0xb4, 0x35, 0x44, 0xeb, 0x47, 0x4a, 0xb9,
+	0x6c, 0x3a, 0x15, 0x9b, 0xc7, 0xac, 0x6d, 0x6c, 0x8f, 0x72, 0x45, 0x01, 0xf2, 0xc6, 0xd3, 0x2c,
+	0x18, 0x8f, 0xf5, 0xb3, 0x12, 0x7a, 0x99, 0x20, 0x8c, 0x8f, 0x54, 0x14, 0x39, 0x97, 0xca, 0x7c,
+	0x08, 0xb5, 0x80, 0x2e, 0xa4, 0x15, 0xc3, 0x20, 0x51, 0xf2, 0x0d, 0x6d, 0xc1, 0xaf, 0xa8, 0x4f,
+	0xf9, 0x6e, 0xf5, 0x49, 0x4f, 0x52, 0xd1, 0x7a, 0xcf, 0x27, 0xc1, 0xbb, 0x04, 0x17, 0x17, 0x91,
+	0x12, 0x15, 0xa8, 0xd9, 0x1a, 0xba, 0xd3, 0x7c, 0xac, 0x3f, 0x04, 0xa0, 0xf3, 0x7d, 0x49, 0xe5,
+	0xb5, 0xae, 0xa0, 0x65, 0xa3, 0x9f, 0xda, 0x0b, 0x50, 0xc3, 0x6e, 0x62, 0xb3, 0x0b, 0x65, 0xf4,
+	0x5f, 0x25, 0xf6, 0x5f, 0x38, 0xa2, 0xc3, 0x5d, 0x86, 0xc1, 0x72, 0xc1, 0x6f, 0xd3, 0xb1, 0x05,
+	0xe0, 0x47, 0x74, 0xdd, 0x90, 0x4f, 0x4c, 0x8f, 0x88, 0x63, 0x14, 0x48, 0x2b, 0xf2, 0x9d, 0x45,
+	0x74, 0x15, 0xc4, 0x74, 0xb8, 0x2a, 0x1f, 0x0e, 0x12, 0x14, 0x1e, 0xf0, 0x7f, 0x4b, 0x50, 0x3f,
+	0x52, 0xf3, 0x73, 0x94, 0xcd, 0xea, 0x2e, 0x68, 0xe4, 0xbc, 0xf0, 0x04, 0xb1, 0xb2, 0x51, 0x83,
+	0xe1, 0x03, 0x77, 0xed, 0x56, 0x28, 0x9b, 0x19, 0x5e, 0x1a, 0x85, 0x2f, 0xe6, 0xa1, 0x21, 0x92,
+	0x8d, 0x33, 0x47, 0xbb, 0x41, 0x0d, 0xd4, 0x0a, 0xe0, 0xcc, 0xf7, 0x49, 0x3b, 0x1f, 0x92, 0xf6,
+	0x47, 0xf1, 0x64, 0xb9, 0x70, 0x9d, 0x58, 0xb1, 0x1e, 0x54, 0x49, 0xdf, 0xa3, 0xf8, 0x8c, 0x31,
+	0xe8, 0x60, 0xef, 0x4d, 0x67, 0xcb, 0x88, 0xfc, 0xb7, 0xe7, 0x5f, 0x04, 0x93, 0xc0, 0x9f, 0xdd,
+	0xb2, 0x7c, 0x9b, 0xf6, 0x86, 0x26, 0x1c, 0x20, 0xfe, 0x04, 0xd1, 0x28, 0xda, 0x8d, 0x0b, 0xe5,
+	0xc4, 0xcb, 0x50, 0x4d, 0x48, 0x35, 0x48, 0x13, 0xbb, 0x7c, 0xe6, 0xae, 0x46, 0x3f, 0x13, 0x2c,
+	0xf9, 0x92, 0xda, 0x53, 0x96, 0xd7, 0x87, 0xd0, 0x98, 0xf3, 0xcd, 0x13, 0xef, 0xf4, 0x15, 0x7a,
+	0x0a, 0xa6, 0x6d, 0x8b, 0x48, 0xa2, 0xa1, 0x1f, 0x87, 0xb7, 0x76, 0xc2, 0x46, 0x33, 0x62, 0xe7,
+	0x7c, 0x86, 0xb6, 0xac, 0x55, 0x27, 0x37, 0x63, 0x2c, 0x04, 0x3d, 0x43, 0xb3, 0xad, 0xca, 0xbf,
+	0xb2, 0x2a, 0x7f, 0x73, 0x13, 0x9a, 0xe8, 0x50, 0xa7, 0xd7, 0xd1, 0x72, 0xae, 0x5f, 0x27, 0x85,
+	0x89, 0xa6, 0x6e, 0xf0, 0xa2, 0xae, 0x4a, 0x44, 0x97, 0xc2, 0x9b, 0x4f, 0xa0, 0x9d, 0x3f, 0x23,
+	0x05, 0x6c, 0x34, 0x7b, 0x7e, 0xbd, 0xaa, 0x4d, 0x43, 0xf3, 0x4d, 0xa8, 0xb1, 0x77, 0xe3, 0xb7,
+	0x6b, 0xed, 0x00, 0x1d, 0x55, 0xa6, 0xd8, 0x42, 0xf8, 0x6e, 0xf9, 0x3b, 0x25, 0x5a, 0x27, 0x7f,
+	0xf2, 0xfc, 0x3a, 0xc6, 0xdd, 0xeb, 0xc8, 0x94, 0xdc, 0x3a, 0xd6, 0xaf, 0x6a, 0xd0, 0xfe, 0x5c,
+	0x85, 0xc1, 0x69, 0x18, 0x2c, 0x82, 0x08, 0xf3, 0x85, 0x41, 0xf1, 0xe6, 0x22, 0xe1, 0x37, 0x69,
+	0x72, 0x9e, 0x6d, 0x7b, 0x94, 0x8a, 0x42, 0x24, 0x97, 0x97, 0x8d, 0x05, 0x75, 0x91, 0xfc, 0x9a,
+	0x2b, 0x68, 0x0a, 0xf1, 0x88, 0xac, 0x59, 0xb6, 0xc5, 0xe3, 0x69, 0x8a, 0xf9, 0x00, 0x60, 0xee,
+	0xdc, 0x1c, 0x2a, 0x27, 0x52, 0x07, 0x6e, 0x62, 0x03, 0x19, 0x86, 0xe4, 0x8c, 0xd0, 0xf8, 0xc6,
+	0x1f, 0x47, 0x2c, 0x67, 0x7c, 0x83, 0x04, 0x26, 0xdf, 0x86, 0x63, 0x32, 0xc6, 0x03, 0x57, 0xab,
+	0x68, 0x86, 0x30, 0xbf, 0x0e, 0x95, 0xf8, 0xc6, 0x67, 0x6f, 0x45, 0x41, 0x9b, 0x32, 0x32, 0x9c,
+	0xa6, 0xcd, 0xd6, 0x26, 0x5a, 0x22, 0xd0, 0x66, 0x26, 0x50, 0xc4, 0x4c, 0x3d, 0x71, 0xc7, 0x88,
+	0xc1, 0x21, 0x1d, 0x20, 0x52, 0x3f, 0x59, 0x2a, 0x7f, 0xaa, 0x38, 0x34, 0x1b, 0x76, 0x0a, 0x9b,
+	0x6f, 0x43, 0x07, 0xf7, 0x1b, 0x69, 0x10, 0x0f, 0xd1, 0xe2, 0x43, 0x14, 0x91, 0x28, 0x86, 0xf6,
+	0xc2, 0xf3, 0x4f, 0x43, 0xe5, 0x7a, 0x53, 0x32, 0xa6, 0x36, 0xaf, 0x52, 0xc0, 0x91, 0x18, 0x10,
+	0x7e, 0x2a, 0x26, 0xcc, 0x76, 0xd4, 0xb1, 0x73, 0x18, 0xf3, 0x11, 0x74, 0xb5, 0x7a, 0x25, 0x3c,
+	0xda, 0x82, 0x8a, 0x58, 0xe2, 0xf3, 0xfc, 0x02, 0xdf, 0x86, 0xf0, 0x15, 0xb1, 0xc4, 0x57, 0xb4,
+	0xbd, 0x7e, 0x6f, 0x9d, 0x45, 0x9a, 0x5b, 0x68, 0xba, 0x98, 0xb0, 0x7c, 0xa1, 0xb2, 0xe3, 0xdf,
+	0xe3, 0xe3, 0xaf, 0xa2, 0xe9, 0x06, 0x82, 0x3a, 0x0a, 0x5c, 0xd5, 0x37, 0xe5, 0x06, 0x19, 0x66,
+	0xf3, 0x7b, 0xb0, 0xb1, 0xa2, 0x4f, 0x79, 0x7d, 0xee, 0x88, 0xf8, 0x5f, 0xcf, 0xeb, 0x73, 0x35,
+	0xaf, 0xc3, 0x7f, 0xd3, 0x80, 0x0d, 0x6d, 0x54, 0x57, 0xde, 0x62, 0x14, 0xd3, 0x96, 0x18, 0x7b,
+	0xd8, 0xf5, 0xab, 0x50, 0xdb, 0x56, 0x02, 0x9a, 0x7f, 0x04, 0x75, 0x76, 0x87, 0x89, 0x2f, 0x78,
+	0x98, 0x69, 0x67, 0x3a, 0x5d, 0x7c, 0x83, 0x56, 0x6d, 0xcd, 0x6e, 0x7e, 0x1b, 0x6a, 0x5f, 0xa0,
+	0x09, 0x48, 0x10, 0x6d, 0xed, 0x3c, 0x58, 0x37, 0x8f, 0x6c, 0x44, 0x4f, 0x13, 0xe6, 0xdf, 0xa1,
+	0x12, 0xbf, 0x4d, 0xc1, 0x6b, 0x1e, 0x3c, 0x47, 0x2f, 0xd3, 0xe0, 0x13, 0xe5, 0xed, 0x2c, 0x21,
+	0x25, 0x5a, 0xdb, 0xcc, 0xb4, 0xf6, 0xfb, 0x60, 0x24, 0x5a, 0x1a, 0xa1, 0x36, 0xd3, 0x4c, 0x6b,
+	0xdd, 0x5d, 0x12, 0x35, 0xd5, 0xf7, 0xc9, 0x26, 0x99, 0x47, 0xd0, 0x45, 0xfd, 0xf3, 0x15, 0x06,
+	0x4e, 0xed, 0x56, 0x81, 0x97, 0x79, 0xb4, 0x6e, 0x99, 0x53, 0xe6, 0x2c, 0xb8, 0xd9, 0xce, 0x22,
+	0x8f, 0x5b, 0x17, 0x03, 0x5a, 0x6b, 0x35, 0xee, 0x19, 0xdc, 0xbb, 0x08, 0x83, 0x2f, 0x94, 0x3f,
+	0x59, 0x24, 0xba, 0x15, 0xa1, 0xc9, 0xd0, 0xd6, 0xef, 0xad, 0xdb, 0xfa, 0x09, 0x33, 0xa7, 0x7a,
+	0xa8, 0x77, 0xef, 0x5d, 0xac, 0xa0, 0x37, 0xf7, 0xa1, 0x95, 0x7b, 0xf0, 0x35, 0xba, 0xf7, 0xb0,
+	0xe8, 0x4b, 0x8d, 0x34, 0x7c, 0xe4, 0x5d, 0xf2, 0x3e, 0x40, 0xf6, 0xfc, 0xff, 0x6f, 0xc7, 0xfe,
+	0x27, 0xd0, 0x2d, 0x0a, 0x7e, 0x8d, 0x6b, 0xbf, 0xd3, 0x14, 0x36, 0xbf, 0x0f, 0xe6, 0x8b, 0xf2,
+	0x7e, 0xd5, 0x0a, 0x9d, 0xfc, 0x0a, 0x7b, 0xf0, 0xc6, 0x5a, 0xb1, 0x7d, 0x99, 0x45, 0xac, 0xbf,
+	0x2c, 0xc1, 0x06, 0x7a, 0x53, 0x5f, 0x71, 0x39, 0x25, 0x16, 0x99, 0x45, 0x85, 0xd2, 0x9d, 0x51,
+	0xe1, 0x3d, 0xa8, 0x45, 0xc4, 0xac, 0x45, 0x74, 0x7f, 0xcd, 0xa3, 0xda, 0xc2, 0x41, 0x11, 0x1a,
+	0x4d, 0x61, 0xb2, 0x50, 0xbe, 0x8b, 0x75, 0x6c, 0x12, 0xa1, 0x11, 0x75, 0x2a, 0x18, 0xeb, 0x9f,
+	0x30, 0x43, 0x12, 0x29, 0x14, 0x32, 0xa2, 0x52, 0x31, 0x23, 0x42, 0x13, 0x4b, 0x75, 0x89, 0x77,
+	0xc5, 0x1c, 0x38, 0x45, 0xd0, 0x0d, 0x2f, 0x82, 0x10, 0xbd, 0x7b, 0x45, 0xf2, 0x5a, 0x06, 0x08,
+	0x1b, 0x2d, 0xb0, 0x1c, 0xe0, 0xf8, 0x51, 0xb1, 0x05, 0xe0, 0x7c, 0x99, 0x6d, 0x4e, 0x27, 0xbb,
+	0x1a, 0xa2, 0x4c, 0x9f, 0x73, 0x4c, 0xce, 0x82, 0x0c, 0x49, 0x07, 0x08, 0x41, 0xe9, 0x8f, 0xf5,
+	0x3f, 0x65, 0x68, 0xef, 0x7b, 0x21, 0xca, 0x49, 0xb9, 0x43, 0x2c, 0xfb, 0x69, 0x15, 0xe5, 0xc7,
+	0x5e, 0x7c, 0xab, 0x13, 0x3a, 0x0d, 0xa5, 0x99, 0x7e, 0xb9, 0x58, 0x3b, 0x8b, 0xfc, 0x2b, 0x5c,
+	0xca, 0x09, 0x60, 0xee, 0x00, 0x48, 0x01, 0xc5, 0x25, 0x7f, 0xf5, 0xee, 0x92, 0xdf, 0x60, 0x36,
+	0x1a, 0xea, 0xba, 0x10, 0xe7, 0x78, 0x92, 0xb1, 0xd4, 0xb9, 0x2e, 0x5c, 0x92, 0x7f, 0xe2, 0xd2,
+	0xe1, 0x5c, 0xcd, 0xd8, 0xff, 0x70, 0xe9, 0x80, 0x40, 0x5a, 0xed, 0x35, 0xe4, 0x38, 0x34, 0xc6,
+	0xe2, 0xbb, 0x1c, 0x2c, 0xf8, 0xf2, 0x7a, 0xc3, 0xfc, 0xc5, 0xb6, 0x4f, 0x16, 0x36, 0x92, 0x49,
+	0x0b, 0xa4, 0xbe, 0xd5, 0x9e, 0x07, 0x38, 0xf8, 0x72, 0xa1, 0x65, 0x6b, 0x0a, 0x2d, 0x7e, 0x3e,
+	0x0b, 0xce, 0x75, 0xb5, 0xcb, 0x63, 0xc9, 0xa9, 0x16, 0xbc, 0x1c, 0x3b, 0x87, 0xb6, 0x9d, 0xc2,
+	0xd6, 0x16, 0x94, 0x4f, 0x16, 0x66, 0x03, 0x2a, 0xa3, 0xe1, 0xb8, 0xf7, 0x1a, 0x0d, 0xf6, 0x87,
+	0x87, 0xbd, 0x12, 0x0d, 0x06, 0xfb, 0xfb, 0xbd, 0x32, 0x0d, 0xf6, 0x06, 0xa3, 0x5e, 0xc5, 0xfa,
+	0x45, 0x05, 0x8c, 0xa3, 0x65, 0xcc, 0x05, 0x4e, 0xf4, 0x32, 0xb5, 0x40, 0x12, 0xaa, 0x59, 0xc8,
+	0x29, 0x90, 0x18, 0x59, 0x83, 0x61, 0x74, 0xca, 0x8f, 0xa0, 0xa6, 0xf0, 0x42, 0x49, 0x18, 0xe8,
+	0xad, 0xde, 0xd4, 0x16, 0x32, 0x86, 0xc7, 0x7a, 0x84, 0x29, 0xe1, 0xdc, 0xc1, 0x37, 0x48, 0x19,
+	0x47, 0x8c, 0x91, 0x3c, 0xd9, 0xd6, 0x74, 0x7c, 0xb1, 0x37, 0xbc, 0x4b, 0x3f, 0x40, 0xf7, 0xe7,
+	0xf9, 0xae, 0xba, 0x99, 0x4c, 0x03, 0xff, 0x62, 0xe6, 0x4d, 0x63, 0x9d, 0x3c, 0xde, 0x17, 0xe2,
+	0x01, 0xd1, 0xf6, 0x34, 0x09, 0x9d, 0x7f, 0x8d, 0xde, 0x37, 0xd2, 0xc5, 0x27, 0x97, 0xab, 0xf4,
+	0x94, 0x7a, 0x69, 0x21, 0x62, 0xa1, 0xda, 0x70, 0x31, 0x63, 0x9b, 0xe0, 0xbb, 0x34, 0xf8, 0x5d,
+	0x5e, 0x67, 0x8b, 0x4a, 0x24, 0xb0, 0xbd, 0x8f, 0x44, 0x7c, 0x98, 0xba, 0xcb, 0xff, 0xa9, 0xee,
+	0x64, 0x76, 0xd1, 0x2a, 0x09, 0x19, 0x06, 0x61, 0xa4, 0xb9, 0x84, 0x26, 0xe7, 0x2c, 0xc8, 0xe0,
+	0xf2, 0xba, 0x0c, 0x82, 0xe2, 0x64, 0x1e, 0x13, 0xff, 0x70, 0xe9, 0xe3, 0x2d, 0x26, 0xe7, 0xce,
+	0xf4, 0x9a, 0x64, 0xe9, 0xbb, 0xfa, 0x15, 0x37, 0x90, 0x70, 0xe0, 0xef, 0xa6, 0x68, 0xeb, 0x31,
+	0xd4, 0x65, 0x77, 0xb3, 0x09, 0xd5, 0xe3, 0x93, 0xe3, 0xa1, 0xbc, 0xdc, 0xe0, 0x90, 0x5e, 0x0e,
+	0x51, 0xfb, 0x83, 0xf1, 0x00, 0x9f, 0x0e, 0x47, 0xe3, 0x1f, 0x9d, 0x0e, 0xf1, 0xed, 0xfe, 0xae,
+	0x04, 0xcd, 0x24, 0x4b, 0x40, 0x47, 0x81, 0xf1, 0x9c, 0xb3, 0x35, 0xed, 0x4d, 0xb8, 0xf3, 0x92,
+	0xab, 0xbd, 0xec, 0x84, 0x4e, 0x0a, 0xcc, 0x62, 0x4d, 0x9c, 0x25, 0x03, 0xf9, 0xca, 0xaf, 0x52,
+	0x68, 0x9c, 0x50, 0xf9, 0x1c, 0xf8, 0x4a, 0x17, 0x43, 0x3c, 0x66, 0x6d, 0xc0, 0x24, 0x49, 0x11,
+	0x77, 0x4d, 0x6b, 0x03, 0xc1, 0x58, 0x87, 0xfd, 0x63, 0x19, 0x9a, 0x69, 0xee, 0xfc, 0x0d, 0x8c,
+	0xd7, 0x89, 0x6c, 0xb5, 0x0b, 0xeb, 0x14, 0x04, 0x6e, 0x67, 0x74, 0xb4, 0xf4, 0xf2, 0xf5, 0x73,
+	0xad, 0x1b, 0x75, 0xe2, 0xfa, 0xf4, 0x99, 0x8d, 0x98, 0xcc, 0x07, 0xd6, 0x5e, 0xe9, 0x03, 0x31,
+	0x70, 0x4e, 0xb1, 0x5a, 0xcb, 0x85, 0x43, 0x6d, 0xa5, 0x5d, 0x46, 0x67, 0x09, 0x98, 0xf6, 0xdd,
+	0x8d, 0xcc, 0x77, 0xbf, 0x03, 0x35, 0x57, 0xcd, 0x62, 0x27, 0xdf, 0xb8, 0x3a, 0x09, 0x1d, 0x9c,
+	0xb7, 0x4f, 0x68, 0x5b, 0xa8, 0xa8, 0xc4, 0xcd, 0x24, 0xb1, 0xd7, 0xed, 0x2a, 0x6e, 0x73, 0x24,
+	0xef, 0x60, 0xa7, 0xd4, 0x4c, 0xcc, 0x90, 0x13, 0xb3, 0xf5, 0x2d, 0xa8, 0x7c, 0xfa, 0x6c, 0xa4,
+	0xef, 0x5a, 0x7a, 0xe1, 0xae, 0x89, 0xb0, 0xcb, 0x99, 0xb0, 0xad, 0xbf, 0xaf, 0x42, 0x43, 0xbb,
+	0x2a, 0x3a, 0xf7, 0x32, 0xad, 0x6d, 0x69, 0x58, 0x8c, 0x39, 0xa9, 0xcf, 0xcb, 0x37, 0x39, 0x2b,
+	0xaf, 0x6e, 0x72, 0x9a, 0xdf, 0xc5, 0xb4, 0x5b, 0x68, 0x79, 0x2f, 0xf9, 0xd5, 0xfc, 0x1c, 0xfd,
+	0x9f, 0xe7, 0xb5, 0x16, 0x19, 0x40, 0xca, 0xc0, 0xcd, 0x9f, 0xd8, 0xb9, 0xe4, 0x27, 0x6a, 0xdb,
+	0x0d, 0x82, 0xc7, 0xce, 0xe5, 0x1d, 0xbe, 0xf2, 0xb7, 0x71, 0x79, 0x5d, 0xf6, 0x9d, 0x6d, 0x76,
+	0x42, 0xe4, 0x26, 0xf3, 0xfe, 0xa7, 0x53, 0xf4, 0x3f, 0x5f, 0xa3, 0xae, 0xcd, 0x7c, 0xee, 0x31,
+	0xad, 0xab, 0x4b, 0x4f, 0x46, 0x8c, 0x33, 0xd7, 0xb9, 0x91, 0xb9, 0x4e, 0xeb, 0x6f, 0x4b, 0xd0,
+	0xd0, 0x12, 0x30, 0x5b, 0xd0, 0xd8, 0x1f, 0x3e, 0x19, 0x9c, 0x1d, 0x92, 0xa3, 0x04, 0xa8, 0xef,
+	0x1e, 0x1c, 0x0f, 0xec, 0x1f, 0x89, 0xaf, 0x3c, 0x38, 0x1e, 0xa3, 0xc1, 0x19, 0x50, 0x7b, 0x72,
+	0x78, 0x32, 0x18, 0xf7, 0x2a, 0x64, 0x7b, 0xbb, 0x27, 0x27, 0x87, 0xbd, 0xaa, 0xd9, 0x86, 0x26,
+	0xda, 0xe3, 0x70, 0x7c, 0x70, 0x34, 0xec, 0xd5, 0x88, 0xf7, 0xe9, 0xf0, 0xa4, 0x57, 0xa7, 0xc1,
+	0xd9, 0xc1, 0x7e, 0xaf, 0x41, 0xf4, 0xd3, 0xc1, 0x68, 0xf4, 0xd9, 0x89, 0xbd, 0xdf, 0x6b, 0xd2,
+	0xba, 0xa3, 0xb1, 0x7d, 0x70, 0xfc, 0xb4, 0x67, 0xd0, 0xf8, 0x64, 0xf7, 0x93, 0xe1, 0xde, 0xb8,
+	0x07, 0xb4, 0xde, 0x27, 0xa3, 0x93, 0xe3, 0x5e, 0x0b, 0xd5, 0xa2, 0x95, 0x93, 0x2f, 0xad, 0x63,
+	0x0f, 0x9f, 0xe0, 0x89, 0x70, 0xf3, 0x67, 0x83, 0xc3, 0xb3, 0x21, 0x1e, 0xa8, 0x0b, 0xc0, 0xc3,
+	0xc9, 0xe1, 0x00, 0x17, 0x2a, 0x5b, 0x3f, 0x84, 0xe6, 0x99, 0xe7, 0xee, 0xce, 0x82, 0xe9, 0x35,
+	0xdf, 0x12, 0xb3, 0x67, 0x9d, 0x5c, 0xf1, 0x98, 0x02, 0x27, 0xab, 0x6c, 0xa4, 0x35, 0x43, 0x43,
+	0x24, 0x49, 0x7f, 0x39, 0x9f, 0x70, 0xdb, 0xbc, 0x22, 0x4e, 0x1e, 0xe1, 0x33, 0xea, 0x9c, 0x1f,
+	0x43, 0x03, 0xff, 0x9f, 0xa2, 0x4f, 0xe2, 0x8e, 0x1b, 0x2d, 0x3d, 0x89, 0xbc, 0x2f, 0x94, 0x0e,
+	0x06, 0x06, 0x63, 0x46, 0x88, 0x40, 0x6f, 0x5b, 0x67, 0x20, 0xa9, 0x19, 0xd8, 0x08, 0x92, 0xe3,
+	0xd8, 0x9a, 0x66, 0xfd, 0x55, 0x29, 0xbd, 0x16, 0xb7, 0x44, 0x1f, 0x42, 0x15, 0x33, 0x83, 0x6b,
+	0xed, 0xa1, 0x5a, 0x7a, 0x0e, 0xed, 0x67, 0x33, 0x01, 0xed, 0xb7, 0xa9, 0x35, 0x2b, 0x59, 0xb8,
+	0x95, 0x53, 0x41, 0x3b, 0x25, 0x16, 0xdf, 0xbc, 0xb2, 0xf2, 0xe6, 0x78, 0xf3, 0x68, 0x31, 0xf3,
+	0xb8, 0x4d, 0x54, 0x21, 0x4f, 0x26, 0x90, 0xf5, 0x6d, 0x80, 0xac, 0x15, 0xbd, 0x3e, 0x7d, 0x73,
+	0x66, 0x9e, 0x16, 0x18, 0x6a, 0x2b, 0x03, 0x28, 0x94, 0x56, 0xae, 0x81, 0x4d, 0xe2, 0x73, 0x66,
+	0xb3, 0x09, 0xb5, 0x26, 0x79, 0x6e, 0xd3, 0x6e, 0x20, 0xfc, 0x29, 0x82, 0x14, 0x82, 0xa4, 0xf7,
+	0x5d, 0x5e, 0xe9, 0x98, 0xf2, 0x54, 0x5b, 0x88, 0xd6, 0x37, 0xa1, 0x2e, 0x6d, 0xd4, 0x9c, 0x1d,
+	0x94, 0xee, 0xb2, 0x03, 0xeb, 0x63, 0x7d, 0x66, 0x6e, 0xba, 0xa2, 0x3f, 0x6d, 0xe9, 0x8e, 0x39,
+	0xf7, 0x4f, 0x4b, 0x59, 0x95, 0x23, 0x4c, 0xba, 0xbd, 0xce, 0xcc, 0xd6, 0x3e, 0x34, 0x5f, 0xfa,
+	0xd5, 0x42, 0x0b, 0xa0, 0x9c, 0x09, 0x60, 0xcd, 0x77, 0x0c, 0xeb, 0xc7, 0x78, 0x80, 0xb4, 0x17,
+	0xaf, 0xcd, 0x52, 0x56, 0x21, 0xb3, 0x7c, 0x9f, 0xba, 0x3e, 0xde, 0xcc, 0x0d, 0x95, 0x5f, 0xb8,
+	0x75, 0xd6, 0xbd, 0x4f, 0xe9, 0x98, 0xee, 0x57, 0xf9, 0x13, 0x43, 0x25, 0x73, 0x9b, 0xe9, 0xf7,
+	0x05, 0xa6, 0x58, 0x37, 0xd0, 0x91, 0x7c, 0xc0, 0xa6, 0x84, 0x3f, 0x7a, 0x69, 0x9e, 0x4a, 0x4d,
+	0x80, 0xac, 0xe6, 0x91, 0x8f, 0x25, 0x39, 0x0c, 0x29, 0xc1, 0x85, 0xa7, 0x66, 0x6e, 0x72, 0x1b,
+	0x0d, 0xd1, 0x23, 0x4b, 0x9e, 0x50, 0x95, 0xce, 0x2f, 0x03, 0xd6, 0x1f, 0x43, 0x3b, 0xd9, 0x99,
+	0x1b, 0x9c, 0xdf, 0x48, 0x73, 0x15, 0x91, 0xb1, 0xb4, 0x44, 0x84, 0xe5, 0x18, 0x2b, 0xf4, 0xdd,
+	0x72, 0xbf, 0x94, 0xa4, 0x2b, 0xd6, 0xbf, 0xd6, 0x92, 0xd9, 0xba, 0xdf, 0x57, 0xc8, 0xa1, 0x4b,
+	0xab, 0x39, 0x74, 0x31, 0x1f, 0x2d, 0xff, 0x56, 0xf9, 0xe8, 0x77, 0xc0, 0x70, 0x39, 0xa5, 0xf2,
+	0x9e, 0x27, 0x0e, 0x7d, 0x73, 0x35, 0x7d, 0xd2, 0x49, 0x17, 0x72, 0xd8, 0x19, 0x33, 0x9d, 0x25,
+	0x0e, 0xae, 0x95, 0x8f, 0x56, 0x1b, 0xea, 0x3b, 0x67, 0x88, 0xac, 0x3b, 0x5c, 0xcb, 0xf7, 0xa9,
+	0x93, 0xfe, 0x7c, 0x3d, 0xd7, 0x9f, 0x47, 0x79, 0x62, 0x3d, 0xa8, 0xc2, 0x38, 0xc9, 0xe6, 0x05,
+	0x4a, 0x13, 0x5f, 0x43, 0xf3, 0x52, 0xe2, 0xfb, 0x75, 0x68, 0xfb, 0x81, 0x3f, 0xf1, 0x97, 0xb3,
+	0x19, 0xd5, 0x1b, 0x3a, 0xe3, 0x69, 0x21, 0xee, 0x58, 0xa3, 0x28, 0x33, 0xca, 0xb3, 0x88, 0x3e,
+	0xb7, 0x24, 0x33, 0xca, 0xf1, 0xb1, 0xd6, 0x6f, 0x41, 0x2f, 0x38, 0xff, 0x31, 0x7d, 0xb4, 0x20,
+	0x89, 0x4d, 0x58, 0x91, 0xa5, 0x2f, 0xd4, 0x15, 0x3c, 0x89, 0xe8, 0x98, 0x54, 0x1a, 0x0f, 0x39,
+	0x77, 0xa2, 0x6b, 0x25, 0x5d, 0x21, 0x7c, 0x74, 0x81, 0x48, 0x8f, 0xa8, 0x36, 0x62, 0x5f, 0x26,
+	0x11, 0xa2, 0x41, 0x6d, 0x27, 0xf2, 0x64, 0x85, 0x9e, 0xff, 0xc6, 0x6a, 0xcf, 0x9f, 0xba, 0x9a,
+	0x49, 0xf2, 0xd9, 0x93, 0x86, 0x56, 0x02, 0xaf, 0x66, 0x7f, 0xf7, 0x5e, 0xc8, 0xfe, 0x1e, 0x03,
+	0x48, 0xfe, 0xca, 0xbe, 0xd9, 0x64, 0xb5, 0x7f, 0x31, 0xe9, 0x35, 0x98, 0x67, 0x97, 0x5c, 0xf6,
+	0x56, 0xea, 0x10, 0xee, 0xdf, 0x95, 0x21, 0xa7, 0x6e, 0xc1, 0x48, 0x5f, 0x3b, 0x97, 0x2f, 0x62,
+	0xb8, 0x38, 0x38, 0xde, 0x1f, 0xfe, 0x29, 0x86, 0x0b, 0x0c, 0x6c, 0xf6, 0xf0, 0xd9, 0xd0, 0x1e,
+	0x0d, 0x31, 0x86, 0x61, 0xd0, 0xc1, 0x0a, 0x60, 0x38, 0xc6, 0xb4, 0xf1, 0x93, 0x6a, 0xb3, 0xd1,
+	0xe3, 0x06, 0x2c, 0x3a, 0xc5, 0xa9, 0x17, 0x5b, 0x7f, 0x01, 0x90, 0xe5, 0xc9, 0xe4, 0x58, 0x33,
+	0x21, 0x8b, 0xea, 0x36, 0xe3, 0x44, 0xbc, 0x5b, 0xa9, 0x4d, 0x95, 0xef, 0x3c, 0x9f, 0x58, 0x99,
+	0xf6, 0x2d, 0x62, 0x7a, 0xec, 0x5b, 0xc8, 0x29, 0xc7, 0x21, 0xc9, 0x51, 0x77, 0xd5, 0x05, 0xb2,
+	0xce, 0xa0, 0x79, 0xe4, 0x2c, 0x5e, 0xa8, 0xa8, 0xdb, 0x69, 0x8b, 0x71, 0xa9, 0xbb, 0xf6, 0x3a,
+	0xdf, 0x79, 0x07, 0x1a, 0x3a, 0x0a, 0x68, 0x47, 0x52, 0x88, 0x10, 0x09, 0xcd, 0xfa, 0x69, 0x09,
+	0x5e, 0x3f, 0xc2, 0x6a, 0x33, 0x4d, 0xf9, 0x4e, 0x9d, 0xdb, 0x59, 0xe0, 0xb8, 0xaf, 0xb0, 0x4d,
+	0x0c, 0x7b, 0x51, 0xb0, 0xc4, 0x9a, 0x76, 0x72, 0x99, 0x7e, 0x2c, 0x30, 0x04, 0xf3, 0x54, 0x7f,
+	0x69, 0x45, 0xbf, 0xc4, 0x44, 0x1d, 0x3b, 0x09, 0x26, 0xd2, 0x1b, 0x50, 0x8f, 0x6f, 0xfc, 0xec,
+	0xdb, 0x44, 0x2d, 0xa6, 0x8e, 0x95, 0xb5, 0x07, 0xc6, 0xf8, 0x86, 0x0b, 0xfe, 0x65, 0x54, 0x48,
+	0x62, 0x4a, 0x2f, 0x49, 0x62, 0xca, 0xc5, 0x80, 0x66, 0xfd, 0x37, 0xc6, 0xd1, 0x5c, 0x2e, 0x8a,
+	0xf6, 0x55, 0xc5, 0xd5, 0x8b, 0xdf, 0x22, 0x93, 0x4d, 0x6c, 0x26, 0x91, 0x09, 0x92, 0xc6, 0x3b,
+	0x51, 0x84, 0xc5, 0x92, 0x72, 0xf5, 0x92, 0xd4, 0x21, 0x18, 0x68, 0x94, 0x79, 0x08, 0x1b, 0xe2,
+	0x5c, 0x93, 0x3e, 0x7d, 0x52, 0xc1, 0xbd, 0xb5, 0x92, 0xfb, 0x4a, 0x67, 0x67, 0x2f, 0xe1, 0x92,
+	0xa6, 0x51, 0xf7, 0xb2, 0x80, 0xdc, 0x1c, 0xc0, 0xfd, 0x35, 0x6c, 0x5f, 0xaa, 0x6d, 0xf9, 0x10,
+	0x3a, 0xd4, 0xe6, 0xf3, 0xe6, 0x28, 0x52, 0x67, 0xbe, 0xe0, 0x24, 0x50, 0x07, 0xc7, 0xaa, 0x8d,
+	0x23, 0xeb, 0x11, 0xb4, 0x4f, 0x95, 0x0a, 0xd1, 0x47, 0x2f, 0xb0, 0x60, 0x50, 0xa2, 0x53, 0x74,
+	0x69, 0x1d, 0x89, 0x35, 0x64, 0xfd, 0x19, 0x18, 0x54, 0xf9, 0xec, 0x3a, 0xf1, 0xf4, 0xea, 0xcb,
+	0x54, 0x46, 0x8f, 0x50, 0xb7, 0x44, 0x4d, 0x74, 0xb1, 0xd2, 0x66, 0xb7, 0xaf, 0x55, 0xc7, 0x4e,
+	0x88, 0x96, 0x0f, 0x95, 0xe3, 0xe5, 0x3c, 0xff, 0xdb, 0x82, 0xaa, 0xfc, 0xb6, 0xa0, 0xd0, 0xda,
+	0x28, 0x17, 0x5b, 0x1b, 0xa4, 0x79, 0x17, 0x41, 0xf8, 0xe7, 0x4e, 0x48, 0x9f, 0x41, 0xa4, 0x7f,
+	0x92, 0x21, 0x0a, 0xad, 0xf3, 0x6a, 0xb1, 0x75, 0x6e, 0x7d, 0x0e, 0xad, 0xe4, 0xd5, 0x0e, 0x5c,
+	0xfe, 0x69, 0x01, 0xab, 0xcd, 0x81, 0x5b, 0xd0, 0x22, 0xe9, 0x4d, 0xa0, 0xf3, 0x39, 0x48, 0x9e,
+	0x5b, 0x80, 0xe2, 0xa9, 0x74, 0x4b, 0x35, 0x6d, 0xb8, 0x3c, 0xc1, 0xd8, 0xa5, 0x4b, 0x96, 0x23,
+	0x85, 0xba, 0x45, 0x8a, 0x38, 0xf3, 0x94, 0x9f, 0x53, 0xd2, 0xa6, 0x20, 0xc6, 0xd1, 0x4b, 0xbe,
+	0xa4, 0x59, 0xdb, 0x98, 0xe3, 0x8a, 0x96, 0x63, 0x44, 0x98, 0x52, 0x5b, 0xbb, 0xc4, 0x5f, 0x16,
+	0x79, 0x4c, 0xa2, 0x9a, 0x47, 0x97, 0x49, 0xae, 0x81, 0x43, 0xeb, 0x5f, 0xca, 0xd0, 0xa1, 0xea,
+	0x77, 0xb9, 0x48, 0x82, 0x7d, 0xae, 0x02, 0x2d, 0x15, 0x2a, 0xd0, 0x7c, 0xb5, 0x59, 0x2e, 0x54,
+	0x9b, 0x85, 0x03, 0x55, 0x8a, 0x09, 0x02, 0x2e, 0xb7, 0xf4, 0xbd, 0x9b, 0xc4, 0x22, 0x31, 0x18,
+	0x10, 0x88, 0x73, 0xde, 0x84, 0x16, 0x19, 0xad, 0xe7, 0x8b, 0xcf, 0xaf, 0x31, 0x31, 0x8f, 0x22,
+	0x2f, 0xe0, 0x4c, 0xa7, 0x2a, 0x8a, 0x28, 0xcd, 0xd3, 0xb5, 0x8b, 0x21, 0x18, 0x4c, 0xf4, 0xd8,
+	0x49, 0xa8, 0x69, 0xa8, 0xe2, 0x49, 0x56, 0x43, 0x1a, 0x82, 0x21, 0xf2, 0x5b, 0xd0, 0x89, 0x90,
+	0x13, 0x17, 0x9a, 0x70, 0xa0, 0xd5, 0x7d, 0x83, 0xb6, 0x46, 0x8e, 0x09, 0x47, 0xca, 0xe0, 0x60,
+	0x9c, 0xbb, 0x9d, 0x07, 0xcb, 0x48, 0xc7, 0xce, 0x0c, 0xb1, 0x92, 0xdc, 0xc0, 0x6a, 0x72, 0x63,
+	0xc5, 0xd0, 0x19, 0xde, 0x2c, 0xf8, 0x7b, 0xec, 0x2b, 0x13, 0xa5, 0x9c, 0x58, 0xcb, 0x05, 0xb1,
+	0xe6, 0x04, 0x54, 0xe1, 0xbe, 0x5d, 0x22, 0x20, 0x4a, 0x9d, 0x82, 0x70, 0xee, 0xc4, 0x89, 0xe0,
+	0x04, 0xb2, 0xfe, 0xba, 0x0c, 0x86, 0x3c, 0x19, 0x5d, 0xf3, 0x3d, 0x74, 0x42, 0x94, 0xc0, 0x94,
+	0x38, 0x1b, 0x79, 0x83, 0x8c, 0x2a, 0x25, 0x6e, 0xe3, 0x1f, 0xa7, 0x30, 0xcc, 0xb2, 0xb6, 0x57,
+	0xa7, 0x3d, 0xbb, 0xe4, 0xee, 0xec, 0xd9, 0x51, 0xf3, 0xc4, 0x3b, 0x12, 0x5e, 0x7f, 0x42, 0x64,
+	0x04, 0xfd, 0xc6, 0x05, 0x97, 0xc0, 0x8c, 0x72, 0xae, 0x5f, 0x8b, 0xc7, 0x59, 0xf2, 0x52, 0x97,
+	0x76, 0x2b, 0x03, 0xd6, 0x15, 0x34, 0xf4, 0xee, 0x14, 0x03, 0xcf, 0x8e, 0x3f, 0x3d, 0x3e, 0xf9,
+	0xec, 0x18, 0x63, 0x63, 0xd2, 0x42, 0x29, 0x65, 0x51, 0xb2, 0x9c, 0x8f, 0x92, 0x15, 0xc2, 0xef,
+	0x9d, 0x9c, 0x61, 0xd1, 0x57, 0x35, 0x3b, 0x60, 0xf0, 0x70, 0x82, 0x54, 0x2c, 0xf0, 0xa8, 0x80,
+	0xdb, 0xfb, 0xc1, 0xf0, 0x68, 0x80, 0x35, 0x5e, 0xd2, 0x80, 0x69, 0x50, 0x8c, 0xb9, 0x27, 0x57,
+	0xce, 0x17, 0x39, 0xf9, 0x9f, 0x24, 0x55, 0xe5, 0x27, 0x49, 0xbf, 0xe3, 0xba, 0xe6, 0x73, 0xe8,
+	0x1c, 0xcc, 0xf3, 0xda, 0x40, 0x5d, 0x04, 0x27, 0x76, 0x74, 0x20, 0xe5, 0x71, 0xee, 0x51, 0xcb,
+	0xf9, 0x47, 0xe5, 0x42, 0x8f, 0xfc, 0xa4, 0x24, 0x47, 0x15, 0x5d, 0xe8, 0x11, 0x86, 0xd2, 0x23,
+	0x6b, 0x0c, 0xdd, 0x64, 0xed, 0xcc, 0xe9, 0xfa, 0x3f, 0x59, 0x3a, 0x6e, 0x6a, 0xa5, 0x02, 0xf1,
+	0x0b, 0xdd, 0xf8, 0x89, 0x92, 0x49, 0x14, 0x42, 0x5e, 0xe7, 0x1c, 0x27, 0xa7, 0x3d, 0x25, 0x81,
+	0x76, 0xfe, 0xad, 0x04, 0x55, 0xf2, 0xc0, 0xd4, 0x20, 0xfa, 0x81, 0xc2, 0x27, 0x3e, 0x57, 0x78,
+	0x94, 0x82, 0xb7, 0xdd, 0x2c, 0x40, 0xd6, 0x6b, 0x1f, 0x96, 0xcc, 0x6d, 0xf9, 0x31, 0x41, 0xf2,
+	0x1b, 0x89, 0x4e, 0xe2, 0xc7, 0xd9, 0xcf, 0xaf, 0xf2, 0x6f, 0x31, 0xff, 0x27, 0x81, 0xe7, 0xef,
+	0xc9, 0x17, 0x76, 0x73, 0xd5, 0xef, 0xaf, 0xce, 0x30, 0x3f, 0x80, 0xfa, 0x41, 0x44, 0x01, 0xe6,
+	0x45, 0x56, 0xce, 0x74, 0xf2, 0xb1, 0xc7, 0x7a, 0x6d, 0xe7, 0xa7, 0x55, 0xa8, 0xd2, 0xf7, 0x0d,
+	0xf3, 0x9b, 0xd0, 0xd0, 0xbd, 0x7d, 0x33, 0xd7, 0xc3, 0xdf, 0xe4, 0x9c, 0x7e, 0xa5, 0xe9, 0xcf,
+	0xbb, 0xf4, 0x24, 0x59, 0xca, 0x7a, 0x58, 0x66, 0xf6, 0xfd, 0xe4, 0x85, 0x43, 0x7d, 0x0c, 0xbd,
+	0x51, 0x8c, 0x16, 0x3b, 0xcf, 0xb1, 0x17, 0x05, 0xb5, 0xae, 0x21, 0xc6, 0xf2, 0xc2, 0x22, 0x46,
+	0xa2, 0xf8, 0xca, 0x84, 0xd5, 0xde, 0x16, 0x33, 0xbf, 0x0b, 0xad, 0xd1, 0x55, 0xb0, 0x9c, 0xb9,
+	0x23, 0x15, 0x62, 0x4e, 0x99, 0xfb, 0xfc, 0xbc, 0x99, 0x1b, 0xe3, 0x81, 0xb6, 0x00, 0x24, 0x18,
+	0x51, 0xcb, 0xc0, 0x6c, 0x10, 0x0d, 0x83, 0xa1, 0x2c, 0x9a, 0x8b, 0x52, 0xc2, 0x99, 0x0b, 0xe6,
+	0x2f, 0xe3, 0xfc, 0x08, 0x3a, 0x7b, 0xac, 0xe5, 0x27, 0xe1, 0x80, 0x34, 0xc4, 0x5c, 0xfd, 0x04,
+	0xbd, 0xb9, 0x8a, 0xc0, 0x49, 0x1f, 0x42, 0x73, 0x1c, 0xde, 0x0a, 0xff, 0x3d, 0x9d, 0x03, 0x65,
+	0xfb, 0xad, 0xb9, 0x25, 0x0a, 0xa4, 0xc3, 0x5f, 0x19, 0x93, 0xef, 0x49, 0x2f, 0x3d, 0xd3, 0xbb,
+	0x98, 0x62, 0x87, 0x8e, 0xe7, 0x53, 0xb9, 0x57, 0x78, 0xd7, 0x95, 0x17, 0xda, 0xf9, 0x79, 0x05,
+	0xea, 0x9f, 0x05, 0xe1, 0x35, 0xea, 0xcd, 0xfb, 0x50, 0xe7, 0xd6, 0xa6, 0x56, 0xce, 0xb4, 0xcd,
+	0xb9, 0xee, 0xf8, 0x6f, 0x83, 0xc1, 0xa2, 0xa6, 0x5f, 0x91, 0x89, 0x02, 0xf0, 0x8f, 0x02, 0x45,
+	0xda, 0x52, 0x86, 0xb2, 0xb6, 0x74, 0xe5, 0xf9, 0xd3, 0x4e, 0x6f, 0xa1, 0xdf, 0xb8, 0xd9, 0x90,
+	0xe6, 0xe1, 0x88, 0x14, 0x1e, 0x5f, 0x11, 0x9d, 0xf2, 0x48, 0xe4, 0x47, 0x4c, 0xd9, 0x0f, 0x8a,
+	0x36, 0xbb, 0x09, 0x22, 0x5d, 0xf9, 0x31, 0xfa, 0x34, 0x69, 0xb7, 0xdf, 0xcb, 0xd2, 0x78, 0xed,
+	0x41, 0x36, 0x7b, 0x79, 0x94, 0x9e, 0xf0, 0x1e, 0xd4, 0xc5, 0xdb, 0xc9, 0x84, 0x42, 0xf0, 0x96,
+	0x53, 0x4b, 0x02, 0x20, 0xac, 0x12, 0x9f, 0x84, 0xb5, 0x10, 0xab, 0x56, 0x58, 0xd1, 0x1c, 0x6c,
+	0x35, 0x55, 0x5e, 0x2e, 0x55, 0x37, 0x93, 0x4b, 0xad, 0xb1, 0xe9, 0x8f, 0xa1, 0x53, 0x48, 0xeb,
+	0xcd, 0x3e, 0x0b, 0x7a, 0x4d, 0xa6, 0xff, 0xc2, 0x3b, 0x7d, 0x0f, 0xcd, 0x9b, 0x5d, 0x19, 0xaa,
+	0x5b, 0x32, 0xe2, 0xe3, 0x15, 0x9c, 0xe7, 0xa6, 0x99, 0x47, 0x25, 0xc6, 0xbe, 0x55, 0xda, 0xed,
+	0xfd, 0xfb, 0x6f, 0x1e, 0x94, 0xfe, 0x03, 0xff, 0x7e, 0x8d, 0x7f, 0xff, 0xf0, 0x5f, 0x0f, 0x5e,
+	0x3b, 0xaf, 0xf3, 0x6f, 0x51, 0x3f, 0xfa, 0x3f, 0x69, 0x73, 0x26, 0x08, 0xcf, 0x2a, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -4498,6 +5004,8 @@ type ZeroClient interface {
 	Timestamps(ctx context.Context, in *Num, opts ...grpc.CallOption) (*AssignedIds, error)
 	CommitOrAbort(ctx context.Context, in *api.TxnContext, opts ...grpc.CallOption) (*api.TxnContext, error)
 	TryAbort(ctx context.Context, in *TxnTimestamps, opts ...grpc.CallOption) (*OracleDelta, error)
+	LeaseSequence(ctx context.Context, in *Num, opts ...grpc.CallOption) (*AssignedIds, error)
+	DrainNode(ctx context.Context, in *Member, opts ...grpc.CallOption) (*api.Payload, error)
 }
 
 type zeroClient struct {
@@ -4635,6 +5143,24 @@ func (c *zeroClient) TryAbort(ctx context.Context, in *TxnTimestamps, opts ...gr
 	return out, nil
 }
 
+func (c *zeroClient) LeaseSequence(ctx context.Context, in *Num, opts ...grpc.CallOption) (*AssignedIds, error) {
+	out := new(AssignedIds)
+	err := c.cc.Invoke(ctx, "/pb.Zero/LeaseSequence", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *zeroClient) DrainNode(ctx context.Context, in *Member, opts ...grpc.CallOption) (*api.Payload, error) {
+	out := new(api.Payload)
+	err := c.cc.Invoke(ctx, "/pb.Zero/DrainNode", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ZeroServer is the server API for Zero service.
 type ZeroServer interface {
 	// These 3 endpoints are for handling membership.
@@ -4647,6 +5173,8 @@ type ZeroServer interface {
 	Timestamps(context.Context, *Num) (*AssignedIds, error)
 	CommitOrAbort(context.Context, *api.TxnContext) (*api.TxnContext, error)
 	TryAbort(context.Context, *TxnTimestamps) (*OracleDelta, error)
+	LeaseSequence(context.Context, *Num) (*AssignedIds, error)
+	DrainNode(context.Context, *Member) (*api.Payload, error)
 }
 
 // UnimplementedZeroServer can be embedded to have forward compatible implementations.
@@ -4680,6 +5208,12 @@ func (*UnimplementedZeroServer) CommitOrAbort(ctx context.Context, req *api.TxnC
 func (*UnimplementedZeroServer) TryAbort(ctx context.Context, req *TxnTimestamps) (*OracleDelta, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method TryAbort not implemented")
 }
+func (*UnimplementedZeroServer) LeaseSequence(ctx context.Context, req *Num) (*AssignedIds, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method LeaseSequence not implemented")
+}
+func (*UnimplementedZeroServer) DrainNode(ctx context.Context, req *Member) (*api.Payload, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DrainNode not implemented")
+}
 
 func RegisterZeroServer(s *grpc.Server, srv ZeroServer) {
 	s.RegisterService(&_Zero_serviceDesc, srv)
@@ -4853,6 +5387,42 @@ func _Zero_TryAbort_Handler(srv interface{}, ctx context.Context, dec func(inter
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Zero_LeaseSequence_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Num)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZeroServer).LeaseSequence(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Zero/LeaseSequence",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZeroServer).LeaseSequence(ctx, req.(*Num))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Zero_DrainNode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Member)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ZeroServer).DrainNode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pb.Zero/DrainNode",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ZeroServer).DrainNode(ctx, req.(*Member))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _Zero_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "pb.Zero",
 	HandlerType: (*ZeroServer)(nil),
@@ -4885,6 +5455,14 @@ var _Zero_serviceDesc = grpc.ServiceDesc{
 			MethodName: "TryAbort",
 			Handler:    _Zero_TryAbort_Handler,
 		},
+		{
+			MethodName: "LeaseSequence",
+			Handler:    _Zero_LeaseSequence_Handler,
+		},
+		{
+			MethodName: "DrainNode",
+			Handler:    _Zero_DrainNode_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -5330,6 +5908,112 @@ var _Worker_serviceDesc = grpc.ServiceDesc{
 	Metadata: "pb.proto",
 }
 
+// ImportClient is the client API for Import service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type ImportClient interface {
+	Import(ctx context.Context, opts ...grpc.CallOption) (Import_ImportClient, error)
+}
+
+type importClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewImportClient(cc *grpc.ClientConn) ImportClient {
+	return &importClient{cc}
+}
+
+func (c *importClient) Import(ctx context.Context, opts ...grpc.CallOption) (Import_ImportClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Import_serviceDesc.Streams[0], "/pb.Import/Import", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &importImportClient{stream}
+	return x, nil
+}
+
+type Import_ImportClient interface {
+	Send(*ImportRequest) error
+	CloseAndRecv() (*ImportResponse, error)
+	grpc.ClientStream
+}
+
+type importImportClient struct {
+	grpc.ClientStream
+}
+
+func (x *importImportClient) Send(m *ImportRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *importImportClient) CloseAndRecv() (*ImportResponse, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(ImportResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ImportServer is the server API for Import service.
+type ImportServer interface {
+	Import(Import_ImportServer) error
+}
+
+// UnimplementedImportServer can be embedded to have forward compatible implementations.
+type UnimplementedImportServer struct {
+}
+
+func (*UnimplementedImportServer) Import(srv Import_ImportServer) error {
+	return status.Errorf(codes.Unimplemented, "method Import not implemented")
+}
+
+func RegisterImportServer(s *grpc.Server, srv ImportServer) {
+	s.RegisterService(&_Import_serviceDesc, srv)
+}
+
+func _Import_Import_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ImportServer).Import(&importImportServer{stream})
+}
+
+type Import_ImportServer interface {
+	SendAndClose(*ImportResponse) error
+	Recv() (*ImportRequest, error)
+	grpc.ServerStream
+}
+
+type importImportServer struct {
+	grpc.ServerStream
+}
+
+func (x *importImportServer) SendAndClose(m *ImportResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *importImportServer) Recv() (*ImportRequest, error) {
+	m := new(ImportRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Import_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "pb.Import",
+	HandlerType: (*ImportServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Import",
+			Handler:       _Import_Import_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "pb.proto",
+}
+
 func (m *List) Marshal() (dAtA []byte, err error) {
 	size := m.Size()
 	dAtA = make([]byte, size)
@@ -5482,6 +6166,13 @@ func (m *Query) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.ValidAt) > 0 {
+		i -= len(m.ValidAt)
+		copy(dAtA[i:], m.ValidAt)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.ValidAt)))
+		i--
+		dAtA[i] = 0x7a
+	}
 	if m.Cache != 0 {
 		i = encodeVarintPb(dAtA, i, uint64(m.Cache))
 		i--
@@ -5696,6 +6387,16 @@ func (m *Result) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.BytesRead != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.BytesRead))
+		i--
+		dAtA[i] = 0x48
+	}
+	if m.KeysRead != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.KeysRead))
+		i--
+		dAtA[i] = 0x40
+	}
 	if m.List {
 		i--
 		if m.List {
@@ -5817,16 +6518,58 @@ func (m *Order) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	if len(m.Langs) > 0 {
-		for iNdEx := len(m.Langs) - 1; iNdEx >= 0; iNdEx-- {
-			i -= len(m.Langs[iNdEx])
-			copy(dAtA[i:], m.Langs[iNdEx])
-			i = encodeVarintPb(dAtA, i, uint64(len(m.Langs[iNdEx])))
-			i--
-			dAtA[i] = 0x1a
-		}
-	}
-	if m.Desc {
+	if m.Reverse {
+		i--
+		if m.Reverse {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x40
+	}
+	if m.Count {
+		i--
+		if m.Count {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x38
+	}
+	if m.Seed != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.Seed))
+		i--
+		dAtA[i] = 0x30
+	}
+	if m.Random {
+		i--
+		if m.Random {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
+	if len(m.Collation) > 0 {
+		i -= len(m.Collation)
+		copy(dAtA[i:], m.Collation)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Collation)))
+		i--
+		dAtA[i] = 0x22
+	}
+	if len(m.Langs) > 0 {
+		for iNdEx := len(m.Langs) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Langs[iNdEx])
+			copy(dAtA[i:], m.Langs[iNdEx])
+			i = encodeVarintPb(dAtA, i, uint64(len(m.Langs[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
+	if m.Desc {
 		i--
 		if m.Desc {
 			dAtA[i] = 1
@@ -6031,6 +6774,11 @@ func (m *Member) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.FeatureVersion != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.FeatureVersion))
+		i--
+		dAtA[i] = 0x70
+	}
 	if m.ClusterInfoOnly {
 		i--
 		if m.ClusterInfoOnly {
@@ -6111,6 +6859,16 @@ func (m *Group) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Excluded {
+		i--
+		if m.Excluded {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x28
+	}
 	if m.Checksum != 0 {
 		i = encodeVarintPb(dAtA, i, uint64(m.Checksum))
 		i--
@@ -6198,6 +6956,63 @@ func (m *ZeroProposal) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.FreezeMode != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.FreezeMode))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x90
+	}
+	if len(m.FreezePredicate) > 0 {
+		i -= len(m.FreezePredicate)
+		copy(dAtA[i:], m.FreezePredicate)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.FreezePredicate)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x8a
+	}
+	if m.FeatureVersion != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.FeatureVersion))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x80
+	}
+	if m.IncludeGroupId != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.IncludeGroupId))
+		i--
+		dAtA[i] = 0x78
+	}
+	if m.ExcludeGroupId != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.ExcludeGroupId))
+		i--
+		dAtA[i] = 0x70
+	}
+	if m.PinGroupId != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.PinGroupId))
+		i--
+		dAtA[i] = 0x68
+	}
+	if len(m.PinPredicate) > 0 {
+		i -= len(m.PinPredicate)
+		copy(dAtA[i:], m.PinPredicate)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.PinPredicate)))
+		i--
+		dAtA[i] = 0x62
+	}
+	if m.MaxSequenceId != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.MaxSequenceId))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.Sequence) > 0 {
+		i -= len(m.Sequence)
+		copy(dAtA[i:], m.Sequence)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Sequence)))
+		i--
+		dAtA[i] = 0x52
+	}
 	if len(m.Cid) > 0 {
 		i -= len(m.Cid)
 		copy(dAtA[i:], m.Cid)
@@ -6305,6 +7120,62 @@ func (m *MembershipState) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.FrozenPredicates) > 0 {
+		for k := range m.FrozenPredicates {
+			v := m.FrozenPredicates[k]
+			baseI := i
+			i = encodeVarintPb(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintPb(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintPb(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x62
+		}
+	}
+	if m.FeatureVersion != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.FeatureVersion))
+		i--
+		dAtA[i] = 0x58
+	}
+	if len(m.PinnedTablets) > 0 {
+		for k := range m.PinnedTablets {
+			v := m.PinnedTablets[k]
+			baseI := i
+			i = encodeVarintPb(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintPb(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintPb(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x52
+		}
+	}
+	if len(m.Sequences) > 0 {
+		for k := range m.Sequences {
+			v := m.Sequences[k]
+			baseI := i
+			i = encodeVarintPb(dAtA, i, uint64(v))
+			i--
+			dAtA[i] = 0x10
+			i -= len(k)
+			copy(dAtA[i:], k)
+			i = encodeVarintPb(dAtA, i, uint64(len(k)))
+			i--
+			dAtA[i] = 0xa
+			i = encodeVarintPb(dAtA, i, uint64(baseI-i))
+			i--
+			dAtA[i] = 0x4a
+		}
+	}
 	if len(m.Cid) > 0 {
 		i -= len(m.Cid)
 		copy(dAtA[i:], m.Cid)
@@ -6551,6 +7422,23 @@ func (m *DirectedEdge) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Expected) > 0 {
+		i -= len(m.Expected)
+		copy(dAtA[i:], m.Expected)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Expected)))
+		i--
+		dAtA[i] = 0x5a
+	}
+	if m.Blob {
+		i--
+		if m.Blob {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
 	if len(m.Facets) > 0 {
 		for iNdEx := len(m.Facets) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -6642,6 +7530,31 @@ func (m *Mutations) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.CommitTs != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.CommitTs))
+		i--
+		dAtA[i] = 0x58
+	}
+	if m.RunInBackground {
+		i--
+		if m.RunInBackground {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x50
+	}
+	if m.AppendOnly {
+		i--
+		if m.AppendOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x48
+	}
 	if len(m.DropValue) > 0 {
 		i -= len(m.DropValue)
 		copy(dAtA[i:], m.DropValue)
@@ -6966,6 +7879,16 @@ func (m *Posting) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Blob {
+		i--
+		if m.Blob {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x78
+	}
 	if m.CommitTs != 0 {
 		i = encodeVarintPb(dAtA, i, uint64(m.CommitTs))
 		i--
@@ -7609,6 +8532,76 @@ func (m *SchemaUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Facets) > 0 {
+		for iNdEx := len(m.Facets) - 1; iNdEx >= 0; iNdEx-- {
+			{
+				size, err := m.Facets[iNdEx].MarshalToSizedBuffer(dAtA[:i])
+				if err != nil {
+					return 0, err
+				}
+				i -= size
+				i = encodeVarintPb(dAtA, i, uint64(size))
+			}
+			i--
+			dAtA[i] = 0x1
+			i--
+			dAtA[i] = 0x9a
+		}
+	}
+	if m.IndexBase != nil {
+		{
+			size, err := m.IndexBase.MarshalToSizedBuffer(dAtA[:i])
+			if err != nil {
+				return 0, err
+			}
+			i -= size
+			i = encodeVarintPb(dAtA, i, uint64(size))
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x92
+	}
+	if m.AppendOnly {
+		i--
+		if m.AppendOnly {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x88
+	}
+	if len(m.Conflict) > 0 {
+		i -= len(m.Conflict)
+		copy(dAtA[i:], m.Conflict)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Conflict)))
+		i--
+		dAtA[i] = 0x1
+		i--
+		dAtA[i] = 0x82
+	}
+	if len(m.Collation) > 0 {
+		i -= len(m.Collation)
+		copy(dAtA[i:], m.Collation)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Collation)))
+		i--
+		dAtA[i] = 0x7a
+	}
+	if m.MaxSize != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.MaxSize))
+		i--
+		dAtA[i] = 0x70
+	}
+	if len(m.Masked) > 0 {
+		i -= len(m.Masked)
+		copy(dAtA[i:], m.Masked)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Masked)))
+		i--
+		dAtA[i] = 0x6a
+	}
 	if len(m.ObjectTypeName) > 0 {
 		i -= len(m.ObjectTypeName)
 		copy(dAtA[i:], m.ObjectTypeName)
@@ -7729,6 +8722,25 @@ func (m *TypeUpdate) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if m.Strict {
+		i--
+		if m.Strict {
+			dAtA[i] = 1
+		} else {
+			dAtA[i] = 0
+		}
+		i--
+		dAtA[i] = 0x20
+	}
+	if len(m.Key) > 0 {
+		for iNdEx := len(m.Key) - 1; iNdEx >= 0; iNdEx-- {
+			i -= len(m.Key[iNdEx])
+			copy(dAtA[i:], m.Key[iNdEx])
+			i = encodeVarintPb(dAtA, i, uint64(len(m.Key[iNdEx])))
+			i--
+			dAtA[i] = 0x1a
+		}
+	}
 	if len(m.Fields) > 0 {
 		for iNdEx := len(m.Fields) - 1; iNdEx >= 0; iNdEx-- {
 			{
@@ -8109,6 +9121,13 @@ func (m *Num) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 		i -= len(m.XXX_unrecognized)
 		copy(dAtA[i:], m.XXX_unrecognized)
 	}
+	if len(m.Sequence) > 0 {
+		i -= len(m.Sequence)
+		copy(dAtA[i:], m.Sequence)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Sequence)))
+		i--
+		dAtA[i] = 0x22
+	}
 	if m.Forwarded {
 		i--
 		if m.Forwarded {
@@ -8543,33 +9562,121 @@ func (m *BackupPostingList) MarshalToSizedBuffer(dAtA []byte) (int, error) {
 	return len(dAtA) - i, nil
 }
 
-func encodeVarintPb(dAtA []byte, offset int, v uint64) int {
-	offset -= sovPb(v)
-	base := offset
-	for v >= 1<<7 {
-		dAtA[offset] = uint8(v&0x7f | 0x80)
-		v >>= 7
-		offset++
+func (m *ImportRequest) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
 	}
-	dAtA[offset] = uint8(v)
-	return base
+	return dAtA[:n], nil
 }
-func (m *List) Size() (n int) {
-	if m == nil {
-		return 0
-	}
+
+func (m *ImportRequest) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ImportRequest) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
 	var l int
 	_ = l
-	if len(m.Uids) > 0 {
-		n += 1 + sovPb(uint64(len(m.Uids)*8)) + len(m.Uids)*8
-	}
 	if m.XXX_unrecognized != nil {
-		n += len(m.XXX_unrecognized)
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
 	}
-	return n
-}
-
-func (m *TaskValue) Size() (n int) {
+	if m.BatchSize != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.BatchSize))
+		i--
+		dAtA[i] = 0x18
+	}
+	if len(m.Format) > 0 {
+		i -= len(m.Format)
+		copy(dAtA[i:], m.Format)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Format)))
+		i--
+		dAtA[i] = 0x12
+	}
+	if len(m.Data) > 0 {
+		i -= len(m.Data)
+		copy(dAtA[i:], m.Data)
+		i = encodeVarintPb(dAtA, i, uint64(len(m.Data)))
+		i--
+		dAtA[i] = 0xa
+	}
+	return len(dAtA) - i, nil
+}
+
+func (m *ImportResponse) Marshal() (dAtA []byte, err error) {
+	size := m.Size()
+	dAtA = make([]byte, size)
+	n, err := m.MarshalToSizedBuffer(dAtA[:size])
+	if err != nil {
+		return nil, err
+	}
+	return dAtA[:n], nil
+}
+
+func (m *ImportResponse) MarshalTo(dAtA []byte) (int, error) {
+	size := m.Size()
+	return m.MarshalToSizedBuffer(dAtA[:size])
+}
+
+func (m *ImportResponse) MarshalToSizedBuffer(dAtA []byte) (int, error) {
+	i := len(dAtA)
+	_ = i
+	var l int
+	_ = l
+	if m.XXX_unrecognized != nil {
+		i -= len(m.XXX_unrecognized)
+		copy(dAtA[i:], m.XXX_unrecognized)
+	}
+	if m.Aborts != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.Aborts))
+		i--
+		dAtA[i] = 0x18
+	}
+	if m.Txns != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.Txns))
+		i--
+		dAtA[i] = 0x10
+	}
+	if m.Nquads != 0 {
+		i = encodeVarintPb(dAtA, i, uint64(m.Nquads))
+		i--
+		dAtA[i] = 0x8
+	}
+	return len(dAtA) - i, nil
+}
+
+func encodeVarintPb(dAtA []byte, offset int, v uint64) int {
+	offset -= sovPb(v)
+	base := offset
+	for v >= 1<<7 {
+		dAtA[offset] = uint8(v&0x7f | 0x80)
+		v >>= 7
+		offset++
+	}
+	dAtA[offset] = uint8(v)
+	return base
+}
+func (m *List) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if len(m.Uids) > 0 {
+		n += 1 + sovPb(uint64(len(m.Uids)*8)) + len(m.Uids)*8
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *TaskValue) Size() (n int) {
 	if m == nil {
 		return 0
 	}
@@ -8663,6 +9770,10 @@ func (m *Query) Size() (n int) {
 	if m.Cache != 0 {
 		n += 1 + sovPb(uint64(m.Cache))
 	}
+	l = len(m.ValidAt)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -8748,6 +9859,12 @@ func (m *Result) Size() (n int) {
 	if m.List {
 		n += 2
 	}
+	if m.KeysRead != 0 {
+		n += 1 + sovPb(uint64(m.KeysRead))
+	}
+	if m.BytesRead != 0 {
+		n += 1 + sovPb(uint64(m.BytesRead))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -8773,6 +9890,22 @@ func (m *Order) Size() (n int) {
 			n += 1 + l + sovPb(uint64(l))
 		}
 	}
+	l = len(m.Collation)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	if m.Random {
+		n += 2
+	}
+	if m.Seed != 0 {
+		n += 1 + sovPb(uint64(m.Seed))
+	}
+	if m.Count {
+		n += 2
+	}
+	if m.Reverse {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -8883,6 +10016,9 @@ func (m *Member) Size() (n int) {
 	if m.ClusterInfoOnly {
 		n += 2
 	}
+	if m.FeatureVersion != 0 {
+		n += 1 + sovPb(uint64(m.FeatureVersion))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -8927,6 +10063,9 @@ func (m *Group) Size() (n int) {
 	if m.Checksum != 0 {
 		n += 1 + sovPb(uint64(m.Checksum))
 	}
+	if m.Excluded {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -8976,6 +10115,36 @@ func (m *ZeroProposal) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPb(uint64(l))
 	}
+	l = len(m.Sequence)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	if m.MaxSequenceId != 0 {
+		n += 1 + sovPb(uint64(m.MaxSequenceId))
+	}
+	l = len(m.PinPredicate)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	if m.PinGroupId != 0 {
+		n += 1 + sovPb(uint64(m.PinGroupId))
+	}
+	if m.ExcludeGroupId != 0 {
+		n += 1 + sovPb(uint64(m.ExcludeGroupId))
+	}
+	if m.IncludeGroupId != 0 {
+		n += 1 + sovPb(uint64(m.IncludeGroupId))
+	}
+	if m.FeatureVersion != 0 {
+		n += 2 + sovPb(uint64(m.FeatureVersion))
+	}
+	l = len(m.FreezePredicate)
+	if l > 0 {
+		n += 2 + l + sovPb(uint64(l))
+	}
+	if m.FreezeMode != 0 {
+		n += 2 + sovPb(uint64(m.FreezeMode))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9036,6 +10205,33 @@ func (m *MembershipState) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPb(uint64(l))
 	}
+	if len(m.Sequences) > 0 {
+		for k, v := range m.Sequences {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovPb(uint64(len(k))) + 1 + sovPb(uint64(v))
+			n += mapEntrySize + 1 + sovPb(uint64(mapEntrySize))
+		}
+	}
+	if len(m.PinnedTablets) > 0 {
+		for k, v := range m.PinnedTablets {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovPb(uint64(len(k))) + 1 + sovPb(uint64(v))
+			n += mapEntrySize + 1 + sovPb(uint64(mapEntrySize))
+		}
+	}
+	if m.FeatureVersion != 0 {
+		n += 1 + sovPb(uint64(m.FeatureVersion))
+	}
+	if len(m.FrozenPredicates) > 0 {
+		for k, v := range m.FrozenPredicates {
+			_ = k
+			_ = v
+			mapEntrySize := 1 + len(k) + sovPb(uint64(len(k))) + 1 + sovPb(uint64(v))
+			n += mapEntrySize + 1 + sovPb(uint64(mapEntrySize))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9136,6 +10332,13 @@ func (m *DirectedEdge) Size() (n int) {
 			n += 1 + l + sovPb(uint64(l))
 		}
 	}
+	if m.Blob {
+		n += 2
+	}
+	l = len(m.Expected)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9182,6 +10385,15 @@ func (m *Mutations) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPb(uint64(l))
 	}
+	if m.AppendOnly {
+		n += 2
+	}
+	if m.RunInBackground {
+		n += 2
+	}
+	if m.CommitTs != 0 {
+		n += 1 + sovPb(uint64(m.CommitTs))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9324,6 +10536,9 @@ func (m *Posting) Size() (n int) {
 	if m.CommitTs != 0 {
 		n += 1 + sovPb(uint64(m.CommitTs))
 	}
+	if m.Blob {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9629,6 +10844,34 @@ func (m *SchemaUpdate) Size() (n int) {
 	if l > 0 {
 		n += 1 + l + sovPb(uint64(l))
 	}
+	l = len(m.Masked)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	if m.MaxSize != 0 {
+		n += 1 + sovPb(uint64(m.MaxSize))
+	}
+	l = len(m.Collation)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	l = len(m.Conflict)
+	if l > 0 {
+		n += 2 + l + sovPb(uint64(l))
+	}
+	if m.AppendOnly {
+		n += 3
+	}
+	if m.IndexBase != nil {
+		l = m.IndexBase.Size()
+		n += 2 + l + sovPb(uint64(l))
+	}
+	if len(m.Facets) > 0 {
+		for _, e := range m.Facets {
+			l = e.Size()
+			n += 2 + l + sovPb(uint64(l))
+		}
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9651,6 +10894,15 @@ func (m *TypeUpdate) Size() (n int) {
 			n += 1 + l + sovPb(uint64(l))
 		}
 	}
+	if len(m.Key) > 0 {
+		for _, s := range m.Key {
+			l = len(s)
+			n += 1 + l + sovPb(uint64(l))
+		}
+	}
+	if m.Strict {
+		n += 2
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -9821,6 +11073,10 @@ func (m *Num) Size() (n int) {
 	if m.Forwarded {
 		n += 2
 	}
+	l = len(m.Sequence)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
 	if m.XXX_unrecognized != nil {
 		n += len(m.XXX_unrecognized)
 	}
@@ -10027,6 +11283,50 @@ func (m *BackupPostingList) Size() (n int) {
 	return n
 }
 
+func (m *ImportRequest) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	l = len(m.Data)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	l = len(m.Format)
+	if l > 0 {
+		n += 1 + l + sovPb(uint64(l))
+	}
+	if m.BatchSize != 0 {
+		n += 1 + sovPb(uint64(m.BatchSize))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
+func (m *ImportResponse) Size() (n int) {
+	if m == nil {
+		return 0
+	}
+	var l int
+	_ = l
+	if m.Nquads != 0 {
+		n += 1 + sovPb(uint64(m.Nquads))
+	}
+	if m.Txns != 0 {
+		n += 1 + sovPb(uint64(m.Txns))
+	}
+	if m.Aborts != 0 {
+		n += 1 + sovPb(uint64(m.Aborts))
+	}
+	if m.XXX_unrecognized != nil {
+		n += len(m.XXX_unrecognized)
+	}
+	return n
+}
+
 func sovPb(x uint64) (n int) {
 	return (math_bits.Len64(x|1) + 6) / 7
 }
@@ -10729,6 +12029,40 @@ func (m *Query) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ValidAt", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.ValidAt = append(m.ValidAt[:0], dAtA[iNdEx:postIndex]...)
+			if m.ValidAt == nil {
+				m.ValidAt = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -11209,20 +12543,58 @@ func (m *Result) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.List = bool(v != 0)
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPb(dAtA[iNdEx:])
-			if err != nil {
-				return err
-			}
-			if skippy < 0 {
-				return ErrInvalidLengthPb
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field KeysRead", wireType)
 			}
-			if (iNdEx + skippy) < 0 {
-				return ErrInvalidLengthPb
+			m.KeysRead = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.KeysRead |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BytesRead", wireType)
+			}
+			m.BytesRead = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BytesRead |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
 			}
 			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
 			iNdEx += skippy
@@ -11347,6 +12719,117 @@ func (m *Order) Unmarshal(dAtA []byte) error {
 			}
 			m.Langs = append(m.Langs, string(dAtA[iNdEx:postIndex]))
 			iNdEx = postIndex
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Collation", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Collation = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Random", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Random = bool(v != 0)
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Seed", wireType)
+			}
+			m.Seed = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Seed |= int64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Count", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Count = bool(v != 0)
+		case 8:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Reverse", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Reverse = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -11942,6 +13425,25 @@ func (m *Member) Unmarshal(dAtA []byte) error {
 				}
 			}
 			m.ClusterInfoOnly = bool(v != 0)
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FeatureVersion", wireType)
+			}
+			m.FeatureVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FeatureVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -12278,6 +13780,26 @@ func (m *Group) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
+		case 5:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Excluded", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Excluded = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -12660,65 +14182,43 @@ func (m *ZeroProposal) Unmarshal(dAtA []byte) error {
 			}
 			m.Cid = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
-		default:
-			iNdEx = preIndex
-			skippy, err := skipPb(dAtA[iNdEx:])
-			if err != nil {
-				return err
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
 			}
-			if skippy < 0 {
-				return ErrInvalidLengthPb
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
 			}
-			if (iNdEx + skippy) < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPb
 			}
-			if (iNdEx + skippy) > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
-			iNdEx += skippy
-		}
-	}
-
-	if iNdEx > l {
-		return io.ErrUnexpectedEOF
-	}
-	return nil
-}
-func (m *MembershipState) Unmarshal(dAtA []byte) error {
-	l := len(dAtA)
-	iNdEx := 0
-	for iNdEx < l {
-		preIndex := iNdEx
-		var wire uint64
-		for shift := uint(0); ; shift += 7 {
-			if shift >= 64 {
-				return ErrIntOverflowPb
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
 			}
-			if iNdEx >= l {
+			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			b := dAtA[iNdEx]
-			iNdEx++
-			wire |= uint64(b&0x7F) << shift
-			if b < 0x80 {
-				break
-			}
-		}
-		fieldNum := int32(wire >> 3)
-		wireType := int(wire & 0x7)
-		if wireType == 4 {
-			return fmt.Errorf("proto: MembershipState: wiretype end group for non-group")
-		}
-		if fieldNum <= 0 {
-			return fmt.Errorf("proto: MembershipState: illegal tag %d (wire type %d)", fieldNum, wire)
-		}
-		switch fieldNum {
-		case 1:
+			m.Sequence = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 11:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Counter", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSequenceId", wireType)
 			}
-			m.Counter = 0
+			m.MaxSequenceId = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPb
@@ -12728,16 +14228,16 @@ func (m *MembershipState) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.Counter |= uint64(b&0x7F) << shift
+				m.MaxSequenceId |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 2:
+		case 12:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Groups", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field PinPredicate", wireType)
 			}
-			var msglen int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPb
@@ -12747,24 +14247,256 @@ func (m *MembershipState) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				msglen |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			if msglen < 0 {
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
 				return ErrInvalidLengthPb
 			}
-			postIndex := iNdEx + msglen
+			postIndex := iNdEx + intStringLen
 			if postIndex < 0 {
 				return ErrInvalidLengthPb
 			}
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			if m.Groups == nil {
-				m.Groups = make(map[uint32]*Group)
-			}
+			m.PinPredicate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 13:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinGroupId", wireType)
+			}
+			m.PinGroupId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.PinGroupId |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field ExcludeGroupId", wireType)
+			}
+			m.ExcludeGroupId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.ExcludeGroupId |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IncludeGroupId", wireType)
+			}
+			m.IncludeGroupId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.IncludeGroupId |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 16:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FeatureVersion", wireType)
+			}
+			m.FeatureVersion = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FeatureVersion |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 17:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FreezePredicate", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.FreezePredicate = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 18:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field FreezeMode", wireType)
+			}
+			m.FreezeMode = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.FreezeMode |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *MembershipState) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: MembershipState: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: MembershipState: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Counter", wireType)
+			}
+			m.Counter = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Counter |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Groups", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Groups == nil {
+				m.Groups = make(map[uint32]*Group)
+			}
 			var mapkey uint32
 			var mapvalue *Group
 			for iNdEx < postIndex {
@@ -13001,11 +14733,322 @@ func (m *MembershipState) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-		case 6:
+		case 6:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxRaftId", wireType)
+			}
+			m.MaxRaftId = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxRaftId |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 7:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Removed", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Removed = append(m.Removed, &Member{})
+			if err := m.Removed[len(m.Removed)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 8:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Cid", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Cid = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 9:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequences", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.Sequences == nil {
+				m.Sequences = make(map[string]uint64)
+			}
+			var mapkey string
+			var mapvalue uint64
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPb
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthPb
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthPb
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPb(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthPb
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.Sequences[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 10:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field PinnedTablets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.PinnedTablets == nil {
+				m.PinnedTablets = make(map[string]uint32)
+			}
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPb
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
+				}
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthPb
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthPb
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPb(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthPb
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
+				}
+			}
+			m.PinnedTablets[mapkey] = mapvalue
+			iNdEx = postIndex
+		case 11:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field MaxRaftId", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FeatureVersion", wireType)
 			}
-			m.MaxRaftId = 0
+			m.FeatureVersion = 0
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPb
@@ -13015,14 +15058,14 @@ func (m *MembershipState) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.MaxRaftId |= uint64(b&0x7F) << shift
+				m.FeatureVersion |= uint32(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-		case 7:
+		case 12:
 			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Removed", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field FrozenPredicates", wireType)
 			}
 			var msglen int
 			for shift := uint(0); ; shift += 7 {
@@ -13049,42 +15092,89 @@ func (m *MembershipState) Unmarshal(dAtA []byte) error {
 			if postIndex > l {
 				return io.ErrUnexpectedEOF
 			}
-			m.Removed = append(m.Removed, &Member{})
-			if err := m.Removed[len(m.Removed)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
-				return err
-			}
-			iNdEx = postIndex
-		case 8:
-			if wireType != 2 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Cid", wireType)
+			if m.FrozenPredicates == nil {
+				m.FrozenPredicates = make(map[string]uint32)
 			}
-			var stringLen uint64
-			for shift := uint(0); ; shift += 7 {
-				if shift >= 64 {
-					return ErrIntOverflowPb
-				}
-				if iNdEx >= l {
-					return io.ErrUnexpectedEOF
+			var mapkey string
+			var mapvalue uint32
+			for iNdEx < postIndex {
+				entryPreIndex := iNdEx
+				var wire uint64
+				for shift := uint(0); ; shift += 7 {
+					if shift >= 64 {
+						return ErrIntOverflowPb
+					}
+					if iNdEx >= l {
+						return io.ErrUnexpectedEOF
+					}
+					b := dAtA[iNdEx]
+					iNdEx++
+					wire |= uint64(b&0x7F) << shift
+					if b < 0x80 {
+						break
+					}
 				}
-				b := dAtA[iNdEx]
-				iNdEx++
-				stringLen |= uint64(b&0x7F) << shift
-				if b < 0x80 {
-					break
+				fieldNum := int32(wire >> 3)
+				if fieldNum == 1 {
+					var stringLenmapkey uint64
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						stringLenmapkey |= uint64(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+					intStringLenmapkey := int(stringLenmapkey)
+					if intStringLenmapkey < 0 {
+						return ErrInvalidLengthPb
+					}
+					postStringIndexmapkey := iNdEx + intStringLenmapkey
+					if postStringIndexmapkey < 0 {
+						return ErrInvalidLengthPb
+					}
+					if postStringIndexmapkey > l {
+						return io.ErrUnexpectedEOF
+					}
+					mapkey = string(dAtA[iNdEx:postStringIndexmapkey])
+					iNdEx = postStringIndexmapkey
+				} else if fieldNum == 2 {
+					for shift := uint(0); ; shift += 7 {
+						if shift >= 64 {
+							return ErrIntOverflowPb
+						}
+						if iNdEx >= l {
+							return io.ErrUnexpectedEOF
+						}
+						b := dAtA[iNdEx]
+						iNdEx++
+						mapvalue |= uint32(b&0x7F) << shift
+						if b < 0x80 {
+							break
+						}
+					}
+				} else {
+					iNdEx = entryPreIndex
+					skippy, err := skipPb(dAtA[iNdEx:])
+					if err != nil {
+						return err
+					}
+					if skippy < 0 {
+						return ErrInvalidLengthPb
+					}
+					if (iNdEx + skippy) > postIndex {
+						return io.ErrUnexpectedEOF
+					}
+					iNdEx += skippy
 				}
 			}
-			intStringLen := int(stringLen)
-			if intStringLen < 0 {
-				return ErrInvalidLengthPb
-			}
-			postIndex := iNdEx + intStringLen
-			if postIndex < 0 {
-				return ErrInvalidLengthPb
-			}
-			if postIndex > l {
-				return io.ErrUnexpectedEOF
-			}
-			m.Cid = string(dAtA[iNdEx:postIndex])
+			m.FrozenPredicates[mapkey] = mapvalue
 			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
@@ -13691,6 +15781,60 @@ func (m *DirectedEdge) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blob", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Blob = bool(v != 0)
+		case 11:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Expected", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Expected = append(m.Expected[:0], dAtA[iNdEx:postIndex]...)
+			if m.Expected == nil {
+				m.Expected = []byte{}
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -13956,6 +16100,65 @@ func (m *Mutations) Unmarshal(dAtA []byte) error {
 			}
 			m.DropValue = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 9:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppendOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AppendOnly = bool(v != 0)
+		case 10:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field RunInBackground", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.RunInBackground = bool(v != 0)
+		case 11:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field CommitTs", wireType)
+			}
+			m.CommitTs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CommitTs |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -14824,7 +17027,26 @@ func (m *Posting) Unmarshal(dAtA []byte) error {
 			if wireType != 0 {
 				return fmt.Errorf("proto: wrong wireType = %d for field CommitTs", wireType)
 			}
-			m.CommitTs = 0
+			m.CommitTs = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.CommitTs |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Blob", wireType)
+			}
+			var v int
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPb
@@ -14834,11 +17056,12 @@ func (m *Posting) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				m.CommitTs |= uint64(b&0x7F) << shift
+				v |= int(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
+			m.Blob = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -16564,6 +18787,211 @@ func (m *SchemaUpdate) Unmarshal(dAtA []byte) error {
 			}
 			m.ObjectTypeName = string(dAtA[iNdEx:postIndex])
 			iNdEx = postIndex
+		case 13:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Masked", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Masked = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 14:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field MaxSize", wireType)
+			}
+			m.MaxSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.MaxSize |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 15:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Collation", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Collation = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 16:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Conflict", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Conflict = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 17:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field AppendOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.AppendOnly = bool(v != 0)
+		case 18:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field IndexBase", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			if m.IndexBase == nil {
+				m.IndexBase = &SchemaUpdate{}
+			}
+			if err := m.IndexBase.Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
+		case 19:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Facets", wireType)
+			}
+			var msglen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				msglen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if msglen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + msglen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Facets = append(m.Facets, &SchemaUpdate{})
+			if err := m.Facets[len(m.Facets)-1].Unmarshal(dAtA[iNdEx:postIndex]); err != nil {
+				return err
+			}
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -16684,6 +19112,58 @@ func (m *TypeUpdate) Unmarshal(dAtA []byte) error {
 				return err
 			}
 			iNdEx = postIndex
+		case 3:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Key", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Key = append(m.Key, string(dAtA[iNdEx:postIndex]))
+			iNdEx = postIndex
+		case 4:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Strict", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.Strict = bool(v != 0)
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -17664,7 +20144,27 @@ func (m *Num) Unmarshal(dAtA []byte) error {
 			}
 		case 2:
 			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnly", wireType)
+				return fmt.Errorf("proto: wrong wireType = %d for field ReadOnly", wireType)
+			}
+			var v int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				v |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			m.ReadOnly = bool(v != 0)
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Forwarded", wireType)
 			}
 			var v int
 			for shift := uint(0); ; shift += 7 {
@@ -17681,12 +20181,12 @@ func (m *Num) Unmarshal(dAtA []byte) error {
 					break
 				}
 			}
-			m.ReadOnly = bool(v != 0)
-		case 3:
-			if wireType != 0 {
-				return fmt.Errorf("proto: wrong wireType = %d for field Forwarded", wireType)
+			m.Forwarded = bool(v != 0)
+		case 4:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Sequence", wireType)
 			}
-			var v int
+			var stringLen uint64
 			for shift := uint(0); ; shift += 7 {
 				if shift >= 64 {
 					return ErrIntOverflowPb
@@ -17696,12 +20196,24 @@ func (m *Num) Unmarshal(dAtA []byte) error {
 				}
 				b := dAtA[iNdEx]
 				iNdEx++
-				v |= int(b&0x7F) << shift
+				stringLen |= uint64(b&0x7F) << shift
 				if b < 0x80 {
 					break
 				}
 			}
-			m.Forwarded = bool(v != 0)
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Sequence = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
 		default:
 			iNdEx = preIndex
 			skippy, err := skipPb(dAtA[iNdEx:])
@@ -18954,6 +21466,256 @@ func (m *BackupPostingList) Unmarshal(dAtA []byte) error {
 	}
 	return nil
 }
+func (m *ImportRequest) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ImportRequest: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ImportRequest: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Data", wireType)
+			}
+			var byteLen int
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				byteLen |= int(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			if byteLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + byteLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Data = append(m.Data[:0], dAtA[iNdEx:postIndex]...)
+			if m.Data == nil {
+				m.Data = []byte{}
+			}
+			iNdEx = postIndex
+		case 2:
+			if wireType != 2 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Format", wireType)
+			}
+			var stringLen uint64
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				stringLen |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+			intStringLen := int(stringLen)
+			if intStringLen < 0 {
+				return ErrInvalidLengthPb
+			}
+			postIndex := iNdEx + intStringLen
+			if postIndex < 0 {
+				return ErrInvalidLengthPb
+			}
+			if postIndex > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.Format = string(dAtA[iNdEx:postIndex])
+			iNdEx = postIndex
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field BatchSize", wireType)
+			}
+			m.BatchSize = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.BatchSize |= uint32(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
+func (m *ImportResponse) Unmarshal(dAtA []byte) error {
+	l := len(dAtA)
+	iNdEx := 0
+	for iNdEx < l {
+		preIndex := iNdEx
+		var wire uint64
+		for shift := uint(0); ; shift += 7 {
+			if shift >= 64 {
+				return ErrIntOverflowPb
+			}
+			if iNdEx >= l {
+				return io.ErrUnexpectedEOF
+			}
+			b := dAtA[iNdEx]
+			iNdEx++
+			wire |= uint64(b&0x7F) << shift
+			if b < 0x80 {
+				break
+			}
+		}
+		fieldNum := int32(wire >> 3)
+		wireType := int(wire & 0x7)
+		if wireType == 4 {
+			return fmt.Errorf("proto: ImportResponse: wiretype end group for non-group")
+		}
+		if fieldNum <= 0 {
+			return fmt.Errorf("proto: ImportResponse: illegal tag %d (wire type %d)", fieldNum, wire)
+		}
+		switch fieldNum {
+		case 1:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Nquads", wireType)
+			}
+			m.Nquads = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Nquads |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 2:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Txns", wireType)
+			}
+			m.Txns = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Txns |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		case 3:
+			if wireType != 0 {
+				return fmt.Errorf("proto: wrong wireType = %d for field Aborts", wireType)
+			}
+			m.Aborts = 0
+			for shift := uint(0); ; shift += 7 {
+				if shift >= 64 {
+					return ErrIntOverflowPb
+				}
+				if iNdEx >= l {
+					return io.ErrUnexpectedEOF
+				}
+				b := dAtA[iNdEx]
+				iNdEx++
+				m.Aborts |= uint64(b&0x7F) << shift
+				if b < 0x80 {
+					break
+				}
+			}
+		default:
+			iNdEx = preIndex
+			skippy, err := skipPb(dAtA[iNdEx:])
+			if err != nil {
+				return err
+			}
+			if skippy < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) < 0 {
+				return ErrInvalidLengthPb
+			}
+			if (iNdEx + skippy) > l {
+				return io.ErrUnexpectedEOF
+			}
+			m.XXX_unrecognized = append(m.XXX_unrecognized, dAtA[iNdEx:iNdEx+skippy]...)
+			iNdEx += skippy
+		}
+	}
+
+	if iNdEx > l {
+		return io.ErrUnexpectedEOF
+	}
+	return nil
+}
 func skipPb(dAtA []byte) (n int, err error) {
 	l := len(dAtA)
 	iNdEx := 0
@@ -24,18 +24,45 @@ import (
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/x"
+	farm "github.com/dgryski/go-farm"
 	"github.com/pkg/errors"
 )
 
+// approxCountDistinct is the name of the aggregator estimating the number of distinct values.
+const approxCountDistinct = "approx_count_distinct"
+
 type aggregator struct {
 	name   string
 	result types.Val
 	count  int // used when we need avergae.
+	// sketch holds the hashes of the values of approx_count_distinct, whose result is only set to
+	// their count by Value.
+	sketch *x.HyperLogLog
 }
 
 func isUnary(f string) bool {
 	return f == "ln" || f == "exp" || f == "u-" || f == "sqrt" ||
-		f == "floor" || f == "ceil" || f == "since"
+		f == "floor" || f == "ceil" || f == "since" || f == "abs" || f == "round"
+}
+
+func isStringFn(f string) bool {
+	return f == "concat" || f == "substr" || f == "lower" || f == "upper" ||
+		f == "trim" || f == "len"
+}
+
+func checkStringFnArgs(f string, n int) error {
+	switch {
+	case f == "concat" && n >= 1:
+	case f == "substr" && (n == 2 || n == 3):
+	case f != "concat" && f != "substr" && n == 1:
+	default:
+		return errors.Errorf("Wrong number of arguments for function %v: %d", f, n)
+	}
+	return nil
+}
+
+func isRounding(f string) bool {
+	return f == "floor" || f == "ceil" || f == "round"
 }
 
 func isBinaryBoolean(f string) bool {
@@ -170,6 +197,18 @@ func (ag *aggregator) ApplyVal(v types.Val) error {
 			}
 			v.Value = math.Ceil(l)
 			res = v
+		case "round":
+			if !isIntOrFloat {
+				return errors.Errorf("Wrong type encountered for func %q", ag.name)
+			}
+			v.Value = math.Round(l)
+			res = v
+		case "abs":
+			if !isIntOrFloat {
+				return errors.Errorf("Wrong type encountered for func %q", ag.name)
+			}
+			v.Value = math.Abs(l)
+			res = v
 		case "since":
 			if v.Tid == types.DateTimeID {
 				v.Value = float64(time.Since(v.Value.(time.Time))) / 1000000000.0
@@ -266,6 +305,10 @@ func (ag *aggregator) ApplyVal(v types.Val) error {
 }
 
 func (ag *aggregator) Apply(val types.Val) {
+	if ag.name == approxCountDistinct {
+		ag.addToSketch(val)
+		return
+	}
 	if ag.result.Value == nil {
 		ag.result = val
 		ag.count++
@@ -305,6 +348,44 @@ func (ag *aggregator) Apply(val types.Val) {
 	ag.result = res
 }
 
+// addToSketch adds the hash of the value, and of its type, to the sketch of approx_count_distinct.
+func (ag *aggregator) addToSketch(val types.Val) {
+	data := types.ValueForType(types.BinaryID)
+	if err := types.Marshal(val, &data); err != nil {
+		// The values which can't be marshalled can't be counted.
+		return
+	}
+	if ag.sketch == nil {
+		ag.sketch = x.NewHyperLogLog()
+		ag.result = types.Val{Tid: types.IntID, Value: int64(0)}
+	}
+	b := append([]byte{byte(val.Tid)}, data.Value.([]byte)...)
+	ag.sketch.Add(farm.Fingerprint64(b))
+}
+
+// merge combines the partial result of another aggregator of the same kind into ag.
+func (ag *aggregator) merge(other *aggregator) {
+	if other.result.Value == nil {
+		return
+	}
+	if other.sketch != nil {
+		if ag.sketch == nil {
+			ag.sketch = x.NewHyperLogLog()
+			ag.result = types.Val{Tid: types.IntID, Value: int64(0)}
+		}
+		ag.sketch.Merge(other.sketch)
+		return
+	}
+	if ag.result.Value == nil {
+		ag.result = other.result
+		ag.count = other.count
+		return
+	}
+	ag.Apply(other.result)
+	// Apply counts the partial result as a single value.
+	ag.count += other.count - 1
+}
+
 func (ag *aggregator) ValueMarshalled() (*pb.TaskValue, error) {
 	data := types.ValueForType(types.BinaryID)
 	ag.divideByCount()
@@ -322,6 +403,10 @@ func (ag *aggregator) ValueMarshalled() (*pb.TaskValue, error) {
 }
 
 func (ag *aggregator) divideByCount() {
+	if ag.sketch != nil {
+		ag.result.Value = int64(ag.sketch.Count())
+		return
+	}
 	if ag.name != "avg" || ag.count == 0 || ag.result.Value == nil {
 		return
 	}
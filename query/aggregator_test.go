@@ -0,0 +1,127 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/stretchr/testify/require"
+)
+
+// multiHopGraph returns the subgraph for
+//
+//	parent(func: uid(1, 2)) {
+//	  friend {        # 1 -> [10, 11], 2 -> [11]
+//	    ~owner {      # 10 -> [100, 101], 11 -> [101]
+//	      s as score  # 100 -> 2, 101 -> 4
+//	    }
+//	  }
+//	  agg as <fn>(val(s))
+//	}
+func multiHopGraph(fn string) (*SubGraph, *SubGraph) {
+	score := &SubGraph{Attr: "score", Params: params{Var: "s"}}
+	owner := &SubGraph{
+		Attr:      "~owner",
+		SrcUIDs:   &pb.List{Uids: []uint64{10, 11}},
+		uidMatrix: []*pb.List{{Uids: []uint64{100, 101}}, {Uids: []uint64{101}}},
+		Children:  []*SubGraph{score},
+	}
+	friend := &SubGraph{
+		Attr:      "friend",
+		SrcUIDs:   &pb.List{Uids: []uint64{1, 2}},
+		uidMatrix: []*pb.List{{Uids: []uint64{10, 11}}, {Uids: []uint64{11}}},
+		Children:  []*SubGraph{owner},
+	}
+	agg := &SubGraph{
+		Attr:    "val",
+		SrcFunc: &Function{Name: fn},
+		Params: params{
+			isInternal: true,
+			NeedsVar:   []gql.VarContext{{Name: "s", Typ: gql.ValueVar}},
+		},
+	}
+	parent := &SubGraph{Children: []*SubGraph{friend, agg}}
+	return parent, agg
+}
+
+func TestEvalLevelAggMultiHop(t *testing.T) {
+	doneVars := map[string]varValue{
+		"s": {Vals: map[uint64]types.Val{
+			100: {Tid: types.IntID, Value: int64(2)},
+			101: {Tid: types.IntID, Value: int64(4)},
+		}},
+	}
+	tests := []struct {
+		fn  string
+		out map[uint64]types.Val
+	}{
+		// 1 reaches 100 once and 101 twice, 2 reaches 101 once.
+		{fn: "sum", out: map[uint64]types.Val{
+			1: {Tid: types.IntID, Value: int64(10)},
+			2: {Tid: types.IntID, Value: int64(4)},
+		}},
+		{fn: "avg", out: map[uint64]types.Val{
+			1: {Tid: types.FloatID, Value: 10.0 / 3},
+			2: {Tid: types.FloatID, Value: 4.0},
+		}},
+		{fn: "min", out: map[uint64]types.Val{
+			1: {Tid: types.IntID, Value: int64(2)},
+			2: {Tid: types.IntID, Value: int64(4)},
+		}},
+		{fn: approxCountDistinct, out: map[uint64]types.Val{
+			1: {Tid: types.IntID, Value: int64(2)},
+			2: {Tid: types.IntID, Value: int64(1)},
+		}},
+	}
+	for _, tc := range tests {
+		parent, agg := multiHopGraph(tc.fn)
+		mp, err := evalLevelAgg(doneVars, agg, parent)
+		require.NoError(t, err)
+		require.Equal(t, tc.out, mp, tc.fn)
+	}
+}
+
+func TestEvalLevelAggMissingVar(t *testing.T) {
+	parent, agg := multiHopGraph("sum")
+	agg.Params.NeedsVar[0].Name = "missing"
+	_, err := evalLevelAgg(map[string]varValue{}, agg, parent)
+	require.Error(t, err)
+}
+
+func TestApproxCountDistinct(t *testing.T) {
+	ag := aggregator{name: approxCountDistinct}
+	_, err := ag.Value()
+	require.Equal(t, ErrEmptyVal, err)
+
+	for _, v := range []types.Val{
+		{Tid: types.StringID, Value: "a"},
+		{Tid: types.StringID, Value: "b"},
+		{Tid: types.StringID, Value: "a"},
+		{Tid: types.IntID, Value: int64(1)},
+		{Tid: types.IntID, Value: int64(1)},
+		// The values of different types are different.
+		{Tid: types.FloatID, Value: 1.0},
+	} {
+		ag.Apply(v)
+	}
+	v, err := ag.Value()
+	require.NoError(t, err)
+	require.Equal(t, types.Val{Tid: types.IntID, Value: int64(4)}, v)
+}
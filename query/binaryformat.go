@@ -0,0 +1,113 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strconv"
+
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// BinaryFormat controls how the binary values of a result are written.
+type BinaryFormat struct {
+	// Encoding is the encoding of the values in JSON strings, "base64" or "hex". Empty means
+	// base64.
+	Encoding string
+	// SummarySize is the size in bytes over which only the size and the SHA-256 hash of a value
+	// are written. 0 always writes the values.
+	SummarySize int
+}
+
+func defaultBinaryFormat() BinaryFormat {
+	return BinaryFormat{Encoding: "base64", SummarySize: x.Config.BinarySummarySize}
+}
+
+// ParseBinaryFormat returns the format asked for by the encoding and full options. A true full
+// option writes the values whatever their size. An empty option keeps the default.
+func ParseBinaryFormat(encoding, full string) (BinaryFormat, error) {
+	bf := defaultBinaryFormat()
+	switch encoding {
+	case "":
+	case "base64", "hex":
+		bf.Encoding = encoding
+	default:
+		return bf, errors.Errorf("Invalid binary encoding %q: expected base64 or hex", encoding)
+	}
+	if full != "" {
+		f, err := strconv.ParseBool(full)
+		if err != nil {
+			return bf, errors.Errorf("Invalid binary full option %q: expected true or false",
+				full)
+		}
+		if f {
+			bf.SummarySize = 0
+		}
+	}
+	return bf, nil
+}
+
+// requestBinaryFormat returns the binary format of the request, given either through
+// BinaryFormatKey or, for gRPC clients, the binary_encoding and binary_full metadata.
+func requestBinaryFormat(ctx context.Context) BinaryFormat {
+	bf, ok := ctx.Value(BinaryFormatKey).(BinaryFormat)
+	if !ok {
+		bf = defaultBinaryFormat()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		var encoding, full string
+		if len(md["binary_encoding"]) > 0 {
+			encoding = md["binary_encoding"][0]
+		}
+		if len(md["binary_full"]) > 0 {
+			full = md["binary_full"][0]
+		}
+		// Invalid values are ignored and the default format applies.
+		if encoding != "" || full != "" {
+			if f, err := ParseBinaryFormat(encoding, full); err == nil {
+				bf = f
+			}
+		}
+	}
+	return bf
+}
+
+// appendBinary appends the JSON of b to dst, which is a string holding the encoded bytes, or
+// an object holding their size and hash if there are too many of them.
+func (bf BinaryFormat) appendBinary(dst []byte, b []byte) []byte {
+	if bf.SummarySize > 0 && len(b) > bf.SummarySize {
+		sum := sha256.Sum256(b)
+		dst = append(dst, `{"size":`...)
+		dst = strconv.AppendInt(dst, int64(len(b)), 10)
+		dst = append(dst, `,"sha256":"`...)
+		dst = append(dst, hex.EncodeToString(sum[:])...)
+		return append(dst, `"}`...)
+	}
+
+	dst = append(dst, '"')
+	if bf.Encoding == "hex" {
+		dst = append(dst, hex.EncodeToString(b)...)
+	} else {
+		dst = append(dst, base64.StdEncoding.EncodeToString(b)...)
+	}
+	return append(dst, '"')
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestBinaryFormat(t *testing.T) {
+	format := func(bf BinaryFormat, b []byte) string {
+		bs, err := valToBytes(types.Val{Tid: types.BinaryID, Value: b}, DefaultFloatFormat, bf)
+		require.NoError(t, err)
+		return string(bs)
+	}
+
+	bin := []byte{0, 1, 0xfe, 0xff}
+	require.Equal(t, `"AAH+/w=="`, format(BinaryFormat{}, bin))
+	require.Equal(t, `"AAH+/w=="`, format(BinaryFormat{Encoding: "base64"}, bin))
+	require.Equal(t, `"0001feff"`, format(BinaryFormat{Encoding: "hex"}, bin))
+	require.Equal(t, `"0001feff"`, format(BinaryFormat{Encoding: "hex", SummarySize: 4}, bin))
+	require.Equal(t,
+		`{"size":4,"sha256":"c5dbae22661af6db18a1f676db82a7ef7de46d27c3a263a872f00478b0d99fc4"}`,
+		format(BinaryFormat{SummarySize: 3}, bin))
+}
+
+func TestParseBinaryFormat(t *testing.T) {
+	defer func(size int) { x.Config.BinarySummarySize = size }(x.Config.BinarySummarySize)
+	x.Config.BinarySummarySize = 100
+
+	bf, err := ParseBinaryFormat("", "")
+	require.NoError(t, err)
+	require.Equal(t, BinaryFormat{Encoding: "base64", SummarySize: 100}, bf)
+
+	bf, err = ParseBinaryFormat("hex", "true")
+	require.NoError(t, err)
+	require.Equal(t, BinaryFormat{Encoding: "hex"}, bf)
+
+	bf, err = ParseBinaryFormat("", "false")
+	require.NoError(t, err)
+	require.Equal(t, BinaryFormat{Encoding: "base64", SummarySize: 100}, bf)
+
+	_, err = ParseBinaryFormat("base32", "")
+	require.Error(t, err)
+	_, err = ParseBinaryFormat("", "maybe")
+	require.Error(t, err)
+}
+
+func TestRequestBinaryFormat(t *testing.T) {
+	require.Equal(t, BinaryFormat{Encoding: "base64"}, requestBinaryFormat(context.Background()))
+
+	bf := BinaryFormat{Encoding: "hex", SummarySize: 10}
+	ctx := context.WithValue(context.Background(), BinaryFormatKey, bf)
+	require.Equal(t, bf, requestBinaryFormat(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("binary_encoding", "hex", "binary_full", "true"))
+	require.Equal(t, BinaryFormat{Encoding: "hex"}, requestBinaryFormat(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("binary_encoding", "bad"))
+	require.Equal(t, BinaryFormat{Encoding: "base64"}, requestBinaryFormat(ctx))
+}
+
+func TestBinaryFormatQuery(t *testing.T) {
+	setSchema(testSchema + "\n bin_value: binary .\n")
+	triples := `<0x3101> <bin_value> "bin-data"^^<xs:base64Binary> .`
+	addTriplesToCluster(triples)
+	defer deleteTriplesInCluster(triples)
+
+	query := `{ me(func: uid(0x3101)) { bin_value } }`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me":[{"bin_value":"YmluLWRhdGE="}]}}`, js)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "binary_encoding", "hex")
+	js, err := processQuery(ctx, t, query)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data": {"me":[{"bin_value":"62696e2d64617461"}]}}`, js)
+}
@@ -0,0 +1,119 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// The statuses of the query blocks with @besteffort or @budget.
+const (
+	BlockOK         = "ok"
+	BlockFailed     = "failed"
+	BlockOverBudget = "over_budget"
+)
+
+type blockStatusKey struct{}
+
+// BlockStatus tells how a query block with @besteffort or @budget ran. The block returns no
+// results unless its status is BlockOK.
+type BlockStatus struct {
+	Block   string `json:"block"`
+	Status  string `json:"status"`
+	Latency string `json:"latency"`
+	Error   string `json:"error,omitempty"`
+}
+
+type blockStatuses struct {
+	sync.Mutex
+	list []BlockStatus
+}
+
+// WithBlockStatus returns a context that collects the statuses of the best effort blocks of
+// the queries run with it, so that they can be read back with BlockStatuses.
+func WithBlockStatus(ctx context.Context) context.Context {
+	return context.WithValue(ctx, blockStatusKey{}, &blockStatuses{})
+}
+
+func addBlockStatus(ctx context.Context, status BlockStatus) {
+	s, ok := ctx.Value(blockStatusKey{}).(*blockStatuses)
+	if !ok {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.list = append(s.list, status)
+}
+
+// BlockStatuses returns the statuses collected for the context, in the order the blocks ended.
+func BlockStatuses(ctx context.Context) []BlockStatus {
+	s, ok := ctx.Value(blockStatusKey{}).(*blockStatuses)
+	if !ok {
+		return nil
+	}
+	s.Lock()
+	defer s.Unlock()
+	return append([]BlockStatus(nil), s.list...)
+}
+
+// runBestEffort runs a block with @besteffort or @budget. If it fails, or runs for longer than
+// its budget, its results are dropped instead of failing the query, unless the whole query was
+// cancelled.
+func runBestEffort(ctx context.Context, gq *gql.GraphQuery, sg *SubGraph,
+	run func(ctx context.Context) error) error {
+
+	start := time.Now()
+	bctx := ctx
+	if gq.Budget > 0 {
+		var cancel context.CancelFunc
+		bctx, cancel = context.WithTimeout(ctx, gq.Budget)
+		defer cancel()
+	}
+	err := run(bctx)
+	status := BlockStatus{Block: gq.Alias, Status: BlockOK}
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		return err
+	case bctx.Err() == context.DeadlineExceeded:
+		status.Status = BlockOverBudget
+	default:
+		status.Status = BlockFailed
+		status.Error = err.Error()
+	}
+	if err != nil {
+		sg.clearResults()
+	}
+	status.Latency = time.Since(start).String()
+	addBlockStatus(ctx, status)
+	return nil
+}
+
+// clearResults drops the results of a root block, which is then returned without any node.
+func (sg *SubGraph) clearResults() {
+	sg.uidMatrix = nil
+	sg.valueMatrix = nil
+	sg.counts = nil
+	sg.DestUIDs = &pb.List{}
+	sg.Children = nil
+	sg.countedAtRoot = false
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// approximateFuncs are the functions at root whose count @approximate can take from the lengths
+// of the posting lists of the index.
+var approximateFuncs = map[string]bool{
+	"has":        true,
+	"eq":         true,
+	"le":         true,
+	"ge":         true,
+	"lt":         true,
+	"gt":         true,
+	"anyofterms": true,
+	"allofterms": true,
+	"anyoftext":  true,
+	"alloftext":  true,
+}
+
+// isCountOnly returns whether count(uid) is the only result of the block, in which case the
+// nodes of the block don't need to be traversed.
+func (sg *SubGraph) isCountOnly() bool {
+	return sg.Params.uidCount && len(sg.Children) == 0 && !sg.Params.isGroupBy &&
+		!sg.Params.IsEmpty && !sg.Params.Recurse && !sg.Params.shortest
+}
+
+// pushCount returns whether the worker can count the nodes of the root block, without
+// returning their uids. That's the case of the count-only blocks whose function is has() and
+// which need no filtering, ordering or pagination.
+func (sg *SubGraph) pushCount() bool {
+	if !sg.isCountOnly() || sg.SrcFunc == nil || sg.SrcFunc.Name != "has" ||
+		isPredicatePattern(sg.Attr) {
+		return false
+	}
+	// The nodes with a value in the language of the query are filtered after being listed.
+	return len(sg.Filters) == 0 && len(sg.Params.Order) == 0 && sg.Params.Count == 0 &&
+		sg.Params.Offset == 0 && !sg.Params.Cascade && !sg.isSampled() &&
+		!schema.State().HasLang(sg.Attr)
+}
+
+// setRootCount sets the count(uid) of the block from the counts which the worker returned for
+// its function. They are the count of each token of the function, so the nodes matching several
+// tokens are counted several times, and the count of a function matching all its tokens is the
+// smallest one.
+func (sg *SubGraph) setRootCount(result *pb.Result) {
+	var count int64
+	for i, c := range result.GetCounts() {
+		switch {
+		case !result.GetIntersectDest():
+			count += int64(c)
+		case i == 0 || int64(c) < count:
+			count = int64(c)
+		}
+	}
+	sg.rootCount, sg.countedAtRoot = count, true
+	sg.DestUIDs = &pb.List{}
+	sg.uidMatrix = []*pb.List{sg.DestUIDs}
+}
+
+// uidCountValue returns the count(uid) of the block.
+func (sg *SubGraph) uidCountValue() int64 {
+	if sg.countedAtRoot {
+		return sg.rootCount
+	}
+	return int64(len(sg.DestUIDs.Uids))
+}
+
+// processApproximate runs the @approximate root block. Its count(uid) is taken from the index, and
+// the approx_count_distinct of its predicates from their sketches, without reading the nodes.
+func (sg *SubGraph) processApproximate(ctx context.Context) error {
+	sg.DestUIDs = &pb.List{}
+	sg.uidMatrix = []*pb.List{sg.DestUIDs}
+	if sg.Params.uidCount {
+		taskQuery, err := createTaskQuery(sg)
+		if err != nil {
+			return err
+		}
+		taskQuery.DoCount = true
+		result, err := worker.ProcessTaskOverNetwork(ctx, taskQuery)
+		if err != nil && !strings.Contains(err.Error(), worker.ErrNonExistentTabletMessage) {
+			return err
+		}
+		sg.setRootCount(result)
+	}
+	for _, child := range sg.Children {
+		result, err := worker.ProcessTaskOverNetwork(ctx, &pb.Query{
+			Attr:    child.Attr,
+			SrcFunc: &pb.SrcFunction{Name: approxCountDistinct},
+			DoCount: true,
+			ReadTs:  sg.ReadTs,
+		})
+		if err != nil && !strings.Contains(err.Error(), worker.ErrNonExistentTabletMessage) {
+			return err
+		}
+		child.setRootCount(result)
+	}
+	return nil
+}
+
+// checkApproximate returns an error unless the @approximate counts of the block can be taken
+// from the index and from the sketches of the predicates. That's the case of the blocks which
+// only request count(uid) of a function, without filtering or pagination, and the
+// approx_count_distinct of predicates.
+func (sg *SubGraph) checkApproximate() error {
+	for _, child := range sg.Children {
+		if child.SrcFunc == nil || child.SrcFunc.Name != approxCountDistinct ||
+			len(child.Params.NeedsVar) > 0 || len(child.Children) > 0 {
+			return errors.Errorf("@approximate blocks can only request count(uid) and" +
+				" approx_count_distinct of predicates")
+		}
+	}
+	if !sg.Params.uidCount && len(sg.Children) == 0 || sg.Params.isGroupBy ||
+		sg.Params.IsEmpty || sg.Params.Recurse || sg.Params.shortest {
+		return errors.Errorf("@approximate blocks can only request count(uid) and" +
+			" approx_count_distinct of predicates")
+	}
+	if sg.SrcFunc == nil || !approximateFuncs[sg.SrcFunc.Name] || sg.SrcFunc.IsCount ||
+		sg.SrcFunc.IsValueVar || sg.SrcFunc.IsLenVar {
+		return errors.Errorf("@approximate can't be used with this function at root")
+	}
+	if len(sg.Filters) > 0 || len(sg.Params.Order) > 0 || sg.Params.Count != 0 ||
+		sg.Params.Offset != 0 || sg.Params.AfterUID != 0 || sg.isSampled() {
+		return errors.Errorf("@approximate can't be used with filters, ordering, pagination" +
+			" or sampling")
+	}
+	return nil
+}
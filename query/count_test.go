@@ -0,0 +1,68 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+func TestSetRootCount(t *testing.T) {
+	sg := &SubGraph{}
+	sg.setRootCount(&pb.Result{Counts: []uint32{3, 1, 4}})
+	require.Equal(t, int64(8), sg.uidCountValue())
+	require.Empty(t, sg.DestUIDs.Uids)
+	require.Len(t, sg.uidMatrix, 1)
+
+	// The nodes of allofterms have all the tokens.
+	sg.setRootCount(&pb.Result{Counts: []uint32{3, 1, 4}, IntersectDest: true})
+	require.Equal(t, int64(1), sg.uidCountValue())
+	sg.setRootCount(&pb.Result{})
+	require.Equal(t, int64(0), sg.uidCountValue())
+
+	sg = &SubGraph{DestUIDs: &pb.List{Uids: []uint64{1, 2}}}
+	require.Equal(t, int64(2), sg.uidCountValue())
+}
+
+func TestCheckApproximate(t *testing.T) {
+	check := func(q string) error {
+		res, err := gql.Parse(gql.Request{Str: q})
+		require.NoError(t, err)
+		_, err = ToSubGraph(context.Background(), res.Query[0])
+		return err
+	}
+	require.NoError(t, check(`{ q(func: has(name)) @approximate { count(uid) } }`))
+	require.NoError(t, check(`{ q(func: anyofterms(name, "a b")) @approximate { c: count(uid) } }`))
+	require.NoError(t, check(`{ q(func: has(name)) @approximate { approx_count_distinct(name) } }`))
+
+	for _, q := range []string{
+		`{ q(func: has(name)) @approximate { count(uid) name } }`,
+		`{ q(func: has(name)) @approximate { name } }`,
+		`{ q(func: has(name)) @approximate { approx_count_distinct(name) age } }`,
+		`{ q(func: uid(1, 2)) @approximate { count(uid) } }`,
+		`{ q(func: regexp(name, /a/)) @approximate { count(uid) } }`,
+		`{ q(func: has(name)) @approximate @filter(has(age)) { count(uid) } }`,
+		`{ q(func: has(name), first: 10) @approximate { count(uid) } }`,
+	} {
+		require.Error(t, check(q), q)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/pkg/errors"
+)
+
+func isCustomFn(f string) bool {
+	return f == "custom"
+}
+
+// evalCustomFn resolves custom(resolver, val(a), ...) by posting the values of the variables
+// to the resolver. The nodes are sent as a JSON list like [{"uid": "0x1", "a": 3}], at most
+// x.Config.CustomResolverBatch at a time, and the resolver replies with the value of each
+// node keyed by uid, like {"0x1": "gold"}.
+func evalCustomFn(ctx context.Context, doneVars map[string]varValue,
+	sg *SubGraph) (map[uint64]types.Val, error) {
+
+	name := sg.SrcFunc.Args[0].Value
+	url, ok := x.Config.CustomResolvers[name]
+	if !ok {
+		return nil, errors.Errorf("Custom resolver %s is not defined", name)
+	}
+
+	uidSet := make(map[uint64]struct{})
+	for _, v := range sg.Params.NeedsVar {
+		for uid := range doneVars[v.Name].Vals {
+			uidSet[uid] = struct{}{}
+		}
+	}
+	uids := make([]uint64, 0, len(uidSet))
+	for uid := range uidSet {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	batch := x.Config.CustomResolverBatch
+	if batch <= 0 {
+		batch = len(uids)
+	}
+	mp := make(map[uint64]types.Val, len(uids))
+	for start := 0; start < len(uids); start += batch {
+		end := start + batch
+		if end > len(uids) {
+			end = len(uids)
+		}
+		nodes := make([]map[string]interface{}, 0, end-start)
+		for _, uid := range uids[start:end] {
+			node := map[string]interface{}{"uid": fmt.Sprintf("%#x", uid)}
+			for _, v := range sg.Params.NeedsVar {
+				if val, ok := doneVars[v.Name].Vals[uid]; ok && val.Value != nil {
+					node[v.Name] = val.Value
+				}
+			}
+			nodes = append(nodes, node)
+		}
+		if err := callCustomResolver(ctx, name, url, nodes, mp); err != nil {
+			return nil, err
+		}
+	}
+	return mp, nil
+}
+
+func callCustomResolver(ctx context.Context, name, url string, nodes []map[string]interface{},
+	mp map[uint64]types.Val) error {
+
+	body, err := json.Marshal(nodes)
+	if err != nil {
+		return errors.Wrapf(err, "while encoding the nodes for custom resolver %s", name)
+	}
+	x.ConfigMu.RLock()
+	timeout := x.Config.CustomResolverTimeout
+	x.ConfigMu.RUnlock()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrapf(err, "while calling custom resolver %s", name)
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "while calling custom resolver %s", name)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return errors.Errorf("Custom resolver %s returned status: %s", name, resp.Status)
+	}
+
+	var res map[string]interface{}
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&res); err != nil {
+		return errors.Wrapf(err, "while decoding the response of custom resolver %s", name)
+	}
+	for k, v := range res {
+		uid, err := strconv.ParseUint(k, 0, 64)
+		if err != nil {
+			return errors.Errorf("Custom resolver %s returned an invalid uid: %q", name, k)
+		}
+		val, err := customValue(v)
+		if err != nil {
+			return errors.Wrapf(err, "while reading the value for %s from custom resolver %s",
+				k, name)
+		}
+		if val.Value != nil {
+			mp[uid] = val
+		}
+	}
+	return nil
+}
+
+func customValue(v interface{}) (types.Val, error) {
+	switch v := v.(type) {
+	case nil:
+		return types.Val{}, nil
+	case string:
+		return types.Val{Tid: types.StringID, Value: v}, nil
+	case bool:
+		return types.Val{Tid: types.BoolID, Value: v}, nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return types.Val{Tid: types.IntID, Value: i}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return types.Val{}, err
+		}
+		return types.Val{Tid: types.FloatID, Value: f}, nil
+	}
+	return types.Val{}, errors.Errorf("Unsupported value of type %T", v)
+}
@@ -0,0 +1,99 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+)
+
+func withCustomResolver(h http.HandlerFunc) func() {
+	srv := httptest.NewServer(h)
+	old := x.Config
+	x.Config.CustomResolvers = map[string]string{"tiers": srv.URL}
+	x.Config.CustomResolverBatch = 2
+	x.Config.CustomResolverTimeout = time.Second
+	return func() {
+		x.Config = old
+		srv.Close()
+	}
+}
+
+func customTestSubGraph(resolver string) *SubGraph {
+	return &SubGraph{
+		SrcFunc: &Function{Name: "custom", Args: []gql.Arg{{Value: resolver}}},
+		Params:  params{NeedsVar: []gql.VarContext{{Name: "s", Typ: gql.ValueVar}}},
+	}
+}
+
+func TestCustomFn(t *testing.T) {
+	var calls int
+	defer withCustomResolver(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		var nodes []map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&nodes))
+		require.True(t, len(nodes) <= 2)
+		res := make(map[string]interface{})
+		for _, n := range nodes {
+			if n["s"].(float64) >= 30 {
+				res[n["uid"].(string)] = "gold"
+			} else {
+				res[n["uid"].(string)] = n["s"]
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	})()
+
+	mp, err := evalCustomFn(context.Background(), windowTestVars(), customTestSubGraph("tiers"))
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, map[uint64]types.Val{
+		1: {Tid: types.IntID, Value: int64(10)},
+		2: {Tid: types.StringID, Value: "gold"},
+		3: {Tid: types.IntID, Value: int64(20)},
+		4: {Tid: types.StringID, Value: "gold"},
+	}, mp)
+}
+
+func TestCustomFnErrors(t *testing.T) {
+	status := http.StatusOK
+	body := `{}`
+	defer withCustomResolver(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(body))
+	})()
+
+	_, err := evalCustomFn(context.Background(), windowTestVars(), customTestSubGraph("ranks"))
+	require.EqualError(t, err, "Custom resolver ranks is not defined")
+
+	status = http.StatusInternalServerError
+	_, err = evalCustomFn(context.Background(), windowTestVars(), customTestSubGraph("tiers"))
+	require.Contains(t, err.Error(), "returned status: 500")
+
+	status, body = http.StatusOK, `{"one": 1}`
+	_, err = evalCustomFn(context.Background(), windowTestVars(), customTestSubGraph("tiers"))
+	require.Contains(t, err.Error(), `returned an invalid uid: "one"`)
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+// dedupedNodesKey is the key of the result listing the nodes found more than once.
+const dedupedNodesKey = "nodes"
+
+// requestDedupeNodes tells if the nodes found more than once in the result must be listed
+// apart, as asked for through DedupeNodesKey or, for gRPC clients, the dedupe_nodes metadata.
+func requestDedupeNodes(ctx context.Context) bool {
+	return boolOption(ctx, DedupeNodesKey, "dedupe_nodes")
+}
+
+// nodeDeduper replaces the nodes found more than once in a result by references to their uids.
+type nodeDeduper struct {
+	counts  map[uint64]int
+	entries map[uint64]*fastJsonNode
+	order   []uint64
+}
+
+// countNodes counts how many times each node is found under fj.
+func countNodes(fj *fastJsonNode, counts map[uint64]int) {
+	for _, a := range fj.attrs {
+		if a.uid != 0 && len(a.attrs) > 0 {
+			counts[a.uid]++
+		}
+		countNodes(a, counts)
+	}
+}
+
+// dedupeNodes moves the nodes found more than once in the result of the blocks to a top-level
+// map keyed by their uids, and leaves references to their uids in their place. The facets of
+// the edges leading to the nodes stay in the references. When a node is found at several places
+// with the same field, the field is taken from the first place.
+func (fj *fastJsonNode) dedupeNodes() error {
+	for _, a := range fj.attrs {
+		if a.attr == dedupedNodesKey {
+			return errors.Errorf("The block name %q is reserved when deduping the nodes",
+				dedupedNodesKey)
+		}
+	}
+
+	d := &nodeDeduper{
+		counts:  make(map[uint64]int),
+		entries: make(map[uint64]*fastJsonNode),
+	}
+	countNodes(fj, d.counts)
+	d.rewrite(fj)
+
+	nodes := &fastJsonNode{attr: dedupedNodesKey}
+	for _, uid := range d.order {
+		nodes.attrs = append(nodes.attrs, d.entries[uid])
+	}
+	if len(nodes.attrs) == 0 {
+		nodes.scalarVal = []byte("{}")
+	}
+	fj.attrs = append(fj.attrs, nodes)
+	return nil
+}
+
+// rewrite replaces the nodes found more than once under fj by their references.
+func (d *nodeDeduper) rewrite(fj *fastJsonNode) {
+	for i, a := range fj.attrs {
+		if a.uid == 0 || d.counts[a.uid] < 2 {
+			d.rewrite(a)
+			continue
+		}
+		fj.attrs[i] = d.reference(a)
+	}
+}
+
+// reference adds the fields of the node n to its entry, and returns the reference to replace it.
+func (d *nodeDeduper) reference(n *fastJsonNode) *fastJsonNode {
+	uid := []byte(fmt.Sprintf("\"%#x\"", n.uid))
+	entry, ok := d.entries[n.uid]
+	if !ok {
+		entry = &fastJsonNode{attr: fmt.Sprintf("%#x", n.uid)}
+		entry.attrs = append(entry.attrs, makeScalarNode("uid", false, uid, false))
+		d.entries[n.uid] = entry
+		d.order = append(d.order, n.uid)
+	}
+	taken := make(map[string]bool, len(entry.attrs))
+	for _, a := range entry.attrs {
+		taken[a.attr] = true
+	}
+
+	ref := &fastJsonNode{attr: n.attr, isChild: n.isChild, list: n.list}
+	ref.attrs = append(ref.attrs, makeScalarNode("uid", false, uid, false))
+	added := &fastJsonNode{}
+	for _, a := range n.attrs {
+		switch {
+		case a.facet:
+			ref.attrs = append(ref.attrs, a)
+		case !taken[a.attr]:
+			added.attrs = append(added.attrs, a)
+		}
+	}
+	// The nodes under the node are deduped too, once they're part of its entry.
+	d.rewrite(added)
+	entry.attrs = append(entry.attrs, added.attrs...)
+	return ref
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// distinctFunc is the root function of the blocks listing the distinct values of an indexed
+// predicate, read from its index instead of from the nodes.
+const distinctFunc = "distinct"
+
+// distinctValue is a distinct value of the predicate of a distinct block, with the number of
+// nodes having it.
+type distinctValue struct {
+	val   types.Val
+	count uint32
+}
+
+// isDistinct tells if sg is a distinct block.
+func (sg *SubGraph) isDistinct() bool {
+	return sg.SrcFunc != nil && sg.SrcFunc.Name == distinctFunc
+}
+
+// checkDistinct returns an error unless the distinct block only requests count(uid), paginated
+// with first and offset.
+func (sg *SubGraph) checkDistinct() error {
+	if len(sg.Children) > 0 || len(sg.Filters) > 0 || len(sg.Params.Order) > 0 ||
+		sg.Params.Var != "" || sg.Params.AfterUID > 0 || sg.Params.isGroupBy ||
+		sg.Params.Recurse || sg.Params.Cascade || sg.Params.Normalize ||
+		sg.Params.Approximate || sg.Params.Facet != nil {
+		return errors.Errorf("A distinct block can only request count(uid), paginated with" +
+			" first and offset")
+	}
+	if sg.Params.Count < 0 {
+		return errors.Errorf("first can't be negative in a distinct block")
+	}
+	return nil
+}
+
+// processDistinct reads the distinct values of the predicate of the distinct block, and the
+// number of nodes having each, from the index of the predicate.
+func (sg *SubGraph) processDistinct(ctx context.Context) error {
+	sg.DestUIDs = &pb.List{}
+	sg.uidMatrix = []*pb.List{sg.DestUIDs}
+	result, err := worker.ProcessTaskOverNetwork(ctx, &pb.Query{
+		Attr: sg.Attr,
+		SrcFunc: &pb.SrcFunction{
+			Name: distinctFunc,
+			Args: []string{strconv.Itoa(sg.Params.Offset), strconv.Itoa(sg.Params.Count)},
+		},
+		ReadTs: sg.ReadTs,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), worker.ErrNonExistentTabletMessage) {
+			return nil
+		}
+		return err
+	}
+	if len(result.ValueMatrix) == 0 {
+		return nil
+	}
+	for i, tv := range result.ValueMatrix[0].Values {
+		val, err := convertTo(tv)
+		if err != nil {
+			return err
+		}
+		dv := distinctValue{val: val}
+		if i < len(result.Counts) {
+			dv.count = result.Counts[i]
+		}
+		sg.distinct = append(sg.distinct, dv)
+	}
+	return nil
+}
+
+// addDistinctValues adds the values of the distinct block to fj, each keyed by the predicate,
+// with the number of nodes having it if the block requests count(uid).
+func (fj *fastJsonNode) addDistinctValues(sg *SubGraph, tr *traversal) error {
+	if err := tr.checkFanout(len(sg.distinct)); err != nil {
+		return err
+	}
+	countField := sg.Params.uidCountAlias
+	if countField == "" {
+		countField = "count"
+	}
+	for _, dv := range sg.distinct {
+		n := fj.New(sg.Params.Alias)
+		n.AddValue(sg.Attr, dv.val)
+		if sg.Params.uidCount {
+			c := types.ValueForType(types.IntID)
+			c.Value = int64(dv.count)
+			n.AddValue(countField, c)
+		}
+		fj.AddListChild(sg.Params.Alias, n)
+	}
+	if len(sg.distinct) == 0 {
+		// So that we return an empty key if the predicate has no values.
+		fj.AddListChild(sg.Params.Alias, &fastJsonNode{})
+	}
+	return nil
+}
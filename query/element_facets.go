@@ -0,0 +1,121 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/types/facets"
+)
+
+// requestElementFacets tells if the values of list predicates queried with their facets must be
+// encoded as objects pairing each value with its facets, as asked for through ElementFacetsKey
+// or, for gRPC clients, the element_facets metadata.
+func requestElementFacets(ctx context.Context) bool {
+	return boolOption(ctx, ElementFacetsKey, "element_facets")
+}
+
+// hasElementFacets tells if the values of pc for the node at idx have a facets each, as the
+// untagged values of list predicates do.
+func (pc *SubGraph) hasElementFacets(idx int) bool {
+	return pc.List && len(pc.Params.Langs) == 0 && !pc.Params.expandAll &&
+		pc.Params.Facet != nil && len(pc.facetsMatrix) > idx &&
+		len(pc.valueMatrix) > idx &&
+		len(pc.facetsMatrix[idx].FacetsList) == len(pc.valueMatrix[idx].Values)
+}
+
+// addValueFacets adds the facets of the values of pc for the node at idx to dst. The facets of
+// a single value are added as pred|facet, and the ones of the elements of a list as objects
+// mapping the index of each element having the facet to its value.
+func addValueFacets(pc *SubGraph, idx int, fieldName string, dst outputNode) error {
+	if len(pc.facetsMatrix) <= idx || len(pc.facetsMatrix[idx].FacetsList) == 0 {
+		return nil
+	}
+	fl := pc.facetsMatrix[idx].FacetsList
+	if !pc.hasElementFacets(idx) {
+		for _, f := range fl[0].Facets {
+			fVal, err := facets.ValFor(f)
+			if err != nil {
+				return err
+			}
+			dst.AddValue(facetName(fieldName, f), fVal)
+		}
+		return nil
+	}
+
+	// The values of each facet are gathered first, as the children of dst are written one at
+	// a time when the result is streamed.
+	type elemVal struct {
+		idx int
+		val types.Val
+	}
+	var names []string
+	byName := make(map[string][]elemVal)
+	for i, fs := range fl {
+		for _, f := range fs.Facets {
+			fVal, err := facets.ValFor(f)
+			if err != nil {
+				return err
+			}
+			name := facetName(fieldName, f)
+			if _, ok := byName[name]; !ok {
+				names = append(names, name)
+			}
+			byName[name] = append(byName[name], elemVal{idx: i, val: fVal})
+		}
+	}
+	for _, name := range names {
+		n := dst.NewChild(name, false)
+		for _, ev := range byName[name] {
+			n.AddValue(strconv.Itoa(ev.idx), ev.val)
+		}
+		dst.AddMapChild(name, n, false)
+	}
+	return nil
+}
+
+// addElementValues adds the values of the list predicate pc for the node at idx to dst as a
+// list of objects, each holding a value under "value" and its facets under "facets".
+func addElementValues(pc *SubGraph, idx int, fieldName string, dst outputNode) error {
+	for i, tv := range pc.valueMatrix[idx].Values {
+		sv, err := convertWithBestEffort(tv, pc.Attr)
+		if err != nil {
+			return err
+		}
+		elem := dst.NewChild(fieldName, true)
+		elem.AddValue("value", sv)
+		fs := elem.NewChild("facets", false)
+		for _, f := range pc.facetsMatrix[idx].FacetsList[i].Facets {
+			fVal, err := facets.ValFor(f)
+			if err != nil {
+				return err
+			}
+			name := f.Key
+			if f.Alias != "" {
+				name = f.Alias
+			}
+			fs.AddValue(name, fVal)
+		}
+		if !fs.IsEmpty() {
+			elem.AddMapChild("facets", fs, false)
+		}
+		dst.AddListChild(fieldName, elem)
+	}
+	return nil
+}
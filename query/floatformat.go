@@ -0,0 +1,108 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"math"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// FloatFormat controls how the float values of a result are written.
+type FloatFormat struct {
+	// Precision is the number of digits after the decimal point, or -1 for the fewest digits
+	// reading back as the same value.
+	Precision int
+	// NonFinite is how NaN and infinite values are written, as JSON doesn't allow them: "null",
+	// or "string" for "NaN", "Infinity" and "-Infinity".
+	NonFinite string
+}
+
+// DefaultFloatFormat writes floats with the fewest digits reading back as the same value, and
+// NaN and infinite values as null.
+var DefaultFloatFormat = FloatFormat{Precision: -1, NonFinite: "null"}
+
+// ParseFloatFormat returns the format asked for by the precision and nonFinite options. An
+// empty option keeps the default.
+func ParseFloatFormat(precision, nonFinite string) (FloatFormat, error) {
+	ff := DefaultFloatFormat
+	if precision != "" {
+		p, err := strconv.Atoi(precision)
+		if err != nil || p < -1 || p > 64 {
+			return ff, errors.Errorf("Invalid float precision %q: expected -1 to 64", precision)
+		}
+		ff.Precision = p
+	}
+	switch nonFinite {
+	case "":
+	case "null", "string":
+		ff.NonFinite = nonFinite
+	default:
+		return ff, errors.Errorf("Invalid non-finite float policy %q: expected null or string",
+			nonFinite)
+	}
+	return ff, nil
+}
+
+// requestFloatFormat returns the float format of the request, given either through
+// FloatFormatKey or, for gRPC clients, the float_precision and non_finite metadata.
+func requestFloatFormat(ctx context.Context) FloatFormat {
+	ff, ok := ctx.Value(FloatFormatKey).(FloatFormat)
+	if !ok {
+		ff = DefaultFloatFormat
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		var precision, nonFinite string
+		if len(md["float_precision"]) > 0 {
+			precision = md["float_precision"][0]
+		}
+		if len(md["non_finite"]) > 0 {
+			nonFinite = md["non_finite"][0]
+		}
+		// Invalid values are ignored and the default format applies.
+		if precision != "" || nonFinite != "" {
+			if f, err := ParseFloatFormat(precision, nonFinite); err == nil {
+				ff = f
+			}
+		}
+	}
+	return ff
+}
+
+// appendFloat appends the JSON of f to dst.
+func (ff FloatFormat) appendFloat(dst []byte, f float64) []byte {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		if ff.NonFinite != "string" {
+			return append(dst, "null"...)
+		}
+		switch {
+		case math.IsNaN(f):
+			return append(dst, `"NaN"`...)
+		case f > 0:
+			return append(dst, `"Infinity"`...)
+		default:
+			return append(dst, `"-Infinity"`...)
+		}
+	}
+	if ff.Precision < 0 {
+		return strconv.AppendFloat(dst, f, 'g', -1, 64)
+	}
+	return strconv.AppendFloat(dst, f, 'f', ff.Precision, 64)
+}
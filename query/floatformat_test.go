@@ -0,0 +1,78 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestFloatFormat(t *testing.T) {
+	format := func(ff FloatFormat, f float64) string {
+		bs, err := valToBytes(types.Val{Tid: types.FloatID, Value: f}, ff, BinaryFormat{})
+		require.NoError(t, err)
+		return string(bs)
+	}
+
+	require.Equal(t, "0.1", format(DefaultFloatFormat, 0.1))
+	require.Equal(t, "123456.789", format(DefaultFloatFormat, 123456.789))
+	require.Equal(t, "1e+21", format(DefaultFloatFormat, 1e21))
+	require.Equal(t, "null", format(DefaultFloatFormat, math.NaN()))
+	require.Equal(t, "null", format(DefaultFloatFormat, math.Inf(-1)))
+
+	ff := FloatFormat{Precision: 2, NonFinite: "string"}
+	require.Equal(t, "0.10", format(ff, 0.1))
+	require.Equal(t, `"NaN"`, format(ff, math.NaN()))
+	require.Equal(t, `"Infinity"`, format(ff, math.Inf(1)))
+	require.Equal(t, `"-Infinity"`, format(ff, math.Inf(-1)))
+}
+
+func TestParseFloatFormat(t *testing.T) {
+	ff, err := ParseFloatFormat("", "")
+	require.NoError(t, err)
+	require.Equal(t, DefaultFloatFormat, ff)
+
+	ff, err = ParseFloatFormat("3", "string")
+	require.NoError(t, err)
+	require.Equal(t, FloatFormat{Precision: 3, NonFinite: "string"}, ff)
+
+	_, err = ParseFloatFormat("-2", "")
+	require.Error(t, err)
+	_, err = ParseFloatFormat("", "zero")
+	require.Error(t, err)
+}
+
+func TestRequestFloatFormat(t *testing.T) {
+	require.Equal(t, DefaultFloatFormat, requestFloatFormat(context.Background()))
+
+	ff := FloatFormat{Precision: 1, NonFinite: "null"}
+	ctx := context.WithValue(context.Background(), FloatFormatKey, ff)
+	require.Equal(t, ff, requestFloatFormat(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("float_precision", "4", "non_finite", "string"))
+	require.Equal(t, FloatFormat{Precision: 4, NonFinite: "string"}, requestFloatFormat(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("float_precision", "bad"))
+	require.Equal(t, DefaultFloatFormat, requestFloatFormat(ctx))
+}
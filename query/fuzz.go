@@ -0,0 +1,231 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dgryski/go-farm"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// The fuzz targets of the filters and of the JSON encoder. The go-fuzz entry points calling
+// them are in fuzz_gofuzz.go, and the tests replay their corpus in fuzz-data.
+
+const (
+	fuzzInteresting = 1
+	fuzzNormal      = 0
+)
+
+// fuzzFilterUids are the uids the fuzzed filters are applied to.
+var fuzzFilterUids = []uint64{1, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+
+// fuzzFilter parses in as the @filter of a query and applies it to fuzzFilterUids, with the
+// results of its functions made up from their hash. It panics if the uids kept aren't a sorted
+// subset of fuzzFilterUids.
+func fuzzFilter(in []byte) int {
+	res, err := gql.Parse(gql.Request{
+		Str: fmt.Sprintf("{ q(func: uid(1)) @filter(%s) { uid } }", in),
+	})
+	if err != nil || len(res.Query) == 0 || res.Query[0].Filter == nil {
+		return fuzzNormal
+	}
+	sg := &SubGraph{}
+	if err := filterCopy(sg, res.Query[0].Filter); err != nil {
+		return fuzzNormal
+	}
+	uids := &pb.List{Uids: fuzzFilterUids}
+	kept := applyFuzzFilter(sg, uids)
+	for i, uid := range kept.Uids {
+		if i > 0 && kept.Uids[i-1] >= uid {
+			panic(fmt.Sprintf("The uids kept by the filter aren't sorted: %v", kept.Uids))
+		}
+		if algo.IndexOf(uids, uid) < 0 {
+			panic(fmt.Sprintf("The filter kept uid %d, which it wasn't applied to", uid))
+		}
+	}
+	return fuzzInteresting
+}
+
+// applyFuzzFilter applies the filter to the uids as ProcessGraph does, with the result of each
+// function made up from its hash.
+func applyFuzzFilter(sg *SubGraph, uids *pb.List) *pb.List {
+	if len(sg.FilterOp) == 0 {
+		h := farm.Fingerprint64([]byte(fmt.Sprintf("%s %+v", sg.Attr, sg.SrcFunc)))
+		out := &pb.List{}
+		for i, uid := range uids.Uids {
+			if h&(1<<uint(i%64)) != 0 {
+				out.Uids = append(out.Uids, uid)
+			}
+		}
+		sg.DestUIDs = out
+	} else {
+		sg.DestUIDs = uids
+	}
+	for _, filter := range sg.Filters {
+		applyFuzzFilter(filter, uids)
+	}
+	if len(sg.Filters) > 0 {
+		sg.applyFilterResults()
+	}
+	return sg.DestUIDs
+}
+
+// Operations of the programs of fuzzEncode.
+const (
+	fuzzOpString = iota
+	fuzzOpInt
+	fuzzOpFloat
+	fuzzOpBool
+	fuzzOpDateTime
+	fuzzOpUid
+	fuzzOpChild
+	fuzzOpEnd
+	fuzzOpCount
+)
+
+// fuzzReader reads the program of fuzzEncode.
+type fuzzReader struct {
+	in []byte
+}
+
+func (r *fuzzReader) byte() byte {
+	if len(r.in) == 0 {
+		return 0
+	}
+	b := r.in[0]
+	r.in = r.in[1:]
+	return b
+}
+
+func (r *fuzzReader) bytes() []byte {
+	n := int(r.byte())
+	if n > len(r.in) {
+		n = len(r.in)
+	}
+	b := r.in[:n]
+	r.in = r.in[n:]
+	return b
+}
+
+func (r *fuzzReader) uint64() uint64 {
+	var b [8]byte
+	copy(b[:], r.bytes())
+	return binary.LittleEndian.Uint64(b[:])
+}
+
+// fuzzEncode runs in as a program adding values and children to a tree of fastJsonNode, the way
+// the results of a query are built, and encodes it. It panics if the JSON isn't valid.
+func fuzzEncode(in []byte) int {
+	r := &fuzzReader{in: in}
+	root := &fastJsonNode{attr: "_root_", intern: newScalarInterner(DefaultFloatFormat,
+		BinaryFormat{})}
+	type frame struct {
+		node *fastJsonNode
+		attr string
+		list bool
+	}
+	stack := []frame{{node: root}}
+	end := func() {
+		top := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		// Empty children are never added to the results.
+		if top.node.IsEmpty() {
+			return
+		}
+		parent := stack[len(stack)-1].node
+		if top.list {
+			parent.AddListChild(top.attr, top.node)
+		} else {
+			parent.AddMapChild(top.attr, top.node, false)
+		}
+	}
+
+	for len(r.in) > 0 {
+		op := r.byte() % fuzzOpCount
+		node := stack[len(stack)-1].node
+		switch op {
+		case fuzzOpString:
+			attr := string(r.bytes())
+			node.AddValue(attr, types.Val{Tid: types.StringID, Value: string(r.bytes())})
+		case fuzzOpInt:
+			attr := string(r.bytes())
+			node.AddValue(attr, types.Val{Tid: types.IntID, Value: int64(r.uint64())})
+		case fuzzOpFloat:
+			attr := string(r.bytes())
+			node.AddValue(attr, types.Val{
+				Tid: types.FloatID, Value: math.Float64frombits(r.uint64())})
+		case fuzzOpBool:
+			attr := string(r.bytes())
+			node.AddListValue(attr, types.Val{Tid: types.BoolID, Value: r.byte()%2 == 0},
+				r.byte()%2 == 0)
+		case fuzzOpDateTime:
+			attr := string(r.bytes())
+			t := time.Unix(int64(r.uint64()%(1<<40)), 0).UTC()
+			node.AddValue(attr, types.Val{Tid: types.DateTimeID, Value: t})
+		case fuzzOpUid:
+			node.SetUID(r.uint64(), "uid")
+		case fuzzOpChild:
+			attr := string(r.bytes())
+			stack = append(stack, frame{
+				node: node.New(attr).(*fastJsonNode), attr: attr, list: r.byte()%2 == 0})
+		case fuzzOpEnd:
+			if len(stack) > 1 {
+				end()
+			}
+		}
+	}
+	for len(stack) > 1 {
+		end()
+	}
+
+	var buf bytes.Buffer
+	if root.IsEmpty() {
+		return fuzzNormal
+	}
+	root.encode(&buf)
+	if err := checkFuzzJSON(buf.Bytes()); err != nil {
+		panic(fmt.Sprintf("%v: %q", err, buf.Bytes()))
+	}
+	return fuzzInteresting
+}
+
+// checkFuzzJSON returns an error unless out is a valid JSON object, which decodes and encodes
+// back.
+func checkFuzzJSON(out []byte) error {
+	if !json.Valid(out) {
+		return errors.New("The encoded JSON isn't valid")
+	}
+	var v map[string]interface{}
+	if err := json.Unmarshal(out, &v); err != nil {
+		return errors.Wrapf(err, "while decoding the encoded JSON")
+	}
+	if _, err := json.Marshal(v); err != nil {
+		return errors.Wrapf(err, "while encoding the decoded JSON")
+	}
+	return nil
+}
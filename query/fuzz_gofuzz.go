@@ -0,0 +1,37 @@
+// +build gofuzz
+
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+// Filter and JSON encoder fuzzers for use with https://github.com/dvyukov/go-fuzz.
+//
+// Build: go-fuzz-build -func FuzzFilter -o query-filter-fuzz.zip github.com/dgraph-io/dgraph/query
+//        go-fuzz-build -func FuzzEncode -o query-encode-fuzz.zip github.com/dgraph-io/dgraph/query
+//
+// Run: go-fuzz -bin=./query-filter-fuzz.zip -workdir fuzz-data/filter
+//      go-fuzz -bin=./query-encode-fuzz.zip -workdir fuzz-data/encode
+
+// FuzzFilter fuzzes the parsing and the evaluation of filters.
+func FuzzFilter(in []byte) int {
+	return fuzzFilter(in)
+}
+
+// FuzzEncode fuzzes the JSON encoding of results.
+func FuzzEncode(in []byte) int {
+	return fuzzEncode(in)
+}
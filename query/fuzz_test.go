@@ -0,0 +1,63 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// replayFuzzCorpus runs fuzz on each input of the corpus in dir, failing the test on panics.
+func replayFuzzCorpus(t *testing.T, dir string, fuzz func([]byte) int) {
+	files, err := filepath.Glob(filepath.Join(dir, "corpus", "*"))
+	require.NoError(t, err)
+	require.NotEmpty(t, files)
+	for _, file := range files {
+		in, err := ioutil.ReadFile(file)
+		require.NoError(t, err)
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					t.Errorf("panic caused by %s, input: %q: %v\n%s", file, in, r, debug.Stack())
+				}
+			}()
+			fuzz(in)
+		}()
+	}
+}
+
+func TestFilterFuzzCorpus(t *testing.T) {
+	replayFuzzCorpus(t, "fuzz-data/filter", fuzzFilter)
+}
+
+func TestEncodeFuzzCorpus(t *testing.T) {
+	replayFuzzCorpus(t, "fuzz-data/encode", fuzzEncode)
+}
+
+func TestWriteJSONKeyEscapes(t *testing.T) {
+	for _, key := range []string{"name", "a\"b", "a\\b", "<a\U0001F600>", "a\nb\x00"} {
+		var buf bytes.Buffer
+		writeJSONKey(&buf, key)
+		buf.WriteString(`:1}`)
+		require.NoError(t, checkFuzzJSON(append([]byte("{"), buf.Bytes()...)), key)
+	}
+}
@@ -0,0 +1,154 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// histogramFunc is the aggregation counting the values of a predicate of the nodes of a root
+// block in buckets, which the worker computes from the index where it can.
+const histogramFunc = "histogram"
+
+// histogramBucket is a bucket of a histogram, holding the values from from up to to excluded.
+type histogramBucket struct {
+	from  types.Val
+	to    types.Val
+	count uint32
+}
+
+// isHistogram tells if sg is a histogram of a predicate of the nodes of its parent.
+func (sg *SubGraph) isHistogram() bool {
+	return sg.SrcFunc != nil && sg.SrcFunc.Name == histogramFunc
+}
+
+// hasHistograms tells if histograms are requested in the block sg.
+func (sg *SubGraph) hasHistograms() bool {
+	for _, child := range sg.Children {
+		if child.isHistogram() {
+			return true
+		}
+	}
+	return false
+}
+
+// checkHistograms returns an error if a histogram is requested below the root of the block sg,
+// or in a block without nodes of its own to count.
+func (sg *SubGraph) checkHistograms() error {
+	var nested func(sg *SubGraph) error
+	nested = func(sg *SubGraph) error {
+		for _, child := range sg.Children {
+			if child.isHistogram() {
+				return errors.Errorf("histogram can only be used at the root of a block")
+			}
+			if err := nested(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, child := range sg.Children {
+		if child.isHistogram() && (sg.Params.IsEmpty || sg.Params.Recurse ||
+			sg.Params.isGroupBy || sg.Params.Normalize || sg.Params.Cascade) {
+			return errors.Errorf("histogram can't be used in empty, @recurse, @groupby," +
+				" @normalize and @cascade blocks")
+		}
+		if err := nested(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processHistogram counts the values of the predicate of the nodes of the parent in the buckets
+// of the histogram sg.
+func (sg *SubGraph) processHistogram(ctx context.Context) error {
+	srcUids := sg.SrcUIDs
+	if srcUids == nil {
+		srcUids = &pb.List{}
+	}
+	args := make([]string, 0, len(sg.SrcFunc.Args))
+	for _, arg := range sg.SrcFunc.Args {
+		args = append(args, arg.Value)
+	}
+	result, err := worker.ProcessTaskOverNetwork(ctx, &pb.Query{
+		Attr:    sg.Attr,
+		UidList: srcUids,
+		SrcFunc: &pb.SrcFunction{Name: histogramFunc, Args: args},
+		ReadTs:  sg.ReadTs,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), worker.ErrNonExistentTabletMessage) {
+			return nil
+		}
+		return err
+	}
+	if len(result.ValueMatrix) == 0 {
+		return nil
+	}
+	bounds := result.ValueMatrix[0].Values
+	for i := 0; i+1 < len(bounds) && i < len(result.Counts); i++ {
+		from, err := convertTo(bounds[i])
+		if err != nil {
+			return err
+		}
+		to, err := convertTo(bounds[i+1])
+		if err != nil {
+			return err
+		}
+		sg.histogram = append(sg.histogram,
+			histogramBucket{from: from, to: to, count: result.Counts[i]})
+	}
+	return nil
+}
+
+// addHistograms adds the histograms requested in the block sg to fj, each as a list of its
+// buckets.
+func (fj *fastJsonNode) addHistograms(sg *SubGraph) {
+	for _, child := range sg.Children {
+		if !child.isHistogram() {
+			continue
+		}
+		fieldName := child.Params.Alias
+		if fieldName == "" {
+			fieldName = fmt.Sprintf("%s(%s)", histogramFunc, child.Attr)
+		}
+		n := fj.New(sg.Params.Alias)
+		for _, b := range child.histogram {
+			bucket := n.New(fieldName)
+			bucket.AddValue("from", b.from)
+			bucket.AddValue("to", b.to)
+			c := types.ValueForType(types.IntID)
+			c.Value = int64(b.count)
+			bucket.AddValue("count", c)
+			n.AddListChild(fieldName, bucket)
+		}
+		if len(child.histogram) == 0 {
+			// So that we return an empty key if the predicate has no values.
+			n.AddListChild(fieldName, &fastJsonNode{})
+		}
+		fj.AddListChild(sg.Params.Alias, n)
+	}
+}
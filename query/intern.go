@@ -0,0 +1,72 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// maxInternedScalars bounds the number of values kept by a scalarInterner, so that
+// predicates with many distinct values don't fill it for nothing.
+const maxInternedScalars = 1 << 14
+
+type internKey struct {
+	attr  string
+	tid   types.TypeID
+	value interface{}
+}
+
+// scalarInterner encodes the scalar values of a response. It reuses the encoded value of the
+// (attr, value) pairs repeated in the response, so that low-cardinality predicates like status
+// flags or country codes don't get a new byte slice for every node. The slices are shared and
+// must not be modified.
+type scalarInterner struct {
+	vals   map[internKey][]byte
+	floats FloatFormat
+	binary BinaryFormat
+}
+
+func newScalarInterner(floats FloatFormat, binary BinaryFormat) *scalarInterner {
+	if floats == (FloatFormat{}) {
+		// The format wasn't set, as for a SubGraph not made by newGraph.
+		floats = DefaultFloatFormat
+	}
+	return &scalarInterner{vals: make(map[internKey][]byte), floats: floats, binary: binary}
+}
+
+// valToBytes is like valToBytes, returning the same slice for the same attr and value.
+func (si *scalarInterner) valToBytes(attr string, v types.Val) ([]byte, error) {
+	if si == nil {
+		return valToBytes(v, DefaultFloatFormat, BinaryFormat{})
+	}
+	// Only the values that can be used as map keys are interned.
+	switch v.Value.(type) {
+	case string, int64, bool:
+	default:
+		return valToBytes(v, si.floats, si.binary)
+	}
+
+	key := internKey{attr: attr, tid: v.Tid, value: v.Value}
+	if bs, ok := si.vals[key]; ok {
+		return bs, nil
+	}
+	bs, err := valToBytes(v, si.floats, si.binary)
+	if err == nil && len(si.vals) < maxInternedScalars {
+		si.vals[key] = bs
+	}
+	return bs, err
+}
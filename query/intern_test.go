@@ -0,0 +1,50 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScalarInterner(t *testing.T) {
+	root := &fastJsonNode{intern: newScalarInterner(DefaultFloatFormat, BinaryFormat{})}
+	a := root.New("a").(*fastJsonNode)
+	b := root.New("b").(*fastJsonNode)
+	a.AddValue("country", types.Val{Tid: types.StringID, Value: "FR"})
+	b.AddValue("country", types.Val{Tid: types.StringID, Value: "FR"})
+	b.AddValue("code", types.Val{Tid: types.StringID, Value: "FR"})
+	require.Equal(t, `"FR"`, string(a.attrs[0].scalarVal))
+	require.True(t, &a.attrs[0].scalarVal[0] == &b.attrs[0].scalarVal[0])
+	// Values are only shared for the same predicate.
+	require.False(t, &a.attrs[0].scalarVal[0] == &b.attrs[1].scalarVal[0])
+
+	// Values which can't be map keys are encoded every time.
+	bin := types.Val{Tid: types.BinaryID, Value: []byte("x")}
+	bs, err := root.intern.valToBytes("bin", bin)
+	require.NoError(t, err)
+	require.Equal(t, `"eA=="`, string(bs))
+	require.Len(t, root.intern.vals, 2)
+
+	// A nil interner encodes every value.
+	var none *scalarInterner
+	bs, err = none.valToBytes("country", types.Val{Tid: types.IntID, Value: int64(3)})
+	require.NoError(t, err)
+	require.Equal(t, "3", string(bs))
+}
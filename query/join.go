@@ -0,0 +1,184 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+const (
+	joinLeft  = "left"
+	joinRight = "right"
+)
+
+// joinPair is a node of each side of a join block, whose values are equal.
+type joinPair struct {
+	left  uint64
+	right uint64
+}
+
+// joinCopy converts the children of the join block gq, which are the left and right sides of
+// the pairs, into the children of sg. Each side is queried like the root of a block, from the
+// nodes paired on that side.
+func joinCopy(ctx context.Context, gq *gql.GraphQuery, sg *SubGraph) error {
+	if gq.Var != "" || gq.Filter != nil || len(gq.Args) > 0 || len(gq.Order) > 0 ||
+		gq.Cascade || gq.Normalize || gq.IsGroupby || gq.Recurse {
+		return errors.Errorf("A join block can't have a variable, a filter, arguments or" +
+			" directives")
+	}
+	seen := make(map[string]bool)
+	for _, child := range gq.Children {
+		if child.Attr != joinLeft && child.Attr != joinRight {
+			return errors.Errorf("A join block only accepts %s and %s, got: %s",
+				joinLeft, joinRight, child.Attr)
+		}
+		if seen[child.Attr] {
+			return errors.Errorf("%s not allowed multiple times in a join block", child.Attr)
+		}
+		seen[child.Attr] = true
+		if definesVars(child) {
+			return errors.Errorf("Variables can't be defined in the %s of a join", child.Attr)
+		}
+
+		side, err := ToSubGraph(ctx, &gql.GraphQuery{
+			Alias:    child.Attr,
+			Func:     &gql.Function{Name: "uid"},
+			Args:     make(map[string]string),
+			Children: child.Children,
+		})
+		if err != nil {
+			return err
+		}
+		sg.Children = append(sg.Children, side)
+	}
+	return nil
+}
+
+// definesVars tells if a variable is defined in gq or under it.
+func definesVars(gq *gql.GraphQuery) bool {
+	if gq.Var != "" || len(gq.FacetVar) > 0 {
+		return true
+	}
+	for _, child := range gq.Children {
+		if definesVars(child) {
+			return true
+		}
+	}
+	return false
+}
+
+// joinKey returns the value the nodes are paired on. The values are compared by their string
+// form, so that the values of predicates of different types can be joined.
+func joinKey(v types.Val) (string, bool) {
+	if v.Tid == types.UidID {
+		return strconv.FormatUint(v.Value.(uint64), 10), true
+	}
+	key := types.Val{Tid: types.StringID, Value: ""}
+	if err := types.Marshal(v, &key); err != nil {
+		return "", false
+	}
+	return key.Value.(string), true
+}
+
+// processJoin pairs the nodes of the left and right value variables of the join block whose
+// values are equal, and then processes each side from the nodes paired on that side.
+func (sg *SubGraph) processJoin(ctx context.Context, vars map[string]varValue) error {
+	args := sg.Params.JoinArgs
+	right := make(map[string][]uint64)
+	for uid, v := range vars[args.Right].Vals {
+		if key, ok := joinKey(v); ok {
+			right[key] = append(right[key], uid)
+		}
+	}
+	for uid, v := range vars[args.Left].Vals {
+		key, ok := joinKey(v)
+		if !ok {
+			continue
+		}
+		for _, r := range right[key] {
+			sg.joinPairs = append(sg.joinPairs, joinPair{left: uid, right: r})
+		}
+	}
+	sort.Slice(sg.joinPairs, func(i, j int) bool {
+		a, b := sg.joinPairs[i], sg.joinPairs[j]
+		return a.left < b.left || (a.left == b.left && a.right < b.right)
+	})
+
+	for _, side := range sg.Children {
+		seen := make(map[uint64]bool)
+		var uids []uint64
+		for _, p := range sg.joinPairs {
+			uid := p.left
+			if side.Params.Alias == joinRight {
+				uid = p.right
+			}
+			if !seen[uid] {
+				seen[uid] = true
+				uids = append(uids, uid)
+			}
+		}
+		if len(uids) == 0 {
+			continue
+		}
+		if err := side.populate(uids); err != nil {
+			return err
+		}
+		rch := make(chan error, 1)
+		ProcessGraph(ctx, side, nil, rch)
+		if err := <-rch; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addJoinPairs adds the pairs of nodes of the join block to fj, each with its left and right
+// nodes.
+func (fj *fastJsonNode) addJoinPairs(sg *SubGraph, tr *traversal) error {
+	if err := tr.checkFanout(len(sg.joinPairs)); err != nil {
+		return err
+	}
+	for _, p := range sg.joinPairs {
+		pair := fj.New(sg.Params.Alias)
+		for _, side := range sg.Children {
+			uid := p.left
+			if side.Params.Alias == joinRight {
+				uid = p.right
+			}
+			n := fj.New(side.Params.Alias)
+			n.(*fastJsonNode).uid = uid
+			n.SetUID(uid, "uid")
+			if err := side.preTraverse(tr, uid, n); err != nil && err != errInvalidUid {
+				return err
+			}
+			pair.AddMapChild(side.Params.Alias, n, false)
+		}
+		fj.AddListChild(sg.Params.Alias, pair)
+	}
+	if len(sg.joinPairs) == 0 {
+		// So that we return an empty key if nothing was paired.
+		fj.AddListChild(sg.Params.Alias, &fastJsonNode{})
+	}
+	return nil
+}
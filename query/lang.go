@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// ParseLangs returns the default language chain of a request from its lang option: languages
+// in preference order separated by commas or colons, which may end with the wildcard ".". The
+// @lang predicates queried without a language follow the chain, then fall back to the value
+// without language unless the chain ends with the wildcard. An empty option returns no chain.
+func ParseLangs(option string) ([]string, error) {
+	if option == "" {
+		return nil, nil
+	}
+	langs := strings.FieldsFunc(option, func(r rune) bool { return r == ',' || r == ':' })
+	for i, lang := range langs {
+		lang = strings.TrimSpace(lang)
+		switch {
+		case lang == ".":
+			if i != len(langs)-1 {
+				return nil, errors.Errorf("Invalid lang %q: the wildcard . must be last", option)
+			}
+		case !isLangTag(lang):
+			return nil, errors.Errorf("Invalid lang %q: %q isn't a language tag", option, lang)
+		}
+		langs[i] = lang
+	}
+	if len(langs) == 0 {
+		return nil, errors.Errorf("Invalid lang %q: no language given", option)
+	}
+	if langs[len(langs)-1] != "." {
+		// The empty language stands for the value without language.
+		langs = append(langs, "")
+	}
+	return langs, nil
+}
+
+func isLangTag(lang string) bool {
+	if lang == "" {
+		return false
+	}
+	for _, r := range lang {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// requestLangs returns the default language chain of the request, given either through
+// LangKey or, for gRPC clients, the lang metadata.
+func requestLangs(ctx context.Context) []string {
+	langs, _ := ctx.Value(LangKey).([]string)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md["lang"]) > 0 {
+		// An invalid chain is ignored and no default applies.
+		if l, err := ParseLangs(md["lang"][0]); err == nil {
+			langs = l
+		}
+	}
+	return langs
+}
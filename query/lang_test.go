@@ -0,0 +1,61 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestParseLangs(t *testing.T) {
+	langs, err := ParseLangs("")
+	require.NoError(t, err)
+	require.Nil(t, langs)
+
+	langs, err = ParseLangs("fr")
+	require.NoError(t, err)
+	require.Equal(t, []string{"fr", ""}, langs)
+
+	langs, err = ParseLangs("fr, en-GB:de")
+	require.NoError(t, err)
+	require.Equal(t, []string{"fr", "en-GB", "de", ""}, langs)
+
+	langs, err = ParseLangs("fr,.")
+	require.NoError(t, err)
+	require.Equal(t, []string{"fr", "."}, langs)
+
+	for _, option := range []string{",", ".,fr", "*", "fr,e n", "fr;en"} {
+		_, err = ParseLangs(option)
+		require.Error(t, err, option)
+	}
+}
+
+func TestRequestLangs(t *testing.T) {
+	require.Nil(t, requestLangs(context.Background()))
+
+	ctx := context.WithValue(context.Background(), LangKey, []string{"en", ""})
+	require.Equal(t, []string{"en", ""}, requestLangs(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("lang", "de,."))
+	require.Equal(t, []string{"de", "."}, requestLangs(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("lang", "*"))
+	require.Nil(t, requestLangs(ctx))
+}
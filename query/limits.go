@@ -0,0 +1,155 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/dgraph-io/dgraph/x"
+	"google.golang.org/grpc/metadata"
+)
+
+// Limits bounds the result of a query, so that untrusted queries can be run without letting
+// them use too much of the server. A zero value means there's no limit.
+type Limits struct {
+	// Depth is the maximum number of nested levels.
+	Depth uint64
+	// Nodes is the maximum number of nodes.
+	Nodes uint64
+	// Fanout is the maximum number of nodes a node can have for one predicate.
+	Fanout uint64
+}
+
+// LimitError is returned when the result of a query exceeds one of its Limits.
+type LimitError struct {
+	// Limit is the name of the exceeded limit: depth, nodes or fanout.
+	Limit string
+	// Value is the value of the exceeded limit.
+	Value uint64
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("Query exceeded the %s limit of %d", e.Limit, e.Value)
+}
+
+// ErrorCode returns x.CodeLimitExceeded.
+func (e *LimitError) ErrorCode() x.ErrorCode {
+	return x.CodeLimitExceeded
+}
+
+// boundLimit returns the limit asked for by the request, if any, without going over the
+// limit of the server.
+func boundLimit(req, server uint64) uint64 {
+	if server == 0 || (req != 0 && req < server) {
+		return req
+	}
+	return server
+}
+
+// requestLimits returns the limits for the request, which can be lowered from the limits of
+// the server either through LimitsKey or, for gRPC clients, the max_depth, max_nodes and
+// max_fanout metadata.
+func requestLimits(ctx context.Context) Limits {
+	l, _ := ctx.Value(LimitsKey).(Limits)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		// An invalid value is ignored and the limit of the server applies.
+		for key, v := range map[string]*uint64{
+			"max_depth":  &l.Depth,
+			"max_nodes":  &l.Nodes,
+			"max_fanout": &l.Fanout,
+		} {
+			if len(md[key]) > 0 {
+				if n, err := strconv.ParseUint(md[key][0], 0, 64); err == nil {
+					*v = n
+				}
+			}
+		}
+	}
+	x.ConfigMu.RLock()
+	defer x.ConfigMu.RUnlock()
+	return Limits{
+		Depth:  boundLimit(l.Depth, x.Config.QueryDepthLimit),
+		Nodes:  boundLimit(l.Nodes, x.Config.QueryNodeLimit),
+		Fanout: boundLimit(l.Fanout, x.Config.QueryFanoutLimit),
+	}
+}
+
+// ctxCheckNodes is the number of nodes added to a result between two checks of whether the
+// query was cancelled.
+const ctxCheckNodes = 1000
+
+// traversal keeps track of the result of a query block while it's built, to enforce its limits
+// and stop once the query is cancelled.
+type traversal struct {
+	ctx    context.Context
+	limits Limits
+	depth  uint64
+	nodes  uint64
+	// elems tells the values of list predicates are paired with their facets.
+	elems bool
+	// trunc truncates the result, if its size is limited or it continues from a cursor.
+	trunc *truncation
+	// more tells the paginated edges with more nodes than returned are flagged.
+	more bool
+}
+
+// elementFacets tells if the values of list predicates are encoded with their facets, as
+// objects holding a value and its facets.
+func (tr *traversal) elementFacets() bool {
+	return tr != nil && tr.elems
+}
+
+// moreFlags tells if the paginated edges with more nodes than returned are flagged.
+func (tr *traversal) moreFlags() bool {
+	return tr != nil && tr.more
+}
+
+// enter is called for every node added to the result, before adding its children.
+func (tr *traversal) enter() error {
+	if tr == nil {
+		return nil
+	}
+	tr.depth++
+	tr.nodes++
+	if tr.limits.Depth > 0 && tr.depth > tr.limits.Depth {
+		return &LimitError{Limit: "depth", Value: tr.limits.Depth}
+	}
+	if tr.limits.Nodes > 0 && tr.nodes > tr.limits.Nodes {
+		return &LimitError{Limit: "nodes", Value: tr.limits.Nodes}
+	}
+	if tr.ctx != nil && tr.nodes%ctxCheckNodes == 0 {
+		return tr.ctx.Err()
+	}
+	return nil
+}
+
+// leave is called once the children of a node were added to the result.
+func (tr *traversal) leave() {
+	if tr != nil {
+		tr.depth--
+	}
+}
+
+// checkFanout is called with the number of nodes a node has for one predicate.
+func (tr *traversal) checkFanout(n int) error {
+	if tr != nil && tr.limits.Fanout > 0 && uint64(n) > tr.limits.Fanout {
+		return &LimitError{Limit: "fanout", Value: tr.limits.Fanout}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestLimits(t *testing.T) {
+	defer func(c x.Options) { x.Config = c }(x.Config)
+	x.Config.QueryDepthLimit = 10
+	x.Config.QueryNodeLimit = 0
+	x.Config.QueryFanoutLimit = 100
+
+	require.Equal(t, Limits{Depth: 10, Fanout: 100}, requestLimits(context.Background()))
+
+	ctx := context.WithValue(context.Background(), LimitsKey,
+		Limits{Depth: 20, Nodes: 500, Fanout: 50})
+	require.Equal(t, Limits{Depth: 10, Nodes: 500, Fanout: 50}, requestLimits(ctx))
+
+	ctx = metadata.NewIncomingContext(context.Background(),
+		metadata.Pairs("max_depth", "3", "max_nodes", "bad"))
+	require.Equal(t, Limits{Depth: 3, Fanout: 100}, requestLimits(ctx))
+}
+
+func TestTraversalLimits(t *testing.T) {
+	tr := &traversal{limits: Limits{Depth: 2, Nodes: 3, Fanout: 2}}
+	require.NoError(t, tr.enter())
+	require.NoError(t, tr.enter())
+	require.EqualError(t, tr.enter(), "Query exceeded the depth limit of 2")
+	tr.leave()
+	tr.leave()
+	require.EqualError(t, tr.enter(), "Query exceeded the nodes limit of 3")
+
+	require.NoError(t, tr.checkFanout(2))
+	err := tr.checkFanout(3)
+	require.Equal(t, &LimitError{Limit: "fanout", Value: 2}, err)
+
+	// A nil traversal has no limits.
+	var none *traversal
+	require.NoError(t, none.enter())
+	require.NoError(t, none.checkFanout(1e6))
+	none.leave()
+}
+
+func TestTraversalCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	tr := &traversal{ctx: ctx}
+	for i := 0; i < ctxCheckNodes; i++ {
+		require.NoError(t, tr.enter())
+		tr.leave()
+	}
+
+	cancel()
+	for i := 1; i < ctxCheckNodes; i++ {
+		require.NoError(t, tr.enter())
+		tr.leave()
+	}
+	require.Equal(t, context.Canceled, tr.enter())
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"unicode/utf8"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/pkg/errors"
+)
+
+// partialMaskKeep is the maximum number of trailing characters kept by the partial masking
+// policy, which never keeps more than a quarter of them.
+const partialMaskKeep = 4
+
+// Unmasker tells if the raw values of a @masked predicate can be returned by a request.
+type Unmasker func(pred string) bool
+
+// maskValues masks the values of the SubGraph as soon as they're fetched, if its predicate is
+// @masked and the request can't unmask it, so that variables, aggregations and every encoder
+// of the result only ever see masked values. Only the requests run with a MaskKey are masked.
+func (sg *SubGraph) maskValues(ctx context.Context) {
+	if len(sg.valueMatrix) == 0 {
+		return
+	}
+	policy := maskPolicy(ctx, sg.Attr)
+	if policy == "" {
+		return
+	}
+
+	for i, vl := range sg.valueMatrix {
+		if policy == "drop" {
+			vl.Values = nil
+			if i < len(sg.LangTags) {
+				// The tags must match the values.
+				sg.LangTags[i].Lang = nil
+			}
+			continue
+		}
+		for j, tv := range vl.Values {
+			vl.Values[j] = maskValue(policy, tv)
+		}
+	}
+}
+
+// maskPolicy returns the policy the values of the predicate are masked with for the request, or
+// an empty string if they aren't masked.
+func maskPolicy(ctx context.Context, pred string) string {
+	unmask, ok := ctx.Value(MaskKey).(Unmasker)
+	if !ok || schema.State() == nil {
+		return ""
+	}
+	policy := schema.State().Masked(pred)
+	if policy == "" || (unmask != nil && unmask(pred)) {
+		return ""
+	}
+	return policy
+}
+
+// maskedArgFuncs are the functions that don't compare the values of their predicate, and so can
+// be run on masked predicates.
+var maskedArgFuncs = map[string]bool{"has": true, "uid": true, "uid_in": true}
+
+// checkMasked returns an error if the SubGraph, its filters or its children compare or sort the
+// values of a @masked predicate the request can't unmask. Functions and sorts run on the raw
+// values, so their results would tell the raw values apart even though they're masked.
+func (sg *SubGraph) checkMasked(ctx context.Context) error {
+	var err error
+	sg.recurse(func(sg *SubGraph) {
+		if err != nil {
+			return
+		}
+		if f := sg.SrcFunc; f != nil && !maskedArgFuncs[f.Name] && !f.IsCount &&
+			!f.IsValueVar && !f.IsLenVar && maskPolicy(ctx, sg.Attr) != "" {
+			err = errors.Errorf("Function %s can't be used on masked predicate %s",
+				f.Name, sg.Attr)
+			return
+		}
+		for _, o := range sg.Params.Order {
+			if maskPolicy(ctx, o.Attr) != "" {
+				err = errors.Errorf("Can't sort by masked predicate %s", o.Attr)
+				return
+			}
+		}
+	})
+	return err
+}
+
+// maskValue returns the string replacing the value under the policy, which is its SHA-256
+// hash, or the value with all but a few of its last characters replaced by '*'.
+func maskValue(policy string, tv *pb.TaskValue) *pb.TaskValue {
+	raw := string(tv.Val)
+	v, _ := getValue(tv)
+	if sv, err := types.Convert(v, types.StringID); err == nil {
+		raw = sv.Value.(string)
+	}
+
+	var masked []byte
+	switch policy {
+	case "partial":
+		n := utf8.RuneCountInString(raw)
+		keep := n / 4
+		if keep > partialMaskKeep {
+			keep = partialMaskKeep
+		}
+		hidden := n - keep
+		for _, r := range raw {
+			if hidden > 0 {
+				r = '*'
+				hidden--
+			}
+			masked = append(masked, string(r)...)
+		}
+	default:
+		sum := sha256.Sum256([]byte(raw))
+		masked = []byte(hex.EncodeToString(sum[:]))
+	}
+	return &pb.TaskValue{Val: masked, ValType: pb.Posting_STRING}
+}
@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaskValue(t *testing.T) {
+	email := &pb.TaskValue{Val: []byte("alice@dgraph.io"), ValType: pb.Posting_STRING}
+	masked := maskValue("partial", email)
+	require.Equal(t, pb.Posting_STRING, masked.ValType)
+	require.Equal(t, "************.io", string(masked.Val))
+	require.Equal(t, "alice@dgraph.io", string(email.Val))
+
+	// Short values are masked entirely.
+	require.Equal(t, "***", string(maskValue("partial",
+		&pb.TaskValue{Val: []byte("abc"), ValType: pb.Posting_STRING}).Val))
+	require.Equal(t, "******gé", string(maskValue("partial",
+		&pb.TaskValue{Val: []byte("abcdefgé"), ValType: pb.Posting_STRING}).Val))
+	require.Equal(t, "************1234", string(maskValue("partial",
+		&pb.TaskValue{Val: []byte("4111111111111234"), ValType: pb.Posting_STRING}).Val))
+
+	require.Equal(t, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae",
+		string(maskValue("hash", &pb.TaskValue{Val: []byte("foo"), ValType: pb.Posting_STRING}).Val))
+
+	// Other types are masked as strings.
+	age := types.ValueForType(types.BinaryID)
+	require.NoError(t, types.Marshal(types.Val{Tid: types.IntID, Value: int64(12345678)}, &age))
+	masked = maskValue("partial", &pb.TaskValue{Val: age.Value.([]byte), ValType: pb.Posting_INT})
+	require.Equal(t, "******78", string(masked.Val))
+}
+
+func TestMaskValuesWithoutKey(t *testing.T) {
+	sg := &SubGraph{
+		Attr: "email",
+		valueMatrix: []*pb.ValueList{{Values: []*pb.TaskValue{
+			{Val: []byte("alice@dgraph.io"), ValType: pb.Posting_STRING},
+		}}},
+	}
+	// Values are only masked for the requests asking for it.
+	sg.maskValues(context.Background())
+	require.Equal(t, "alice@dgraph.io", string(sg.valueMatrix[0].Values[0].Val))
+}
+
+func TestMaskedFilterAndOrder(t *testing.T) {
+	setSchema(`masked_email: string @index(exact) @masked(hash) .`)
+	defer dropPredicate("masked_email")
+	addTriplesToCluster(`
+		<0x9001> <masked_email> "alice@dgraph.io" .
+		<0x9002> <masked_email> "bob@dgraph.io" .
+	`)
+
+	// Requests without an access token can't unmask any predicate.
+	anon := getNewClient()
+	run := func(query string) (string, error) {
+		resp, err := anon.NewReadOnlyTxn().Query(context.Background(), query)
+		if err != nil {
+			return "", err
+		}
+		return string(resp.Json), nil
+	}
+
+	js, err := run(`{ q(func: has(masked_email), first: 1) { masked_email } }`)
+	require.NoError(t, err)
+	require.NotContains(t, js, "@dgraph.io")
+
+	// Comparing or sorting the raw values would tell them apart despite the masking.
+	for _, query := range []string{
+		`{ q(func: eq(masked_email, "alice@dgraph.io")) { uid } }`,
+		`{ q(func: has(masked_email)) @filter(eq(masked_email, "alice@dgraph.io")) { uid } }`,
+		`{ q(func: uid(0x9001)) @filter(regexp(masked_email, /^alice/)) { uid } }`,
+		`{ q(func: has(masked_email), orderasc: masked_email) { uid } }`,
+		`{ q(func: uid(0x1)) { friend(orderdesc: masked_email) { uid } } }`,
+	} {
+		_, err := run(query)
+		require.Error(t, err, query)
+		require.Contains(t, err.Error(), "masked predicate masked_email", query)
+	}
+
+	// Requests that can unmask the predicate aren't restricted.
+	js = processQueryNoErr(t, `{ q(func: eq(masked_email, "alice@dgraph.io")) { uid } }`)
+	require.JSONEq(t, `{"data": {"q": [{"uid": "0x9001"}]}}`, js)
+}
@@ -17,6 +17,10 @@
 package query
 
 import (
+	"math"
+	"strings"
+	"unicode/utf8"
+
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
@@ -108,7 +112,7 @@ func processBinary(mNode *mathTree) error {
 }
 
 // processUnary handles the unary operands like
-// u-, log, exp, since, floor, ceil
+// u-, log, exp, since, floor, ceil, round, abs
 func processUnary(mNode *mathTree) error {
 	destMap := make(map[uint64]types.Val)
 	srcMap := mNode.Child[0].Val
@@ -210,6 +214,191 @@ func processTernary(mNode *mathTree) error {
 	return nil
 }
 
+// processNary evaluates fn for every uid present in any of the children, passing it the
+// value of each child in order. If all the children are constants, so is the result.
+func processNary(mNode *mathTree, fn func(args []types.Val) (types.Val, error)) error {
+	args := make([]types.Val, len(mNode.Child))
+	keys := make(map[uint64]struct{})
+	allConst := true
+	for i, ch := range mNode.Child {
+		if ch.Const.Value != nil {
+			args[i] = ch.Const
+			continue
+		}
+		allConst = false
+		for k := range ch.Val {
+			keys[k] = struct{}{}
+		}
+	}
+
+	if allConst {
+		var err error
+		mNode.Const, err = fn(args)
+		return err
+	}
+
+	destMap := make(map[uint64]types.Val, len(keys))
+	for k := range keys {
+		for i, ch := range mNode.Child {
+			if ch.Const.Value == nil {
+				args[i] = ch.Val[k]
+			}
+		}
+		res, err := fn(args)
+		if err != nil {
+			return err
+		}
+		destMap[k] = res
+	}
+	mNode.Val = destMap
+	return nil
+}
+
+// processRounding handles floor, ceil and round with a precision, i.e. the number of
+// decimal places to keep. A negative precision rounds to tens, hundreds and so on.
+func processRounding(mNode *mathTree) error {
+	round := math.Round
+	switch mNode.Fn {
+	case "floor":
+		round = math.Floor
+	case "ceil":
+		round = math.Ceil
+	}
+	return processNary(mNode, func(args []types.Val) (types.Val, error) {
+		var l float64
+		if args[0].Value != nil {
+			// A missing value is treated as 0, like in the other functions.
+			var err error
+			if l, err = toFloat(args[0]); err != nil {
+				return types.Val{}, errors.Errorf("Wrong type encountered for func %q", mNode.Fn)
+			}
+		}
+		prec, err := toFloat(args[1])
+		if err != nil || prec != math.Trunc(prec) {
+			return types.Val{}, errors.Errorf("Precision of %v should be an integer", mNode.Fn)
+		}
+		p := math.Pow(10, prec)
+		return types.Val{Tid: types.FloatID, Value: round(l*p) / p}, nil
+	})
+}
+
+// processVariadic handles min and max with more than two arguments.
+func processVariadic(mNode *mathTree) error {
+	return processNary(mNode, func(args []types.Val) (types.Val, error) {
+		ag := aggregator{name: mNode.Fn}
+		for _, arg := range args {
+			if err := ag.ApplyVal(arg); err != nil {
+				return types.Val{}, err
+			}
+		}
+		return ag.Value()
+	})
+}
+
+// processCondChain handles cond(c1, v1, c2, v2, ..., default), which evaluates to the
+// value following the first true condition, or to the default if none of them is.
+// A condition missing for a uid is considered to be false.
+func processCondChain(mNode *mathTree) error {
+	return processNary(mNode, func(args []types.Val) (types.Val, error) {
+		for i := 0; i+1 < len(args); i += 2 {
+			if args[i].Value == nil {
+				continue
+			}
+			v, ok := args[i].Value.(bool)
+			if !ok {
+				return types.Val{}, errors.Errorf(
+					"Condition %d of conditional function not a bool value", i/2+1)
+			}
+			if v {
+				return args[i+1], nil
+			}
+		}
+		return args[len(args)-1], nil
+	})
+}
+
+// processString handles the string functions concat, substr, lower, upper, trim and len.
+// Values of other types are converted to strings and missing values are treated as
+// empty strings.
+func processString(mNode *mathTree) error {
+	fn := mNode.Fn
+	return processNary(mNode, func(args []types.Val) (types.Val, error) {
+		strs := make([]string, 0, len(args))
+		for i, arg := range args {
+			if fn == "substr" && i > 0 {
+				// The offset and length are numbers.
+				break
+			}
+			str, err := toString(arg)
+			if err != nil {
+				return types.Val{}, errors.Wrapf(err, "Wrong type encountered for func %q", fn)
+			}
+			strs = append(strs, str)
+		}
+
+		res := types.Val{Tid: types.StringID}
+		switch fn {
+		case "concat":
+			res.Value = strings.Join(strs, "")
+		case "lower":
+			res.Value = strings.ToLower(strs[0])
+		case "upper":
+			res.Value = strings.ToUpper(strs[0])
+		case "trim":
+			res.Value = strings.TrimSpace(strs[0])
+		case "len":
+			res = types.Val{Tid: types.IntID, Value: int64(utf8.RuneCountInString(strs[0]))}
+		case "substr":
+			sub, err := substr([]rune(strs[0]), args[1:])
+			if err != nil {
+				return types.Val{}, err
+			}
+			res.Value = sub
+		}
+		return res, nil
+	})
+}
+
+// substr returns the runes of str starting at the offset given by the first argument,
+// up to the length given by the optional second argument. Out of range offsets and
+// lengths are clamped to the string.
+func substr(str []rune, args []types.Val) (string, error) {
+	bounds := make([]int, 0, 2)
+	for _, arg := range args {
+		f, err := toFloat(arg)
+		if err != nil || f != math.Trunc(f) || f < 0 {
+			return "", errors.Errorf("Offset and length of substr should be positive integers")
+		}
+		bounds = append(bounds, int(math.Min(f, float64(len(str)))))
+	}
+	start, end := bounds[0], len(str)
+	if len(bounds) > 1 && start+bounds[1] < end {
+		end = start + bounds[1]
+	}
+	return string(str[start:end]), nil
+}
+
+func toString(v types.Val) (string, error) {
+	if v.Value == nil {
+		return "", nil
+	}
+	str := types.ValueForType(types.StringID)
+	if err := types.Marshal(v, &str); err != nil {
+		return "", err
+	}
+	return str.Value.(string), nil
+}
+
+func toFloat(v types.Val) (float64, error) {
+	switch v.Tid {
+	case types.IntID:
+		return float64(v.Value.(int64)), nil
+	case types.FloatID:
+		return v.Value.(float64), nil
+	}
+	return 0, errors.Errorf("Expected a number. Got value of type %s", v.Tid.Name())
+}
+
 func evalMathTree(mNode *mathTree) error {
 	if mNode.Const.Value != nil {
 		return nil
@@ -231,6 +420,24 @@ func evalMathTree(mNode *mathTree) error {
 	}
 
 	aggName := mNode.Fn
+	switch {
+	case isStringFn(aggName):
+		if err := checkStringFnArgs(aggName, len(mNode.Child)); err != nil {
+			return err
+		}
+		return processString(mNode)
+	case isRounding(aggName) && len(mNode.Child) == 2:
+		return processRounding(mNode)
+	case (aggName == "min" || aggName == "max") && len(mNode.Child) > 2:
+		return processVariadic(mNode)
+	case isTernary(aggName) && len(mNode.Child) > 3:
+		if len(mNode.Child)%2 == 0 {
+			return errors.Errorf("Function %v expects an odd number of arguments. But got: %v",
+				aggName, len(mNode.Child))
+		}
+		return processCondChain(mNode)
+	}
+
 	if isUnary(aggName) {
 		if len(mNode.Child) != 1 {
 			return errors.Errorf("Function %v expects 1 argument. But got: %v", aggName,
@@ -156,6 +156,20 @@ func TestProcessUnary(t *testing.T) {
 			}},
 			out: types.Val{Tid: types.FloatID, Value: 3.0},
 		},
+		{in: &mathTree{
+			Fn: "round",
+			Child: []*mathTree{
+				{Const: types.Val{Tid: types.FloatID, Value: 2.5}},
+			}},
+			out: types.Val{Tid: types.FloatID, Value: 3.0},
+		},
+		{in: &mathTree{
+			Fn: "abs",
+			Child: []*mathTree{
+				{Const: types.Val{Tid: types.IntID, Value: int64(-7)}},
+			}},
+			out: types.Val{Tid: types.FloatID, Value: 7.0},
+		},
 	}
 	for _, tc := range tests {
 		t.Logf("Test %s", tc.in.Fn)
@@ -265,4 +279,129 @@ func TestProcessTernary(t *testing.T) {
 	}
 }
 
+func TestProcessRounding(t *testing.T) {
+	tests := []struct {
+		fn   string
+		val  float64
+		prec int64
+		out  float64
+	}{
+		{fn: "round", val: 3.14159, prec: 2, out: 3.14},
+		{fn: "floor", val: 3.149, prec: 2, out: 3.14},
+		{fn: "ceil", val: 3.141, prec: 2, out: 3.15},
+		{fn: "round", val: 1234.5, prec: -2, out: 1200},
+	}
+	for _, tc := range tests {
+		in := &mathTree{
+			Fn: tc.fn,
+			Child: []*mathTree{
+				{Var: "v", Val: map[uint64]types.Val{1: {Tid: types.FloatID, Value: tc.val}}},
+				{Const: types.Val{Tid: types.IntID, Value: tc.prec}},
+			}}
+		require.NoError(t, evalMathTree(in))
+		require.InDelta(t, tc.out, in.Val[1].Value, 1e-9, "%s(%v, %d)", tc.fn, tc.val, tc.prec)
+	}
+
+	in := &mathTree{
+		Fn: "round",
+		Child: []*mathTree{
+			{Const: types.Val{Tid: types.FloatID, Value: 1.0}},
+			{Const: types.Val{Tid: types.FloatID, Value: 1.5}},
+		}}
+	require.Error(t, evalMathTree(in))
+}
+
+func TestProcessVariadic(t *testing.T) {
+	in := &mathTree{
+		Fn: "max",
+		Child: []*mathTree{
+			{Var: "v", Val: map[uint64]types.Val{
+				1: {Tid: types.IntID, Value: int64(1)},
+				2: {Tid: types.IntID, Value: int64(8)},
+			}},
+			{Const: types.Val{Tid: types.FloatID, Value: 5.0}},
+			{Var: "v", Val: map[uint64]types.Val{1: {Tid: types.FloatID, Value: 3.0}}},
+		}}
+	require.NoError(t, evalMathTree(in))
+	require.EqualValues(t, types.Val{Tid: types.FloatID, Value: 5.0}, in.Val[1])
+	require.EqualValues(t, types.Val{Tid: types.FloatID, Value: 8.0}, in.Val[2])
+
+	in = &mathTree{
+		Fn: "min",
+		Child: []*mathTree{
+			{Const: types.Val{Tid: types.FloatID, Value: 4.0}},
+			{Const: types.Val{Tid: types.FloatID, Value: 2.0}},
+			{Const: types.Val{Tid: types.FloatID, Value: 3.0}},
+		}}
+	require.NoError(t, evalMathTree(in))
+	require.EqualValues(t, types.Val{Tid: types.FloatID, Value: 2.0}, in.Const)
+}
+
+func TestProcessCondChain(t *testing.T) {
+	boolVal := func(b bool) types.Val { return types.Val{Tid: types.BoolID, Value: b} }
+	in := &mathTree{
+		Fn: "cond",
+		Child: []*mathTree{
+			{Var: "v", Val: map[uint64]types.Val{1: boolVal(true), 2: boolVal(false), 3: boolVal(false)}},
+			{Const: types.Val{Tid: types.IntID, Value: int64(1)}},
+			{Var: "v", Val: map[uint64]types.Val{1: boolVal(true), 2: boolVal(true), 3: boolVal(false)}},
+			{Const: types.Val{Tid: types.IntID, Value: int64(2)}},
+			{Const: types.Val{Tid: types.IntID, Value: int64(3)}},
+		}}
+	require.NoError(t, evalMathTree(in))
+	require.EqualValues(t, int64(1), in.Val[1].Value)
+	require.EqualValues(t, int64(2), in.Val[2].Value)
+	require.EqualValues(t, int64(3), in.Val[3].Value)
+
+	in.Child = in.Child[:4]
+	require.Error(t, evalMathTree(in))
+}
+
+func TestProcessString(t *testing.T) {
+	str := func(s string) types.Val { return types.Val{Tid: types.StringID, Value: s} }
+	name := &mathTree{Var: "n", Val: map[uint64]types.Val{
+		1: str("  Alice "),
+		2: str("Bob"),
+	}}
+	tests := []struct {
+		in  *mathTree
+		out map[uint64]types.Val
+	}{
+		{in: &mathTree{Fn: "lower", Child: []*mathTree{name}},
+			out: map[uint64]types.Val{1: str("  alice "), 2: str("bob")}},
+		{in: &mathTree{Fn: "upper", Child: []*mathTree{name}},
+			out: map[uint64]types.Val{1: str("  ALICE "), 2: str("BOB")}},
+		{in: &mathTree{Fn: "trim", Child: []*mathTree{name}},
+			out: map[uint64]types.Val{1: str("Alice"), 2: str("Bob")}},
+		{in: &mathTree{Fn: "len", Child: []*mathTree{name}},
+			out: map[uint64]types.Val{
+				1: {Tid: types.IntID, Value: int64(8)},
+				2: {Tid: types.IntID, Value: int64(3)},
+			}},
+		{in: &mathTree{Fn: "concat", Child: []*mathTree{
+			{Const: str("name: ")},
+			name,
+			{Const: types.Val{Tid: types.IntID, Value: int64(7)}},
+		}},
+			out: map[uint64]types.Val{1: str("name:   Alice 7"), 2: str("name: Bob7")}},
+		{in: &mathTree{Fn: "substr", Child: []*mathTree{
+			name,
+			{Const: types.Val{Tid: types.FloatID, Value: 2.0}},
+			{Const: types.Val{Tid: types.FloatID, Value: 3.0}},
+		}},
+			out: map[uint64]types.Val{1: str("Ali"), 2: str("b")}},
+	}
+	for _, tc := range tests {
+		t.Logf("Test %s", tc.in.Fn)
+		require.NoError(t, evalMathTree(tc.in))
+		require.Equal(t, tc.out, tc.in.Val)
+	}
+
+	bad := &mathTree{Fn: "substr", Child: []*mathTree{
+		name,
+		{Const: types.Val{Tid: types.FloatID, Value: -1.0}},
+	}}
+	require.Error(t, evalMathTree(bad))
+}
+
 func TestEvalMathTree(t *testing.T) {}
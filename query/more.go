@@ -0,0 +1,78 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// moreSuffix is appended to the name of a paginated edge to flag that it has more nodes than
+// returned.
+const moreSuffix = "@more"
+
+// requestMoreFlags tells if the paginated edges with more nodes than returned must be flagged,
+// as asked for through MoreFlagsKey or, for gRPC clients, the more_flags metadata.
+func requestMoreFlags(ctx context.Context) bool {
+	return boolOption(ctx, MoreFlagsKey, "more_flags")
+}
+
+// pageMore tells if the page from start to end of a list of n uids paginated with count leaves
+// out some of its uids: the ones after the page, or the ones before it for a negative count,
+// which pages from the end.
+func pageMore(count, start, end, n int) bool {
+	if count < 0 {
+		return start > 0
+	}
+	return end < n
+}
+
+// widenPage returns the count of a page with one more uid than a page of count, for trimPage
+// to tell if the list had more uids. The sorted pages can't have a negative count.
+func widenPage(count int) int {
+	if count <= 0 {
+		return count
+	}
+	return count + 1
+}
+
+// trimPage trims the uid of a page widened by widenPage, and tells if it had one.
+func trimPage(ul *pb.List, count int) bool {
+	if count <= 0 || len(ul.Uids) <= count {
+		return false
+	}
+	ul.Uids = ul.Uids[:count]
+	return true
+}
+
+// hasMoreAt tells if the pagination of pc left out some of the nodes of the node at idx.
+func (pc *SubGraph) hasMoreAt(idx int) bool {
+	return idx < len(pc.more) && pc.more[idx]
+}
+
+// addMoreFlag flags the edge of pc from the node at idx in dst, if it has more nodes than
+// returned.
+func addMoreFlag(pc *SubGraph, idx int, fieldName string, dst outputNode) {
+	if !pc.hasMoreAt(idx) {
+		return
+	}
+	c := types.ValueForType(types.BoolID)
+	c.Value = true
+	dst.AddValue(fieldName+moreSuffix, c)
+}
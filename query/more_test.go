@@ -0,0 +1,78 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+func TestRequestMoreFlags(t *testing.T) {
+	require.False(t, requestMoreFlags(context.Background()))
+	ctx := context.WithValue(context.Background(), MoreFlagsKey, true)
+	require.True(t, requestMoreFlags(ctx))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("more_flags", "false"))
+	require.False(t, requestMoreFlags(ctx))
+}
+
+func TestPageMore(t *testing.T) {
+	for _, tc := range []struct {
+		count, offset, n int
+		more             bool
+	}{
+		{count: 2, n: 3, more: true},
+		{count: 3, n: 3},
+		{count: 2, offset: 1, n: 3},
+		{count: 1, offset: 1, n: 3, more: true},
+		{offset: 1, n: 3},
+		{count: -2, n: 3, more: true},
+		{count: -3, n: 3},
+		{count: 2, n: 0},
+	} {
+		start, end := x.PageRange(tc.count, tc.offset, tc.n)
+		require.Equal(t, tc.more, pageMore(tc.count, start, end, tc.n), "%+v", tc)
+	}
+}
+
+func TestTrimPage(t *testing.T) {
+	ul := &pb.List{Uids: []uint64{1, 2, 3}}
+	require.Equal(t, 3, widenPage(2))
+	require.True(t, trimPage(ul, 2))
+	require.Equal(t, []uint64{1, 2}, ul.Uids)
+	require.False(t, trimPage(ul, 2))
+
+	require.Equal(t, -2, widenPage(-2))
+	require.False(t, trimPage(ul, -2))
+}
+
+func TestAddMoreFlag(t *testing.T) {
+	pc := &SubGraph{more: []bool{false, true}}
+	fj := &fastJsonNode{}
+	addMoreFlag(pc, 0, "friend", fj)
+	addMoreFlag(pc, 1, "friend", fj)
+	addMoreFlag(pc, 2, "friend", fj)
+	var out bytes.Buffer
+	fj.encode(&out)
+	require.Equal(t, `{"friend@more":true}`, out.String())
+}
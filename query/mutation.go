@@ -54,7 +54,8 @@ func ApplyMutations(ctx context.Context, m *pb.Mutations) (*api.TxnContext, erro
 func expandEdges(ctx context.Context, m *pb.Mutations) ([]*pb.DirectedEdge, error) {
 	edges := make([]*pb.DirectedEdge, 0, 2*len(m.Edges))
 	for _, edge := range m.Edges {
-		x.AssertTrue(edge.Op == pb.DirectedEdge_DEL || edge.Op == pb.DirectedEdge_SET)
+		x.AssertTrue(edge.Op == pb.DirectedEdge_DEL || edge.Op == pb.DirectedEdge_SET ||
+			edge.Op == pb.DirectedEdge_ADD || edge.Op == pb.DirectedEdge_CAS)
 
 		var preds []string
 		if edge.Attr != x.Star {
@@ -110,9 +111,17 @@ func verifyUid(ctx context.Context, uid uint64) error {
 
 // AssignUids tries to assign unique ids to each identity in the subjects and objects in the
 // format of _:xxx. An identity, e.g. _:a, will only be assigned one uid regardless how many times
-// it shows up in the subjects or objects
+// it shows up in the subjects or objects. The uids are assigned in the order the identities first
+// show up, so that the same nquads get the same uids from the same lease.
 func AssignUids(ctx context.Context, nquads []*api.NQuad) (map[string]uint64, error) {
 	newUids := make(map[string]uint64)
+	var blanks []string
+	addBlank := func(blank string) {
+		if _, has := newUids[blank]; !has {
+			newUids[blank] = 0
+			blanks = append(blanks, blank)
+		}
+	}
 	num := &pb.Num{}
 	var err error
 	for _, nq := range nquads {
@@ -126,7 +135,7 @@ func AssignUids(ctx context.Context, nquads []*api.NQuad) (map[string]uint64, er
 		}
 		var uid uint64
 		if strings.HasPrefix(nq.Subject, "_:") {
-			newUids[nq.Subject] = 0
+			addBlank(nq.Subject)
 		} else if uid, err = gql.ParseUid(nq.Subject); err != nil {
 			return newUids, err
 		}
@@ -134,10 +143,10 @@ func AssignUids(ctx context.Context, nquads []*api.NQuad) (map[string]uint64, er
 			return newUids, err
 		}
 
-		if len(nq.ObjectId) > 0 {
+		if len(nq.ObjectId) > 0 && !gql.IsValueOp(nq.ObjectId) {
 			var uid uint64
 			if strings.HasPrefix(nq.ObjectId, "_:") {
-				newUids[nq.ObjectId] = 0
+				addBlank(nq.ObjectId)
 			} else if uid, err = gql.ParseUid(nq.ObjectId); err != nil {
 				return newUids, err
 			}
@@ -157,7 +166,7 @@ func AssignUids(ctx context.Context, nquads []*api.NQuad) (map[string]uint64, er
 		}
 		curId := res.StartId
 		// assign generated ones now
-		for k := range newUids {
+		for _, k := range blanks {
 			x.AssertTruef(curId != 0 && curId <= res.EndId, "not enough uids generated")
 			newUids[k] = curId
 			curId++
@@ -178,13 +187,20 @@ func ToDirectedEdges(gmu *gql.Mutation,
 		if len(nq.Subject) == 0 {
 			return nil
 		}
+		isValueOp := gql.IsValueOp(nq.ObjectId)
+		if isValueOp && (op != pb.DirectedEdge_SET || nq.Predicate == x.Star) {
+			return errors.Errorf("%s can only be used as the value of a predicate in a set "+
+				"mutation", nq.ObjectId)
+		}
 		// Get edge from nquad using newUids.
 		var edge *pb.DirectedEdge
 		edge, err = wnq.ToEdgeUsing(newUids)
 		if err != nil {
 			return errors.Wrap(err, "")
 		}
-		edge.Op = op
+		if !isValueOp {
+			edge.Op = op
+		}
 		edges = append(edges, edge)
 		return nil
 	}
@@ -201,6 +217,9 @@ func ToDirectedEdges(gmu *gql.Mutation,
 		if nq.Subject == x.Star && nq.ObjectValue.GetDefaultVal() == x.Star {
 			return edges, errors.New("Predicate deletion should be called via alter")
 		}
+		if facets.HasOps(nq.Facets) {
+			return edges, errors.New("Facet operators can only be used in set mutations")
+		}
 		if err := parse(nq, pb.DirectedEdge_DEL); err != nil {
 			return edges, err
 		}
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// isHasEdge tells if the filter is has_edge, whose filters are run on the nodes its edges lead
+// to.
+func (sg *SubGraph) isHasEdge() bool {
+	return sg.SrcFunc != nil && sg.SrcFunc.Name == "has_edge"
+}
+
+// outerAttrs adds the predicates of the outer(pred) arguments of the filters to attrs. The
+// filters of a has_edge under them have outer nodes of their own, and are left out.
+func outerAttrs(filters []*SubGraph, attrs []string) []string {
+	for _, f := range filters {
+		if f.SrcFunc != nil {
+			for _, arg := range f.SrcFunc.Args {
+				if arg.IsOuter && !x.HasString(attrs, arg.Value) {
+					attrs = append(attrs, arg.Value)
+				}
+			}
+		}
+		if !f.isHasEdge() {
+			attrs = outerAttrs(f.Filters, attrs)
+		}
+	}
+	return attrs
+}
+
+// hasOuterArgs tells if the filters of a has_edge compare with the values of the node the
+// edges leave.
+func hasOuterArgs(filters []*SubGraph) bool {
+	return len(outerAttrs(filters, nil)) > 0
+}
+
+// outerGroups groups the nodes of the has_edge filter sg by their values of the predicates of
+// the outer(pred) arguments of its filters, and returns the edges of each group, with the
+// filters comparing with the values of the group. The nodes without a value of one of the
+// predicates have no matching edge. Only the first value of a list predicate is compared with.
+func (sg *SubGraph) outerGroups(ctx context.Context) ([]*SubGraph, error) {
+	attrs := outerAttrs(sg.Filters, nil)
+	values := make([]*SubGraph, 0, len(attrs))
+	rch := make(chan error, len(attrs))
+	for _, attr := range attrs {
+		v := sg.edgesOf(sg.SrcUIDs, nil)
+		v.Attr = attr
+		values = append(values, v)
+		go ProcessGraph(ctx, v, sg, rch)
+	}
+	var valuesErr error
+	for range attrs {
+		if err := <-rch; err != nil {
+			valuesErr = err
+		}
+	}
+	if valuesErr != nil {
+		return nil, valuesErr
+	}
+
+	type group struct {
+		uids []uint64
+		vals map[string]string
+	}
+	groups := make(map[string]*group)
+	var keys []string
+	for i, uid := range sg.SrcUIDs.GetUids() {
+		vals := make(map[string]string, len(attrs))
+		key := make([]string, 0, len(attrs))
+		for j, attr := range attrs {
+			if i >= len(values[j].valueMatrix) || len(values[j].valueMatrix[i].Values) == 0 {
+				break
+			}
+			v, err := getValue(values[j].valueMatrix[i].Values[0])
+			if err != nil {
+				break
+			}
+			sv, err := types.Convert(v, types.StringID)
+			if err != nil {
+				break
+			}
+			vals[attr] = sv.Value.(string)
+			key = append(key, vals[attr])
+		}
+		if len(vals) < len(attrs) {
+			continue
+		}
+		k := strings.Join(key, "\x00")
+		g, ok := groups[k]
+		if !ok {
+			g = &group{vals: vals}
+			groups[k] = g
+			keys = append(keys, k)
+		}
+		g.uids = append(g.uids, uid)
+	}
+
+	edges := make([]*SubGraph, 0, len(keys))
+	for _, k := range keys {
+		g := groups[k]
+		edges = append(edges, sg.edgesOf(&pb.List{Uids: g.uids}, withOuterValues(sg.Filters,
+			g.vals)))
+	}
+	return edges, nil
+}
+
+// withOuterValues returns copies of the filters, yet to be run, with their outer(pred) arguments
+// replaced by the values in vals. The filters of a has_edge under them are copied as they are.
+func withOuterValues(filters []*SubGraph, vals map[string]string) []*SubGraph {
+	out := make([]*SubGraph, 0, len(filters))
+	for _, f := range filters {
+		c := *f
+		if f.DestUIDs != nil {
+			// The filters of uid variables intersect their uids in place.
+			c.DestUIDs = &pb.List{Uids: append(f.DestUIDs.Uids[:0:0], f.DestUIDs.Uids...)}
+		}
+		if f.SrcFunc != nil && vals != nil {
+			fn := *f.SrcFunc
+			fn.Args = make([]gql.Arg, 0, len(f.SrcFunc.Args))
+			for _, arg := range f.SrcFunc.Args {
+				if arg.IsOuter {
+					arg = gql.Arg{Value: vals[arg.Value]}
+				}
+				fn.Args = append(fn.Args, arg)
+			}
+			c.SrcFunc = &fn
+		}
+		if f.isHasEdge() {
+			c.Filters = withOuterValues(f.Filters, nil)
+		} else {
+			c.Filters = withOuterValues(f.Filters, vals)
+		}
+		out = append(out, &c)
+	}
+	return out
+}
@@ -18,6 +18,7 @@ package query
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"sort"
@@ -31,15 +32,24 @@ import (
 
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/task"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/types/facets"
+	"github.com/dgraph-io/dgraph/worker"
 	"github.com/dgraph-io/dgraph/x"
 )
 
 // ToJson converts the list of subgraph into a JSON response by calling toFastJSON.
 func ToJson(l *Latency, sgl []*SubGraph) ([]byte, error) {
+	return ToJsonWithSpill(context.Background(), l, sgl)
+}
+
+// ToJsonWithSpill is like ToJson, but a response spilled to disk is kept for SpilledJSON
+// and nil is returned instead, if the context was made by WithSpill. It stops once the context
+// is cancelled.
+func ToJsonWithSpill(ctx context.Context, l *Latency, sgl []*SubGraph) ([]byte, error) {
 	sgr := &SubGraph{}
 	for _, sg := range sgl {
 		if sg.Params.Alias == "var" || sg.Params.Alias == "shortest" {
@@ -48,9 +58,28 @@ func ToJson(l *Latency, sgl []*SubGraph) ([]byte, error) {
 		if sg.Params.GetUid {
 			sgr.Params.GetUid = true
 		}
+		// All the blocks of a request share the same limits.
+		sgr.Params.limits = sg.Params.limits
+		sgr.Params.floatFormat = sg.Params.floatFormat
+		sgr.Params.binaryFormat = sg.Params.binaryFormat
+		sgr.Params.dedupeNodes = sg.Params.dedupeNodes
+		sgr.Params.elementFacets = sg.Params.elementFacets
+		sgr.Params.maxResponseBytes = sg.Params.maxResponseBytes
+		sgr.Params.cursor = sg.Params.cursor
+		sgr.Params.moreFlags = sg.Params.moreFlags
+		sgr.Params.outputProfile = sg.Params.outputProfile
 		sgr.Children = append(sgr.Children, sg)
 	}
-	return sgr.toFastJSON(l)
+	buf, err := sgr.toFastJSON(ctx, l)
+	if err != nil {
+		return nil, err
+	}
+	if s, ok := ctx.Value(spillKey{}).(*spilled); ok && buf.Spilled() {
+		s.buf = buf
+		return nil, nil
+	}
+	defer buf.Close()
+	return buf.Bytes()
 }
 
 // outputNode is the generic output / writer for preTraverse.
@@ -60,7 +89,12 @@ type outputNode interface {
 	AddMapChild(attr string, node outputNode, isRoot bool)
 	AddListChild(attr string, child outputNode)
 	New(attr string) outputNode
+	// NewChild returns a node to be added under attr with AddListChild if list is true, or
+	// with AddMapChild otherwise.
+	NewChild(attr string, list bool) outputNode
 	SetUID(uid uint64, attr string)
+	// AddFacet adds a facet of the edge leading to the node.
+	AddFacet(attr string, v types.Val)
 	IsEmpty() bool
 
 	addCountAtRoot(*SubGraph)
@@ -84,6 +118,12 @@ type fastJsonNode struct {
 	scalarVal []byte
 	attrs     []*fastJsonNode
 	list      bool
+	intern    *scalarInterner
+
+	// uid is the uid of the node, and facet tells the value is a facet of the edge leading to
+	// its parent. They're kept to dedupe the nodes of the result.
+	uid   uint64
+	facet bool
 }
 
 func (fj *fastJsonNode) AddValue(attr string, v types.Val) {
@@ -91,11 +131,18 @@ func (fj *fastJsonNode) AddValue(attr string, v types.Val) {
 }
 
 func (fj *fastJsonNode) AddListValue(attr string, v types.Val, list bool) {
-	if bs, err := valToBytes(v); err == nil {
+	if bs, err := fj.intern.valToBytes(attr, v); err == nil {
 		fj.attrs = append(fj.attrs, makeScalarNode(attr, false, bs, list))
 	}
 }
 
+func (fj *fastJsonNode) AddFacet(attr string, v types.Val) {
+	fj.AddValue(attr, v)
+	if n := len(fj.attrs); n > 0 && fj.attrs[n-1].attr == attr {
+		fj.attrs[n-1].facet = true
+	}
+}
+
 func (fj *fastJsonNode) AddMapChild(attr string, val outputNode, isRoot bool) {
 	var childNode *fastJsonNode
 	for _, c := range fj.attrs {
@@ -123,7 +170,15 @@ func (fj *fastJsonNode) AddListChild(attr string, child outputNode) {
 }
 
 func (fj *fastJsonNode) New(attr string) outputNode {
-	return &fastJsonNode{attr: attr, isChild: false}
+	n := &fastJsonNode{attr: attr, isChild: false}
+	if fj != nil {
+		n.intern = fj.intern
+	}
+	return n
+}
+
+func (fj *fastJsonNode) NewChild(attr string, list bool) outputNode {
+	return fj.New(attr)
 }
 
 func (fj *fastJsonNode) SetUID(uid uint64, attr string) {
@@ -143,16 +198,24 @@ func (fj *fastJsonNode) IsEmpty() bool {
 	return len(fj.attrs) == 0
 }
 
-func valToBytes(v types.Val) ([]byte, error) {
+func valToBytes(v types.Val, ff FloatFormat, bf BinaryFormat) ([]byte, error) {
 	switch v.Tid {
 	case types.StringID, types.DefaultID:
 		return json.Marshal(v.Value)
 	case types.BinaryID:
-		return []byte(fmt.Sprintf("%q", v.Value)), nil
+		b, ok := v.Value.([]byte)
+		if !ok {
+			return nil, errors.Errorf("Expected a []byte value, got %T", v.Value)
+		}
+		return bf.appendBinary(nil, b), nil
 	case types.IntID:
 		return []byte(fmt.Sprintf("%d", v.Value)), nil
 	case types.FloatID:
-		return []byte(fmt.Sprintf("%f", v.Value)), nil
+		f, ok := v.Value.(float64)
+		if !ok {
+			return nil, errors.Errorf("Expected a float64 value, got %T", v.Value)
+		}
+		return ff.appendFloat(nil, f), nil
 	case types.BoolID:
 		if v.Value.(bool) {
 			return []byte("true"), nil
@@ -171,6 +234,9 @@ func valToBytes(v types.Val) ([]byte, error) {
 		return []byte(fmt.Sprintf("\"%#x\"", v.Value)), nil
 	case types.PasswordID:
 		return []byte(fmt.Sprintf("%q", v.Value.(string))), nil
+	case types.JSONID:
+		// The document was validated when it was stored, so it's emitted as it is.
+		return []byte(v.Value.(string)), nil
 	default:
 		return nil, errors.New("Unsupported types.Val.Tid")
 	}
@@ -194,14 +260,30 @@ func (n nodeSlice) Swap(i, j int) {
 	n[i], n[j] = n[j], n[i]
 }
 
-func (fj *fastJsonNode) writeKey(out *bytes.Buffer) {
+func (fj *fastJsonNode) writeKey(out jsonWriter) {
+	writeJSONKey(out, fj.attr)
+	out.WriteRune(':')
+}
+
+// writeJSONKey writes the key as a JSON string. Keys are written as they are, unless they have
+// characters to escape, as predicates given as IRIs can have, e.g. <a\U0001F600>.
+func writeJSONKey(out jsonWriter, key string) {
+	for i := 0; i < len(key); i++ {
+		if c := key[i]; c < 0x20 || c == '"' || c == '\\' {
+			var buf bytes.Buffer
+			enc := json.NewEncoder(&buf)
+			enc.SetEscapeHTML(false)
+			x.Check(enc.Encode(key))
+			out.Write(bytes.TrimSuffix(buf.Bytes(), []byte{'\n'}))
+			return
+		}
+	}
 	out.WriteRune('"')
-	out.WriteString(fj.attr)
+	out.WriteString(key)
 	out.WriteRune('"')
-	out.WriteRune(':')
 }
 
-func (fj *fastJsonNode) encode(out *bytes.Buffer) {
+func (fj *fastJsonNode) encode(out jsonWriter) {
 	// set relative ordering
 	for i, a := range fj.attrs {
 		a.order = i
@@ -271,31 +353,144 @@ func (fj *fastJsonNode) encode(out *bytes.Buffer) {
 	}
 }
 
-func merge(parent [][]*fastJsonNode, child [][]*fastJsonNode) ([][]*fastJsonNode, error) {
+func merge(parent [][]*fastJsonNode, child [][]*fastJsonNode,
+	args gql.NormalizeArgs) ([][]*fastJsonNode, error) {
 	if len(parent) == 0 {
 		return child, nil
 	}
 
 	// Here we merge two slices of maps.
 	mergedList := make([][]*fastJsonNode, 0, len(parent)*len(child))
+	x.ConfigMu.RLock()
+	limit := x.Config.NormalizeNodeLimit
+	x.ConfigMu.RUnlock()
 	cnt := 0
 	for _, pa := range parent {
 		for _, ca := range child {
 			cnt += len(pa) + len(ca)
-			if cnt > x.Config.NormalizeNodeLimit {
+			if cnt > limit {
 				return nil, errors.Errorf(
 					"Couldn't evaluate @normalize directive - too many results")
 			}
 			list := make([]*fastJsonNode, 0, len(pa)+len(ca))
 			list = append(list, pa...)
-			list = append(list, ca...)
+			if args.Suffix {
+				list = append(list, suffixDuplicates(pa, ca)...)
+			} else {
+				list = append(list, ca...)
+			}
 			mergedList = append(mergedList, list)
 		}
 	}
 	return mergedList, nil
 }
 
-func (fj *fastJsonNode) normalize() ([][]*fastJsonNode, error) {
+// suffixDuplicates renames the child keys that are already in the parent to key_1, key_2...
+// It returns copies of the renamed nodes, as the nodes are shared between merged results.
+func suffixDuplicates(parent, child []*fastJsonNode) []*fastJsonNode {
+	taken := make(map[string]bool, len(parent))
+	for _, a := range parent {
+		taken[a.attr] = true
+	}
+	renamed := make(map[string]string)
+	out := make([]*fastJsonNode, 0, len(child))
+	for _, a := range child {
+		if a.attr == "uid" || !taken[a.attr] {
+			out = append(out, a)
+			continue
+		}
+		name, ok := renamed[a.attr]
+		if !ok {
+			for i := 1; ; i++ {
+				name = fmt.Sprintf("%s_%d", a.attr, i)
+				if !taken[name] {
+					break
+				}
+			}
+			renamed[a.attr] = name
+		}
+		cp := *a
+		cp.attr = name
+		out = append(out, &cp)
+	}
+	return out
+}
+
+// prefixKeys prefixes the keys of the flattened results of a child. The nodes are replaced by
+// copies, as they are still part of the unflattened result.
+func prefixKeys(normalized [][]*fastJsonNode, prefix string) {
+	for i, attrs := range normalized {
+		prefixed := make([]*fastJsonNode, 0, len(attrs))
+		for _, a := range attrs {
+			cp := *a
+			cp.attr = prefix + a.attr
+			prefixed = append(prefixed, &cp)
+		}
+		normalized[i] = prefixed
+	}
+}
+
+// dedupeUids keeps only the last uid, which is the one of the deepest node.
+func dedupeUids(attrs []*fastJsonNode) []*fastJsonNode {
+	last := -1
+	for i, a := range attrs {
+		if a.attr == "uid" {
+			last = i
+		}
+	}
+	if last == -1 {
+		return attrs
+	}
+	out := attrs[:0:0]
+	for i, a := range attrs {
+		if a.attr != "uid" || i == last {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// finishNormalize applies the arguments of @normalize that concern the whole flattened result.
+func finishNormalize(attrs []*fastJsonNode, args gql.NormalizeArgs) []*fastJsonNode {
+	if args.Dedupe {
+		seen := make(map[string]bool)
+		out := attrs[:0:0]
+		for _, a := range attrs {
+			if a.isChild || len(a.attrs) > 0 {
+				out = append(out, a)
+				continue
+			}
+			key := a.attr + "\x00" + string(a.scalarVal)
+			if !seen[key] {
+				seen[key] = true
+				out = append(out, a)
+			}
+		}
+		attrs = out
+	}
+	if args.KeepOrder && !args.Suffix {
+		// Keys returned by more than one level have to be next to each other to be
+		// encoded as a list.
+		pos := make(map[string]int)
+		var groups [][]*fastJsonNode
+		for _, a := range attrs {
+			if i, ok := pos[a.attr]; ok {
+				groups[i] = append(groups[i], a)
+				continue
+			}
+			pos[a.attr] = len(groups)
+			groups = append(groups, []*fastJsonNode{a})
+		}
+		out := make([]*fastJsonNode, 0, len(attrs))
+		for _, g := range groups {
+			out = append(out, g...)
+		}
+		attrs = out
+	}
+	return attrs
+}
+
+func (fj *fastJsonNode) normalize(args gql.NormalizeArgs) ([][]*fastJsonNode, error) {
 	cnt := 0
 	for _, a := range fj.attrs {
 		if a.isChild {
@@ -328,40 +523,28 @@ func (fj *fastJsonNode) normalize() ([][]*fastJsonNode, error) {
 		}
 		childSlice := make([][]*fastJsonNode, 0, 5)
 		for ci < len(fj.attrs) && childNode.attr == fj.attrs[ci].attr {
-			normalized, err := fj.attrs[ci].normalize()
+			normalized, err := fj.attrs[ci].normalize(args)
 			if err != nil {
 				return nil, err
 			}
+			if args.Prefix {
+				prefixKeys(normalized, childNode.attr+".")
+			}
 			childSlice = append(childSlice, normalized...)
 			ci++
 		}
 		// Merging with parent.
 		var err error
-		parentSlice, err = merge(parentSlice, childSlice)
+		parentSlice, err = merge(parentSlice, childSlice, args)
 		if err != nil {
 			return nil, err
 		}
 	}
 	for i, slice := range parentSlice {
-		sort.Sort(nodeSlice(slice))
-
-		first := -1
-		last := 0
-		for i := range slice {
-			if slice[i].attr == "uid" {
-				if first == -1 {
-					first = i
-				}
-				last = i
-			}
-		}
-		if first != -1 && first != last {
-			if first == 0 {
-				parentSlice[i] = slice[last:]
-			} else {
-				parentSlice[i] = append(slice[:first], slice[last:]...)
-			}
+		if !args.KeepOrder {
+			sort.Sort(nodeSlice(slice))
 		}
+		parentSlice[i] = dedupeUids(slice)
 	}
 
 	return parentSlice, nil
@@ -388,7 +571,7 @@ func (fj *fastJsonNode) addGroupby(sg *SubGraph, res *groupResults, fname string
 
 func (fj *fastJsonNode) addCountAtRoot(sg *SubGraph) {
 	c := types.ValueForType(types.IntID)
-	c.Value = int64(len(sg.DestUIDs.Uids))
+	c.Value = sg.uidCountValue()
 	n1 := fj.New(sg.Params.Alias)
 	field := sg.Params.uidCountAlias
 	if field == "" {
@@ -398,6 +581,21 @@ func (fj *fastJsonNode) addCountAtRoot(sg *SubGraph) {
 	fj.AddListChild(sg.Params.Alias, n1)
 }
 
+// addDistinctCounts adds the approx_count_distinct of the predicates of an @approximate block.
+func (fj *fastJsonNode) addDistinctCounts(sg *SubGraph) {
+	for _, child := range sg.Children {
+		c := types.ValueForType(types.IntID)
+		c.Value = child.rootCount
+		fieldName := child.Params.Alias
+		if fieldName == "" {
+			fieldName = fmt.Sprintf("%s(%s)", child.SrcFunc.Name, child.Attr)
+		}
+		n1 := fj.New(fieldName)
+		n1.AddValue(fieldName, c)
+		fj.AddListChild(sg.Params.Alias, n1)
+	}
+}
+
 func (fj *fastJsonNode) addAggregations(sg *SubGraph) error {
 	for _, child := range sg.Children {
 		aggVal, ok := child.Params.uidToVal[0]
@@ -423,12 +621,24 @@ func (fj *fastJsonNode) addAggregations(sg *SubGraph) error {
 	return nil
 }
 
-func processNodeUids(fj *fastJsonNode, sg *SubGraph) error {
-	var seedNode *fastJsonNode
+func processNodeUids(fj *fastJsonNode, sg *SubGraph, tr *traversal) error {
+	if tr.trunc.skipBlock() {
+		fj.AddListChild(sg.Params.Alias, &fastJsonNode{})
+		return nil
+	}
 	if sg.Params.IsEmpty {
 		return fj.addAggregations(sg)
 	}
 
+	if sg.Params.subgraph {
+		return fj.addSubgraph(sg, tr)
+	}
+	if sg.Params.JoinArgs.Left != "" {
+		return fj.addJoinPairs(sg, tr)
+	}
+	if sg.isDistinct() {
+		return fj.addDistinctValues(sg, tr)
+	}
 	if sg.uidMatrix == nil {
 		fj.AddListChild(sg.Params.Alias, &fastJsonNode{})
 		return nil
@@ -439,6 +649,18 @@ func processNodeUids(fj *fastJsonNode, sg *SubGraph) error {
 		hasChild = true
 		fj.addCountAtRoot(sg)
 	}
+	if sg.hasHistograms() {
+		hasChild = true
+		fj.addHistograms(sg)
+	}
+	if sg.Params.Approximate {
+		fj.addDistinctCounts(sg)
+		return nil
+	}
+	if sg.isCountOnly() {
+		// There is nothing else to add for the nodes.
+		return nil
+	}
 
 	if sg.Params.isGroupBy {
 		if len(sg.GroupbyRes) == 0 {
@@ -449,16 +671,20 @@ func processNodeUids(fj *fastJsonNode, sg *SubGraph) error {
 	}
 
 	lenList := len(sg.uidMatrix[0].Uids)
+	if err := tr.checkFanout(lenList); err != nil {
+		return err
+	}
 	for i := 0; i < lenList; i++ {
 		uid := sg.uidMatrix[0].Uids[i]
-		if algo.IndexOf(sg.DestUIDs, uid) < 0 {
-			// This UID was filtered. So Ignore it.
+		if algo.IndexOf(sg.DestUIDs, uid) < 0 || tr.trunc.skipNode(i) {
+			// This UID was filtered, or returned before the cursor. So Ignore it.
 			continue
 		}
 
-		n1 := seedNode.New(sg.Params.Alias)
-		if err := sg.preTraverse(uid, n1); err != nil {
-			if err.Error() == "_INV_" {
+		n1 := fj.New(sg.Params.Alias)
+		n1.(*fastJsonNode).uid = uid
+		if err := sg.preTraverse(tr, uid, n1); err != nil {
+			if err == errInvalidUid {
 				continue
 			}
 			return err
@@ -468,19 +694,26 @@ func processNodeUids(fj *fastJsonNode, sg *SubGraph) error {
 			continue
 		}
 
-		hasChild = true
-		if !sg.Params.Normalize {
-			fj.AddListChild(sg.Params.Alias, n1)
-			continue
+		nodes := []*fastJsonNode{n1.(*fastJsonNode)}
+		if sg.Params.Normalize {
+			// Lets normalize the response now.
+			normalized, err := n1.(*fastJsonNode).normalize(sg.Params.NormalizeArgs)
+			if err != nil {
+				return err
+			}
+			nodes = nodes[:0]
+			for _, c := range normalized {
+				c = finishNormalize(c, sg.Params.NormalizeArgs)
+				nodes = append(nodes, &fastJsonNode{attrs: c})
+			}
 		}
-
-		// Lets normalize the response now.
-		normalized, err := n1.(*fastJsonNode).normalize()
-		if err != nil {
-			return err
+		if !tr.trunc.fitsNodes(i, nodes) {
+			break
 		}
-		for _, c := range normalized {
-			fj.AddListChild(sg.Params.Alias, &fastJsonNode{attrs: c})
+
+		hasChild = true
+		for _, n := range nodes {
+			fj.AddListChild(sg.Params.Alias, n)
 		}
 	}
 
@@ -495,34 +728,91 @@ func processNodeUids(fj *fastJsonNode, sg *SubGraph) error {
 type Extensions struct {
 	Latency *api.Latency    `json:"server_latency,omitempty"`
 	Txn     *api.TxnContext `json:"txn,omitempty"`
+	Uids    *UidLabels      `json:"uids,omitempty"`
+	// Warnings lists the non-fatal issues found while running the request.
+	Warnings []string `json:"warnings,omitempty"`
+	// Metrics counts the resources used to run the request.
+	Metrics *worker.QueryMetrics `json:"metrics,omitempty"`
+	// Blocks tells how the blocks with @besteffort or @budget ran.
+	Blocks []BlockStatus `json:"blocks,omitempty"`
+	// Truncated tells the response was truncated to its maximum size, and Cursor is where
+	// the next request continues it from.
+	Truncated bool   `json:"truncated,omitempty"`
+	Cursor    string `json:"cursor,omitempty"`
+}
+
+// UidLabels maps the names a mutation used to refer to nodes to their uids.
+type UidLabels struct {
+	// BlankNodes maps each blank node to the uid assigned to it.
+	BlankNodes map[string]string `json:"blank_nodes,omitempty"`
+	// Vars maps each variable of the upsert query used in the mutation to its uids. It's
+	// the uid assigned to the variable if the query didn't match any node.
+	Vars map[string][]string `json:"vars,omitempty"`
 }
 
-func (sg *SubGraph) toFastJSON(l *Latency) ([]byte, error) {
+// toFastJSON encodes the result in a buffer which spills to disk once it grows over
+// x.Config.ResponseSpillSize.
+func (sg *SubGraph) toFastJSON(ctx context.Context, l *Latency) (*SpillBuffer, error) {
 	defer func() {
 		l.Json = time.Since(l.Start) - l.Parsing - l.Processing - l.Transport
 	}()
 
-	var seedNode *fastJsonNode
-	var err error
-	n := seedNode.New("_root_")
-	for _, sg := range sg.Children {
-		err = processNodeUids(n.(*fastJsonNode), sg)
-		if err != nil {
-			return nil, err
+	trunc, err := newTruncation(sg)
+	if err != nil {
+		return nil, err
+	}
+	tr := &traversal{ctx: ctx, limits: sg.Params.limits, elems: sg.Params.elementFacets,
+		trunc: trunc, more: sg.Params.moreFlags}
+	bufw := NewSpillBuffer(x.Config.ResponseSpillSize, x.Config.ResponseSpillDir)
+	if sg.streamable() {
+		err = sg.streamJSON(tr, bufw)
+	} else {
+		err = sg.treeJSON(tr, bufw)
+	}
+	if err == nil {
+		err = bufw.Flush()
+	}
+	if err != nil {
+		bufw.Close()
+		return nil, err
+	}
+	setTruncated(ctx, trunc)
+	return bufw, nil
+}
+
+// treeJSON builds the result of the blocks as a tree of fastJsonNode, which is then encoded
+// to out.
+func (sg *SubGraph) treeJSON(tr *traversal, out jsonWriter) error {
+	n := &fastJsonNode{attr: "_root_", intern: newScalarInterner(sg.Params.floatFormat, sg.Params.binaryFormat)}
+	for i, sg := range sg.Children {
+		if tr.trunc != nil {
+			tr.trunc.block = i
+		}
+		if err := processNodeUids(n, sg, tr); err != nil {
+			return err
+		}
+	}
+	if sg.Params.dedupeNodes && sg.Params.outputProfile != ProfileDefault {
+		return errors.Errorf("The nodes can't be deduped with the %s output profile",
+			sg.Params.outputProfile)
+	}
+	if sg.Params.dedupeNodes {
+		if err := n.dedupeNodes(); err != nil {
+			return err
 		}
 	}
+	n = n.toProfile(sg.Params.outputProfile)
 
 	// According to GraphQL spec response should only contain data, errors and extensions as top
 	// level keys. Hence we send server_latency under extensions key.
 	// https://facebook.github.io/graphql/#sec-Response-Format
 
-	var bufw bytes.Buffer
-	if len(n.(*fastJsonNode).attrs) == 0 {
-		bufw.WriteString(`{}`)
+	if len(n.attrs) == 0 {
+		out.WriteString(`{}`)
 	} else {
-		n.(*fastJsonNode).encode(&bufw)
+		n.encode(out)
 	}
-	return bufw.Bytes(), nil
+	return nil
 }
 
 func (sg *SubGraph) fieldName() string {
@@ -550,6 +840,9 @@ func aggWithVarFieldName(pc *SubGraph) string {
 	if pc.Params.Alias != "" {
 		return pc.Params.Alias
 	}
+	if pc.SrcFunc != nil && isCustomFn(pc.SrcFunc.Name) {
+		return fmt.Sprintf("custom(%v)", pc.SrcFunc.Args[0].Value)
+	}
 	fieldName := fmt.Sprintf("val(%v)", pc.Params.Var)
 	if len(pc.Params.NeedsVar) > 0 {
 		fieldName = fmt.Sprintf("val(%v)", pc.Params.NeedsVar[0].Name)
@@ -570,17 +863,64 @@ func addInternalNode(pc *SubGraph, uid uint64, dst outputNode) error {
 	return nil
 }
 
-func addCheckPwd(pc *SubGraph, vals []*pb.TaskValue, dst outputNode) {
-	c := types.ValueForType(types.BoolID)
+// addEditDistance adds the distance computed by the editdistance function, unless the node has
+// no value for the predicate.
+func addEditDistance(pc *SubGraph, vals []*pb.TaskValue, dst outputNode) {
 	if len(vals) == 0 {
-		c.Value = false
+		return
+	}
+	c := types.ValueForType(types.IntID)
+	c.Value = task.ToInt(vals[0])
+
+	fieldName := pc.Params.Alias
+	if fieldName == "" {
+		fieldName = fmt.Sprintf("%s(%s)", pc.SrcFunc.Name, pc.Attr)
+	}
+	dst.AddValue(fieldName, c)
+}
+
+// addJSONPath adds the parts of the JSON documents selected by the jsonpath function.
+func addJSONPath(pc *SubGraph, vals []*pb.TaskValue, dst outputNode) error {
+	fieldName := pc.Params.Alias
+	if fieldName == "" {
+		fieldName = fmt.Sprintf("%s(%s)", pc.SrcFunc.Name, pc.Attr)
+	}
+	for _, tv := range vals {
+		sv, err := convertWithBestEffort(tv, pc.Attr)
+		if err != nil {
+			return err
+		}
+		if pc.List {
+			dst.AddListValue(fieldName, sv, true)
+		} else {
+			dst.AddValue(fieldName, sv)
+		}
+	}
+	return nil
+}
+
+func addCheckPwd(pc *SubGraph, vals []*pb.TaskValue, dst outputNode) {
+	var c types.Val
+	if pc.SrcFunc.Name == "checkpwdlock" {
+		// The result tells if the password is locked out, instead of being a boolean.
+		c = types.ValueForType(types.StringID)
+		if len(vals) == 0 {
+			c.Value = "mismatch"
+		} else {
+			c.Value = task.ToString(vals[0])
+		}
 	} else {
-		c.Value = task.ToBool(vals[0])
+		c = types.ValueForType(types.BoolID)
+		if len(vals) == 0 {
+			c.Value = false
+		} else {
+			c.Value = task.ToBool(vals[0])
+		}
 	}
 
 	fieldName := pc.Params.Alias
 	if fieldName == "" {
-		fieldName = fmt.Sprintf("checkpwd(%s)", pc.Attr)
+		fieldName = fmt.Sprintf("%s(%s)", pc.SrcFunc.Name, pc.Attr)
 	}
 	dst.AddValue(fieldName, c)
 }
@@ -601,8 +941,16 @@ func facetName(fieldName string, f *api.Facet) string {
 	return fieldName + FacetDelimeter + f.Key
 }
 
+// errInvalidUid is returned by preTraverse for a uid which must be left out of the results.
+var errInvalidUid = errors.New("Invalid uid in the results")
+
 // This method gets the values and children for a subprotos.
-func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
+func (sg *SubGraph) preTraverse(tr *traversal, uid uint64, dst outputNode) error {
+	if err := tr.enter(); err != nil {
+		return err
+	}
+	defer tr.leave()
+
 	if sg.Params.IgnoreReflex {
 		if alreadySeen(sg.Params.parentIds, uid) {
 			// A node can't have itself as the child at any level.
@@ -612,10 +960,17 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 		sg.Params.parentIds = append(sg.Params.parentIds, uid)
 	}
 
+	var typeNames []string
+	var fields map[string]struct{}
+	if sg.Params.Typed {
+		typeNames = sg.nodeTypes(uid)
+		fields = typeFields(typeNames)
+	}
+
 	var invalidUids map[uint64]bool
 	// We go through all predicate children of the subprotos.
 	for _, pc := range sg.Children {
-		if pc.Params.ignoreResult {
+		if pc.Params.ignoreResult || !isTypeField(pc, fields) {
 			continue
 		}
 		if pc.IsInternal() {
@@ -657,9 +1012,18 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 		if len(pc.counts) > 0 {
 			addCount(pc, uint64(pc.counts[idx]), dst)
 
-		} else if pc.SrcFunc != nil && pc.SrcFunc.Name == "checkpwd" {
+		} else if pc.SrcFunc != nil && (pc.SrcFunc.Name == "checkpwd" ||
+			pc.SrcFunc.Name == "checkpwdlock") {
 			addCheckPwd(pc, pc.valueMatrix[idx].Values, dst)
 
+		} else if pc.SrcFunc != nil && pc.SrcFunc.Name == "editdistance" {
+			addEditDistance(pc, pc.valueMatrix[idx].Values, dst)
+
+		} else if pc.SrcFunc != nil && pc.SrcFunc.Name == "jsonpath" {
+			if err := addJSONPath(pc, pc.valueMatrix[idx].Values, dst); err != nil {
+				return err
+			}
+
 		} else if idx < len(pc.uidMatrix) && len(pc.uidMatrix[idx].Uids) > 0 {
 			var fcsList []*pb.Facets
 			if pc.Params.Facet != nil {
@@ -672,13 +1036,19 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 			// We create as many predicate entity children as the length of uids for
 			// this predicate.
 			ul := pc.uidMatrix[idx]
+			if err := tr.checkFanout(len(ul.Uids)); err != nil {
+				return err
+			}
 			for childIdx, childUID := range ul.Uids {
 				if fieldName == "" || (invalidUids != nil && invalidUids[childUID]) {
 					continue
 				}
-				uc := dst.New(fieldName)
-				if rerr := pc.preTraverse(childUID, uc); rerr != nil {
-					if rerr.Error() == "_INV_" {
+				uc := dst.NewChild(fieldName, pc.List)
+				if fj, ok := uc.(*fastJsonNode); ok {
+					fj.uid = childUID
+				}
+				if rerr := pc.preTraverse(tr, childUID, uc); rerr != nil {
+					if rerr == errInvalidUid {
 						if invalidUids == nil {
 							invalidUids = make(map[uint64]bool)
 						}
@@ -699,7 +1069,7 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 							return err
 						}
 
-						uc.AddValue(facetName(fieldName, f), fVal)
+						uc.AddFacet(facetName(fieldName, f), fVal)
 					}
 				}
 
@@ -715,7 +1085,7 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 				}
 			}
 			if pc.Params.uidCount && !(pc.Params.uidCountAlias == "" && pc.Params.Normalize) {
-				uc := dst.New(fieldName)
+				uc := dst.NewChild(fieldName, true)
 				c := types.ValueForType(types.IntID)
 				c.Value = int64(len(ul.Uids))
 				alias := pc.Params.uidCountAlias
@@ -725,6 +1095,10 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 				uc.AddValue(alias, c)
 				dst.AddListChild(fieldName, uc)
 			}
+			// The keys without an alias are dropped by @normalize.
+			if tr.moreFlags() && !pc.Params.Normalize {
+				addMoreFlag(pc, idx, fieldName, dst)
+			}
 		} else {
 			if pc.Params.Alias == "" && len(pc.Params.Langs) > 0 {
 				fieldName += "@"
@@ -736,16 +1110,16 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 				continue
 			}
 
-			if len(pc.facetsMatrix) > idx && len(pc.facetsMatrix[idx].FacetsList) > 0 {
-				// in case of Value we have only one Facets
-				for _, f := range pc.facetsMatrix[idx].FacetsList[0].Facets {
-					fVal, err := facets.ValFor(f)
-					if err != nil {
-						return err
-					}
-
-					dst.AddValue(facetName(fieldName, f), fVal)
+			// The objects pairing the values with their facets would be flattened by
+			// @normalize, which keeps the values as they are.
+			if tr.elementFacets() && !pc.Params.Normalize && pc.hasElementFacets(idx) {
+				if err := addElementValues(pc, idx, fieldName, dst); err != nil {
+					return err
 				}
+				continue
+			}
+			if err := addValueFacets(pc, idx, fieldName, dst); err != nil {
+				return err
 			}
 
 			if len(pc.valueMatrix) <= idx {
@@ -793,6 +1167,10 @@ func (sg *SubGraph) preTraverse(uid uint64, dst outputNode) error {
 		sg.Params.parentIds = (sg.Params.parentIds)[:len(sg.Params.parentIds)-1]
 	}
 
+	if len(typeNames) > 0 && !dst.IsEmpty() && !sg.Params.Normalize {
+		sg.addNodeTypes(typeNames, dst)
+	}
+
 	// Only for shortest path query we wan't to return uid always if there is
 	// nothing else at that level.
 	if (sg.Params.GetUid && !dst.IsEmpty()) || sg.Params.shortest {
@@ -25,6 +25,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/dgraph-io/dgraph/gql"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/x"
 )
@@ -91,7 +92,7 @@ func TestNormalizeJSONLimit(t *testing.T) {
 				types.ValueForType(types.StringID))
 		}
 	}
-	_, err := n.(*fastJsonNode).normalize()
+	_, err := n.(*fastJsonNode).normalize(gql.NormalizeArgs{})
 	require.Error(t, err, "Couldn't evaluate @normalize directive - too many results")
 }
 
@@ -116,7 +117,7 @@ func TestNormalizeJSONUid1(t *testing.T) {
 	child3.AddValue("attr3", types.ValueForType(types.StringID))
 	child2.AddListChild("child3", child3)
 
-	normalized, err := n.(*fastJsonNode).normalize()
+	normalized, err := n.(*fastJsonNode).normalize(gql.NormalizeArgs{})
 	require.NoError(t, err)
 	require.NotNil(t, normalized)
 	nn := (&fastJsonNode{}).New("root")
@@ -147,7 +148,7 @@ func TestNormalizeJSONUid2(t *testing.T) {
 	child3.AddValue(fmt.Sprintf("attr3"), types.ValueForType(types.StringID))
 	child2.AddListChild("child3", child3)
 
-	normalized, err := n.(*fastJsonNode).normalize()
+	normalized, err := n.(*fastJsonNode).normalize(gql.NormalizeArgs{})
 	require.NoError(t, err)
 	require.NotNil(t, normalized)
 	nn := (&fastJsonNode{}).New("root")
@@ -159,3 +160,100 @@ func TestNormalizeJSONUid2(t *testing.T) {
 	nn.(*fastJsonNode).encode(&b)
 	require.JSONEq(t, `{"alias":[{"___attr1":"","___attr2":"","uid":"0x3","attr3":""}]}`, b.String())
 }
+
+func normalizeArgsTestNode() outputNode {
+	str := func(s string) types.Val { return types.Val{Tid: types.StringID, Value: s} }
+	n := (&fastJsonNode{}).New("root")
+	child1 := n.New("child1")
+	child1.AddValue("name", str("Alice"))
+	child1.AddValue("city", str("Paris"))
+	n.AddListChild("child1", child1)
+
+	child2 := n.New("child2")
+	child2.AddValue("name", str("Bob"))
+	child2.AddValue("city", str("Paris"))
+	child2.AddValue("age", types.Val{Tid: types.IntID, Value: int64(32)})
+	child1.AddListChild("child2", child2)
+	return n
+}
+
+func TestNormalizeJSONArgs(t *testing.T) {
+	x.Config.NormalizeNodeLimit = 1e4
+
+	tests := []struct {
+		args gql.NormalizeArgs
+		out  string
+	}{
+		{args: gql.NormalizeArgs{},
+			out: `{"alias":[{"age":32,"city":["Paris","Paris"],"name":["Alice","Bob"]}]}`},
+		{args: gql.NormalizeArgs{KeepOrder: true},
+			out: `{"alias":[{"name":["Alice","Bob"],"city":["Paris","Paris"],"age":32}]}`},
+		{args: gql.NormalizeArgs{Suffix: true},
+			out: `{"alias":[{"age":32,"city":"Paris","city_1":"Paris","name":"Alice",` +
+				`"name_1":"Bob"}]}`},
+		{args: gql.NormalizeArgs{KeepOrder: true, Dedupe: true},
+			out: `{"alias":[{"name":["Alice","Bob"],"city":"Paris","age":32}]}`},
+		{args: gql.NormalizeArgs{Prefix: true},
+			out: `{"alias":[{"child1.child2.age":32,"child1.child2.city":"Paris",` +
+				`"child1.child2.name":"Bob","child1.city":"Paris","child1.name":"Alice"}]}`},
+	}
+	for _, tc := range tests {
+		normalized, err := normalizeArgsTestNode().(*fastJsonNode).normalize(tc.args)
+		require.NoError(t, err)
+		nn := (&fastJsonNode{}).New("root")
+		for _, c := range normalized {
+			nn.AddListChild("alias", &fastJsonNode{attrs: finishNormalize(c, tc.args)})
+		}
+
+		var b bytes.Buffer
+		nn.(*fastJsonNode).encode(&b)
+		require.Equal(t, tc.out, b.String(), "%+v", tc.args)
+	}
+}
+
+func TestDedupeNodes(t *testing.T) {
+	str := func(s string) types.Val { return types.Val{Tid: types.StringID, Value: s} }
+	node := func(attr string, uid uint64) *fastJsonNode {
+		return &fastJsonNode{attr: attr, isChild: true, uid: uid}
+	}
+
+	root := &fastJsonNode{attr: "_root_"}
+	a1 := node("a", 1)
+	a1.AddValue("name", str("A"))
+	friend := node("friend", 3)
+	friend.AddValue("name", str("C"))
+	friend.AddFacet("friend|close", types.Val{Tid: types.BoolID, Value: true})
+	a1.AddListChild("friend", friend)
+	root.AddListChild("a", a1)
+
+	b1 := node("b", 1)
+	b1.AddValue("name", str("A"))
+	b1.AddValue("age", types.Val{Tid: types.IntID, Value: int64(20)})
+	b2 := node("b", 2)
+	b2.AddValue("name", str("B"))
+	root.AddListChild("b", b1)
+	root.AddListChild("b", b2)
+
+	c3 := node("c", 3)
+	c3.AddValue("name", str("C"))
+	root.AddListChild("c", c3)
+
+	require.NoError(t, root.dedupeNodes())
+	var buf bytes.Buffer
+	root.encode(&buf)
+	require.JSONEq(t, `{
+		"a": [{"uid": "0x1"}],
+		"b": [{"uid": "0x1"}, {"name": "B"}],
+		"c": [{"uid": "0x3"}],
+		"nodes": {
+			"0x1": {"uid": "0x1", "name": "A", "friend": [{"uid": "0x3", "friend|close": true}],
+				"age": 20},
+			"0x3": {"uid": "0x3", "name": "C"}
+		}
+	}`, buf.String())
+
+	// The key of the nodes can't be taken by a block.
+	root = &fastJsonNode{attr: "_root_"}
+	root.AddListChild("nodes", node("nodes", 1))
+	require.Error(t, root.dedupeNodes())
+}
@@ -0,0 +1,107 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// isPredicatePattern tells if the predicate of has is a pattern matching several predicates,
+// where * matches any characters and ? any one character.
+func isPredicatePattern(pred string) bool {
+	return strings.ContainsAny(pred, "*?")
+}
+
+// patternRegexp returns the regular expression matching the predicates of the pattern.
+func patternRegexp(pattern string) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteByte('^')
+	for _, r := range pattern {
+		switch r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteByte('.')
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteByte('$')
+	re, err := regexp.Compile(b.String())
+	return re, errors.Wrapf(err, "while compiling the pattern %q", pattern)
+}
+
+// matchingPredicates returns the predicates of the schema matching the pattern, sorted. The
+// schema is read from the groups serving the predicates, without reading their data.
+func matchingPredicates(ctx context.Context, pattern string) ([]string, error) {
+	re, err := patternRegexp(pattern)
+	if err != nil {
+		return nil, err
+	}
+	schs, err := worker.GetSchemaOverNetwork(ctx, &pb.SchemaRequest{})
+	if err != nil {
+		return nil, err
+	}
+	var preds []string
+	for _, sch := range schs {
+		if re.MatchString(sch.Predicate) {
+			preds = append(preds, sch.Predicate)
+		}
+	}
+	sort.Strings(preds)
+	return preds, nil
+}
+
+// processPatternTask runs the task of has over each predicate matching the pattern of its
+// predicate, and merges the uids of the results.
+func processPatternTask(ctx context.Context, q *pb.Query) (*pb.Result, error) {
+	preds, err := matchingPredicates(ctx, q.Attr)
+	if err != nil {
+		return nil, err
+	}
+	out := &pb.Result{}
+	for _, pred := range preds {
+		pq := *q
+		pq.Attr = pred
+		res, err := worker.ProcessTaskOverNetwork(ctx, &pq)
+		if err != nil && strings.Contains(err.Error(), worker.ErrNonExistentTabletMessage) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		for i, l := range res.UidMatrix {
+			if i == len(out.UidMatrix) {
+				out.UidMatrix = append(out.UidMatrix, l)
+				continue
+			}
+			out.UidMatrix[i] = algo.MergeSorted([]*pb.List{out.UidMatrix[i], l})
+		}
+	}
+	if len(out.UidMatrix) == 0 {
+		out.UidMatrix = []*pb.List{{}}
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternRegexp(t *testing.T) {
+	re, err := patternRegexp("address.*")
+	require.NoError(t, err)
+	require.True(t, re.MatchString("address.city"))
+	require.True(t, re.MatchString("address."))
+	require.False(t, re.MatchString("addressXcity"))
+	require.False(t, re.MatchString("home.address.city"))
+
+	re, err = patternRegexp("<http://schema.org/?ame>")
+	require.NoError(t, err)
+	require.True(t, re.MatchString("<http://schema.org/name>"))
+	require.False(t, re.MatchString("<http://schema.org/names>"))
+
+	require.True(t, isPredicatePattern("a.*"))
+	require.False(t, isPredicatePattern("a.b"))
+}
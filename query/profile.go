@@ -0,0 +1,296 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// OutputProfile is the shape the JSON results are wrapped in, for the clients expecting the
+// documents of a given specification.
+type OutputProfile string
+
+const (
+	// ProfileDefault returns the nodes nested under the edges leading to them.
+	ProfileDefault OutputProfile = ""
+	// ProfileJSONAPI returns each block as a JSON:API document, with the nodes as resources
+	// identified by their uids.
+	ProfileJSONAPI OutputProfile = "jsonapi"
+	// ProfileHAL returns the nodes as HAL resources, with the nodes they lead to embedded.
+	ProfileHAL OutputProfile = "hal"
+)
+
+// ParseOutputProfile returns the profile of the given name. An empty name is the default
+// profile.
+func ParseOutputProfile(name string) (OutputProfile, error) {
+	switch p := OutputProfile(name); p {
+	case ProfileDefault, ProfileJSONAPI, ProfileHAL:
+		return p, nil
+	default:
+		return ProfileDefault, errors.Errorf("Invalid output profile %q: expected jsonapi or hal",
+			name)
+	}
+}
+
+// requestOutputProfile returns the output profile of the request, given either through
+// OutputProfileKey or, for gRPC clients, the output_profile metadata.
+func requestOutputProfile(ctx context.Context) OutputProfile {
+	p, _ := ctx.Value(OutputProfileKey).(OutputProfile)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md["output_profile"]) > 0 {
+		// Invalid values are ignored and the default profile applies.
+		if v, err := ParseOutputProfile(md["output_profile"][0]); err == nil {
+			p = v
+		}
+	}
+	return p
+}
+
+// defaultResourceType is the JSON:API type of the nodes whose dgraph.type isn't queried.
+var defaultResourceType = []byte(`"node"`)
+
+// objectNode returns the JSON object of the attrs.
+func objectNode(attr string, attrs []*fastJsonNode) *fastJsonNode {
+	n := &fastJsonNode{attr: attr, attrs: attrs}
+	if len(attrs) == 0 {
+		n.scalarVal = []byte("{}")
+	}
+	return n
+}
+
+// isNode tells if fj is a node of the result, rather than a value or an object computed for
+// the block, like a count or @groupby.
+func (fj *fastJsonNode) isNode() bool {
+	return fj.uid != 0 && len(fj.attrs) > 0
+}
+
+// isEmptyResult tells if fj only marks a block without results.
+func (fj *fastJsonNode) isEmptyResult() bool {
+	return len(fj.attrs) == 0 && fj.scalarVal == nil
+}
+
+// toProfile returns the result of the blocks in fj in the shape of the profile.
+func (fj *fastJsonNode) toProfile(p OutputProfile) *fastJsonNode {
+	switch p {
+	case ProfileJSONAPI:
+		return fj.toJSONAPI()
+	case ProfileHAL:
+		return fj.toHAL()
+	default:
+		return fj
+	}
+}
+
+// apiResource is a node of a JSON:API document.
+type apiResource struct {
+	typ           []byte
+	id            string
+	attributes    []*fastJsonNode
+	relationships []*apiRelationship
+}
+
+// apiRelationship is an edge leaving a resource, to the resources it lists.
+type apiRelationship struct {
+	name  string
+	toOne bool
+	data  []*apiLinkage
+}
+
+// apiLinkage refers to the resource an edge leads to, with the facets of the edge.
+type apiLinkage struct {
+	res  *apiResource
+	meta []*fastJsonNode
+}
+
+// jsonAPIDoc is the JSON:API document of a block. Each resource is listed once, either as the
+// primary data or among the included resources.
+type jsonAPIDoc struct {
+	resources map[uint64]*apiResource
+	data      []*apiResource
+	included  []*apiResource
+	results   []*fastJsonNode
+}
+
+// toJSONAPI returns the blocks in fj as JSON:API documents, keyed by the names of the blocks.
+func (fj *fastJsonNode) toJSONAPI() *fastJsonNode {
+	root := &fastJsonNode{attr: fj.attr}
+	for i := 0; i < len(fj.attrs); {
+		name := fj.attrs[i].attr
+		doc := &jsonAPIDoc{resources: make(map[uint64]*apiResource)}
+		var nodes []*fastJsonNode
+		for ; i < len(fj.attrs) && fj.attrs[i].attr == name; i++ {
+			switch a := fj.attrs[i]; {
+			case a.isNode():
+				nodes = append(nodes, a)
+				doc.data = append(doc.data, doc.resource(a, true))
+			case !a.isEmptyResult():
+				doc.results = append(doc.results, a)
+			}
+		}
+		for _, n := range nodes {
+			doc.merge(doc.resources[n.uid], n)
+		}
+		root.attrs = append(root.attrs, doc.encode(name))
+	}
+	return root
+}
+
+// resource returns the resource of the node n, adding it to the document if it's new.
+func (d *jsonAPIDoc) resource(n *fastJsonNode, primary bool) *apiResource {
+	if r, ok := d.resources[n.uid]; ok {
+		return r
+	}
+	r := &apiResource{id: fmt.Sprintf("%#x", n.uid)}
+	d.resources[n.uid] = r
+	if !primary {
+		d.included = append(d.included, r)
+	}
+	return r
+}
+
+// merge adds the fields of the node n to its resource. When a node is found at several places
+// with the same field, the field is taken from the first place.
+func (d *jsonAPIDoc) merge(r *apiResource, n *fastJsonNode) {
+	taken := make(map[string]bool, len(r.attributes)+len(r.relationships))
+	for _, a := range r.attributes {
+		taken[a.attr] = true
+	}
+	for _, rel := range r.relationships {
+		taken[rel.name] = true
+	}
+
+	rels := make(map[string]*apiRelationship)
+	for _, a := range n.attrs {
+		switch {
+		case taken[a.attr] || a.facet || a.attr == "uid":
+			// The field was taken from another place, or isn't a field of the node.
+		case a.isNode():
+			rel, ok := rels[a.attr]
+			if !ok {
+				rel = &apiRelationship{name: a.attr, toOne: !a.isChild}
+				rels[a.attr] = rel
+				r.relationships = append(r.relationships, rel)
+			}
+			link := &apiLinkage{res: d.resource(a, false)}
+			for _, f := range a.attrs {
+				if f.facet {
+					link.meta = append(link.meta, f)
+				}
+			}
+			rel.data = append(rel.data, link)
+			d.merge(link.res, a)
+		default:
+			if a.attr == "dgraph.type" && r.typ == nil && a.scalarVal != nil {
+				r.typ = a.scalarVal
+			}
+			r.attributes = append(r.attributes, a)
+		}
+	}
+}
+
+// encode returns the JSON:API document of the block of the given name.
+func (d *jsonAPIDoc) encode(name string) *fastJsonNode {
+	doc := &fastJsonNode{attr: name}
+	if len(d.data) == 0 {
+		doc.attrs = append(doc.attrs, makeScalarNode("data", false, []byte("[]"), false))
+	}
+	for _, r := range d.data {
+		doc.attrs = append(doc.attrs, r.encode("data"))
+	}
+	for _, r := range d.included {
+		doc.attrs = append(doc.attrs, r.encode("included"))
+	}
+	if len(d.results) > 0 {
+		// The results which aren't nodes, like counts, are kept as they are.
+		results := make([]*fastJsonNode, 0, len(d.results))
+		for _, res := range d.results {
+			cp := *res
+			cp.attr = "results"
+			cp.isChild = true
+			results = append(results, &cp)
+		}
+		doc.attrs = append(doc.attrs, objectNode("meta", results))
+	}
+	return doc
+}
+
+// identifier returns the type and id members identifying the resource.
+func (r *apiResource) identifier() []*fastJsonNode {
+	typ := r.typ
+	if typ == nil {
+		typ = defaultResourceType
+	}
+	return []*fastJsonNode{
+		makeScalarNode("type", false, typ, false),
+		makeScalarNode("id", false, []byte(fmt.Sprintf("%q", r.id)), false),
+	}
+}
+
+// encode returns the resource object, added to a list under attr.
+func (r *apiResource) encode(attr string) *fastJsonNode {
+	res := &fastJsonNode{attr: attr, isChild: true, attrs: r.identifier()}
+	if len(r.attributes) > 0 {
+		res.attrs = append(res.attrs, objectNode("attributes", r.attributes))
+	}
+	if len(r.relationships) == 0 {
+		return res
+	}
+	rels := make([]*fastJsonNode, 0, len(r.relationships))
+	for _, rel := range r.relationships {
+		data := make([]*fastJsonNode, 0, len(rel.data))
+		for _, link := range rel.data {
+			l := &fastJsonNode{attr: "data", isChild: !rel.toOne, attrs: link.res.identifier()}
+			if len(link.meta) > 0 {
+				l.attrs = append(l.attrs, objectNode("meta", link.meta))
+			}
+			data = append(data, l)
+		}
+		rels = append(rels, objectNode(rel.name, data))
+	}
+	res.attrs = append(res.attrs, objectNode("relationships", rels))
+	return res
+}
+
+// toHAL returns the nodes in fj as HAL resources, with a link to themselves and the nodes
+// their edges lead to embedded.
+func (fj *fastJsonNode) toHAL() *fastJsonNode {
+	if len(fj.attrs) == 0 {
+		return fj
+	}
+	out := *fj
+	out.attrs = make([]*fastJsonNode, 0, len(fj.attrs)+2)
+	if fj.isNode() {
+		self := makeScalarNode("href", false, []byte(fmt.Sprintf("\"%#x\"", fj.uid)), false)
+		out.attrs = append(out.attrs, objectNode("_links",
+			[]*fastJsonNode{objectNode("self", []*fastJsonNode{self})}))
+	}
+	var embedded []*fastJsonNode
+	for _, a := range fj.attrs {
+		if fj.isNode() && a.isNode() {
+			embedded = append(embedded, a.toHAL())
+			continue
+		}
+		out.attrs = append(out.attrs, a.toHAL())
+	}
+	if len(embedded) > 0 {
+		out.attrs = append(out.attrs, objectNode("_embedded", embedded))
+	}
+	return &out
+}
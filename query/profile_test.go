@@ -0,0 +1,103 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+// profileTestNode returns the result of a block a with the node 0x1, its friends 0x2 and 0x3,
+// and a block b counting the nodes.
+func profileTestNode() *fastJsonNode {
+	str := func(s string) types.Val { return types.Val{Tid: types.StringID, Value: s} }
+	root := &fastJsonNode{attr: "_root_"}
+	a := &fastJsonNode{attr: "a", isChild: true, uid: 1}
+	a.AddValue("name", str("A"))
+	for _, uid := range []uint64{2, 3} {
+		friend := &fastJsonNode{uid: uid}
+		friend.AddValue("name", str(map[uint64]string{2: "B", 3: "C"}[uid]))
+		if uid == 2 {
+			friend.AddListValue("dgraph.type", str("Person"), true)
+			friend.AddFacet("friend|close", types.Val{Tid: types.BoolID, Value: true})
+		}
+		a.AddListChild("friend", friend)
+	}
+	root.AddListChild("a", a)
+
+	count := &fastJsonNode{}
+	count.AddValue("count", types.Val{Tid: types.IntID, Value: int64(3)})
+	root.AddListChild("b", count)
+	return root
+}
+
+func TestJSONAPIProfile(t *testing.T) {
+	var buf bytes.Buffer
+	profileTestNode().toProfile(ProfileJSONAPI).encode(&buf)
+	require.JSONEq(t, `{
+		"a": {
+			"data": [{"type": "node", "id": "0x1", "attributes": {"name": "A"},
+				"relationships": {"friend": {"data": [
+					{"type": "Person", "id": "0x2", "meta": {"friend|close": true}},
+					{"type": "node", "id": "0x3"}]}}}],
+			"included": [
+				{"type": "Person", "id": "0x2",
+					"attributes": {"name": "B", "dgraph.type": ["Person"]}},
+				{"type": "node", "id": "0x3", "attributes": {"name": "C"}}]
+		},
+		"b": {"data": [], "meta": {"results": [{"count": 3}]}}
+	}`, buf.String())
+}
+
+func TestHALProfile(t *testing.T) {
+	var buf bytes.Buffer
+	profileTestNode().toProfile(ProfileHAL).encode(&buf)
+	require.JSONEq(t, `{
+		"a": [{"_links": {"self": {"href": "0x1"}}, "name": "A",
+			"_embedded": {"friend": [
+				{"_links": {"self": {"href": "0x2"}}, "name": "B", "dgraph.type": ["Person"],
+					"friend|close": true},
+				{"_links": {"self": {"href": "0x3"}}, "name": "C"}]}}],
+		"b": [{"count": 3}]
+	}`, buf.String())
+}
+
+func TestParseOutputProfile(t *testing.T) {
+	p, err := ParseOutputProfile("")
+	require.NoError(t, err)
+	require.Equal(t, ProfileDefault, p)
+	p, err = ParseOutputProfile("jsonapi")
+	require.NoError(t, err)
+	require.Equal(t, ProfileJSONAPI, p)
+	p, err = ParseOutputProfile("hal")
+	require.NoError(t, err)
+	require.Equal(t, ProfileHAL, p)
+	_, err = ParseOutputProfile("xml")
+	require.Error(t, err)
+
+	ctx := context.WithValue(context.Background(), OutputProfileKey, ProfileHAL)
+	require.Equal(t, ProfileHAL, requestOutputProfile(ctx))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("output_profile", "jsonapi"))
+	require.Equal(t, ProfileJSONAPI, requestOutputProfile(ctx))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("output_profile", "xml"))
+	require.Equal(t, ProfileHAL, requestOutputProfile(ctx))
+}
@@ -146,14 +146,20 @@ type params struct {
 	// mapping of uid to values. This is populated into a SubGraph from a value variable that is
 	// part of req.Vars. This value variable variable would have been defined in some other query.
 	uidToVal map[uint64]types.Val
+	// subtotal tells the values are the subtotals of a rollup, set with the aggregation.
+	subtotal bool
 
 	// directives
-	Normalize   bool // True if @normalize directive is specified
-	Recurse     bool // True if @recurse directive is specified
-	RecurseArgs gql.RecurseArgs
+	Normalize     bool // True if @normalize directive is specified
+	NormalizeArgs gql.NormalizeArgs
+	Recurse       bool // True if @recurse directive is specified
+	RecurseArgs   gql.RecurseArgs
+	SampleArgs    gql.SampleArgs // Set if @sample directive is specified
 
 	Cascade      bool // True if @cascade directive is specified
 	IgnoreReflex bool // True if ignorereflex directive is specified.
+	Typed        bool // True if @typed directive is specified.
+	Approximate  bool // True if @approximate directive is specified.
 
 	// ShortestPathArgs contains the from and to functions to execute a shortest path query.
 	// The function is evaluated and the value of the nodes between which to run the shortest path
@@ -164,13 +170,40 @@ type params struct {
 	numPaths         int // used for k-shortest path query to specify number of paths to return.
 	MaxWeight        float64
 	MinWeight        float64
+	bidirectional    bool     // The shortest path is searched from both of its ends.
+	astar            string   // Geo predicate of the heuristic of A* shortest path searches.
+	Through          []uint64 // Nodes the shortest path must pass through, in order.
+	Avoid            []uint64 // Nodes the shortest path must not pass through.
+	// maxEdges is the most edges of the predicate a shortest path may take, if limitEdges.
+	maxEdges   int
+	limitEdges bool
 
 	// used by recurse and shortest path queries to specify the graph depth to explore.
 	ExploreDepth uint64
 
-	isInternal   bool   // Determines if processTask has to be called or not.
-	ignoreResult bool   // Node results are ignored.
-	Expand       string // Value is either _all_/variable-name or empty.
+	// JoinArgs are the value variables of a join block, whose nodes are paired on their values.
+	JoinArgs gql.JoinArgs
+
+	isInternal   bool         // Determines if processTask has to be called or not.
+	ignoreResult bool         // Node results are ignored.
+	limits       Limits       // Limits of the result, only set at the root.
+	floatFormat  FloatFormat  // Format of the floats of the result, only set at the root.
+	binaryFormat BinaryFormat // Format of the binary values of the result, only set at the root.
+	dedupeNodes  bool         // Lists the nodes found more than once apart, only set at the root.
+	defaultLangs []string     // Language chain of the @lang predicates queried without one.
+	validAt      []byte       // Time of the @at directive, at which the uid edges must be valid.
+	typeChild    bool         // Fetches the types of the nodes for the @typed directive.
+	Expand       string       // Value is either _all_/variable-name/quoted pattern or empty.
+	// outputProfile is the shape of the result, only set at the root.
+	outputProfile OutputProfile
+	// elementFacets pairs the values of list predicates with their facets, only set at the root.
+	elementFacets bool
+	// maxResponseBytes is the size over which the result is truncated, and cursor the position
+	// of a truncated result it continues from. They're only set at the root.
+	maxResponseBytes uint64
+	cursor           string
+	// moreFlags flags the paginated edges with more nodes than returned, only set at the root.
+	moreFlags bool
 
 	isGroupBy    bool              // True if @groupby is specified.
 	groupbyAttrs []gql.GroupByAttr // list of attributes to groupby.
@@ -181,6 +214,7 @@ type params struct {
 	IsEmpty       bool     // Won't have any SrcUids or DestUids. Only used to get aggregated vars
 	expandAll     bool     // expand all languages
 	shortest      bool
+	subgraph      bool // Returns the nodes and edges of the recursion as two lists.
 }
 
 type pathMetadata struct {
@@ -219,6 +253,8 @@ type SubGraph struct {
 	// uidMatrix is a slice of List. There would be one List corresponding to each uid in SrcUIDs.
 	// In graph terms, a list is a slice of outgoing edges from a node.
 	uidMatrix []*pb.List
+	// more tells, for each list of uidMatrix, if the pagination left out some of its uids.
+	more []bool
 
 	// facetsMatrix contains the facet values. There would a list corresponding to each uid in
 	// uidMatrix.
@@ -244,7 +280,18 @@ type SubGraph struct {
 	DestUIDs *pb.List
 	List     bool // whether predicate is of list type
 
+	// rootCount is the count(uid) of a count-only root block which the worker counted without
+	// returning its uids, see pushCount. It's set if countedAtRoot is true.
+	rootCount     int64
+	countedAtRoot bool
+
 	pathMeta *pathMetadata
+	// joinPairs are the pairs of nodes with equal values of a join block.
+	joinPairs []joinPair
+	// distinct are the distinct values of the predicate of a distinct block.
+	distinct []distinctValue
+	// histogram are the buckets of a histogram, with the counts of their values.
+	histogram []histogramBucket
 }
 
 func (sg *SubGraph) recurse(set func(sg *SubGraph)) {
@@ -290,6 +337,13 @@ func (sg *SubGraph) createSrcFunction(gf *gql.Function) {
 		return
 	}
 
+	// xid function is an alias for eq on the predicate declared with @xid.
+	if gf.Name == "xid" {
+		sg.Attr = gf.Attr
+		sg.SrcFunc.Name = "eq"
+		return
+	}
+
 	if gf.Lang != "" {
 		sg.Params.Langs = append(sg.Params.Langs, gf.Lang)
 	}
@@ -500,8 +554,11 @@ func treeCopy(gq *gql.GraphQuery, sg *SubGraph) error {
 			IgnoreReflex:   sg.Params.IgnoreReflex,
 			Langs:          gchild.Langs,
 			NeedsVar:       append(gchild.NeedsVar[:0:0], gchild.NeedsVar...),
+			defaultLangs:   sg.Params.defaultLangs,
+			validAt:        sg.Params.validAt,
 			Normalize:      sg.Params.Normalize,
 			Order:          gchild.Order,
+			Typed:          sg.Params.Typed,
 			Var:            gchild.Var,
 			groupbyAttrs:   gchild.GroupbyAttrs,
 			isGroupBy:      gchild.IsGroupby,
@@ -525,6 +582,9 @@ func treeCopy(gq *gql.GraphQuery, sg *SubGraph) error {
 		if err := args.fill(gchild); err != nil {
 			return err
 		}
+		if args.limitEdges && sg.Params.Alias != "shortest" {
+			return errors.Errorf("maxedges is only allowed inside shortest")
+		}
 
 		if len(args.Order) != 0 && len(args.FacetOrder) != 0 {
 			return errors.Errorf("Cannot specify order at both args and facets")
@@ -543,7 +603,10 @@ func treeCopy(gq *gql.GraphQuery, sg *SubGraph) error {
 		}
 
 		if gchild.Func != nil &&
-			(gchild.Func.IsAggregator() || gchild.Func.IsPasswordVerifier()) {
+			(gchild.Func.IsAggregator() || gchild.Func.IsWindowFunc() ||
+				gchild.Func.IsCustomFunc() || gchild.Func.IsPasswordVerifier() ||
+				gchild.Func.IsEditDistance() || gchild.Func.IsJSONPath() ||
+				gchild.Func.IsHistogram()) {
 			if len(gchild.Children) != 0 {
 				return errors.Errorf("Node with %q cant have child attr", gchild.Func.Name)
 			}
@@ -560,6 +623,10 @@ func treeCopy(gq *gql.GraphQuery, sg *SubGraph) error {
 				return errors.Errorf(`Argument cannot be "uid"`)
 			}
 			dst.createSrcFunction(gchild.Func)
+			if gchild.Func.IsHistogram() {
+				// The histograms are added once for the block, not for each of its nodes.
+				dst.Params.ignoreResult = true
+			}
 		}
 
 		if gchild.Filter != nil {
@@ -601,6 +668,16 @@ func (args *params) fill(gq *gql.GraphQuery) error {
 		}
 		args.AfterUID = after
 	}
+	if v, ok := gq.Args["orderrandom"]; ok {
+		order, err := randomOrder(v)
+		if err != nil {
+			return err
+		}
+		if len(args.Order) > 0 {
+			return errors.Errorf("orderrandom can't be used with other orders")
+		}
+		args.Order = []*pb.Order{order}
+	}
 
 	if args.Alias == "shortest" {
 		if v, ok := gq.Args["depth"]; ok {
@@ -639,6 +716,25 @@ func (args *params) fill(gq *gql.GraphQuery) error {
 			args.MinWeight = -math.MaxFloat64
 		}
 
+		if v, ok := gq.Args["bidirectional"]; ok {
+			bidirectional, err := strconv.ParseBool(v)
+			if err != nil {
+				return err
+			}
+			args.bidirectional = bidirectional
+		}
+		args.astar = gq.Args["astar"]
+		if args.bidirectional || args.astar != "" {
+			switch {
+			case args.bidirectional && args.astar != "":
+				return errors.Errorf("bidirectional and astar can't be used together")
+			case args.numPaths > 1:
+				return errors.Errorf("bidirectional and astar only return a single shortest path")
+			case args.bidirectional && args.ExploreDepth > 0:
+				return errors.Errorf("depth can't be used with bidirectional")
+			}
+		}
+
 		if gq.ShortestPathArgs.From == nil || gq.ShortestPathArgs.To == nil {
 			return errors.Errorf("from/to can't be nil for shortest path")
 		}
@@ -648,6 +744,22 @@ func (args *params) fill(gq *gql.GraphQuery) error {
 		if len(gq.ShortestPathArgs.To.UID) > 0 {
 			args.To = gq.ShortestPathArgs.To.UID[0]
 		}
+		if through := gq.ShortestPathArgs.Through; through != nil {
+			args.Through = append(args.Through, through.UID...)
+		}
+		if avoid := gq.ShortestPathArgs.Avoid; avoid != nil {
+			args.Avoid = append(args.Avoid, avoid.UID...)
+			sort.Slice(args.Avoid, func(i, j int) bool { return args.Avoid[i] < args.Avoid[j] })
+		}
+	}
+
+	if v, ok := gq.Args["maxedges"]; ok {
+		maxEdges, err := strconv.ParseUint(v, 0, 8)
+		if err != nil {
+			return errors.Errorf("maxedges should be a number between 0 and 255. Got: %s", v)
+		}
+		args.maxEdges = int(maxEdges)
+		args.limitEdges = true
 	}
 
 	if v, ok := gq.Args["first"]; ok {
@@ -662,14 +774,37 @@ func (args *params) fill(gq *gql.GraphQuery) error {
 
 // ToSubGraph converts the GraphQuery into the pb.SubGraph instance type.
 func ToSubGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
+	if err := resolveKeyFuncs(ctx, gq); err != nil {
+		return nil, err
+	}
+	if err := resolveXidFuncs(ctx, gq); err != nil {
+		return nil, err
+	}
 	sg, err := newGraph(ctx, gq)
 	if err != nil {
 		return nil, err
 	}
-	err = treeCopy(gq, sg)
+	if sg.Params.JoinArgs.Left != "" {
+		err = joinCopy(ctx, gq, sg)
+	} else {
+		err = treeCopy(gq, sg)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if sg.Params.Approximate {
+		if err := sg.checkApproximate(); err != nil {
+			return nil, err
+		}
+	}
+	if sg.isDistinct() {
+		if err := sg.checkDistinct(); err != nil {
+			return nil, err
+		}
+	}
+	if err := sg.checkHistograms(); err != nil {
+		return nil, err
+	}
 	return sg, err
 }
 
@@ -679,6 +814,34 @@ type ContextKey int
 const (
 	// DebugKey is the key used to toggle debug mode.
 	DebugKey ContextKey = iota
+	// LimitsKey is the key used to pass the Limits of a request.
+	LimitsKey
+	// FloatFormatKey is the key used to pass the FloatFormat of a request.
+	FloatFormatKey
+	// BinaryFormatKey is the key used to pass the BinaryFormat of a request.
+	BinaryFormatKey
+	// MaskKey is the key used to pass the Unmasker of a request. The values of the requests
+	// without it aren't masked.
+	MaskKey
+	// LangKey is the key used to pass the default language chain of a request.
+	LangKey
+	// DedupeNodesKey is the key used to ask for the nodes found more than once in the result
+	// to be listed apart.
+	DedupeNodesKey
+	// OutputProfileKey is the key used to pass the OutputProfile of a request.
+	OutputProfileKey
+	// ElementFacetsKey is the key used to ask for the values of list predicates to be paired
+	// with their facets.
+	ElementFacetsKey
+	// MaxResponseBytesKey is the key used to pass the size over which the response of a
+	// request is truncated.
+	MaxResponseBytesKey
+	// CursorKey is the key used to pass the cursor of the truncated response a request
+	// continues from.
+	CursorKey
+	// MoreFlagsKey is the key used to ask for the paginated edges with more nodes than
+	// returned to be flagged.
+	MoreFlagsKey
 )
 
 func isDebug(ctx context.Context) bool {
@@ -699,6 +862,19 @@ func isDebug(ctx context.Context) bool {
 	return debug || d
 }
 
+// boolOption tells if the boolean option of the request is set, given either through key or,
+// for gRPC clients, the metadata named mdKey. The metadata takes precedence, and invalid values
+// of it are ignored.
+func boolOption(ctx context.Context, key ContextKey, mdKey string) bool {
+	set, _ := ctx.Value(key).(bool)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md[mdKey]) > 0 {
+		if v, err := strconv.ParseBool(md[mdKey][0]); err == nil {
+			set = v
+		}
+	}
+	return set
+}
+
 func (sg *SubGraph) populate(uids []uint64) error {
 	// Put sorted entries in matrix.
 	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
@@ -708,6 +884,18 @@ func (sg *SubGraph) populate(uids []uint64) error {
 	return nil
 }
 
+// atTime parses the time of the @at directive, unix seconds or a datetime.
+func atTime(val string) (time.Time, error) {
+	if secs, err := strconv.ParseInt(val, 0, 64); err == nil {
+		return time.Unix(secs, 0), nil
+	}
+	t, err := types.ParseTime(val)
+	if err != nil {
+		return t, errors.Errorf("Expected a datetime or unix seconds in @at, got: %s", val)
+	}
+	return t, nil
+}
+
 // newGraph returns the SubGraph and its task query.
 func newGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
 	// This would set the Result field in SubGraph,
@@ -717,23 +905,43 @@ func newGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
 	// The attr at root (if present) would stand for the source functions attr.
 	args := params{
 		Alias:            gq.Alias,
+		Approximate:      gq.Approximate,
 		Cascade:          gq.Cascade,
 		GetUid:           isDebug(ctx),
 		IgnoreReflex:     gq.IgnoreReflex,
 		IsEmpty:          gq.IsEmpty,
 		Langs:            gq.Langs,
 		NeedsVar:         append(gq.NeedsVar[:0:0], gq.NeedsVar...),
+		limits:           requestLimits(ctx),
+		floatFormat:      requestFloatFormat(ctx),
+		binaryFormat:     requestBinaryFormat(ctx),
+		dedupeNodes:      requestDedupeNodes(ctx),
+		elementFacets:    requestElementFacets(ctx),
+		maxResponseBytes: requestMaxResponseBytes(ctx),
+		cursor:           requestCursor(ctx),
+		moreFlags:        requestMoreFlags(ctx),
+		outputProfile:    requestOutputProfile(ctx),
+		defaultLangs:     requestLangs(ctx),
 		Normalize:        gq.Normalize,
+		NormalizeArgs:    gq.NormalizeArgs,
 		Order:            gq.Order,
 		ParentVars:       make(map[string]varValue),
 		Recurse:          gq.Recurse,
 		RecurseArgs:      gq.RecurseArgs,
+		SampleArgs:       gq.SampleArgs,
 		ShortestPathArgs: gq.ShortestPathArgs,
+		JoinArgs:         gq.JoinArgs,
+		Typed:            gq.Typed,
 		Var:              gq.Var,
 		groupbyAttrs:     gq.GroupbyAttrs,
 		isGroupBy:        gq.IsGroupby,
 		uidCount:         gq.UidCount,
 		uidCountAlias:    gq.UidCountAlias,
+		subgraph:         gq.Subgraph,
+	}
+	if gq.Subgraph && (gq.Normalize || gq.IsGroupby || gq.UidCount || gq.Approximate) {
+		return nil, errors.Errorf("subgraph can't be used with @normalize, @groupby," +
+			" @approximate or count(uid)")
 	}
 
 	for argk := range gq.Args {
@@ -744,6 +952,15 @@ func newGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
 	if err := args.fill(gq); err != nil {
 		return nil, errors.Wrapf(err, "while filling args")
 	}
+	if gq.At != "" {
+		at, err := atTime(gq.At)
+		if err != nil {
+			return nil, err
+		}
+		if args.validAt, err = at.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
 
 	sg := &SubGraph{Params: args}
 
@@ -757,7 +974,8 @@ func newGraph(ctx context.Context, gq *gql.GraphQuery) (*SubGraph, error) {
 				return nil, errors.Errorf(`Argument cannot be "uid"`)
 			}
 		}
-		if !isValidFuncName(gq.Func.Name) {
+		// distinct is only valid at root, unlike the functions which filters can use too.
+		if !isValidFuncName(gq.Func.Name) && gq.Func.Name != distinctFunc {
 			return nil, errors.Errorf("Invalid function name: %s", gq.Func.Name)
 		}
 
@@ -825,7 +1043,9 @@ func createTaskQuery(sg *SubGraph) (*pb.Query, error) {
 		attr = strings.TrimPrefix(attr, "~")
 	}
 	var srcFunc *pb.SrcFunction
-	if sg.SrcFunc != nil {
+	// The values counted by approx_count_distinct in @groupby are fetched like the ones of the
+	// predicate.
+	if sg.SrcFunc != nil && sg.SrcFunc.Name != approxCountDistinct {
 		srcFunc = &pb.SrcFunction{}
 		srcFunc.Name = sg.SrcFunc.Name
 		srcFunc.IsCount = sg.SrcFunc.IsCount
@@ -834,6 +1054,10 @@ func createTaskQuery(sg *SubGraph) (*pb.Query, error) {
 			if arg.IsValueVar {
 				return nil, errors.Errorf("Unsupported use of value var")
 			}
+			if arg.IsOuter {
+				return nil, errors.Errorf("outer(%s) is only allowed in the filter of has_edge",
+					arg.Value)
+			}
 		}
 	}
 
@@ -843,11 +1067,18 @@ func createTaskQuery(sg *SubGraph) (*pb.Query, error) {
 		sg.Params.Langs = nil
 	}
 
+	// Values of @lang predicates queried without a language follow the default chain.
+	langs := sg.Params.Langs
+	if len(langs) == 0 && !sg.Params.expandAll && sg.SrcFunc == nil &&
+		len(sg.Params.defaultLangs) > 0 && schema.State().HasLang(attr) {
+		langs = sg.Params.defaultLangs
+	}
+
 	out := &pb.Query{
 		ReadTs:       sg.ReadTs,
 		Cache:        int32(sg.Cache),
 		Attr:         attr,
-		Langs:        sg.Params.Langs,
+		Langs:        langs,
 		Reverse:      reverse,
 		SrcFunc:      srcFunc,
 		AfterUid:     sg.Params.AfterUID,
@@ -855,6 +1086,7 @@ func createTaskQuery(sg *SubGraph) (*pb.Query, error) {
 		FacetParam:   sg.Params.Facet,
 		FacetsFilter: sg.facetsFilter,
 		ExpandAll:    sg.Params.expandAll,
+		ValidAt:      sg.Params.validAt,
 	}
 
 	if sg.SrcUIDs != nil {
@@ -886,12 +1118,19 @@ func evalLevelAgg(
 
 	needsVar := sg.Params.NeedsVar[0].Name
 	if parent.Params.IsEmpty {
+		if sg.SrcFunc.isRollup() {
+			return nil, errors.Errorf("Rollup of %s isn't allowed in a block without nodes",
+				aggWithVarFieldName(sg))
+		}
 		// The aggregated value doesn't really belong to a uid, we put it in uidToVal map
 		// corresponding to uid 0 to avoid defining another field in SubGraph.
 		vals := doneVars[needsVar].Vals
 		if len(vals) == 0 {
 			mp = make(map[uint64]types.Val)
 			mp[0] = types.Val{Tid: types.FloatID, Value: 0.0}
+			if sg.SrcFunc.Name == approxCountDistinct {
+				mp[0] = types.Val{Tid: types.IntID, Value: int64(0)}
+			}
 			return mp, nil
 		}
 
@@ -912,50 +1151,127 @@ func evalLevelAgg(
 		return mp, nil
 	}
 
-	var relSG *SubGraph
+	var relPath []*SubGraph
 	for _, ch := range parent.Children {
 		if sg == ch {
 			continue
 		}
-		for _, v := range ch.Params.FacetVar {
-			if v == needsVar {
-				relSG = ch
-			}
-		}
-		for _, cch := range ch.Children {
-			// Find the sibling node whose child has the required variable.
-			if cch.Params.Var == needsVar {
-				relSG = ch
-			}
+		if relPath = findVarPath(ch, needsVar); relPath != nil {
+			break
 		}
 	}
-	if relSG == nil {
+	if relPath == nil {
 		return nil, errors.Errorf("Invalid variable aggregation. Check the levels.")
 	}
 
-	vals := doneVars[needsVar].Vals
-	mp = make(map[uint64]types.Val)
-	// Go over the sibling node and aggregate.
-	for i, list := range relSG.uidMatrix {
-		ag := aggregator{
-			name: sg.SrcFunc.Name,
-		}
-		for _, uid := range list.Uids {
-			if val, ok := vals[uid]; ok {
-				ag.Apply(val)
+	var subtotals func(level *SubGraph, aggs map[uint64]*aggregator) error
+	if sg.SrcFunc.isRollup() {
+		// The subtotals at each level are output as a field of the nodes of the level.
+		fieldName := aggWithVarFieldName(sg)
+		subtotals = func(level *SubGraph, aggs map[uint64]*aggregator) error {
+			mp, err := aggregatorValues(aggs)
+			if err != nil {
+				return err
 			}
+			level.Children = append(level.Children, &SubGraph{
+				Attr: sg.Attr,
+				Params: params{
+					Alias:      fieldName,
+					isInternal: true,
+					subtotal:   true,
+					uidToVal:   mp,
+				},
+			})
+			return nil
 		}
+	}
+	aggs, err := aggregateOverPath(sg.SrcFunc.Name, doneVars[needsVar].Vals, relPath, subtotals)
+	if err != nil {
+		return nil, err
+	}
+	return aggregatorValues(aggs)
+}
+
+// aggregatorValues returns the values of the aggregations, leaving out the empty ones.
+func aggregatorValues(aggs map[uint64]*aggregator) (map[uint64]types.Val, error) {
+	mp := make(map[uint64]types.Val)
+	for uid, ag := range aggs {
 		v, err := ag.Value()
 		if err != nil && err != ErrEmptyVal {
 			return nil, err
 		}
 		if v.Value != nil {
-			mp[relSG.SrcUIDs.Uids[i]] = v
+			mp[uid] = v
 		}
 	}
 	return mp, nil
 }
 
+// findVarPath returns the chain of subgraphs starting at sg that leads to the definition
+// of the value variable, so that it can be aggregated at the level above sg. The values
+// of the variable are keyed by the destination uids of the last subgraph in the path.
+// Any edge, including a reverse one, can be part of the path.
+func findVarPath(sg *SubGraph, varName string) []*SubGraph {
+	for _, v := range sg.Params.FacetVar {
+		if v == varName {
+			return []*SubGraph{sg}
+		}
+	}
+	for _, ch := range sg.Children {
+		// Find the node whose child has the required variable.
+		if ch.Params.Var == varName {
+			return []*SubGraph{sg}
+		}
+	}
+	for _, ch := range sg.Children {
+		if ch.IsInternal() || len(ch.uidMatrix) == 0 {
+			continue
+		}
+		if path := findVarPath(ch, varName); path != nil {
+			return append([]*SubGraph{sg}, path...)
+		}
+	}
+	return nil
+}
+
+// aggregateOverPath aggregates vals, keyed by the destination uids of the last subgraph in
+// the path, one hop at a time up to the source uids of the first subgraph. A value
+// reachable through multiple paths contributes once per path. Partial results are merged
+// rather than re-aggregated, so avg is the average of all the values reached and not the
+// average of the averages at each hop. If subtotals isn't nil, it's called with the partial
+// results of every hop but the last one, keyed by the destination uids of the subgraph
+// passed to it.
+func aggregateOverPath(name string, vals map[uint64]types.Val, path []*SubGraph,
+	subtotals func(*SubGraph, map[uint64]*aggregator) error) (map[uint64]*aggregator, error) {
+	var prev map[uint64]*aggregator
+	for i := len(path) - 1; i >= 0; i-- {
+		if prev != nil && subtotals != nil {
+			if err := subtotals(path[i], prev); err != nil {
+				return nil, err
+			}
+		}
+		relSG := path[i]
+		cur := make(map[uint64]*aggregator)
+		for j, list := range relSG.uidMatrix {
+			ag := &aggregator{name: name}
+			for _, uid := range list.Uids {
+				if prev == nil {
+					if val, ok := vals[uid]; ok {
+						ag.Apply(val)
+					}
+				} else if child, ok := prev[uid]; ok {
+					ag.merge(child)
+				}
+			}
+			if ag.result.Value != nil {
+				cur[relSG.SrcUIDs.Uids[j]] = ag
+			}
+		}
+		prev = cur
+	}
+	return prev, nil
+}
+
 func (mt *mathTree) extractVarNodes() []*mathTree {
 	var nodeList []*mathTree
 	for _, ch := range mt.Child {
@@ -1045,8 +1361,8 @@ func (sg *SubGraph) transformVars(doneVars map[string]varValue, path []*SubGraph
 	return nil
 }
 
-func (sg *SubGraph) valueVarAggregation(doneVars map[string]varValue, path []*SubGraph,
-	parent *SubGraph) error {
+func (sg *SubGraph) valueVarAggregation(ctx context.Context, doneVars map[string]varValue,
+	path []*SubGraph, parent *SubGraph) error {
 	if !sg.IsInternal() && !sg.IsGroupBy() && !sg.Params.IsEmpty {
 		return nil
 	}
@@ -1055,6 +1371,10 @@ func (sg *SubGraph) valueVarAggregation(doneVars map[string]varValue, path []*Su
 	if sg.Params.IsEmpty && parent == nil {
 		return nil
 	}
+	// The subtotals of a rollup are set along with its aggregation.
+	if sg.Params.subtotal {
+		return nil
+	}
 
 	if sg.IsGroupBy() {
 		if err := sg.processGroupBy(doneVars, path); err != nil {
@@ -1072,6 +1392,29 @@ func (sg *SubGraph) valueVarAggregation(doneVars map[string]varValue, path []*Su
 			doneVars[sg.Params.Var] = it
 		}
 		sg.Params.uidToVal = mp
+	} else if sg.SrcFunc != nil && isCustomFn(sg.SrcFunc.Name) {
+		mp, err := evalCustomFn(ctx, doneVars, sg)
+		if err != nil {
+			return err
+		}
+		if sg.Params.Var != "" {
+			it := doneVars[sg.Params.Var]
+			it.Vals = mp
+			doneVars[sg.Params.Var] = it
+		}
+		sg.Params.uidToVal = mp
+	} else if sg.SrcFunc != nil && gql.IsWindowFunc(sg.SrcFunc.Name) {
+		// Rank the values of the variable across all the uids it was assigned to.
+		mp, err := evalWindowFn(doneVars, sg)
+		if err != nil {
+			return err
+		}
+		if sg.Params.Var != "" {
+			it := doneVars[sg.Params.Var]
+			it.Vals = mp
+			doneVars[sg.Params.Var] = it
+		}
+		sg.Params.uidToVal = mp
 	} else if sg.MathExp != nil {
 		// Preprocess to bring all variables to the same level.
 		err := sg.transformVars(doneVars, path)
@@ -1146,18 +1489,18 @@ func (sg *SubGraph) valueVarAggregation(doneVars map[string]varValue, path []*Su
 	return nil
 }
 
-func (sg *SubGraph) populatePostAggregation(doneVars map[string]varValue, path []*SubGraph,
-	parent *SubGraph) error {
+func (sg *SubGraph) populatePostAggregation(ctx context.Context, doneVars map[string]varValue,
+	path []*SubGraph, parent *SubGraph) error {
 	for idx := 0; idx < len(sg.Children); idx++ {
 		child := sg.Children[idx]
 		path = append(path, sg)
-		err := child.populatePostAggregation(doneVars, path, sg)
+		err := child.populatePostAggregation(ctx, doneVars, path, sg)
 		path = path[:len(path)-1]
 		if err != nil {
 			return err
 		}
 	}
-	return sg.valueVarAggregation(doneVars, path, parent)
+	return sg.valueVarAggregation(ctx, doneVars, path, parent)
 }
 
 // Filters might have updated the destuids. facetMatrix should also be updated to exclude uids that
@@ -1242,8 +1585,9 @@ func (sg *SubGraph) populateVarMap(doneVars map[string]varValue, sgPath []*SubGr
 		var exclude bool
 		for _, child := range sg.Children {
 			// For uid we dont actually populate the uidMatrix or values. So a node asking for
-			// uid would always be excluded. Therefore we skip it.
-			if child.Attr == "uid" {
+			// uid would always be excluded. Therefore we skip it. The types fetched for
+			// @typed aren't part of the result either.
+			if child.Attr == "uid" || child.Params.typeChild {
 				continue
 			}
 
@@ -1327,7 +1671,7 @@ func (sg *SubGraph) populateUidValVar(doneVars map[string]varValue, sgPath []*Su
 
 		val := types.Val{
 			Tid:   types.IntID,
-			Value: int64(len(sg.DestUIDs.Uids)),
+			Value: sg.uidCountValue(),
 		}
 		doneVars[sg.Params.Var].Vals[math.MaxUint64] = val
 	} else if len(sg.DestUIDs.Uids) != 0 || (sg.Attr == "uid" && sg.SrcUIDs != nil) {
@@ -1531,6 +1875,21 @@ func (sg *SubGraph) fillShortestPathVars(mp map[string]varValue) error {
 			sg.Params.To = uidVar.Uids.Uids[0]
 		}
 	}
+
+	if avoid := sg.Params.ShortestPathArgs.Avoid; avoid != nil && len(avoid.NeedsVar) > 0 {
+		lists := []*pb.List{{Uids: sg.Params.Avoid}}
+		for _, v := range avoid.NeedsVar {
+			uidVar, ok := mp[v.Name]
+			if !ok {
+				return errors.Errorf("value of avoid var(%s) should have already been populated",
+					v.Name)
+			}
+			if uidVar.Uids != nil {
+				lists = append(lists, uidVar.Uids)
+			}
+		}
+		sg.Params.Avoid = algo.MergeSorted(lists).Uids
+	}
 	return nil
 }
 
@@ -1793,6 +2152,13 @@ func expandSubgraph(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 			}
 			preds = append(preds, rpreds...)
 		default:
+			if pattern, err := strconv.Unquote(child.Params.Expand); err == nil {
+				span.Annotate(nil, "expand pattern")
+				if preds, err = matchingPredicates(ctx, pattern); err != nil {
+					return out, err
+				}
+				break
+			}
 			span.Annotate(nil, "expand default")
 			// We already have the predicates populated from the var.
 			preds = getPredsFromVals(child.ExpandPreds)
@@ -1805,7 +2171,10 @@ func expandSubgraph(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 				Attr:   pred,
 			}
 			temp.Params = child.Params
-			temp.Params.expandAll = child.Params.Expand == "_all_"
+			// With a default language chain, a single value of each predicate is returned
+			// instead of the values of all the languages.
+			temp.Params.expandAll = child.Params.Expand == "_all_" &&
+				len(child.Params.defaultLangs) == 0
 			temp.Params.ParentVars = make(map[string]varValue)
 			for k, v := range child.Params.ParentVars {
 				temp.Params.ParentVars[k] = v
@@ -1833,6 +2202,86 @@ func expandSubgraph(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 	return out, nil
 }
 
+// applyFilterResults keeps the DestUIDs of sg which pass its filters, from their DestUIDs.
+func (sg *SubGraph) applyFilterResults() {
+	var lists []*pb.List
+	for _, filter := range sg.Filters {
+		lists = append(lists, filter.DestUIDs)
+	}
+	if sg.FilterOp == "or" {
+		sg.DestUIDs = algo.MergeSorted(lists)
+	} else if sg.FilterOp == "not" {
+		x.AssertTrue(len(sg.Filters) == 1)
+		sg.DestUIDs = algo.Difference(sg.DestUIDs, sg.Filters[0].DestUIDs)
+	} else if sg.FilterOp == "and" {
+		sg.DestUIDs = algo.IntersectSorted(lists)
+	} else {
+		// We need to also intersect the original dest uids in this case to get the final
+		// DestUIDs.
+		// me(func: eq(key, "key1")) @filter(eq(key, "key2"))
+
+		// TODO - See if the server performing the filter can intersect with the srcUIDs before
+		// returning them in this case.
+		lists = append(lists, sg.DestUIDs)
+		sg.DestUIDs = algo.IntersectSorted(lists)
+	}
+}
+
+// processHasEdge keeps the nodes of the has_edge filter sg with an edge of its predicate leading
+// to a node matching its filters. When the filters compare with outer(pred), the nodes are
+// grouped by their values of pred, and the filters are run for each group with the values.
+func (sg *SubGraph) processHasEdge(ctx context.Context) error {
+	groups := []*SubGraph{sg.edgesOf(sg.SrcUIDs, sg.Filters)}
+	if hasOuterArgs(sg.Filters) {
+		var err error
+		if groups, err = sg.outerGroups(ctx); err != nil {
+			return err
+		}
+	}
+	rch := make(chan error, len(groups))
+	for _, edges := range groups {
+		go ProcessGraph(ctx, edges, sg, rch)
+	}
+	var edgesErr error
+	for range groups {
+		if err := <-rch; err != nil {
+			edgesErr = err
+		}
+	}
+	if edgesErr != nil {
+		return edgesErr
+	}
+
+	lists := make([]*pb.List, 0, len(groups))
+	for _, edges := range groups {
+		l := &pb.List{}
+		for i, ul := range edges.uidMatrix {
+			for _, uid := range ul.Uids {
+				if algo.IndexOf(edges.DestUIDs, uid) >= 0 {
+					l.Uids = append(l.Uids, edges.SrcUIDs.Uids[i])
+					break
+				}
+			}
+		}
+		lists = append(lists, l)
+	}
+	sg.DestUIDs = algo.MergeSorted(lists)
+	return nil
+}
+
+// edgesOf returns the SubGraph of the edges of the has_edge filter sg leaving the nodes in src,
+// filtered by filters.
+func (sg *SubGraph) edgesOf(src *pb.List, filters []*SubGraph) *SubGraph {
+	return &SubGraph{
+		Attr:    sg.Attr,
+		ReadTs:  sg.ReadTs,
+		Cache:   sg.Cache,
+		SrcUIDs: src,
+		Filters: filters,
+		Params:  params{ParentVars: sg.Params.ParentVars},
+	}
+}
+
 // ProcessGraph processes the SubGraph instance accumulating result for the query
 // from different instances. Note: taskQuery is nil for root node.
 func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
@@ -1847,6 +2296,11 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 	stop := x.SpanTimer(span, "query.ProcessGraph"+suffix)
 	defer stop()
 
+	if err := ctx.Err(); err != nil {
+		// Don't start processing the children of a cancelled query.
+		rch <- err
+		return
+	}
 	if sg.Attr == "uid" {
 		// We dont need to call ProcessGraph for uid, as we already have uids
 		// populated from parent and there is nothing to process but uidMatrix
@@ -1872,6 +2326,26 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 				return sg.DestUIDs.Uids[i] < sg.DestUIDs.Uids[j]
 			})
 		}
+		// The workers skip the uids up to after for the other roots, but these aren't read.
+		if sg.Params.AfterUID > 0 {
+			sg.applyAfter()
+		}
+	} else if sg.isHasEdge() {
+		// The filters of has_edge are run on the nodes the edges lead to, not on sg.
+		rch <- sg.processHasEdge(ctx)
+		return
+	} else if sg.Params.Approximate {
+		// The counts of @approximate blocks are all done by the workers.
+		rch <- sg.processApproximate(ctx)
+		return
+	} else if parent == nil && sg.isDistinct() {
+		// The values of distinct blocks are read from the index by the workers.
+		rch <- sg.processDistinct(ctx)
+		return
+	} else if sg.isHistogram() {
+		// The buckets of histograms are counted by the workers.
+		rch <- sg.processHistogram(ctx)
+		return
 	} else if len(sg.Attr) == 0 {
 		// This is when we have uid function in children.
 		if sg.SrcFunc != nil && sg.SrcFunc.Name == "uid" {
@@ -1930,7 +2404,14 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 				rch <- err
 				return
 			}
-			result, err := worker.ProcessTaskOverNetwork(ctx, taskQuery)
+			if parent == nil && sg.pushCount() {
+				taskQuery.DoCount = true
+			}
+			processTask := worker.ProcessTaskOverNetwork
+			if sg.SrcFunc != nil && sg.SrcFunc.Name == "has" && isPredicatePattern(sg.Attr) {
+				processTask = processPatternTask
+			}
+			result, err := processTask(ctx, taskQuery)
 			if err != nil && strings.Contains(err.Error(), worker.ErrNonExistentTabletMessage) {
 				sg.UnknownAttr = true
 			} else if err != nil {
@@ -1938,12 +2419,19 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 				return
 			}
 
+			if parent == nil && taskQuery.DoCount {
+				sg.setRootCount(result)
+				rch <- nil
+				return
+			}
+
 			sg.uidMatrix = result.UidMatrix
 			sg.valueMatrix = result.ValueMatrix
 			sg.facetsMatrix = result.FacetMatrix
 			sg.counts = result.Counts
 			sg.LangTags = result.LangMatrix
 			sg.List = result.List
+			sg.maskValues(ctx)
 
 			if sg.Params.DoCount {
 				if len(sg.Filters) == 0 {
@@ -2003,27 +2491,11 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 		}
 
 		// Now apply the results from filter.
-		var lists []*pb.List
-		for _, filter := range sg.Filters {
-			lists = append(lists, filter.DestUIDs)
-		}
-		if sg.FilterOp == "or" {
-			sg.DestUIDs = algo.MergeSorted(lists)
-		} else if sg.FilterOp == "not" {
-			x.AssertTrue(len(sg.Filters) == 1)
-			sg.DestUIDs = algo.Difference(sg.DestUIDs, sg.Filters[0].DestUIDs)
-		} else if sg.FilterOp == "and" {
-			sg.DestUIDs = algo.IntersectSorted(lists)
-		} else {
-			// We need to also intersect the original dest uids in this case to get the final
-			// DestUIDs.
-			// me(func: eq(key, "key1")) @filter(eq(key, "key2"))
+		sg.applyFilterResults()
+	}
 
-			// TODO - See if the server performing the filter can intersect with the srcUIDs before
-			// returning them in this case.
-			lists = append(lists, sg.DestUIDs)
-			sg.DestUIDs = algo.IntersectSorted(lists)
-		}
+	if parent == nil && sg.isSampled() {
+		sg.applySample()
 	}
 
 	if len(sg.Params.Order) == 0 && len(sg.Params.FacetOrder) == 0 {
@@ -2083,6 +2555,18 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 		}
 	}
 
+	if sg.Params.Typed && len(sg.Children) > 0 && !sg.IsGroupBy() {
+		// Fetch the types of the nodes so that the output only has the fields of their types.
+		sg.Children = append(sg.Children, &SubGraph{
+			Attr:   "dgraph.type",
+			ReadTs: sg.ReadTs,
+			Params: params{
+				ignoreResult: true,
+				typeChild:    true,
+			},
+		})
+	}
+
 	if len(sg.Children) > 0 {
 		// We store any variable defined by this node in the map and pass it on
 		// to the children which might depend on it. We only need to do this if the SubGraph
@@ -2140,6 +2624,19 @@ func ProcessGraph(ctx context.Context, sg, parent *SubGraph, rch chan error) {
 	rch <- childErr
 }
 
+// applyAfter drops the uids up to Params.AfterUID from the result of a uid() root.
+func (sg *SubGraph) applyAfter() {
+	after := sg.Params.AfterUID
+	keep := func(uid uint64, _ int) bool { return uid > after }
+	sg.DestUIDs = &pb.List{Uids: append(sg.DestUIDs.Uids[:0:0], sg.DestUIDs.Uids...)}
+	algo.ApplyFilter(sg.DestUIDs, keep)
+	for i, ul := range sg.uidMatrix {
+		l := &pb.List{Uids: append(ul.Uids[:0:0], ul.Uids...)}
+		algo.ApplyFilter(l, keep)
+		sg.uidMatrix[i] = l
+	}
+}
+
 // applyPagination applies count and offset to lists inside uidMatrix.
 func (sg *SubGraph) applyPagination(ctx context.Context) error {
 	if sg.Params.Count == 0 && sg.Params.Offset == 0 { // No pagination.
@@ -2147,9 +2644,11 @@ func (sg *SubGraph) applyPagination(ctx context.Context) error {
 	}
 
 	sg.updateUidMatrix()
+	sg.more = make([]bool, len(sg.uidMatrix))
 	for i := 0; i < len(sg.uidMatrix); i++ {
 		// Apply the offsets.
 		start, end := x.PageRange(sg.Params.Count, sg.Params.Offset, len(sg.uidMatrix[i].Uids))
+		sg.more[i] = pageMore(sg.Params.Count, start, end, len(sg.uidMatrix[i].Uids))
 		sg.uidMatrix[i].Uids = sg.uidMatrix[i].Uids[start:end]
 	}
 	// Re-merge the UID matrix.
@@ -2183,15 +2682,24 @@ func (sg *SubGraph) applyOrderAndPagination(ctx context.Context) error {
 	if sg.Params.Count == 0 {
 		// Only retrieve up to 1000 results by default.
 		sg.Params.Count = 1000
+		for _, ul := range sg.uidMatrix {
+			if len(ul.Uids)-sg.Params.Offset > sg.Params.Count {
+				AddWarning(ctx, "Ordered results of %s were limited to the first %d."+
+					" Use first to get more", sg.fieldName(), sg.Params.Count)
+				break
+			}
+		}
 	}
 
 	x.AssertTrue(len(sg.Params.Order) > 0)
 
+	// One more uid is sorted, to tell if the lists have more uids than the page. The uids
+	// without a value for the order are dropped by the sort, so their length can't tell.
 	sort := &pb.SortMessage{
 		Order:     sg.Params.Order,
 		UidMatrix: sg.uidMatrix,
 		Offset:    int32(sg.Params.Offset),
-		Count:     int32(sg.Params.Count),
+		Count:     int32(widenPage(sg.Params.Count)),
 		ReadTs:    sg.ReadTs,
 	}
 	result, err := worker.SortOverNetwork(ctx, sort)
@@ -2200,6 +2708,10 @@ func (sg *SubGraph) applyOrderAndPagination(ctx context.Context) error {
 	}
 
 	x.AssertTrue(len(result.UidMatrix) == len(sg.uidMatrix))
+	sg.more = make([]bool, len(result.UidMatrix))
+	for i, ul := range result.UidMatrix {
+		sg.more[i] = trimPage(ul, sg.Params.Count)
+	}
 	if sg.facetsMatrix != nil {
 		// The order of uids in the lists which are part of the uidMatrix would have been changed
 		// after sort. We want to update the order of lists in the facetMatrix accordingly.
@@ -2291,8 +2803,10 @@ func (sg *SubGraph) sortAndPaginateUsingFacet(ctx context.Context) error {
 
 	if sg.Params.Count != 0 || sg.Params.Offset != 0 {
 		// Apply the pagination.
+		sg.more = make([]bool, len(sg.uidMatrix))
 		for i := 0; i < len(sg.uidMatrix); i++ {
 			start, end := x.PageRange(sg.Params.Count, sg.Params.Offset, len(sg.uidMatrix[i].Uids))
+			sg.more[i] = pageMore(sg.Params.Count, start, end, len(sg.uidMatrix[i].Uids))
 			sg.uidMatrix[i].Uids = sg.uidMatrix[i].Uids[start:end]
 			// We also have to paginate the facetsMatrix for safety.
 			sg.facetsMatrix[i].FacetsList = sg.facetsMatrix[i].FacetsList[start:end]
@@ -2328,7 +2842,9 @@ func (sg *SubGraph) sortAndPaginateUsingVar(ctx context.Context) error {
 		if len(values) == 0 {
 			continue
 		}
-		if err := types.Sort(values, &pb.List{Uids: uids}, []bool{sg.Params.Order[0].Desc}); err != nil {
+		order := sg.Params.Order[0]
+		if err := types.SortCollated(values, &pb.List{Uids: uids}, []bool{order.Desc},
+			[]string{order.Collation}); err != nil {
 			return err
 		}
 		sg.uidMatrix[i].Uids = uids
@@ -2336,8 +2852,10 @@ func (sg *SubGraph) sortAndPaginateUsingVar(ctx context.Context) error {
 
 	if sg.Params.Count != 0 || sg.Params.Offset != 0 {
 		// Apply the pagination.
+		sg.more = make([]bool, len(sg.uidMatrix))
 		for i := 0; i < len(sg.uidMatrix); i++ {
 			start, end := x.PageRange(sg.Params.Count, sg.Params.Offset, len(sg.uidMatrix[i].Uids))
+			sg.more[i] = pageMore(sg.Params.Count, start, end, len(sg.uidMatrix[i].Uids))
 			sg.uidMatrix[i].Uids = sg.uidMatrix[i].Uids[start:end]
 		}
 	}
@@ -2350,8 +2868,9 @@ func (sg *SubGraph) sortAndPaginateUsingVar(ctx context.Context) error {
 // isValidArg checks if arg passed is valid keyword.
 func isValidArg(a string) bool {
 	switch a {
-	case "numpaths", "from", "to", "orderasc", "orderdesc", "first", "offset", "after", "depth",
-		"minweight", "maxweight":
+	case "numpaths", "from", "to", "orderasc", "orderdesc", "orderrandom", "collation", "first",
+		"offset", "after", "depth", "minweight", "maxweight", "bidirectional", "astar",
+		"maxedges":
 		return true
 	}
 	return false
@@ -2361,7 +2880,8 @@ func isValidArg(a string) bool {
 func isValidFuncName(f string) bool {
 	switch f {
 	case "anyofterms", "allofterms", "val", "regexp", "anyoftext", "alloftext",
-		"has", "uid", "uid_in", "anyof", "allof", "type", "match":
+		"has", "uid", "uid_in", "anyof", "allof", "type", "match", "xid", "key", "has_edge",
+		"prefix", "suffix", "containsany", "containsall":
 		return true
 	}
 	return isInequalityFn(f) || types.IsGeoFunc(f)
@@ -2377,12 +2897,18 @@ func isInequalityFn(f string) bool {
 
 func isAggregatorFn(f string) bool {
 	switch f {
-	case "min", "max", "sum", "avg":
+	case "min", "max", "sum", "avg", approxCountDistinct:
 		return true
 	}
 	return false
 }
 
+// isRollup tells if the aggregation also outputs its subtotals at the levels between it and its
+// variable, as asked for with sum(val(x), rollup).
+func (f *Function) isRollup() bool {
+	return f != nil && isAggregatorFn(f.Name) && len(f.Args) > 0 && f.Args[0].Value == "rollup"
+}
+
 func isUidFnWithoutVar(f *gql.Function) bool {
 	return f != nil && f.Name == "uid" && len(f.NeedsVar) == 0
 }
@@ -2465,7 +2991,9 @@ func (sg *SubGraph) getAllPredicates(predicates map[string]struct{}) {
 		predicates[sg.Attr] = struct{}{}
 	}
 	for _, o := range sg.Params.Order {
-		predicates[o.Attr] = struct{}{}
+		if !o.Random {
+			predicates[o.Attr] = struct{}{}
+		}
 	}
 	for _, pred := range sg.Params.groupbyAttrs {
 		predicates[pred.Attr] = struct{}{}
@@ -2526,6 +3054,9 @@ func (req *Request) ProcessQuery(ctx context.Context) (err error) {
 		if err != nil {
 			return errors.Wrapf(err, "while converting to subgraph")
 		}
+		if err := sg.checkMasked(ctx); err != nil {
+			return err
+		}
 		sg.recurse(func(sg *SubGraph) {
 			sg.ReadTs = req.ReadTs
 			sg.Cache = req.Cache
@@ -2594,18 +3125,31 @@ func (req *Request) ProcessQuery(ctx context.Context) (err error) {
 				continue
 			}
 
-			if sg.Params.Alias == "shortest" {
-				// We allow only one shortest path block per query.
-				go func() {
+			run := func(ctx context.Context) error {
+				switch {
+				case sg.Params.Alias == "shortest":
+					// We allow only one shortest path block per query.
+					var err error
 					shortestSg, err = shortestPath(ctx, sg)
-					errChan <- err
-				}()
-			} else if sg.Params.Recurse {
+					return err
+				case sg.Params.Recurse:
+					return recurse(ctx, sg)
+				case sg.Params.JoinArgs.Left != "":
+					return sg.processJoin(ctx, req.Vars)
+				default:
+					rch := make(chan error, 1)
+					ProcessGraph(ctx, sg, nil, rch)
+					return <-rch
+				}
+			}
+			if gq := queries[idx]; gq.BestEffort {
 				go func() {
-					errChan <- recurse(ctx, sg)
+					errChan <- runBestEffort(ctx, gq, sg, run)
 				}()
 			} else {
-				go ProcessGraph(ctx, sg, nil, errChan)
+				go func() {
+					errChan <- run(ctx)
+				}()
 			}
 		}
 
@@ -2629,7 +3173,18 @@ func (req *Request) ProcessQuery(ctx context.Context) (err error) {
 			if err := sg.populateVarMap(req.Vars, sgPath); err != nil {
 				return err
 			}
-			if err := sg.populatePostAggregation(req.Vars, []*SubGraph{}, nil); err != nil {
+			if queries[idx].BestEffort {
+				// The variables of a best effort block whose results were dropped are empty.
+				for _, v := range req.GqlQuery.QueryVars[idx].Defines {
+					if _, ok := req.Vars[v]; !ok {
+						req.Vars[v] = varValue{
+							Uids: &pb.List{},
+							Vals: make(map[uint64]types.Val),
+						}
+					}
+				}
+			}
+			if err := sg.populatePostAggregation(ctx, req.Vars, []*SubGraph{}, nil); err != nil {
 				return err
 			}
 		}
@@ -619,6 +619,42 @@ func TestHasFuncAtRootWithAfter(t *testing.T) {
 	require.JSONEq(t, `{"data": {"me":[{"friend":[{"count":1}],"name":"Rick Grimes","uid":"0x17"},{"friend":[{"count":1}],"name":"Andrea","uid":"0x1f"}]}}`, js)
 }
 
+func TestUidFuncAtRootWithAfter(t *testing.T) {
+
+	query := `
+	{
+		me(func: uid(0x01, 0x17, 0x1f), after: 0x01) {
+			uid
+			name
+		}
+	}
+	`
+
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me":[{"name":"Rick Grimes","uid":"0x17"},{"name":"Andrea","uid":"0x1f"}]}}`, js)
+}
+
+func TestVarAtRootWithAfter(t *testing.T) {
+
+	query := `
+	{
+		var(func: has(friend)) {
+			f as uid
+		}
+		var(func: uid(f), after: 0x01) {
+			g as uid
+		}
+		me(func: uid(g), first: 1) {
+			uid
+			name
+		}
+	}
+	`
+
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me":[{"name":"Rick Grimes","uid":"0x17"}]}}`, js)
+}
+
 func TestHasFuncAtRootFilter(t *testing.T) {
 
 	query := `
@@ -1607,8 +1643,8 @@ func TestMultiSort5(t *testing.T) {
 		}
 	}`
 	js := processQueryNoErr(t, query)
-	// Null value for third Alice comes at first.
-	require.JSONEq(t, `{"data": {"me":[{"name":"Alice","age":75},{"name":"Alice","age":75,"salary":10002.000000},{"name":"Alice","age":25,"salary":10000.000000},{"name":"Bob","age":25},{"name":"Bob","age":75},{"name":"Colin","age":25},{"name":"Elizabeth","age":25},{"name":"Elizabeth","age":75}]}}`, js)
+	// Null value for third Alice comes at first. The ties without a salary are ordered by uid.
+	require.JSONEq(t, `{"data": {"me":[{"name":"Alice","age":75},{"name":"Alice","age":75,"salary":10002.000000},{"name":"Alice","age":25,"salary":10000.000000},{"name":"Bob","age":75},{"name":"Bob","age":25},{"name":"Colin","age":25},{"name":"Elizabeth","age":75},{"name":"Elizabeth","age":25}]}}`, js)
 }
 
 func TestMultiSort6Paginate(t *testing.T) {
@@ -336,7 +336,7 @@ func TestKShortestPathWeighted(t *testing.T) {
 	// We only get one path in this case as the facet is present only in one path.
 	js := processQueryNoErr(t, query)
 	require.JSONEq(t,
-		`{"data":{"_path_":[{"uid":"0x1","_weight_":0.3,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		`{"data":{"_path_":[{"uid":"0x1","_weight_":0.30000000000000004,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
 		js)
 }
 
@@ -352,7 +352,7 @@ func TestKShortestPathWeightedMinMaxNoEffect(t *testing.T) {
 	// The path meets the weight requirements so it does not get filtered.
 	js := processQueryNoErr(t, query)
 	require.JSONEq(t,
-		`{"data":{"_path_":[{"uid":"0x1","_weight_":0.3,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		`{"data":{"_path_":[{"uid":"0x1","_weight_":0.30000000000000004,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
 		js)
 }
 
@@ -686,6 +686,234 @@ func TestShortestPathWeights(t *testing.T) {
 		js)
 }
 
+func TestShortestPathWeightExpression(t *testing.T) {
+
+	query := `
+		{
+			A as shortest(from:1, to:1002, weight: math(weight * 10)) {
+				path @facets(weight)
+			}
+
+			me(func: uid( A)) {
+				name
+			}
+		}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"me":[{"name":"Michonne"},{"name":"Andrea"},{"name":"Alice"},{"name":"Bob"},{"name":"Matt"}],"_path_":[{"uid":"0x1","_weight_":4,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path":{"uid":"0x3ea","path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		js)
+}
+
+func TestShortestPathWeightNodeValue(t *testing.T) {
+
+	// The age is the one of the node the edge leads to.
+	query := `
+		{
+			shortest(from:1, to:31, weight: math(weight + age)) {
+				path @facets(weight)
+			}
+		}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"_path_":[{"uid":"0x1","_weight_":19.1,"path":{"uid":"0x1f","path|weight":0.100000}}]}}`,
+		js)
+
+	// The edges to the nodes without an age are skipped.
+	query = `
+		{
+			A as shortest(from:1, to:1000, weight: math(age)) {
+				path
+			}
+
+			me(func: uid(A)) {
+				name
+			}
+		}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": []}}`, js)
+}
+
+func TestShortestPathNegativeWeight(t *testing.T) {
+
+	query := `
+		{
+			shortest(from:1, to:1002, weight: math(-weight)) {
+				path @facets(weight)
+			}
+		}`
+	_, err := processQuery(context.Background(), t, query)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "negative weight")
+}
+
+func TestShortestPathMaxWeight(t *testing.T) {
+
+	query := `
+		{
+			A as shortest(from:1, to:1002, maxweight: 0.3) {
+				path @facets(weight)
+			}
+
+			me(func: uid(A)) {
+				name
+			}
+		}`
+	// The shortest path weighs 0.4.
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": []}}`, js)
+
+	query = `
+		{
+			A as shortest(from:1, to:1002, maxweight: 0.4) {
+				path @facets(weight)
+			}
+
+			me(func: uid(A)) {
+				name
+			}
+		}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"me":[{"name":"Michonne"},{"name":"Andrea"},{"name":"Alice"},{"name":"Bob"},{"name":"Matt"}],"_path_":[{"uid":"0x1","_weight_":0.4,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path":{"uid":"0x3ea","path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		js)
+}
+
+func TestShortestPathBidirectional(t *testing.T) {
+	query := `
+		{
+			A as shortest(from:0x01, to:31, bidirectional: true) {
+				friend
+			}
+
+			me(func: uid( A)) {
+				name
+			}
+		}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data": {"_path_":[{"uid":"0x1", "_weight_": 1, "friend":{"uid":"0x1f"}}],"me":[{"name":"Michonne"},{"name":"Andrea"}]}}`,
+		js)
+
+	query = `
+		{
+			A as shortest(from:23, to:1, bidirectional: true) {
+				friend
+			}
+
+			me(func: uid( A)) {
+				name
+			}
+		}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data": {"_path_":[{"uid":"0x17","_weight_":1, "friend":{"uid":"0x1"}}],"me":[{"name":"Rick Grimes"},{"name":"Michonne"}]}}`,
+		js)
+}
+
+func TestShortestPathBidirectionalError(t *testing.T) {
+	for _, query := range []string{
+		// The backward search follows the reverse edges.
+		`{ shortest(from:1, to:1002, bidirectional: true) { path } }`,
+		`{ shortest(from:1, to:31, bidirectional: true, astar: loc) { friend } }`,
+		`{ shortest(from:1, to:31, bidirectional: true, numpaths: 2) { friend } }`,
+		`{ shortest(from:1, to:31, bidirectional: true, depth: 2) { friend } }`,
+		`{ shortest(from:1, to:31, bidirectional: true) { friend @filter(has(name)) } }`,
+	} {
+		_, err := processQuery(context.Background(), t, query)
+		require.Error(t, err, query)
+	}
+}
+
+func TestShortestPathAStar(t *testing.T) {
+	query := `
+		{
+			shortest(from:1, to:24, astar: loc) {
+				path @facets(weight)
+			}
+		}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"_path_":[{"uid":"0x1","_weight_":0.2,"path":{"uid":"0x18","path|weight":0.200000}}]}}`,
+		js)
+
+	// Without the location of the destination, the search is a Dijkstra search.
+	query = `
+		{
+			shortest(from:1, to:1002, astar: loc) {
+				path @facets(weight)
+			}
+		}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"_path_":[{"uid":"0x1","_weight_":0.4,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path":{"uid":"0x3ea","path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		js)
+}
+
+func TestShortestPathAvoid(t *testing.T) {
+	query := `
+		{
+			A as shortest(from:1, to:1002, avoid: uid(1001)) {
+				path @facets(weight)
+			}
+
+			me(func: uid(A)) {
+				name
+			}
+		}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"me":[{"name":"Michonne"},{"name":"Andrea"},{"name":"Alice"},{"name":"Matt"}],"_path_":[{"uid":"0x1","_weight_":0.8999999999999999,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3ea","path|weight":0.700000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		js)
+}
+
+func TestShortestPathThrough(t *testing.T) {
+	query := `
+		{
+			shortest(from:1, to:1003, through: uid(1002)) {
+				path @facets(weight)
+			}
+		}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"_path_":[{"uid":"0x1","_weight_":1,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3e9","path":{"uid":"0x3ea","path":{"uid":"0x3eb","path|weight":0.600000},"path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		js)
+}
+
+func TestShortestPathMaxEdges(t *testing.T) {
+	query := `
+		{
+			shortest(from:1, to:1002) {
+				path(maxedges: 2) @facets(weight)
+			}
+		}`
+	// The paths to 1002 take at least 3 edges.
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data":{}}`, js)
+
+	query = `
+		{
+			shortest(from:1, to:1002) {
+				path(maxedges: 3) @facets(weight)
+			}
+		}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t,
+		`{"data":{"_path_":[{"uid":"0x1","_weight_":0.8999999999999999,"path":{"uid":"0x1f","path":{"uid":"0x3e8","path":{"uid":"0x3ea","path|weight":0.700000},"path|weight":0.100000},"path|weight":0.100000}}]}}`,
+		js)
+}
+
+func TestShortestPathConstraintsError(t *testing.T) {
+	for _, query := range []string{
+		`{ me(func: uid(1)) { path(maxedges: 1) } }`,
+		`{ shortest(from:1, to:1002) { path(maxedges: 256) } }`,
+		`{ shortest(from:1, to:1002, numpaths: 2, avoid: uid(1001)) { path } }`,
+		`{ shortest(from:1, to:1002, astar: loc, through: uid(1001)) { path } }`,
+	} {
+		_, err := processQuery(context.Background(), t, query)
+		require.Error(t, err, query)
+	}
+}
+
 func TestShortestPath2(t *testing.T) {
 
 	query := `
@@ -23,8 +23,10 @@ import (
 	// "strings"
 	"testing"
 
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/dgraph-io/dgo/y"
 	"github.com/stretchr/testify/require"
-	// "google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestDeleteAndReaddIndex(t *testing.T) {
@@ -341,3 +343,345 @@ func TestTypeExpandLang(t *testing.T) {
 	require.JSONEq(t, `{"data": {"q":[
 		{"make":"Toyota","model":"Prius", "model@jp":"プリウス", "year":2009}]}}`, js)
 }
+
+func TestTypedDirective(t *testing.T) {
+	query := `{
+		q(func: uid(3, 6)) @typed {
+			name
+			best_friend {
+				name
+			}
+			pet {
+				name
+			}
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"q":[
+		{"name":"Margaret", "dgraph.type":["Person"],
+			"pet":[{"name":"Bear", "dgraph.type":["Animal","Pet"]}]},
+		{"name":"Bear", "dgraph.type":["Animal","Pet"]}
+	]}}`, js)
+}
+
+func TestTypedDirectiveWithType(t *testing.T) {
+	query := `{
+		q(func: uid(3)) @typed {
+			name
+			dgraph.type
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"q":[{"name":"Margaret", "dgraph.type":["Person"]}]}}`, js)
+}
+
+func TestKeyFunc(t *testing.T) {
+	s1 := testSchema + `
+		type Account @key(tenant, email) {
+			tenant: string
+			email: string
+			name: string
+		}
+		tenant: string @index(exact) .
+		email: string @index(hash) .
+	`
+	setSchema(s1)
+	triples := `
+		<0x4001> <tenant> "acme" .
+		<0x4001> <email> "ann@x" .
+		<0x4001> <name> "Ann" .
+		<0x4001> <dgraph.type> "Account" .
+		<0x4002> <tenant> "globex" .
+		<0x4002> <email> "ann@x" .
+		<0x4002> <name> "Ann G" .
+		<0x4002> <dgraph.type> "Account" .
+	`
+	addTriplesToCluster(triples)
+	defer deleteTriplesInCluster(triples)
+
+	query := `{
+		q(func: key(Account, "acme", "ann@x")) {
+			name
+		}
+		f(func: uid(0x4001, 0x4002)) @filter(key(Account, "globex", "ann@x")) {
+			name
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"q":[{"name":"Ann"}], "f":[{"name":"Ann G"}]}}`, js)
+
+	_, err := processQuery(context.Background(), t,
+		`{ q(func: key(Account, "acme")) { name } }`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(),
+		"key function of type Account expects the 2 values of (tenant, email), got 1")
+
+	_, err = processQuery(context.Background(), t, `{ q(func: key(Person, "acme")) { name } }`)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Type Person has no key declared with @key")
+
+	setSchema(testSchema)
+}
+
+func TestKeyConcurrentInsert(t *testing.T) {
+	setSchema(testSchema + `
+		type Account @key(tenant, email) {
+			tenant: string
+			email: string
+		}
+		tenant: string @index(exact) .
+		email: string @index(hash) .
+	`)
+	defer setSchema(testSchema)
+
+	// Neither transaction sees the node of the other one, so only the commit tells them apart.
+	ctx := context.Background()
+	mu := &api.Mutation{SetNquads: []byte(`
+		_:a <tenant> "initech" .
+		_:a <email> "bob@x" .
+		_:a <dgraph.type> "Account" .
+	`)}
+	txn1 := client.NewTxn()
+	defer txn1.Discard(ctx)
+	assigned, err := txn1.Mutate(ctx, mu)
+	require.NoError(t, err)
+	txn2 := client.NewTxn()
+	defer txn2.Discard(ctx)
+	_, err = txn2.Mutate(ctx, mu)
+	require.NoError(t, err)
+
+	require.NoError(t, txn1.Commit(ctx))
+	require.Equal(t, y.ErrAborted, txn2.Commit(ctx))
+	deleteTriplesInCluster("<" + assigned.Uids["a"] + "> * * .")
+}
+func TestBestEffortBlocks(t *testing.T) {
+	// The side block fails as noindex_name has no index, and its variable is empty.
+	query := `{
+		main(func: uid(1)) {
+			name
+		}
+		side(func: eq(noindex_name, "Michonne's name not indexed")) @besteffort {
+			x as name
+		}
+		other(func: uid(x)) {
+			name
+		}
+		fast(func: uid(1)) @budget(10s) {
+			name
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"main": [{"name": "Michonne"}], "side": [], "other": [],
+		"fast": [{"name": "Michonne"}]}}`, js)
+
+	_, err := processQuery(context.Background(), t,
+		`{ side(func: eq(noindex_name, "Michonne's name not indexed")) { name } }`)
+	require.Error(t, err)
+}
+
+func TestDedupeNodesQuery(t *testing.T) {
+	query := `{
+		me(func: uid(1)) {
+			name
+			friend(first: 1) {
+				name
+			}
+		}
+		other(func: uid(23)) {
+			name
+		}
+	}`
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "dedupe_nodes", "true")
+	js, err := processQuery(ctx, t, query)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"data": {
+		"me": [{"name": "Michonne", "friend": [{"uid": "0x17"}]}],
+		"other": [{"uid": "0x17"}],
+		"nodes": {"0x17": {"uid": "0x17", "name": "Rick Grimes"}}}}`, js)
+}
+
+func TestAggregationRollup(t *testing.T) {
+	query := `{
+		me(func: uid(1)) {
+			friend @filter(uid(23, 31)) {
+				friend {
+					a as age
+				}
+			}
+			total: sum(val(a), rollup)
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [{
+		"friend": [
+			{"friend": [{"age": 38}], "total": 38},
+			{"friend": [{"age": 15}], "total": 15}],
+		"total": 53}]}}`, js)
+
+	_, err := processQuery(context.Background(), t, `{
+		var(func: uid(1)) { friend { friend { a as age } } }
+		me() { sum(val(a), rollup) }
+	}`)
+	require.Error(t, err)
+}
+
+func TestJoinOnValues(t *testing.T) {
+	query := `{
+		var(func: uid(23, 24, 25)) { l as age }
+		var(func: uid(24, 31)) { r as age }
+		pairs(left: val(l), right: val(r)) {
+			left { name age }
+			right { name }
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"pairs": [
+		{"left": {"uid": "0x17", "name": "Rick Grimes", "age": 15},
+			"right": {"uid": "0x18", "name": "Glenn Rhee"}},
+		{"left": {"uid": "0x18", "name": "Glenn Rhee", "age": 15},
+			"right": {"uid": "0x18", "name": "Glenn Rhee"}}]}}`, js)
+
+	js = processQueryNoErr(t, `{
+		var(func: uid(25)) { l as age }
+		var(func: uid(24, 31)) { r as age }
+		pairs(left: val(l), right: val(r)) { left { name } }
+	}`)
+	require.JSONEq(t, `{"data": {"pairs": []}}`, js)
+
+	_, err := processQuery(context.Background(), t, `{
+		var(func: uid(23)) { l as age }
+		var(func: uid(24)) { r as age }
+		pairs(left: val(l), right: val(r)) { name }
+	}`)
+	require.Error(t, err)
+}
+
+func TestFilterHasEdge(t *testing.T) {
+	query := `{
+		me(func: uid(1, 23, 24, 31)) @filter(has_edge(friend, @filter(eq(name, "Glenn Rhee")))) {
+			name
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [{"name": "Michonne"}, {"name": "Andrea"}]}}`, js)
+
+	query = `{
+		me(func: uid(1, 23, 24, 31)) @filter(not has_edge(friend, @filter(eq(name, "Glenn Rhee")))) {
+			name
+		}
+	}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [{"name": "Rick Grimes"}, {"name": "Glenn Rhee"}]}}`, js)
+
+	query = `{
+		me(func: uid(1, 23, 24, 31)) @filter(not has_edge(friend)) {
+			name
+		}
+	}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [{"name": "Glenn Rhee"}]}}`, js)
+}
+
+func TestFilterHasEdgeOuter(t *testing.T) {
+	query := `{
+		me(func: uid(1, 23, 31)) @filter(has_edge(friend, @filter(gt(age, outer(age))))) {
+			name
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [{"name": "Rick Grimes"}]}}`, js)
+
+	query = `{
+		me(func: uid(1, 23, 31)) @filter(not has_edge(friend, @filter(gt(age, outer(age))))) {
+			name
+		}
+	}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [{"name": "Michonne"}, {"name": "Andrea"}]}}`, js)
+
+	_, err := processQuery(context.Background(), t, `{
+		me(func: uid(1, 23, 31)) @filter(gt(age, outer(age))) { name }
+	}`)
+	require.Error(t, err)
+}
+
+func TestPredicatePattern(t *testing.T) {
+	query := `{
+		me(func: uid(1, 23, 24)) @filter(has("ali?s")) {
+			name
+			expand("ali*")
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	// ali* matches alive as well as alias.
+	require.JSONEq(t, `{"data": {"me": [
+		{"name": "Rick Grimes", "alias": "Zambo Alice", "alive": true},
+		{"name": "Glenn Rhee", "alias": "John Alice"}]}}`, js)
+}
+
+func TestOrderByReverseCount(t *testing.T) {
+	query := `{
+		me(func: uid(1, 23, 24, 25, 31), orderdesc: count(~friend), first: 3) {
+			name
+			count(~friend)
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [
+		{"name": "Glenn Rhee", "count(~friend)": 2},
+		{"name": "Michonne", "count(~friend)": 1},
+		{"name": "Rick Grimes", "count(~friend)": 1}]}}`, js)
+}
+
+func TestOrderTiesByUid(t *testing.T) {
+	query := `{
+		me(func: uid(10000, 10001, 10002, 10003, 10004, 10005, 10006, 10007), orderasc: age,
+			offset: 2, first: 3) {
+			uid
+		}
+	}`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [
+		{"uid": "0x2716"}, {"uid": "0x2717"}, {"uid": "0x2711"}]}}`, js)
+
+	query = `{
+		me(func: uid(10000, 10001, 10002, 10003, 10004, 10005, 10006, 10007), orderdesc: age,
+			offset: 2, first: 3) {
+			uid
+		}
+	}`
+	js = processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data": {"me": [
+		{"uid": "0x2713"}, {"uid": "0x2714"}, {"uid": "0x2710"}]}}`, js)
+}
+
+func TestDistinctValues(t *testing.T) {
+	js := processQueryNoErr(t, `{ q(func: distinct(alive)) { count(uid) } }`)
+	require.JSONEq(t, `{"data": {"q": [
+		{"alive": false, "count": 2},
+		{"alive": true, "count": 2}]}}`, js)
+
+	js = processQueryNoErr(t, `{ q(func: distinct(alive), offset: 1) { } }`)
+	require.JSONEq(t, `{"data": {"q": [{"alive": true}]}}`, js)
+
+	_, err := processQuery(context.Background(), t, `{ q(func: distinct(alive)) { name } }`)
+	require.Error(t, err)
+}
+
+func TestHistogram(t *testing.T) {
+	js := processQueryNoErr(t, `{ q(func: uid(23, 24, 25, 31, 101)) {
+		ages: histogram(age, buckets: [0, 16, 18, 20])
+	} }`)
+	require.JSONEq(t, `{"data": {"q": [{"ages": [
+		{"from": 0, "to": 16, "count": 2},
+		{"from": 16, "to": 18, "count": 1},
+		{"from": 18, "to": 20, "count": 1}]}]}}`, js)
+
+	_, err := processQuery(context.Background(), t, `{ q(func: uid(1)) {
+		friend { histogram(age, buckets: [0, 16]) } } }`)
+	require.Error(t, err)
+
+	_, err = processQuery(context.Background(), t, `{ q(func: uid(1)) {
+		histogram(name, buckets: [0, 16]) } }`)
+	require.Error(t, err)
+}
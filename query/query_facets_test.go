@@ -902,7 +902,7 @@ func TestFacetsAlias2(t *testing.T) {
 	`
 
 	js := processQueryNoErr(t, query)
-	require.JSONEq(t, `{"data":{"me2":[{"friend":[{"friend|close":true,"f":false,"friend|since":"2005-05-02T15:04:05Z"},{"friend|since":"2006-01-02T15:04:05Z"},{"friend|since":"2006-01-02T15:04:05Z"},{"friend|close":true,"f":true,"friend|since":"2004-05-02T15:04:05Z","friend|tag":"Domain3"},{"friend|close":false,"f":true,"friend|since":"2007-05-02T15:04:05Z","friend|tag":34}]}],"me":[{"name":"Rick Grimes", "val(a)":"2006-01-02T15:04:05Z"}]}}`, js)
+	require.JSONEq(t, `{"data":{"me2":[{"friend":[{"friend|since":"2006-01-02T15:04:05Z"},{"friend|since":"2006-01-02T15:04:05Z"},{"friend|close":true,"f":false,"friend|since":"2005-05-02T15:04:05Z"},{"friend|close":true,"f":true,"friend|since":"2004-05-02T15:04:05Z","friend|tag":"Domain3"},{"friend|close":false,"f":true,"friend|since":"2007-05-02T15:04:05Z","friend|tag":34}]}],"me":[{"name":"Rick Grimes", "val(a)":"2006-01-02T15:04:05Z"}]}}`, js)
 }
 
 func TestTypeExpandFacets(t *testing.T) {
@@ -918,3 +918,59 @@ func TestTypeExpandFacets(t *testing.T) {
 		{"make":"Toyota","model":"Prius", "model@jp":"プリウス", "model|type":"Electric",
 			"year":2009}]}}`, js)
 }
+
+func TestFacetsValidAt(t *testing.T) {
+	setSchema(testSchema + "\n employer: [uid] @reverse @count .\n")
+	triples := `
+		<0x3001> <name> "Ann" .
+		<0x3002> <name> "Acme" .
+		<0x3003> <name> "Globex" .
+		<0x3004> <name> "Initech" .
+		<0x3001> <employer> <0x3002> (valid_from = 2010-01-01T00:00:00, valid_to = 2015-01-01T00:00:00) .
+		<0x3001> <employer> <0x3003> (valid_from = 2015-01-01T00:00:00) .
+		<0x3001> <employer> <0x3004> (valid_to = 1262304000) .
+	`
+	addTriplesToCluster(triples)
+	defer deleteTriplesInCluster(triples)
+
+	query := `
+		{
+			before(func: uid(0x3001)) @at(946684800) {
+				employer { name }
+			}
+			during(func: uid(0x3001)) @at("2012-06-01") {
+				employer { name }
+				count(employer)
+			}
+			after(func: uid(0x3001)) @at("2015-01-01T00:00:00Z") {
+				employer { name }
+			}
+			reverse(func: uid(0x3003)) @at("2012-06-01") {
+				~employer { name }
+			}
+			all(func: uid(0x3001)) {
+				count(employer)
+			}
+		}
+	`
+	js := processQueryNoErr(t, query)
+	require.JSONEq(t, `{"data":{
+		"before":[{"employer":[{"name":"Initech"}]}],
+		"during":[{"employer":[{"name":"Acme"}],"count(employer)":1}],
+		"after":[{"employer":[{"name":"Globex"}]}],
+		"reverse":[],
+		"all":[{"count(employer)":3}]}}`, js)
+}
+
+func TestFacetsValidAtError(t *testing.T) {
+	query := `
+		{
+			me(func: uid(0x1)) @at("yesterday") {
+				friend { name }
+			}
+		}
+	`
+	_, err := processQuery(context.Background(), t, query)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Expected a datetime or unix seconds in @at, got: yesterday")
+}
@@ -31,6 +31,9 @@ func (start *SubGraph) expandRecurse(ctx context.Context, maxDepth uint64) error
 	reachMap := make(map[string]struct{})
 	allowLoop := start.Params.RecurseArgs.AllowLoop
 	var numEdges uint64
+	x.ConfigMu.RLock()
+	edgeLimit := x.Config.QueryEdgeLimit
+	x.ConfigMu.RUnlock()
 	var exec []*SubGraph
 	var err error
 
@@ -161,10 +164,10 @@ func (start *SubGraph) expandRecurse(ctx context.Context, maxDepth uint64) error
 			out = append(out, exp...)
 		}
 
-		if numEdges > x.Config.QueryEdgeLimit {
+		if numEdges > edgeLimit {
 			// If we've seen too many edges, stop the query.
 			return errors.Errorf("Exceeded query edge limit = %v. Found %v edges.",
-				x.Config.QueryEdgeLimit, numEdges)
+				edgeLimit, numEdges)
 		}
 
 		if len(out) == 0 {
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// isSampled returns whether the block has the @sample directive.
+func (sg *SubGraph) isSampled() bool {
+	return sg.Params.SampleArgs.Count > 0 || sg.Params.SampleArgs.Percent > 0
+}
+
+// applySample keeps a random sample of the nodes of the root, after the filters and before
+// the ordering, pagination and traversal. Like the filters, it only updates DestUIDs.
+func (sg *SubGraph) applySample() {
+	sg.DestUIDs = sampleUids(sg.DestUIDs, sg.Params.SampleArgs)
+}
+
+// sampleUids returns a random sample of the sorted uids, in the same order. The sample only
+// depends on the seed and on the uids, so that queries with a seed return the same sample of
+// the same nodes.
+func sampleUids(uids *pb.List, args gql.SampleArgs) *pb.List {
+	n := len(uids.Uids)
+	k := n
+	if args.Count > 0 && args.Count < uint64(n) {
+		k = int(args.Count)
+	} else if args.Percent > 0 {
+		k = int(math.Round(float64(n) * args.Percent / 100))
+	}
+	if k >= n {
+		return uids
+	}
+
+	seed := args.Seed
+	if !args.HasSeed {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewSource(seed))
+	// Selection sampling keeps each uid with the probability that the number of uids still to
+	// pick is picked among the uids left.
+	out := make([]uint64, 0, k)
+	for i, uid := range uids.Uids {
+		if rng.Intn(n-i) < k-len(out) {
+			out = append(out, uid)
+		}
+	}
+	return &pb.List{Uids: out}
+}
+
+// randomOrder returns the random order of the orderrandom argument, which is either true or the
+// seed of the order. Orders without a seed are seeded when the query runs.
+func randomOrder(v string) (*pb.Order, error) {
+	if v == "true" {
+		return &pb.Order{Random: true, Seed: time.Now().UnixNano()}, nil
+	}
+	seed, err := strconv.ParseInt(v, 0, 64)
+	if err != nil {
+		return nil, errors.Errorf("orderrandom must be true or an integer seed. Got: %s", v)
+	}
+	return &pb.Order{Random: true, Seed: seed}, nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+func TestSampleUids(t *testing.T) {
+	uids := &pb.List{}
+	for i := uint64(1); i <= 1000; i++ {
+		uids.Uids = append(uids.Uids, i*3)
+	}
+
+	for _, args := range []gql.SampleArgs{
+		{Count: 10, Seed: 1, HasSeed: true},
+		{Count: 999},
+		{Percent: 2.5, Seed: 7, HasSeed: true},
+	} {
+		sample := sampleUids(uids, args)
+		if args.Count > 0 {
+			require.Len(t, sample.Uids, int(args.Count))
+		} else {
+			require.Len(t, sample.Uids, 25)
+		}
+		// The sample is a sorted subset of the uids.
+		require.Equal(t, sample.Uids, algo.IntersectSorted([]*pb.List{sample, uids}).Uids)
+	}
+
+	// The samples with the same seed are the same.
+	args := gql.SampleArgs{Count: 50, Seed: 42, HasSeed: true}
+	require.Equal(t, sampleUids(uids, args), sampleUids(uids, args))
+	args.Seed = 43
+	require.NotEqual(t, sampleUids(uids, gql.SampleArgs{Count: 50, Seed: 42, HasSeed: true}),
+		sampleUids(uids, args))
+
+	// All the uids are kept when there are fewer of them than requested.
+	require.Equal(t, uids, sampleUids(uids, gql.SampleArgs{Count: 5000}))
+	require.Equal(t, uids, sampleUids(uids, gql.SampleArgs{Percent: 100}))
+}
+
+func TestRandomOrder(t *testing.T) {
+	order, err := randomOrder("42")
+	require.NoError(t, err)
+	require.Equal(t, &pb.Order{Random: true, Seed: 42}, order)
+
+	order, err = randomOrder("true")
+	require.NoError(t, err)
+	require.True(t, order.Random)
+
+	_, err = randomOrder("name")
+	require.Error(t, err)
+}
@@ -20,12 +20,16 @@ import (
 	"container/heap"
 	"context"
 	"math"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/gql"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/types/facets"
+	"github.com/dgraph-io/dgraph/worker"
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/pkg/errors"
 )
@@ -47,6 +51,8 @@ type queueItem struct {
 	hop   int     // number of hops taken to reach this node.
 	index int
 	path  route // used in k shortest path.
+	// state of the node in constrained shortest path searches.
+	state *pathState
 }
 
 var pathPool = sync.Pool{
@@ -132,10 +138,289 @@ func (sg *SubGraph) getCost(matrix, list int) (cost float64,
 	return cost, fcs, rerr
 }
 
+// edgeFacets returns the facets of an edge, which the weight expression may use.
+func (sg *SubGraph) edgeFacets(matrix, list int) *pb.Facets {
+	if len(sg.facetsMatrix) <= matrix || len(sg.facetsMatrix[matrix].FacetsList) <= list {
+		return nil
+	}
+	return sg.facetsMatrix[matrix].FacetsList[list]
+}
+
+// weightedEdge is an edge found by expandOut, before it's added to the adjacency map.
+type weightedEdge struct {
+	from, to uint64
+	attr     string
+	facet    *pb.Facets
+	cost     float64
+}
+
+// evalWeights sets the cost of the edges to the value of the weight expression. Its names are
+// the facets of the edges, or else the predicates of the nodes the edges lead to. The edges
+// lacking the value of a name are skipped, like the ones lacking the facet used as their weight.
+func (sg *SubGraph) evalWeights(ctx context.Context, weight *gql.MathTree,
+	edges []weightedEdge) ([]weightedEdge, error) {
+	mt := &mathTree{}
+	if err := mathCopy(mt, weight); err != nil {
+		return nil, err
+	}
+	vars := mt.extractVarNodes()
+	vals := make(map[string]map[uint64]types.Val)
+	for _, v := range vars {
+		vals[v.Var] = make(map[uint64]types.Val)
+	}
+
+	// The values of the names which aren't facets of an edge are looked up on its destination.
+	lookups := make(map[string][]int)
+	for i, e := range edges {
+		for name, mp := range vals {
+			if tv, ok := facetValue(e.facet, name); ok {
+				mp[uint64(i)] = tv
+				continue
+			}
+			lookups[name] = append(lookups[name], i)
+		}
+	}
+	for name, idx := range lookups {
+		uids := make([]uint64, 0, len(idx))
+		for _, i := range idx {
+			uids = append(uids, edges[i].to)
+		}
+		nodeVals, err := sg.nodeValues(ctx, name, uids)
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range idx {
+			if tv, ok := nodeVals[edges[i].to]; ok {
+				vals[name][uint64(i)] = tv
+			}
+		}
+	}
+
+	// Only the edges having all the values are evaluated.
+	keep := make(map[uint64]bool, len(edges))
+	for i := range edges {
+		keep[uint64(i)] = true
+		for _, mp := range vals {
+			if _, ok := mp[uint64(i)]; !ok {
+				keep[uint64(i)] = false
+				break
+			}
+		}
+	}
+	for _, v := range vars {
+		v.Val = make(map[uint64]types.Val)
+		for k, tv := range vals[v.Var] {
+			if keep[k] {
+				v.Val[k] = tv
+			}
+		}
+	}
+	if err := evalMathTree(mt); err != nil {
+		return nil, errors.Wrapf(err, "while evaluating the weight of shortest path")
+	}
+
+	var out []weightedEdge
+	for i, e := range edges {
+		if !keep[uint64(i)] {
+			continue
+		}
+		tv := mt.Const
+		if tv.Value == nil {
+			var ok bool
+			if tv, ok = mt.Val[uint64(i)]; !ok {
+				continue
+			}
+		}
+		cost, err := toFloat(tv)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while evaluating the weight of shortest path")
+		}
+		if math.IsNaN(cost) {
+			continue
+		}
+		e.cost = cost
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// facetValue returns the value of the facet of the edge with the given key.
+func facetValue(fcs *pb.Facets, key string) (types.Val, bool) {
+	if fcs == nil {
+		return types.Val{}, false
+	}
+	for _, f := range fcs.Facets {
+		if f.Key != key {
+			continue
+		}
+		tv, err := facets.ValFor(f)
+		return tv, err == nil
+	}
+	return types.Val{}, false
+}
+
+// nodeValues returns the values of the predicate for the nodes which have one.
+func (sg *SubGraph) nodeValues(ctx context.Context, attr string,
+	uids []uint64) (map[uint64]types.Val, error) {
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	uids = algo.MergeSorted([]*pb.List{{Uids: uids}}).Uids
+	temp := &SubGraph{
+		Attr:    attr,
+		SrcUIDs: &pb.List{Uids: uids},
+		ReadTs:  sg.ReadTs,
+	}
+	taskQuery, err := createTaskQuery(temp)
+	if err != nil {
+		return nil, err
+	}
+	result, err := worker.ProcessTaskOverNetwork(ctx, taskQuery)
+	if err != nil {
+		return nil, err
+	}
+	vals := make(map[uint64]types.Val)
+	for i, list := range result.ValueMatrix {
+		if i >= len(uids) || len(list.Values) == 0 {
+			continue
+		}
+		tv, err := convertWithBestEffort(list.Values[0], attr)
+		if err != nil {
+			return nil, err
+		}
+		vals[uids[i]] = tv
+	}
+	return vals, nil
+}
+
+// reach keeps the cost of the cheapest path found so far from the source to the nodes, so that
+// the ones which can't be reached within maxweight aren't expanded.
+type reach struct {
+	cost      map[uint64]float64
+	maxWeight float64
+	// next are the nodes which can be reached within maxweight, and weren't expanded yet.
+	next map[uint64]struct{}
+}
+
+// relax updates the cost of reaching the destination of an edge and, if it was expanded, of the
+// nodes reached through it.
+func (r *reach) relax(adjacencyMap map[uint64]map[uint64]mapItem, from, to uint64,
+	cost float64) {
+	type step struct {
+		from, to uint64
+		cost     float64
+	}
+	steps := []step{{from: from, to: to, cost: cost}}
+	for len(steps) > 0 {
+		s := steps[len(steps)-1]
+		steps = steps[:len(steps)-1]
+		c := r.cost[s.from] + s.cost
+		if old, ok := r.cost[s.to]; (ok && old <= c) || c > r.maxWeight {
+			continue
+		}
+		r.cost[s.to] = c
+		neighbours, expanded := adjacencyMap[s.to]
+		if !expanded {
+			r.next[s.to] = struct{}{}
+			continue
+		}
+		for toUid, info := range neighbours {
+			steps = append(steps, step{from: s.to, to: toUid, cost: info.cost})
+		}
+	}
+}
+
+// frontier returns the sorted nodes to expand next, and resets them.
+func (r *reach) frontier() []uint64 {
+	uids := make([]uint64, 0, len(r.next))
+	for uid := range r.next {
+		uids = append(uids, uid)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	r.next = make(map[uint64]struct{})
+	return uids
+}
+
+// reverseAttr returns the predicate following the edges of attr the other way.
+func reverseAttr(attr string) string {
+	if strings.HasPrefix(attr, "~") {
+		return attr[1:]
+	}
+	return "~" + attr
+}
+
+// levelEdges returns the edges found by the subgraphs expanding a level of the shortest path
+// query, along with their cost. If backward is set, the subgraphs followed the reverse edges,
+// which are turned around.
+func (sg *SubGraph) levelEdges(ctx context.Context, exec []*SubGraph,
+	backward bool) ([]weightedEdge, error) {
+	weight := sg.Params.ShortestPathArgs.Weight
+	var edges []weightedEdge
+	for _, subgraph := range exec {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if subgraph.UnknownAttr {
+			continue
+		}
+		attr := subgraph.Attr
+		if backward {
+			attr = reverseAttr(attr)
+		}
+
+		for mIdx, fromUID := range subgraph.SrcUIDs.Uids {
+			// This can happen when trying to go traverse a predicate of type password
+			// for example.
+			if mIdx >= len(subgraph.uidMatrix) {
+				continue
+			}
+
+			for lIdx, toUID := range subgraph.uidMatrix[mIdx].Uids {
+				e := weightedEdge{from: fromUID, to: toUID, attr: attr}
+				if backward {
+					e.from, e.to = toUID, fromUID
+				}
+				if weight != nil {
+					// The cost is evaluated once all the edges of the level are known.
+					e.facet = subgraph.edgeFacets(mIdx, lIdx)
+					edges = append(edges, e)
+					continue
+				}
+				// The default cost we'd use is 1.
+				cost, facet, err := subgraph.getCost(mIdx, lIdx)
+				if err == errFacet {
+					// Ignore the edge and continue.
+					continue
+				} else if err != nil {
+					return nil, err
+				}
+				e.cost, e.facet = cost, facet
+				edges = append(edges, e)
+			}
+		}
+	}
+
+	if weight != nil {
+		var err error
+		if edges, err = sg.evalWeights(ctx, weight, edges); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range edges {
+		if e.cost < 0 {
+			return nil, errors.Errorf("Edge %s from %#x to %#x has negative weight %v. The"+
+				" weights of shortest path queries must not be negative.",
+				e.attr, e.from, e.to, e.cost)
+		}
+	}
+	return edges, nil
+}
+
 func (sg *SubGraph) expandOut(ctx context.Context,
 	adjacencyMap map[uint64]map[uint64]mapItem, next chan bool, rch chan error) {
 
 	var numEdges uint64
+	x.ConfigMu.RLock()
+	edgeLimit := x.Config.QueryEdgeLimit
+	x.ConfigMu.RUnlock()
 	var exec []*SubGraph
 	var err error
 	in := []uint64{sg.Params.From}
@@ -147,6 +432,14 @@ func (sg *SubGraph) expandOut(ctx context.Context,
 		child.SrcUIDs = sg.DestUIDs
 		exec = append(exec, child)
 	}
+	var pruned *reach
+	if sg.Params.MaxWeight < math.MaxFloat64 {
+		pruned = &reach{
+			cost:      map[uint64]float64{sg.Params.From: 0},
+			maxWeight: sg.Params.MaxWeight,
+			next:      make(map[uint64]struct{}),
+		}
+	}
 	dummy := &SubGraph{}
 	for {
 		isNext := <-next
@@ -172,56 +465,52 @@ func (sg *SubGraph) expandOut(ctx context.Context,
 		}
 
 		for _, subgraph := range exec {
-			select {
-			case <-ctx.Done():
-				rch <- ctx.Err()
-				return
-			default:
-				if subgraph.UnknownAttr {
-					continue
-				}
-
-				// Send the destuids in res chan.
-				for mIdx, fromUID := range subgraph.SrcUIDs.Uids {
-					// This can happen when trying to go traverse a predicate of type password
-					// for example.
-					if mIdx >= len(subgraph.uidMatrix) {
-						continue
-					}
-
-					for lIdx, toUID := range subgraph.uidMatrix[mIdx].Uids {
-						if adjacencyMap[fromUID] == nil {
-							adjacencyMap[fromUID] = make(map[uint64]mapItem)
-						}
-						// The default cost we'd use is 1.
-						cost, facet, err := subgraph.getCost(mIdx, lIdx)
-						if err == errFacet {
-							// Ignore the edge and continue.
-							continue
-						} else if err != nil {
-							rch <- err
-							return
-						}
-						adjacencyMap[fromUID][toUID] = mapItem{
-							cost:  cost,
-							facet: facet,
-							attr:  subgraph.Attr,
-						}
-						numEdges++
-					}
+			for _, fromUID := range subgraph.SrcUIDs.Uids {
+				if adjacencyMap[fromUID] == nil {
+					adjacencyMap[fromUID] = make(map[uint64]mapItem)
 				}
 			}
 		}
+		edges, err := sg.levelEdges(ctx, exec, false)
+		if err != nil {
+			rch <- err
+			return
+		}
+		for _, e := range edges {
+			adjacencyMap[e.from][e.to] = mapItem{
+				cost:  e.cost,
+				facet: e.facet,
+				attr:  e.attr,
+			}
+			numEdges++
+			if pruned != nil {
+				pruned.relax(adjacencyMap, e.from, e.to, e.cost)
+			}
+		}
 
-		if numEdges > x.Config.QueryEdgeLimit {
+		if numEdges > edgeLimit {
 			// If we've seen too many edges, stop the query.
 			rch <- errors.Errorf("Exceeded query edge limit = %v. Found %v edges.",
-				x.Config.QueryEdgeLimit, numEdges)
+				edgeLimit, numEdges)
 			return
 		}
 
 		// modify the exec and attach child nodes.
 		var out []*SubGraph
+		if pruned != nil {
+			// Only the nodes which can be reached within maxweight are expanded.
+			uids := pruned.frontier()
+			for _, child := range sg.Children {
+				if len(uids) == 0 {
+					break
+				}
+				temp := new(SubGraph)
+				temp.copyFiltersRecurse(child)
+				temp.SrcUIDs = &pb.List{Uids: uids}
+				out = append(out, temp)
+			}
+			exec = nil
+		}
 		for _, subgraph := range exec {
 			if len(subgraph.DestUIDs.Uids) == 0 {
 				continue
@@ -321,31 +610,33 @@ func runKShortestPaths(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 				break
 			}
 		}
-		if item.hop > numHops && numHops < maxHops {
+		if item.hop > numHops && numHops < maxHops && !stopExpansion {
 			// Explore the next level by calling processGraph and add them
 			// to the queue.
-			if !stopExpansion {
-				next <- true
-				select {
-				case err = <-expandErr:
-					if err != nil {
-						if err == errStop {
-							stopExpansion = true
-						} else {
-							return nil, err
-						}
+			next <- true
+			select {
+			case err = <-expandErr:
+				if err != nil {
+					// errStop tells there are no more levels to expand, the last one was
+					// still added to the adjacencyMap.
+					if err == errStop {
+						stopExpansion = true
+					} else {
+						return nil, err
 					}
-				case <-ctx.Done():
-					return nil, ctx.Err()
 				}
-				numHops++
+			case <-ctx.Done():
+				return nil, ctx.Err()
 			}
+			numHops++
 		}
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
-			if stopExpansion {
+			// Once there are no more levels, the nodes are still relaxed through the edges
+			// already in the adjacencyMap, up to the last level expanded.
+			if stopExpansion && item.hop > numHops {
 				continue
 			}
 		}
@@ -439,9 +730,22 @@ func shortestPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 		numPaths = 1
 	}
 
+	if sg.isConstrained() {
+		if numPaths > 1 || sg.Params.bidirectional || sg.Params.astar != "" {
+			return nil, errors.Errorf("through, avoid and maxedges can't be used with numpaths," +
+				" bidirectional or astar")
+		}
+		return constrainedPath(ctx, sg)
+	}
 	if numPaths > 1 {
 		return runKShortestPaths(ctx, sg)
 	}
+	if sg.Params.bidirectional {
+		return bidirectionalPath(ctx, sg)
+	}
+	if sg.Params.astar != "" {
+		return astarPath(ctx, sg)
+	}
 	pq := make(priorityQueue, 0)
 	heap.Init(&pq)
 
@@ -458,6 +762,7 @@ func shortestPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 	if maxHops == 0 {
 		maxHops = int(math.MaxInt32)
 	}
+	maxWeight := sg.Params.MaxWeight
 	next := make(chan bool, 2)
 	expandErr := make(chan error, 2)
 	adjacencyMap := make(map[uint64]map[uint64]mapItem)
@@ -481,12 +786,10 @@ func shortestPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 			totalWeight = item.cost
 			break
 		}
-		if item.hop > numHops && numHops < maxHops {
+		if item.hop > numHops && numHops < maxHops && !stopExpansion {
 			// Explore the next level by calling processGraph and add them
 			// to the queue.
-			if !stopExpansion {
-				next <- true
-			}
+			next <- true
 			select {
 			case err = <-expandErr:
 				if err != nil {
@@ -505,49 +808,53 @@ func shortestPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		default:
-			if !stopExpansion {
-				neighbours := adjacencyMap[item.uid]
-				for toUid, info := range neighbours {
-					cost := info.cost
-					d, ok := dist[toUid]
-					if ok && d.cost <= item.cost+cost {
-						continue
+			// The nodes are still relaxed through the edges already in the adjacencyMap once
+			// there are no more levels to expand.
+			neighbours := adjacencyMap[item.uid]
+			for toUid, info := range neighbours {
+				cost := info.cost
+				// Skip neighbour if the cost is greater than the maximum weight allowed.
+				if item.cost+cost > maxWeight {
+					continue
+				}
+				d, ok := dist[toUid]
+				if ok && d.cost <= item.cost+cost {
+					continue
+				}
+				if !ok {
+					// This is the first time we're seeing this node. So
+					// create a new node and add it to the heap and map.
+					node := &queueItem{
+						uid:  toUid,
+						cost: item.cost + cost,
+						hop:  item.hop + 1,
 					}
-					if !ok {
-						// This is the first time we're seeing this node. So
-						// create a new node and add it to the heap and map.
-						node := &queueItem{
-							uid:  toUid,
-							cost: item.cost + cost,
-							hop:  item.hop + 1,
-						}
-						heap.Push(&pq, node)
-						dist[toUid] = nodeInfo{
-							parent: item.uid,
-							node:   node,
-							mapItem: mapItem{
-								cost:  item.cost + cost,
-								attr:  info.attr,
-								facet: info.facet,
-							},
-						}
-					} else {
-						// We've already seen this node. So, just update the cost
-						// and fix the priority in the heap and map.
-						node := dist[toUid].node
-						node.cost = item.cost + cost
-						node.hop = item.hop + 1
-						heap.Fix(&pq, node.index)
-						// Update the map with new values.
-						dist[toUid] = nodeInfo{
-							parent: item.uid,
-							node:   node,
-							mapItem: mapItem{
-								cost:  item.cost + cost,
-								attr:  info.attr,
-								facet: info.facet,
-							},
-						}
+					heap.Push(&pq, node)
+					dist[toUid] = nodeInfo{
+						parent: item.uid,
+						node:   node,
+						mapItem: mapItem{
+							cost:  item.cost + cost,
+							attr:  info.attr,
+							facet: info.facet,
+						},
+					}
+				} else {
+					// We've already seen this node. So, just update the cost
+					// and fix the priority in the heap and map.
+					node := dist[toUid].node
+					node.cost = item.cost + cost
+					node.hop = item.hop + 1
+					heap.Fix(&pq, node.index)
+					// Update the map with new values.
+					dist[toUid] = nodeInfo{
+						parent: item.uid,
+						node:   node,
+						mapItem: mapItem{
+							cost:  item.cost + cost,
+							attr:  info.attr,
+							facet: info.facet,
+						},
 					}
 				}
 			}
@@ -555,6 +862,13 @@ func shortestPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 	}
 
 	next <- false
+	return sg.pathFromDist(ctx, dist, totalWeight), nil
+}
+
+// pathFromDist sets the destination of sg to the path to sg.Params.To through the parents of
+// the nodes in dist, and returns the subgraph of the path, if there is one.
+func (sg *SubGraph) pathFromDist(ctx context.Context, dist map[uint64]nodeInfo,
+	totalWeight float64) []*SubGraph {
 	// Go through the distance map to find the path.
 	var result []uint64
 	cur := sg.Params.To
@@ -565,7 +879,7 @@ func shortestPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 	// Put the path in DestUIDs of the root.
 	if cur != sg.Params.From {
 		sg.DestUIDs = &pb.List{}
-		return nil, nil
+		return nil
 	}
 
 	result = append(result, cur)
@@ -577,7 +891,7 @@ func shortestPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
 	sg.DestUIDs.Uids = result
 
 	shortestSg := createPathSubgraph(ctx, dist, totalWeight, result)
-	return []*SubGraph{shortestSg}, nil
+	return []*SubGraph{shortestSg}
 }
 
 func createPathSubgraph(ctx context.Context, dist map[uint64]nodeInfo, totalWeight float64,
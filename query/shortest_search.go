@@ -0,0 +1,529 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"container/heap"
+	"context"
+	"math"
+	"sort"
+
+	"github.com/golang/geo/s2"
+	"github.com/pkg/errors"
+	geom "github.com/twpayne/go-geom"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// pathSearch is a Dijkstra search from a node, used for the sides of a bidirectional search and
+// for A* searches. Unlike expandOut, which expands the graph a level at a time, the nodes are
+// expanded when they're visited: the ones waiting in the queue are expanded along with them, so
+// that there's still a single query per predicate for many nodes.
+type pathSearch struct {
+	sg *SubGraph
+	// backward searches follow the reverse edges of the predicates, from the destination.
+	backward  bool
+	templates []*SubGraph
+	target    uint64
+	maxHops   int
+	maxWeight float64
+	edges     *uint64
+	edgeLimit uint64
+
+	dist map[uint64]nodeInfo
+	pq   priorityQueue
+	// adj are the edges out of the expanded nodes, or into them for backward searches.
+	adj map[uint64]map[uint64]mapItem
+
+	// loc is the geo predicate of the nodes for A* searches, whose distance to the location of
+	// the target is the heuristic of the search.
+	loc       string
+	targetLoc *s2.LatLng
+	h         map[uint64]float64
+}
+
+func newPathSearch(sg *SubGraph, source, target uint64, backward bool,
+	edges *uint64) *pathSearch {
+	x.ConfigMu.RLock()
+	edgeLimit := x.Config.QueryEdgeLimit
+	x.ConfigMu.RUnlock()
+	maxHops := int(sg.Params.ExploreDepth)
+	if maxHops == 0 {
+		maxHops = int(math.MaxInt32)
+	}
+	s := &pathSearch{
+		sg:        sg,
+		backward:  backward,
+		target:    target,
+		maxHops:   maxHops,
+		maxWeight: sg.Params.MaxWeight,
+		edges:     edges,
+		edgeLimit: edgeLimit,
+		dist:      make(map[uint64]nodeInfo),
+		adj:       make(map[uint64]map[uint64]mapItem),
+		h:         make(map[uint64]float64),
+	}
+	for _, child := range sg.Children {
+		if tid, err := schema.State().TypeOf(child.Attr); backward && err == nil &&
+			tid != types.UidID {
+			// The values can't be followed backwards, and have no edges to follow forwards.
+			continue
+		}
+		temp := new(SubGraph)
+		temp.copyFiltersRecurse(child)
+		if backward {
+			temp.Attr = reverseAttr(child.Attr)
+		}
+		s.templates = append(s.templates, temp)
+	}
+	node := &queueItem{uid: source}
+	heap.Push(&s.pq, node)
+	s.dist[source] = nodeInfo{node: node}
+	return s
+}
+
+// heuristic returns a lower bound of the cost from the node to the target, which is zero unless
+// it's an A* search and both nodes have a location.
+func (s *pathSearch) heuristic(uid uint64) float64 {
+	return s.h[uid]
+}
+
+// locate sets the heuristic of the nodes of an A* search: their distance in meters, as the crow
+// flies, to the target.
+func (s *pathSearch) locate(ctx context.Context, uids []uint64) error {
+	if s.loc == "" || len(uids) == 0 {
+		return nil
+	}
+	vals, err := s.sg.nodeValues(ctx, s.loc, uids)
+	if err != nil {
+		return err
+	}
+	for uid, tv := range vals {
+		if ll, ok := pointOf(tv); ok {
+			s.h[uid] = float64(types.EarthDistance(ll.Distance(*s.targetLoc)))
+		}
+	}
+	return nil
+}
+
+// pointOf returns the coordinates of a geo value which is a point.
+func pointOf(tv types.Val) (s2.LatLng, bool) {
+	if tv.Tid != types.GeoID {
+		return s2.LatLng{}, false
+	}
+	p, ok := tv.Value.(*geom.Point)
+	if !ok {
+		return s2.LatLng{}, false
+	}
+	return s2.LatLngFromDegrees(p.Y(), p.X()), true
+}
+
+// expand finds the edges of the node, and of the ones waiting in the queue which weren't
+// expanded yet.
+func (s *pathSearch) expand(ctx context.Context, uid uint64) error {
+	uids := []uint64{uid}
+	for _, item := range s.pq {
+		if _, ok := s.adj[item.uid]; !ok {
+			uids = append(uids, item.uid)
+		}
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	for _, u := range uids {
+		s.adj[u] = make(map[uint64]mapItem)
+	}
+
+	edges, err := s.edgesOf(ctx, uids)
+	if err != nil {
+		return err
+	}
+	var found []uint64
+	for _, e := range edges {
+		from, to := e.from, e.to
+		if s.backward {
+			from, to = to, from
+		}
+		if item, ok := s.adj[from][to]; ok && item.cost <= e.cost {
+			// Of the edges of several predicates between the nodes, the cheapest one is kept.
+			continue
+		}
+		s.adj[from][to] = mapItem{cost: e.cost, facet: e.facet, attr: e.attr}
+		if _, ok := s.h[to]; !ok {
+			found = append(found, to)
+		}
+	}
+	return s.locate(ctx, found)
+}
+
+// edgesOf returns the edges of the sorted nodes, with a query per predicate.
+func (s *pathSearch) edgesOf(ctx context.Context, uids []uint64) ([]weightedEdge, error) {
+	exec := make([]*SubGraph, 0, len(s.templates))
+	for _, t := range s.templates {
+		temp := new(SubGraph)
+		temp.copyFiltersRecurse(t)
+		temp.SrcUIDs = &pb.List{Uids: uids}
+		exec = append(exec, temp)
+	}
+	rch := make(chan error, len(exec))
+	dummy := &SubGraph{}
+	for _, subgraph := range exec {
+		go ProcessGraph(ctx, subgraph, dummy, rch)
+	}
+	for range exec {
+		select {
+		case err := <-rch:
+			if err != nil {
+				return nil, err
+			}
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	edges, err := s.sg.levelEdges(ctx, exec, s.backward)
+	if err != nil {
+		return nil, err
+	}
+	*s.edges += uint64(len(edges))
+	if *s.edges > s.edgeLimit {
+		return nil, errors.Errorf("Exceeded query edge limit = %v. Found %v edges.",
+			s.edgeLimit, *s.edges)
+	}
+	return edges, nil
+}
+
+// visit pops the closest node from the queue and, unless it's the target, updates the cost of
+// its neighbours. It returns the node.
+func (s *pathSearch) visit(ctx context.Context) (*queueItem, error) {
+	item := heap.Pop(&s.pq).(*queueItem)
+	if item.uid == s.target || item.hop >= s.maxHops {
+		return item, nil
+	}
+	if _, ok := s.adj[item.uid]; !ok {
+		if err := s.expand(ctx, item.uid); err != nil {
+			return nil, err
+		}
+	}
+
+	cur := s.dist[item.uid].cost
+	for toUid, info := range s.adj[item.uid] {
+		cost := cur + info.cost
+		// The heuristic is a lower bound of the rest of the path, so the paths through the
+		// neighbour would weigh more than maxWeight.
+		if cost+s.heuristic(toUid) > s.maxWeight {
+			continue
+		}
+		d, ok := s.dist[toUid]
+		if ok && d.cost <= cost {
+			continue
+		}
+		node := d.node
+		if !ok || node.index < 0 {
+			// This is the first time we're seeing this node, or it was visited through a more
+			// expensive path, which can only happen if the heuristic overestimates a cost.
+			node = &queueItem{uid: toUid}
+			node.cost = cost + s.heuristic(toUid)
+			node.hop = item.hop + 1
+			heap.Push(&s.pq, node)
+		} else {
+			node.cost = cost + s.heuristic(toUid)
+			node.hop = item.hop + 1
+			heap.Fix(&s.pq, node.index)
+		}
+		s.dist[toUid] = nodeInfo{
+			parent: item.uid,
+			node:   node,
+			mapItem: mapItem{
+				cost:  cost,
+				attr:  info.attr,
+				facet: info.facet,
+			},
+		}
+	}
+	return item, nil
+}
+
+// bidirectionalPath finds the shortest path with two Dijkstra searches, one from the source
+// along the edges and the other from the destination along the reverse edges, which stop once
+// the paths they found meet through a node and no shorter one can be found.
+func bidirectionalPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
+	for _, child := range sg.Children {
+		if len(child.Filters) > 0 {
+			return nil, errors.Errorf("Filters aren't supported with bidirectional shortest path")
+		}
+	}
+	var edges uint64
+	fwd := newPathSearch(sg, sg.Params.From, 0, false, &edges)
+	bwd := newPathSearch(sg, sg.Params.To, 0, true, &edges)
+
+	best := math.Inf(1)
+	var meet uint64
+	if sg.Params.From == sg.Params.To {
+		best, meet = 0, sg.Params.From
+	}
+	for fwd.pq.Len() > 0 && bwd.pq.Len() > 0 {
+		if fwd.pq[0].cost+bwd.pq[0].cost >= best {
+			break
+		}
+		s, other := fwd, bwd
+		if bwd.pq[0].cost < fwd.pq[0].cost {
+			s, other = bwd, fwd
+		}
+		item, err := s.visit(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while searching the shortest path")
+		}
+		meets := []uint64{item.uid}
+		for uid := range s.adj[item.uid] {
+			meets = append(meets, uid)
+		}
+		for _, uid := range meets {
+			d, ok := s.dist[uid]
+			o, found := other.dist[uid]
+			if ok && found && d.cost+o.cost < best {
+				best, meet = d.cost+o.cost, uid
+			}
+		}
+	}
+	if meet == 0 || best > sg.Params.MaxWeight {
+		sg.DestUIDs = &pb.List{}
+		return nil, nil
+	}
+
+	// The path is the one of the forward search to the node where the searches met, followed
+	// by the one of the backward search, whose edges are turned around.
+	path := make(map[uint64]nodeInfo)
+	for uid := meet; uid != sg.Params.From; uid = fwd.dist[uid].parent {
+		path[uid] = fwd.dist[uid]
+	}
+	for uid := meet; uid != sg.Params.To; {
+		info := bwd.dist[uid]
+		path[info.parent] = nodeInfo{
+			parent:  uid,
+			mapItem: mapItem{attr: info.attr, facet: info.facet},
+		}
+		uid = info.parent
+	}
+	return sg.pathFromDist(ctx, path, best), nil
+}
+
+// astarPath finds the shortest path with the A* algorithm, whose heuristic is the distance in
+// meters, as the crow flies, between the locations of the nodes and of the destination. It's
+// only a lower bound of the cost of the rest of the path if the weights of the edges are at
+// least the distance between the locations of their nodes, such as the lengths of roads.
+func astarPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
+	var edges uint64
+	s := newPathSearch(sg, sg.Params.From, sg.Params.To, false, &edges)
+	vals, err := sg.nodeValues(ctx, sg.Params.astar, []uint64{sg.Params.To})
+	if err != nil {
+		return nil, err
+	}
+	// Without the location of the destination, the search is a plain Dijkstra search.
+	if ll, ok := pointOf(vals[sg.Params.To]); ok {
+		s.loc = sg.Params.astar
+		s.targetLoc = &ll
+		if err := s.locate(ctx, []uint64{sg.Params.From}); err != nil {
+			return nil, err
+		}
+	}
+
+	var totalWeight float64
+	for s.pq.Len() > 0 {
+		item, err := s.visit(ctx)
+		if err != nil {
+			return nil, errors.Wrapf(err, "while searching the shortest path")
+		}
+		if item.uid == sg.Params.To {
+			totalWeight = s.dist[item.uid].cost
+			break
+		}
+	}
+	return sg.pathFromDist(ctx, s.dist, totalWeight), nil
+}
+
+// isConstrained returns whether the shortest path query has constraints on the nodes or on the
+// predicates of the path.
+func (sg *SubGraph) isConstrained() bool {
+	if len(sg.Params.Through) > 0 || len(sg.Params.Avoid) > 0 {
+		return true
+	}
+	for _, child := range sg.Children {
+		if child.Params.limitEdges {
+			return true
+		}
+	}
+	return false
+}
+
+// pathState is a state of a constrained search: a node, along with the number of nodes of
+// through passed so far and the number of edges of each limited predicate taken, one per byte.
+// A node can be passed several times in different states, such as on the way to a node the
+// path must pass through and on the way back.
+type pathState struct {
+	uid    uint64
+	via    int
+	counts string
+}
+
+type stateInfo struct {
+	cost   float64
+	parent pathState
+	// edge is the edge into the state.
+	edge pathInfo
+	node *queueItem
+}
+
+// constrainedPath finds the shortest path passing through the nodes of through in order,
+// avoiding the nodes of avoid and taking at most maxedges edges of the limited predicates. The
+// constraints apply as the nodes are expanded, by searching the shortest path among the states
+// of the search, so the edges breaking them are never followed.
+func constrainedPath(ctx context.Context, sg *SubGraph) ([]*SubGraph, error) {
+	var numEdges uint64
+	s := newPathSearch(sg, sg.Params.From, sg.Params.To, false, &numEdges)
+	// The predicates which can't be followed at all aren't expanded.
+	limits := make(map[string]int)
+	var maxEdges []byte
+	templates := s.templates[:0]
+	for i, child := range sg.Children {
+		if child.Params.limitEdges {
+			if child.Params.maxEdges == 0 {
+				continue
+			}
+			limits[child.Attr] = len(maxEdges)
+			maxEdges = append(maxEdges, byte(child.Params.maxEdges))
+		}
+		templates = append(templates, s.templates[i])
+	}
+	s.templates = templates
+	avoid := make(map[uint64]bool)
+	for _, uid := range sg.Params.Avoid {
+		avoid[uid] = true
+	}
+	through := sg.Params.Through
+
+	start := pathState{uid: sg.Params.From, counts: string(make([]byte, len(maxEdges)))}
+	if len(through) > 0 && through[0] == start.uid {
+		start.via++
+	}
+	dist := map[pathState]*stateInfo{start: {node: &queueItem{uid: start.uid, state: &start}}}
+	var pq priorityQueue
+	heap.Push(&pq, dist[start].node)
+	adj := make(map[uint64][]weightedEdge)
+
+	var end *pathState
+	for pq.Len() > 0 {
+		item := heap.Pop(&pq).(*queueItem)
+		cur := *item.state
+		if cur.uid == sg.Params.To && cur.via == len(through) {
+			end = &cur
+			break
+		}
+		if item.hop >= s.maxHops {
+			continue
+		}
+		if _, ok := adj[cur.uid]; !ok {
+			// The nodes waiting in the queue are expanded along with this one.
+			uids := []uint64{cur.uid}
+			for _, it := range pq {
+				if _, ok := adj[it.uid]; !ok {
+					uids = append(uids, it.uid)
+				}
+			}
+			sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+			uids = algo.MergeSorted([]*pb.List{{Uids: uids}}).Uids
+			for _, uid := range uids {
+				adj[uid] = []weightedEdge{}
+			}
+			edges, err := s.edgesOf(ctx, uids)
+			if err != nil {
+				return nil, errors.Wrapf(err, "while searching the shortest path")
+			}
+			for _, e := range edges {
+				adj[e.from] = append(adj[e.from], e)
+			}
+		}
+
+		info := dist[cur]
+		for _, e := range adj[cur.uid] {
+			if avoid[e.to] && e.to != sg.Params.To {
+				continue
+			}
+			next := pathState{uid: e.to, via: cur.via, counts: cur.counts}
+			if idx, ok := limits[e.attr]; ok {
+				counts := []byte(cur.counts)
+				if counts[idx] >= maxEdges[idx] {
+					continue
+				}
+				counts[idx]++
+				next.counts = string(counts)
+			}
+			if next.via < len(through) && e.to == through[next.via] {
+				next.via++
+			}
+			cost := info.cost + e.cost
+			if cost > sg.Params.MaxWeight {
+				continue
+			}
+			d, ok := dist[next]
+			if ok && d.cost <= cost {
+				continue
+			}
+			var node *queueItem
+			if ok {
+				node = d.node
+				node.cost, node.hop = cost, item.hop+1
+				heap.Fix(&pq, node.index)
+			} else {
+				node = &queueItem{uid: e.to, cost: cost, hop: item.hop + 1}
+				node.state = &next
+				heap.Push(&pq, node)
+			}
+			dist[next] = &stateInfo{
+				cost:   cost,
+				parent: cur,
+				edge:   pathInfo{uid: e.to, attr: e.attr, facet: e.facet},
+				node:   node,
+			}
+		}
+	}
+	if end == nil {
+		sg.DestUIDs = &pb.List{}
+		return nil, nil
+	}
+
+	var steps []pathInfo
+	for st := *end; st != start; st = dist[st].parent {
+		steps = append(steps, dist[st].edge)
+	}
+	steps = append(steps, pathInfo{uid: start.uid})
+	var uids []uint64
+	seen := make(map[uint64]bool)
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	for _, step := range steps {
+		if !seen[step.uid] {
+			seen[step.uid] = true
+			uids = append(uids, step.uid)
+		}
+	}
+	sg.DestUIDs.Uids = uids
+	return createkroutesubgraph(ctx, []route{{route: steps, totalWeight: dist[*end].cost}}), nil
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"os"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+)
+
+// jsonWriter is where the JSON of a response is encoded.
+type jsonWriter interface {
+	io.Writer
+	io.ByteWriter
+	io.StringWriter
+	WriteRune(r rune) (int, error)
+}
+
+// SpillBuffer is a growable buffer which moves its content to a temporary file once it grows
+// over its limit, so that a giant response doesn't have to fit in memory. The first write
+// error is kept and returned by all the following calls.
+type SpillBuffer struct {
+	limit int
+	dir   string
+	buf   bytes.Buffer
+	file  *os.File
+	w     *bufio.Writer
+	size  int64
+	err   error
+}
+
+// NewSpillBuffer returns a buffer spilling to a file in dir, or in the default directory for
+// temporary files if dir is empty, once it holds more than limit bytes. A limit of zero or
+// less keeps everything in memory.
+func NewSpillBuffer(limit int, dir string) *SpillBuffer {
+	return &SpillBuffer{limit: limit, dir: dir}
+}
+
+func (b *SpillBuffer) spill() {
+	if b.file, b.err = ioutil.TempFile(b.dir, "dgraph-response-"); b.err != nil {
+		b.err = errors.Wrapf(b.err, "while creating spill file")
+		return
+	}
+	b.w = bufio.NewWriterSize(b.file, 1<<20)
+	if _, b.err = b.w.Write(b.buf.Bytes()); b.err != nil {
+		b.err = errors.Wrapf(b.err, "while writing to spill file")
+	}
+	b.buf = bytes.Buffer{}
+}
+
+// grow makes room for n more bytes, spilling to disk if the limit is crossed.
+func (b *SpillBuffer) grow(n int) {
+	b.size += int64(n)
+	if b.file == nil && b.err == nil && b.limit > 0 && b.size > int64(b.limit) {
+		b.spill()
+	}
+}
+
+func (b *SpillBuffer) Write(p []byte) (int, error) {
+	if b.grow(len(p)); b.err != nil {
+		return 0, b.err
+	}
+	if b.w != nil {
+		if _, b.err = b.w.Write(p); b.err != nil {
+			return 0, b.err
+		}
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *SpillBuffer) WriteByte(c byte) error {
+	if b.grow(1); b.err != nil {
+		return b.err
+	}
+	if b.w != nil {
+		b.err = b.w.WriteByte(c)
+		return b.err
+	}
+	return b.buf.WriteByte(c)
+}
+
+func (b *SpillBuffer) WriteRune(r rune) (int, error) {
+	if b.grow(utf8.RuneLen(r)); b.err != nil {
+		return 0, b.err
+	}
+	if b.w != nil {
+		var n int
+		n, b.err = b.w.WriteRune(r)
+		return n, b.err
+	}
+	return b.buf.WriteRune(r)
+}
+
+func (b *SpillBuffer) WriteString(s string) (int, error) {
+	if b.grow(len(s)); b.err != nil {
+		return 0, b.err
+	}
+	if b.w != nil {
+		var n int
+		n, b.err = b.w.WriteString(s)
+		return n, b.err
+	}
+	return b.buf.WriteString(s)
+}
+
+// Flush writes the buffered content to the file if it was spilled, and returns the first
+// error met while writing to the buffer.
+func (b *SpillBuffer) Flush() error {
+	if b.err == nil && b.w != nil {
+		if err := b.w.Flush(); err != nil {
+			b.err = errors.Wrapf(err, "while flushing spill file")
+		}
+	}
+	return b.err
+}
+
+// Len returns the number of bytes written to the buffer.
+func (b *SpillBuffer) Len() int64 {
+	return b.size
+}
+
+// Spilled tells if the content of the buffer was moved to a file.
+func (b *SpillBuffer) Spilled() bool {
+	return b.file != nil
+}
+
+// Bytes returns the content of the buffer, reading it back in memory if it was spilled.
+func (b *SpillBuffer) Bytes() ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.file == nil {
+		return b.buf.Bytes(), nil
+	}
+	var out bytes.Buffer
+	out.Grow(int(b.size))
+	if _, err := b.WriteTo(&out); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// WriteTo writes the content of the buffer to w, streaming it from the file if it was spilled.
+func (b *SpillBuffer) WriteTo(w io.Writer) (int64, error) {
+	if err := b.Flush(); err != nil {
+		return 0, err
+	}
+	if b.file == nil {
+		return io.Copy(w, bytes.NewReader(b.buf.Bytes()))
+	}
+	return io.Copy(w, io.NewSectionReader(b.file, 0, b.size))
+}
+
+// Close releases the buffer, removing its file if it was spilled.
+func (b *SpillBuffer) Close() error {
+	b.buf = bytes.Buffer{}
+	if b.file == nil {
+		return nil
+	}
+	name := b.file.Name()
+	err := b.file.Close()
+	if rerr := os.Remove(name); err == nil {
+		err = rerr
+	}
+	b.file, b.w = nil, nil
+	return err
+}
+
+type spillKey struct{}
+
+type spilled struct {
+	buf *SpillBuffer
+}
+
+// WithSpill returns a context whose request can leave a response that was spilled to disk out
+// of Response.Json, for the caller to stream it from SpilledJSON instead.
+func WithSpill(ctx context.Context) context.Context {
+	return context.WithValue(ctx, spillKey{}, &spilled{})
+}
+
+// SpilledJSON returns the spilled response of the request run with the context, if any. The
+// caller must close it once it's written.
+func SpilledJSON(ctx context.Context) *SpillBuffer {
+	if s, ok := ctx.Value(spillKey{}).(*spilled); ok {
+		return s.buf
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSpillBuffer(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spill")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	b := NewSpillBuffer(8, dir)
+	b.WriteString(`{"a":`)
+	require.False(t, b.Spilled())
+	b.WriteRune('"')
+	b.Write([]byte("ü-long"))
+	b.WriteByte('"')
+	b.WriteRune('}')
+	require.True(t, b.Spilled())
+	require.Equal(t, int64(len(`{"a":"ü-long"}`)), b.Len())
+
+	var out bytes.Buffer
+	_, err = b.WriteTo(&out)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":"ü-long"}`, out.String())
+	js, err := b.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, `{"a":"ü-long"}`, string(js))
+
+	require.NoError(t, b.Close())
+	files, err := ioutil.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, files)
+}
+
+func TestSpillBufferInMemory(t *testing.T) {
+	b := NewSpillBuffer(0, "")
+	b.WriteString(`{"a":"some long value"}`)
+	require.False(t, b.Spilled())
+	js, err := b.Bytes()
+	require.NoError(t, err)
+	require.Equal(t, `{"a":"some long value"}`, string(js))
+	require.NoError(t, b.Close())
+}
+
+func TestSpilledJSON(t *testing.T) {
+	require.Nil(t, SpilledJSON(context.Background()))
+	ctx := WithSpill(context.Background())
+	require.Nil(t, SpilledJSON(ctx))
+}
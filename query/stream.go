@@ -0,0 +1,325 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/pkg/errors"
+)
+
+// errFallback is kept by a streamEncoder when a node needs to be merged with another one,
+// which only a tree of fastJsonNode can do.
+var errFallback = errors.New("Node can't be streamed")
+
+// streamEncoder writes the JSON of a result while its nodes are traversed, instead of building
+// a tree of fastJsonNode first. The output is the same as the one of fastJsonNode.encode.
+// The root node being written is kept in buf, so that children which turn out to be empty can
+// be dropped.
+type streamEncoder struct {
+	buf    bytes.Buffer
+	err    error
+	intern *scalarInterner
+}
+
+// streamFrame is the state of an object being written.
+type streamFrame struct {
+	// attrs is the number of values written.
+	attrs int
+	// lastAttr is the key of the last value.
+	lastAttr string
+	// inArray tells if the values of lastAttr are written as an array.
+	inArray bool
+	// valStart is the offset of the first value of lastAttr.
+	valStart int
+	// hasUID tells if the uid key was written.
+	hasUID bool
+}
+
+// streamNode is the outputNode of a streamEncoder.
+type streamNode struct {
+	streamFrame
+	enc *streamEncoder
+	// saved is the state of the parent before this node was started, and mark the offset
+	// of the buffer, to drop this node if it's never added.
+	saved streamFrame
+	mark  int
+	// child is the last child started, until it's added or dropped.
+	child *streamNode
+	done  bool
+}
+
+// streamable tells if the result of the blocks can be written while it's traversed, which
+// isn't the case when the nodes have to be rearranged once they're built.
+func (sg *SubGraph) streamable() bool {
+	if sg.Params.dedupeNodes || sg.Params.outputProfile != ProfileDefault {
+		// The nodes found more than once are only known once the whole result is built, and
+		// the profiles reshape the whole result.
+		return false
+	}
+	var check func(sg *SubGraph) bool
+	check = func(sg *SubGraph) bool {
+		if sg.Params.Normalize || sg.Params.isGroupBy {
+			return false
+		}
+		for _, child := range sg.Children {
+			if !check(child) {
+				return false
+			}
+		}
+		return true
+	}
+	// The blocks sharing an alias, like the paths of a k-shortest path query, are merged under
+	// a single key.
+	aliases := make(map[string]struct{}, len(sg.Children))
+	for _, block := range sg.Children {
+		if _, ok := aliases[block.Params.Alias]; ok {
+			return false
+		}
+		aliases[block.Params.Alias] = struct{}{}
+		if block.Params.IsEmpty || block.Params.uidCount || block.Params.subgraph ||
+			block.Params.JoinArgs.Left != "" || block.isDistinct() || block.hasHistograms() ||
+			!check(block) {
+			return false
+		}
+	}
+	return true
+}
+
+// streamJSON writes the result of the blocks to out while they're traversed.
+func (sg *SubGraph) streamJSON(tr *traversal, out jsonWriter) error {
+	if len(sg.Children) == 0 {
+		out.WriteString(`{}`)
+		return nil
+	}
+	enc := &streamEncoder{intern: newScalarInterner(sg.Params.floatFormat, sg.Params.binaryFormat)}
+	out.WriteByte('{')
+	for i, block := range sg.Children {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if tr.trunc != nil {
+			tr.trunc.block = i
+		}
+		writeJSONKey(out, block.Params.Alias)
+		out.WriteString(`:[`)
+		if err := block.streamBlock(enc, tr, out); err != nil {
+			return err
+		}
+		out.WriteByte(']')
+	}
+	out.WriteByte('}')
+	return nil
+}
+
+// streamBlock writes the nodes of the block to out, one root node at a time. A root node
+// holding nodes to be merged is built as a tree instead.
+func (sg *SubGraph) streamBlock(enc *streamEncoder, tr *traversal, out jsonWriter) error {
+	if sg.uidMatrix == nil || tr.trunc.skipBlock() {
+		return nil
+	}
+	uids := sg.uidMatrix[0].Uids
+	if err := tr.checkFanout(len(uids)); err != nil {
+		return err
+	}
+	first := true
+	for i, uid := range uids {
+		if algo.IndexOf(sg.DestUIDs, uid) < 0 || tr.trunc.skipNode(i) {
+			// This UID was filtered, or returned before the cursor. So Ignore it.
+			continue
+		}
+
+		enc.buf.Reset()
+		enc.err = nil
+		var saved traversal
+		if tr != nil {
+			saved = *tr
+		}
+		n := &streamNode{enc: enc}
+		err := sg.preTraverse(tr, uid, n)
+		if err == nil && enc.err == errFallback {
+			if tr != nil {
+				*tr = saved
+			}
+			enc.buf.Reset()
+			fj := &fastJsonNode{intern: enc.intern}
+			if err = sg.preTraverse(tr, uid, fj); err == nil && !fj.IsEmpty() {
+				fj.encode(&enc.buf)
+			}
+		} else if err == nil {
+			n.close()
+		}
+		if err != nil {
+			if err == errInvalidUid {
+				continue
+			}
+			return err
+		}
+
+		if enc.buf.Len() == 0 {
+			continue
+		}
+		if !tr.trunc.fits(i, enc.buf.Len()) {
+			break
+		}
+		if !first {
+			out.WriteByte(',')
+		}
+		first = false
+		out.Write(enc.buf.Bytes())
+	}
+	return nil
+}
+
+// settle drops the last child started if it wasn't added, as it was empty.
+func (n *streamNode) settle() {
+	c := n.child
+	if c == nil {
+		return
+	}
+	n.child = nil
+	if !c.done {
+		n.enc.buf.Truncate(c.mark)
+		n.streamFrame = c.saved
+	}
+}
+
+// beginValue writes the key of a value, or the separator with the previous value if it has
+// the same key, in which case they're written as an array.
+func (n *streamNode) beginValue(attr string, list bool) {
+	n.settle()
+	b := &n.enc.buf
+	if n.attrs > 0 && attr == n.lastAttr {
+		if !n.inArray {
+			// Make room for the start of the array before the first value.
+			b.WriteByte(0)
+			bs := b.Bytes()
+			copy(bs[n.valStart+1:], bs[n.valStart:len(bs)-1])
+			bs[n.valStart] = '['
+			n.inArray = true
+		}
+		b.WriteByte(',')
+		n.attrs++
+		return
+	}
+
+	if n.inArray {
+		b.WriteByte(']')
+	}
+	if n.attrs == 0 {
+		b.WriteByte('{')
+	} else {
+		b.WriteByte(',')
+	}
+	writeJSONKey(b, attr)
+	b.WriteByte(':')
+	n.valStart = b.Len()
+	n.inArray = list
+	if list {
+		b.WriteByte('[')
+	}
+	n.lastAttr = attr
+	n.attrs++
+	if attr == "uid" {
+		n.hasUID = true
+	}
+}
+
+// close ends the object, if anything was written to it.
+func (n *streamNode) close() {
+	n.settle()
+	n.done = true
+	if n.attrs == 0 {
+		return
+	}
+	if n.inArray {
+		n.enc.buf.WriteByte(']')
+	}
+	n.enc.buf.WriteByte('}')
+}
+
+func (n *streamNode) AddValue(attr string, v types.Val) {
+	n.AddListValue(attr, v, false)
+}
+
+func (n *streamNode) AddListValue(attr string, v types.Val, list bool) {
+	if bs, err := n.enc.intern.valToBytes(attr, v); err == nil {
+		n.beginValue(attr, list)
+		n.enc.buf.Write(bs)
+	}
+}
+
+func (n *streamNode) AddMapChild(attr string, child outputNode, isRoot bool) {
+	child.(*streamNode).close()
+}
+
+func (n *streamNode) AddListChild(attr string, child outputNode) {
+	child.(*streamNode).close()
+}
+
+func (n *streamNode) New(attr string) outputNode {
+	return n.NewChild(attr, false)
+}
+
+func (n *streamNode) NewChild(attr string, list bool) outputNode {
+	n.settle()
+	if !list && n.attrs > 0 && attr == n.lastAttr {
+		// fastJsonNode merges the children of map nodes sharing the same key.
+		n.enc.err = errFallback
+	}
+	c := &streamNode{enc: n.enc, saved: n.streamFrame, mark: n.enc.buf.Len()}
+	n.beginValue(attr, list)
+	n.child = c
+	return c
+}
+
+func (n *streamNode) SetUID(uid uint64, attr string) {
+	n.settle()
+	// if we're in debug mode, uid may be added second time, skip this
+	if attr == "uid" && n.hasUID {
+		return
+	}
+	n.beginValue(attr, false)
+	fmt.Fprintf(&n.enc.buf, "\"%#x\"", uid)
+}
+
+func (n *streamNode) AddFacet(attr string, v types.Val) {
+	n.AddValue(attr, v)
+}
+
+func (n *streamNode) IsEmpty() bool {
+	n.settle()
+	return n.attrs == 0
+}
+
+// The results below are only added at the root or for @groupby, which are never streamed.
+
+func (n *streamNode) addCountAtRoot(*SubGraph) {
+	n.enc.err = errFallback
+}
+
+func (n *streamNode) addGroupby(*SubGraph, *groupResults, string) {
+	n.enc.err = errFallback
+}
+
+func (n *streamNode) addAggregations(*SubGraph) error {
+	n.enc.err = errFallback
+	return nil
+}
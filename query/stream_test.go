@@ -0,0 +1,108 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/stretchr/testify/require"
+)
+
+func strVal(s string) types.Val {
+	return types.Val{Tid: types.StringID, Value: s}
+}
+
+// requireSameEncoding checks that the streamed output of build is the one of fastJsonNode.
+func requireSameEncoding(t *testing.T, expected string, build func(n outputNode)) {
+	fj := &fastJsonNode{}
+	build(fj)
+	var tree bytes.Buffer
+	fj.encode(&tree)
+	require.Equal(t, expected, tree.String())
+
+	enc := &streamEncoder{}
+	n := &streamNode{enc: enc}
+	build(n)
+	n.close()
+	require.NoError(t, enc.err)
+	require.Equal(t, expected, enc.buf.String())
+}
+
+func TestStreamEncoding(t *testing.T) {
+	requireSameEncoding(t, `{"name":"a","friend":[{"name":"b","uid":"0x2"},{"name":"c"}],`+
+		`"boss":{"name":"d"},"tags":["x","y"],"name@en":["e","f"],"uid":"0x1"}`,
+		func(n outputNode) {
+			n.AddValue("name", strVal("a"))
+			c := n.NewChild("friend", true)
+			c.AddValue("name", strVal("b"))
+			c.SetUID(2, "uid")
+			c.SetUID(2, "uid")
+			n.AddListChild("friend", c)
+			// Empty children are never added and are dropped.
+			c = n.NewChild("friend", true)
+			require.True(t, c.IsEmpty())
+			c = n.NewChild("friend", true)
+			c.AddValue("name", strVal("c"))
+			n.AddListChild("friend", c)
+			c = n.NewChild("boss", false)
+			c.AddValue("name", strVal("d"))
+			n.AddMapChild("boss", c, false)
+			c = n.NewChild("empty", true)
+			c.NewChild("grandchild", true)
+			require.True(t, c.IsEmpty())
+			n.AddListValue("tags", strVal("x"), true)
+			n.AddListValue("tags", strVal("y"), true)
+			// Repeated values of a non list key are turned into an array.
+			n.AddValue("name@en", strVal("e"))
+			n.AddValue("name@en", strVal("f"))
+			n.SetUID(1, "uid")
+		})
+}
+
+func TestStreamEncodingEmpty(t *testing.T) {
+	fj := &fastJsonNode{}
+	enc := &streamEncoder{}
+	n := &streamNode{enc: enc}
+	n.NewChild("friend", true)
+	n.close()
+	require.True(t, fj.IsEmpty())
+	require.Empty(t, enc.buf.String())
+}
+
+func TestStreamEncodingFallback(t *testing.T) {
+	enc := &streamEncoder{}
+	n := &streamNode{enc: enc}
+	c := n.NewChild("boss", false)
+	c.AddValue("name", strVal("a"))
+	n.AddMapChild("boss", c, false)
+	n.NewChild("boss", false)
+	require.Equal(t, errFallback, enc.err)
+}
+
+func TestStreamableSharedAlias(t *testing.T) {
+	sg := &SubGraph{Children: []*SubGraph{
+		{Params: params{Alias: "me"}},
+		{Params: params{Alias: "_path_"}},
+	}}
+	require.True(t, sg.streamable())
+
+	// Each path of a k-shortest path query is a block named _path_.
+	sg.Children = append(sg.Children, &SubGraph{Params: params{Alias: "_path_"}})
+	require.False(t, sg.streamable())
+}
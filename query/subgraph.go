@@ -0,0 +1,315 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/types/facets"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+// graphNode is the outputNode of a subgraph block. Instead of nesting the nodes under the edges
+// leading to them, it lists each node reached once under nodes, and each edge traversed under
+// edges, with its facets.
+type graphNode struct {
+	*fastJsonNode
+	nodes []outputNode
+	edges []outputNode
+	// maxHops is the depth of the subgraph. The recursion reads the edges one level deeper,
+	// which are left out.
+	maxHops uint64
+	added   map[uint64]bool
+	// visited are the nodes whose edges were added, for each level of the recursion.
+	visited map[*SubGraph]map[uint64]bool
+}
+
+// addSubgraph adds the nodes and the edges of the subgraph block to fj.
+func (fj *fastJsonNode) addSubgraph(sg *SubGraph, tr *traversal) error {
+	g := &graphNode{
+		fastJsonNode: fj.New(sg.Params.Alias).(*fastJsonNode),
+		maxHops:      sg.Params.RecurseArgs.Depth - 1,
+		added:        make(map[uint64]bool),
+		visited:      make(map[*SubGraph]map[uint64]bool),
+	}
+	var roots []uint64
+	if len(sg.uidMatrix) > 0 {
+		roots = sg.uidMatrix[0].Uids
+	}
+	for _, uid := range roots {
+		if algo.IndexOf(sg.DestUIDs, uid) < 0 {
+			// This UID was filtered. So Ignore it.
+			continue
+		}
+		if err := g.addNode(sg, tr, uid, 0); err != nil {
+			return err
+		}
+	}
+	// The children sharing a key must be next to each other, and an empty child is encoded as an
+	// empty list.
+	for _, l := range []struct {
+		key   string
+		nodes []outputNode
+	}{{"nodes", g.nodes}, {"edges", g.edges}} {
+		if len(l.nodes) == 0 {
+			l.nodes = append(l.nodes, &fastJsonNode{})
+		}
+		for _, n := range l.nodes {
+			g.AddListChild(l.key, n)
+		}
+	}
+	fj.AddMapChild(sg.Params.Alias, g.fastJsonNode, false)
+	return nil
+}
+
+// addNode adds the node with the values of the level of the recursion, unless it was added
+// already, and then the edges leaving it and the nodes they lead to.
+func (g *graphNode) addNode(sg *SubGraph, tr *traversal, uid uint64, hops uint64) error {
+	if g.visited[sg] == nil {
+		g.visited[sg] = make(map[uint64]bool)
+	}
+	if g.visited[sg][uid] {
+		return nil
+	}
+	g.visited[sg][uid] = true
+	if err := tr.enter(); err != nil {
+		return err
+	}
+	defer tr.leave()
+
+	if !g.added[uid] {
+		g.added[uid] = true
+		n := g.New("nodes")
+		n.SetUID(uid, "uid")
+		if err := addNodeValues(sg, uid, n); err != nil {
+			return err
+		}
+		g.nodes = append(g.nodes, n)
+	}
+	if hops == g.maxHops {
+		return nil
+	}
+
+	for _, pc := range sg.Children {
+		idx := algo.IndexOf(pc.SrcUIDs, uid)
+		if idx < 0 || idx >= len(pc.uidMatrix) || len(pc.uidMatrix[idx].Uids) == 0 {
+			continue
+		}
+		ul := pc.uidMatrix[idx]
+		if err := tr.checkFanout(len(ul.Uids)); err != nil {
+			return err
+		}
+		for childIdx, childUID := range ul.Uids {
+			e := g.New("edges")
+			pred, from, to := pc.fieldName(), uid, childUID
+			if pc.Params.Alias == "" && strings.HasPrefix(pred, "~") {
+				// A reverse edge is the edge of the predicate the other way around.
+				pred, from, to = pred[1:], childUID, uid
+			}
+			e.SetUID(from, "from")
+			e.SetUID(to, "to")
+			e.AddValue("predicate", types.Val{Tid: types.StringID, Value: pred})
+			if pc.Params.Facet != nil && len(pc.facetsMatrix) > idx &&
+				len(pc.facetsMatrix[idx].FacetsList) > childIdx {
+				fs := g.New("facets")
+				for _, f := range pc.facetsMatrix[idx].FacetsList[childIdx].Facets {
+					fVal, err := facets.ValFor(f)
+					if err != nil {
+						return err
+					}
+					name := f.Key
+					if f.Alias != "" {
+						name = f.Alias
+					}
+					fs.AddValue(name, fVal)
+				}
+				if !fs.IsEmpty() {
+					e.AddMapChild("facets", fs, false)
+				}
+			}
+			g.edges = append(g.edges, e)
+
+			if err := g.addNode(pc, tr, childUID, hops+1); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// addNodeValues adds the scalar values and the counts of the node to dst, as preTraverse does.
+func addNodeValues(sg *SubGraph, uid uint64, dst outputNode) error {
+	for _, pc := range sg.Children {
+		if pc.Params.ignoreResult || pc.Attr == "uid" {
+			continue
+		}
+		idx := algo.IndexOf(pc.SrcUIDs, uid)
+		if idx < 0 {
+			continue
+		}
+		if len(pc.counts) > 0 {
+			addCount(pc, uint64(pc.counts[idx]), dst)
+			continue
+		}
+		if idx < len(pc.uidMatrix) && len(pc.uidMatrix[idx].Uids) > 0 {
+			// The edges are added to the list of edges.
+			continue
+		}
+
+		fieldName := pc.fieldName()
+		if pc.Params.Alias == "" && len(pc.Params.Langs) > 0 {
+			fieldName += "@" + strings.Join(pc.Params.Langs, ":")
+		}
+		if err := addValueFacets(pc, idx, fieldName, dst); err != nil {
+			return err
+		}
+		if len(pc.valueMatrix) <= idx {
+			continue
+		}
+		for _, tv := range pc.valueMatrix[idx].Values {
+			sv, err := convertWithBestEffort(tv, pc.Attr)
+			if err != nil {
+				return err
+			}
+			dst.AddListValue(fieldName, sv, pc.List && len(pc.Params.Langs) == 0)
+		}
+	}
+	return nil
+}
+
+// GraphFromJSON returns the nodes and the edges of the subgraph blocks of the JSON data of a
+// query response, to be written in one of the graph formats. The nodes of several subgraph
+// blocks are merged.
+func GraphFromJSON(js []byte) ([]*worker.GraphNode, []*worker.GraphEdge, error) {
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal(js, &data); err != nil {
+		return nil, nil, err
+	}
+	type subgraph struct {
+		Nodes []map[string]interface{} `json:"nodes"`
+		Edges []struct {
+			From      string                 `json:"from"`
+			To        string                 `json:"to"`
+			Predicate string                 `json:"predicate"`
+			Facets    map[string]interface{} `json:"facets"`
+		} `json:"edges"`
+	}
+
+	var nodes []*worker.GraphNode
+	var edges []*worker.GraphEdge
+	added := make(map[uint64]*worker.GraphNode)
+	var found bool
+	for _, block := range data {
+		var sg subgraph
+		dec := json.NewDecoder(bytes.NewReader(block))
+		dec.UseNumber()
+		if err := dec.Decode(&sg); err != nil || sg.Nodes == nil || sg.Edges == nil {
+			// Not a subgraph block.
+			continue
+		}
+		found = true
+
+		for _, m := range sg.Nodes {
+			uidStr, _ := m["uid"].(string)
+			uid, err := strconv.ParseUint(uidStr, 0, 64)
+			if err != nil {
+				return nil, nil, errors.Errorf("Invalid uid of node: %v", m["uid"])
+			}
+			delete(m, "uid")
+			n, ok := added[uid]
+			if !ok {
+				n = &worker.GraphNode{Uid: uid}
+				added[uid] = n
+				nodes = append(nodes, n)
+			} else if len(n.Attrs) > 0 {
+				// The values of the node were added by another block.
+				continue
+			}
+			n.Attrs = jsonGraphAttrs(m)
+		}
+		for _, e := range sg.Edges {
+			from, err := strconv.ParseUint(e.From, 0, 64)
+			if err != nil {
+				return nil, nil, errors.Errorf("Invalid uid of edge: %s", e.From)
+			}
+			to, err := strconv.ParseUint(e.To, 0, 64)
+			if err != nil {
+				return nil, nil, errors.Errorf("Invalid uid of edge: %s", e.To)
+			}
+			edges = append(edges, &worker.GraphEdge{
+				From:      from,
+				To:        to,
+				Predicate: e.Predicate,
+				Attrs:     jsonGraphAttrs(e.Facets),
+			})
+		}
+	}
+	if !found {
+		return nil, nil, errors.Errorf("The query has no subgraph block")
+	}
+	return nodes, edges, nil
+}
+
+// jsonGraphAttrs returns the JSON values as attributes, sorted by name. The values of a list are
+// repeated attributes.
+func jsonGraphAttrs(m map[string]interface{}) []worker.GraphAttr {
+	var attrs []worker.GraphAttr
+	var add func(name string, v interface{})
+	add = func(name string, v interface{}) {
+		switch v := v.(type) {
+		case string:
+			attrs = append(attrs, worker.GraphAttr{Name: name, Tid: types.StringID, Value: v})
+		case bool:
+			attrs = append(attrs, worker.GraphAttr{Name: name, Tid: types.BoolID,
+				Value: strconv.FormatBool(v)})
+		case json.Number:
+			tid := types.FloatID
+			if _, err := v.Int64(); err == nil {
+				tid = types.IntID
+			}
+			attrs = append(attrs, worker.GraphAttr{Name: name, Tid: tid, Value: v.String()})
+		case []interface{}:
+			for _, elem := range v {
+				add(name, elem)
+			}
+		case nil:
+		default:
+			// Objects, like the geo values, are kept as JSON.
+			js, _ := json.Marshal(v)
+			attrs = append(attrs, worker.GraphAttr{Name: name, Tid: types.StringID,
+				Value: string(js)})
+		}
+	}
+
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		add(name, m[name])
+	}
+	return attrs
+}
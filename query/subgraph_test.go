@@ -0,0 +1,59 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+)
+
+func TestGraphFromJSON(t *testing.T) {
+	nodes, edges, err := GraphFromJSON([]byte(`{
+		"me": [{"name": "Alice"}],
+		"sg": {
+			"nodes": [
+				{"uid": "0x2", "name": "Alice", "age": 38, "alive": true, "dgraph.type": ["Person"]},
+				{"uid": "0x1"}
+			],
+			"edges": [
+				{"from": "0x2", "to": "0x1", "predicate": "friend", "facets": {"weight": 0.5}}
+			]
+		}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, []*worker.GraphNode{
+		{Uid: 2, Attrs: []worker.GraphAttr{
+			{Name: "age", Tid: types.IntID, Value: "38"},
+			{Name: "alive", Tid: types.BoolID, Value: "true"},
+			{Name: "dgraph.type", Tid: types.StringID, Value: "Person"},
+			{Name: "name", Tid: types.StringID, Value: "Alice"},
+		}},
+		{Uid: 1},
+	}, nodes)
+	require.Equal(t, []*worker.GraphEdge{
+		{From: 2, To: 1, Predicate: "friend", Attrs: []worker.GraphAttr{
+			{Name: "weight", Tid: types.FloatID, Value: "0.5"},
+		}},
+	}, edges)
+
+	_, _, err = GraphFromJSON([]byte(`{"me": [{"name": "Alice"}]}`))
+	require.Error(t, err)
+}
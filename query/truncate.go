@@ -0,0 +1,182 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestMaxResponseBytes returns the size over which the response of the request is
+// truncated, as asked for through MaxResponseBytesKey or, for gRPC clients, the
+// max_response_bytes metadata. Zero means the response isn't truncated.
+func requestMaxResponseBytes(ctx context.Context) uint64 {
+	max, _ := ctx.Value(MaxResponseBytesKey).(uint64)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md["max_response_bytes"]) > 0 {
+		// An invalid value is ignored, as for the limits.
+		if n, err := strconv.ParseUint(md["max_response_bytes"][0], 0, 64); err == nil {
+			max = n
+		}
+	}
+	return max
+}
+
+// requestCursor returns the cursor of a truncated response the request continues from, as
+// given through CursorKey or, for gRPC clients, the cursor metadata.
+func requestCursor(ctx context.Context) string {
+	cursor, _ := ctx.Value(CursorKey).(string)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md["cursor"]) > 0 {
+		cursor = md["cursor"][0]
+	}
+	return cursor
+}
+
+// cursor is the position of a root node in the result: the index of its block, not counting
+// the var and shortest blocks, and its index in the nodes of the block.
+type cursor struct {
+	block int
+	node  int
+}
+
+func (c cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.block, c.node)))
+}
+
+func parseCursor(s string) (cursor, error) {
+	var c cursor
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		_, err = fmt.Sscanf(string(b), "%d:%d", &c.block, &c.node)
+	}
+	if err != nil || c.block < 0 || c.node < 0 {
+		return c, errors.Errorf("Invalid cursor: %s", s)
+	}
+	return c, nil
+}
+
+// truncation stops adding root nodes to the result once it would grow over maxBytes, and keeps
+// the position of the first root node left out, for another request to continue from it. At
+// least one root node is always added, so that every continuation makes progress. The blocks
+// which don't list root nodes, like the aggregations, are added whole.
+type truncation struct {
+	maxBytes uint64
+	size     uint64
+	// start is the position of the first root node to add, read from the cursor of the request.
+	start cursor
+	// block is the index of the block being added.
+	block int
+	// next is the position of the first root node left out, once the result is truncated.
+	next *cursor
+}
+
+// newTruncation returns the truncation of the result of sg, or nil if the request neither
+// limits its size nor continues a truncated response.
+func newTruncation(sg *SubGraph) (*truncation, error) {
+	if sg.Params.maxResponseBytes == 0 && sg.Params.cursor == "" {
+		return nil, nil
+	}
+	t := &truncation{maxBytes: sg.Params.maxResponseBytes}
+	if sg.Params.cursor != "" {
+		start, err := parseCursor(sg.Params.cursor)
+		if err != nil {
+			return nil, err
+		}
+		t.start = start
+	}
+	return t, nil
+}
+
+// skipBlock tells if the block being added must be left empty, as its nodes were returned before
+// the cursor, or the result was truncated.
+func (t *truncation) skipBlock() bool {
+	return t != nil && (t.block < t.start.block || t.next != nil)
+}
+
+// skipNode tells if the root node at idx of the block being added was returned before the
+// cursor.
+func (t *truncation) skipNode(idx int) bool {
+	return t != nil && t.block == t.start.block && idx < t.start.node
+}
+
+// fits tells if the root node at idx, which takes size bytes, can be added. The result is
+// truncated at this node if it can't.
+func (t *truncation) fits(idx, size int) bool {
+	if t == nil || t.maxBytes == 0 {
+		return true
+	}
+	if t.next != nil {
+		return false
+	}
+	if t.size > 0 && t.size+uint64(size) > t.maxBytes {
+		t.next = &cursor{block: t.block, node: idx}
+		return false
+	}
+	t.size += uint64(size)
+	return true
+}
+
+// fitsNodes is like fits, for the nodes built for the root node at idx.
+func (t *truncation) fitsNodes(idx int, nodes []*fastJsonNode) bool {
+	if t == nil || t.maxBytes == 0 {
+		return true
+	}
+	var buf bytes.Buffer
+	for _, n := range nodes {
+		n.encode(&buf)
+	}
+	return t.fits(idx, buf.Len())
+}
+
+type truncationKey struct{}
+
+type truncated struct {
+	cursor string
+}
+
+// WithTruncation returns a context which keeps the cursor of the response of the query run with
+// it, if it's truncated, for Truncated to read it back. A context which keeps it already is
+// returned as is.
+func WithTruncation(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(truncationKey{}).(*truncated); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, truncationKey{}, &truncated{})
+}
+
+// Truncated returns the cursor to continue the truncated response of the query run with the
+// context from, and false if the response wasn't truncated.
+func Truncated(ctx context.Context) (string, bool) {
+	if t, ok := ctx.Value(truncationKey{}).(*truncated); ok && t.cursor != "" {
+		return t.cursor, true
+	}
+	return "", false
+}
+
+func setTruncated(ctx context.Context, t *truncation) {
+	if t == nil || t.next == nil {
+		return
+	}
+	if s, ok := ctx.Value(truncationKey{}).(*truncated); ok {
+		s.cursor = t.next.String()
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestRequestMaxResponseBytes(t *testing.T) {
+	require.Equal(t, uint64(0), requestMaxResponseBytes(context.Background()))
+
+	ctx := context.WithValue(context.Background(), MaxResponseBytesKey, uint64(100))
+	ctx = context.WithValue(ctx, CursorKey, "abc")
+	require.Equal(t, uint64(100), requestMaxResponseBytes(ctx))
+	require.Equal(t, "abc", requestCursor(ctx))
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("max_response_bytes", "50"))
+	require.Equal(t, uint64(50), requestMaxResponseBytes(ctx))
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("max_response_bytes", "bad"))
+	require.Equal(t, uint64(100), requestMaxResponseBytes(ctx))
+}
+
+func TestParseCursor(t *testing.T) {
+	c := cursor{block: 2, node: 31}
+	parsed, err := parseCursor(c.String())
+	require.NoError(t, err)
+	require.Equal(t, c, parsed)
+
+	for _, s := range []string{"", "!!", cursor{block: -1}.String()} {
+		_, err := parseCursor(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestTruncation(t *testing.T) {
+	// A nil truncation adds everything.
+	var none *truncation
+	require.False(t, none.skipBlock())
+	require.False(t, none.skipNode(0))
+	require.True(t, none.fits(0, 1e6))
+
+	tr := &truncation{maxBytes: 10, start: cursor{block: 1, node: 2}}
+	require.True(t, tr.skipBlock())
+	tr.block = 1
+	require.False(t, tr.skipBlock())
+	require.True(t, tr.skipNode(1))
+	require.False(t, tr.skipNode(2))
+
+	// The first node is always added, even if it's over the limit.
+	require.True(t, tr.fits(2, 12))
+	require.False(t, tr.fits(3, 1))
+	require.Equal(t, &cursor{block: 1, node: 3}, tr.next)
+	require.False(t, tr.fits(4, 1))
+	tr.block = 2
+	require.True(t, tr.skipBlock())
+
+	tr = &truncation{maxBytes: 10}
+	require.True(t, tr.fits(0, 4))
+	require.True(t, tr.fits(1, 6))
+	require.False(t, tr.fits(2, 1))
+}
+
+func TestTruncated(t *testing.T) {
+	_, ok := Truncated(context.Background())
+	require.False(t, ok)
+
+	ctx := WithTruncation(context.Background())
+	require.Equal(t, ctx, WithTruncation(ctx))
+	setTruncated(ctx, &truncation{})
+	_, ok = Truncated(ctx)
+	require.False(t, ok)
+
+	setTruncated(ctx, &truncation{next: &cursor{block: 1, node: 5}})
+	c, ok := Truncated(ctx)
+	require.True(t, ok)
+	require.Equal(t, cursor{block: 1, node: 5}.String(), c)
+}
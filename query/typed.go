@@ -0,0 +1,92 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"strings"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// nodeTypes returns the dgraph.type values of the node, as fetched for the @typed directive.
+func (sg *SubGraph) nodeTypes(uid uint64) []string {
+	for _, pc := range sg.Children {
+		if !pc.Params.typeChild {
+			continue
+		}
+		idx := algo.IndexOf(pc.SrcUIDs, uid)
+		if idx < 0 || idx >= len(pc.valueMatrix) {
+			return nil
+		}
+		var out []string
+		for _, tv := range pc.valueMatrix[idx].Values {
+			if len(tv.Val) > 0 {
+				out = append(out, string(tv.Val))
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// typeFields returns the predicates that belong to at least one of the given types. It returns
+// nil if none of the types is defined in the schema, in which case all fields are valid.
+func typeFields(typeNames []string) map[string]struct{} {
+	var fields map[string]struct{}
+	for _, name := range typeNames {
+		typ, ok := schema.State().GetType(name)
+		if !ok {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]struct{})
+		}
+		for _, f := range typ.Fields {
+			fields[f.Predicate] = struct{}{}
+		}
+	}
+	return fields
+}
+
+// isTypeField tells if the child should be part of the output of a node whose types have
+// the given fields. Fields outside of the predicates, like uid, reverse edges and values
+// computed from variables, are always valid.
+func isTypeField(pc *SubGraph, fields map[string]struct{}) bool {
+	if fields == nil || pc.IsInternal() {
+		return true
+	}
+	switch {
+	case pc.Attr == "uid", pc.Attr == "dgraph.type", strings.HasPrefix(pc.Attr, "~"):
+		return true
+	}
+	_, ok := fields[pc.Attr]
+	return ok
+}
+
+// addNodeTypes annotates the output of the node with its types, unless they were asked for.
+func (sg *SubGraph) addNodeTypes(typeNames []string, dst outputNode) {
+	for _, pc := range sg.Children {
+		if pc.Attr == "dgraph.type" && !pc.Params.typeChild {
+			return
+		}
+	}
+	for _, name := range typeNames {
+		dst.AddListValue("dgraph.type", types.Val{Tid: types.StringID, Value: name}, true)
+	}
+}
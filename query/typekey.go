@@ -0,0 +1,141 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/pkg/errors"
+)
+
+// TypeKey returns the key predicates of the type, declared with @key.
+func TypeKey(ctx context.Context, typeName string) ([]string, error) {
+	types, err := worker.GetTypes(ctx, &pb.SchemaRequest{Types: []string{typeName}})
+	if err != nil {
+		return nil, err
+	}
+	if len(types) == 0 {
+		return nil, errors.Errorf("Type %s doesn't exist", typeName)
+	}
+	if len(types[0].Key) == 0 {
+		return nil, errors.Errorf("Type %s has no key declared with @key", typeName)
+	}
+	return types[0].Key, nil
+}
+
+// PredicateValues returns the values of the predicate of each of the sorted uids at readTs.
+func PredicateValues(ctx context.Context, attr string, uids []uint64,
+	readTs uint64) ([][]types.Val, error) {
+	result, err := worker.ProcessTaskOverNetwork(ctx, &pb.Query{
+		Attr:    attr,
+		UidList: &pb.List{Uids: uids},
+		ReadTs:  readTs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := make([][]types.Val, len(uids))
+	for i, list := range result.ValueMatrix {
+		for _, tv := range list.Values {
+			val, err := convertWithBestEffort(tv, attr)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = append(out[i], val)
+		}
+	}
+	return out, nil
+}
+
+// keyFilter returns the filter of the nodes of the type of the key function, whose key
+// predicates have its values: eq(pred1, v1) AND eq(pred2, v2) ... AND type(Type).
+func keyFilter(ctx context.Context, f *gql.Function) (*gql.FilterTree, error) {
+	key, err := TypeKey(ctx, f.Attr)
+	if err != nil {
+		return nil, err
+	}
+	if len(f.Args) != len(key) {
+		return nil, errors.Errorf("key function of type %s expects the %d values of (%s), got %d",
+			f.Attr, len(key), strings.Join(key, ", "), len(f.Args))
+	}
+	tree := &gql.FilterTree{Op: "and"}
+	for i, pred := range key {
+		tree.Child = append(tree.Child, &gql.FilterTree{
+			Func: &gql.Function{Name: "eq", Attr: pred, Args: []gql.Arg{f.Args[i]}},
+		})
+	}
+	tree.Child = append(tree.Child, &gql.FilterTree{
+		Func: &gql.Function{Name: "type", Args: []gql.Arg{{Value: f.Attr}}},
+	})
+	return tree, nil
+}
+
+// resolveKeyFuncs rewrites the key functions into the eq functions of the key predicates of
+// their type. At the root, the first one becomes the function of the block and the others are
+// added to its filter.
+func resolveKeyFuncs(ctx context.Context, gq *gql.GraphQuery) error {
+	var walkFilter func(ft *gql.FilterTree) error
+	walkFilter = func(ft *gql.FilterTree) error {
+		if ft == nil {
+			return nil
+		}
+		if ft.Func != nil && ft.Func.Name == "key" {
+			tree, err := keyFilter(ctx, ft.Func)
+			if err != nil {
+				return err
+			}
+			*ft = *tree
+			return nil
+		}
+		for _, ch := range ft.Child {
+			if err := walkFilter(ch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var walk func(gq *gql.GraphQuery) error
+	walk = func(gq *gql.GraphQuery) error {
+		if err := walkFilter(gq.Filter); err != nil {
+			return err
+		}
+		if gq.Func != nil && gq.Func.Name == "key" {
+			tree, err := keyFilter(ctx, gq.Func)
+			if err != nil {
+				return err
+			}
+			gq.Func = tree.Child[0].Func
+			if gq.Filter != nil {
+				tree.Child = append(tree.Child, gq.Filter)
+			}
+			gq.Filter = &gql.FilterTree{Op: "and", Child: tree.Child[1:]}
+		}
+		for _, ch := range gq.Children {
+			if err := walk(ch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(gq)
+}
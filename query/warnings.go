@@ -0,0 +1,65 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type warningsKey struct{}
+
+// warnings collects the non-fatal issues found while running a request.
+type warnings struct {
+	sync.Mutex
+	list []string
+	seen map[string]bool
+}
+
+// WithWarnings returns a context that collects the warnings added while running a request
+// with it, so that they can be read back with Warnings.
+func WithWarnings(ctx context.Context) context.Context {
+	return context.WithValue(ctx, warningsKey{}, &warnings{seen: make(map[string]bool)})
+}
+
+// AddWarning records a non-fatal issue with the request. It does nothing if the context
+// doesn't collect warnings, and the same warning is only recorded once.
+func AddWarning(ctx context.Context, format string, args ...interface{}) {
+	w, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return
+	}
+	msg := fmt.Sprintf(format, args...)
+	w.Lock()
+	defer w.Unlock()
+	if !w.seen[msg] {
+		w.seen[msg] = true
+		w.list = append(w.list, msg)
+	}
+}
+
+// Warnings returns the warnings collected for the context, in the order they were added.
+func Warnings(ctx context.Context) []string {
+	w, ok := ctx.Value(warningsKey{}).(*warnings)
+	if !ok {
+		return nil
+	}
+	w.Lock()
+	defer w.Unlock()
+	return append([]string(nil), w.list...)
+}
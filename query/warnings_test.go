@@ -0,0 +1,35 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarnings(t *testing.T) {
+	AddWarning(context.Background(), "ignored")
+	require.Empty(t, Warnings(context.Background()))
+
+	ctx := WithWarnings(context.Background())
+	AddWarning(ctx, "Results of %s were limited", "me")
+	AddWarning(ctx, "second")
+	AddWarning(ctx, "Results of %s were limited", "me")
+	require.Equal(t, []string{"Results of me were limited", "second"}, Warnings(ctx))
+}
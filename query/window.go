@@ -0,0 +1,109 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"sort"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/pkg/errors"
+)
+
+type windowEntry struct {
+	uid uint64
+	val types.Val
+}
+
+// sortWindowEntries sorts the entries by value, breaking ties by uid so that row_number
+// and cumsum are deterministic.
+func sortWindowEntries(entries []windowEntry, desc bool) error {
+	var sortErr error
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i].val, entries[j].val
+		if less, err := types.Less(a, b); err != nil {
+			sortErr = err
+			return false
+		} else if less {
+			return !desc
+		}
+		if less, _ := types.Less(b, a); less {
+			return desc
+		}
+		return entries[i].uid < entries[j].uid
+	})
+	return sortErr
+}
+
+// evalWindowFn computes rank, dense_rank, row_number or cumsum for every uid present in
+// the value variable used by the subgraph. Values are considered in descending order
+// unless the asc order was given, so that rank 1 is the highest value by default.
+func evalWindowFn(doneVars map[string]varValue, sg *SubGraph) (map[uint64]types.Val, error) {
+	if len(sg.Params.NeedsVar) == 0 {
+		return nil, errors.Errorf("Function %v requires a value variable", sg.SrcFunc.Name)
+	}
+	desc := true
+	if len(sg.SrcFunc.Args) > 0 && sg.SrcFunc.Args[0].Value == "asc" {
+		desc = false
+	}
+
+	vals := doneVars[sg.Params.NeedsVar[0].Name].Vals
+	entries := make([]windowEntry, 0, len(vals))
+	for uid, val := range vals {
+		if val.Value == nil {
+			continue
+		}
+		entries = append(entries, windowEntry{uid: uid, val: val})
+	}
+	if err := sortWindowEntries(entries, desc); err != nil {
+		return nil, errors.Wrapf(err, "While evaluating %v", sg.SrcFunc.Name)
+	}
+
+	mp := make(map[uint64]types.Val, len(entries))
+	if sg.SrcFunc.Name == "cumsum" {
+		ag := aggregator{name: "sum"}
+		for _, e := range entries {
+			if e.val.Tid != types.IntID && e.val.Tid != types.FloatID {
+				return nil, errors.Errorf("cumsum is only supported for int and float values."+
+					" Got: %s", e.val.Tid.Name())
+			}
+			ag.Apply(e.val)
+			mp[e.uid] = ag.result
+		}
+		return mp, nil
+	}
+
+	var rank, denseRank int64
+	for i, e := range entries {
+		// A value equal to the previous one shares its rank.
+		tie := i > 0 && types.CompareVals("eq", entries[i-1].val, e.val)
+		if !tie {
+			rank = int64(i + 1)
+			denseRank++
+		}
+		var v int64
+		switch sg.SrcFunc.Name {
+		case "rank":
+			v = rank
+		case "dense_rank":
+			v = denseRank
+		case "row_number":
+			v = int64(i + 1)
+		}
+		mp[e.uid] = types.Val{Tid: types.IntID, Value: v}
+	}
+	return mp, nil
+}
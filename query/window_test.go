@@ -0,0 +1,86 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"testing"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/stretchr/testify/require"
+)
+
+func windowTestVars() map[string]varValue {
+	intVal := func(v int64) types.Val { return types.Val{Tid: types.IntID, Value: v} }
+	return map[string]varValue{
+		"s": {Vals: map[uint64]types.Val{
+			1: intVal(10),
+			2: intVal(30),
+			3: intVal(20),
+			4: intVal(30),
+		}},
+	}
+}
+
+func windowTestSubGraph(name string, args ...gql.Arg) *SubGraph {
+	return &SubGraph{
+		SrcFunc: &Function{Name: name, Args: args},
+		Params:  params{NeedsVar: []gql.VarContext{{Name: "s", Typ: gql.ValueVar}}},
+	}
+}
+
+func TestWindowFnRank(t *testing.T) {
+	tests := []struct {
+		name string
+		args []gql.Arg
+		out  map[uint64]int64
+	}{
+		{name: "rank", out: map[uint64]int64{2: 1, 4: 1, 3: 3, 1: 4}},
+		{name: "dense_rank", out: map[uint64]int64{2: 1, 4: 1, 3: 2, 1: 3}},
+		{name: "row_number", out: map[uint64]int64{2: 1, 4: 2, 3: 3, 1: 4}},
+		{name: "rank", args: []gql.Arg{{Value: "asc"}},
+			out: map[uint64]int64{1: 1, 3: 2, 2: 3, 4: 3}},
+	}
+
+	for _, tc := range tests {
+		mp, err := evalWindowFn(windowTestVars(), windowTestSubGraph(tc.name, tc.args...))
+		require.NoError(t, err)
+		require.Equal(t, len(tc.out), len(mp))
+		for uid, v := range tc.out {
+			require.Equal(t, types.Val{Tid: types.IntID, Value: v}, mp[uid],
+				"%s of uid %d", tc.name, uid)
+		}
+	}
+}
+
+func TestWindowFnCumsum(t *testing.T) {
+	mp, err := evalWindowFn(windowTestVars(),
+		windowTestSubGraph("cumsum", gql.Arg{Value: "asc"}))
+	require.NoError(t, err)
+	require.Equal(t, int64(10), mp[1].Value)
+	require.Equal(t, int64(30), mp[3].Value)
+	require.Equal(t, int64(60), mp[2].Value)
+	require.Equal(t, int64(90), mp[4].Value)
+}
+
+func TestWindowFnCumsumNonNumeric(t *testing.T) {
+	doneVars := map[string]varValue{
+		"s": {Vals: map[uint64]types.Val{1: {Tid: types.StringID, Value: "a"}}},
+	}
+	_, err := evalWindowFn(doneVars, windowTestSubGraph("cumsum"))
+	require.Error(t, err)
+}
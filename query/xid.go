@@ -0,0 +1,118 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package query
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/worker"
+	"github.com/pkg/errors"
+)
+
+// XidPredicates returns the sorted list of predicates declared with @xid across all groups.
+func XidPredicates(ctx context.Context) ([]string, error) {
+	nodes, err := worker.GetSchemaOverNetwork(ctx, &pb.SchemaRequest{
+		Fields: []string{"tokenizer"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	xidTok := tok.XidTokenizer{}.Name()
+	var preds []string
+	for _, node := range nodes {
+		for _, t := range node.Tokenizer {
+			if t == xidTok {
+				preds = append(preds, node.Predicate)
+				break
+			}
+		}
+	}
+	sort.Strings(preds)
+	return preds, nil
+}
+
+// DefaultXidPredicate returns the predicate to use when an external id is given without
+// one. It's only defined when exactly one predicate is declared with @xid.
+func DefaultXidPredicate(preds []string) (string, error) {
+	switch len(preds) {
+	case 0:
+		return "", errors.Errorf("No predicate has been declared with @xid")
+	case 1:
+		return preds[0], nil
+	}
+	return "", errors.Errorf("More than one predicate is declared with @xid: %v."+
+		" Specify the predicate to look up the external id", preds)
+}
+
+// resolveXidFuncs sets the attribute of the xid functions that didn't specify one. The schema
+// is only fetched if such a function is found.
+func resolveXidFuncs(ctx context.Context, gq *gql.GraphQuery) error {
+	var pred string
+	resolve := func(f *gql.Function) error {
+		if f == nil || f.Name != "xid" || f.Attr != "" {
+			return nil
+		}
+		if pred == "" {
+			preds, err := XidPredicates(ctx)
+			if err != nil {
+				return err
+			}
+			if pred, err = DefaultXidPredicate(preds); err != nil {
+				return err
+			}
+		}
+		f.Attr = pred
+		return nil
+	}
+
+	var walkFilter func(ft *gql.FilterTree) error
+	walkFilter = func(ft *gql.FilterTree) error {
+		if ft == nil {
+			return nil
+		}
+		if err := resolve(ft.Func); err != nil {
+			return err
+		}
+		for _, ch := range ft.Child {
+			if err := walkFilter(ch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var walk func(gq *gql.GraphQuery) error
+	walk = func(gq *gql.GraphQuery) error {
+		if err := resolve(gq.Func); err != nil {
+			return err
+		}
+		if err := walkFilter(gq.Filter); err != nil {
+			return err
+		}
+		for _, ch := range gq.Children {
+			if err := walk(ch); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return walk(gq)
+}
@@ -0,0 +1,586 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package querybuilder builds GraphQL+- queries programmatically. Every value given to a
+// function or used for pagination is sent as a GraphQL variable instead of being written
+// into the query text, and predicate and variable names are validated, so that user input
+// can't change the structure of the query.
+//
+//	q := querybuilder.New("people")
+//	q.Block("me", querybuilder.Eq("name", name)).
+//		Filter(querybuilder.Has("age")).
+//		First(10).
+//		Fields("uid", "name").
+//		Edge("friend", func(b *querybuilder.Block) {
+//			b.Facets("since").Fields("name")
+//		})
+//	text, vars, err := q.Build()
+package querybuilder
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Query is a query made of blocks.
+type Query struct {
+	name   string
+	blocks []*Block
+	vars   []variable
+}
+
+type variable struct {
+	name  string
+	typ   string
+	value string
+}
+
+// New returns an empty query with the given name.
+func New(name string) *Query {
+	if name == "" {
+		name = "q"
+	}
+	return &Query{name: name}
+}
+
+// Block adds a block to the query, returning the nodes matched by the root function.
+func (q *Query) Block(alias string, root *Func) *Block {
+	b := &Block{name: alias, root: root}
+	q.blocks = append(q.blocks, b)
+	return b
+}
+
+// Var adds a var block to the query. Its results are only used through the variables that
+// it defines.
+func (q *Query) Var(root *Func) *Block {
+	return q.Block("var", root)
+}
+
+// Build returns the text of the query and the values of its GraphQL variables, in the form
+// expected by the Vars of api.Request.
+func (q *Query) Build() (string, map[string]string, error) {
+	q.vars = q.vars[:0]
+	if err := checkName(q.name); err != nil {
+		return "", nil, err
+	}
+	if len(q.blocks) == 0 {
+		return "", nil, errors.Errorf("Query %s has no blocks", q.name)
+	}
+
+	var body strings.Builder
+	for _, b := range q.blocks {
+		if err := b.writeRoot(q, &body); err != nil {
+			return "", nil, err
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteString("query ")
+	sb.WriteString(q.name)
+	vars := make(map[string]string, len(q.vars))
+	if len(q.vars) > 0 {
+		decls := make([]string, len(q.vars))
+		for i, v := range q.vars {
+			decls[i] = v.name + ": " + v.typ
+			vars[v.name] = v.value
+		}
+		sb.WriteString("(" + strings.Join(decls, ", ") + ")")
+	}
+	sb.WriteString(" {\n")
+	sb.WriteString(body.String())
+	sb.WriteString("}\n")
+	return sb.String(), vars, nil
+}
+
+// addVar declares a GraphQL variable holding v and returns its name.
+func (q *Query) addVar(v interface{}) (string, error) {
+	var typ, value string
+	switch v := v.(type) {
+	case string:
+		typ, value = "string", v
+	case int:
+		typ, value = "int", strconv.Itoa(v)
+	case int32:
+		typ, value = "int", strconv.FormatInt(int64(v), 10)
+	case int64:
+		typ, value = "int", strconv.FormatInt(v, 10)
+	case float32:
+		typ, value = "float", strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		typ, value = "float", strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		typ, value = "bool", strconv.FormatBool(v)
+	case time.Time:
+		typ, value = "string", v.Format(time.RFC3339Nano)
+	default:
+		return "", errors.Errorf("Unsupported value type %T for %v", v, v)
+	}
+	name := fmt.Sprintf("$v%d", len(q.vars))
+	q.vars = append(q.vars, variable{name: name, typ: typ, value: value})
+	return name, nil
+}
+
+var (
+	nameRe = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+	predRe = regexp.MustCompile(`^~?[\p{L}\p{N}_.]+(@[a-zA-Z:.\-]+)?$`)
+)
+
+// checkName checks the name of a query, an alias or a variable.
+func checkName(name string) error {
+	if !nameRe.MatchString(name) {
+		return errors.Errorf("Invalid name: %q", name)
+	}
+	return nil
+}
+
+// predicate returns how pred is written in the query. Predicates with characters that
+// aren't allowed in names are written as IRIs.
+func predicate(pred string) (string, error) {
+	if predRe.MatchString(pred) {
+		return pred, nil
+	}
+	if pred == "" || strings.ContainsAny(pred, "<>\"{}|^`\\") ||
+		strings.IndexFunc(pred, func(r rune) bool { return r <= ' ' }) >= 0 {
+		return "", errors.Errorf("Invalid predicate: %q", pred)
+	}
+	return "<" + pred + ">", nil
+}
+
+// Func is a function used at the root of a block or in a filter.
+type Func struct {
+	name string
+	attr string
+	args []interface{}
+	uids []uint64
+	vars []string
+}
+
+// Fn returns the function name applied to pred with the given arguments, like
+// Fn("anyofterms", "name", "alice bob").
+func Fn(name, pred string, args ...interface{}) *Func {
+	return &Func{name: name, attr: pred, args: args}
+}
+
+// Eq returns eq(pred, values...).
+func Eq(pred string, values ...interface{}) *Func { return Fn("eq", pred, values...) }
+
+// Le returns le(pred, value).
+func Le(pred string, value interface{}) *Func { return Fn("le", pred, value) }
+
+// Lt returns lt(pred, value).
+func Lt(pred string, value interface{}) *Func { return Fn("lt", pred, value) }
+
+// Ge returns ge(pred, value).
+func Ge(pred string, value interface{}) *Func { return Fn("ge", pred, value) }
+
+// Gt returns gt(pred, value).
+func Gt(pred string, value interface{}) *Func { return Fn("gt", pred, value) }
+
+// Has returns has(pred).
+func Has(pred string) *Func { return Fn("has", pred) }
+
+// Type returns type(name).
+func Type(name string) *Func { return &Func{name: "type", vars: []string{name}} }
+
+// Uid returns uid(uids...).
+func Uid(uids ...uint64) *Func { return &Func{name: "uid", uids: uids} }
+
+// UidVar returns uid(vars...) for the given uid or value variables.
+func UidVar(vars ...string) *Func { return &Func{name: "uid", vars: vars} }
+
+// Val returns val(v), to be used as the predicate of a function, like Gt(Val("score"), 3).
+func Val(v string) string { return "val(" + v + ")" }
+
+// Count returns count(pred), to be used as the predicate of a function.
+func Count(pred string) string { return "count(" + pred + ")" }
+
+var wrappedRe = regexp.MustCompile(`^(val|count)\((.*)\)$`)
+
+func (f *Func) write(q *Query, sb *strings.Builder) error {
+	if !nameRe.MatchString(f.name) {
+		return errors.Errorf("Invalid function name: %q", f.name)
+	}
+	var args []string
+	switch {
+	case f.name == "uid":
+		for _, uid := range f.uids {
+			args = append(args, fmt.Sprintf("%#x", uid))
+		}
+		fallthrough
+	case f.name == "type":
+		for _, v := range f.vars {
+			if err := checkName(v); err != nil {
+				return err
+			}
+			args = append(args, v)
+		}
+	default:
+		attr, err := funcAttr(f.attr)
+		if err != nil {
+			return err
+		}
+		args = append(args, attr)
+		for _, arg := range f.args {
+			name, err := q.addVar(arg)
+			if err != nil {
+				return err
+			}
+			args = append(args, name)
+		}
+	}
+	sb.WriteString(f.name + "(" + strings.Join(args, ", ") + ")")
+	return nil
+}
+
+func funcAttr(attr string) (string, error) {
+	m := wrappedRe.FindStringSubmatch(attr)
+	if m == nil {
+		return predicate(attr)
+	}
+	if m[1] == "val" {
+		return attr, checkName(m[2])
+	}
+	pred, err := predicate(m[2])
+	return m[1] + "(" + pred + ")", err
+}
+
+// Filter is a condition of a @filter directive.
+type Filter interface {
+	write(q *Query, sb *strings.Builder) error
+}
+
+type connective struct {
+	op      string
+	filters []Filter
+}
+
+// And returns the conjunction of the filters.
+func And(filters ...Filter) Filter { return &connective{op: "and", filters: filters} }
+
+// Or returns the disjunction of the filters.
+func Or(filters ...Filter) Filter { return &connective{op: "or", filters: filters} }
+
+// Not returns the negation of the filter.
+func Not(f Filter) Filter { return &connective{op: "not", filters: []Filter{f}} }
+
+func (c *connective) write(q *Query, sb *strings.Builder) error {
+	if len(c.filters) == 0 {
+		return errors.Errorf("Empty %s filter", c.op)
+	}
+	if c.op == "not" {
+		sb.WriteString("not ")
+	}
+	sb.WriteString("(")
+	for i, f := range c.filters {
+		if i > 0 {
+			sb.WriteString(" " + c.op + " ")
+		}
+		if err := f.write(q, sb); err != nil {
+			return err
+		}
+	}
+	sb.WriteString(")")
+	return nil
+}
+
+type order struct {
+	pred string
+	desc bool
+}
+
+type field struct {
+	alias   string
+	varName string
+	pred    string
+	facets  []string
+	block   *Block
+}
+
+// Block is a query block or an edge of it. Its methods return the block so that calls can
+// be chained.
+type Block struct {
+	name      string
+	varName   string
+	root      *Func
+	filter    Filter
+	first     *int
+	offset    *int
+	after     uint64
+	orders    []order
+	facets    []string
+	hasFacets bool
+	fields    []field
+	cascade   bool
+	normalize bool
+}
+
+// As stores the uids of the block in the variable v.
+func (b *Block) As(v string) *Block { b.varName = v; return b }
+
+// Filter sets the @filter directive of the block.
+func (b *Block) Filter(f Filter) *Block { b.filter = f; return b }
+
+// First limits the block to its first n results, or its last ones if n is negative.
+func (b *Block) First(n int) *Block { b.first = &n; return b }
+
+// Offset skips the first n results of the block.
+func (b *Block) Offset(n int) *Block { b.offset = &n; return b }
+
+// After returns the results of the block with a uid greater than uid.
+func (b *Block) After(uid uint64) *Block { b.after = uid; return b }
+
+// OrderAsc orders the results of the block by pred in ascending order. Further orders sort
+// the results with the same value.
+func (b *Block) OrderAsc(pred string) *Block {
+	b.orders = append(b.orders, order{pred: pred})
+	return b
+}
+
+// OrderDesc orders the results of the block by pred in descending order.
+func (b *Block) OrderDesc(pred string) *Block {
+	b.orders = append(b.orders, order{pred: pred, desc: true})
+	return b
+}
+
+// Facets adds the @facets directive to an edge, fetching the given facets or all of them if
+// none is given.
+func (b *Block) Facets(keys ...string) *Block {
+	b.hasFacets = true
+	b.facets = append(b.facets, keys...)
+	return b
+}
+
+// Cascade adds the @cascade directive to the block.
+func (b *Block) Cascade() *Block { b.cascade = true; return b }
+
+// Normalize adds the @normalize directive to the block.
+func (b *Block) Normalize() *Block { b.normalize = true; return b }
+
+// Fields fetches the given scalar predicates, uid, or values of variables given by Val.
+func (b *Block) Fields(preds ...string) *Block {
+	for _, pred := range preds {
+		b.fields = append(b.fields, field{pred: pred})
+	}
+	return b
+}
+
+// Alias fetches pred under the name alias.
+func (b *Block) Alias(alias, pred string) *Block {
+	b.fields = append(b.fields, field{alias: alias, pred: pred})
+	return b
+}
+
+// ValueVar stores the values of pred in the value variable v.
+func (b *Block) ValueVar(v, pred string) *Block {
+	b.fields = append(b.fields, field{varName: v, pred: pred})
+	return b
+}
+
+// FieldFacets fetches the scalar predicate pred along with the given facets.
+func (b *Block) FieldFacets(pred string, keys ...string) *Block {
+	if keys == nil {
+		keys = []string{}
+	}
+	b.fields = append(b.fields, field{pred: pred, facets: keys})
+	return b
+}
+
+// Edge follows the uid predicate pred. The edge is built by fn.
+func (b *Block) Edge(pred string, fn func(e *Block)) *Block {
+	e := &Block{name: pred}
+	fn(e)
+	b.fields = append(b.fields, field{pred: pred, block: e})
+	return b
+}
+
+func (b *Block) writeRoot(q *Query, sb *strings.Builder) error {
+	if b.root == nil {
+		return errors.Errorf("Block %s has no root function", b.name)
+	}
+	if err := checkName(b.name); err != nil {
+		return err
+	}
+	if b.hasFacets {
+		return errors.Errorf("@facets can only be used on edges. Got it on block %s", b.name)
+	}
+	sb.WriteString("\t")
+	if b.varName != "" {
+		if err := checkName(b.varName); err != nil {
+			return err
+		}
+		sb.WriteString(b.varName + " as ")
+	}
+	sb.WriteString(b.name + "(func: ")
+	if err := b.root.write(q, sb); err != nil {
+		return err
+	}
+	if err := b.writeArgs(q, sb, true); err != nil {
+		return err
+	}
+	sb.WriteString(")")
+	if err := b.writeDirectives(q, sb); err != nil {
+		return err
+	}
+	return b.writeBody(q, sb, 1)
+}
+
+// writeArgs writes the pagination and ordering arguments. At the root they follow the
+// function, otherwise they are written within their own parentheses.
+func (b *Block) writeArgs(q *Query, sb *strings.Builder, root bool) error {
+	var args []string
+	for _, o := range b.orders {
+		pred, err := funcAttr(o.pred)
+		if err != nil {
+			return err
+		}
+		if o.desc {
+			args = append(args, "orderdesc: "+pred)
+		} else {
+			args = append(args, "orderasc: "+pred)
+		}
+	}
+	for _, p := range []struct {
+		name string
+		val  *int
+	}{{"first", b.first}, {"offset", b.offset}} {
+		if p.val == nil {
+			continue
+		}
+		v, err := q.addVar(*p.val)
+		if err != nil {
+			return err
+		}
+		args = append(args, p.name+": "+v)
+	}
+	if b.after != 0 {
+		args = append(args, fmt.Sprintf("after: %#x", b.after))
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	if root {
+		sb.WriteString(", " + strings.Join(args, ", "))
+		return nil
+	}
+	sb.WriteString(" (" + strings.Join(args, ", ") + ")")
+	return nil
+}
+
+func (b *Block) writeDirectives(q *Query, sb *strings.Builder) error {
+	if b.hasFacets {
+		if err := writeFacets(sb, b.facets); err != nil {
+			return err
+		}
+	}
+	if b.filter != nil {
+		sb.WriteString(" @filter")
+		// And and Or already wrap themselves in parentheses.
+		var fsb strings.Builder
+		if err := b.filter.write(q, &fsb); err != nil {
+			return err
+		}
+		f := fsb.String()
+		if _, ok := b.filter.(*Func); ok || strings.HasPrefix(f, "not ") {
+			f = "(" + f + ")"
+		}
+		sb.WriteString(f)
+	}
+	if b.cascade {
+		sb.WriteString(" @cascade")
+	}
+	if b.normalize {
+		sb.WriteString(" @normalize")
+	}
+	return nil
+}
+
+func writeFacets(sb *strings.Builder, keys []string) error {
+	sorted := append(keys[:0:0], keys...)
+	sort.Strings(sorted)
+	for _, k := range sorted {
+		if err := checkName(k); err != nil {
+			return err
+		}
+	}
+	if len(sorted) == 0 {
+		sb.WriteString(" @facets")
+		return nil
+	}
+	sb.WriteString(" @facets(" + strings.Join(sorted, ", ") + ")")
+	return nil
+}
+
+func (b *Block) writeBody(q *Query, sb *strings.Builder, depth int) error {
+	if len(b.fields) == 0 {
+		return errors.Errorf("Block %s has no fields", b.name)
+	}
+	sb.WriteString(" {\n")
+	indent := strings.Repeat("\t", depth+1)
+	for _, f := range b.fields {
+		pred, err := funcAttr(f.pred)
+		if err != nil {
+			return err
+		}
+		sb.WriteString(indent)
+		switch {
+		case f.alias != "":
+			if err := checkName(f.alias); err != nil {
+				return err
+			}
+			sb.WriteString(f.alias + ": ")
+		case f.varName != "":
+			if err := checkName(f.varName); err != nil {
+				return err
+			}
+			sb.WriteString(f.varName + " as ")
+		case f.block != nil && f.block.varName != "":
+			if err := checkName(f.block.varName); err != nil {
+				return err
+			}
+			sb.WriteString(f.block.varName + " as ")
+		}
+		sb.WriteString(pred)
+		if f.facets != nil {
+			if err := writeFacets(sb, f.facets); err != nil {
+				return err
+			}
+		}
+		if f.block != nil {
+			if f.block.root != nil {
+				return errors.Errorf("Edge %s can't have a root function", f.pred)
+			}
+			if err := f.block.writeArgs(q, sb, false); err != nil {
+				return err
+			}
+			if err := f.block.writeDirectives(q, sb); err != nil {
+				return err
+			}
+			if err := f.block.writeBody(q, sb, depth+1); err != nil {
+				return err
+			}
+			continue
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString(strings.Repeat("\t", depth) + "}\n")
+	return nil
+}
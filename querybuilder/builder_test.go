@@ -0,0 +1,127 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package querybuilder
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgraph/gql"
+	"github.com/stretchr/testify/require"
+)
+
+func parse(t *testing.T, q *Query) (string, map[string]string, gql.Result) {
+	text, vars, err := q.Build()
+	require.NoError(t, err)
+	res, err := gql.Parse(gql.Request{Str: text, Variables: vars})
+	require.NoError(t, err, text)
+	return text, vars, res
+}
+
+func TestBuild(t *testing.T) {
+	q := New("people")
+	q.Var(Fn("anyofterms", "name@en", "alice bob")).
+		Edge("friend", func(e *Block) {
+			e.As("f").ValueVar("a", "age")
+		})
+	q.Block("me", UidVar("f")).
+		Filter(And(Gt(Val("a"), 18), Not(Has("banned")))).
+		OrderDesc(Val("a")).
+		First(10).
+		Offset(5).
+		Fields("uid", "name").
+		Alias("years", Val("a")).
+		FieldFacets("nick", "origin").
+		Edge("~friend", func(e *Block) {
+			e.Facets("since", "close").
+				Filter(Or(Eq("name", "x\" } evil { y"), Ge("dob", time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)))).
+				First(2).
+				Fields("name").
+				Edge("http://schema.org/knows", func(e *Block) { e.Fields("uid") })
+		}).
+		Cascade()
+
+	text, vars, res := parse(t, q)
+	require.Equal(t, `query people($v0: string, $v1: int, $v2: int, $v3: int, $v4: int, $v5: string, $v6: string) {
+	var(func: anyofterms(name@en, $v0)) {
+		f as friend {
+			a as age
+		}
+	}
+	me(func: uid(f), orderdesc: val(a), first: $v1, offset: $v2) @filter(gt(val(a), $v3) and not (has(banned))) @cascade {
+		uid
+		name
+		years: val(a)
+		nick @facets(origin)
+		~friend (first: $v4) @facets(close, since) @filter(eq(name, $v5) or ge(dob, $v6)) {
+			name
+			<http://schema.org/knows> {
+				uid
+			}
+		}
+	}
+}
+`, text)
+	require.Equal(t, map[string]string{
+		"$v0": "alice bob",
+		"$v1": "10",
+		"$v2": "5",
+		"$v3": "18",
+		"$v4": "2",
+		"$v5": "x\" } evil { y",
+		"$v6": "2000-01-01T00:00:00Z",
+	}, vars)
+
+	require.Len(t, res.Query, 2)
+	me := res.Query[1]
+	require.Equal(t, "me", me.Alias)
+	require.Equal(t, "10", me.Args["first"])
+	friend := me.Children[4]
+	require.Equal(t, "~friend", friend.Attr)
+	require.Equal(t, "2", friend.Args["first"])
+	require.Equal(t, "x\" } evil { y", friend.Filter.Child[0].Func.Args[0].Value)
+}
+
+func TestBuildUidAndType(t *testing.T) {
+	q := New("")
+	q.Block("me", Uid(1, 0x2a)).After(3).Filter(Type("Person")).Fields("name")
+	text, _, res := parse(t, q)
+	require.Contains(t, text, "me(func: uid(0x1, 0x2a), after: 0x3) @filter(type(Person))")
+	require.Equal(t, []uint64{1, 0x2a}, res.Query[0].UID)
+}
+
+func TestBuildErrors(t *testing.T) {
+	tests := []func(q *Query){
+		func(q *Query) {},
+		func(q *Query) { q.Block("me", nil).Fields("name") },
+		func(q *Query) { q.Block("me", Has("name")) },
+		func(q *Query) { q.Block("me evil", Has("name")).Fields("name") },
+		func(q *Query) { q.Block("me", Has("name> } {")).Fields("name") },
+		func(q *Query) { q.Block("me", Has("name")).Fields("a b") },
+		func(q *Query) { q.Block("me", Eq("name", []string{"a"})).Fields("name") },
+		func(q *Query) { q.Block("me", UidVar("f)")).Fields("name") },
+		func(q *Query) { q.Block("me", Has("name")).Facets().Fields("name") },
+		func(q *Query) { q.Block("me", Has("name")).Filter(And()).Fields("name") },
+		func(q *Query) { q.Block("me", Has(Val("a b"))).Fields("name") },
+	}
+	for i, fn := range tests {
+		q := New("q")
+		fn(q)
+		_, _, err := q.Build()
+		require.Error(t, err, "case %d", i)
+	}
+}
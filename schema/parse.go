@@ -17,8 +17,10 @@
 package schema
 
 import (
+	"strconv"
 	"strings"
 
+	"github.com/dgraph-io/dgraph/collate"
 	"github.com/dgraph-io/dgraph/lex"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/tok"
@@ -63,12 +65,71 @@ func parseDirective(it *lex.ItemIterator, schema *pb.SchemaUpdate, t types.TypeI
 		if err != nil {
 			return err
 		}
+		if hasXidTokenizer(schema.Tokenizer) && !hasXidTokenizer(tokenizer) {
+			// @xid might come before @index, keep its tokenizer.
+			tokenizer = append(tokenizer, (tok.XidTokenizer{}).Name())
+		}
 		schema.Directive = pb.SchemaUpdate_INDEX
 		schema.Tokenizer = tokenizer
+	case "xid":
+		if t != types.StringID || schema.List {
+			return next.Errorf("@xid directive can only be specified for string type."+
+				" Got: [%v] for attr: [%v]", t.Name(), schema.Predicate)
+		}
+		if !hasXidTokenizer(schema.Tokenizer) {
+			schema.Tokenizer = append(schema.Tokenizer, (tok.XidTokenizer{}).Name())
+		}
+		// An external id must identify a single node, so conflicts are always checked.
+		schema.Directive = pb.SchemaUpdate_INDEX
+		schema.Upsert = true
+	case "masked":
+		if t == types.UidID || t == types.PasswordID || t == types.DefaultID {
+			return next.Errorf("@masked directive can only be specified for scalar types."+
+				" Got: [%v] for attr: [%v]", t.Name(), schema.Predicate)
+		}
+		policy, err := parseMaskedDirective(it, schema.Predicate)
+		if err != nil {
+			return err
+		}
+		schema.Masked = policy
+	case "maxsize":
+		if t == types.UidID {
+			return next.Errorf("@maxsize directive can only be specified for scalar types."+
+				" Got: [%v] for attr: [%v]", t.Name(), schema.Predicate)
+		}
+		size, err := parseMaxSizeDirective(it, schema.Predicate)
+		if err != nil {
+			return err
+		}
+		schema.MaxSize = size
+	case "collate":
+		if t != types.StringID {
+			return next.Errorf("@collate directive can only be specified for string type."+
+				" Got: [%v] for attr: [%v]", t.Name(), schema.Predicate)
+		}
+		lang, err := parseCollateDirective(it, schema.Predicate)
+		if err != nil {
+			return err
+		}
+		schema.Collation = lang
+	case "conflict":
+		granularity, err := parseConflictDirective(it, schema.Predicate)
+		if err != nil {
+			return err
+		}
+		schema.Conflict = granularity
+	case "facets":
+		fcs, err := parseFacetsDirective(it, schema.Predicate)
+		if err != nil {
+			return err
+		}
+		schema.Facets = fcs
 	case "count":
 		schema.Count = true
 	case "upsert":
 		schema.Upsert = true
+	case "appendonly":
+		schema.AppendOnly = true
 	case "lang":
 		if t != types.StringID || schema.List {
 			return next.Errorf("@lang directive can only be specified for string type."+
@@ -83,6 +144,173 @@ func parseDirective(it *lex.ItemIterator, schema *pb.SchemaUpdate, t types.TypeI
 	return nil
 }
 
+// parseMaskedDirective returns the policy of the @masked(policy) directive.
+func parseMaskedDirective(it *lex.ItemIterator, predicate string) (string, error) {
+	if !it.Next() || it.Item().Typ != itemLeftRound {
+		return "", it.Item().Errorf("Require masking policy of pred: %s", predicate)
+	}
+	it.Next()
+	next := it.Item()
+	if next.Typ != itemText {
+		return "", next.Errorf("Expected masking policy but got: %v", next.Val)
+	}
+	policy := strings.ToLower(next.Val)
+	switch policy {
+	case "hash", "partial", "drop":
+	default:
+		return "", next.Errorf("Invalid masking policy %s: expected hash, partial or drop",
+			next.Val)
+	}
+	if !it.Next() || it.Item().Typ != itemRightRound {
+		return "", it.Item().Errorf("Expected ) after masking policy of pred: %s", predicate)
+	}
+	return policy, nil
+}
+
+// parseMaxSizeDirective returns the size in bytes of the @maxsize(size) directive.
+func parseMaxSizeDirective(it *lex.ItemIterator, predicate string) (uint64, error) {
+	if !it.Next() || it.Item().Typ != itemLeftRound {
+		return 0, it.Item().Errorf("Require maximum size of pred: %s", predicate)
+	}
+	it.Next()
+	next := it.Item()
+	if next.Typ != itemNumber {
+		return 0, next.Errorf("Expected maximum size but got: %v", next.Val)
+	}
+	size, err := strconv.ParseUint(next.Val, 10, 64)
+	if err != nil || size == 0 {
+		return 0, next.Errorf("Invalid maximum size %s: expected a positive number of bytes",
+			next.Val)
+	}
+	if !it.Next() || it.Item().Typ != itemRightRound {
+		return 0, it.Item().Errorf("Expected ) after maximum size of pred: %s", predicate)
+	}
+	return size, nil
+}
+
+// parseCollateDirective returns the language of the @collate(lang) directive.
+func parseCollateDirective(it *lex.ItemIterator, predicate string) (string, error) {
+	if !it.Next() || it.Item().Typ != itemLeftRound {
+		return "", it.Item().Errorf("Require collation language of pred: %s", predicate)
+	}
+	it.Next()
+	next := it.Item()
+	if next.Typ != itemText {
+		return "", next.Errorf("Expected collation language but got: %v", next.Val)
+	}
+	if err := collate.Valid(next.Val); err != nil {
+		return "", next.Errorf("%v", err)
+	}
+	if !it.Next() || it.Item().Typ != itemRightRound {
+		return "", it.Item().Errorf("Expected ) after collation language of pred: %s", predicate)
+	}
+	return next.Val, nil
+}
+
+// parseConflictDirective returns the granularity of the @conflict(granularity) directive.
+func parseConflictDirective(it *lex.ItemIterator, predicate string) (string, error) {
+	if !it.Next() || it.Item().Typ != itemLeftRound {
+		return "", it.Item().Errorf("Require conflict granularity of pred: %s", predicate)
+	}
+	it.Next()
+	next := it.Item()
+	if next.Typ != itemText {
+		return "", next.Errorf("Expected conflict granularity but got: %v", next.Val)
+	}
+	granularity := strings.ToLower(next.Val)
+	switch granularity {
+	case "predicate", "uid", "value", "none":
+	default:
+		return "", next.Errorf("Invalid conflict granularity %s: expected predicate, uid, "+
+			"value or none", next.Val)
+	}
+	if !it.Next() || it.Item().Typ != itemRightRound {
+		return "", it.Item().Errorf("Expected ) after conflict granularity of pred: %s",
+			predicate)
+	}
+	return granularity, nil
+}
+
+// parseFacetsDirective returns the facets declared by the @facets(name: type @index(tokenizer),
+// ...) directive. Each facet has the name of the facet as its predicate.
+func parseFacetsDirective(it *lex.ItemIterator, predicate string) ([]*pb.SchemaUpdate, error) {
+	if !it.Next() || it.Item().Typ != itemLeftRound {
+		return nil, it.Item().Errorf("Require facets of pred: %s", predicate)
+	}
+	var fcs []*pb.SchemaUpdate
+	seen := make(map[string]bool)
+	for {
+		it.Next()
+		next := it.Item()
+		if next.Typ == itemRightRound && len(fcs) > 0 {
+			return fcs, nil
+		}
+		if len(fcs) > 0 {
+			if next.Typ != itemComma {
+				return nil, next.Errorf("Expected a comma or ) after facet of pred: %s",
+					predicate)
+			}
+			it.Next()
+			next = it.Item()
+		}
+		if next.Typ != itemText {
+			return nil, next.Errorf("Expected facet name but got: %v", next.Val)
+		}
+		if seen[next.Val] {
+			return nil, next.Errorf("Facet %s declared multiple times for pred: %s", next.Val,
+				predicate)
+		}
+		seen[next.Val] = true
+		facet := &pb.SchemaUpdate{Predicate: next.Val}
+
+		if !it.Next() || it.Item().Typ != itemColon {
+			return nil, it.Item().Errorf("Missing colon after facet %s of pred: %s",
+				facet.Predicate, predicate)
+		}
+		it.Next()
+		next = it.Item()
+		t, ok := types.TypeForName(strings.ToLower(next.Val))
+		switch {
+		case next.Typ != itemText || !ok:
+			return nil, next.Errorf("Undefined type of facet %s of pred: %s", facet.Predicate,
+				predicate)
+		case t != types.StringID && t != types.IntID && t != types.FloatID &&
+			t != types.BoolID && t != types.DateTimeID:
+			return nil, next.Errorf("Invalid type %s of facet %s of pred: %s: expected string,"+
+				" int, float, bool or datetime", t.Name(), facet.Predicate, predicate)
+		}
+		facet.ValueType = t.Enum()
+
+		if item, ok := it.PeekOne(); ok && item.Typ == itemAt {
+			it.Next()
+			if !it.Next() || it.Item().Typ != itemText || it.Item().Val != "index" {
+				return nil, it.Item().Errorf("Only @index can be specified for facet %s of"+
+					" pred: %s", facet.Predicate, predicate)
+			}
+			tokenizers, err := parseIndexDirective(it, facet.Predicate, t)
+			if err != nil {
+				return nil, err
+			}
+			if len(tokenizers) == 0 {
+				return nil, it.Item().Errorf("Require type of tokenizer for facet %s of pred: %s"+
+					" for indexing.", facet.Predicate, predicate)
+			}
+			facet.Directive = pb.SchemaUpdate_INDEX
+			facet.Tokenizer = tokenizers
+		}
+		fcs = append(fcs, facet)
+	}
+}
+
+func hasXidTokenizer(tokenizers []string) bool {
+	for _, t := range tokenizers {
+		if t == (tok.XidTokenizer{}).Name() {
+			return true
+		}
+	}
+	return false
+}
+
 func parseScalarPair(it *lex.ItemIterator, predicate string) (*pb.SchemaUpdate, error) {
 	it.Next()
 	next := it.Item()
@@ -155,6 +383,16 @@ func parseScalarPair(it *lex.ItemIterator, predicate string) (*pb.SchemaUpdate,
 	if next.Typ != itemDot {
 		return nil, next.Errorf("Invalid ending")
 	}
+	if schema.Upsert && schema.Conflict != "" {
+		// The conflicts of an @upsert predicate are what makes its values unique.
+		return nil, next.Errorf("@conflict can't be used with @upsert or @xid for pred: %s",
+			predicate)
+	}
+	if schema.AppendOnly && (schema.Upsert || schema.Conflict != "") {
+		// Append-only predicates aren't part of transactions, so they never conflict.
+		return nil, next.Errorf("@appendonly can't be used with @upsert, @xid or @conflict"+
+			" for pred: %s", predicate)
+	}
 	it.Next()
 	next = it.Item()
 	if next.Typ == lex.ItemEOF {
@@ -174,7 +412,8 @@ func parseIndexDirective(it *lex.ItemIterator, predicate string,
 	var seen = make(map[string]bool)
 	var seenSortableTok bool
 
-	if typ == types.UidID || typ == types.DefaultID || typ == types.PasswordID {
+	if typ == types.UidID || typ == types.DefaultID || typ == types.PasswordID ||
+		typ == types.JSONID {
 		return tokenizers, it.Item().Errorf("Indexing not allowed on predicate %s of type %s",
 			predicate, typ.Name())
 	}
@@ -245,8 +484,8 @@ func resolveTokenizers(updates []*pb.SchemaUpdate) error {
 	for _, schema := range updates {
 		typ := types.TypeID(schema.ValueType)
 
-		if (typ == types.UidID || typ == types.DefaultID || typ == types.PasswordID) &&
-			schema.Directive == pb.SchemaUpdate_INDEX {
+		if (typ == types.UidID || typ == types.DefaultID || typ == types.PasswordID ||
+			typ == types.JSONID) && schema.Directive == pb.SchemaUpdate_INDEX {
 			return errors.Errorf("Indexing not allowed on predicate %s of type %s",
 				schema.Predicate, typ.Name())
 		}
@@ -305,6 +544,12 @@ func parseTypeDeclaration(it *lex.ItemIterator) (*pb.TypeUpdate, error) {
 	typeUpdate := &pb.TypeUpdate{TypeName: it.Item().Val}
 
 	it.Next()
+	for it.Item().Typ == itemAt {
+		if err := parseTypeDirective(it, typeUpdate); err != nil {
+			return nil, err
+		}
+		it.Next()
+	}
 	if it.Item().Typ != itemLeftCurl {
 		return nil, it.Item().Errorf("Expected {. Got %v", it.Item().Val)
 	}
@@ -322,6 +567,9 @@ func parseTypeDeclaration(it *lex.ItemIterator) (*pb.TypeUpdate, error) {
 			}
 
 			typeUpdate.Fields = fields
+			if err := checkTypeKey(typeUpdate); err != nil {
+				return nil, err
+			}
 			return typeUpdate, nil
 		case itemText:
 			field, err := parseTypeField(it)
@@ -338,6 +586,83 @@ func parseTypeDeclaration(it *lex.ItemIterator) (*pb.TypeUpdate, error) {
 	return nil, errors.Errorf("Shouldn't reach here.")
 }
 
+// parseTypeDirective parses a directive of a type declaration, @key(pred1, pred2, ...) or
+// @strict.
+func parseTypeDirective(it *lex.ItemIterator, typ *pb.TypeUpdate) error {
+	it.Next()
+	next := it.Item()
+	switch {
+	case next.Typ == itemText && next.Val == "key":
+		if len(typ.Key) > 0 {
+			return next.Errorf("Repeated @key directive of type: %s", typ.TypeName)
+		}
+		key, err := parseKeyDirective(it, typ.TypeName)
+		if err != nil {
+			return err
+		}
+		typ.Key = key
+	case next.Typ == itemText && next.Val == "strict":
+		typ.Strict = true
+	default:
+		return next.Errorf("Invalid directive of type %s: expected @key or @strict", typ.TypeName)
+	}
+	return nil
+}
+
+// parseKeyDirective returns the predicates of the @key(pred1, pred2, ...) directive of a type.
+func parseKeyDirective(it *lex.ItemIterator, typeName string) ([]string, error) {
+	if !it.Next() || it.Item().Typ != itemLeftRound {
+		return nil, it.Item().Errorf("Require key predicates of type: %s", typeName)
+	}
+	var key []string
+	for it.Next() {
+		next := it.Item()
+		if next.Typ != itemText {
+			return nil, next.Errorf("Expected key predicate but got: %v", next.Val)
+		}
+		for _, pred := range key {
+			if pred == next.Val {
+				return nil, next.Errorf("Repeated key predicate %s of type: %s", pred, typeName)
+			}
+		}
+		key = append(key, next.Val)
+
+		it.Next()
+		switch it.Item().Typ {
+		case itemRightRound:
+			return key, nil
+		case itemComma:
+		default:
+			return nil, it.Item().Errorf("Expected , or ) after key predicate of type: %s",
+				typeName)
+		}
+	}
+	return nil, it.Item().Errorf("Expected ) after key predicates of type: %s", typeName)
+}
+
+// checkTypeKey checks that the key predicates of the type are single valued scalar fields of it.
+func checkTypeKey(typ *pb.TypeUpdate) error {
+	for _, pred := range typ.Key {
+		var field *pb.SchemaUpdate
+		for _, f := range typ.Fields {
+			if f.Predicate == pred {
+				field = f
+				break
+			}
+		}
+		switch {
+		case field == nil:
+			return errors.Errorf("Key predicate %s is not a field of type: %s", pred, typ.TypeName)
+		case field.List:
+			return errors.Errorf("Key predicate %s of type %s can't be a list", pred, typ.TypeName)
+		case field.ValueType == pb.Posting_UID || field.ValueType == pb.Posting_OBJECT:
+			return errors.Errorf("Key predicate %s of type %s must be a scalar", pred,
+				typ.TypeName)
+		}
+	}
+	return nil
+}
+
 func parseTypeField(it *lex.ItemIterator) (*pb.SchemaUpdate, error) {
 	field := &pb.SchemaUpdate{Predicate: it.Item().Val}
 	var list bool
@@ -416,7 +741,7 @@ func isTypeDeclaration(item lex.Item, it *lex.ItemIterator) bool {
 	case nextItems[0].Typ != itemText:
 		return false
 
-	case nextItems[1].Typ != itemLeftCurl:
+	case nextItems[1].Typ != itemLeftCurl && nextItems[1].Typ != itemAt:
 		return false
 	}
 
@@ -24,6 +24,7 @@ import (
 	"github.com/dgraph-io/badger"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dgraph-io/dgraph/collate"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/x"
@@ -143,11 +144,16 @@ var schemaIndexVal3Password = `
 pass: password @index .
 `
 
+var schemaIndexVal3JSON = `
+settings: json @index(exact) .
+`
+
 // Object types cant be indexed.
 func TestSchemaIndex_Error2(t *testing.T) {
 	require.Error(t, ParseBytes([]byte(schemaIndexVal3Uid), 1))
 	require.Error(t, ParseBytes([]byte(schemaIndexVal3Default), 1))
 	require.Error(t, ParseBytes([]byte(schemaIndexVal3Password), 1))
+	require.Error(t, ParseBytes([]byte(schemaIndexVal3JSON), 1))
 }
 
 var schemaIndexVal4 = `
@@ -159,6 +165,55 @@ func TestSchemaIndex_Error3(t *testing.T) {
 	require.Error(t, ParseBytes([]byte(schemaIndexVal4), 1))
 }
 
+var schemaXidVal = `
+email    : string @xid .
+username : string @index(exact) @xid .
+code     : string @xid @index(hash) .
+name     : string @index(exact) .
+`
+
+func TestSchemaXid(t *testing.T) {
+	require.NoError(t, ParseBytes([]byte(schemaXidVal), 1))
+	checkSchema(t, State().predicate, []nameType{
+		{"email", &pb.SchemaUpdate{
+			Predicate: "email",
+			ValueType: pb.Posting_STRING,
+			Tokenizer: []string{"xid"},
+			Directive: pb.SchemaUpdate_INDEX,
+			Upsert:    true,
+		}},
+		{"username", &pb.SchemaUpdate{
+			Predicate: "username",
+			ValueType: pb.Posting_STRING,
+			Tokenizer: []string{"exact", "xid"},
+			Directive: pb.SchemaUpdate_INDEX,
+			Upsert:    true,
+		}},
+		{"code", &pb.SchemaUpdate{
+			Predicate: "code",
+			ValueType: pb.Posting_STRING,
+			Tokenizer: []string{"hash", "xid"},
+			Directive: pb.SchemaUpdate_INDEX,
+			Upsert:    true,
+		}},
+		{"name", &pb.SchemaUpdate{
+			Predicate: "name",
+			ValueType: pb.Posting_STRING,
+			Tokenizer: []string{"exact"},
+			Directive: pb.SchemaUpdate_INDEX,
+		}},
+	})
+	require.True(t, State().IsXid("email"))
+	require.False(t, State().IsXid("name"))
+	require.False(t, State().IsXid("missing"))
+	require.Equal(t, []string{"code", "email", "username"}, State().XidPredicates())
+}
+
+func TestSchemaXid_Error(t *testing.T) {
+	require.Error(t, ParseBytes([]byte("age: int @xid ."), 1))
+	require.Error(t, ParseBytes([]byte("emails: [string] @xid ."), 1))
+}
+
 var schemaIndexVal5 = `
 age     : int @index(int) .
 name    : string @index(exact) @count .
@@ -735,6 +790,57 @@ func TestParseTypeErrMissingType(t *testing.T) {
 	require.Contains(t, err.Error(), "Missing field type in type declaration")
 }
 
+func TestParseTypeKey(t *testing.T) {
+	reset()
+	result, err := Parse(`
+		type User @key(tenant, email) {
+			tenant: string
+			email: string
+			name: string
+		}
+	`)
+	require.NoError(t, err)
+	require.Len(t, result.Types, 1)
+	require.Equal(t, []string{"tenant", "email"}, result.Types[0].Key)
+	require.Len(t, result.Types[0].Fields, 3)
+}
+
+func TestParseTypeKeyErr(t *testing.T) {
+	for schema, msg := range map[string]string{
+		"type User @key(tenant, email) {\n tenant: string\n}\n":  "is not a field of type",
+		"type User @key(tenant, tenant) {\n tenant: string\n}\n": "Repeated key predicate",
+		"type User @key(emails) {\n emails: [string]\n}\n":       "can't be a list",
+		"type User @key(org) {\n org: Org\n}\n":                  "must be a scalar",
+		"type User @key() {\n tenant: string\n}\n":               "Expected key predicate",
+		"type User @index(tenant) {\n tenant: string\n}\n":       "expected @key",
+		"type User @key(a) @key(a) {\n a: string\n}\n":           "Repeated @key directive",
+	} {
+		reset()
+		_, err := Parse(schema)
+		require.Error(t, err, schema)
+		require.Contains(t, err.Error(), msg, schema)
+	}
+}
+
+func TestParseTypeStrict(t *testing.T) {
+	reset()
+	result, err := Parse(`
+		type User @strict @key(email) {
+			email: string!
+			name: string
+		}
+		type Org {
+			name: string
+		}
+	`)
+	require.NoError(t, err)
+	require.Len(t, result.Types, 2)
+	require.True(t, result.Types[0].Strict)
+	require.Equal(t, []string{"email"}, result.Types[0].Key)
+	require.True(t, result.Types[0].Fields[0].NonNullable)
+	require.False(t, result.Types[1].Strict)
+}
+
 func TestParseComments(t *testing.T) {
 	reset()
 	_, err := Parse(`
@@ -813,3 +919,208 @@ func TestMain(m *testing.M) {
 	os.RemoveAll(dir)
 	os.Exit(r)
 }
+
+var schemaMaskedVal = `
+email : string @index(exact) @masked(hash) .
+ssn   : string @masked(partial) .
+age   : int @masked(drop) .
+`
+
+func TestSchemaMasked(t *testing.T) {
+	require.NoError(t, ParseBytes([]byte(schemaMaskedVal), 1))
+	checkSchema(t, State().predicate, []nameType{
+		{"email", &pb.SchemaUpdate{
+			Predicate: "email",
+			ValueType: pb.Posting_STRING,
+			Tokenizer: []string{"exact"},
+			Directive: pb.SchemaUpdate_INDEX,
+			Masked:    "hash",
+		}},
+		{"ssn", &pb.SchemaUpdate{
+			Predicate: "ssn",
+			ValueType: pb.Posting_STRING,
+			Masked:    "partial",
+		}},
+		{"age", &pb.SchemaUpdate{
+			Predicate: "age",
+			ValueType: pb.Posting_INT,
+			Masked:    "drop",
+		}},
+	})
+	require.Equal(t, "hash", State().Masked("email"))
+	require.Equal(t, "", State().Masked("missing"))
+}
+
+func TestSchemaMasked_Error(t *testing.T) {
+	require.Error(t, ParseBytes([]byte("email: string @masked ."), 1))
+	require.Error(t, ParseBytes([]byte("email: string @masked(shuffle) ."), 1))
+	require.Error(t, ParseBytes([]byte("email: string @masked(hash ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: uid @masked(drop) ."), 1))
+	require.Error(t, ParseBytes([]byte("pass: password @masked(drop) ."), 1))
+}
+
+var schemaMaxSizeVal = `
+bio: string @maxsize(1024) .
+photo: [string] @maxsize(65536) @index(exact) .
+`
+
+func TestSchemaMaxSize(t *testing.T) {
+	require.NoError(t, ParseBytes([]byte(schemaMaxSizeVal), 1))
+	checkSchema(t, State().predicate, []nameType{
+		{"bio", &pb.SchemaUpdate{
+			Predicate: "bio",
+			ValueType: pb.Posting_STRING,
+			MaxSize:   1024,
+		}},
+		{"photo", &pb.SchemaUpdate{
+			Predicate: "photo",
+			ValueType: pb.Posting_STRING,
+			List:      true,
+			Tokenizer: []string{"exact"},
+			Directive: pb.SchemaUpdate_INDEX,
+			MaxSize:   65536,
+		}},
+	})
+	require.Equal(t, uint64(1024), State().MaxSize("bio"))
+	require.Equal(t, uint64(0), State().MaxSize("missing"))
+}
+
+func TestSchemaMaxSize_Error(t *testing.T) {
+	require.Error(t, ParseBytes([]byte("bio: string @maxsize ."), 1))
+	require.Error(t, ParseBytes([]byte("bio: string @maxsize(0) ."), 1))
+	require.Error(t, ParseBytes([]byte("bio: string @maxsize(big) ."), 1))
+	require.Error(t, ParseBytes([]byte("bio: string @maxsize(10 ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: uid @maxsize(10) ."), 1))
+}
+
+var schemaCollateVal = `
+name: string @collate(sv) @index(exact) .
+title: string @collate(pt-BR) .
+`
+
+func TestSchemaCollate(t *testing.T) {
+	require.NoError(t, ParseBytes([]byte(schemaCollateVal), 1))
+	checkSchema(t, State().predicate, []nameType{
+		{"name", &pb.SchemaUpdate{
+			Predicate: "name",
+			ValueType: pb.Posting_STRING,
+			Tokenizer: []string{"exact"},
+			Directive: pb.SchemaUpdate_INDEX,
+			Collation: "sv",
+		}},
+		{"title", &pb.SchemaUpdate{
+			Predicate: "title",
+			ValueType: pb.Posting_STRING,
+			Collation: "pt-BR",
+		}},
+	})
+	require.Equal(t, "sv", State().Collation("name"))
+	require.Equal(t, "", State().Collation("missing"))
+
+	// The exact index of the predicate holds the collation keys.
+	tokenizers := State().Tokenizer("name")
+	require.Len(t, tokenizers, 1)
+	tokens, err := tokenizers[0].Tokens("åsna")
+	require.NoError(t, err)
+	require.Equal(t, []string{string(collate.Key("sv", "åsna"))}, tokens)
+}
+
+func TestSchemaCollate_Error(t *testing.T) {
+	require.Error(t, ParseBytes([]byte("name: string @collate ."), 1))
+	require.Error(t, ParseBytes([]byte("name: string @collate(sv ."), 1))
+	require.Error(t, ParseBytes([]byte("name: string @collate(abcdefghij) ."), 1))
+	require.Error(t, ParseBytes([]byte("age: int @collate(sv) ."), 1))
+}
+
+var schemaConflictVal = `
+visit: [uid] @conflict(none) .
+views: int @conflict(predicate) .
+status: string @conflict(VALUE) .
+`
+
+func TestSchemaConflict(t *testing.T) {
+	require.NoError(t, ParseBytes([]byte(schemaConflictVal), 1))
+	checkSchema(t, State().predicate, []nameType{
+		{"visit", &pb.SchemaUpdate{
+			Predicate: "visit",
+			ValueType: pb.Posting_UID,
+			List:      true,
+			Conflict:  "none",
+		}},
+		{"views", &pb.SchemaUpdate{
+			Predicate: "views",
+			ValueType: pb.Posting_INT,
+			Conflict:  "predicate",
+		}},
+		{"status", &pb.SchemaUpdate{
+			Predicate: "status",
+			ValueType: pb.Posting_STRING,
+			Conflict:  "value",
+		}},
+	})
+	require.Equal(t, "none", State().Conflict("visit"))
+	require.Equal(t, "", State().Conflict("missing"))
+}
+
+func TestSchemaConflict_Error(t *testing.T) {
+	require.Error(t, ParseBytes([]byte("views: int @conflict ."), 1))
+	require.Error(t, ParseBytes([]byte("views: int @conflict(row) ."), 1))
+	require.Error(t, ParseBytes([]byte("views: int @conflict(uid ."), 1))
+	require.Error(t, ParseBytes([]byte("email: string @index(exact) @upsert @conflict(none) ."), 1))
+	require.Error(t, ParseBytes([]byte("email: string @conflict(uid) @xid ."), 1))
+}
+
+func TestSchemaAppendOnly(t *testing.T) {
+	require.NoError(t, ParseBytes([]byte("event: [uid] @count @appendonly ."), 1))
+	checkSchema(t, State().predicate, []nameType{
+		{"event", &pb.SchemaUpdate{
+			Predicate:  "event",
+			ValueType:  pb.Posting_UID,
+			List:       true,
+			Count:      true,
+			AppendOnly: true,
+		}},
+	})
+	require.True(t, State().IsAppendOnly("event"))
+	require.False(t, State().IsAppendOnly("missing"))
+
+	require.Error(t, ParseBytes([]byte("email: string @xid @appendonly ."), 1))
+	require.Error(t, ParseBytes([]byte("event: [uid] @appendonly @conflict(none) ."), 1))
+}
+
+func TestSchemaFacets(t *testing.T) {
+	require.NoError(t, ParseBytes([]byte(
+		"friend: [uid] @facets(since: datetime @index(year), weight: Float) @reverse ."), 1))
+	checkSchema(t, State().predicate, []nameType{
+		{"friend", &pb.SchemaUpdate{
+			Predicate: "friend",
+			ValueType: pb.Posting_UID,
+			List:      true,
+			Directive: pb.SchemaUpdate_REVERSE,
+			Facets: []*pb.SchemaUpdate{
+				{
+					Predicate: "since",
+					ValueType: pb.Posting_DATETIME,
+					Directive: pb.SchemaUpdate_INDEX,
+					Tokenizer: []string{"year"},
+				},
+				{Predicate: "weight", ValueType: pb.Posting_FLOAT},
+			},
+		}},
+	})
+	require.Len(t, State().Facets("friend"), 2)
+	require.Len(t, State().IndexedFacets("friend"), 1)
+	require.Nil(t, State().Facets("missing"))
+}
+
+func TestSchemaFacets_Error(t *testing.T) {
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets() ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets(since) ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets(since: geo) ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets(since: int since: int) ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets(since: int, since: int) ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets(since: int @count) ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets(since: int @index(term)) ."), 1))
+	require.Error(t, ParseBytes([]byte("friend: [uid] @facets(since: int @index()) ."), 1))
+}
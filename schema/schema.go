@@ -19,7 +19,9 @@ package schema
 import (
 	"bytes"
 	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 
 	"github.com/dgraph-io/badger"
 	"github.com/golang/glog"
@@ -36,6 +38,9 @@ import (
 var (
 	pstate *state
 	pstore *badger.DB
+	// version is incremented on every change of the schema. It's kept outside of the state
+	// so that it keeps increasing when the state is reset.
+	version uint64
 )
 
 func (s *state) init() {
@@ -57,9 +62,16 @@ func State() *state {
 	return pstate
 }
 
+// Version returns a number that changes every time the schema is changed, so that values
+// derived from the schema can tell when they are stale.
+func (s *state) Version() uint64 {
+	return atomic.LoadUint64(&version)
+}
+
 func (s *state) DeleteAll() {
 	s.Lock()
 	defer s.Unlock()
+	atomic.AddUint64(&version, 1)
 
 	for pred := range s.predicate {
 		delete(s.predicate, pred)
@@ -86,6 +98,7 @@ func (s *state) Delete(attr string) error {
 	}
 
 	delete(s.predicate, attr)
+	atomic.AddUint64(&version, 1)
 	return nil
 }
 
@@ -105,6 +118,7 @@ func (s *state) DeleteType(typeName string) error {
 	}
 
 	delete(s.types, typeName)
+	atomic.AddUint64(&version, 1)
 	return nil
 }
 
@@ -127,6 +141,7 @@ func (s *state) Set(pred string, schema pb.SchemaUpdate) {
 	s.Lock()
 	defer s.Unlock()
 	s.predicate[pred] = &schema
+	atomic.AddUint64(&version, 1)
 	s.elog.Printf(logUpdate(schema, pred))
 }
 
@@ -136,6 +151,7 @@ func (s *state) SetType(typeName string, typ pb.TypeUpdate) {
 	s.Lock()
 	defer s.Unlock()
 	s.types[typeName] = &typ
+	atomic.AddUint64(&version, 1)
 	s.elog.Printf(logTypeUpdate(typ, typeName))
 }
 
@@ -226,7 +242,7 @@ func (s *state) Tokenizer(pred string) []tok.Tokenizer {
 	for _, it := range schema.Tokenizer {
 		t, found := tok.GetTokenizer(it)
 		x.AssertTruef(found, "Invalid tokenizer %s", it)
-		tokenizers = append(tokenizers, t)
+		tokenizers = append(tokenizers, tok.GetCollatedTokenizer(t, schema.Collation))
 	}
 	return tokenizers
 }
@@ -291,6 +307,106 @@ func (s *state) HasUpsert(pred string) bool {
 	return false
 }
 
+// Masked returns the masking policy of the predicate, or an empty string if its values aren't
+// masked.
+func (s *state) Masked(pred string) string {
+	s.RLock()
+	defer s.RUnlock()
+	if schema, ok := s.predicate[pred]; ok {
+		return schema.Masked
+	}
+	return ""
+}
+
+// IsAppendOnly returns whether the predicate has the @appendonly directive.
+func (s *state) IsAppendOnly(pred string) bool {
+	s.RLock()
+	defer s.RUnlock()
+	if schema, ok := s.predicate[pred]; ok {
+		return schema.AppendOnly
+	}
+	return false
+}
+
+// Conflict returns the granularity of the conflict detection of the predicate set by its
+// @conflict directive, or an empty string for the default one.
+func (s *state) Conflict(pred string) string {
+	s.RLock()
+	defer s.RUnlock()
+	if schema, ok := s.predicate[pred]; ok {
+		return schema.Conflict
+	}
+	return ""
+}
+
+// MaxSize returns the maximum size in bytes of the values of the predicate, or zero if it's
+// unlimited.
+func (s *state) MaxSize(pred string) uint64 {
+	s.RLock()
+	defer s.RUnlock()
+	if schema, ok := s.predicate[pred]; ok {
+		return schema.MaxSize
+	}
+	return 0
+}
+
+// Collation returns the language whose collation orders the values of the predicate, or an
+// empty string if they are ordered bytewise.
+func (s *state) Collation(pred string) string {
+	s.RLock()
+	defer s.RUnlock()
+	if schema, ok := s.predicate[pred]; ok {
+		return schema.Collation
+	}
+	return ""
+}
+
+// IsXid returns whether the predicate was declared with the @xid directive.
+func (s *state) IsXid(pred string) bool {
+	s.RLock()
+	defer s.RUnlock()
+	if schema, ok := s.predicate[pred]; ok {
+		return hasXidTokenizer(schema.Tokenizer)
+	}
+	return false
+}
+
+// XidPredicates returns the sorted list of predicates declared with the @xid directive.
+func (s *state) XidPredicates() []string {
+	s.RLock()
+	defer s.RUnlock()
+	var out []string
+	for pred, schema := range s.predicate {
+		if hasXidTokenizer(schema.Tokenizer) {
+			out = append(out, pred)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Facets returns the facets declared by the @facets directive of the predicate, or nil if its
+// facets aren't declared.
+func (s *state) Facets(pred string) []*pb.SchemaUpdate {
+	s.RLock()
+	defer s.RUnlock()
+	if schema, ok := s.predicate[pred]; ok {
+		return schema.Facets
+	}
+	return nil
+}
+
+// IndexedFacets returns the facets of the predicate declared with an index.
+func (s *state) IndexedFacets(pred string) []*pb.SchemaUpdate {
+	var out []*pb.SchemaUpdate
+	for _, f := range s.Facets(pred) {
+		if len(f.Tokenizer) > 0 {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
 func (s *state) HasLang(pred string) bool {
 	s.RLock()
 	defer s.RUnlock()
@@ -488,6 +604,7 @@ func IsReservedPredicateChanged(pred string, update *pb.SchemaUpdate) bool {
 }
 
 func reset() {
+	atomic.AddUint64(&version, 1)
 	pstate = new(state)
 	pstate.init()
 }
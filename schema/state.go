@@ -35,6 +35,7 @@ const (
 	itemLeftSquare
 	itemRightSquare
 	itemExclamationMark
+	itemNumber // unsigned integer
 )
 
 func lexText(l *lex.Lexer) lex.StateFn {
@@ -81,6 +82,8 @@ Loop:
 		case r == '_':
 			// Predicates can start with _.
 			return lexWord
+		case isDigit(r):
+			return lexNumber
 		default:
 			return l.Errorf("Invalid schema. Unexpected %s", l.Input[l.Start:l.Pos])
 		}
@@ -106,6 +109,15 @@ func lexWord(l *lex.Lexer) lex.StateFn {
 	return lexText
 }
 
+// lexNumber lexes an unsigned integer, the first digit of which was absorbed.
+func lexNumber(l *lex.Lexer) lex.StateFn {
+	for isDigit(l.Next()) {
+	}
+	l.Backup()
+	l.Emit(itemNumber)
+	return lexText
+}
+
 // lexTextComment lexes a comment text inside a schema.
 func lexTextComment(l *lex.Lexer) lex.StateFn {
 	for {
@@ -137,11 +149,15 @@ func isNameBegin(r rune) bool {
 	}
 }
 
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
 func isNameSuffix(r rune) bool {
 	if isNameBegin(r) {
 		return true
 	}
-	if r >= '0' && r <= '9' {
+	if isDigit(r) {
 		return true
 	}
 	if r == '_' || r == '.' || r == '-' { // Use by freebase.
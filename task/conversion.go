@@ -58,3 +58,13 @@ func ToBool(val *pb.TaskValue) bool {
 	result := ToInt(val)
 	return result != 0
 }
+
+// FromString converts the given string in to a pb.TaskValue object.
+func FromString(val string) *pb.TaskValue {
+	return &pb.TaskValue{Val: []byte(val), ValType: pb.Posting_STRING}
+}
+
+// ToString converts the given pb.TaskValue object into a string.
+func ToString(val *pb.TaskValue) string {
+	return string(val.Val)
+}
@@ -19,12 +19,14 @@ package tok
 import (
 	"encoding/binary"
 	"plugin"
+	"strings"
 	"time"
 
 	"github.com/golang/glog"
 	geom "github.com/twpayne/go-geom"
 	"golang.org/x/crypto/blake2b"
 
+	"github.com/dgraph-io/dgraph/collate"
 	"github.com/dgraph-io/dgraph/types"
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/pkg/errors"
@@ -49,9 +51,18 @@ const (
 	IdentBool     = 0x9
 	IdentTrigram  = 0xA
 	IdentHash     = 0xB
+	IdentXid      = 0xC
+	IdentExactCI  = 0xD
+	IdentFacet    = 0x7F
 	IdentCustom   = 0x80
 )
 
+// FacetToken returns the token of the facet index of a predicate for the facet with the given
+// key, from a token of its value encoded with the identifier of its tokenizer.
+func FacetToken(key, token string) string {
+	return string([]byte{IdentFacet}) + key + "\x00" + token
+}
+
 // Tokenizer defines what a tokenizer must provide.
 type Tokenizer interface {
 
@@ -90,9 +101,11 @@ func init() {
 	registerTokenizer(MonthTokenizer{})
 	registerTokenizer(DayTokenizer{})
 	registerTokenizer(ExactTokenizer{})
+	registerTokenizer(ExactCITokenizer{})
 	registerTokenizer(BoolTokenizer{})
 	registerTokenizer(TrigramTokenizer{})
 	registerTokenizer(HashTokenizer{})
+	registerTokenizer(XidTokenizer{})
 	registerTokenizer(TermTokenizer{})
 	registerTokenizer(FullTextTokenizer{})
 	setupBleve()
@@ -288,13 +301,17 @@ func (t TermTokenizer) Identifier() byte { return IdentTerm }
 func (t TermTokenizer) IsSortable() bool { return false }
 func (t TermTokenizer) IsLossy() bool    { return true }
 
-// ExactTokenizer returns the exact string as a token.
-type ExactTokenizer struct{}
+// ExactTokenizer returns the exact string as a token. If the predicate has a collation, the
+// token is the collation key of the string instead so that the index is in collation order.
+type ExactTokenizer struct{ collation string }
 
 func (t ExactTokenizer) Name() string { return "exact" }
 func (t ExactTokenizer) Type() string { return "string" }
 func (t ExactTokenizer) Tokens(v interface{}) ([]string, error) {
 	if term, ok := v.(string); ok {
+		if t.collation != "" {
+			return []string{string(collate.Key(t.collation, term))}, nil
+		}
 		return []string{term}, nil
 	}
 	return nil, errors.Errorf("Exact indices only supported for string types")
@@ -303,6 +320,22 @@ func (t ExactTokenizer) Identifier() byte { return IdentExact }
 func (t ExactTokenizer) IsSortable() bool { return true }
 func (t ExactTokenizer) IsLossy() bool    { return false }
 
+// ExactCITokenizer returns the lower case string as a token, so that eq() matches the values
+// regardless of their case. It isn't sortable, as the tokens don't keep the order of the values.
+type ExactCITokenizer struct{}
+
+func (t ExactCITokenizer) Name() string { return "exact_ci" }
+func (t ExactCITokenizer) Type() string { return "string" }
+func (t ExactCITokenizer) Tokens(v interface{}) ([]string, error) {
+	if term, ok := v.(string); ok {
+		return []string{strings.ToLower(term)}, nil
+	}
+	return nil, errors.Errorf("Exact indices only supported for string types")
+}
+func (t ExactCITokenizer) Identifier() byte { return IdentExactCI }
+func (t ExactCITokenizer) IsSortable() bool { return false }
+func (t ExactCITokenizer) IsLossy() bool    { return false }
+
 // FullTextTokenizer generates full-text tokens from string data.
 type FullTextTokenizer struct{ lang string }
 
@@ -395,6 +428,23 @@ func (t HashTokenizer) IsSortable() bool { return false }
 // query operations using the hash index.
 func (t HashTokenizer) IsLossy() bool { return false }
 
+// XidTokenizer indexes the external id of a node. It is added by the @xid directive and
+// stores the exact string, so that a lookup by xid never needs to fetch the values.
+type XidTokenizer struct{}
+
+func (t XidTokenizer) Name() string { return "xid" }
+func (t XidTokenizer) Type() string { return "string" }
+func (t XidTokenizer) Tokens(v interface{}) ([]string, error) {
+	term, ok := v.(string)
+	if !ok {
+		return nil, errors.Errorf("Xid tokenizer only supported for string types")
+	}
+	return []string{term}, nil
+}
+func (t XidTokenizer) Identifier() byte { return IdentXid }
+func (t XidTokenizer) IsSortable() bool { return false }
+func (t XidTokenizer) IsLossy() bool    { return false }
+
 // PluginTokenizer is implemented by external plugins loaded dynamically via
 // *.so files. It follows the implementation semantics of the Tokenizer
 // interface.
@@ -426,6 +476,36 @@ func encodeInt(val int64) string {
 	return string(buf)
 }
 
+// DecodeToken returns the value an index token of the tokenizer was built from, for the
+// tokenizers whose tokens are the values themselves: exact without a collation, xid, int and
+// bool.
+// The token starts with the identifier of the tokenizer, as in the index keys.
+func DecodeToken(t Tokenizer, token string) (types.Val, bool) {
+	if len(token) == 0 || token[0] != t.Identifier() {
+		return types.Val{}, false
+	}
+	token = token[1:]
+	switch t := t.(type) {
+	case ExactTokenizer:
+		if t.collation != "" {
+			return types.Val{}, false
+		}
+		return types.Val{Tid: types.StringID, Value: token}, true
+	case XidTokenizer:
+		return types.Val{Tid: types.StringID, Value: token}, true
+	case IntTokenizer, BoolTokenizer:
+		if len(token) != 9 {
+			return types.Val{}, false
+		}
+		v := int64(binary.BigEndian.Uint64([]byte(token[1:])))
+		if _, ok := t.(BoolTokenizer); ok {
+			return types.Val{Tid: types.BoolID, Value: v != 0}, true
+		}
+		return types.Val{Tid: types.IntID, Value: v}, true
+	}
+	return types.Val{}, false
+}
+
 func encodeToken(tok string, typ byte) string {
 	return string(typ) + tok
 }
@@ -179,6 +179,65 @@ func TestTrigramTokenizer(t *testing.T) {
 	require.Equal(t, expected, tokens)
 }
 
+func TestExactCITokenizer(t *testing.T) {
+	tokenizer, has := GetTokenizer("exact_ci")
+	require.True(t, has)
+	require.False(t, tokenizer.IsSortable())
+	require.False(t, tokenizer.IsLossy())
+
+	tokens, err := BuildTokens("Alice@Example.COM", tokenizer)
+	require.NoError(t, err)
+	require.Equal(t, []string{encodeToken("alice@example.com", IdentExactCI)}, tokens)
+
+	_, err = tokenizer.Tokens(int64(1))
+	require.Error(t, err)
+}
+
+func TestXidTokenizer(t *testing.T) {
+	tokenizer, has := GetTokenizer("xid")
+	require.True(t, has)
+	require.False(t, tokenizer.IsSortable())
+	require.False(t, tokenizer.IsLossy())
+
+	tokens, err := BuildTokens("user-123", tokenizer)
+	require.NoError(t, err)
+	require.Equal(t, []string{encodeToken("user-123", IdentXid)}, tokens)
+
+	_, err = tokenizer.Tokens(int64(1))
+	require.Error(t, err)
+}
+
+func TestDecodeToken(t *testing.T) {
+	for _, v := range []interface{}{"Alice", int64(-5), int64(0), int64(math.MaxInt64), true,
+		false} {
+		var tokenizer Tokenizer
+		switch v.(type) {
+		case string:
+			tokenizer = ExactTokenizer{}
+		case int64:
+			tokenizer = IntTokenizer{}
+		case bool:
+			tokenizer = BoolTokenizer{}
+		}
+		tokens, err := BuildTokens(v, tokenizer)
+		require.NoError(t, err)
+		val, ok := DecodeToken(tokenizer, tokens[0])
+		require.True(t, ok)
+		require.Equal(t, v, val.Value)
+	}
+
+	// The tokens of the other tokenizers aren't the values.
+	for _, tokenizer := range []Tokenizer{ExactTokenizer{collation: "en"}, HashTokenizer{},
+		TermTokenizer{}} {
+		tokens, err := BuildTokens("Alice", tokenizer)
+		require.NoError(t, err)
+		_, ok := DecodeToken(tokenizer, tokens[0])
+		require.False(t, ok)
+	}
+	_, ok := DecodeToken(IntTokenizer{}, encodeToken("Alice", IdentExact))
+	require.False(t, ok)
+}
+
 func TestGetFullTextTokens(t *testing.T) {
 	val := "Our chief weapon is surprise...surprise and fear...fear and surprise...." +
 		"Our two weapons are fear and surprise...and ruthless efficiency.... " +
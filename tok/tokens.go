@@ -34,6 +34,19 @@ func GetLangTokenizer(t Tokenizer, lang string) Tokenizer {
 	return t
 }
 
+// GetCollatedTokenizer returns the tokenizer of a predicate with the given collation. Only the
+// exact tokenizer depends on the collation.
+func GetCollatedTokenizer(t Tokenizer, collation string) Tokenizer {
+	if collation == "" {
+		return t
+	}
+	switch t.(type) {
+	case ExactTokenizer:
+		return ExactTokenizer{collation: collation}
+	}
+	return t
+}
+
 // GetTokens returns the tokens for the given tokenizer ID and value.
 // funcArgs should only have one element which is the value that needs to be tokenized.
 func GetTokens(id byte, funcArgs ...string) ([]string, error) {
@@ -89,6 +89,12 @@ func Convert(from Val, toID TypeID) (Val, error) {
 				*res = w
 			case PasswordID:
 				*res = string(data)
+			case JSONID:
+				j, err := compactJSON(data)
+				if err != nil {
+					return to, err
+				}
+				*res = j
 			default:
 				return to, cantConvert(fromID, toID)
 			}
@@ -142,6 +148,12 @@ func Convert(from Val, toID TypeID) (Val, error) {
 					return to, err
 				}
 				*res = p
+			case JSONID:
+				j, err := compactJSON(data)
+				if err != nil {
+					return to, err
+				}
+				*res = j
 			default:
 				return to, cantConvert(fromID, toID)
 			}
@@ -302,6 +314,18 @@ func Convert(from Val, toID TypeID) (Val, error) {
 				return to, cantConvert(fromID, toID)
 			}
 		}
+	case JSONID:
+		{
+			vc := string(data)
+			switch toID {
+			case BinaryID:
+				*res = []byte(vc)
+			case StringID, DefaultID, JSONID:
+				*res = vc
+			default:
+				return to, cantConvert(fromID, toID)
+			}
+		}
 	default:
 		return to, cantConvert(fromID, toID)
 	}
@@ -433,6 +457,16 @@ func Marshal(from Val, to *Val) error {
 		default:
 			return cantConvert(fromID, toID)
 		}
+	case JSONID:
+		vc := val.(string)
+		switch toID {
+		case StringID, DefaultID, JSONID:
+			*res = vc
+		case BinaryID:
+			*res = []byte(vc)
+		default:
+			return cantConvert(fromID, toID)
+		}
 	default:
 		return cantConvert(fromID, toID)
 	}
@@ -501,6 +535,12 @@ func ObjectValue(id TypeID, value interface{}) (*api.Value, error) {
 			return def, errors.Errorf("Expected value of type password. Got : %v", value)
 		}
 		return &api.Value{Val: &api.Value_PasswordVal{PasswordVal: v}}, nil
+	case JSONID:
+		var v string
+		if v, ok = value.(string); !ok {
+			return def, errors.Errorf("Expected value of type json. Got : %v", value)
+		}
+		return &api.Value{Val: &api.Value_StrVal{StrVal: v}}, nil
 	default:
 		return def, errors.Errorf("ObjectValue not available for: %v", id)
 	}
@@ -535,6 +575,9 @@ func (v Val) MarshalJSON() ([]byte, error) {
 		return json.Marshal(v.Safe().(string))
 	case PasswordID:
 		return json.Marshal(v.Value.(string))
+	case JSONID:
+		// The document is emitted as it is.
+		return []byte(v.Value.(string)), nil
 	}
 	return nil, errors.Errorf("Invalid type for MarshalJSON: %v", v.Tid)
 }
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facets
+
+import (
+	"math"
+	"sort"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/types"
+)
+
+// The operators prefixed to the keys of the facets of a set mutation update the facets of the
+// edge instead of replacing them. Once any facet of an edge has an operator, the facets without
+// one are merged too.
+const (
+	// MergeOp sets the facet, keeping the other facets of the edge.
+	MergeOp = '&'
+	// IncrementOp adds the value of the facet to its current value, zero if it has none.
+	IncrementOp = '+'
+	// DeleteOp deletes the facet from the edge. Its value is ignored.
+	DeleteOp = '-'
+)
+
+// SplitOp returns the operator of the facet key, or 0 if it has none, and the key without it.
+func SplitOp(key string) (byte, string) {
+	if len(key) > 0 {
+		switch key[0] {
+		case MergeOp, IncrementOp, DeleteOp:
+			return key[0], key[1:]
+		}
+	}
+	return 0, key
+}
+
+// HasOps returns true if any of the facets has an operator.
+func HasOps(fs []*api.Facet) bool {
+	for _, f := range fs {
+		if op, _ := SplitOp(f.Key); op != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// ApplyOps returns the facets of an edge having the facets cur once the facets fs of a set
+// mutation are applied to them, sorted by key.
+func ApplyOps(cur, fs []*api.Facet) ([]*api.Facet, error) {
+	byKey := make(map[string]*api.Facet, len(cur)+len(fs))
+	for _, f := range cur {
+		byKey[f.Key] = f
+	}
+	for _, f := range fs {
+		op, key := SplitOp(f.Key)
+		switch op {
+		case DeleteOp:
+			delete(byKey, key)
+		case IncrementOp:
+			sum, err := increment(byKey[key], f, key)
+			if err != nil {
+				return nil, err
+			}
+			byKey[key] = sum
+		default:
+			nf := *f
+			nf.Key = key
+			byKey[key] = &nf
+		}
+	}
+
+	out := make([]*api.Facet, 0, len(byKey))
+	for _, f := range byKey {
+		out = append(out, f)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Key < out[j].Key
+	})
+	return out, nil
+}
+
+// increment returns the facet key with the value of cur incremented by the value of delta. The
+// sum of two ints is an int, and a float otherwise.
+func increment(cur, delta *api.Facet, key string) (*api.Facet, error) {
+	d, err := ValFor(delta)
+	if err != nil {
+		return nil, err
+	}
+	if d.Tid != types.IntID && d.Tid != types.FloatID {
+		return nil, errors.Errorf("Facet %s can only be incremented by an int or a float", key)
+	}
+	if cur == nil {
+		out := *delta
+		out.Key = key
+		return &out, nil
+	}
+	c, err := ValFor(cur)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case c.Tid == types.IntID && d.Tid == types.IntID:
+		x, y := c.Value.(int64), d.Value.(int64)
+		if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+			return nil, errors.Errorf("Incrementing facet %s overflows", key)
+		}
+		return ToBinary(key, x+y, api.Facet_INT)
+	case c.Tid == types.IntID || c.Tid == types.FloatID:
+		return ToBinary(key, toFloat(c)+toFloat(d), api.Facet_FLOAT)
+	default:
+		return nil, errors.Errorf("Facet %s of type %s can't be incremented", key, c.Tid.Name())
+	}
+}
+
+// toFloat returns the value of an int or a float as a float.
+func toFloat(v types.Val) float64 {
+	if v.Tid == types.IntID {
+		return float64(v.Value.(int64))
+	}
+	return v.Value.(float64)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package facets
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+)
+
+func facetsFor(t *testing.T, kvs ...string) []*api.Facet {
+	var fs []*api.Facet
+	for i := 0; i < len(kvs); i += 2 {
+		f, err := FacetFor(kvs[i], kvs[i+1])
+		require.NoError(t, err)
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+func values(t *testing.T, fs []*api.Facet) map[string]interface{} {
+	out := make(map[string]interface{})
+	for _, f := range fs {
+		v, err := ValFor(f)
+		require.NoError(t, err)
+		out[f.Key] = v.Value
+	}
+	return out
+}
+
+func TestApplyOps(t *testing.T) {
+	cur := facetsFor(t, "close", "true", "since", "2006", "visits", "2", "weight", "1")
+	fs, err := ApplyOps(cur, facetsFor(t, "+visits", "3", "+weight", "0.5", "-since", "",
+		"&note", `"met at work"`, "+likes", "1"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{"close": true, "likes": int64(1),
+		"note": "met at work", "visits": int64(5), "weight": 1.5}, values(t, fs))
+	for i := 1; i < len(fs); i++ {
+		require.True(t, fs[i-1].Key < fs[i].Key)
+	}
+
+	_, err = ApplyOps(cur, facetsFor(t, "+close", "1"))
+	require.Error(t, err)
+	_, err = ApplyOps(cur, facetsFor(t, "+visits", `"one"`))
+	require.Error(t, err)
+	_, err = ApplyOps(facetsFor(t, "visits", strconv.Itoa(math.MaxInt64)),
+		facetsFor(t, "+visits", "1"))
+	require.Error(t, err)
+}
+
+func TestSortAndValidateOps(t *testing.T) {
+	fs := facetsFor(t, "&b", "1", "+a", "1", "c", "1")
+	require.NoError(t, SortAndValidate(fs))
+	require.Equal(t, "+a", fs[0].Key)
+	require.Equal(t, "&b", fs[1].Key)
+
+	require.Error(t, SortAndValidate(facetsFor(t, "a", "1", "-a", "")))
+	require.Error(t, SortAndValidate(facetsFor(t, "+a", "true")))
+	require.Error(t, SortAndValidate(facetsFor(t, "+", "1")))
+}
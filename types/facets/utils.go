@@ -29,18 +29,30 @@ import (
 	"github.com/pkg/errors"
 )
 
-// SortAndValidate sorts And validates the facets.
+// SortAndValidate sorts And validates the facets. The facets are sorted by their keys without
+// their operators, which can only be given once for each key.
 func SortAndValidate(fs []*api.Facet) error {
 	if len(fs) == 0 {
 		return nil
 	}
 	sort.Slice(fs, func(i, j int) bool {
-		return fs[i].Key < fs[j].Key
+		_, ki := SplitOp(fs[i].Key)
+		_, kj := SplitOp(fs[j].Key)
+		return ki < kj
 	})
-	for i := 1; i < len(fs); i++ {
-		if fs[i-1].Key == fs[i].Key {
-			return errors.Errorf("Repeated keys are not allowed in facets. But got %s",
-				fs[i].Key)
+	for i, f := range fs {
+		op, key := SplitOp(f.Key)
+		switch {
+		case op != 0 && key == "":
+			return errors.Errorf("Empty facet key in %s", f.Key)
+		case op == IncrementOp && f.ValType != api.Facet_INT && f.ValType != api.Facet_FLOAT:
+			return errors.Errorf("Facet %s can only be incremented by an int or a float", key)
+		}
+		if i == 0 {
+			continue
+		}
+		if _, prev := SplitOp(fs[i-1].Key); prev == key {
+			return errors.Errorf("Repeated keys are not allowed in facets. But got %s", key)
 		}
 	}
 	return nil
@@ -192,3 +204,45 @@ func ValFor(f *api.Facet) (types.Val, error) {
 
 	return types.Convert(val, facetTid)
 }
+
+// ConvertTo converts the facet to the value of the given type, keeping its key. A string facet
+// gets the tokens of its value, as when it's parsed.
+func ConvertTo(f *api.Facet, tid types.TypeID) (*api.Facet, error) {
+	var vt api.Facet_ValType
+	switch tid {
+	case types.IntID:
+		vt = api.Facet_INT
+	case types.FloatID:
+		vt = api.Facet_FLOAT
+	case types.BoolID:
+		vt = api.Facet_BOOL
+	case types.DateTimeID:
+		vt = api.Facet_DATETIME
+	case types.StringID:
+		vt = api.Facet_STRING
+	default:
+		return nil, errors.Errorf("Invalid facet type: %s", tid.Name())
+	}
+	if f.ValType == vt {
+		return f, nil
+	}
+
+	from, err := TypeIDFor(f)
+	if err != nil {
+		return nil, err
+	}
+	// The value of the facet is in the binary form of its type.
+	v, err := types.Convert(types.Val{Tid: from, Value: f.Value}, tid)
+	if err != nil {
+		return nil, err
+	}
+	out, err := ToBinary(f.Key, v.Value, vt)
+	if err != nil {
+		return nil, err
+	}
+	if vt == api.Facet_STRING {
+		out.Tokens, err = tok.GetTermTokens([]string{v.Value.(string)})
+		sort.Strings(out.Tokens)
+	}
+	return out, err
+}
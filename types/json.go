@@ -0,0 +1,120 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// compactJSON validates a JSON document and removes its insignificant whitespace.
+func compactJSON(data []byte) (string, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, bytes.TrimSpace(data)); err != nil {
+		return "", errors.Wrapf(err, "Invalid JSON value")
+	}
+	return buf.String(), nil
+}
+
+// jsonPathStep is a member name or an array index of a path expression.
+type jsonPathStep struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// JSONPath selects a part of a JSON document, e.g. $.a.b or $.items[0]['first name'].
+type JSONPath struct {
+	steps []jsonPathStep
+}
+
+// ParseJSONPath parses a path expression. The path starts with $ followed by any number of
+// .member, ['member'] and [index] steps.
+func ParseJSONPath(path string) (*JSONPath, error) {
+	invalid := func(reason string) error {
+		return errors.Errorf("Invalid JSON path %q: %s", path, reason)
+	}
+	if !strings.HasPrefix(path, "$") {
+		return nil, invalid("it must start with $")
+	}
+	p := &JSONPath{}
+	for rest := path[1:]; len(rest) > 0; {
+		switch rest[0] {
+		case '.':
+			end := strings.IndexAny(rest[1:], ".[")
+			if end < 0 {
+				end = len(rest) - 1
+			}
+			key := rest[1 : end+1]
+			if key == "" {
+				return nil, invalid("empty member name")
+			}
+			p.steps = append(p.steps, jsonPathStep{key: key})
+			rest = rest[end+1:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, invalid("missing ]")
+			}
+			inner := rest[1:end]
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') &&
+				inner[len(inner)-1] == inner[0] {
+				p.steps = append(p.steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+			} else {
+				idx, err := strconv.Atoi(inner)
+				if err != nil || idx < 0 {
+					return nil, invalid("expected a quoted member name or an index in []")
+				}
+				p.steps = append(p.steps, jsonPathStep{index: idx, isIdx: true})
+			}
+			rest = rest[end+1:]
+		default:
+			return nil, invalid("expected . or [ after " + path[:len(path)-len(rest)])
+		}
+	}
+	return p, nil
+}
+
+// Select returns the part of the JSON document selected by the path, as it is in the document.
+// It returns false if the document doesn't have the path.
+func (p *JSONPath) Select(doc []byte) ([]byte, bool) {
+	cur := json.RawMessage(doc)
+	for _, step := range p.steps {
+		if step.isIdx {
+			var arr []json.RawMessage
+			if err := json.Unmarshal(cur, &arr); err != nil || step.index >= len(arr) {
+				return nil, false
+			}
+			cur = arr[step.index]
+			continue
+		}
+		var obj map[string]json.RawMessage
+		if err := json.Unmarshal(cur, &obj); err != nil {
+			return nil, false
+		}
+		next, ok := obj[step.key]
+		if !ok {
+			return nil, false
+		}
+		cur = next
+	}
+	return cur, true
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertJSON(t *testing.T) {
+	src := Val{Tid: StringID, Value: []byte(` {"a": {"b": [1, 2]}, "c": "d"} `)}
+	dst, err := Convert(src, JSONID)
+	require.NoError(t, err)
+	require.Equal(t, `{"a":{"b":[1,2]},"c":"d"}`, dst.Value)
+
+	// The document is stored and emitted as it is.
+	b := ValueForType(BinaryID)
+	require.NoError(t, Marshal(dst, &b))
+	back, err := Convert(Val{Tid: BinaryID, Value: b.Value}, JSONID)
+	require.NoError(t, err)
+	out, err := back.MarshalJSON()
+	require.NoError(t, err)
+	require.Equal(t, `{"a":{"b":[1,2]},"c":"d"}`, string(out))
+
+	_, err = Convert(Val{Tid: StringID, Value: []byte(`{"a": }`)}, JSONID)
+	require.Error(t, err)
+	_, err = Convert(Val{Tid: JSONID, Value: []byte(`1`)}, IntID)
+	require.Error(t, err)
+}
+
+func TestJSONPath(t *testing.T) {
+	doc := []byte(`{"a":{"b":[10,{"c":true}]},"first name":"Alice","n":null}`)
+	for path, expected := range map[string]string{
+		"$":               `{"a":{"b":[10,{"c":true}]},"first name":"Alice","n":null}`,
+		"$.a":             `{"b":[10,{"c":true}]}`,
+		"$.a.b[0]":        `10`,
+		"$.a.b[1].c":      `true`,
+		"$['first name']": `"Alice"`,
+		`$["a"]["b"][1]`:  `{"c":true}`,
+		"$.n":             `null`,
+	} {
+		p, err := ParseJSONPath(path)
+		require.NoError(t, err, path)
+		part, ok := p.Select(doc)
+		require.True(t, ok, path)
+		require.Equal(t, expected, string(part), path)
+	}
+
+	for _, path := range []string{"$.b", "$.a.b[2]", "$.a.b.c", "$['first name'][0]", "$.n.x"} {
+		p, err := ParseJSONPath(path)
+		require.NoError(t, err, path)
+		_, ok := p.Select(doc)
+		require.False(t, ok, path)
+	}
+
+	for _, path := range []string{"a.b", "$.", "$..a", "$[a]", "$[-1]", "$[0", "$a"} {
+		_, err := ParseJSONPath(path)
+		require.Error(t, err, path)
+	}
+}
@@ -17,22 +17,58 @@
 package types
 
 import (
-	"golang.org/x/crypto/bcrypt"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
 
+	"github.com/dgraph-io/dgraph/x"
 	"github.com/pkg/errors"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
 )
 
 const (
 	pwdLenLimit = 6
+
+	argon2Prefix  = "$argon2id$"
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+	argon2Threads = 4
+	// Defaults of the argon2id parameters, as recommended by golang.org/x/crypto/argon2.
+	argon2DefaultTime   = 1
+	argon2DefaultMemory = 64 * 1024
 )
 
-// Encrypt encrypts the given plain-text password.
+// Encrypt encrypts the given plain-text password, with bcrypt or argon2id depending on
+// x.Config.PasswordHash.
 func Encrypt(plain string) (string, error) {
 	if len(plain) < pwdLenLimit {
 		return "", errors.Errorf("Password too short, i.e. should have at least 6 chars")
 	}
+	if minEntropy := x.Config.PasswordMinEntropy; minEntropy > 0 {
+		if e := PasswordEntropy(plain); e < minEntropy {
+			return "", errors.Errorf("Password too weak, i.e. has an estimated entropy of "+
+				"%.0f bits, should have at least %.0f", e, minEntropy)
+		}
+	}
 
-	encrypted, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	switch x.Config.PasswordHash {
+	case "", "bcrypt":
+	case "argon2id":
+		return encryptArgon2(plain)
+	default:
+		return "", errors.Errorf("Unknown password hash: %s", x.Config.PasswordHash)
+	}
+
+	cost := x.Config.BcryptCost
+	if cost == 0 {
+		cost = bcrypt.DefaultCost
+	}
+	encrypted, err := bcrypt.GenerateFromPassword([]byte(plain), cost)
 	if err != nil {
 		return "", err
 	}
@@ -40,11 +76,99 @@ func Encrypt(plain string) (string, error) {
 	return string(encrypted), nil
 }
 
+func encryptArgon2(plain string) (string, error) {
+	time, memory := x.Config.Argon2Time, x.Config.Argon2Memory
+	if time == 0 {
+		time = argon2DefaultTime
+	}
+	if memory == 0 {
+		memory = argon2DefaultMemory
+	}
+	salt := make([]byte, argon2SaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return "", errors.Wrapf(err, "while generating password salt")
+	}
+	key := argon2.IDKey([]byte(plain), salt, time, memory, argon2Threads, argon2KeyLen)
+	// The encoding used by the reference implementation of argon2.
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2Prefix, argon2.Version, memory,
+		time, argon2Threads, base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+func verifyArgon2(plain, encrypted string) error {
+	var version int
+	var memory, time uint32
+	var threads uint8
+	parts := strings.Split(strings.TrimPrefix(encrypted, argon2Prefix), "$")
+	if len(parts) != 4 {
+		return errors.Errorf("Invalid argon2id crypted string")
+	}
+	if _, err := fmt.Sscanf(parts[0], "v=%d", &version); err != nil || version != argon2.Version {
+		return errors.Errorf("Unsupported argon2id version: %s", parts[0])
+	}
+	if _, err := fmt.Sscanf(parts[1], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return errors.Errorf("Invalid argon2id parameters: %s", parts[1])
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return errors.Errorf("Invalid argon2id salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[3])
+	if err != nil {
+		return errors.Errorf("Invalid argon2id key")
+	}
+
+	other := argon2.IDKey([]byte(plain), salt, time, memory, threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, other) != 1 {
+		return errors.Errorf("Password doesn't match")
+	}
+	return nil
+}
+
 // VerifyPassword checks that the plain-text password matches the encrypted password.
 func VerifyPassword(plain, encrypted string) error {
 	if len(plain) < pwdLenLimit || len(encrypted) == 0 {
 		return errors.Errorf("Invalid password/crypted string")
 	}
 
+	if strings.HasPrefix(encrypted, argon2Prefix) {
+		return verifyArgon2(plain, encrypted)
+	}
 	return bcrypt.CompareHashAndPassword([]byte(encrypted), []byte(plain))
 }
+
+// PasswordEntropy estimates the entropy of the password in bits, as its length times the
+// number of bits needed by the classes of characters it uses.
+func PasswordEntropy(plain string) float64 {
+	var lower, upper, digit, symbol, other bool
+	var n int
+	for _, r := range plain {
+		n++
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+
+	var pool float64
+	for _, c := range []struct {
+		used bool
+		size float64
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if c.used {
+			pool += c.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+	return float64(n) * math.Log2(pool)
+}
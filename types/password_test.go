@@ -16,7 +16,15 @@
 
 package types
 
-import "testing"
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+)
 
 func TestEncrypt(t *testing.T) {
 	tests := []struct {
@@ -147,3 +155,43 @@ func TestVerifyPassword(t *testing.T) {
 		})
 	}
 }
+
+func TestPasswordHashes(t *testing.T) {
+	defer func(cfg x.Options) { x.Config = cfg }(x.Config)
+
+	x.Config.PasswordHash = "bcrypt"
+	x.Config.BcryptCost = bcrypt.MinCost
+	encrypted, err := Encrypt("123456")
+	require.NoError(t, err)
+	cost, err := bcrypt.Cost([]byte(encrypted))
+	require.NoError(t, err)
+	require.Equal(t, bcrypt.MinCost, cost)
+	require.NoError(t, VerifyPassword("123456", encrypted))
+
+	x.Config.PasswordHash = "argon2id"
+	x.Config.Argon2Time = 2
+	x.Config.Argon2Memory = 1024
+	encrypted, err = Encrypt("123456")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(encrypted, "$argon2id$v=19$m=1024,t=2,p=4$"))
+	require.NoError(t, VerifyPassword("123456", encrypted))
+	require.Error(t, VerifyPassword("1234567", encrypted))
+	require.Error(t, VerifyPassword("123456", "$argon2id$v=19$m=1024"))
+
+	x.Config.PasswordHash = "md5"
+	_, err = Encrypt("123456")
+	require.Error(t, err)
+}
+
+func TestPasswordEntropy(t *testing.T) {
+	require.Equal(t, 0.0, PasswordEntropy(""))
+	require.InDelta(t, 6*math.Log2(10), PasswordEntropy("123456"), 1e-9)
+	require.InDelta(t, 8*math.Log2(26+26+10+33), PasswordEntropy("aB3$aB3$"), 1e-9)
+
+	defer func(cfg x.Options) { x.Config = cfg }(x.Config)
+	x.Config.PasswordMinEntropy = 40
+	_, err := Encrypt("password")
+	require.Error(t, err)
+	_, err = Encrypt("correct horse battery")
+	require.NoError(t, err)
+}
@@ -50,6 +50,8 @@ const (
 	PasswordID = TypeID(pb.Posting_PASSWORD)
 	// StringID represents the string type.
 	StringID = TypeID(pb.Posting_STRING)
+	// JSONID represents the type of small JSON documents.
+	JSONID = TypeID(pb.Posting_JSON)
 	// UndefinedID represents the undefined type.
 	UndefinedID = TypeID(100)
 )
@@ -65,6 +67,7 @@ var typeNameMap = map[string]TypeID{
 	"uid":      UidID,
 	"string":   StringID,
 	"password": PasswordID,
+	"json":     JSONID,
 }
 
 // TypeID represents the type of the data.
@@ -98,6 +101,8 @@ func (t TypeID) Name() string {
 		return "string"
 	case PasswordID:
 		return "password"
+	case JSONID:
+		return "json"
 	}
 	return ""
 }
@@ -182,6 +187,10 @@ func ValueForType(id TypeID) Val {
 		var p string
 		return Val{PasswordID, p}
 
+	case JSONID:
+		var j string
+		return Val{JSONID, j}
+
 	default:
 		return Val{}
 	}
@@ -20,6 +20,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/dgraph-io/dgraph/collate"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/x"
 	"github.com/pkg/errors"
@@ -30,6 +31,8 @@ type sortBase struct {
 	desc   []bool  // Sort orders for different values.
 	ul     *pb.List
 	o      []*pb.Facets
+	// Collations of the string values, if they aren't ordered bytewise.
+	collations []string
 }
 
 // Len returns size of vector.
@@ -47,7 +50,8 @@ func (s sortBase) Swap(i, j int) {
 
 type byValue struct{ sortBase }
 
-// Less compares two elements
+// Less compares two elements. The elements with equal values are ordered by uid, so that the
+// order doesn't depend on the order of the input, and pages of sorted results don't overlap.
 func (s byValue) Less(i, j int) bool {
 	first, second := s.values[i], s.values[j]
 	if len(first) == 0 || len(second) == 0 {
@@ -56,6 +60,9 @@ func (s byValue) Less(i, j int) bool {
 	for vidx := range first {
 		// Null value is considered greatest hence comes at first place while doing descending sort
 		// and at last place while doing ascending sort.
+		if first[vidx].Value == nil && second[vidx].Value == nil {
+			continue
+		}
 		if first[vidx].Value == nil {
 			return s.desc[vidx]
 		}
@@ -70,18 +77,37 @@ func (s byValue) Less(i, j int) bool {
 		}
 
 		// Its either less or greater.
-		less := less(first[vidx], second[vidx])
+		var collation string
+		if vidx < len(s.collations) {
+			collation = s.collations[vidx]
+		}
+		less := collatedLess(collation, first[vidx], second[vidx])
 		if s.desc[vidx] {
 			return !less
 		}
 		return less
 	}
-	return false
+	return s.ul.Uids[i] < s.ul.Uids[j]
 }
 
 // SortWithFacet sorts the given array in-place and considers the given facets to calculate
 // the proper ordering.
 func SortWithFacet(v [][]Val, ul *pb.List, l []*pb.Facets, desc []bool) error {
+	return sortValues(v, ul, l, desc, nil)
+}
+
+// Sort sorts the given array in-place.
+func Sort(v [][]Val, ul *pb.List, desc []bool) error {
+	return sortValues(v, ul, nil, desc, nil)
+}
+
+// SortCollated sorts the given array in-place. The strings of the i-th values are compared in
+// the order of the language collations[i], or bytewise if it's empty.
+func SortCollated(v [][]Val, ul *pb.List, desc []bool, collations []string) error {
+	return sortValues(v, ul, nil, desc, collations)
+}
+
+func sortValues(v [][]Val, ul *pb.List, l []*pb.Facets, desc []bool, collations []string) error {
 	if len(v) == 0 || len(v[0]) == 0 {
 		return nil
 	}
@@ -94,17 +120,12 @@ func SortWithFacet(v [][]Val, ul *pb.List, l []*pb.Facets, desc []bool) error {
 		return errors.Errorf("Value of type: %s isn't sortable", typ.Name())
 	}
 	var toBeSorted sort.Interface
-	b := sortBase{v, desc, ul, l}
+	b := sortBase{v, desc, ul, l, collations}
 	toBeSorted = byValue{b}
 	sort.Sort(toBeSorted)
 	return nil
 }
 
-// Sort sorts the given array in-place.
-func Sort(v [][]Val, ul *pb.List, desc []bool) error {
-	return SortWithFacet(v, ul, nil, desc)
-}
-
 // Less returns true if a is strictly less than b.
 func Less(a, b Val) (bool, error) {
 	if a.Tid != b.Tid {
@@ -139,6 +160,15 @@ func less(a, b Val) bool {
 	return false
 }
 
+// collatedLess is less, except that strings are compared in the order of the language
+// collation if it isn't empty.
+func collatedLess(collation string, a, b Val) bool {
+	if collation == "" || a.Tid != b.Tid || (a.Tid != StringID && a.Tid != DefaultID) {
+		return less(a, b)
+	}
+	return collate.Compare(collation, a.Safe().(string), b.Safe().(string)) < 0
+}
+
 func mismatchedLess(a, b Val) bool {
 	x.AssertTrue(a.Tid != b.Tid)
 	if (a.Tid != IntID && a.Tid != FloatID) || (b.Tid != IntID && b.Tid != FloatID) {
@@ -61,6 +61,21 @@ func TestSortStrings(t *testing.T) {
 		toString(t, list, StringID))
 }
 
+func TestSortStringsCollated(t *testing.T) {
+	list := getInput(t, StringID, []string{"Öl", "zebra", "apa", "Åsna"})
+	ul := getUIDList(4)
+	require.NoError(t, SortCollated(list, ul, []bool{false}, []string{"sv"}))
+	require.EqualValues(t, []uint64{300, 200, 400, 100}, ul.Uids)
+	require.EqualValues(t, []string{"apa", "zebra", "Åsna", "Öl"},
+		toString(t, list, StringID))
+
+	list = getInput(t, StringID, []string{"Öl", "zebra", "apa", "Åsna"})
+	ul = getUIDList(4)
+	require.NoError(t, SortCollated(list, ul, []bool{true}, []string{"en"}))
+	require.EqualValues(t, []string{"zebra", "Öl", "Åsna", "apa"},
+		toString(t, list, StringID))
+}
+
 func TestSortInts(t *testing.T) {
 	list := getInput(t, IntID, []string{"22", "111", "11", "212"})
 	ul := getUIDList(4)
@@ -122,6 +137,20 @@ func TestSortIntAndFloat(t *testing.T) {
 
 }
 
+func TestSortTiesByUid(t *testing.T) {
+	for _, desc := range []bool{false, true} {
+		list := getInput(t, IntID, []string{"2", "1", "2", "1", "2"})
+		list = append(list, []Val{{Tid: IntID}}, []Val{{Tid: IntID}})
+		ul := &pb.List{Uids: []uint64{500, 400, 100, 300, 200, 700, 600}}
+		require.NoError(t, Sort(list, ul, []bool{desc}))
+		if desc {
+			require.EqualValues(t, []uint64{600, 700, 100, 200, 500, 300, 400}, ul.Uids)
+		} else {
+			require.EqualValues(t, []uint64{300, 400, 100, 200, 500, 600, 700}, ul.Uids)
+		}
+	}
+}
+
 func TestEqual(t *testing.T) {
 	require.True(t, equal(Val{Tid: IntID, Value: int64(3)}, Val{Tid: IntID, Value: int64(3)}),
 		"equal should return true for two equal values")
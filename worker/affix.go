@@ -0,0 +1,139 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dgraph-io/badger"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// matchAffix returns true if val starts with affix for the prefix function, or ends with it for
+// the suffix function.
+func matchAffix(fname, affix, val string) bool {
+	if fname == "prefix" {
+		return strings.HasPrefix(val, affix)
+	}
+	return strings.HasSuffix(val, affix)
+}
+
+// hasExactIndex returns true if the exact index of attr holds the values as they are, which
+// isn't the case when the predicate has a collation.
+func hasExactIndex(attr string) bool {
+	return schema.State().HasTokenizer(tok.IdentExact, attr) && schema.State().Collation(attr) == ""
+}
+
+// uidsForAffix collects a list of uids whose values might start or end with the affix. The
+// prefix function scans the exact index keys starting with the prefix. Otherwise, the uids having
+// all the trigrams of the affix are used, which requires at least 3 characters, else all the
+// exact index keys are scanned. matchAffix does the actual match.
+func uidsForAffix(ctx context.Context, attr string, arg funcArgs) (*pb.List, error) {
+	affix := arg.srcFn.affix
+	useTrigram := schema.State().HasTokenizer(tok.IdentTrigram, attr) &&
+		utf8.RuneCountInString(affix) >= 3
+	useExact := hasExactIndex(attr)
+	switch {
+	case useExact && (arg.srcFn.fname == "prefix" || !useTrigram):
+		return uidsForExactAffix(ctx, attr, arg)
+	case useTrigram:
+		return uidsForTrigrams(ctx, attr, arg)
+	}
+	return nil, errors.Errorf("Attribute %v does not have an exact index, or a trigram index "+
+		"with at least 3 characters, for %s matching. Please add an index or use has/uid "+
+		"function with %s() as filter.", attr, arg.srcFn.fname, arg.srcFn.fname)
+}
+
+func uidsForExactAffix(ctx context.Context, attr string, arg funcArgs) (*pb.List, error) {
+	ident := string(tok.ExactTokenizer{}.Identifier())
+	prefix := x.IndexKey(attr, ident)
+	if arg.srcFn.fname == "prefix" {
+		prefix = x.IndexKey(attr, ident+arg.srcFn.affix)
+	}
+	iterOpt := badger.DefaultIteratorOptions
+	iterOpt.PrefetchValues = false
+	iterOpt.Prefix = prefix
+	txn := pstore.NewTransactionAt(arg.q.ReadTs, false)
+	defer txn.Discard()
+	itr := txn.NewIterator(iterOpt)
+	defer itr.Close()
+
+	opts := posting.ListOptions{ReadTs: arg.q.ReadTs}
+	var uidMatrix []*pb.List
+	for itr.Seek(prefix); itr.Valid(); itr.Next() {
+		k := x.Parse(itr.Item().Key())
+		if k == nil || !k.IsIndex() {
+			continue
+		}
+		// The term starts with the tokenizer identifier.
+		if arg.srcFn.fname == "suffix" && !strings.HasSuffix(k.Term[1:], arg.srcFn.affix) {
+			continue
+		}
+		pl, err := posting.GetNoStore(x.IndexKey(attr, k.Term))
+		if err != nil {
+			return nil, err
+		}
+		countRead(ctx, pl)
+		uids, err := pl.Uids(opts)
+		if err != nil {
+			return nil, err
+		}
+		uidMatrix = append(uidMatrix, uids)
+	}
+	return algo.MergeSorted(uidMatrix), nil
+}
+
+func uidsForTrigrams(ctx context.Context, attr string, arg funcArgs) (*pb.List, error) {
+	trigrams, err := tok.GetTokens(tok.IdentTrigram, arg.srcFn.affix)
+	if err != nil {
+		return nil, err
+	}
+	opts := posting.ListOptions{ReadTs: arg.q.ReadTs}
+	var results *pb.List
+	for _, t := range trigrams {
+		pl, err := posting.GetNoStore(x.IndexKey(attr, t))
+		if err != nil {
+			return nil, err
+		}
+		countRead(ctx, pl)
+		uids, err := pl.Uids(opts)
+		if err != nil {
+			return nil, err
+		}
+		if results == nil {
+			results = uids
+		} else {
+			algo.IntersectWith(results, uids, results)
+		}
+		if results.Size() == 0 {
+			break
+		}
+	}
+	if results == nil {
+		results = &pb.List{}
+	}
+	return results, nil
+}
@@ -0,0 +1,53 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+)
+
+func TestMatchAffix(t *testing.T) {
+	require.True(t, matchAffix("prefix", "ali", "alice"))
+	require.False(t, matchAffix("prefix", "ice", "alice"))
+	require.True(t, matchAffix("suffix", "@dgraph.io", "alice@dgraph.io"))
+	require.False(t, matchAffix("suffix", "@dgraph.io", "alice@dgraph.io.evil"))
+}
+
+func TestParseAffixFunction(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte("email: string @index(trigram) ."), 1))
+	q := &pb.Query{Attr: "email", SrcFunc: &pb.SrcFunction{Name: "suffix",
+		Args: []string{"@dgraph.io"}}}
+	fc, err := parseSrcFn(q)
+	require.NoError(t, err)
+	require.Equal(t, affixFn, fc.fnType)
+	require.Equal(t, "@dgraph.io", fc.affix)
+
+	q.SrcFunc.Args = []string{""}
+	_, err = parseSrcFn(q)
+	require.Error(t, err)
+
+	// Without an exact index, the trigram index requires 3 characters.
+	_, err = uidsForAffix(context.Background(), "email", funcArgs{q: q, srcFn: &functionContext{fname: "prefix",
+		affix: "al"}})
+	require.Error(t, err)
+}
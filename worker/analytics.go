@@ -0,0 +1,337 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"math/rand"
+	"sort"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// AnalyticsGraph is the undirected graph of the uid edges of some predicates, loaded in memory
+// to run the graph algorithms on it. The nodes are numbered in the order of their uids, and the
+// graph takes 4 bytes per node and 8 bytes per edge.
+type AnalyticsGraph struct {
+	Uids []uint64
+	// The neighbours of node i are adj[offsets[i]:offsets[i+1]], sorted, without the node
+	// itself.
+	offsets []uint32
+	adj     []uint32
+}
+
+// LoadAnalyticsGraph reads the edges of the uid predicates at readTs from the groups serving
+// them, a batch of subjects at a time. It fails if there are more than maxEdges edges, to bound
+// the memory used. loaded is called with the number of edges read so far.
+func LoadAnalyticsGraph(ctx context.Context, preds []string, readTs, maxEdges uint64,
+	loaded func(edges uint64)) (*AnalyticsGraph, error) {
+	schema, err := GetSchemaOverNetwork(ctx, &pb.SchemaRequest{
+		Predicates: preds,
+		Fields:     []string{"type"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	types := make(map[string]string)
+	for _, s := range schema {
+		types[s.Predicate] = s.Type
+	}
+
+	var from, to []uint64
+	for _, pred := range preds {
+		if types[pred] != "uid" {
+			return nil, errors.Errorf("Predicate %s isn't a uid predicate", pred)
+		}
+		subjects, err := hasUids(ctx, pred, readTs)
+		if err != nil {
+			return nil, err
+		}
+		err = forEachUidEdges(ctx, pred, subjects, readTs,
+			func(subjects *pb.List, objects []*pb.List) error {
+				for i, uid := range subjects.Uids {
+					for _, obj := range objects[i].Uids {
+						from = append(from, uid)
+						to = append(to, obj)
+					}
+				}
+				if uint64(len(from)) > maxEdges {
+					return errors.Errorf("The predicates have more than %d edges", maxEdges)
+				}
+				loaded(uint64(len(from)))
+				return nil
+			})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return newAnalyticsGraph(from, to), nil
+}
+
+// newAnalyticsGraph returns the undirected graph of the edges from[i] -> to[i].
+func newAnalyticsGraph(from, to []uint64) *AnalyticsGraph {
+	uids := make([]uint64, 0, 2*len(from))
+	uids = append(append(uids, from...), to...)
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+	n := 0
+	for i, uid := range uids {
+		if i == 0 || uid != uids[n-1] {
+			uids[n] = uid
+			n++
+		}
+	}
+	g := &AnalyticsGraph{Uids: uids[:n:n], offsets: make([]uint32, n+1)}
+
+	// The uids are mapped to their node by binary search, which saves a map.
+	node := func(uid uint64) uint32 {
+		return uint32(sort.Search(n, func(i int) bool { return g.Uids[i] >= uid }))
+	}
+	for i := range from {
+		if from[i] == to[i] {
+			continue
+		}
+		g.offsets[node(from[i])+1]++
+		g.offsets[node(to[i])+1]++
+	}
+	for i := 1; i <= n; i++ {
+		g.offsets[i] += g.offsets[i-1]
+	}
+	g.adj = make([]uint32, g.offsets[n])
+	next := append([]uint32{}, g.offsets[:n]...)
+	for i := range from {
+		if from[i] == to[i] {
+			continue
+		}
+		u, v := node(from[i]), node(to[i])
+		g.adj[next[u]] = v
+		next[u]++
+		g.adj[next[v]] = u
+		next[v]++
+	}
+
+	// The edges repeated in both directions, or by several predicates, are only kept once.
+	var end uint32
+	for i := 0; i < n; i++ {
+		nbrs := g.adj[g.offsets[i]:g.offsets[i+1]]
+		sort.Slice(nbrs, func(a, b int) bool { return nbrs[a] < nbrs[b] })
+		g.offsets[i] = end
+		for j, v := range nbrs {
+			if j == 0 || v != nbrs[j-1] {
+				g.adj[end] = v
+				end++
+			}
+		}
+	}
+	g.offsets[n] = end
+	g.adj = g.adj[:end:end]
+	return g
+}
+
+// NumEdges returns the number of undirected edges of the graph.
+func (g *AnalyticsGraph) NumEdges() uint64 {
+	return uint64(len(g.adj) / 2)
+}
+
+func (g *AnalyticsGraph) neighbours(u uint32) []uint32 {
+	return g.adj[g.offsets[u]:g.offsets[u+1]]
+}
+
+// sources returns the nodes from which the shortest paths are computed: all of them, or samples
+// of them picked at random if samples is positive.
+func (g *AnalyticsGraph) sources(samples int, seed int64) []uint32 {
+	n := len(g.Uids)
+	if samples <= 0 || samples >= n {
+		all := make([]uint32, n)
+		for i := range all {
+			all[i] = uint32(i)
+		}
+		return all
+	}
+	perm := rand.New(rand.NewSource(seed)).Perm(n)[:samples]
+	sources := make([]uint32, samples)
+	for i, p := range perm {
+		sources[i] = uint32(p)
+	}
+	return sources
+}
+
+// Betweenness returns the betweenness centrality of the nodes: the number of shortest paths
+// between two other nodes going through them, with Brandes' algorithm. If samples is positive,
+// it's estimated from the shortest paths from as many random sources. done is called after each
+// source, out of the number of sources.
+func (g *AnalyticsGraph) Betweenness(ctx context.Context, samples int, seed int64,
+	done func(done, total uint64)) ([]float64, error) {
+	n := len(g.Uids)
+	sources := g.sources(samples, seed)
+	bc := make([]float64, n)
+	sigma := make([]float64, n)
+	delta := make([]float64, n)
+	dist := make([]int32, n)
+	order := make([]uint32, 0, n)
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	for i, s := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		order = order[:0]
+		sigma[s], dist[s] = 1, 0
+		order = append(order, s)
+		for head := 0; head < len(order); head++ {
+			u := order[head]
+			for _, v := range g.neighbours(u) {
+				if dist[v] < 0 {
+					dist[v] = dist[u] + 1
+					order = append(order, v)
+				}
+				if dist[v] == dist[u]+1 {
+					sigma[v] += sigma[u]
+				}
+			}
+		}
+		// The dependencies are accumulated from the farthest nodes back to the source.
+		for j := len(order) - 1; j >= 0; j-- {
+			w := order[j]
+			for _, v := range g.neighbours(w) {
+				if dist[v] == dist[w]-1 {
+					delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+				}
+			}
+			if w != s {
+				bc[w] += delta[w]
+			}
+		}
+		for _, u := range order {
+			sigma[u], delta[u], dist[u] = 0, 0, -1
+		}
+		done(uint64(i+1), uint64(len(sources)))
+	}
+
+	// Each path is found from both of its ends.
+	scale := 0.5 * float64(n) / float64(len(sources))
+	for i := range bc {
+		bc[i] *= scale
+	}
+	return bc, nil
+}
+
+// Closeness returns the closeness centrality of the nodes, as the inverse of their average
+// distance to the nodes they reach, times the fraction of the nodes they reach, so that it's
+// comparable between the components of the graph. If samples is positive, it's estimated from
+// the distances from as many random sources. done is called after each source, out of the number
+// of sources.
+func (g *AnalyticsGraph) Closeness(ctx context.Context, samples int, seed int64,
+	done func(done, total uint64)) ([]float64, error) {
+	n := len(g.Uids)
+	sources := g.sources(samples, seed)
+	isSource := make([]bool, n)
+	for _, s := range sources {
+		isSource[s] = true
+	}
+	// reached are the number of sources reaching each node, and sum the sum of their distances.
+	reached := make([]uint32, n)
+	sum := make([]uint64, n)
+	dist := make([]int32, n)
+	order := make([]uint32, 0, n)
+	for i := range dist {
+		dist[i] = -1
+	}
+
+	for i, s := range sources {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		order = append(order[:0], s)
+		dist[s] = 0
+		for head := 0; head < len(order); head++ {
+			u := order[head]
+			if u != s {
+				reached[u]++
+				sum[u] += uint64(dist[u])
+			}
+			for _, v := range g.neighbours(u) {
+				if dist[v] < 0 {
+					dist[v] = dist[u] + 1
+					order = append(order, v)
+				}
+			}
+		}
+		for _, u := range order {
+			dist[u] = -1
+		}
+		done(uint64(i+1), uint64(len(sources)))
+	}
+
+	cc := make([]float64, n)
+	for u := range cc {
+		others := len(sources)
+		if isSource[u] {
+			others--
+		}
+		if reached[u] == 0 || others == 0 {
+			continue
+		}
+		r := float64(reached[u])
+		cc[u] = r / float64(others) * r / float64(sum[u])
+	}
+	return cc, nil
+}
+
+// Triangles returns the number of triangles each node is part of. done is called after each
+// node, out of the number of nodes.
+func (g *AnalyticsGraph) Triangles(ctx context.Context,
+	done func(done, total uint64)) ([]uint64, error) {
+	n := len(g.Uids)
+	counts := make([]uint64, n)
+	for u := 0; u < n; u++ {
+		if u%1000 == 0 {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+		}
+		// Each triangle u < v < w is counted once, from its smallest node.
+		nu := g.neighbours(uint32(u))
+		for _, v := range nu {
+			if v <= uint32(u) {
+				continue
+			}
+			nv := g.neighbours(v)
+			for i, j := 0, 0; i < len(nu) && j < len(nv); {
+				switch {
+				case nu[i] < nv[j]:
+					i++
+				case nu[i] > nv[j]:
+					j++
+				default:
+					if w := nu[i]; w > v {
+						counts[u]++
+						counts[v]++
+						counts[w]++
+					}
+					i++
+					j++
+				}
+			}
+		}
+		done(uint64(u+1), uint64(n))
+	}
+	return counts, nil
+}
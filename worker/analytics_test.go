@@ -0,0 +1,117 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// testAnalyticsGraph is the path 1-2-3 and the triangle 3-4-5, with an edge repeated in the
+// other direction and a loop.
+func testAnalyticsGraph() *AnalyticsGraph {
+	return newAnalyticsGraph(
+		[]uint64{1, 2, 3, 4, 5, 2, 1},
+		[]uint64{2, 3, 4, 5, 3, 1, 1})
+}
+
+func TestAnalyticsGraph(t *testing.T) {
+	g := testAnalyticsGraph()
+	require.Equal(t, []uint64{1, 2, 3, 4, 5}, g.Uids)
+	require.Equal(t, uint64(5), g.NumEdges())
+	require.Equal(t, []uint32{0, 2}, g.neighbours(1))
+	require.Equal(t, []uint32{1, 3, 4}, g.neighbours(2))
+}
+
+func TestCentrality(t *testing.T) {
+	g := testAnalyticsGraph()
+	var steps uint64
+	bc, err := g.Betweenness(context.Background(), 0, 0, func(done, total uint64) {
+		require.Equal(t, uint64(5), total)
+		steps = done
+	})
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), steps)
+	require.InDeltaSlice(t, []float64{0, 3, 4, 0, 0}, bc, 1e-9)
+
+	cc, err := g.Closeness(context.Background(), 0, 0, func(done, total uint64) {})
+	require.NoError(t, err)
+	require.InDeltaSlice(t, []float64{4.0 / 9, 4.0 / 6, 4.0 / 5, 4.0 / 7, 4.0 / 7}, cc, 1e-9)
+
+	// The scores of a sample are estimates.
+	bc, err = g.Betweenness(context.Background(), 2, 1, func(done, total uint64) {
+		require.Equal(t, uint64(2), total)
+	})
+	require.NoError(t, err)
+	require.Len(t, bc, 5)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = g.Closeness(ctx, 0, 0, func(done, total uint64) {})
+	require.Error(t, err)
+}
+
+func TestTriangles(t *testing.T) {
+	g := testAnalyticsGraph()
+	counts, err := g.Triangles(context.Background(), func(done, total uint64) {})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0, 0, 1, 1, 1}, counts)
+}
+
+// testCommunities are two cliques of four nodes, 1 to 4 and 5 to 8, joined by the edge 4-5.
+func testCommunities() *AnalyticsGraph {
+	var from, to []uint64
+	for _, clique := range [][]uint64{{1, 2, 3, 4}, {5, 6, 7, 8}} {
+		for i, u := range clique {
+			for _, v := range clique[i+1:] {
+				from, to = append(from, u), append(to, v)
+			}
+		}
+	}
+	return newAnalyticsGraph(append(from, 4), append(to, 5))
+}
+
+func TestCommunities(t *testing.T) {
+	g := testCommunities()
+	expected := []uint64{1, 1, 1, 1, 5, 5, 5, 5}
+	for seed := int64(0); seed < 5; seed++ {
+		var passes uint64
+		communities, err := g.Louvain(context.Background(), 20, seed, func(done, total uint64) {
+			require.Equal(t, uint64(20), total)
+			passes = done
+		})
+		require.NoError(t, err)
+		require.Equal(t, expected, communities, "seed %d", seed)
+		require.True(t, passes < 20)
+
+		communities, err = g.LabelPropagation(context.Background(), 20, seed,
+			func(done, total uint64) {})
+		require.NoError(t, err)
+		require.Equal(t, expected, communities, "seed %d", seed)
+	}
+
+	// 12 of the 13 edges are within the communities, and each has half of the degrees.
+	require.InDelta(t, 12.0/13-0.5, g.Modularity(expected), 1e-9)
+	require.InDelta(t, 0, g.Modularity(make([]uint64, 8)), 1e-9)
+
+	// Without any pass, each node is its own community.
+	communities, err := g.Louvain(context.Background(), 0, 0, func(done, total uint64) {})
+	require.NoError(t, err)
+	require.Equal(t, g.Uids, communities)
+}
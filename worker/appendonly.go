@@ -0,0 +1,124 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// splitAppendOnly moves the edges of the predicates with the @appendonly directive out of the
+// mutation, into a mutation which is committed as soon as it's applied instead of being part of
+// the transaction. It returns nil if the mutation has no such edges.
+func splitAppendOnly(m *pb.Mutations) (*pb.Mutations, error) {
+	var edges, rest []*pb.DirectedEdge
+	for _, edge := range m.Edges {
+		if isDeletePredicateEdge(edge) || !schema.State().IsAppendOnly(edge.Attr) {
+			rest = append(rest, edge)
+			continue
+		}
+		if edge.Op != pb.DirectedEdge_SET {
+			return nil, errors.Errorf("Predicate %s is append-only, values can only be added to it",
+				edge.Attr)
+		}
+		edges = append(edges, edge)
+	}
+	if len(edges) == 0 {
+		return nil, nil
+	}
+	m.Edges = rest
+	return &pb.Mutations{GroupId: m.GroupId, StartTs: m.StartTs, Edges: edges, AppendOnly: true},
+		nil
+}
+
+// isEmptyMutation returns whether the mutation has nothing left to apply.
+func isEmptyMutation(m *pb.Mutations) bool {
+	return len(m.Edges) == 0 && len(m.Schema) == 0 && len(m.Types) == 0 &&
+		m.DropOp == pb.Mutations_NONE
+}
+
+// errStaleCommitTs is returned for an append-only mutation applied after a commit at a higher
+// version, as a rollup at that version would hide its edges.
+var errStaleCommitTs = errors.New("A commit at a higher version was applied before the " +
+	"append-only mutation")
+
+// proposeAppendOnly proposes the append-only mutation at a version assigned by Zero, so that
+// all the replicas commit its edges at the same version, also when the Raft logs are replayed.
+// It's proposed again at a new version if a commit at a higher version was applied before it.
+func proposeAppendOnly(ctx context.Context, m *pb.Mutations) error {
+	for i := 0; ; i++ {
+		ts, err := Timestamps(ctx, &pb.Num{Val: 1})
+		if err != nil {
+			return err
+		}
+		m.CommitTs = ts.StartId
+		err = groups().Node.proposeAndWait(ctx, &pb.Proposal{Mutations: m})
+		if err != errStaleCommitTs || i >= x.WorkerConfig.MaxRetries {
+			return err
+		}
+	}
+}
+
+// applyAppendOnly applies the edges of an append-only mutation, and commits them to disk right
+// away at the version of the mutation, without going through Zero. Zero assigns the version
+// when the mutation is proposed, so two mutations never share it. Only append-only mutations
+// write to these predicates, so a transaction can't use the same version either. The edges
+// become visible once the max assigned timestamp reaches their version.
+func (n *node) applyAppendOnly(ctx context.Context, m *pb.Mutations) error {
+	commitTs := m.CommitTs
+	if commitTs == 0 {
+		return errors.New("Append-only mutation without a commit ts")
+	}
+	// The rollups are done at the version of the commits applied before, which must not be
+	// higher than the version of the edges. The check gives the same result on all replicas,
+	// as they apply the same commits in the same order.
+	if commitTs < atomic.LoadUint64(&n.maxCommitTs) {
+		return errStaleCommitTs
+	}
+
+	// The txn isn't registered with the Oracle, as it's never part of a delta from Zero.
+	txn := posting.NewTxn(commitTs)
+	for _, edge := range m.Edges {
+		if edge.Op != pb.DirectedEdge_SET || !schema.State().IsAppendOnly(edge.Attr) {
+			return errors.Errorf("Predicate %s isn't append-only", edge.Attr)
+		}
+		for {
+			err := runMutation(ctx, edge, txn)
+			if err == nil {
+				break
+			}
+			if err != posting.ErrRetry {
+				return err
+			}
+		}
+	}
+	txn.Update()
+	posting.NoteCommit(txn.Predicates(), commitTs)
+
+	writer := posting.NewTxnWriter(pstore)
+	if err := txn.CommitToDisk(writer, commitTs); err != nil {
+		return err
+	}
+	return writer.Flush()
+}
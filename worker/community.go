@@ -0,0 +1,285 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"math/rand"
+
+	"golang.org/x/net/context"
+)
+
+// communityIds returns the community of each node as the smallest uid of its community, given
+// the label of the community of each node.
+func (g *AnalyticsGraph) communityIds(labels []uint32) []uint64 {
+	// The nodes are in the order of their uids, so the first node of a label has its smallest uid.
+	first := make(map[uint32]uint64)
+	ids := make([]uint64, len(labels))
+	for u, l := range labels {
+		if _, ok := first[l]; !ok {
+			first[l] = g.Uids[u]
+		}
+		ids[u] = first[l]
+	}
+	return ids
+}
+
+// shuffled returns the nodes in a random order.
+func shuffled(n int, rng *rand.Rand) []uint32 {
+	order := make([]uint32, n)
+	for i, p := range rng.Perm(n) {
+		order[i] = uint32(p)
+	}
+	return order
+}
+
+// LabelPropagation returns the community of each node, as the smallest uid of its community.
+// Each node starts in its own community, and then joins the community most of its neighbours
+// are in, until no node moves or after iterations passes over the nodes, which are visited in a
+// random order picked with seed. done is called after each pass, out of iterations.
+func (g *AnalyticsGraph) LabelPropagation(ctx context.Context, iterations int, seed int64,
+	done func(done, total uint64)) ([]uint64, error) {
+	n := len(g.Uids)
+	labels := make([]uint32, n)
+	for i := range labels {
+		labels[i] = uint32(i)
+	}
+	rng := rand.New(rand.NewSource(seed))
+	counts := make([]uint32, n)
+	var touched []uint32
+
+	for it := 0; it < iterations; it++ {
+		var moved bool
+		for i, u := range shuffled(n, rng) {
+			if i%1000 == 0 {
+				if err := ctx.Err(); err != nil {
+					return nil, err
+				}
+			}
+			touched = touched[:0]
+			for _, v := range g.neighbours(u) {
+				if counts[labels[v]] == 0 {
+					touched = append(touched, labels[v])
+				}
+				counts[labels[v]]++
+			}
+			// The node stays in its community unless another one has more of its neighbours,
+			// and the ties are broken by the smallest label, so that the passes converge.
+			best, bestCount := labels[u], counts[labels[u]]
+			for _, l := range touched {
+				if c := counts[l]; c > bestCount || c == bestCount && best != labels[u] && l < best {
+					best, bestCount = l, c
+				}
+				counts[l] = 0
+			}
+			if best != labels[u] {
+				labels[u] = best
+				moved = true
+			}
+		}
+		done(uint64(it+1), uint64(iterations))
+		if !moved {
+			break
+		}
+	}
+	return g.communityIds(labels), nil
+}
+
+// louvainLevel is the weighted graph of a level of the Louvain method, whose nodes are the
+// communities of the level below.
+type louvainLevel struct {
+	offsets []uint32
+	adj     []uint32
+	weights []float64
+	// degree is the sum of the weights of the edges of the nodes of the community, including
+	// the ones within it.
+	degree []float64
+}
+
+func (l *louvainLevel) size() int {
+	return len(l.degree)
+}
+
+// Louvain returns the community of each node, as the smallest uid of its community, with the
+// Louvain method: the nodes move to the community of a neighbour which increases the modularity
+// the most, until none moves, and then the communities are merged into the nodes of the next
+// level. It stops when no node moves, or after iterations passes over the nodes of all the
+// levels, which are visited in a random order picked with seed. done is called after each pass,
+// out of iterations.
+func (g *AnalyticsGraph) Louvain(ctx context.Context, iterations int, seed int64,
+	done func(done, total uint64)) ([]uint64, error) {
+	n := len(g.Uids)
+	level := &louvainLevel{
+		offsets: g.offsets,
+		adj:     g.adj,
+		weights: make([]float64, len(g.adj)),
+		degree:  make([]float64, n),
+	}
+	for u := 0; u < n; u++ {
+		level.degree[u] = float64(g.offsets[u+1] - g.offsets[u])
+	}
+	for i := range level.weights {
+		level.weights[i] = 1
+	}
+	// twoM is twice the sum of the weights of the edges, which the levels keep.
+	twoM := float64(len(g.adj))
+
+	// labels is the community of each node of the graph, among the nodes of the current level.
+	labels := make([]uint32, n)
+	for i := range labels {
+		labels[i] = uint32(i)
+	}
+	rng := rand.New(rand.NewSource(seed))
+	var passes int
+	for passes < iterations && twoM > 0 {
+		size := level.size()
+		comm := make([]uint32, size)
+		tot := make([]float64, size)
+		for c := range comm {
+			comm[c] = uint32(c)
+			tot[c] = level.degree[c]
+		}
+		links := make([]float64, size)
+		var touched []uint32
+
+		var levelMoved bool
+		for passes < iterations {
+			var moved bool
+			for i, u := range shuffled(size, rng) {
+				if i%1000 == 0 {
+					if err := ctx.Err(); err != nil {
+						return nil, err
+					}
+				}
+				touched = touched[:0]
+				for k := level.offsets[u]; k < level.offsets[u+1]; k++ {
+					c := comm[level.adj[k]]
+					if links[c] == 0 {
+						touched = append(touched, c)
+					}
+					links[c] += level.weights[k]
+				}
+				// The gain of joining community c is links[c] - tot[c]*degree/twoM, up to
+				// terms which are the same for all of them.
+				cu, degree := comm[u], level.degree[u]
+				tot[cu] -= degree
+				best := cu
+				bestGain := links[cu] - tot[cu]*degree/twoM
+				for _, c := range touched {
+					if gain := links[c] - tot[c]*degree/twoM; gain > bestGain+1e-12 {
+						best, bestGain = c, gain
+					}
+				}
+				for _, c := range touched {
+					links[c] = 0
+				}
+				tot[best] += degree
+				if best != cu {
+					comm[u] = best
+					moved = true
+				}
+			}
+			passes++
+			done(uint64(passes), uint64(iterations))
+			if !moved {
+				break
+			}
+			levelMoved = true
+		}
+		if !levelMoved {
+			break
+		}
+
+		// The communities are numbered, and become the nodes of the next level.
+		ids := make([]uint32, size)
+		for i := range ids {
+			ids[i] = ^uint32(0)
+		}
+		var next uint32
+		for u := 0; u < size; u++ {
+			if ids[comm[u]] == ^uint32(0) {
+				ids[comm[u]] = next
+				next++
+			}
+		}
+		for i, l := range labels {
+			labels[i] = ids[comm[l]]
+		}
+		level = level.merge(comm, ids, int(next))
+	}
+	return g.communityIds(labels), nil
+}
+
+// merge returns the level whose nodes are the communities of the nodes of l. The edges within a
+// community are dropped, as they only count in its degree.
+func (l *louvainLevel) merge(comm, ids []uint32, size int) *louvainLevel {
+	members := make([][]uint32, size)
+	next := &louvainLevel{offsets: make([]uint32, size+1), degree: make([]float64, size)}
+	for u := 0; u < l.size(); u++ {
+		c := ids[comm[u]]
+		members[c] = append(members[c], uint32(u))
+		next.degree[c] += l.degree[u]
+	}
+	links := make([]float64, size)
+	var touched []uint32
+	for c, nodes := range members {
+		touched = touched[:0]
+		for _, u := range nodes {
+			for k := l.offsets[u]; k < l.offsets[u+1]; k++ {
+				d := ids[comm[l.adj[k]]]
+				if d == uint32(c) {
+					continue
+				}
+				if links[d] == 0 {
+					touched = append(touched, d)
+				}
+				links[d] += l.weights[k]
+			}
+		}
+		for _, d := range touched {
+			next.adj = append(next.adj, d)
+			next.weights = append(next.weights, links[d])
+			links[d] = 0
+		}
+		next.offsets[c+1] = uint32(len(next.adj))
+	}
+	return next
+}
+
+// Modularity returns the modularity of the communities of the nodes: the fraction of the edges
+// within the communities, minus the one expected if the edges were placed at random.
+func (g *AnalyticsGraph) Modularity(communities []uint64) float64 {
+	twoM := float64(len(g.adj))
+	if twoM == 0 {
+		return 0
+	}
+	internal := make(map[uint64]float64)
+	tot := make(map[uint64]float64)
+	for u := range g.Uids {
+		c := communities[u]
+		tot[c] += float64(g.offsets[u+1] - g.offsets[u])
+		for _, v := range g.neighbours(uint32(u)) {
+			if communities[v] == c {
+				internal[c]++
+			}
+		}
+	}
+	var q float64
+	for c, t := range tot {
+		q += internal[c]/twoM - (t/twoM)*(t/twoM)
+	}
+	return q
+}
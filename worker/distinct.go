@@ -0,0 +1,166 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"github.com/dgraph-io/badger"
+	"github.com/pkg/errors"
+	otrace "go.opencensus.io/trace"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// distinctTokenizer returns the index the distinct values of the predicate are read from. The
+// tokens of the exact, xid, int and bool indices are the values. Each token of the hash and
+// exact_ci indices stands for a single value too, which is read from a node having it.
+func distinctTokenizer(attr string) (tok.Tokenizer, error) {
+	var found tok.Tokenizer
+	for _, t := range schema.State().Tokenizer(attr) {
+		switch t.Identifier() {
+		case tok.IdentExact, tok.IdentXid, tok.IdentInt, tok.IdentBool:
+			return t, nil
+		case tok.IdentHash, tok.IdentExactCI:
+			found = t
+		}
+	}
+	if found == nil {
+		return nil, errors.Errorf("Predicate %s needs an exact, hash, exact_ci, int or bool"+
+			" index for distinct", attr)
+	}
+	return found, nil
+}
+
+// distinctValues returns the distinct values of the predicate at readTs, in the order of its
+// index, with the number of nodes having each. The first offset values are skipped, and at
+// most first values are returned if first is positive.
+func distinctValues(ctx context.Context, attr string, readTs uint64,
+	offset, first int) (*pb.ValueList, []uint32, error) {
+	span := otrace.FromContext(ctx)
+	stop := x.SpanTimer(span, "distinctValues: "+attr)
+	defer stop()
+
+	if !schema.State().IsIndexed(attr) {
+		return nil, nil, errors.Errorf("Predicate %s is not indexed", attr)
+	}
+	tokenizer, err := distinctTokenizer(attr)
+	if err != nil {
+		return nil, nil, err
+	}
+	typ, err := schema.State().TypeOf(attr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	txn := pstore.NewTransactionAt(readTs, false)
+	defer txn.Discard()
+
+	itOpt := badger.DefaultIteratorOptions
+	itOpt.PrefetchValues = false
+	itOpt.Prefix = x.IndexKey(attr, string(tokenizer.Identifier()))
+	it := txn.NewIterator(itOpt)
+	defer it.Close()
+
+	out := &pb.ValueList{}
+	var counts []uint32
+	var n int
+	for it.Seek(itOpt.Prefix); it.Valid(); it.Next() {
+		if first > 0 && len(counts) == first {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		default:
+		}
+
+		key := it.Item().KeyCopy(nil)
+		k := x.Parse(key)
+		if k == nil {
+			continue
+		}
+		pl, err := posting.GetNoStore(key)
+		if err != nil {
+			return nil, nil, err
+		}
+		count := pl.Length(readTs, 0)
+		if count <= 0 {
+			// All the nodes having the value were deleted.
+			continue
+		}
+		if n++; n <= offset {
+			continue
+		}
+
+		val, ok := tok.DecodeToken(tokenizer, k.Term)
+		if !ok {
+			if val, ok, err = tokenValue(attr, tokenizer, typ, pl, k.Term, readTs); err != nil {
+				return nil, nil, err
+			}
+			if !ok {
+				continue
+			}
+		}
+		data := types.ValueForType(types.BinaryID)
+		if err := types.Marshal(val, &data); err != nil {
+			return nil, nil, err
+		}
+		out.Values = append(out.Values,
+			&pb.TaskValue{ValType: val.Tid.Enum(), Val: data.Value.([]byte)})
+		counts = append(counts, uint32(count))
+	}
+	return out, counts, nil
+}
+
+// tokenValue returns the value of the predicate whose token is term, read from the first node of
+// the index posting list pl. It returns false if the node doesn't have it anymore.
+func tokenValue(attr string, tokenizer tok.Tokenizer, typ types.TypeID, pl *posting.List,
+	term string, readTs uint64) (types.Val, bool, error) {
+	uids, err := pl.Uids(posting.ListOptions{ReadTs: readTs})
+	if err != nil || len(uids.Uids) == 0 {
+		return types.Val{}, false, err
+	}
+	data, err := posting.GetNoStore(x.DataKey(attr, uids.Uids[0]))
+	if err != nil {
+		return types.Val{}, false, err
+	}
+	vals, err := data.AllValues(readTs)
+	if err != nil {
+		return types.Val{}, false, err
+	}
+	for _, v := range vals {
+		sv, err := types.Convert(v, typ)
+		if err != nil {
+			continue
+		}
+		tokens, err := tok.BuildTokens(sv.Value, tokenizer)
+		if err != nil {
+			continue
+		}
+		for _, t := range tokens {
+			if t == term {
+				return sv, true, nil
+			}
+		}
+	}
+	return types.Val{}, false, nil
+}
@@ -54,11 +54,12 @@ type node struct {
 	*conn.Node
 
 	// Fields which are never changed after init.
-	applyCh  chan []*pb.Proposal
-	rollupCh chan uint64 // Channel to run posting list rollups.
-	ctx      context.Context
-	gid      uint32
-	closer   *y.Closer
+	applyCh   chan []*pb.Proposal
+	rollupCh  chan uint64           // Channel to run posting list rollups.
+	proposeCh chan *pendingProposal // Channel of the proposals to batch.
+	ctx       context.Context
+	gid       uint32
+	closer    *y.Closer
 
 	streaming int32 // Used to avoid calculating snapshot
 
@@ -66,6 +67,10 @@ type node struct {
 	elog        trace.EventLog
 
 	pendingSize int64
+
+	// The highest commit ts applied, or the read ts of the snapshot the node started from.
+	// Append-only mutations must be committed above it. Accessed atomically.
+	maxCommitTs uint64
 }
 
 // Now that we apply txn updates via Raft, waiting based on Txn timestamps is
@@ -88,10 +93,11 @@ func newNode(store *raftwal.DiskStorage, gid uint32, id uint64, myAddr string) *
 		// We need a generous size for applyCh, because raft.Tick happens every
 		// 10ms. If we restrict the size here, then Raft goes into a loop trying
 		// to maintain quorum health.
-		applyCh:  make(chan []*pb.Proposal, 1000),
-		rollupCh: make(chan uint64, 3),
-		elog:     trace.NewEventLog("Dgraph", "ApplyCh"),
-		closer:   y.NewCloser(3), // Matches CLOSER:1
+		applyCh:   make(chan []*pb.Proposal, 1000),
+		rollupCh:  make(chan uint64, 3),
+		proposeCh: make(chan *pendingProposal, 1000),
+		elog:      trace.NewEventLog("Dgraph", "ApplyCh"),
+		closer:    y.NewCloser(4), // Matches CLOSER:1
 	}
 	return n
 }
@@ -122,7 +128,8 @@ func (n *node) applyConfChange(e raftpb.Entry) {
 	n.DoneConfChange(cc.ID, nil)
 }
 
-var errHasPendingTxns = errors.New("Pending transactions found. Please retry operation")
+var errHasPendingTxns = x.WithCode(
+	errors.New("Pending transactions found. Please retry operation"), x.CodeRetriable)
 
 // We must not wait here. Previously, we used to block until we have aborted the
 // transactions. We're now applying all updates serially, so blocking for one
@@ -145,15 +152,29 @@ func detectPendingTxns(attr string) error {
 func (n *node) applyMutations(ctx context.Context, proposal *pb.Proposal) (rerr error) {
 	span := otrace.FromContext(ctx)
 
+	if m := proposal.Mutations; m.DropOp != pb.Mutations_NONE || len(m.Schema) > 0 ||
+		len(m.Types) > 0 {
+		// Results may change without a commit, once the change is applied.
+		defer posting.NoteSchemaChange()
+	}
+
 	if proposal.Mutations.DropOp == pb.Mutations_DATA {
 		// Ensures nothing get written to disk due to commit proposals.
 		posting.Oracle().ResetTxns()
+		if err := dropIndexBuilds(""); err != nil {
+			return err
+		}
+		dropSketches("")
 		return posting.DeleteData()
 	}
 
 	if proposal.Mutations.DropOp == pb.Mutations_ALL {
 		// Ensures nothing get written to disk due to commit proposals.
 		posting.Oracle().ResetTxns()
+		if err := dropIndexBuilds(""); err != nil {
+			return err
+		}
+		dropSketches("")
 		schema.State().DeleteAll()
 
 		if err := posting.DeleteAll(); err != nil {
@@ -195,7 +216,8 @@ func (n *node) applyMutations(ctx context.Context, proposal *pb.Proposal) (rerr
 			if err := detectPendingTxns(supdate.Predicate); err != nil {
 				return err
 			}
-			if err := runSchemaMutation(ctx, supdate, startTs); err != nil {
+			if err := runSchemaMutation(ctx, supdate, startTs,
+				proposal.Mutations.RunInBackground); err != nil {
 				return err
 			}
 		}
@@ -209,6 +231,11 @@ func (n *node) applyMutations(ctx context.Context, proposal *pb.Proposal) (rerr
 		return nil
 	}
 
+	if proposal.Mutations.AppendOnly {
+		span.Annotatef(nil, "Applying append-only mutations")
+		return n.applyAppendOnly(ctx, proposal.Mutations)
+	}
+
 	// Scheduler tracks tasks at subject, predicate level, so doing
 	// schema stuff here simplies the design and we needn't worry about
 	// serializing the mutations per predicate or schema mutations
@@ -227,10 +254,15 @@ func (n *node) applyMutations(ctx context.Context, proposal *pb.Proposal) (rerr
 				return err
 			}
 			span.Annotatef(nil, "Deleting predicate: %s", edge.Attr)
+			if err := dropIndexBuilds(edge.Attr); err != nil {
+				return err
+			}
+			dropSketches(edge.Attr)
+			defer posting.NoteSchemaChange()
 			return posting.DeletePredicate(ctx, edge.Attr)
 		}
-		// Dont derive schema when doing deletion.
-		if edge.Op == pb.DirectedEdge_DEL {
+		// Dont derive schema when doing deletion, or add and cas which need a typed predicate.
+		if edge.Op != pb.DirectedEdge_SET {
 			continue
 		}
 		if _, ok := schemaMap[edge.Attr]; !ok {
@@ -336,6 +368,7 @@ func (n *node) applyCommitted(proposal *pb.Proposal) error {
 
 	switch {
 	case len(proposal.Kv) > 0:
+		defer posting.NoteSchemaChange()
 		return populateKeyValues(ctx, proposal.Kv)
 
 	case proposal.State != nil:
@@ -347,6 +380,11 @@ func (n *node) applyCommitted(proposal *pb.Proposal) error {
 
 	case len(proposal.CleanPredicate) > 0:
 		n.elog.Printf("Cleaning predicate: %s", proposal.CleanPredicate)
+		if err := dropIndexBuilds(proposal.CleanPredicate); err != nil {
+			return err
+		}
+		dropSketches(proposal.CleanPredicate)
+		defer posting.NoteSchemaChange()
 		return posting.DeletePredicate(ctx, proposal.CleanPredicate)
 
 	case proposal.Delta != nil:
@@ -501,12 +539,20 @@ func (n *node) processApplyCh() {
 func (n *node) commitOrAbort(pkey string, delta *pb.OracleDelta) error {
 	// First let's commit all mutations to disk.
 	writer := posting.NewTxnWriter(pstore)
+	type committed struct {
+		preds    []string
+		commitTs uint64
+	}
+	var commits []committed
 	toDisk := func(start, commit uint64) {
 		txn := posting.Oracle().GetTxn(start)
 		if txn == nil {
 			return
 		}
 		txn.Update()
+		if commit > 0 {
+			commits = append(commits, committed{preds: txn.Predicates(), commitTs: commit})
+		}
 		err := x.RetryUntilSuccess(x.WorkerConfig.MaxRetries, 10*time.Millisecond, func() error {
 			return txn.CommitToDisk(writer, commit)
 		})
@@ -517,9 +563,12 @@ func (n *node) commitOrAbort(pkey string, delta *pb.OracleDelta) error {
 		}
 	}
 
+	maxCommitTs := atomic.LoadUint64(&n.maxCommitTs)
 	for _, status := range delta.Txns {
 		toDisk(status.StartTs, status.CommitTs)
+		maxCommitTs = x.Max(maxCommitTs, status.CommitTs)
 	}
+	atomic.StoreUint64(&n.maxCommitTs, maxCommitTs)
 	if err := writer.Flush(); err != nil {
 		return errors.Wrapf(err, "while flushing to disk")
 	}
@@ -527,6 +576,11 @@ func (n *node) commitOrAbort(pkey string, delta *pb.OracleDelta) error {
 	g := groups()
 	atomic.StoreUint64(&g.deltaChecksum, delta.GroupChecksums[g.groupId()])
 
+	// Note the commits before they become visible, see worker.PredicateVersion.
+	for _, c := range commits {
+		posting.NoteCommit(c.preds, c.commitTs)
+	}
+
 	// Now advance Oracle(), so we can service waiting reads.
 	posting.Oracle().ProcessDelta(delta)
 	return nil
@@ -603,6 +657,7 @@ func (n *node) retrieveSnapshot(snap pb.Snapshot) error {
 	if err := schema.LoadFromDb(); err != nil {
 		return errors.Wrapf(err, "while initializing schema")
 	}
+	atomic.StoreUint64(&n.maxCommitTs, x.Max(atomic.LoadUint64(&n.maxCommitTs), snap.ReadTs))
 	groups().triggerMembershipSync()
 	return nil
 }
@@ -889,7 +944,9 @@ func (n *node) Run() {
 						x.Fatalf("Unable to unmarshal proposal: %v %q\n", err, entry.Data)
 					}
 					if pctx := n.Proposals.Get(proposal.Key); pctx != nil {
-						atomic.AddUint32(&pctx.Found, 1)
+						if atomic.AddUint32(&pctx.Found, 1) == 1 {
+							proposalLogged(pctx.Ctx)
+						}
 						if span := otrace.FromContext(pctx.Ctx); span != nil {
 							span.Annotate(nil, "Proposal found in CommittedEntries")
 						}
@@ -991,9 +1048,17 @@ func (n *node) rollupLists(readTs uint64) error {
 		atomic.AddInt64(size, delta)
 	}
 
+	buildTs := indexBuildsTs()
 	stream := pstore.NewStreamAt(readTs)
 	stream.LogPrefix = "Rolling up"
 	stream.ChooseKey = func(item *badger.Item) bool {
+		// The index builds write their entries below readTs, a rollup would hide them.
+		if buildTs > 0 {
+			if pk := x.Parse(item.Key()); pk != nil && isIndexBuilding(pk.Attr) {
+				addTo(item.Key(), item.EstimatedSize())
+				return false
+			}
+		}
 		switch item.UserMeta() {
 		case posting.BitSchemaPosting, posting.BitCompletePosting, posting.BitEmptyPosting:
 			addTo(item.Key(), item.EstimatedSize())
@@ -1034,8 +1099,13 @@ func (n *node) rollupLists(readTs uint64) error {
 	// For all the keys, let's see if they're in the LRU cache. If so, we can roll them up.
 	glog.Infof("Rolled up %d keys. Done", atomic.LoadUint64(&numKeys))
 
-	// We can now discard all invalid versions of keys below this ts.
-	pstore.SetDiscardTs(readTs)
+	// We can now discard all invalid versions of keys below this ts. The versions read by the
+	// index builds are kept until they're done.
+	if buildTs > 0 && buildTs < readTs {
+		pstore.SetDiscardTs(buildTs - 1)
+	} else {
+		pstore.SetDiscardTs(readTs)
+	}
 
 	if amLeader {
 		// Only leader sends the tablet size updates to Zero. No one else does.
@@ -1251,7 +1321,8 @@ func (n *node) calculateSnapshot(startIdx uint64, discardN int) (*pb.Snapshot, e
 				span.Annotatef(nil, "Error: %v", err)
 				return nil, err
 			}
-			if proposal.Mutations != nil {
+			// Append-only mutations are committed when applied, they're never pending.
+			if proposal.Mutations != nil && !proposal.Mutations.AppendOnly {
 				start := proposal.Mutations.StartTs
 				if start >= minPendingStart && snapshotIdx == 0 {
 					snapshotIdx = entry.Index - 1
@@ -1372,6 +1443,10 @@ func (n *node) InitAndStartNode() {
 			// zero-member Raft group.
 			n.SetConfState(&sp.Metadata.ConfState)
 
+			var snap pb.Snapshot
+			x.Check(snap.Unmarshal(sp.Data))
+			atomic.StoreUint64(&n.maxCommitTs, snap.ReadTs)
+
 			members := groups().members(n.gid)
 			for _, id := range sp.Metadata.ConfState.Nodes {
 				m, ok := members[id]
@@ -1403,6 +1478,7 @@ func (n *node) InitAndStartNode() {
 	}
 	go n.processRollups()
 	go n.processApplyCh()
+	go n.batchProposals()
 	go n.BatchAndSendMessages()
 	go n.Run()
 }
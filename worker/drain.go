@@ -0,0 +1,80 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"time"
+
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/conn"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// Drain prepares this Alpha to be shut down without downtime: the leadership of its group is
+// moved to another healthy member, and if there's none, Zero moves the tablets of the group
+// to the other groups.
+func Drain(ctx context.Context) error {
+	g := groups()
+	n := g.Node
+	if n.AmLeader() {
+		if err := transferLeadership(ctx, n, g.groupId()); err != nil {
+			return err
+		}
+	}
+
+	pl := g.connToZeroLeader()
+	if pl == nil {
+		return errors.Errorf("Unable to reach the Zero leader")
+	}
+	m := &pb.Member{Id: n.Id, GroupId: g.groupId()}
+	_, err := pb.NewZeroClient(pl.Get()).DrainNode(ctx, m)
+	return err
+}
+
+// transferLeadership moves the leadership of the group to a healthy peer, if there's one.
+func transferLeadership(ctx context.Context, n *node, gid uint32) error {
+	var peer uint64
+	for id, m := range groups().members(gid) {
+		if id == n.Id {
+			continue
+		}
+		if pl, err := conn.GetPools().Get(m.Addr); err == nil && pl.IsHealthy() {
+			peer = id
+			break
+		}
+	}
+	if peer == 0 {
+		// Zero moves the tablets out of the group instead.
+		return nil
+	}
+
+	glog.Infof("Transferring leadership of group %d from %#x to %#x", gid, n.Id, peer)
+	n.Raft().TransferLeadership(ctx, n.Id, peer)
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for n.AmLeader() {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return errors.Wrapf(ctx.Err(), "while transferring leadership to %#x", peer)
+		}
+	}
+	return nil
+}
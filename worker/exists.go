@@ -0,0 +1,65 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	otrace "go.opencensus.io/trace"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// ExistingUids returns the uids of the sorted list which are nodes with any predicate at readTs.
+// Each predicate reads the posting lists of the uids which weren't found in the predicates before
+// it, without scanning the predicate.
+func ExistingUids(ctx context.Context, uids *pb.List, readTs uint64) (*pb.List, error) {
+	ctx, span := otrace.StartSpan(ctx, "worker.ExistingUids")
+	defer span.End()
+
+	schema, err := GetSchemaOverNetwork(ctx, &pb.SchemaRequest{Fields: []string{"lang"}})
+	if err != nil {
+		return nil, err
+	}
+	remaining := uids
+	var found []*pb.List
+	for _, s := range schema {
+		if len(remaining.Uids) == 0 {
+			break
+		}
+		// has() of a list of uids reads their posting lists.
+		q := &pb.Query{
+			Attr:    s.Predicate,
+			UidList: remaining,
+			SrcFunc: &pb.SrcFunction{Name: "has"},
+			ReadTs:  readTs,
+		}
+		if s.Lang {
+			// Keep the nodes with values in any language.
+			q.Langs = []string{"."}
+		}
+		reply, err := ProcessTaskOverNetwork(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		has := algo.MergeSorted(reply.UidMatrix)
+		found = append(found, has)
+		remaining = algo.Difference(remaining, has)
+	}
+	span.Annotatef(nil, "Found %d of %d uids", len(uids.Uids)-len(remaining.Uids), len(uids.Uids))
+	return algo.MergeSorted(found), nil
+}
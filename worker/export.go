@@ -64,6 +64,10 @@ var exportFormats = map[string]exportFormat{
 		pre:  "",
 		post: "",
 	},
+	// The graph formats are written by a graphWriter once all the data is read.
+	"graphml": {ext: ".graphml"},
+	"gexf":    {ext: ".gexf"},
+	"dot":     {ext: ".dot"},
 }
 
 type exporter struct {
@@ -85,6 +89,7 @@ var rdfTypeMap = map[types.TypeID]string{
 	types.GeoID:      "geo:geojson",
 	types.BinaryID:   "xs:base64Binary",
 	types.PasswordID: "xs:password",
+	types.JSONID:     "rdf:JSON",
 }
 
 // UIDs like 0x1 look weird but 64-bit ones like 0x0000000000000001 are too long.
@@ -157,7 +162,10 @@ func (e *exporter) toJSON() (*bpb.KVList, error) {
 				fmt.Fprintf(bp, `,"%s":`, e.attr)
 			}
 
-			val := types.Val{Tid: types.TypeID(p.ValType), Value: p.Value}
+			val, err := posting.ResolveValue(p)
+			if err != nil {
+				return err
+			}
 			str, err := valToStr(val)
 			if err != nil {
 				// Copying this behavior from RDF exporter.
@@ -216,7 +224,10 @@ func (e *exporter) toRDF() (*bpb.KVList, error) {
 		if p.PostingType == pb.Posting_REF {
 			fmt.Fprint(bp, fmt.Sprintf(uidFmtStrRdf, p.Uid))
 		} else {
-			val := types.Val{Tid: types.TypeID(p.ValType), Value: p.Value}
+			val, err := posting.ResolveValue(p)
+			if err != nil {
+				return err
+			}
 			str, err := valToStr(val)
 			if err != nil {
 				glog.Errorf("Ignoring error: %+v\n", err)
@@ -305,6 +316,44 @@ func toSchema(attr string, update pb.SchemaUpdate) (*bpb.KVList, error) {
 	if update.Upsert {
 		buf.WriteString(" @upsert")
 	}
+	if update.Masked != "" {
+		buf.WriteString(" @masked(")
+		buf.WriteString(update.Masked)
+		buf.WriteByte(')')
+	}
+	if update.MaxSize > 0 {
+		fmt.Fprintf(&buf, " @maxsize(%d)", update.MaxSize)
+	}
+	if update.Collation != "" {
+		buf.WriteString(" @collate(")
+		buf.WriteString(update.Collation)
+		buf.WriteByte(')')
+	}
+	if update.Conflict != "" {
+		buf.WriteString(" @conflict(")
+		buf.WriteString(update.Conflict)
+		buf.WriteByte(')')
+	}
+	if update.AppendOnly {
+		buf.WriteString(" @appendonly")
+	}
+	if len(update.Facets) > 0 {
+		buf.WriteString(" @facets(")
+		for i, f := range update.Facets {
+			if i > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(f.Predicate)
+			buf.WriteString(": ")
+			buf.WriteString(types.TypeID(f.ValueType).Name())
+			if len(f.Tokenizer) > 0 {
+				buf.WriteString(" @index(")
+				buf.WriteString(strings.Join(f.Tokenizer, ","))
+				buf.WriteByte(')')
+			}
+		}
+		buf.WriteByte(')')
+	}
 	buf.WriteString(" . \n")
 	kv := &bpb.KV{
 		Value:   buf.Bytes(),
@@ -315,7 +364,14 @@ func toSchema(attr string, update pb.SchemaUpdate) (*bpb.KVList, error) {
 
 func toType(attr string, update pb.TypeUpdate) (*bpb.KVList, error) {
 	var buf bytes.Buffer
-	buf.WriteString(fmt.Sprintf("type %s {\n", attr))
+	buf.WriteString(fmt.Sprintf("type %s ", attr))
+	if len(update.Key) > 0 {
+		buf.WriteString(fmt.Sprintf("@key(%s) ", strings.Join(update.Key, ", ")))
+	}
+	if update.Strict {
+		buf.WriteString("@strict ")
+	}
+	buf.WriteString("{\n")
 	for _, field := range update.Fields {
 		buf.WriteString(fieldToString(field))
 	}
@@ -446,6 +502,22 @@ func export(ctx context.Context, in *pb.ExportRequest) error {
 		return err
 	}
 
+	var graph *graphWriter
+	if _, ok := graphEncoders[in.Format]; ok {
+		edgePath, err := path(".edges")
+		if err != nil {
+			return err
+		}
+		if graph, err = newGraphWriter(in.Format, edgePath); err != nil {
+			return err
+		}
+		defer func() {
+			if err := graph.close(); err != nil {
+				glog.Warningf("Unable to remove the edges of the export: %v", err)
+			}
+		}()
+	}
+
 	stream := pstore.NewStreamAt(in.ReadTs)
 	stream.LogPrefix = "Export"
 	stream.ChooseKey = func(item *badger.Item) bool {
@@ -513,6 +585,8 @@ func export(ctx context.Context, in *pb.ExportRequest) error {
 				return e.toJSON()
 			case "rdf":
 				return e.toRDF()
+			case "graphml", "gexf", "dot":
+				return nil, graph.add(e)
 			default:
 				glog.Fatalf("Invalid export format found: %s", in.Format)
 			}
@@ -531,6 +605,8 @@ func export(ctx context.Context, in *pb.ExportRequest) error {
 	case "rdf":
 		// The separator for RDF should be empty since the toRDF function already
 		// adds newline to each RDF entry.
+	case "graphml", "gexf", "dot":
+		// The graph formats don't send any data.
 	default:
 		glog.Fatalf("Invalid export format found: %s", in.Format)
 	}
@@ -572,6 +648,11 @@ func export(ctx context.Context, in *pb.ExportRequest) error {
 	if err := stream.Orchestrate(ctx); err != nil {
 		return err
 	}
+	if graph != nil {
+		if err := graph.writeTo(dataWriter.gw); err != nil {
+			return err
+		}
+	}
 	if _, err = dataWriter.gw.Write([]byte(xfmt.post)); err != nil {
 		return err
 	}
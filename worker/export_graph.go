@@ -0,0 +1,557 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/types/facets"
+)
+
+// GraphAttr is an attribute of a node or of an edge, with its value as a string.
+type GraphAttr struct {
+	Name  string
+	Tid   types.TypeID
+	Value string
+}
+
+// GraphNode is a node of a graph in one of the graph formats.
+type GraphNode struct {
+	Uid   uint64
+	Attrs []GraphAttr
+}
+
+// GraphEdge is an edge of a graph in one of the graph formats. The predicate is its label, and
+// the facets are its attributes.
+type GraphEdge struct {
+	From      uint64
+	To        uint64
+	Predicate string
+	Attrs     []GraphAttr
+}
+
+const (
+	nodeClass = "node"
+	edgeClass = "edge"
+)
+
+// graphEncoder writes a graph in one of the graph formats: the header declaring the attributes,
+// then the nodes, then the edges.
+type graphEncoder interface {
+	// native returns whether the attribute is written as a property of the format, instead of as
+	// a declared attribute.
+	native(class string, a GraphAttr) bool
+	begin(buf *bytes.Buffer, keys *graphKeys)
+	node(buf *bytes.Buffer, keys *graphKeys, n *GraphNode)
+	edges(buf *bytes.Buffer)
+	edge(buf *bytes.Buffer, keys *graphKeys, id int, e *GraphEdge)
+	end(buf *bytes.Buffer)
+}
+
+var graphEncoders = map[string]graphEncoder{
+	"graphml": graphmlEncoder{},
+	"gexf":    gexfEncoder{},
+	"dot":     dotEncoder{},
+}
+
+var graphContentTypes = map[string]string{
+	"graphml": "application/graphml+xml",
+	"gexf":    "application/gexf+xml",
+	"dot":     "text/vnd.graphviz",
+}
+
+// NormalizeGraphFormat returns the normalized string for the graph format if it is valid, an
+// empty string otherwise.
+func NormalizeGraphFormat(format string) string {
+	format = strings.ToLower(format)
+	if _, ok := graphEncoders[format]; ok {
+		return format
+	}
+	return ""
+}
+
+// GraphContentType returns the content type of the graph format.
+func GraphContentType(format string) string {
+	return graphContentTypes[format]
+}
+
+// graphKey is the declaration of an attribute of the nodes or of the edges.
+type graphKey struct {
+	id   string
+	name string
+	typ  string
+}
+
+// graphKeys gives ids to the attributes in the order in which they're declared. The GraphML and
+// GEXF files declare all of them before the graph.
+type graphKeys struct {
+	enc   graphEncoder
+	byId  map[string]*graphKey
+	nodes []*graphKey
+	edges []*graphKey
+}
+
+func newGraphKeys(enc graphEncoder) *graphKeys {
+	return &graphKeys{enc: enc, byId: make(map[string]*graphKey)}
+}
+
+// graphAttrType returns the type of the attribute in GraphML and GEXF.
+func graphAttrType(tid types.TypeID) string {
+	switch tid {
+	case types.IntID:
+		return "long"
+	case types.FloatID:
+		return "double"
+	case types.BoolID:
+		return "boolean"
+	default:
+		return "string"
+	}
+}
+
+// declare declares the attributes which aren't native to the format. An attribute with values
+// of different types is declared as a string.
+func (k *graphKeys) declare(class string, attrs []GraphAttr) {
+	for _, a := range attrs {
+		if k.enc.native(class, a) {
+			continue
+		}
+		typ := graphAttrType(a.Tid)
+		key, ok := k.byId[class+"\x00"+a.Name]
+		switch {
+		case !ok:
+			key = &graphKey{id: fmt.Sprintf("d%d", len(k.byId)), name: a.Name, typ: typ}
+			k.byId[class+"\x00"+a.Name] = key
+			if class == nodeClass {
+				k.nodes = append(k.nodes, key)
+			} else {
+				k.edges = append(k.edges, key)
+			}
+		case key.typ != typ:
+			key.typ = "string"
+		}
+	}
+}
+
+func (k *graphKeys) id(class, name string) string {
+	return k.byId[class+"\x00"+name].id
+}
+
+// mergeAttrs joins the values of the attributes with the same name, which the graph formats
+// don't allow to repeat.
+func mergeAttrs(attrs []GraphAttr) []GraphAttr {
+	var merged []GraphAttr
+	idx := make(map[string]int)
+	for _, a := range attrs {
+		i, ok := idx[a.Name]
+		if !ok {
+			idx[a.Name] = len(merged)
+			merged = append(merged, a)
+			continue
+		}
+		merged[i].Tid = types.StringID
+		merged[i].Value += "," + a.Value
+	}
+	return merged
+}
+
+func graphUid(uid uint64) string {
+	return fmt.Sprintf("0x%x", uid)
+}
+
+func xmlEscape(s string) string {
+	var buf bytes.Buffer
+	// Writing to a bytes.Buffer never fails.
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+type graphmlEncoder struct{}
+
+func (graphmlEncoder) native(class string, a GraphAttr) bool {
+	return false
+}
+
+func (graphmlEncoder) begin(buf *bytes.Buffer, keys *graphKeys) {
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.WriteString(`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n")
+	buf.WriteString(`  <key id="predicate" for="edge" attr.name="predicate"` +
+		` attr.type="string"/>` + "\n")
+	for _, l := range []struct {
+		class string
+		keys  []*graphKey
+	}{{nodeClass, keys.nodes}, {edgeClass, keys.edges}} {
+		for _, key := range l.keys {
+			fmt.Fprintf(buf, "  <key id=%q for=%q attr.name=\"%s\" attr.type=%q/>\n",
+				key.id, l.class, xmlEscape(key.name), key.typ)
+		}
+	}
+	buf.WriteString(`  <graph id="dgraph" edgedefault="directed">` + "\n")
+}
+
+func (graphmlEncoder) node(buf *bytes.Buffer, keys *graphKeys, n *GraphNode) {
+	fmt.Fprintf(buf, "    <node id=%q>\n", graphUid(n.Uid))
+	for _, a := range n.Attrs {
+		fmt.Fprintf(buf, "      <data key=%q>%s</data>\n", keys.id(nodeClass, a.Name),
+			xmlEscape(a.Value))
+	}
+	buf.WriteString("    </node>\n")
+}
+
+func (graphmlEncoder) edges(buf *bytes.Buffer) {}
+
+func (graphmlEncoder) edge(buf *bytes.Buffer, keys *graphKeys, id int, e *GraphEdge) {
+	fmt.Fprintf(buf, "    <edge id=\"e%d\" source=%q target=%q>\n", id, graphUid(e.From),
+		graphUid(e.To))
+	fmt.Fprintf(buf, "      <data key=\"predicate\">%s</data>\n", xmlEscape(e.Predicate))
+	for _, a := range e.Attrs {
+		fmt.Fprintf(buf, "      <data key=%q>%s</data>\n", keys.id(edgeClass, a.Name),
+			xmlEscape(a.Value))
+	}
+	buf.WriteString("    </edge>\n")
+}
+
+func (graphmlEncoder) end(buf *bytes.Buffer) {
+	buf.WriteString("  </graph>\n</graphml>\n")
+}
+
+// gexfEncoder writes the predicate of the edges as their label, and a numeric weight facet as
+// their weight.
+type gexfEncoder struct{}
+
+func (gexfEncoder) native(class string, a GraphAttr) bool {
+	return class == edgeClass && a.Name == "weight" && a.Tid.IsNumber()
+}
+
+func (gexfEncoder) begin(buf *bytes.Buffer, keys *graphKeys) {
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	buf.WriteString(`<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">` + "\n")
+	buf.WriteString(`  <graph mode="static" defaultedgetype="directed">` + "\n")
+	for _, l := range []struct {
+		class string
+		keys  []*graphKey
+	}{{nodeClass, keys.nodes}, {edgeClass, keys.edges}} {
+		if len(l.keys) == 0 {
+			continue
+		}
+		fmt.Fprintf(buf, "    <attributes class=%q>\n", l.class)
+		for _, key := range l.keys {
+			fmt.Fprintf(buf, "      <attribute id=%q title=\"%s\" type=%q/>\n", key.id,
+				xmlEscape(key.name), key.typ)
+		}
+		buf.WriteString("    </attributes>\n")
+	}
+	buf.WriteString("    <nodes>\n")
+}
+
+func gexfAttValues(buf *bytes.Buffer, keys *graphKeys, class string, attrs []GraphAttr) {
+	var wrote bool
+	for _, a := range attrs {
+		if (gexfEncoder{}).native(class, a) {
+			continue
+		}
+		if !wrote {
+			buf.WriteString("        <attvalues>\n")
+			wrote = true
+		}
+		fmt.Fprintf(buf, "          <attvalue for=%q value=\"%s\"/>\n", keys.id(class, a.Name),
+			xmlEscape(a.Value))
+	}
+	if wrote {
+		buf.WriteString("        </attvalues>\n")
+	}
+}
+
+func (gexfEncoder) node(buf *bytes.Buffer, keys *graphKeys, n *GraphNode) {
+	uid := graphUid(n.Uid)
+	fmt.Fprintf(buf, "      <node id=%q label=%q>\n", uid, uid)
+	gexfAttValues(buf, keys, nodeClass, n.Attrs)
+	buf.WriteString("      </node>\n")
+}
+
+func (gexfEncoder) edges(buf *bytes.Buffer) {
+	buf.WriteString("    </nodes>\n    <edges>\n")
+}
+
+func (enc gexfEncoder) edge(buf *bytes.Buffer, keys *graphKeys, id int, e *GraphEdge) {
+	fmt.Fprintf(buf, "      <edge id=\"%d\" source=%q target=%q label=\"%s\"", id,
+		graphUid(e.From), graphUid(e.To), xmlEscape(e.Predicate))
+	for _, a := range e.Attrs {
+		if enc.native(edgeClass, a) {
+			fmt.Fprintf(buf, " weight=%q", a.Value)
+		}
+	}
+	buf.WriteString(">\n")
+	gexfAttValues(buf, keys, edgeClass, e.Attrs)
+	buf.WriteString("      </edge>\n")
+}
+
+func (gexfEncoder) end(buf *bytes.Buffer) {
+	buf.WriteString("    </edges>\n  </graph>\n</gexf>\n")
+}
+
+// dotEncoder writes the predicate of the edges as their label, and the facets as their
+// attributes, so that a weight facet is their weight.
+type dotEncoder struct{}
+
+var dotReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotReplacer.Replace(s) + `"`
+}
+
+func dotAttrs(buf *bytes.Buffer, attrs []GraphAttr) {
+	for i, a := range attrs {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+		buf.WriteString(dotQuote(a.Name))
+		buf.WriteByte('=')
+		buf.WriteString(dotQuote(a.Value))
+	}
+}
+
+func (dotEncoder) native(class string, a GraphAttr) bool {
+	return true
+}
+
+func (dotEncoder) begin(buf *bytes.Buffer, keys *graphKeys) {
+	buf.WriteString("digraph dgraph {\n")
+}
+
+func (dotEncoder) node(buf *bytes.Buffer, keys *graphKeys, n *GraphNode) {
+	buf.WriteString("  " + dotQuote(graphUid(n.Uid)))
+	if len(n.Attrs) > 0 {
+		buf.WriteString(" [")
+		dotAttrs(buf, n.Attrs)
+		buf.WriteByte(']')
+	}
+	buf.WriteString(";\n")
+}
+
+func (dotEncoder) edges(buf *bytes.Buffer) {}
+
+func (dotEncoder) edge(buf *bytes.Buffer, keys *graphKeys, id int, e *GraphEdge) {
+	fmt.Fprintf(buf, "  %s -> %s [", dotQuote(graphUid(e.From)), dotQuote(graphUid(e.To)))
+	dotAttrs(buf, append([]GraphAttr{{Name: "label", Value: e.Predicate}}, e.Attrs...))
+	buf.WriteString("];\n")
+}
+
+func (dotEncoder) end(buf *bytes.Buffer) {
+	buf.WriteString("}\n")
+}
+
+// EncodeGraph writes the nodes and the edges to w in the graph format. The edges may only lead
+// to the nodes given.
+func EncodeGraph(w io.Writer, format string, nodes []*GraphNode, edges []*GraphEdge) error {
+	enc, ok := graphEncoders[format]
+	if !ok {
+		return errors.Errorf("Invalid graph format: %s", format)
+	}
+	keys := newGraphKeys(enc)
+	for _, n := range nodes {
+		n.Attrs = mergeAttrs(n.Attrs)
+		keys.declare(nodeClass, n.Attrs)
+	}
+	for _, e := range edges {
+		e.Attrs = mergeAttrs(e.Attrs)
+		keys.declare(edgeClass, e.Attrs)
+	}
+
+	var buf bytes.Buffer
+	enc.begin(&buf, keys)
+	for _, n := range nodes {
+		enc.node(&buf, keys, n)
+	}
+	enc.edges(&buf)
+	for i, e := range edges {
+		enc.edge(&buf, keys, i, e)
+	}
+	enc.end(&buf)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// graphWriter collects the nodes and the edges of a group for its export in a graph format. The
+// values of the nodes are kept in memory until the export ends, because the nodes must be
+// written before the edges, and the edges are written to a temporary file as they come.
+type graphWriter struct {
+	sync.Mutex
+	enc      graphEncoder
+	keys     *graphKeys
+	nodes    map[uint64][]GraphAttr
+	edgePath string
+	edgeFd   *os.File
+	edgeBuf  *bufio.Writer
+	numEdges int
+}
+
+func newGraphWriter(format, edgePath string) (*graphWriter, error) {
+	enc, ok := graphEncoders[format]
+	if !ok {
+		return nil, errors.Errorf("Invalid graph format: %s", format)
+	}
+	fd, err := os.Create(edgePath)
+	if err != nil {
+		return nil, err
+	}
+	return &graphWriter{
+		enc:      enc,
+		keys:     newGraphKeys(enc),
+		nodes:    make(map[uint64][]GraphAttr),
+		edgePath: edgePath,
+		edgeFd:   fd,
+		edgeBuf:  bufio.NewWriterSize(fd, 1e6),
+	}, nil
+}
+
+// facetAttrs returns the facets as attributes whose names are prefixed by prefix.
+func facetAttrs(fcts []*api.Facet, prefix string) []GraphAttr {
+	var attrs []GraphAttr
+	for _, fct := range fcts {
+		str, err := facetToString(fct)
+		if err != nil {
+			glog.Errorf("Ignoring error: %+v", err)
+			continue
+		}
+		tid, err := facets.TypeIDFor(fct)
+		if err != nil {
+			glog.Errorf("Error getting type id from facet %#v: %v", fct, err)
+			continue
+		}
+		attrs = append(attrs, GraphAttr{Name: prefix + fct.Key, Tid: tid, Value: str})
+	}
+	return attrs
+}
+
+// add adds the values of the posting list of the exporter to its node, and writes its edges.
+func (g *graphWriter) add(e *exporter) error {
+	var attrs []GraphAttr
+	var edges []*GraphEdge
+	err := e.pl.Iterate(e.readTs, 0, func(p *pb.Posting) error {
+		if p.PostingType == pb.Posting_REF {
+			edges = append(edges, &GraphEdge{
+				From:      e.uid,
+				To:        p.Uid,
+				Predicate: e.attr,
+				Attrs:     mergeAttrs(facetAttrs(p.Facets, "")),
+			})
+			return nil
+		}
+
+		val, err := posting.ResolveValue(p)
+		if err != nil {
+			return err
+		}
+		str, err := valToStr(val)
+		if err != nil {
+			glog.Errorf("Ignoring error: %+v\n", err)
+			return nil
+		}
+		name := e.attr
+		if p.PostingType == pb.Posting_VALUE_LANG {
+			name += "@" + string(p.LangTag)
+		}
+		attrs = append(attrs, GraphAttr{Name: name, Tid: val.Tid, Value: str})
+		// The facets of a value are attributes of the node, as in the JSON exports.
+		attrs = append(attrs, facetAttrs(p.Facets, e.attr+"|")...)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	g.nodes[e.uid] = append(g.nodes[e.uid], attrs...)
+	var buf bytes.Buffer
+	for _, edge := range edges {
+		if _, ok := g.nodes[edge.To]; !ok {
+			g.nodes[edge.To] = nil
+		}
+		g.keys.declare(edgeClass, edge.Attrs)
+		g.enc.edge(&buf, g.keys, g.numEdges, edge)
+		g.numEdges++
+	}
+	_, err = g.edgeBuf.Write(buf.Bytes())
+	return err
+}
+
+// writeTo writes the graph to w, with the nodes sorted by uid.
+func (g *graphWriter) writeTo(w io.Writer) error {
+	uids := make([]uint64, 0, len(g.nodes))
+	for uid, attrs := range g.nodes {
+		uids = append(uids, uid)
+		attrs = mergeAttrs(attrs)
+		g.nodes[uid] = attrs
+		g.keys.declare(nodeClass, attrs)
+	}
+	sort.Slice(uids, func(i, j int) bool { return uids[i] < uids[j] })
+
+	var buf bytes.Buffer
+	g.enc.begin(&buf, g.keys)
+	for _, uid := range uids {
+		g.enc.node(&buf, g.keys, &GraphNode{Uid: uid, Attrs: g.nodes[uid]})
+		if buf.Len() >= 1e6 {
+			if _, err := w.Write(buf.Bytes()); err != nil {
+				return err
+			}
+			buf.Reset()
+		}
+	}
+	g.enc.edges(&buf)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return err
+	}
+	buf.Reset()
+
+	if err := g.edgeBuf.Flush(); err != nil {
+		return err
+	}
+	if _, err := g.edgeFd.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, g.edgeFd); err != nil {
+		return err
+	}
+	g.enc.end(&buf)
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// close removes the temporary file of the edges.
+func (g *graphWriter) close() error {
+	if err := g.edgeFd.Close(); err != nil {
+		return err
+	}
+	return os.Remove(g.edgePath)
+}
@@ -0,0 +1,115 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/types"
+)
+
+func testGraph() ([]*GraphNode, []*GraphEdge) {
+	nodes := []*GraphNode{
+		{Uid: 1, Attrs: []GraphAttr{
+			{Name: "name", Tid: types.StringID, Value: "Alice & Bob"},
+			{Name: "age", Tid: types.IntID, Value: "33"},
+		}},
+		{Uid: 2, Attrs: []GraphAttr{
+			{Name: "dgraph.type", Tid: types.StringID, Value: "Person"},
+			{Name: "dgraph.type", Tid: types.StringID, Value: "Admin"},
+		}},
+	}
+	edges := []*GraphEdge{
+		{From: 1, To: 2, Predicate: "friend", Attrs: []GraphAttr{
+			{Name: "weight", Tid: types.FloatID, Value: "2.5"},
+		}},
+	}
+	return nodes, edges
+}
+
+func TestEncodeGraphML(t *testing.T) {
+	nodes, edges := testGraph()
+	var buf bytes.Buffer
+	require.NoError(t, EncodeGraph(&buf, "graphml", nodes, edges))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns">
+  <key id="predicate" for="edge" attr.name="predicate" attr.type="string"/>
+  <key id="d0" for="node" attr.name="name" attr.type="string"/>
+  <key id="d1" for="node" attr.name="age" attr.type="long"/>
+  <key id="d2" for="node" attr.name="dgraph.type" attr.type="string"/>
+  <key id="d3" for="edge" attr.name="weight" attr.type="double"/>
+  <graph id="dgraph" edgedefault="directed">
+    <node id="0x1">
+      <data key="d0">Alice &amp; Bob</data>
+      <data key="d1">33</data>
+    </node>
+    <node id="0x2">
+      <data key="d2">Person,Admin</data>
+    </node>
+    <edge id="e0" source="0x1" target="0x2">
+      <data key="predicate">friend</data>
+      <data key="d3">2.5</data>
+    </edge>
+  </graph>
+</graphml>
+`, buf.String())
+}
+
+func TestEncodeGEXF(t *testing.T) {
+	nodes, edges := testGraph()
+	var buf bytes.Buffer
+	require.NoError(t, EncodeGraph(&buf, "gexf", nodes, edges))
+	// The weight is the weight of the edge, instead of one of its attributes.
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://www.gexf.net/1.2draft" version="1.2">
+  <graph mode="static" defaultedgetype="directed">
+    <attributes class="node">
+      <attribute id="d0" title="name" type="string"/>
+      <attribute id="d1" title="age" type="long"/>
+      <attribute id="d2" title="dgraph.type" type="string"/>
+    </attributes>
+    <nodes>
+      <node id="0x1" label="0x1">
+        <attvalues>
+          <attvalue for="d0" value="Alice &amp; Bob"/>
+          <attvalue for="d1" value="33"/>
+        </attvalues>
+      </node>
+      <node id="0x2" label="0x2">
+        <attvalues>
+          <attvalue for="d2" value="Person,Admin"/>
+        </attvalues>
+      </node>
+    </nodes>
+    <edges>
+      <edge id="0" source="0x1" target="0x2" label="friend" weight="2.5">
+      </edge>
+    </edges>
+  </graph>
+</gexf>
+`, buf.String())
+}
+
+func TestEncodeGraphInvalidFormat(t *testing.T) {
+	var buf bytes.Buffer
+	require.Error(t, EncodeGraph(&buf, "csv", nil, nil))
+	require.Equal(t, "", NormalizeGraphFormat("csv"))
+	require.Equal(t, "gexf", NormalizeGraphFormat("GEXF"))
+}
@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
@@ -331,6 +332,58 @@ func TestExportJson(t *testing.T) {
 	checkExportSchema(t, schemaFileList)
 }
 
+func TestExportDot(t *testing.T) {
+	initTestExport(t, "name:string @index .")
+
+	bdir, err := ioutil.TempDir("", "export")
+	require.NoError(t, err)
+	defer os.RemoveAll(bdir)
+
+	time.Sleep(1 * time.Second)
+
+	x.WorkerConfig.ExportPath = bdir
+	readTs := timestamp()
+	// Do the following so export won't block forever for readTs.
+	posting.Oracle().ProcessDelta(&pb.OracleDelta{MaxAssigned: readTs})
+	req := pb.ExportRequest{ReadTs: readTs, GroupId: 1, Format: "dot"}
+	err = export(context.Background(), &req)
+	require.NoError(t, err)
+
+	// The temporary file of the edges is removed.
+	fileList, schemaFileList := getExportFileList(t, bdir)
+
+	f, err := os.Open(fileList[0])
+	require.NoError(t, err)
+	r, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	gotDot, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	lines := strings.Split(string(gotDot), "\n")
+	require.Equal(t, "digraph dgraph {", lines[0])
+	require.Equal(t, []string{
+		"  \"0x1\" [\"name\"=\"pho\ton\"];",
+		"  \"0x2\" [\"name@en\"=\"pho\ton\"];",
+		`  "0x3" ["name"="First Line\nSecondLine"];`,
+		`  "0x4";`,
+		`  "0x5" ["name"=""];`,
+		"  \"0x6\" [\"name\"=\"Ding!\aDing!\aDing!\a\"];",
+	}, lines[1:7])
+	// The edges are written in the order in which they were read.
+	edges := lines[7:11]
+	sort.Strings(edges)
+	require.Equal(t, []string{
+		`  "0x1" -> "0x5" ["label"="friend"];`,
+		`  "0x2" -> "0x5" ["label"="friend"];`,
+		`  "0x3" -> "0x5" ["label"="friend"];`,
+		`  "0x4" -> "0x5" ["label"="friend", "age"="33", "close"="true", "game"="football", ` +
+			`"poem"="roses are red\nviolets are blue", "since"="2005-05-02T15:04:05Z"];`,
+	}, edges)
+	require.Equal(t, []string{"}", ""}, lines[11:])
+
+	checkExportSchema(t, schemaFileList)
+}
+
 func TestExportFormat(t *testing.T) {
 	tmpdir, err := ioutil.TempDir("", "export")
 	require.NoError(t, err)
@@ -421,6 +474,25 @@ func TestToSchema(t *testing.T) {
 			},
 			expected: "<B*-tree>:[uid] @reverse . \n",
 		},
+		{
+			skv: &skv{
+				attr: "friend",
+				schema: pb.SchemaUpdate{
+					ValueType: pb.Posting_UID,
+					List:      true,
+					Facets: []*pb.SchemaUpdate{
+						{
+							Predicate: "since",
+							ValueType: pb.Posting_DATETIME,
+							Directive: pb.SchemaUpdate_INDEX,
+							Tokenizer: []string{"year"},
+						},
+						{Predicate: "weight", ValueType: pb.Posting_FLOAT},
+					},
+				},
+			},
+			expected: "<friend>:[uid] @facets(since: datetime @index(year), weight: float) . \n",
+		},
 		{
 			skv: &skv{
 				attr: "base_de_données",
@@ -466,6 +538,31 @@ func TestToSchema(t *testing.T) {
 			},
 			expected: "<data.base>:string @lang . \n",
 		},
+		{
+			skv: &skv{
+				attr: "views",
+				schema: pb.SchemaUpdate{
+					Predicate: "",
+					ValueType: pb.Posting_INT,
+					Directive: pb.SchemaUpdate_NONE,
+					Conflict:  "predicate",
+				},
+			},
+			expected: "<views>:int @conflict(predicate) . \n",
+		},
+		{
+			skv: &skv{
+				attr: "event",
+				schema: pb.SchemaUpdate{
+					Predicate:  "",
+					ValueType:  pb.Posting_UID,
+					Directive:  pb.SchemaUpdate_NONE,
+					List:       true,
+					AppendOnly: true,
+				},
+			},
+			expected: "<event>:[uid] @appendonly . \n",
+		},
 	}
 	for _, testCase := range testCases {
 		list, err := toSchema(testCase.skv.attr, testCase.skv.schema)
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// facetIndexUids returns the nodes having an edge which may match the facet filter, read from
+// the facet index of the predicate, or nil if the index can't tell. Only eq on an indexed
+// facet, alone or under an and, is looked up. The other nodes have no matching edge.
+func (qs *queryState) facetIndexUids(ctx context.Context, q *pb.Query,
+	ftree *facetsTree) (*pb.List, error) {
+	if ftree == nil {
+		return nil, nil
+	}
+	if strings.ToLower(ftree.op) == "and" {
+		for _, c := range ftree.children {
+			if uids, err := qs.facetIndexUids(ctx, q, c); err != nil || uids != nil {
+				return uids, err
+			}
+		}
+		return nil, nil
+	}
+	fn := ftree.function
+	if fn == nil || strings.ToLower(fn.name) != "eq" {
+		return nil, nil
+	}
+	var decl *pb.SchemaUpdate
+	for _, d := range schema.State().IndexedFacets(q.Attr) {
+		if d.Predicate == fn.key {
+			decl = d
+			break
+		}
+	}
+	if decl == nil || indexBuildError(q.Attr, "facets") != nil {
+		// An index being built isn't complete yet.
+		return nil, nil
+	}
+
+	// The values of the facet are converted to its type when they're set.
+	val, err := types.Convert(fn.val, types.TypeID(decl.ValueType))
+	if err != nil {
+		return nil, nil
+	}
+	tokenizer, ok := tok.GetTokenizer(decl.Tokenizer[0])
+	if !ok {
+		return nil, nil
+	}
+	tokens, err := tok.BuildTokens(val.Value, tokenizer)
+	if err != nil || len(tokens) == 0 {
+		return nil, nil
+	}
+	// The edges with an equal value have the same tokens, the first is enough to find them.
+	pl, err := qs.cache.Get(x.IndexKey(q.Attr, tok.FacetToken(decl.Predicate, tokens[0])))
+	if err != nil {
+		return nil, err
+	}
+	countRead(ctx, pl)
+	return pl.Uids(posting.ListOptions{ReadTs: q.ReadTs})
+}
@@ -0,0 +1,59 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types/facets"
+)
+
+// validateFacetOps checks that the operators of the facets of the edge can be applied, see
+// facets.ApplyOps.
+func validateFacetOps(edge *pb.DirectedEdge, su *pb.SchemaUpdate) error {
+	switch {
+	case edge.Op == pb.DirectedEdge_DEL:
+		return errors.Errorf("Facet operators can't be used to delete edges of predicate %s",
+			edge.Attr)
+	case su.Conflict == "none":
+		// Concurrent operations wouldn't conflict, so one of the updates could get lost.
+		return errors.Errorf("Facet operators can't be used on predicate %s with @conflict(none)",
+			edge.Attr)
+	}
+	return nil
+}
+
+// applyFacetOps returns the edge with the facets which result from applying the operators of
+// its facets to the facets of the edge in the posting list. The posting list must have been read
+// with txn.Get, so that concurrent operations on the same edge conflict.
+func applyFacetOps(edge *pb.DirectedEdge, plist *posting.List,
+	readTs uint64) (*pb.DirectedEdge, error) {
+	cur, err := plist.EdgeFacets(readTs, edge)
+	if err != nil {
+		return nil, err
+	}
+	fs, err := facets.ApplyOps(cur, edge.Facets)
+	if err != nil {
+		return nil, errors.Wrapf(err, "while updating the facets of predicate %s of node %#x",
+			edge.Attr, edge.Entity)
+	}
+	out := *edge
+	out.Facets = fs
+	return &out, nil
+}
@@ -0,0 +1,231 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// Directions of the degree of a Supernode.
+const (
+	DegreeOut = "out"
+	DegreeIn  = "in"
+)
+
+// GraphStats are the statistics of the nodes and edges of the cluster.
+type GraphStats struct {
+	ReadTs uint64 `json:"read_ts"`
+	// Nodes is the number of nodes with any predicate.
+	Nodes      uint64            `json:"nodes"`
+	Types      map[string]uint64 `json:"types"`
+	Predicates []*PredicateStats `json:"predicates"`
+	// Supernodes are the nodes with the largest degrees above the threshold, largest first.
+	Supernodes []*Supernode `json:"supernodes"`
+}
+
+// PredicateStats are the statistics of a predicate. The degrees are only given for the uid
+// predicates.
+type PredicateStats struct {
+	Predicate string `json:"predicate"`
+	Type      string `json:"type"`
+	// Nodes is the number of nodes with the predicate.
+	Nodes uint64       `json:"nodes"`
+	Out   *DegreeStats `json:"out,omitempty"`
+	In    *DegreeStats `json:"in,omitempty"`
+}
+
+// DegreeStats is the distribution of the degrees of the nodes with at least one edge of a
+// predicate, in buckets of powers of two.
+type DegreeStats struct {
+	Nodes   uint64          `json:"nodes"`
+	Edges   uint64          `json:"edges"`
+	Max     uint64          `json:"max"`
+	Mean    float64         `json:"mean"`
+	Buckets []*DegreeBucket `json:"buckets"`
+}
+
+// DegreeBucket is the number of nodes whose degree is between Min and Max.
+type DegreeBucket struct {
+	Min   uint64 `json:"min"`
+	Max   uint64 `json:"max"`
+	Count uint64 `json:"count"`
+}
+
+// Supernode is a node with a degree of a predicate above the threshold.
+type Supernode struct {
+	Uid       string `json:"uid"`
+	Predicate string `json:"predicate"`
+	Direction string `json:"direction"`
+	Degree    uint64 `json:"degree"`
+}
+
+type degreeHistogram struct {
+	buckets [65]uint64
+	nodes   uint64
+	edges   uint64
+	max     uint64
+}
+
+func (h *degreeHistogram) add(degree uint64) {
+	if degree == 0 {
+		return
+	}
+	h.buckets[bits.Len64(degree)]++
+	h.nodes++
+	h.edges += degree
+	if degree > h.max {
+		h.max = degree
+	}
+}
+
+func (h *degreeHistogram) stats() *DegreeStats {
+	s := &DegreeStats{Nodes: h.nodes, Edges: h.edges, Max: h.max, Buckets: []*DegreeBucket{}}
+	if h.nodes > 0 {
+		s.Mean = float64(h.edges) / float64(h.nodes)
+	}
+	for i := 1; i < len(h.buckets); i++ {
+		if h.buckets[i] == 0 {
+			continue
+		}
+		s.Buckets = append(s.Buckets, &DegreeBucket{
+			Min:   1 << uint(i-1),
+			Max:   1<<uint(i) - 1,
+			Count: h.buckets[i],
+		})
+	}
+	return s
+}
+
+// supernodes keeps the limit nodes with the largest degrees above the threshold.
+type supernodes struct {
+	threshold uint64
+	limit     int
+	nodes     []*Supernode
+}
+
+func (s *supernodes) add(uid uint64, pred, dir string, degree uint64) {
+	if degree < s.threshold || s.limit <= 0 {
+		return
+	}
+	s.nodes = append(s.nodes, &Supernode{
+		Uid:       fmt.Sprintf("%#x", uid),
+		Predicate: pred,
+		Direction: dir,
+		Degree:    degree,
+	})
+	if len(s.nodes) >= 2*s.limit {
+		s.truncate()
+	}
+}
+
+func (s *supernodes) truncate() {
+	sort.SliceStable(s.nodes, func(i, j int) bool { return s.nodes[i].Degree > s.nodes[j].Degree })
+	if len(s.nodes) > s.limit {
+		s.nodes = s.nodes[:s.limit]
+	}
+}
+
+// ComputeGraphStats reads all the predicates of the cluster at the same timestamp, and returns
+// the distributions of the degrees of the uid predicates, the number of nodes of each type, and
+// at most limit supernodes with a degree of at least threshold. The uids of the nodes, and the
+// in-degrees of the nodes of one predicate at a time, are kept in memory while it runs.
+func ComputeGraphStats(ctx context.Context, threshold uint64, limit int) (*GraphStats, error) {
+	ts, err := Timestamps(ctx, &pb.Num{ReadOnly: true})
+	if err != nil {
+		return nil, err
+	}
+	readTs := ts.ReadOnly
+	schema, err := GetSchemaOverNetwork(ctx, &pb.SchemaRequest{Fields: []string{"type"}})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &GraphStats{
+		ReadTs:     readTs,
+		Types:      make(map[string]uint64),
+		Predicates: []*PredicateStats{},
+	}
+	super := &supernodes{threshold: threshold, limit: limit}
+	nodes := &pb.List{}
+	for _, s := range schema {
+		subjects, err := hasUids(ctx, s.Predicate, readTs)
+		if err != nil {
+			return nil, err
+		}
+		nodes = algo.MergeSorted([]*pb.List{nodes, subjects})
+		ps := &PredicateStats{
+			Predicate: s.Predicate,
+			Type:      s.Type,
+			Nodes:     uint64(len(subjects.Uids)),
+		}
+		stats.Predicates = append(stats.Predicates, ps)
+
+		switch {
+		case s.Type == "uid":
+			var out, in degreeHistogram
+			inDegrees := make(map[uint64]uint64)
+			err = forEachUidEdges(ctx, s.Predicate, subjects, readTs,
+				func(subjects *pb.List, objects []*pb.List) error {
+					for i, uid := range subjects.Uids {
+						degree := uint64(len(objects[i].Uids))
+						out.add(degree)
+						super.add(uid, s.Predicate, DegreeOut, degree)
+						for _, obj := range objects[i].Uids {
+							inDegrees[obj]++
+						}
+					}
+					return nil
+				})
+			if err != nil {
+				return nil, err
+			}
+			for uid, degree := range inDegrees {
+				in.add(degree)
+				super.add(uid, s.Predicate, DegreeIn, degree)
+			}
+			ps.Out, ps.In = out.stats(), in.stats()
+
+		case s.Predicate == "dgraph.type":
+			err = forEachBatch(ctx, s.Predicate, subjects, readTs,
+				func(subjects *pb.List, reply *pb.Result) error {
+					for _, vals := range reply.ValueMatrix {
+						for _, v := range vals.Values {
+							stats.Types[string(v.Val)]++
+						}
+					}
+					return nil
+				})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	stats.Nodes = uint64(len(nodes.Uids))
+	super.truncate()
+	stats.Supernodes = super.nodes
+	if stats.Supernodes == nil {
+		stats.Supernodes = []*Supernode{}
+	}
+	return stats, nil
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDegreeHistogram(t *testing.T) {
+	var h degreeHistogram
+	for _, d := range []uint64{0, 1, 1, 2, 3, 4, 10} {
+		h.add(d)
+	}
+	require.Equal(t, &DegreeStats{
+		Nodes: 6,
+		Edges: 21,
+		Max:   10,
+		Mean:  3.5,
+		Buckets: []*DegreeBucket{
+			{Min: 1, Max: 1, Count: 2},
+			{Min: 2, Max: 3, Count: 2},
+			{Min: 4, Max: 7, Count: 1},
+			{Min: 8, Max: 15, Count: 1},
+		},
+	}, h.stats())
+
+	var empty degreeHistogram
+	require.Equal(t, &DegreeStats{Buckets: []*DegreeBucket{}}, empty.stats())
+}
+
+func TestSupernodes(t *testing.T) {
+	s := &supernodes{threshold: 10, limit: 2}
+	for uid := uint64(1); uid <= 20; uid++ {
+		s.add(uid, "friend", DegreeOut, uid)
+	}
+	s.add(0x100, "friend", DegreeIn, 100)
+	s.truncate()
+	require.Equal(t, []*Supernode{
+		{Uid: "0x100", Predicate: "friend", Direction: DegreeIn, Degree: 100},
+		{Uid: "0x14", Predicate: "friend", Direction: DegreeOut, Degree: 20},
+	}, s.nodes)
+}
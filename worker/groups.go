@@ -30,6 +30,7 @@ import (
 	"github.com/dgraph-io/badger/y"
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgraph/conn"
+	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/raftwal"
 	"github.com/dgraph-io/dgraph/schema"
@@ -100,7 +101,11 @@ func StartRaftNodes(walStore *badger.DB, bindall bool) {
 	// Successfully connect with dgraphzero, before doing anything else.
 
 	// Connect with Zero leader and figure out what group we should belong to.
-	m := &pb.Member{Id: x.WorkerConfig.RaftId, Addr: x.WorkerConfig.MyAddr}
+	m := &pb.Member{
+		Id:             x.WorkerConfig.RaftId,
+		Addr:           x.WorkerConfig.MyAddr,
+		FeatureVersion: x.FeatureVersion,
+	}
 	var connState *pb.ConnectionState
 	var err error
 	for { // Keep on retrying. See: https://github.com/dgraph-io/dgraph/issues/2289
@@ -135,6 +140,7 @@ func StartRaftNodes(walStore *badger.DB, bindall bool) {
 	gr.Node = newNode(store, gid, x.WorkerConfig.RaftId, x.WorkerConfig.MyAddr)
 
 	x.Checkf(schema.LoadFromDb(), "Error while initializing schema")
+	x.Checkf(resumeIndexBuilds(gr.ctx), "Error while resuming the index builds")
 	raftServer.UpdateNode(gr.Node.Node)
 	gr.Node.InitAndStartNode()
 	x.UpdateHealthStatus(true)
@@ -332,6 +338,42 @@ func (g *groupi) ServesGroup(gid uint32) bool {
 	return g.groupId() == gid
 }
 
+// FeatureEnabled returns whether Zero has enabled the features of the version v, i.e. all the
+// members of the cluster support them.
+func (g *groupi) FeatureEnabled(v uint32) bool {
+	g.RLock()
+	defer g.RUnlock()
+	return g.state != nil && g.state.FeatureVersion >= v
+}
+
+// FrozenMode returns the mode the predicate is frozen in by Zero, or 0 if it isn't frozen.
+func (g *groupi) FrozenMode(pred string) uint32 {
+	g.RLock()
+	defer g.RUnlock()
+	if g.state == nil {
+		return 0
+	}
+	return g.state.FrozenPredicates[pred]
+}
+
+// checkWritable returns an error if the predicate is frozen by Zero, whatever the mode.
+func (g *groupi) checkWritable(pred string) error {
+	if mode := g.FrozenMode(pred); mode != 0 {
+		return errors.Errorf("Predicate %s is %s for maintenance, its mutations are rejected",
+			pred, x.FreezeModeName(mode))
+	}
+	return nil
+}
+
+// checkReadable returns an error if the predicate is fully frozen by Zero.
+func (g *groupi) checkReadable(pred string) error {
+	if g.FrozenMode(pred) == x.PredicateFrozen {
+		return errors.Errorf("Predicate %s is frozen for maintenance, its queries are rejected",
+			pred)
+	}
+	return nil
+}
+
 func (g *groupi) ChecksumsMatch(ctx context.Context) error {
 	if atomic.LoadUint64(&g.deltaChecksum) == atomic.LoadUint64(&g.membershipChecksum) {
 		return nil
@@ -560,6 +602,37 @@ func KnownGroups() []uint32 {
 	return groups().KnownGroups()
 }
 
+// RemotePredicates returns which of the predicates are served by another group than the one of
+// this alpha, so that reading them takes a call over the network.
+func RemotePredicates(preds []string) (map[string]bool, error) {
+	g := groups()
+	remote := make(map[string]bool, len(preds))
+	for _, pred := range preds {
+		gid, err := g.BelongsToReadOnly(pred)
+		if err != nil {
+			return nil, err
+		}
+		remote[pred] = gid != 0 && gid != g.groupId()
+	}
+	return remote, nil
+}
+
+// PredicateVersion returns a version of the predicate as read at readTs, which changes whenever
+// a commit to it becomes visible. It's the commit ts of its last write up to readTs, or readTs
+// itself if the last write is newer. All the commits up to readTs must have been applied.
+// Predicates that another group serves, or that aren't known yet, use readTs.
+func PredicateVersion(pred string, readTs uint64) uint64 {
+	g := groups()
+	g.RLock()
+	tablet := g.tablets[pred]
+	g.RUnlock()
+
+	if tablet == nil || tablet.GetGroupId() != g.groupId() {
+		return readTs
+	}
+	return x.Min(posting.PredicateCommitTs(pred), readTs)
+}
+
 func (g *groupi) triggerMembershipSync() {
 	// It's ok if we miss the trigger, periodic membership sync runs every minute.
 	select {
@@ -623,11 +696,12 @@ func (g *groupi) connToZeroLeader() *conn.Pool {
 func (g *groupi) doSendMembership(tablets map[string]*pb.Tablet) error {
 	leader := g.Node.AmLeader()
 	member := &pb.Member{
-		Id:         x.WorkerConfig.RaftId,
-		GroupId:    g.groupId(),
-		Addr:       x.WorkerConfig.MyAddr,
-		Leader:     leader,
-		LastUpdate: uint64(time.Now().Unix()),
+		Id:             x.WorkerConfig.RaftId,
+		GroupId:        g.groupId(),
+		Addr:           x.WorkerConfig.MyAddr,
+		Leader:         leader,
+		LastUpdate:     uint64(time.Now().Unix()),
+		FeatureVersion: x.FeatureVersion,
 	}
 	group := &pb.Group{
 		Members: make(map[uint64]*pb.Member),
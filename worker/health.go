@@ -0,0 +1,66 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+// RaftHealth is the state of the Raft group of this Alpha and of its connection to Zero, as
+// reported by the readiness probe.
+type RaftHealth struct {
+	GroupId             uint32 `json:"group_id"`
+	Leader              bool   `json:"leader"`
+	LeaderId            uint64 `json:"leader_id"`
+	CommittedIndex      uint64 `json:"committed_index"`
+	AppliedIndex        uint64 `json:"applied_index"`
+	PendingProposals    int    `json:"pending_proposals"`
+	MaxPendingProposals int    `json:"max_pending_proposals"`
+	ZeroConnected       bool   `json:"zero_connected"`
+}
+
+// ApplyLag returns the number of committed Raft entries not applied yet.
+func (h *RaftHealth) ApplyLag() uint64 {
+	if h.CommittedIndex < h.AppliedIndex {
+		return 0
+	}
+	return h.CommittedIndex - h.AppliedIndex
+}
+
+// Health returns the state of the Raft group of this Alpha, or nil if the Alpha hasn't joined
+// its group yet.
+func Health() *RaftHealth {
+	g := groups()
+	if g == nil || g.Node == nil {
+		return nil
+	}
+	n := g.Node
+	r := n.Raft()
+	if r == nil {
+		return nil
+	}
+	status := r.Status()
+	h := &RaftHealth{
+		GroupId:             g.groupId(),
+		Leader:              status.Lead == status.ID,
+		LeaderId:            status.Lead,
+		CommittedIndex:      status.Commit,
+		AppliedIndex:        n.Applied.DoneUntil(),
+		PendingProposals:    len(pendingProposals),
+		MaxPendingProposals: cap(pendingProposals),
+	}
+	if pl := g.Leader(0); pl != nil {
+		h.ZeroConnected = pl.IsHealthy()
+	}
+	return h
+}
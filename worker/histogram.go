@@ -0,0 +1,218 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sort"
+
+	"github.com/dgraph-io/badger"
+	"github.com/pkg/errors"
+	otrace "go.opencensus.io/trace"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/tok"
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// histogramBounds converts the boundaries of the buckets of a histogram to the type of the
+// predicate, which must be an int, a float or a datetime, and checks that they're increasing.
+func histogramBounds(attr string, args []string) ([]types.Val, error) {
+	typ, err := schema.State().TypeOf(attr)
+	if err != nil {
+		return nil, err
+	}
+	switch typ {
+	case types.IntID, types.FloatID, types.DateTimeID:
+	default:
+		return nil, errors.Errorf("histogram only supports int, float and datetime predicates,"+
+			" %s is of type %s", attr, typ.Name())
+	}
+	if len(args) < 2 {
+		return nil, errors.Errorf("histogram needs at least two bucket boundaries")
+	}
+
+	bounds := make([]types.Val, 0, len(args))
+	for _, arg := range args {
+		v, err := convertValue(attr, arg)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(bounds); n > 0 {
+			if less, err := types.Less(bounds[n-1], v); err != nil || !less {
+				return nil, errors.Errorf("The bucket boundaries of histogram must be increasing")
+			}
+		}
+		bounds = append(bounds, v)
+	}
+	return bounds, nil
+}
+
+// bucketOf returns the index of the bucket of the histogram v falls in, or -1 if it's in none.
+// Each bucket holds the values from its lower boundary, up to its upper boundary excluded.
+func bucketOf(v types.Val, bounds []types.Val) int {
+	i := sort.Search(len(bounds), func(i int) bool {
+		less, err := types.Less(v, bounds[i])
+		return err == nil && less
+	})
+	if i == 0 || i == len(bounds) {
+		return -1
+	}
+	return i - 1
+}
+
+// histogram returns the number of values of the predicate of the nodes of the query which fall
+// in each bucket between consecutive boundaries. The values of list predicates are each counted.
+func histogram(ctx context.Context, q *pb.Query, bounds []types.Val) ([]uint32, error) {
+	span := otrace.FromContext(ctx)
+	stop := x.SpanTimer(span, "histogram: "+q.Attr)
+	defer stop()
+
+	if !histogramIndexed(q.Attr, bounds) {
+		return histogramWithoutIndex(ctx, q, bounds)
+	}
+
+	// Like for sorting, the counts are taken both from the index and from the values of the
+	// nodes, and the first to be done is returned. The index is faster for many nodes, and the
+	// values for a few.
+	cctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		counts []uint32
+		err    error
+	}
+	resCh := make(chan result, 2)
+	go func() {
+		counts, err := histogramWithIndex(cctx, q, bounds)
+		resCh <- result{counts: counts, err: err}
+	}()
+	go func() {
+		counts, err := histogramWithoutIndex(cctx, q, bounds)
+		resCh <- result{counts: counts, err: err}
+	}()
+
+	r := <-resCh
+	if r.err != nil {
+		r = <-resCh
+	}
+	return r.counts, r.err
+}
+
+// histogramIndexed tells if the counts of the histogram can be taken from the index of the
+// predicate. That's the case of the int index of an int predicate, whose tokens are the values.
+func histogramIndexed(attr string, bounds []types.Val) bool {
+	if bounds[0].Tid != types.IntID || !schema.State().IsIndexed(attr) {
+		return false
+	}
+	for _, t := range schema.State().Tokenizer(attr) {
+		if t.Identifier() == tok.IdentInt {
+			return true
+		}
+	}
+	return false
+}
+
+// histogramWithIndex counts the nodes of the query in the index buckets of the values between
+// the first and the last boundaries.
+func histogramWithIndex(ctx context.Context, q *pb.Query, bounds []types.Val) ([]uint32, error) {
+	tokens := make([]string, len(bounds))
+	for i, b := range bounds {
+		toks, err := tok.BuildTokens(b.Value, tok.IntTokenizer{})
+		if err != nil {
+			return nil, err
+		}
+		tokens[i] = toks[0]
+	}
+
+	txn := pstore.NewTransactionAt(q.ReadTs, false)
+	defer txn.Discard()
+
+	itOpt := badger.DefaultIteratorOptions
+	itOpt.PrefetchValues = false
+	itOpt.Prefix = x.IndexKey(q.Attr, string(tok.IntTokenizer{}.Identifier()))
+	it := txn.NewIterator(itOpt)
+	defer it.Close()
+
+	counts := make([]uint32, len(bounds)-1)
+	var b int
+	for it.Seek(x.IndexKey(q.Attr, tokens[0])); it.Valid(); it.Next() {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+
+		key := it.Item().KeyCopy(nil)
+		k := x.Parse(key)
+		if k == nil {
+			continue
+		}
+		for b < len(counts) && k.Term >= tokens[b+1] {
+			b++
+		}
+		if b == len(counts) {
+			break
+		}
+		pl, err := posting.GetNoStore(key)
+		if err != nil {
+			return nil, err
+		}
+		uids, err := pl.Uids(posting.ListOptions{ReadTs: q.ReadTs, Intersect: q.UidList})
+		if err != nil {
+			return nil, err
+		}
+		counts[b] += uint32(len(uids.Uids))
+	}
+	return counts, nil
+}
+
+// histogramWithoutIndex reads the values of the nodes of the query to count them.
+func histogramWithoutIndex(ctx context.Context, q *pb.Query,
+	bounds []types.Val) ([]uint32, error) {
+	counts := make([]uint32, len(bounds)-1)
+	for i, uid := range q.UidList.GetUids() {
+		if i%1000 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+
+		pl, err := posting.GetNoStore(x.DataKey(q.Attr, uid))
+		if err != nil {
+			return nil, err
+		}
+		vals, err := pl.AllValues(q.ReadTs)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vals {
+			sv, err := types.Convert(v, bounds[0].Tid)
+			if err != nil {
+				continue
+			}
+			if b := bucketOf(sv, bounds); b >= 0 {
+				counts[b]++
+			}
+		}
+	}
+	return counts, nil
+}
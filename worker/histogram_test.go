@@ -0,0 +1,66 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+func TestHistogramBounds(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte("score: float .\nlabel: string ."), 1))
+	bounds, err := histogramBounds("score", []string{"-1", "0.5", "10"})
+	require.NoError(t, err)
+	require.Equal(t, []types.Val{
+		{Tid: types.FloatID, Value: -1.0},
+		{Tid: types.FloatID, Value: 0.5},
+		{Tid: types.FloatID, Value: 10.0}}, bounds)
+
+	for _, args := range [][]string{{"1"}, {"1", "1"}, {"2", "1"}, {"1", "x"}} {
+		_, err := histogramBounds("score", args)
+		require.Error(t, err, args)
+	}
+	_, err = histogramBounds("label", []string{"a", "b"})
+	require.Error(t, err)
+
+	q := &pb.Query{Attr: "score", UidList: &pb.List{Uids: []uint64{1}},
+		SrcFunc: &pb.SrcFunction{Name: "histogram", Args: []string{"0", "1"}}}
+	fc, err := parseSrcFn(q)
+	require.NoError(t, err)
+	require.Equal(t, histogramFn, fc.fnType)
+	require.Len(t, fc.bounds, 2)
+
+	// Histograms count the values of the nodes of a block, so they aren't allowed at root.
+	q.UidList = nil
+	_, err = parseSrcFn(q)
+	require.Error(t, err)
+}
+
+func TestBucketOf(t *testing.T) {
+	bounds := []types.Val{
+		{Tid: types.IntID, Value: int64(0)},
+		{Tid: types.IntID, Value: int64(18)},
+		{Tid: types.IntID, Value: int64(65)}}
+	for v, bucket := range map[int64]int{-1: -1, 0: 0, 17: 0, 18: 1, 64: 1, 65: -1, 100: -1} {
+		require.Equal(t, bucket, bucketOf(types.Val{Tid: types.IntID, Value: v}, bounds), v)
+	}
+}
@@ -0,0 +1,556 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dgraph-io/badger"
+	"github.com/golang/glog"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// Statuses of the index builds.
+const (
+	indexBuildQueued  = "queued"
+	indexBuildRunning = "running"
+	indexBuildPaused  = "paused"
+	indexBuildDone    = "done"
+	indexBuildFailed  = "failed"
+)
+
+// maxFinishedIndexBuilds is the number of finished builds kept to be listed.
+const maxFinishedIndexBuilds = 20
+
+// IndexBuild is the status of the background build of the indices of a predicate, after a
+// schema update changed them.
+type IndexBuild struct {
+	Predicate string `json:"predicate"`
+	// Indices are the indices being built: the tokenizers of the index, reverse or count.
+	Indices   []string  `json:"indices"`
+	Status    string    `json:"status"`
+	StartTs   uint64    `json:"start_ts"`
+	KeysDone  uint64    `json:"keys_done"`
+	KeysTotal uint64    `json:"keys_total"`
+	Started   time.Time `json:"started,omitempty"`
+	Finished  time.Time `json:"finished,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// indexJob runs the builds of the indices of a predicate. Its fields are guarded by
+// indexJobs, except for keysDone.
+type indexJob struct {
+	status   IndexBuild
+	keysDone uint64
+
+	// update is the schema of the predicate, persisted without IndexBase once the builds are done.
+	update pb.SchemaUpdate
+	builds []*posting.IndexBuild
+	cancel context.CancelFunc
+	done   chan struct{}
+	// resume is closed when a paused job is resumed. It's nil unless the job is paused.
+	resume chan struct{}
+	// next is the time at which the next posting list can be read, to throttle the builds.
+	next time.Time
+	// persisted tells if the schema was persisted without IndexBase once the builds were done.
+	persisted bool
+}
+
+var indexJobs = struct {
+	sync.Mutex
+	// active are the jobs whose builds haven't completed, by predicate. Failed jobs stay there
+	// until another schema update supersedes them.
+	active map[string]*indexJob
+	// persisting are the jobs whose builds completed, by predicate, until their schema is
+	// persisted. The indices can be read, but a schema update still stops the job first.
+	persisting map[string]*indexJob
+	finished   []*indexJob
+	// running allows one build to run at once.
+	running chan struct{}
+}{
+	active:     make(map[string]*indexJob),
+	persisting: make(map[string]*indexJob),
+	running:    make(chan struct{}, 1),
+}
+
+// startIndexBuilds runs the builds of the indices of the predicate in the background. update is
+// the schema of the predicate, which is persisted without its IndexBase once they're done.
+func startIndexBuilds(update *pb.SchemaUpdate, builds []*posting.IndexBuild, startTs uint64) {
+	ctx, cancel := context.WithCancel(context.Background())
+	j := &indexJob{
+		status: IndexBuild{
+			Predicate: update.Predicate,
+			Status:    indexBuildQueued,
+			StartTs:   startTs,
+		},
+		update: *update,
+		builds: builds,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	for _, b := range builds {
+		b.Throttle = j.throttle
+		if b.Kind == "index" {
+			j.status.Indices = append(j.status.Indices, b.Tokenizers...)
+		} else {
+			j.status.Indices = append(j.status.Indices, b.Kind)
+		}
+	}
+
+	indexJobs.Lock()
+	indexJobs.active[update.Predicate] = j
+	indexJobs.Unlock()
+
+	glog.Infof("Queued the build of indices %v of predicate %s at ts %d",
+		j.status.Indices, update.Predicate, startTs)
+	go j.run(ctx)
+}
+
+func (j *indexJob) run(ctx context.Context) {
+	defer close(j.done)
+
+	select {
+	case indexJobs.running <- struct{}{}:
+		defer func() { <-indexJobs.running }()
+	case <-ctx.Done():
+		return
+	}
+
+	err := j.build(ctx)
+	if err != nil && ctx.Err() != nil {
+		// The job was stopped, and removed from the active jobs by stopIndexBuilds.
+		return
+	}
+
+	indexJobs.Lock()
+	j.status.Finished = time.Now()
+	if err != nil {
+		glog.Errorf("While building indices %v of predicate %s: %v",
+			j.status.Indices, j.status.Predicate, err)
+		j.status.Status = indexBuildFailed
+		j.status.Error = err.Error()
+		indexJobs.Unlock()
+		return
+	}
+	glog.Infof("Done building indices %v of predicate %s in %s", j.status.Indices,
+		j.status.Predicate, j.status.Finished.Sub(j.status.Started).Round(time.Millisecond))
+	j.status.Status = indexBuildDone
+	// The indices are complete, they can be read while the schema is persisted.
+	if indexJobs.active[j.status.Predicate] == j {
+		delete(indexJobs.active, j.status.Predicate)
+		indexJobs.persisting[j.status.Predicate] = j
+	}
+	indexJobs.finished = append(indexJobs.finished, j)
+	if len(indexJobs.finished) > maxFinishedIndexBuilds {
+		indexJobs.finished = indexJobs.finished[1:]
+	}
+	indexJobs.Unlock()
+
+	err = j.persist(ctx)
+	if err != nil && ctx.Err() == nil {
+		glog.Errorf("While persisting the schema of predicate %s after building indices %v: %v",
+			j.status.Predicate, j.status.Indices, err)
+	}
+	indexJobs.Lock()
+	j.persisted = err == nil
+	if indexJobs.persisting[j.status.Predicate] == j {
+		delete(indexJobs.persisting, j.status.Predicate)
+	}
+	indexJobs.Unlock()
+}
+
+func (j *indexJob) build(ctx context.Context) error {
+	indexJobs.Lock()
+	if j.status.Status == indexBuildQueued {
+		j.status.Status = indexBuildRunning
+	}
+	j.status.Started = time.Now()
+	indexJobs.Unlock()
+
+	// The builds read the data at startTs, so the commits before it must have been applied.
+	if err := posting.Oracle().WaitForTs(ctx, j.status.StartTs); err != nil {
+		return err
+	}
+	var total uint64
+	for _, b := range j.builds {
+		n, err := b.CountKeys()
+		if err != nil {
+			return err
+		}
+		total += n
+	}
+	indexJobs.Lock()
+	j.status.KeysTotal = total
+	indexJobs.Unlock()
+
+	for _, b := range j.builds {
+		if err := b.Run(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// persist persists the schema of the predicate without its IndexBase once the builds are done,
+// so that they aren't resumed when the Alpha restarts.
+func (j *indexJob) persist(ctx context.Context) error {
+	j.update.IndexBase = nil
+	// Like the entries of the builds, the schema is written again if the writes are blocked by
+	// the schema update of another predicate.
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		err := updateSchema(&j.update)
+		if errors.Cause(err) != badger.ErrBlockedWrites {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// throttle is called by the builds before reading each posting list. It blocks while the job
+// is paused, and keeps the builds under x.Config.IndexBuildRate posting lists per second.
+func (j *indexJob) throttle(ctx context.Context) error {
+	atomic.AddUint64(&j.keysDone, 1)
+
+	for {
+		indexJobs.Lock()
+		resume := j.resume
+		indexJobs.Unlock()
+		if resume == nil {
+			break
+		}
+		select {
+		case <-resume:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	x.ConfigMu.RLock()
+	rate := x.Config.IndexBuildRate
+	x.ConfigMu.RUnlock()
+	if rate == 0 {
+		return nil
+	}
+
+	indexJobs.Lock()
+	now := time.Now()
+	if j.next.Before(now) {
+		j.next = now
+	}
+	wait := j.next.Sub(now)
+	j.next = j.next.Add(time.Second / time.Duration(rate))
+	indexJobs.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(wait)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// stopIndexBuilds stops the builds of the predicate, or of all the predicates if it's empty,
+// and waits until they're stopped. It returns the jobs which didn't complete, including the
+// ones whose builds are done but whose schema wasn't persisted.
+func stopIndexBuilds(pred string) []*indexJob {
+	indexJobs.Lock()
+	var jobs []*indexJob
+	for _, m := range []map[string]*indexJob{indexJobs.active, indexJobs.persisting} {
+		for attr, j := range m {
+			if pred != "" && attr != pred {
+				continue
+			}
+			delete(m, attr)
+			jobs = append(jobs, j)
+		}
+	}
+	indexJobs.Unlock()
+
+	var stopped []*indexJob
+	for _, j := range jobs {
+		j.cancel()
+		<-j.done
+		indexJobs.Lock()
+		if !j.persisted {
+			stopped = append(stopped, j)
+		}
+		indexJobs.Unlock()
+	}
+	return stopped
+}
+
+// dropIndexBuilds stops the builds of the predicate, or of all the predicates if it's empty,
+// before its data is dropped: its indices are empty once it is.
+func dropIndexBuilds(pred string) error {
+	for _, j := range stopIndexBuilds(pred) {
+		j.update.IndexBase = nil
+		if err := updateSchema(&j.update); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// completedIndices deletes what the stopped job built of the indices of the predicate, and
+// returns the schema of the predicate without the indices it didn't complete, so that a schema
+// update superseding the job builds them again.
+func (j *indexJob) completedIndices() (pb.SchemaUpdate, error) {
+	s := j.update
+	s.IndexBase = nil
+	for _, b := range j.builds {
+		if err := b.Delete(); err != nil {
+			return s, err
+		}
+		switch b.Kind {
+		case "reverse":
+			s.Directive = pb.SchemaUpdate_NONE
+		case "count":
+			s.Count = false
+		case "facets":
+			// The facets stay declared, without their indices.
+			fcs := make([]*pb.SchemaUpdate, 0, len(s.Facets))
+			for _, f := range s.Facets {
+				f := *f
+				f.Directive = pb.SchemaUpdate_NONE
+				f.Tokenizer = nil
+				fcs = append(fcs, &f)
+			}
+			s.Facets = fcs
+		default:
+			var tokenizers []string
+			for _, t := range s.Tokenizer {
+				if !x.HasString(b.Tokenizers, t) {
+					tokenizers = append(tokenizers, t)
+				}
+			}
+			s.Tokenizer = tokenizers
+		}
+	}
+	return s, nil
+}
+
+// indexBuildError returns an error if the index of the given kind of the predicate is being
+// built, so that queries don't read an incomplete index.
+func indexBuildError(attr, kind string) error {
+	indexJobs.Lock()
+	defer indexJobs.Unlock()
+	j, ok := indexJobs.active[attr]
+	if !ok {
+		return nil
+	}
+	for _, b := range j.builds {
+		if b.Kind != kind {
+			continue
+		}
+		if j.status.Status == indexBuildFailed {
+			return errors.Errorf("The build of the %s of predicate %s failed: %s. "+
+				"Update its schema to build it again.", kind, attr, j.status.Error)
+		}
+		var done uint64
+		if j.status.KeysTotal > 0 {
+			done = 100 * atomic.LoadUint64(&j.keysDone) / j.status.KeysTotal
+		}
+		return errors.Errorf("The %s of predicate %s is being built (%d%% done)", kind, attr, done)
+	}
+	return nil
+}
+
+// indexBuildsTs returns the lowest StartTs of the builds which haven't completed, or 0 if
+// there's none. The versions the builds read must be kept until they're done.
+func indexBuildsTs() uint64 {
+	indexJobs.Lock()
+	defer indexJobs.Unlock()
+	var ts uint64
+	for _, j := range indexJobs.active {
+		if ts == 0 || j.status.StartTs < ts {
+			ts = j.status.StartTs
+		}
+	}
+	return ts
+}
+
+// isIndexBuilding tells if the indices of the predicate are being built.
+func isIndexBuilding(attr string) bool {
+	indexJobs.Lock()
+	defer indexJobs.Unlock()
+	_, ok := indexJobs.active[attr]
+	return ok
+}
+
+// IndexBuilds returns the status of the index builds of this Alpha, the ones which haven't
+// completed first.
+func IndexBuilds() []IndexBuild {
+	indexJobs.Lock()
+	defer indexJobs.Unlock()
+	var builds []IndexBuild
+	for _, j := range indexJobs.active {
+		builds = append(builds, j.snapshot())
+	}
+	sort.Slice(builds, func(i, k int) bool { return builds[i].Predicate < builds[k].Predicate })
+	for i := len(indexJobs.finished) - 1; i >= 0; i-- {
+		builds = append(builds, indexJobs.finished[i].snapshot())
+	}
+	return builds
+}
+
+func (j *indexJob) snapshot() IndexBuild {
+	s := j.status
+	s.Indices = append([]string{}, j.status.Indices...)
+	s.KeysDone = atomic.LoadUint64(&j.keysDone)
+	if s.KeysTotal > 0 && s.KeysDone > s.KeysTotal {
+		s.KeysDone = s.KeysTotal
+	}
+	return s
+}
+
+// PauseIndexBuild pauses the build of the indices of the predicate on this Alpha.
+func PauseIndexBuild(pred string) error {
+	indexJobs.Lock()
+	defer indexJobs.Unlock()
+	j, ok := indexJobs.active[pred]
+	if !ok {
+		return errors.Errorf("No index of predicate %s is being built", pred)
+	}
+	switch j.status.Status {
+	case indexBuildPaused:
+		return nil
+	case indexBuildFailed:
+		return errors.Errorf("The build of the indices of predicate %s failed", pred)
+	}
+	j.resume = make(chan struct{})
+	j.status.Status = indexBuildPaused
+	glog.Infof("Paused the build of indices %v of predicate %s", j.status.Indices, pred)
+	return nil
+}
+
+// ResumeIndexBuild resumes the paused build of the indices of the predicate on this Alpha.
+func ResumeIndexBuild(pred string) error {
+	indexJobs.Lock()
+	defer indexJobs.Unlock()
+	j, ok := indexJobs.active[pred]
+	if !ok {
+		return errors.Errorf("No index of predicate %s is being built", pred)
+	}
+	if j.status.Status != indexBuildPaused {
+		return errors.Errorf("The build of the indices of predicate %s isn't paused", pred)
+	}
+	close(j.resume)
+	j.resume = nil
+	j.status.Status = indexBuildRunning
+	if j.status.Started.IsZero() {
+		j.status.Status = indexBuildQueued
+	}
+	glog.Infof("Resumed the build of indices %v of predicate %s", j.status.Indices, pred)
+	return nil
+}
+
+// CheckIndex compares the index and the reverse edges of the predicate with its data on this
+// Alpha, and repairs the differences if asked to.
+func CheckIndex(ctx context.Context, attr string, repair bool) (*posting.IndexReport, error) {
+	if ok, err := groups().ServesTabletReadOnly(attr); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, errors.Errorf("Predicate %s isn't served by this Alpha", attr)
+	}
+	if isIndexBuilding(attr) {
+		return nil, errors.Errorf("The indices of predicate %s are being built", attr)
+	}
+
+	report, err := posting.CheckIndex(ctx, attr, posting.Oracle().MaxAssigned())
+	if err != nil || !repair || report.NumMissing+report.NumOrphans == 0 {
+		return report, err
+	}
+	// The repair is written after the commits the check didn't see.
+	ts, err := Timestamps(ctx, &pb.Num{Val: 1})
+	if err != nil {
+		return nil, err
+	}
+	return report, report.Repair(ctx, ts.StartId)
+}
+
+// resumeIndexBuilds starts again the builds which didn't complete before the Alpha stopped.
+// Their indices are deleted and built from scratch, reading the data at a timestamp from Zero.
+func resumeIndexBuilds(ctx context.Context) error {
+	var preds []string
+	for _, pred := range schema.State().Predicates() {
+		if s, ok := schema.State().Get(pred); ok && s.IndexBase != nil {
+			preds = append(preds, pred)
+		}
+	}
+	if len(preds) == 0 {
+		return nil
+	}
+	sort.Strings(preds)
+
+	var startTs uint64
+	err := x.RetryUntilSuccess(10, time.Second, func() error {
+		ts, err := Timestamps(ctx, &pb.Num{Val: 1, ReadOnly: true})
+		if err == nil {
+			startTs = ts.ReadOnly
+		}
+		return err
+	})
+	if err != nil {
+		return errors.Wrapf(err, "while getting the timestamp of the index builds")
+	}
+
+	for _, pred := range preds {
+		current, _ := schema.State().Get(pred)
+		// The values were converted to a list before the builds started.
+		base := *current.IndexBase
+		base.List = current.List
+		rebuild := posting.IndexRebuild{
+			Attr:          pred,
+			StartTs:       startTs,
+			OldSchema:     &base,
+			CurrentSchema: &current,
+		}
+		builds, err := rebuild.Prepare(ctx)
+		if err != nil {
+			return errors.Wrapf(err, "while resuming the index builds of predicate %s", pred)
+		}
+		if len(builds) == 0 {
+			current.IndexBase = nil
+			if err := updateSchema(&current); err != nil {
+				return err
+			}
+			continue
+		}
+		startIndexBuilds(&current, builds, startTs)
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexBuildThrottle(t *testing.T) {
+	defer func(cfg x.Options) { x.Config = cfg }(x.Config)
+	j := &indexJob{
+		status: IndexBuild{
+			Predicate: "indexing",
+			Status:    indexBuildRunning,
+			KeysTotal: 4,
+			Started:   time.Now(),
+		},
+		builds: []*posting.IndexBuild{{Attr: "indexing", Kind: "index"}},
+		cancel: func() {},
+		done:   make(chan struct{}),
+	}
+	close(j.done)
+	indexJobs.Lock()
+	indexJobs.active["indexing"] = j
+	indexJobs.Unlock()
+	defer stopIndexBuilds("indexing")
+
+	ctx := context.Background()
+	require.NoError(t, j.throttle(ctx))
+	require.EqualError(t, indexBuildError("indexing", "index"),
+		"The index of predicate indexing is being built (25% done)")
+	require.NoError(t, indexBuildError("indexing", "count"))
+	require.NoError(t, indexBuildError("other", "index"))
+
+	// A paused build waits until it's resumed.
+	require.NoError(t, PauseIndexBuild("indexing"))
+	require.Equal(t, indexBuildPaused, IndexBuilds()[0].Status)
+	errCh := make(chan error, 1)
+	go func() { errCh <- j.throttle(ctx) }()
+	select {
+	case <-errCh:
+		t.Fatal("The throttle of a paused build returned")
+	case <-time.After(50 * time.Millisecond):
+	}
+	require.NoError(t, ResumeIndexBuild("indexing"))
+	require.NoError(t, <-errCh)
+	require.Equal(t, indexBuildRunning, IndexBuilds()[0].Status)
+	require.Error(t, ResumeIndexBuild("indexing"))
+
+	// A paused build stops when its context is cancelled.
+	require.NoError(t, PauseIndexBuild("indexing"))
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	require.Equal(t, context.Canceled, j.throttle(cctx))
+	require.NoError(t, ResumeIndexBuild("indexing"))
+
+	// The rate limits the posting lists read per second.
+	x.Config.IndexBuildRate = 100
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		require.NoError(t, j.throttle(ctx))
+	}
+	require.True(t, time.Since(start) >= 40*time.Millisecond)
+	require.EqualValues(t, 4, IndexBuilds()[0].KeysDone)
+}
+
+func TestIndexBuildPersisting(t *testing.T) {
+	j := &indexJob{
+		status: IndexBuild{
+			Predicate: "persisting",
+			Status:    indexBuildDone,
+			KeysTotal: 1,
+		},
+		builds: []*posting.IndexBuild{{Attr: "persisting", Kind: "index"}},
+		cancel: func() {},
+		done:   make(chan struct{}),
+	}
+	close(j.done)
+	indexJobs.Lock()
+	indexJobs.persisting["persisting"] = j
+	indexJobs.Unlock()
+
+	// The indices are complete, so they're read while the schema is persisted.
+	require.NoError(t, indexBuildError("persisting", "index"))
+	require.False(t, isIndexBuilding("persisting"))
+
+	// A schema update still stops the job, and persists its schema instead.
+	require.Equal(t, []*indexJob{j}, stopIndexBuilds("persisting"))
+	indexJobs.Lock()
+	require.Empty(t, indexJobs.persisting)
+	indexJobs.Unlock()
+}
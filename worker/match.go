@@ -17,6 +17,13 @@
 package worker
 
 import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+
 	"github.com/dgraph-io/dgraph/algo"
 	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/protos/pb"
@@ -75,19 +82,120 @@ func min(a, b, c int) int {
 	return c
 }
 
+// osaDistance is the optimal string alignment distance between two strings. It's the
+// Levenshtein distance where swapping two adjacent characters also counts as a single edit,
+// as long as no substring is edited more than once.
+func osaDistance(s, t string) int {
+	r1, r2 := []rune(s), []rune(t)
+	// prev2, prev and cur are the rows i-2, i-1 and i of the distance matrix.
+	prev2 := make([]int, len(r2)+1)
+	prev := make([]int, len(r2)+1)
+	cur := make([]int, len(r2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(r1); i++ {
+		cur[0] = i
+		for j := 1; j <= len(r2); j++ {
+			cost := 0
+			if r1[i-1] != r2[j-1] {
+				cost = 1
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && r1[i-1] == r2[j-2] && r1[i-2] == r2[j-1] &&
+				prev2[j-2]+1 < cur[j] {
+				cur[j] = prev2[j-2] + 1
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(r2)]
+}
+
+// editDistance returns the distance between s and t, counting transpositions as a single
+// edit if asked to. Levenshtein distances above max are only known to be greater than max.
+func editDistance(s, t string, max int, transpositions bool) int {
+	if transpositions {
+		return osaDistance(s, t)
+	}
+	return levenshteinDistance(s, t, max)
+}
+
 // matchFuzzy takes in a value (from posting) and compares it to our list of ngram tokens.
 // Returns true if value matches fuzzy tokens, false otherwise.
-func matchFuzzy(query, val string, max int) bool {
+func matchFuzzy(query, val string, max int, transpositions bool) bool {
 	if val == "" {
 		return false
 	}
-	return levenshteinDistance(val, query, max) <= max
+	return editDistance(val, query, max, transpositions) <= max
+}
+
+const (
+	// Terms shorter than autoLow runes must match exactly with the auto distance, and terms
+	// shorter than autoHigh runes may have one edit. Longer terms may have two edits.
+	autoLow  = 3
+	autoHigh = 6
+)
+
+// parseMatchDistance parses the max distance argument of the match function for the term. The
+// argument is either an int, or "auto" to pick the distance from the length of the term.
+// The length thresholds of auto can be given as "auto:low,high".
+func parseMatchDistance(arg, term string) (int, error) {
+	if !strings.HasPrefix(arg, "auto") {
+		max, err := strconv.ParseInt(arg, 10, 32)
+		if err != nil {
+			return 0, errors.Errorf("Levenshtein distance value must be an int or auto, got %v",
+				arg)
+		}
+		if max < 0 {
+			return 0, errors.Errorf("Levenshtein distance value must be greater than 0, got %v",
+				arg)
+		}
+		return int(max), nil
+	}
+
+	low, high := autoLow, autoHigh
+	if arg != "auto" {
+		thresholds := strings.Split(strings.TrimPrefix(arg, "auto:"), ",")
+		if !strings.HasPrefix(arg, "auto:") || len(thresholds) != 2 {
+			return 0, errors.Errorf("Invalid auto distance %q, expected auto:low,high", arg)
+		}
+		var err error
+		if low, err = strconv.Atoi(strings.TrimSpace(thresholds[0])); err != nil {
+			return 0, errors.Errorf("Invalid auto distance %q, expected auto:low,high", arg)
+		}
+		if high, err = strconv.Atoi(strings.TrimSpace(thresholds[1])); err != nil {
+			return 0, errors.Errorf("Invalid auto distance %q, expected auto:low,high", arg)
+		}
+		if low < 0 || high < low {
+			return 0, errors.Errorf("Invalid auto distance %q, low must be between 0 and high",
+				arg)
+		}
+	}
+
+	switch n := utf8.RuneCountInString(term); {
+	case n < low:
+		return 0, nil
+	case n < high:
+		return 1, nil
+	}
+	return 2, nil
+}
+
+// parseTranspositions parses the optional argument of the match and editdistance functions,
+// which allows transpositions of adjacent characters as a single edit.
+func parseTranspositions(fname, arg string) (bool, error) {
+	if arg != "transpositions" {
+		return false, errors.Errorf("Function '%s' expects transpositions as an optional "+
+			"argument, got %q", fname, arg)
+	}
+	return true, nil
 }
 
 // uidsForMatch collects a list of uids that "might" match a fuzzy term based on the ngram
 // index. matchFuzzy does the actual fuzzy match.
 // Returns the list of uids even if empty, or an error otherwise.
-func uidsForMatch(attr string, arg funcArgs) (*pb.List, error) {
+func uidsForMatch(ctx context.Context, attr string, arg funcArgs) (*pb.List, error) {
 	opts := posting.ListOptions{ReadTs: arg.q.ReadTs}
 	uidsForNgram := func(ngram string) (*pb.List, error) {
 		key := x.IndexKey(attr, ngram)
@@ -95,6 +203,7 @@ func uidsForMatch(attr string, arg funcArgs) (*pb.List, error) {
 		if err != nil {
 			return nil, err
 		}
+		countRead(ctx, pl)
 		return pl.Uids(opts)
 	}
 
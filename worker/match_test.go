@@ -16,3 +16,51 @@ func TestDistance(t *testing.T) {
 	require.Equal(t, 3, levenshteinDistance("detour", "...detour", 2))
 	require.Equal(t, 3, levenshteinDistance("detour", "..detour.", 2))
 }
+
+func TestDistanceTranspositions(t *testing.T) {
+	require.Equal(t, 2, editDistance("detour", "dteour", 6, false))
+	require.Equal(t, 1, editDistance("detour", "dteour", 2, true))
+	require.Equal(t, 0, editDistance("detour", "detour", 2, true))
+	require.Equal(t, 2, editDistance("detour", "dteoru", 2, true))
+	// A substring isn't edited more than once.
+	require.Equal(t, 3, editDistance("ca", "abc", 3, true))
+	require.Equal(t, 6, editDistance("", "detour", 2, true))
+	require.True(t, matchFuzzy("Stpehen", "Stephen", 1, true))
+	require.False(t, matchFuzzy("Stpehen", "Stephen", 1, false))
+}
+
+func TestParseMatchDistance(t *testing.T) {
+	max, err := parseMatchDistance("3", "Stephen")
+	require.NoError(t, err)
+	require.Equal(t, 3, max)
+	_, err = parseMatchDistance("-1", "Stephen")
+	require.Error(t, err)
+	_, err = parseMatchDistance("three", "Stephen")
+	require.Error(t, err)
+
+	// The auto distance depends on the length of the term.
+	for term, expected := range map[string]int{"ab": 0, "abc": 1, "abcde": 1, "abcdef": 2,
+		"Åsa": 1} {
+		max, err = parseMatchDistance("auto", term)
+		require.NoError(t, err)
+		require.Equal(t, expected, max, term)
+	}
+	max, err = parseMatchDistance("auto:1,3", "abc")
+	require.NoError(t, err)
+	require.Equal(t, 2, max)
+	max, err = parseMatchDistance("auto: 4, 8", "abc")
+	require.NoError(t, err)
+	require.Equal(t, 0, max)
+	for _, arg := range []string{"auto:", "auto:3", "auto:a,b", "auto:6,3", "autox"} {
+		_, err = parseMatchDistance(arg, "abc")
+		require.Error(t, err, arg)
+	}
+}
+
+func TestParseTranspositions(t *testing.T) {
+	transpositions, err := parseTranspositions("match", "transpositions")
+	require.NoError(t, err)
+	require.True(t, transpositions)
+	_, err = parseTranspositions("match", "swaps")
+	require.Error(t, err)
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+type queryMetricsKey struct{}
+
+// QueryMetrics counts the resources used to run a request, across all the groups it touched.
+type QueryMetrics struct {
+	// KeysRead is the number of posting lists read.
+	KeysRead uint64 `json:"keys_read"`
+	// BytesRead is the number of bytes read from Badger to build those posting lists.
+	BytesRead uint64 `json:"bytes_read"`
+	// NetworkBytes is the size of the tasks sent to other groups and of their results.
+	NetworkBytes uint64 `json:"network_bytes"`
+	// ResponseBytes is the size of the response sent to the client.
+	ResponseBytes uint64 `json:"response_bytes"`
+}
+
+// WithQueryMetrics returns a context that counts the resources used by the tasks run with it,
+// so that they can be read back with QueryMetricsFrom.
+func WithQueryMetrics(ctx context.Context) context.Context {
+	return context.WithValue(ctx, queryMetricsKey{}, &QueryMetrics{})
+}
+
+// QueryMetricsFrom returns a copy of the metrics counted for the context, or nil if the
+// context doesn't count them.
+func QueryMetricsFrom(ctx context.Context) *QueryMetrics {
+	m, ok := ctx.Value(queryMetricsKey{}).(*QueryMetrics)
+	if !ok {
+		return nil
+	}
+	return &QueryMetrics{
+		KeysRead:      atomic.LoadUint64(&m.KeysRead),
+		BytesRead:     atomic.LoadUint64(&m.BytesRead),
+		NetworkBytes:  atomic.LoadUint64(&m.NetworkBytes),
+		ResponseBytes: atomic.LoadUint64(&m.ResponseBytes),
+	}
+}
+
+func addQueryMetrics(ctx context.Context, keys, bytes, network uint64) {
+	m, ok := ctx.Value(queryMetricsKey{}).(*QueryMetrics)
+	if !ok {
+		return
+	}
+	atomic.AddUint64(&m.KeysRead, keys)
+	atomic.AddUint64(&m.BytesRead, bytes)
+	atomic.AddUint64(&m.NetworkBytes, network)
+}
+
+// AddResponseBytes records the size of the response sent for the request run with the context.
+func AddResponseBytes(ctx context.Context, n int) {
+	if m, ok := ctx.Value(queryMetricsKey{}).(*QueryMetrics); ok {
+		atomic.AddUint64(&m.ResponseBytes, uint64(n))
+	}
+}
+
+// countRead records that the posting list was read to process a task.
+func countRead(ctx context.Context, pl *posting.List) {
+	addQueryMetrics(ctx, 1, pl.ReadBytes(), 0)
+}
+
+// countRemoteTask records the resources used by a task processed by another group.
+func countRemoteTask(ctx context.Context, q *pb.Query, r *pb.Result) {
+	addQueryMetrics(ctx, r.KeysRead, r.BytesRead, uint64(q.Size()+r.Size()))
+}
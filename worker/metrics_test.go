@@ -0,0 +1,47 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+func TestQueryMetrics(t *testing.T) {
+	// Nothing is counted without a context made by WithQueryMetrics.
+	ctx := context.Background()
+	AddResponseBytes(ctx, 10)
+	require.Nil(t, QueryMetricsFrom(ctx))
+
+	ctx = WithQueryMetrics(ctx)
+	q := &pb.Query{Attr: "name", ReadTs: 5}
+	r := &pb.Result{Counts: []uint32{1, 2}, KeysRead: 3, BytesRead: 100}
+	countRemoteTask(ctx, q, r)
+	countRemoteTask(ctx, q, r)
+	AddResponseBytes(ctx, 42)
+
+	require.Equal(t, &QueryMetrics{
+		KeysRead:      6,
+		BytesRead:     200,
+		NetworkBytes:  uint64(2 * (q.Size() + r.Size())),
+		ResponseBytes: 42,
+	}, QueryMetricsFrom(ctx))
+}
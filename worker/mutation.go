@@ -19,23 +19,29 @@ package worker
 import (
 	"bytes"
 	"math"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/dgraph-io/badger"
 	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/dgraph-io/dgo/y"
 
+	"github.com/dgraph-io/dgraph/blob"
 	"github.com/dgraph-io/dgraph/conn"
 	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/types/facets"
 	"github.com/dgraph-io/dgraph/x"
 
 	"github.com/golang/glog"
 	"github.com/pkg/errors"
 	otrace "go.opencensus.io/trace"
 	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 var (
@@ -59,7 +65,7 @@ func runMutation(ctx context.Context, edge *pb.DirectedEdge, txn *posting.Txn) e
 	// isn't consistent across the entire cluster. We should just apply whatever is given to us.
 
 	su, ok := schema.State().Get(edge.Attr)
-	if edge.Op == pb.DirectedEdge_SET {
+	if edge.Op != pb.DirectedEdge_DEL {
 		if !ok {
 			return errors.Errorf("runMutation: Unable to find schema for %s", edge.Attr)
 		}
@@ -74,6 +80,9 @@ func runMutation(ctx context.Context, edge *pb.DirectedEdge, txn *posting.Txn) e
 	if err := ValidateAndConvert(edge, &su); err != nil {
 		return err
 	}
+	if err := checkFacets(edge, &su); err != nil {
+		return err
+	}
 
 	key := x.DataKey(edge.Attr, edge.Entity)
 	// The following is a performance optimization which allows us to not read a posting list from
@@ -84,8 +93,8 @@ func runMutation(ctx context.Context, edge *pb.DirectedEdge, txn *posting.Txn) e
 	// the rollup operation would consolidate all these deltas into a posting list.
 	var getFn func(key []byte) (*posting.List, error)
 	switch {
-	case len(su.GetTokenizer()) > 0 || su.GetCount():
-		// Any index or count index.
+	case len(su.GetTokenizer()) > 0 || su.GetCount() || len(su.GetFacets()) > 0:
+		// Any index, count index or declared facets, which may be indexed.
 		getFn = txn.Get
 	case su.GetValueType() == pb.Posting_UID && !su.GetList():
 		// Single UID, not a list.
@@ -93,6 +102,12 @@ func runMutation(ctx context.Context, edge *pb.DirectedEdge, txn *posting.Txn) e
 	case edge.Op == pb.DirectedEdge_DEL:
 		// Covers various delete cases to keep things simple.
 		getFn = txn.Get
+	case isValueOp(edge):
+		// The current value is needed to compute the new one.
+		getFn = txn.Get
+	case facets.HasOps(edge.Facets):
+		// The current facets are needed to compute the new ones.
+		getFn = txn.Get
 	default:
 		// Reverse index doesn't need the posting list to be read. We already covered count index,
 		// single uid and delete all above.
@@ -112,13 +127,40 @@ func runMutation(ctx context.Context, edge *pb.DirectedEdge, txn *posting.Txn) e
 	if err != nil {
 		return err
 	}
-	return plist.AddMutationWithIndex(ctx, edge, txn)
+	if isValueOp(edge) {
+		if edge, err = applyValueOp(edge, plist, txn.StartTs); err != nil {
+			return err
+		}
+	}
+	if facets.HasOps(edge.Facets) {
+		if edge, err = applyFacetOps(edge, plist, txn.StartTs); err != nil {
+			return err
+		}
+	}
+	if err := plist.AddMutationWithIndex(ctx, edge, txn); err != nil {
+		return err
+	}
+	addToSketch(edge)
+	return nil
 }
 
 // This is serialized with mutations, called after applied watermarks catch up
-// and further mutations are blocked until this is done.
-func runSchemaMutation(ctx context.Context, update *pb.SchemaUpdate, startTs uint64) error {
-	if err := runSchemaMutationHelper(ctx, update, startTs); err != nil {
+// and further mutations are blocked until this is done. The indices are built before it returns,
+// unless background is set.
+func runSchemaMutation(ctx context.Context, update *pb.SchemaUpdate, startTs uint64,
+	background bool) error {
+	// The values may be converted to the new type.
+	dropSketches(update.Predicate)
+	old, builds, err := runSchemaMutationHelper(ctx, update, startTs)
+	if err == nil && !background {
+		for _, b := range builds {
+			if err = b.Run(ctx); err != nil {
+				break
+			}
+		}
+		builds = nil
+	}
+	if err != nil {
 		// on error, we restore the memory state to be the same as the disk
 		maxRetries := 10
 		loadErr := x.RetryUntilSuccess(maxRetries, 10*time.Millisecond, func() error {
@@ -130,21 +172,51 @@ func runSchemaMutation(ctx context.Context, update *pb.SchemaUpdate, startTs uin
 		}
 		return err
 	}
+	if len(builds) == 0 {
+		return updateSchema(update)
+	}
 
-	return updateSchema(update)
+	// The indices are built in the background. The schema they're built from is kept on disk
+	// until they're done, to build them again if the Alpha restarts before.
+	current := *update
+	current.IndexBase = &old
+	if err := updateSchema(&current); err != nil {
+		return err
+	}
+	startIndexBuilds(&current, builds, startTs)
+	return nil
 }
 
-func runSchemaMutationHelper(ctx context.Context, update *pb.SchemaUpdate, startTs uint64) error {
+// runSchemaMutationHelper applies the schema update in memory, and prepares the index builds
+// it needs. It returns the schema the indices are built from, and the builds.
+func runSchemaMutationHelper(ctx context.Context, update *pb.SchemaUpdate,
+	startTs uint64) (pb.SchemaUpdate, []*posting.IndexBuild, error) {
 	if tablet, err := groups().Tablet(update.Predicate); err != nil {
-		return err
+		return pb.SchemaUpdate{}, nil, err
 	} else if tablet.GetGroupId() != groups().groupId() {
-		return errors.Errorf("Tablet isn't being served by this group. Tablet: %+v", tablet)
+		return pb.SchemaUpdate{}, nil,
+			errors.Errorf("Tablet isn't being served by this group. Tablet: %+v", tablet)
 	}
 
 	if err := checkSchema(update); err != nil {
-		return err
+		return pb.SchemaUpdate{}, nil, err
 	}
 	old, _ := schema.State().Get(update.Predicate)
+	// The update supersedes the builds of the previous one, the indices they didn't complete
+	// are built again.
+	for _, j := range stopIndexBuilds(update.Predicate) {
+		if j.status.Status == indexBuildDone {
+			// Its indices are complete, only its schema wasn't persisted, which the update does.
+			continue
+		}
+		glog.Infof("Stopped the build of indices %v of predicate %s for a new schema update",
+			j.status.Indices, update.Predicate)
+		var err error
+		if old, err = j.completedIndices(); err != nil {
+			return pb.SchemaUpdate{}, nil, err
+		}
+	}
+	old.IndexBase = nil
 	current := *update
 	// Sets only in memory, we will update it on disk only after schema mutations
 	// are successful and  written to disk.
@@ -153,14 +225,10 @@ func runSchemaMutationHelper(ctx context.Context, update *pb.SchemaUpdate, start
 	// Once we remove index or reverse edges from schema, even though the values
 	// are present in db, they won't be used due to validation in work/task.go
 
-	// We don't want to use sync watermarks for background removal, because it would block
-	// linearizable read requests. Only downside would be on system crash, stale edges
-	// might remain, which is ok.
-
-	// Indexing can't be done in background as it can cause race conditons with new
-	// index mutations (old set and new del)
-	// We need watermark for index/reverse edge addition for linearizable reads.
-	// (both applied and synced watermarks).
+	// The removed and rebuilt indices are deleted here, and the mutations applied from now on
+	// maintain the new ones. The builds read the data at startTs and write their entries at
+	// startTs, so the entries of the later mutations take precedence over them: they can run in
+	// the background. Queries reject the indices until they're built.
 	defer glog.Infof("Done schema update %+v\n", update)
 	rebuild := posting.IndexRebuild{
 		Attr:          update.Predicate,
@@ -168,7 +236,8 @@ func runSchemaMutationHelper(ctx context.Context, update *pb.SchemaUpdate, start
 		OldSchema:     &old,
 		CurrentSchema: &current,
 	}
-	return rebuild.Run(ctx)
+	builds, err := rebuild.Prepare(ctx)
+	return old, builds, err
 }
 
 // updateSchema commits the schema to disk in blocking way, should be ok because this happens
@@ -356,9 +425,23 @@ func ValidateAndConvert(edge *pb.DirectedEdge, su *pb.SchemaUpdate) error {
 	if isDeletePredicateEdge(edge) {
 		return nil
 	}
+	if edge.Blob {
+		// The value was checked before it was offloaded, only its key is left.
+		return nil
+	}
 	if types.TypeID(edge.ValueType) == types.DefaultID && isStarAll(edge.Value) {
 		return nil
 	}
+	if isValueOp(edge) {
+		if err := validateValueOp(edge, su); err != nil {
+			return err
+		}
+	}
+	if facets.HasOps(edge.Facets) {
+		if err := validateFacetOps(edge, su); err != nil {
+			return err
+		}
+	}
 
 	storageType := posting.TypeID(edge)
 	schemaType := types.TypeID(su.ValueType)
@@ -408,6 +491,84 @@ func ValidateAndConvert(edge *pb.DirectedEdge, su *pb.SchemaUpdate) error {
 	return nil
 }
 
+// checkValueSize returns an error if the value of the edge is larger than the maximum size set
+// by the @maxsize directive of its predicate.
+func checkValueSize(edge *pb.DirectedEdge, su *pb.SchemaUpdate) error {
+	if su.MaxSize == 0 || edge.Blob ||
+		(edge.Op != pb.DirectedEdge_SET && edge.Op != pb.DirectedEdge_CAS) {
+		return nil
+	}
+	if size := uint64(len(edge.Value)); size > su.MaxSize {
+		return errors.Errorf("Value of %d bytes for predicate %s exceeds its maximum size of %d"+
+			" bytes", size, edge.Attr, su.MaxSize)
+	}
+	return nil
+}
+
+// checkFacets returns an error if the edge has a facet which isn't declared by the @facets
+// directive of its predicate, and converts the facets to their declared types. The facets of
+// predicates without the directive aren't checked.
+func checkFacets(edge *pb.DirectedEdge, su *pb.SchemaUpdate) error {
+	if len(su.Facets) == 0 {
+		return nil
+	}
+	for i, f := range edge.Facets {
+		op, key := facets.SplitOp(f.Key)
+		var decl *pb.SchemaUpdate
+		for _, d := range su.Facets {
+			if d.Predicate == key {
+				decl = d
+				break
+			}
+		}
+		if decl == nil {
+			return errors.Errorf("Facet %s isn't declared in the schema of predicate %s",
+				key, edge.Attr)
+		}
+		typ := types.TypeID(decl.ValueType)
+		switch {
+		case op == facets.DeleteOp:
+			continue
+		case op == facets.IncrementOp && typ != types.IntID && typ != types.FloatID:
+			return errors.Errorf("Facet %s of predicate %s of type %s can't be incremented",
+				key, edge.Attr, typ.Name())
+		}
+		cf, err := facets.ConvertTo(f, typ)
+		if err != nil {
+			return errors.Wrapf(err, "while converting facet %s of predicate %s to %s",
+				key, edge.Attr, typ.Name())
+		}
+		edge.Facets[i] = cf
+	}
+	return nil
+}
+
+// offloadValue moves the value of the edge to the blob store, leaving only its key in the edge,
+// if it's larger than x.Config.BlobOffloadSize. Only string and binary values of predicates
+// which aren't indexed or lists are offloaded, as the others are read by their value.
+func offloadValue(edge *pb.DirectedEdge, su *pb.SchemaUpdate) error {
+	if !blob.Enabled() || x.Config.BlobOffloadSize <= 0 || edge.Blob ||
+		edge.Op != pb.DirectedEdge_SET || edge.ValueId != 0 ||
+		len(edge.Value) <= x.Config.BlobOffloadSize {
+		return nil
+	}
+	switch types.TypeID(su.ValueType) {
+	case types.StringID, types.BinaryID, types.DefaultID:
+	default:
+		return nil
+	}
+	if su.List || su.Directive != pb.SchemaUpdate_NONE || su.Upsert {
+		return nil
+	}
+	key, err := blob.Put(edge.Value)
+	if err != nil {
+		return err
+	}
+	edge.Value = []byte(key)
+	edge.Blob = true
+	return nil
+}
+
 // AssignUidsOverNetwork sends a request to assign UIDs to blank nodes to the current zero leader.
 func AssignUidsOverNetwork(ctx context.Context, num *pb.Num) (*pb.AssignedIds, error) {
 	pl := groups().Leader(0)
@@ -444,6 +605,12 @@ func fillTxnContext(tctx *api.TxnContext, startTs uint64) {
 // the leader of the group gid for proposing.
 func proposeOrSend(ctx context.Context, gid uint32, m *pb.Mutations, chr chan res) {
 	res := res{}
+	// The proposal is at least in the Raft log once it has been applied.
+	defer func() {
+		if res.err == nil {
+			proposalLogged(ctx)
+		}
+	}()
 	if groups().ServesGroup(gid) {
 		res.ctx = &api.TxnContext{}
 		res.err = (&grpcWorker{}).proposeAndWait(ctx, res.ctx, m)
@@ -510,6 +677,7 @@ func populateMutationMap(src *pb.Mutations) (map[uint32]*pb.Mutations, error) {
 			mm[gid] = mu
 		}
 		mu.Schema = append(mu.Schema, schema)
+		mu.RunInBackground = src.RunInBackground
 	}
 
 	if src.DropOp > 0 {
@@ -551,20 +719,40 @@ func MutateOverNetwork(ctx context.Context, m *pb.Mutations) (*api.TxnContext, e
 	defer span.End()
 
 	tctx := &api.TxnContext{StartTs: m.StartTs}
+	for _, edge := range m.Edges {
+		if err := groups().checkWritable(edge.Attr); err != nil {
+			return tctx, err
+		}
+	}
 	mutationMap, err := populateMutationMap(m)
 	if err != nil {
 		return tctx, err
 	}
 
+	if _, ok := mutationMap[0]; ok {
+		span.Annotatef(nil, "state: %+v", groups().state)
+		span.Annotatef(nil, "Group id zero for mutation: %+v", mutationMap[0])
+		return tctx, errNonExistentTablet
+	}
+
+	// The mutation is logged once the proposals of all the groups are.
+	var logged func()
+	if f, ok := ctx.Value(loggedKey{}).(func()); ok && f != nil {
+		pending := int32(len(mutationMap))
+		logged = func() {
+			if atomic.AddInt32(&pending, -1) == 0 {
+				f()
+			}
+		}
+	}
 	resCh := make(chan res, len(mutationMap))
 	for gid, mu := range mutationMap {
-		if gid == 0 {
-			span.Annotatef(nil, "state: %+v", groups().state)
-			span.Annotatef(nil, "Group id zero for mutation: %+v", mu)
-			return tctx, errNonExistentTablet
-		}
 		mu.StartTs = m.StartTs
-		go proposeOrSend(ctx, gid, mu, resCh)
+		gctx := ctx
+		if logged != nil {
+			gctx = context.WithValue(ctx, loggedKey{}, onceFunc(logged))
+		}
+		go proposeOrSend(gctx, gid, mu, resCh)
 	}
 
 	// Wait for all the goroutines to reply back.
@@ -584,7 +772,29 @@ func MutateOverNetwork(ctx context.Context, m *pb.Mutations) (*api.TxnContext, e
 	return tctx, e
 }
 
-// CommitOverNetwork makes a proxy call to Zero to commit or abort a transaction.
+type loggedKey struct{}
+
+// WithProposalsLogged returns a context whose mutations call logged once their proposals are
+// in the Raft logs of their groups, before they're applied. The proposals sent to another
+// Alpha are only known to be logged once they've been applied.
+func WithProposalsLogged(ctx context.Context, logged func()) context.Context {
+	return context.WithValue(ctx, loggedKey{}, logged)
+}
+
+// proposalLogged calls the function set by WithProposalsLogged, if any.
+func proposalLogged(ctx context.Context) {
+	if f, ok := ctx.Value(loggedKey{}).(func()); ok && f != nil {
+		f()
+	}
+}
+
+func onceFunc(f func()) func() {
+	var once sync.Once
+	return func() { once.Do(f) }
+}
+
+// CommitOverNetwork makes a proxy call to Zero to commit or abort a transaction. If Zero aborted
+// the transaction due to a conflict, the returned error wraps y.ErrAborted with the details.
 func CommitOverNetwork(ctx context.Context, tc *api.TxnContext) (uint64, error) {
 	ctx, span := otrace.StartSpan(ctx, "worker.CommitOverNetwork")
 	defer span.End()
@@ -598,6 +808,9 @@ func CommitOverNetwork(ctx context.Context, tc *api.TxnContext) (uint64, error)
 
 	if err != nil {
 		span.Annotatef(nil, "Error=%v", err)
+		if st, ok := status.FromError(err); ok && st.Code() == codes.Aborted {
+			return 0, errors.Wrap(y.ErrAborted, st.Message())
+		}
 		return 0, err
 	}
 	var attributes []otrace.Attribute
@@ -631,7 +844,33 @@ func (w *grpcWorker) proposeAndWait(ctx context.Context, txnCtx *api.TxnContext,
 	}
 
 	node := groups().Node
-	err := node.proposeAndWait(ctx, &pb.Proposal{Mutations: m})
+	// The edges of append-only predicates are applied first, and stay applied even if the
+	// transaction is aborted.
+	am, err := splitAppendOnly(m)
+	if err != nil {
+		return err
+	}
+	if am != nil && !groups().FeatureEnabled(x.FeatureAppendOnly) {
+		// Some members run an older build, which would apply the edges as part of the
+		// transaction anyway. Keep them in the transaction until the whole cluster is upgraded.
+		m.Edges = append(m.Edges, am.Edges...)
+		am = nil
+	}
+	if am != nil {
+		actx := ctx
+		if !isEmptyMutation(m) {
+			// Only the last proposal of the mutation tells that it's logged.
+			actx = WithProposalsLogged(ctx, nil)
+		}
+		if err := proposeAppendOnly(actx, am); err != nil {
+			return err
+		}
+		if isEmptyMutation(m) {
+			return nil
+		}
+	}
+
+	err = node.proposeAndWait(ctx, &pb.Proposal{Mutations: m})
 	fillTxnContext(txnCtx, m.StartTs)
 	return err
 }
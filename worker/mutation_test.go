@@ -17,15 +17,22 @@
 package worker
 
 import (
+	"context"
+	"io/ioutil"
+	"os"
 	"reflect"
 	"testing"
 
+	"github.com/dgraph-io/dgo/protos/api"
 	"github.com/stretchr/testify/require"
 
+	"github.com/dgraph-io/dgraph/blob"
 	"github.com/dgraph-io/dgraph/posting"
 	"github.com/dgraph-io/dgraph/protos/pb"
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/types/facets"
+	"github.com/dgraph-io/dgraph/x"
 )
 
 func TestConvertEdgeType(t *testing.T) {
@@ -124,7 +131,7 @@ func TestPopulateMutationMap(t *testing.T) {
 	schema := []*pb.SchemaUpdate{{
 		Predicate: "name",
 	}}
-	m := &pb.Mutations{Edges: edges, Schema: schema}
+	m := &pb.Mutations{Edges: edges, Schema: schema, RunInBackground: true}
 
 	mutationsMap, err := populateMutationMap(m)
 	require.NoError(t, err)
@@ -132,6 +139,7 @@ func TestPopulateMutationMap(t *testing.T) {
 	require.NotNil(t, mu)
 	require.NotNil(t, mu.Edges)
 	require.NotNil(t, mu.Schema)
+	require.True(t, mu.RunInBackground)
 }
 
 func TestCheckSchema(t *testing.T) {
@@ -209,3 +217,236 @@ func TestCheckSchema(t *testing.T) {
 	err = checkSchema(result.Preds[1])
 	require.NoError(t, err)
 }
+
+func TestCheckValueSize(t *testing.T) {
+	su := &pb.SchemaUpdate{ValueType: pb.Posting_STRING, MaxSize: 4}
+	edge := &pb.DirectedEdge{Attr: "bio", Value: []byte("abcd")}
+	require.NoError(t, checkValueSize(edge, su))
+
+	edge.Value = []byte("abcde")
+	require.Error(t, checkValueSize(edge, su))
+
+	// Deletions are never rejected.
+	edge.Op = pb.DirectedEdge_DEL
+	require.NoError(t, checkValueSize(edge, su))
+
+	su.MaxSize = 0
+	edge.Op = pb.DirectedEdge_SET
+	require.NoError(t, checkValueSize(edge, su))
+}
+
+func TestCheckFacets(t *testing.T) {
+	su := &pb.SchemaUpdate{
+		ValueType: pb.Posting_UID,
+		Facets: []*pb.SchemaUpdate{
+			{Predicate: "since", ValueType: pb.Posting_DATETIME},
+			{Predicate: "weight", ValueType: pb.Posting_FLOAT},
+		},
+	}
+	since, err := facets.FacetFor("since", `"2006-01-02"`)
+	require.NoError(t, err)
+	weight, err := facets.FacetFor("weight", "3")
+	require.NoError(t, err)
+	edge := &pb.DirectedEdge{Attr: "friend", Facets: []*api.Facet{since, weight}}
+	require.NoError(t, checkFacets(edge, su))
+	require.Equal(t, api.Facet_DATETIME, edge.Facets[0].ValType)
+	require.Equal(t, api.Facet_FLOAT, edge.Facets[1].ValType)
+
+	other, err := facets.FacetFor("other", "1")
+	require.NoError(t, err)
+	edge.Facets = append(edge.Facets, other)
+	require.Error(t, checkFacets(edge, su))
+
+	bad, err := facets.FacetFor("weight", `"heavy"`)
+	require.NoError(t, err)
+	edge.Facets = []*api.Facet{bad}
+	require.Error(t, checkFacets(edge, su))
+
+	// The facets of predicates without declared facets aren't checked.
+	edge.Facets = []*api.Facet{other}
+	require.NoError(t, checkFacets(edge, &pb.SchemaUpdate{ValueType: pb.Posting_UID}))
+
+	// The keys of the facets with operators are checked without them, and the increments
+	// are converted to the declared types.
+	incr, err := facets.FacetFor("+weight", "1")
+	require.NoError(t, err)
+	edge.Facets = []*api.Facet{{Key: "-since"}, incr}
+	require.NoError(t, checkFacets(edge, su))
+	require.Equal(t, "+weight", edge.Facets[1].Key)
+	require.Equal(t, api.Facet_FLOAT, edge.Facets[1].ValType)
+
+	incr.Key = "+since"
+	edge.Facets = []*api.Facet{incr}
+	require.Error(t, checkFacets(edge, su))
+}
+
+func TestOffloadValue(t *testing.T) {
+	dir, err := ioutil.TempDir("", "blob")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, blob.Init(dir))
+	defer blob.Init("")
+	defer func(size int) { x.Config.BlobOffloadSize = size }(x.Config.BlobOffloadSize)
+	x.Config.BlobOffloadSize = 4
+
+	su := &pb.SchemaUpdate{ValueType: pb.Posting_STRING}
+	edge := &pb.DirectedEdge{Attr: "bio", Value: []byte("abcd")}
+	require.NoError(t, offloadValue(edge, su))
+	require.False(t, edge.Blob)
+
+	edge.Value = []byte("abcde")
+	require.NoError(t, offloadValue(edge, su))
+	require.True(t, edge.Blob)
+	require.Equal(t, blob.Key([]byte("abcde")), string(edge.Value))
+	require.NoError(t, ValidateAndConvert(edge, su))
+
+	val, err := posting.ResolveValue(posting.NewPosting(edge))
+	require.NoError(t, err)
+	require.Equal(t, []byte("abcde"), val.Value)
+
+	// Values read by their content aren't offloaded.
+	for _, su := range []*pb.SchemaUpdate{
+		{ValueType: pb.Posting_STRING, Directive: pb.SchemaUpdate_INDEX},
+		{ValueType: pb.Posting_STRING, List: true},
+		{ValueType: pb.Posting_INT},
+	} {
+		edge := &pb.DirectedEdge{Attr: "bio", Value: []byte("abcde")}
+		require.NoError(t, offloadValue(edge, su))
+		require.False(t, edge.Blob)
+	}
+}
+
+func TestValueOps(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte(`
+		views: int .
+		price: float .
+		status: string .
+		scores: [int] .
+	`), 1))
+
+	txn := posting.Oracle().RegisterStartTs(timestamp())
+	mutate := func(attr string, op pb.DirectedEdge_Op, value, expected string) error {
+		edge := &pb.DirectedEdge{
+			Entity:   0x200,
+			Attr:     attr,
+			Op:       op,
+			Value:    []byte(value),
+			Expected: []byte(expected),
+		}
+		su, _ := schema.State().Get(attr)
+		// The edge is converted when it's proposed, and again when it's applied.
+		if err := ValidateAndConvert(edge, &su); err != nil {
+			return err
+		}
+		return runMutation(context.Background(), edge, txn)
+	}
+	value := func(attr string) interface{} {
+		pl, err := txn.Get(x.DataKey(attr, 0x200))
+		require.NoError(t, err)
+		val, err := pl.Value(txn.StartTs)
+		require.NoError(t, err)
+		val, err = types.Convert(val, val.Tid)
+		require.NoError(t, err)
+		return val.Value
+	}
+
+	// A missing value counts as zero.
+	require.NoError(t, mutate("views", pb.DirectedEdge_ADD, "5", ""))
+	require.NoError(t, mutate("views", pb.DirectedEdge_ADD, "-2", ""))
+	require.Equal(t, int64(3), value("views"))
+	require.NoError(t, mutate("price", pb.DirectedEdge_ADD, "1.5", ""))
+	require.NoError(t, mutate("price", pb.DirectedEdge_ADD, "2", ""))
+	require.Equal(t, 3.5, value("price"))
+
+	require.Error(t, mutate("status", pb.DirectedEdge_CAS, "sold", "in stock"))
+	require.NoError(t, mutate("status", pb.DirectedEdge_SET, "in stock", ""))
+	require.NoError(t, mutate("status", pb.DirectedEdge_CAS, "sold", "in stock"))
+	require.Equal(t, "sold", value("status"))
+	require.Error(t, mutate("status", pb.DirectedEdge_CAS, "sold", "in stock"))
+	require.NoError(t, mutate("views", pb.DirectedEdge_CAS, "10", "3"))
+	require.Equal(t, int64(10), value("views"))
+
+	require.Error(t, mutate("status", pb.DirectedEdge_ADD, "1", ""))
+	require.Error(t, mutate("scores", pb.DirectedEdge_ADD, "1", ""))
+	require.Error(t, mutate("views", pb.DirectedEdge_ADD, "one", ""))
+}
+
+func TestAppendOnly(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte(`
+		event: [uid] @appendonly .
+		title: string .
+	`), 1))
+
+	m := &pb.Mutations{GroupId: 1, StartTs: timestamp(), Edges: []*pb.DirectedEdge{
+		{Entity: 0x300, Attr: "event", ValueId: 0x301, Op: pb.DirectedEdge_SET},
+		{Entity: 0x300, Attr: "title", Value: []byte("run"), Op: pb.DirectedEdge_SET},
+		{Entity: 0x300, Attr: "event", ValueId: 0x302, Op: pb.DirectedEdge_SET},
+	}}
+	am, err := splitAppendOnly(m)
+	require.NoError(t, err)
+	require.True(t, am.AppendOnly)
+	require.Equal(t, m.StartTs, am.StartTs)
+	require.Len(t, am.Edges, 2)
+	require.Len(t, m.Edges, 1)
+	require.Equal(t, "title", m.Edges[0].Attr)
+
+	am2, err := splitAppendOnly(m)
+	require.NoError(t, err)
+	require.Nil(t, am2)
+	_, err = splitAppendOnly(&pb.Mutations{Edges: []*pb.DirectedEdge{
+		{Entity: 0x300, Attr: "event", ValueId: 0x301, Op: pb.DirectedEdge_DEL},
+	}})
+	require.Error(t, err)
+
+	// The edges are committed at the version of the mutation as soon as they're applied.
+	n := &node{}
+	require.Error(t, n.applyAppendOnly(context.Background(), am))
+	am.CommitTs = timestamp()
+	require.NoError(t, n.applyAppendOnly(context.Background(), am))
+	commitTs := timestamp()
+	require.NoError(t, n.applyAppendOnly(context.Background(), &pb.Mutations{
+		StartTs: am.StartTs, CommitTs: commitTs, AppendOnly: true, Edges: []*pb.DirectedEdge{
+			{Entity: 0x300, Attr: "event", ValueId: 0x303, Op: pb.DirectedEdge_SET},
+		}}))
+
+	pl, err := posting.GetNoStore(x.DataKey("event", 0x300))
+	require.NoError(t, err)
+	uids, err := pl.Uids(posting.ListOptions{ReadTs: commitTs})
+	require.NoError(t, err)
+	require.Equal(t, []uint64{0x301, 0x302, 0x303}, uids.Uids)
+
+	// A mutation applied after a commit at a higher version must be proposed again.
+	n.maxCommitTs = timestamp()
+	require.Equal(t, errStaleCommitTs, n.applyAppendOnly(context.Background(), &pb.Mutations{
+		StartTs: am.StartTs, CommitTs: commitTs, AppendOnly: true,
+		Edges: []*pb.DirectedEdge{
+			{Entity: 0x300, Attr: "event", ValueId: 0x304, Op: pb.DirectedEdge_SET},
+		}}))
+
+	require.Error(t, n.applyAppendOnly(context.Background(), &pb.Mutations{
+		StartTs: am.StartTs, CommitTs: timestamp(), AppendOnly: true, Edges: m.Edges}))
+}
+
+func TestFrozenPredicates(t *testing.T) {
+	g := &groupi{state: &pb.MembershipState{FrozenPredicates: map[string]uint32{
+		"name": x.PredicateReadOnly,
+		"age":  x.PredicateFrozen,
+	}}}
+
+	require.NoError(t, g.checkWritable("friend"))
+	require.NoError(t, g.checkReadable("friend"))
+
+	// A read-only predicate can be queried, but not mutated.
+	err := g.checkWritable("name")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Predicate name is read_only")
+	require.NoError(t, g.checkReadable("name"))
+
+	require.Error(t, g.checkWritable("age"))
+	err = g.checkReadable("age")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "Predicate age is frozen")
+
+	// Nothing is frozen before the first membership state is received.
+	require.NoError(t, (&groupi{}).checkWritable("name"))
+}
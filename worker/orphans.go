@@ -0,0 +1,176 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"fmt"
+
+	"github.com/golang/glog"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/algo"
+	"github.com/dgraph-io/dgraph/protos/pb"
+)
+
+// Kinds of GraphIssue.
+const (
+	// GraphOrphan is a node without any uid edge from or to another node.
+	GraphOrphan = "orphan"
+	// GraphDangling is a uid edge to a node which has no predicate.
+	GraphDangling = "dangling"
+)
+
+// graphScanBatch is the number of subjects whose uid edges are read by one task.
+const graphScanBatch = 10000
+
+// GraphIssue is a node or an edge left behind by partial deletes.
+type GraphIssue struct {
+	Kind string `json:"kind"`
+	Uid  string `json:"uid"`
+	// Predicate and Object are the predicate and the object of a dangling edge.
+	Predicate string `json:"predicate,omitempty"`
+	Object    string `json:"object,omitempty"`
+}
+
+// FindGraphIssues reads all the predicates of the cluster, and calls fn with the dangling edges
+// and then with the orphan nodes. The uids of the nodes are kept in memory while it runs.
+func FindGraphIssues(ctx context.Context, fn func(*GraphIssue) error) error {
+	ts, err := Timestamps(ctx, &pb.Num{ReadOnly: true})
+	if err != nil {
+		return err
+	}
+	readTs := ts.ReadOnly
+	schema, err := GetSchemaOverNetwork(ctx, &pb.SchemaRequest{Fields: []string{"type"}})
+	if err != nil {
+		return err
+	}
+
+	// nodes are the nodes with any predicate, and linked the ones with a uid edge from or to
+	// another node.
+	nodes, linked := &pb.List{}, &pb.List{}
+	var uidPreds []string
+	for _, s := range schema {
+		subjects, err := hasUids(ctx, s.Predicate, readTs)
+		if err != nil {
+			return err
+		}
+		nodes = algo.MergeSorted([]*pb.List{nodes, subjects})
+		if s.Type != "uid" {
+			continue
+		}
+		uidPreds = append(uidPreds, s.Predicate)
+		err = forEachUidEdges(ctx, s.Predicate, subjects, readTs,
+			func(subjects *pb.List, objects []*pb.List) error {
+				linked = algo.MergeSorted(append(objects, linked, subjects))
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+	}
+	glog.Infof("Found %d nodes in %d predicates at ts %d", len(nodes.Uids), len(schema), readTs)
+
+	for _, pred := range uidPreds {
+		subjects, err := hasUids(ctx, pred, readTs)
+		if err != nil {
+			return err
+		}
+		err = forEachUidEdges(ctx, pred, subjects, readTs,
+			func(subjects *pb.List, objects []*pb.List) error {
+				for i, uid := range subjects.Uids {
+					for _, obj := range objects[i].Uids {
+						if algo.IndexOf(nodes, obj) >= 0 {
+							continue
+						}
+						err := fn(&GraphIssue{
+							Kind:      GraphDangling,
+							Uid:       fmt.Sprintf("%#x", uid),
+							Predicate: pred,
+							Object:    fmt.Sprintf("%#x", obj),
+						})
+						if err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, uid := range nodes.Uids {
+		if algo.IndexOf(linked, uid) >= 0 {
+			continue
+		}
+		if err := fn(&GraphIssue{Kind: GraphOrphan, Uid: fmt.Sprintf("%#x", uid)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hasUids returns the subjects of the predicate.
+func hasUids(ctx context.Context, attr string, readTs uint64) (*pb.List, error) {
+	reply, err := ProcessTaskOverNetwork(ctx, &pb.Query{
+		Attr:    attr,
+		SrcFunc: &pb.SrcFunction{Name: "has"},
+		ReadTs:  readTs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(reply.UidMatrix) == 0 {
+		return &pb.List{}, nil
+	}
+	return reply.UidMatrix[0], nil
+}
+
+// forEachUidEdges calls fn with batches of the subjects, and the objects of their edges.
+func forEachUidEdges(ctx context.Context, attr string, subjects *pb.List, readTs uint64,
+	fn func(subjects *pb.List, objects []*pb.List) error) error {
+	return forEachBatch(ctx, attr, subjects, readTs,
+		func(subjects *pb.List, reply *pb.Result) error {
+			return fn(subjects, reply.UidMatrix)
+		})
+}
+
+// forEachBatch calls fn with batches of the subjects, and the result of the task reading their
+// values or edges.
+func forEachBatch(ctx context.Context, attr string, subjects *pb.List, readTs uint64,
+	fn func(subjects *pb.List, reply *pb.Result) error) error {
+	for start := 0; start < len(subjects.Uids); start += graphScanBatch {
+		end := start + graphScanBatch
+		if end > len(subjects.Uids) {
+			end = len(subjects.Uids)
+		}
+		batch := &pb.List{Uids: subjects.Uids[start:end]}
+		reply, err := ProcessTaskOverNetwork(ctx, &pb.Query{
+			Attr:    attr,
+			UidList: batch,
+			ReadTs:  readTs,
+		})
+		if err != nil {
+			return err
+		}
+		if err := fn(batch, reply); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -106,7 +106,8 @@ func uniqueKey() string {
 }
 
 var errInternalRetry = errors.New("Retry Raft proposal internally")
-var errUnableToServe = errors.New("Server overloaded with pending proposals. Please retry later")
+var errUnableToServe = x.WithCode(
+	errors.New("Server overloaded with pending proposals. Please retry later"), x.CodeRetriable)
 
 // proposeAndWait sends a proposal through RAFT. It waits on a channel for the proposal
 // to be applied(written to WAL) to all the nodes in the group.
@@ -157,6 +158,12 @@ func (n *node) proposeAndWait(ctx context.Context, proposal *pb.Proposal) (perr
 				continue
 			} else if err := ValidateAndConvert(edge, &su); err != nil {
 				return err
+			} else if err := checkValueSize(edge, &su); err != nil {
+				return err
+			} else if err := checkFacets(edge, &su); err != nil {
+				return err
+			} else if err := offloadValue(edge, &su); err != nil {
+				return err
 			}
 		}
 		for _, schema := range proposal.Mutations.Schema {
@@ -202,7 +209,7 @@ func (n *node) proposeAndWait(ctx context.Context, proposal *pb.Proposal) (perr
 		if err != nil {
 			return err
 		}
-		if err = n.Raft().Propose(cctx, data); err != nil {
+		if err = n.propose(cctx, data); err != nil {
 			return errors.Wrapf(err, "While proposing")
 		}
 
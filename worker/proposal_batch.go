@@ -0,0 +1,198 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"go.etcd.io/etcd/raft/raftpb"
+	"golang.org/x/net/context"
+)
+
+// maxProposalBatchLatency bounds the latency, as every mutation waits for it.
+const maxProposalBatchLatency = time.Second
+
+// ProposalBatchOptions are the limits of the batches of proposals sent through Raft. The
+// proposals of a batch are appended to the Raft log and replicated together.
+type ProposalBatchOptions struct {
+	// MaxBytes is the size over which a batch is sent without waiting for more proposals.
+	MaxBytes int
+	// MaxLatency is how long the first proposal of a batch waits for more proposals. 0 only
+	// batches the proposals which are already waiting.
+	MaxLatency time.Duration
+}
+
+// ProposalBatchStats counts the proposals sent through Raft since the Alpha started.
+type ProposalBatchStats struct {
+	Batches   uint64
+	Proposals uint64
+	Bytes     uint64
+}
+
+var proposalBatching = struct {
+	sync.Mutex
+	opts  ProposalBatchOptions
+	stats ProposalBatchStats
+}{opts: ProposalBatchOptions{MaxBytes: 1 << 20}}
+
+// ProposalBatching returns the current limits of the batches of proposals.
+func ProposalBatching() ProposalBatchOptions {
+	proposalBatching.Lock()
+	defer proposalBatching.Unlock()
+	return proposalBatching.opts
+}
+
+// Validate returns an error if the limits of the batches are invalid.
+func (opts ProposalBatchOptions) Validate() error {
+	if opts.MaxBytes <= 0 {
+		return errors.Errorf("Invalid max bytes %d for proposal batches", opts.MaxBytes)
+	}
+	if opts.MaxLatency < 0 || opts.MaxLatency > maxProposalBatchLatency {
+		return errors.Errorf("Invalid max latency %v for proposal batches: expected 0 to %v",
+			opts.MaxLatency, maxProposalBatchLatency)
+	}
+	return nil
+}
+
+// SetProposalBatching changes the limits of the batches of proposals, starting with the next
+// batch.
+func SetProposalBatching(opts ProposalBatchOptions) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	proposalBatching.Lock()
+	defer proposalBatching.Unlock()
+	proposalBatching.opts = opts
+	return nil
+}
+
+// ProposalBatchingStats returns the number of batches and proposals sent through Raft.
+func ProposalBatchingStats() ProposalBatchStats {
+	proposalBatching.Lock()
+	defer proposalBatching.Unlock()
+	return proposalBatching.stats
+}
+
+type pendingProposal struct {
+	ctx   context.Context
+	data  []byte
+	errCh chan error
+}
+
+// propose adds the proposal to the next batch, and returns once the batch has been handed to
+// Raft. As with raft.Node.Propose, it doesn't wait for the proposal to be committed.
+func (n *node) propose(ctx context.Context, data []byte) error {
+	p := &pendingProposal{ctx: ctx, data: data, errCh: make(chan error, 1)}
+	select {
+	case n.proposeCh <- p:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case err := <-p.errCh:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// collectBatch returns the batch started by the first proposal, with the proposals received
+// from ch until the batch reaches the max bytes or the max latency has passed.
+func collectBatch(first *pendingProposal, ch <-chan *pendingProposal,
+	opts ProposalBatchOptions) []*pendingProposal {
+
+	batch := []*pendingProposal{first}
+	size := len(first.data)
+	var timeout <-chan time.Time
+	if opts.MaxLatency > 0 {
+		timer := time.NewTimer(opts.MaxLatency)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	for size < opts.MaxBytes {
+		var p *pendingProposal
+		if timeout == nil {
+			select {
+			case p = <-ch:
+			default:
+				return batch
+			}
+		} else {
+			select {
+			case p = <-ch:
+			case <-timeout:
+				return batch
+			}
+		}
+		batch = append(batch, p)
+		size += len(p.data)
+	}
+	return batch
+}
+
+// batchProposals sends the proposals to Raft in batches, so that they're written to the log
+// and replicated in one go.
+func (n *node) batchProposals() {
+	defer n.closer.Done() // CLOSER:1
+
+	for {
+		var first *pendingProposal
+		select {
+		case first = <-n.proposeCh:
+		case <-n.closer.HasBeenClosed():
+			return
+		}
+		batch := collectBatch(first, n.proposeCh, ProposalBatching())
+
+		// Proposals whose context is done while they waited aren't sent.
+		var entries []raftpb.Entry
+		var sent []*pendingProposal
+		var size int
+		for _, p := range batch {
+			if err := p.ctx.Err(); err != nil {
+				p.errCh <- err
+				continue
+			}
+			entries = append(entries, raftpb.Entry{Data: p.data})
+			sent = append(sent, p)
+			size += len(p.data)
+		}
+
+		var err error
+		switch len(sent) {
+		case 0:
+			continue
+		case 1:
+			// Propose reports a dropped proposal, e.g. if there's no leader.
+			err = n.Raft().Propose(sent[0].ctx, sent[0].data)
+		default:
+			// A dropped batch isn't reported, the proposals get retried after their timeout.
+			err = n.Raft().Step(n.ctx, raftpb.Message{Type: raftpb.MsgProp, Entries: entries})
+		}
+		for _, p := range sent {
+			p.errCh <- err
+		}
+
+		proposalBatching.Lock()
+		proposalBatching.stats.Batches++
+		proposalBatching.stats.Proposals += uint64(len(sent))
+		proposalBatching.stats.Bytes += uint64(size)
+		proposalBatching.Unlock()
+	}
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectBatch(t *testing.T) {
+	ch := make(chan *pendingProposal, 10)
+	newProposal := func(size int) *pendingProposal {
+		return &pendingProposal{data: make([]byte, size)}
+	}
+	for i := 0; i < 3; i++ {
+		ch <- newProposal(10)
+	}
+
+	// Without latency, only the waiting proposals are batched.
+	batch := collectBatch(newProposal(10), ch, ProposalBatchOptions{MaxBytes: 100})
+	require.Equal(t, 4, len(batch))
+
+	// The batch is sent once it reaches the max bytes.
+	for i := 0; i < 3; i++ {
+		ch <- newProposal(10)
+	}
+	batch = collectBatch(newProposal(10), ch, ProposalBatchOptions{MaxBytes: 20})
+	require.Equal(t, 2, len(batch))
+	require.Equal(t, 2, len(ch))
+	<-ch
+	<-ch
+
+	// With latency, the proposals received in the meantime join the batch.
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		ch <- newProposal(10)
+	}()
+	batch = collectBatch(newProposal(10), ch,
+		ProposalBatchOptions{MaxBytes: 100, MaxLatency: time.Second / 2})
+	require.Equal(t, 2, len(batch))
+}
+
+func TestSetProposalBatching(t *testing.T) {
+	defer func(opts ProposalBatchOptions) {
+		require.NoError(t, SetProposalBatching(opts))
+	}(ProposalBatching())
+
+	opts := ProposalBatchOptions{MaxBytes: 4 << 20, MaxLatency: 5 * time.Millisecond}
+	require.NoError(t, SetProposalBatching(opts))
+	require.Equal(t, opts, ProposalBatching())
+
+	require.Error(t, SetProposalBatching(ProposalBatchOptions{MaxBytes: 0}))
+	require.Error(t, SetProposalBatching(ProposalBatchOptions{MaxBytes: 1, MaxLatency: -1}))
+	require.Error(t, SetProposalBatching(
+		ProposalBatchOptions{MaxBytes: 1, MaxLatency: 2 * time.Second}))
+	require.Equal(t, opts, ProposalBatching())
+}
@@ -0,0 +1,135 @@
+/*
+ * Copyright 2016-2018 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// Results of checkpwdlock.
+const (
+	pwdMatch    = "match"
+	pwdMismatch = "mismatch"
+	pwdLocked   = "locked"
+)
+
+// maxPwdFailures bounds the number of passwords whose failures are tracked, so that
+// checkpwdlock calls on many nodes can't grow the tracker without limit.
+const maxPwdFailures = 1 << 16
+
+type pwdKey struct {
+	attr string
+	uid  uint64
+}
+
+type pwdAttempts struct {
+	failures int
+	// last is the time of the last failure, or the end of the lockout once locked.
+	last   time.Time
+	locked bool
+}
+
+// pwdTracker counts the consecutive failed checkpwdlock calls of each password, to lock it
+// out after x.Config.PasswordMaxFailures of them. The failures are tracked by each Alpha
+// separately.
+type pwdTracker struct {
+	sync.Mutex
+	attempts map[pwdKey]*pwdAttempts
+}
+
+var pwdFailures = &pwdTracker{attempts: make(map[pwdKey]*pwdAttempts)}
+
+// stale tells if the attempts don't count anymore at now.
+func (a *pwdAttempts) stale(now time.Time) bool {
+	if a.locked {
+		return !now.Before(a.last)
+	}
+	return now.Sub(a.last) >= x.Config.PasswordLockout
+}
+
+// isLocked tells if the password of the node is locked out.
+func (t *pwdTracker) isLocked(attr string, uid uint64, now time.Time) bool {
+	t.Lock()
+	defer t.Unlock()
+	key := pwdKey{attr: attr, uid: uid}
+	a, ok := t.attempts[key]
+	if !ok {
+		return false
+	}
+	if a.stale(now) {
+		delete(t.attempts, key)
+		return false
+	}
+	return a.locked
+}
+
+// record records the result of a check of the password of the node, and returns the result
+// of checkpwdlock.
+func (t *pwdTracker) record(attr string, uid uint64, match bool, now time.Time) string {
+	t.Lock()
+	defer t.Unlock()
+	key := pwdKey{attr: attr, uid: uid}
+	if match {
+		delete(t.attempts, key)
+		return pwdMatch
+	}
+	if x.Config.PasswordMaxFailures <= 0 {
+		return pwdMismatch
+	}
+
+	a, ok := t.attempts[key]
+	if !ok || a.stale(now) {
+		if len(t.attempts) >= maxPwdFailures {
+			t.sweep(now)
+		}
+		a = &pwdAttempts{}
+		t.attempts[key] = a
+	}
+	a.failures++
+	a.last = now
+	if a.failures >= x.Config.PasswordMaxFailures {
+		a.locked = true
+		a.last = now.Add(x.Config.PasswordLockout)
+	}
+	return pwdMismatch
+}
+
+// sweep removes the stale attempts, or all of them if none is stale.
+func (t *pwdTracker) sweep(now time.Time) {
+	for key, a := range t.attempts {
+		if a.stale(now) {
+			delete(t.attempts, key)
+		}
+	}
+	if len(t.attempts) >= maxPwdFailures {
+		t.attempts = make(map[pwdKey]*pwdAttempts)
+	}
+}
+
+// checkPwdLock returns the result of checkpwdlock for the password of the node.
+func checkPwdLock(attr string, uid uint64, plain, encrypted string) string {
+	now := time.Now()
+	if pwdFailures.isLocked(attr, uid, now) {
+		return pwdLocked
+	}
+	err := types.VerifyPassword(plain, encrypted)
+	return pwdFailures.record(attr, uid, err == nil, now)
+}
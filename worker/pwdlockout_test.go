@@ -0,0 +1,71 @@
+/*
+ * Copyright 2016-2018 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgraph/types"
+	"github.com/dgraph-io/dgraph/x"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPwdLockout(t *testing.T) {
+	defer func(cfg x.Options) { x.Config = cfg }(x.Config)
+	x.Config.PasswordMaxFailures = 2
+	x.Config.PasswordLockout = time.Minute
+	tracker := &pwdTracker{attempts: make(map[pwdKey]*pwdAttempts)}
+	now := time.Now()
+
+	require.Equal(t, pwdMismatch, tracker.record("password", 1, false, now))
+	require.False(t, tracker.isLocked("password", 1, now))
+	// A success resets the failures.
+	require.Equal(t, pwdMatch, tracker.record("password", 1, true, now))
+	require.Equal(t, pwdMismatch, tracker.record("password", 1, false, now))
+	require.False(t, tracker.isLocked("password", 1, now))
+
+	require.Equal(t, pwdMismatch, tracker.record("password", 1, false, now))
+	require.True(t, tracker.isLocked("password", 1, now))
+	require.True(t, tracker.isLocked("password", 1, now.Add(59*time.Second)))
+	// Other nodes and predicates aren't locked out.
+	require.False(t, tracker.isLocked("password", 2, now))
+	require.False(t, tracker.isLocked("pin", 1, now))
+
+	require.False(t, tracker.isLocked("password", 1, now.Add(time.Minute)))
+	require.Empty(t, tracker.attempts)
+
+	// Failures older than the lockout don't count anymore.
+	require.Equal(t, pwdMismatch, tracker.record("password", 1, false, now))
+	later := now.Add(2 * time.Minute)
+	require.Equal(t, pwdMismatch, tracker.record("password", 1, false, later))
+	require.False(t, tracker.isLocked("password", 1, later))
+}
+
+func TestCheckPwdLock(t *testing.T) {
+	defer func(cfg x.Options) { x.Config = cfg }(x.Config)
+	x.Config.PasswordMaxFailures = 1
+	x.Config.PasswordLockout = time.Minute
+	defer func() { delete(pwdFailures.attempts, pwdKey{attr: "password", uid: 10}) }()
+
+	encrypted, err := types.Encrypt("123456")
+	require.NoError(t, err)
+	require.Equal(t, pwdMatch, checkPwdLock("password", 10, "123456", encrypted))
+	require.Equal(t, pwdMismatch, checkPwdLock("password", 10, "654321", encrypted))
+	// The right password isn't even checked while locked out.
+	require.Equal(t, pwdLocked, checkPwdLock("password", 10, "123456", encrypted))
+}
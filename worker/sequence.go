@@ -0,0 +1,67 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"golang.org/x/net/context"
+	"sync"
+
+	"github.com/dgraph-io/dgraph/conn"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// sequences holds the values of each sequence leased from Zero which haven't been handed out.
+var sequences = struct {
+	sync.Mutex
+	leases map[string]*pb.AssignedIds
+}{leases: make(map[string]*pb.AssignedIds)}
+
+func leaseSequence(ctx context.Context, name string, num uint64) (*pb.AssignedIds, error) {
+	pl := groups().Leader(0)
+	if pl == nil {
+		return nil, conn.ErrNoConnection
+	}
+	c := pb.NewZeroClient(pl.Get())
+	return c.LeaseSequence(ctx, &pb.Num{Val: num, Sequence: name})
+}
+
+// NextSequenceValues returns the next n values of the named sequence. The values are leased from
+// Zero in blocks of at least x.Config.SequenceLease values, which this Alpha hands out in order.
+func NextSequenceValues(ctx context.Context, name string, n int) ([]uint64, error) {
+	sequences.Lock()
+	defer sequences.Unlock()
+
+	vals := make([]uint64, 0, n)
+	lease := sequences.leases[name]
+	for len(vals) < n {
+		if lease == nil || lease.StartId > lease.EndId {
+			num := uint64(n - len(vals))
+			if min := uint64(x.Config.SequenceLease); num < min {
+				num = min
+			}
+			var err error
+			if lease, err = leaseSequence(ctx, name, num); err != nil {
+				return nil, err
+			}
+			sequences.leases[name] = lease
+		}
+		vals = append(vals, lease.StartId)
+		lease.StartId++
+	}
+	return vals, nil
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/dgraph-io/badger"
+	farm "github.com/dgryski/go-farm"
+	otrace "go.opencensus.io/trace"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/x"
+)
+
+// sketches are the HyperLogLog sketches of the distinct values of the predicates, which answer
+// approx_count_distinct at root. The sketch of a predicate is built by scanning it the first time
+// it's requested, and then kept up to date by the mutations. The values which are deleted, or
+// set by transactions which are aborted, are still counted until the sketch is dropped with the
+// predicate or rebuilt after a schema change.
+var sketches = struct {
+	sync.Mutex
+	m map[string]*x.HyperLogLog
+}{m: make(map[string]*x.HyperLogLog)}
+
+// postingHash returns the hash the sketches use for the value of a posting, or its uid.
+func postingHash(p *pb.Posting) uint64 {
+	if p.PostingType == pb.Posting_REF {
+		return uidHash(p.Uid)
+	}
+	return farm.Fingerprint64(p.Value)
+}
+
+func uidHash(uid uint64) uint64 {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uid)
+	return farm.Fingerprint64(b[:])
+}
+
+// addToSketch adds the value of the edge to the sketch of its predicate, if it was built.
+func addToSketch(edge *pb.DirectedEdge) {
+	if edge.Op != pb.DirectedEdge_SET {
+		return
+	}
+	sketches.Lock()
+	defer sketches.Unlock()
+	h, ok := sketches.m[edge.Attr]
+	if !ok {
+		return
+	}
+	if edge.ValueType == pb.Posting_UID {
+		h.Add(uidHash(edge.ValueId))
+	} else {
+		h.Add(farm.Fingerprint64(edge.Value))
+	}
+}
+
+// dropSketches drops the sketch of the predicate, or all of them if attr is empty.
+func dropSketches(attr string) {
+	sketches.Lock()
+	defer sketches.Unlock()
+	if attr == "" {
+		sketches.m = make(map[string]*x.HyperLogLog)
+		return
+	}
+	delete(sketches.m, attr)
+}
+
+// approxDistinctValues returns the estimated number of distinct values of the predicate, or
+// of distinct nodes it points to for a uid predicate.
+func approxDistinctValues(ctx context.Context, attr string, readTs uint64) (uint64, error) {
+	sketches.Lock()
+	h, ok := sketches.m[attr]
+	if ok {
+		defer sketches.Unlock()
+		return h.Count(), nil
+	}
+	sketches.Unlock()
+
+	h, err := buildSketch(ctx, attr, readTs)
+	if err != nil {
+		return 0, err
+	}
+	sketches.Lock()
+	defer sketches.Unlock()
+	if built, ok := sketches.m[attr]; ok {
+		// Another request built it first, and it may have been updated since.
+		return built.Count(), nil
+	}
+	sketches.m[attr] = h
+	return h.Count(), nil
+}
+
+// buildSketch scans the values of the predicate at readTs.
+func buildSketch(ctx context.Context, attr string, readTs uint64) (*x.HyperLogLog, error) {
+	span := otrace.FromContext(ctx)
+	stop := x.SpanTimer(span, "buildSketch: "+attr)
+	defer stop()
+
+	txn := pstore.NewTransactionAt(readTs, false)
+	defer txn.Discard()
+
+	pk := x.ParsedKey{Attr: attr}
+	itOpt := badger.DefaultIteratorOptions
+	itOpt.PrefetchValues = false
+	itOpt.AllVersions = true
+	itOpt.Prefix = pk.DataPrefix()
+	it := txn.NewIterator(itOpt)
+	defer it.Close()
+
+	h := x.NewHyperLogLog()
+	var prevKey []byte
+	var n int
+	for it.Seek(itOpt.Prefix); it.Valid(); {
+		item := it.Item()
+		if bytes.Equal(item.Key(), prevKey) {
+			it.Next()
+			continue
+		}
+		prevKey = append(prevKey[:0], item.Key()...)
+
+		l, err := posting.ReadPostingList(item.KeyCopy(nil), it)
+		if err != nil {
+			return nil, err
+		}
+		if err := l.Iterate(readTs, 0, func(p *pb.Posting) error {
+			h.Add(postingHash(p))
+			return nil
+		}); err != nil {
+			return nil, err
+		}
+
+		if n++; n%100000 == 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		}
+	}
+	return h, nil
+}
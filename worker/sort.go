@@ -17,6 +17,8 @@
 package worker
 
 import (
+	"math"
+	"math/rand"
 	"sort"
 	"strings"
 	"time"
@@ -52,6 +54,13 @@ type sortresult struct {
 
 // SortOverNetwork sends sort query over the network.
 func SortOverNetwork(ctx context.Context, q *pb.SortMessage) (*pb.SortResult, error) {
+	if q.Order[0].Random {
+		// Random orders don't read any predicate.
+		return randomSort(ctx, q), nil
+	}
+	if err := groups().checkReadable(q.Order[0].Attr); err != nil {
+		return &emptySortResult, err
+	}
 	gid, err := groups().BelongsToReadOnly(q.Order[0].Attr)
 	if err != nil {
 		return &emptySortResult, err
@@ -113,6 +122,132 @@ func (w *grpcWorker) Sort(ctx context.Context, s *pb.SortMessage) (*pb.SortResul
 	}
 }
 
+// randomSort orders each uid list randomly. The lists are paginated by reservoir sampling
+// offset + count of their uids, which are then shuffled, so that the lists are never copied or
+// shuffled entirely. The same seed gives the same order.
+func randomSort(ctx context.Context, ts *pb.SortMessage) *pb.SortResult {
+	span := otrace.FromContext(ctx)
+	span.Annotatef(nil, "randomSort with seed %d", ts.Order[0].Seed)
+
+	count := int(ts.Count)
+	if count < 0 {
+		// The last uids of a random order are as random as the first ones.
+		count = -count
+	}
+	rng := rand.New(rand.NewSource(ts.Order[0].Seed))
+	r := &pb.SortResult{UidMatrix: make([]*pb.List, 0, len(ts.UidMatrix))}
+	for _, ul := range ts.UidMatrix {
+		uids := ul.Uids
+		k := len(uids)
+		if count > 0 && int(ts.Offset)+count < k {
+			k = int(ts.Offset) + count
+		}
+		reservoir := make([]uint64, k)
+		copy(reservoir, uids[:k])
+		for i := k; i < len(uids); i++ {
+			if j := rng.Intn(i + 1); j < k {
+				reservoir[j] = uids[i]
+			}
+		}
+		rng.Shuffle(len(reservoir), func(i, j int) {
+			reservoir[i], reservoir[j] = reservoir[j], reservoir[i]
+		})
+		start, end := x.PageRange(0, int(ts.Offset), len(reservoir))
+		r.UidMatrix = append(r.UidMatrix, &pb.List{Uids: reservoir[start:end]})
+	}
+	return r
+}
+
+// sortByCount orders each uid list by the count of the edges of the predicate of the order, or
+// of its reverse edges, reading the buckets of the count index from the highest count when the
+// order is descending. The uids in a bucket are in the order of the uids. As the nodes without
+// edges aren't in the index, they have the lowest count, and a descending order stops reading
+// the index once every list has offset + count uids.
+func sortByCount(ctx context.Context, ts *pb.SortMessage) (*pb.SortResult, error) {
+	span := otrace.FromContext(ctx)
+	span.Annotate(nil, "sortByCount")
+
+	order := ts.Order[0]
+	if !schema.State().HasCount(order.Attr) {
+		return nil, errors.Errorf("Need @count directive in schema for attr: %s to sort by its"+
+			" count", order.Attr)
+	}
+	if order.Reverse && !schema.State().IsReversed(order.Attr) {
+		return nil, errors.Errorf("Predicate %s doesn't have reverse edge", order.Attr)
+	}
+	if err := indexBuildError(order.Attr, "count"); err != nil {
+		return nil, err
+	}
+
+	need := int(ts.Offset + ts.Count)
+	sorted := make([][]uint64, len(ts.UidMatrix))
+	done := func(i int) bool {
+		return order.Desc && ts.Count > 0 && len(sorted[i]) >= need
+	}
+
+	iterOpt := badger.DefaultIteratorOptions
+	iterOpt.PrefetchValues = false
+	iterOpt.Reverse = order.Desc
+	iterOpt.Prefix = x.ParsedKey{Attr: order.Attr}.CountPrefix(order.Reverse)
+	var seekKey []byte
+	if order.Desc {
+		// We need to reach the bucket of the highest count.
+		seekKey = x.CountKey(order.Attr, math.MaxUint32, order.Reverse)
+	}
+	txn := pstore.NewTransactionAt(ts.ReadTs, false)
+	defer txn.Discard()
+	itr := txn.NewIterator(iterOpt)
+	defer itr.Close()
+
+BUCKETS:
+	for itr.Seek(seekKey); itr.Valid(); itr.Next() {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Don't put the count keys in memory.
+		pl, err := posting.GetNoStore(itr.Item().KeyCopy(nil))
+		if err != nil {
+			return nil, err
+		}
+		countRead(ctx, pl)
+		for i, ul := range ts.UidMatrix {
+			if done(i) {
+				continue
+			}
+			res, err := pl.Uids(posting.ListOptions{Intersect: ul, ReadTs: ts.ReadTs})
+			if err != nil {
+				return nil, err
+			}
+			sorted[i] = append(sorted[i], res.Uids...)
+		}
+		for i := range ts.UidMatrix {
+			if !done(i) {
+				continue BUCKETS
+			}
+		}
+		break
+	}
+
+	r := &pb.SortResult{UidMatrix: make([]*pb.List, 0, len(ts.UidMatrix))}
+	for i, ul := range ts.UidMatrix {
+		uids := sorted[i]
+		if !done(i) {
+			found := &pb.List{Uids: append(uids[:0:0], uids...)}
+			sort.Slice(found.Uids, func(a, b int) bool { return found.Uids[a] < found.Uids[b] })
+			// The nodes without edges.
+			rest := algo.Difference(ul, found).Uids
+			if order.Desc {
+				uids = append(uids, rest...)
+			} else {
+				uids = append(rest, uids...)
+			}
+		}
+		start, end := x.PageRange(int(ts.Count), int(ts.Offset), len(uids))
+		r.UidMatrix = append(r.UidMatrix, &pb.List{Uids: uids[start:end]})
+	}
+	return r, nil
+}
+
 var (
 	errContinue = errors.Errorf("Continue processing buckets")
 	errDone     = errors.Errorf("Done processing buckets")
@@ -198,6 +333,10 @@ func sortWithIndex(ctx context.Context, ts *pb.SortMessage) *sortresult {
 	if !schema.State().IsIndexed(order.Attr) {
 		return resultWithError(errors.Errorf("Attribute %s is not indexed.", order.Attr))
 	}
+	// Sorting without the index takes over while it's built.
+	if err := indexBuildError(order.Attr, "index"); err != nil {
+		return resultWithError(err)
+	}
 
 	tokenizers := schema.State().Tokenizer(order.Attr)
 	var tokenizer tok.Tokenizer
@@ -221,6 +360,12 @@ func sortWithIndex(ctx context.Context, ts *pb.SortMessage) *sortresult {
 		return resultWithError(errors.Errorf("Attribute %s is not sortable.", order.Attr))
 	}
 
+	// The index is in the order of the collation of the predicate.
+	if c := collation(order); c != schema.State().Collation(order.Attr) {
+		return resultWithError(errors.Errorf(
+			"Index of attribute %s is not in the order of collation %s.", order.Attr, c))
+	}
+
 	// Iterate over every bucket / token.
 	iterOpt := badger.DefaultIteratorOptions
 	iterOpt.PrefetchValues = false
@@ -375,8 +520,10 @@ func multiSort(ctx context.Context, r *sortresult, ts *pb.SortMessage) error {
 	}
 
 	desc := make([]bool, 0, len(ts.Order))
+	collations := make([]string, 0, len(ts.Order))
 	for _, o := range ts.Order {
 		desc = append(desc, o.Desc)
+		collations = append(collations, collation(o))
 	}
 
 	// Values have been accumulated, now we do the multisort for each list.
@@ -387,7 +534,7 @@ func multiSort(ctx context.Context, r *sortresult, ts *pb.SortMessage) error {
 			x.AssertTrue(idx >= 0)
 			vals[j] = sortVals[idx]
 		}
-		if err := types.Sort(vals, ul, desc); err != nil {
+		if err := types.SortCollated(vals, ul, desc, collations); err != nil {
 			return err
 		}
 		// Paginate
@@ -426,6 +573,9 @@ func processSort(ctx context.Context, ts *pb.SortMessage) (*pb.SortResult, error
 			"We do not yet support negative or infinite count with sorting: %s %d. "+
 				"Try flipping order and return first few elements instead.", ts.Order[0].Attr, ts.Count)
 	}
+	if ts.Order[0].Count {
+		return sortByCount(ctx, ts)
+	}
 	// TODO (pawan) - Why check only the first attribute, what if other attributes are of list type?
 	if schema.State().IsList(ts.Order[0].Attr) {
 		return nil, errors.Errorf("Sorting not supported on attr: %s of type: [scalar]",
@@ -530,6 +680,7 @@ func intersectBucket(ctx context.Context, ts *pb.SortMessage, token string,
 	if err != nil {
 		return err
 	}
+	countRead(ctx, pl)
 	var vals []types.Val
 
 	// For each UID list, we need to intersect with the index bucket.
@@ -690,7 +841,7 @@ func sortByValue(ctx context.Context, ts *pb.SortMessage, ul *pb.List,
 		default:
 			uid := ul.Uids[i]
 			uids = append(uids, uid)
-			val, err := fetchValue(uid, order.Attr, order.Langs, typ, ts.ReadTs)
+			val, err := fetchValue(ctx, uid, order.Attr, order.Langs, typ, ts.ReadTs)
 			if err != nil {
 				// Value couldn't be found or couldn't be converted to the sort
 				// type.  By using a nil Value, it will appear at the
@@ -700,7 +851,8 @@ func sortByValue(ctx context.Context, ts *pb.SortMessage, ul *pb.List,
 			values = append(values, []types.Val{val})
 		}
 	}
-	err := types.Sort(values, &pb.List{Uids: uids}, []bool{order.Desc})
+	err := types.SortCollated(values, &pb.List{Uids: uids}, []bool{order.Desc},
+		[]string{collation(order)})
 	ul.Uids = uids
 	if len(ts.Order) > 1 {
 		for _, v := range values {
@@ -710,14 +862,24 @@ func sortByValue(ctx context.Context, ts *pb.SortMessage, ul *pb.List,
 	return multiSortVals, err
 }
 
+// collation returns the language whose collation orders the strings of the sort order: the one
+// given with the order, else the one of the predicate. It's empty if they are ordered bytewise.
+func collation(order *pb.Order) string {
+	if order.Collation != "" {
+		return order.Collation
+	}
+	return schema.State().Collation(order.Attr)
+}
+
 // fetchValue gets the value for a given UID.
-func fetchValue(uid uint64, attr string, langs []string, scalar types.TypeID,
+func fetchValue(ctx context.Context, uid uint64, attr string, langs []string, scalar types.TypeID,
 	readTs uint64) (types.Val, error) {
 	// Don't put the values in memory
 	pl, err := posting.GetNoStore(x.DataKey(attr, uid))
 	if err != nil {
 		return types.Val{}, err
 	}
+	countRead(ctx, pl)
 
 	src, err := pl.ValueFor(readTs, langs)
 
@@ -17,9 +17,13 @@
 package worker
 
 import (
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
 )
 
 func TestRemoveDuplicates(t *testing.T) {
@@ -54,3 +58,37 @@ func TestRemoveDuplicates(t *testing.T) {
 		require.Equal(t, set, toSet(test.setOut))
 	}
 }
+
+func TestRandomSort(t *testing.T) {
+	uids := &pb.List{}
+	for i := uint64(1); i <= 100; i++ {
+		uids.Uids = append(uids.Uids, i)
+	}
+	randomSortOf := func(seed int64, offset, count int32) []uint64 {
+		res := randomSort(context.Background(), &pb.SortMessage{
+			Order:     []*pb.Order{{Random: true, Seed: seed}},
+			UidMatrix: []*pb.List{uids, {}},
+			Offset:    offset,
+			Count:     count,
+		})
+		require.Len(t, res.UidMatrix, 2)
+		require.Empty(t, res.UidMatrix[1].Uids)
+		return res.UidMatrix[0].Uids
+	}
+
+	// All the uids are kept without pagination, in another order.
+	all := randomSortOf(1, 0, 0)
+	require.NotEqual(t, uids.Uids, all)
+	sorted := append([]uint64{}, all...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	require.Equal(t, uids.Uids, sorted)
+
+	// The same seed gives the same order.
+	require.Equal(t, randomSortOf(7, 0, 10), randomSortOf(7, 0, 10))
+	require.NotEqual(t, randomSortOf(7, 0, 10), randomSortOf(8, 0, 10))
+
+	require.Len(t, randomSortOf(1, 0, 10), 10)
+	require.Len(t, randomSortOf(1, 0, -10), 10)
+	require.Len(t, randomSortOf(1, 95, 10), 5)
+	require.Empty(t, randomSortOf(1, 200, 10))
+}
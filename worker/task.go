@@ -129,6 +129,9 @@ func processWithBackupRequest(
 // query.
 func ProcessTaskOverNetwork(ctx context.Context, q *pb.Query) (*pb.Result, error) {
 	attr := q.Attr
+	if err := groups().checkReadable(attr); err != nil {
+		return &pb.Result{}, err
+	}
 	gid, err := groups().BelongsToReadOnly(attr)
 	if err != nil {
 		return &pb.Result{}, err
@@ -156,6 +159,7 @@ func ProcessTaskOverNetwork(ctx context.Context, q *pb.Query) (*pb.Result, error
 	}
 
 	reply := result.(*pb.Result)
+	countRemoteTask(ctx, q, reply)
 	if span != nil {
 		span.Annotatef(nil, "Reply from server. len: %v gid: %v Attr: %v",
 			len(reply.UidMatrix), gid, attr)
@@ -217,6 +221,12 @@ const (
 	uidInFn
 	customIndexFn
 	matchFn
+	affixFn
+	editDistanceFn
+	jsonPathFn
+	distinctCountFn
+	distinctFn
+	histogramFn
 	standardFn = 100
 )
 
@@ -243,9 +253,12 @@ func parseFuncTypeHelper(name string) (FuncType, string) {
 	switch f {
 	case "le", "ge", "lt", "gt", "eq":
 		return compareAttrFn, f
+	case "containsany", "containsall":
+		// These are eq with multiple values, containsall intersects the uids of the values.
+		return compareAttrFn, eq
 	case "min", "max", "sum", "avg":
 		return aggregatorFn, f
-	case "checkpwd":
+	case "checkpwd", "checkpwdlock":
 		return passwordFn, f
 	case "regexp":
 		return regexFn, f
@@ -259,6 +272,18 @@ func parseFuncTypeHelper(name string) (FuncType, string) {
 		return customIndexFn, f
 	case "match":
 		return matchFn, f
+	case "prefix", "suffix":
+		return affixFn, f
+	case "editdistance":
+		return editDistanceFn, f
+	case "jsonpath":
+		return jsonPathFn, f
+	case "approx_count_distinct":
+		return distinctCountFn, f
+	case "distinct":
+		return distinctFn, f
+	case "histogram":
+		return histogramFn, f
 	default:
 		if types.IsGeoFunc(f) {
 			return geoFn, f
@@ -275,6 +300,24 @@ func needsIndex(fnType FuncType) bool {
 	return false
 }
 
+// readsIndex tells if the function reads the index of the predicate.
+func readsIndex(fnType FuncType) bool {
+	switch fnType {
+	case regexFn, customIndexFn, affixFn, distinctFn, histogramFn:
+		return true
+	}
+	return needsIndex(fnType)
+}
+
+// FuncReadsIndex tells if the function of a query reads the index of its predicate.
+func FuncReadsIndex(name string, isCount bool) bool {
+	fnType, _ := parseFuncTypeHelper(name)
+	if isCount && fnType == compareAttrFn {
+		return false
+	}
+	return readsIndex(fnType)
+}
+
 // needsIntersect checks if the function type needs algo.IntersectSorted() after the results
 // are collected. This is needed for functions that require all values to  match, like
 // "allofterms", "alloftext", and custom functions with "allof".
@@ -293,14 +336,14 @@ type funcArgs struct {
 // The function tells us whether we want to fetch value posting lists or uid posting lists.
 func (srcFn *functionContext) needsValuePostings(typ types.TypeID) (bool, error) {
 	switch srcFn.fnType {
-	case aggregatorFn, passwordFn:
+	case aggregatorFn, passwordFn, editDistanceFn, jsonPathFn:
 		return true, nil
 	case compareAttrFn:
 		if len(srcFn.tokens) > 0 {
 			return false, nil
 		}
 		return true, nil
-	case geoFn, regexFn, fullTextSearchFn, standardFn, hasFn, customIndexFn, matchFn:
+	case geoFn, regexFn, fullTextSearchFn, standardFn, hasFn, customIndexFn, matchFn, affixFn:
 		// All of these require an index, hence would require fetching uid postings.
 		return false, nil
 	case uidInFn, compareScalarFn:
@@ -325,7 +368,7 @@ func (qs *queryState) handleValuePostings(ctx context.Context, args funcArgs) er
 	}
 
 	switch srcFn.fnType {
-	case notAFunction, aggregatorFn, passwordFn, compareAttrFn:
+	case notAFunction, aggregatorFn, passwordFn, compareAttrFn, editDistanceFn, jsonPathFn:
 	default:
 		return errors.Errorf("Unhandled function in handleValuePostings: %s", srcFn.fname)
 	}
@@ -335,8 +378,8 @@ func (qs *queryState) handleValuePostings(ctx context.Context, args funcArgs) er
 		return nil
 	}
 	if srcFn.fnType == passwordFn && srcFn.atype != types.PasswordID {
-		return errors.Errorf("checkpwd fn can only be used on attr: [%s] with schema type "+
-			"password. Got type: %s", q.Attr, types.TypeID(srcFn.atype).Name())
+		return errors.Errorf("%s fn can only be used on attr: [%s] with schema type "+
+			"password. Got type: %s", srcFn.fname, q.Attr, types.TypeID(srcFn.atype).Name())
 	}
 	if srcFn.n == 0 {
 		return nil
@@ -371,6 +414,7 @@ func (qs *queryState) handleValuePostings(ctx context.Context, args funcArgs) er
 			if err != nil {
 				return err
 			}
+			countRead(ctx, pl)
 			var vals []types.Val
 			if q.ExpandAll {
 				vals, err = pl.AllValues(args.q.ReadTs)
@@ -438,7 +482,14 @@ func (qs *queryState) handleValuePostings(ctx context.Context, args funcArgs) er
 			}
 
 			// add facets to result.
-			if q.FacetParam != nil {
+			if q.FacetParam != nil && listType && len(q.Langs) == 0 && !q.ExpandAll {
+				// Each value of a list has its own facets.
+				fcts, err := pl.AllUntaggedFacets(args.q.ReadTs, q.FacetParam)
+				if err != nil {
+					return err
+				}
+				out.FacetMatrix = append(out.FacetMatrix, &pb.FacetsList{FacetsList: fcts})
+			} else if q.FacetParam != nil {
 				fs, err := pl.Facets(args.q.ReadTs, q.FacetParam, q.Langs)
 				if err != nil {
 					fs = []*api.Facet{}
@@ -471,6 +522,12 @@ func (qs *queryState) handleValuePostings(ctx context.Context, args funcArgs) er
 					out.ValueMatrix[lastPos].Values[0] = ctask.FalseVal
 				}
 				pwd := q.SrcFunc.Args[0]
+				if srcFn.fname == "checkpwdlock" {
+					res := checkPwdLock(q.Attr, q.UidList.Uids[i], pwd, string(newValue.Val))
+					out.ValueMatrix[lastPos].Values[0] = ctask.FromString(res)
+					out.UidMatrix = append(out.UidMatrix, &pb.List{})
+					continue
+				}
 				err = types.VerifyPassword(pwd, string(newValue.Val))
 				if err != nil {
 					out.ValueMatrix[lastPos].Values[0] = ctask.FalseVal
@@ -479,6 +536,33 @@ func (qs *queryState) handleValuePostings(ctx context.Context, args funcArgs) er
 				}
 				// Add an empty UID list to make later processing consistent
 				out.UidMatrix = append(out.UidMatrix, &pb.List{})
+			case srcFn.fnType == editDistanceFn:
+				// Replace the values with the smallest distance to the term.
+				lastPos := len(out.ValueMatrix) - 1
+				if vals := out.ValueMatrix[lastPos].Values; len(vals) > 0 {
+					dist := -1
+					for _, v := range vals {
+						d := editDistance(string(v.Val), srcFn.tokens[0], 0, srcFn.transpositions)
+						if dist < 0 || d < dist {
+							dist = d
+						}
+					}
+					out.ValueMatrix[lastPos].Values = []*pb.TaskValue{ctask.FromInt(dist)}
+				}
+				// Add an empty UID list to make later processing consistent
+				out.UidMatrix = append(out.UidMatrix, &pb.List{})
+			case srcFn.fnType == jsonPathFn:
+				// Replace the documents with the parts selected by the path.
+				lastPos := len(out.ValueMatrix) - 1
+				var selected []*pb.TaskValue
+				for _, v := range out.ValueMatrix[lastPos].Values {
+					if part, ok := srcFn.jsonPath.Select(v.Val); ok {
+						selected = append(selected, &pb.TaskValue{Val: part, ValType: v.ValType})
+					}
+				}
+				out.ValueMatrix[lastPos].Values = selected
+				// Add an empty UID list to make later processing consistent
+				out.UidMatrix = append(out.UidMatrix, &pb.List{})
 			default:
 				out.UidMatrix = append(out.UidMatrix, uidList)
 			}
@@ -524,6 +608,19 @@ func (qs *queryState) handleUidPostings(
 	if err != nil {
 		return err
 	}
+	var facetUids *pb.List
+	if srcFn.fnType == notAFunction && !q.Reverse && !q.DoCount {
+		if facetUids, err = qs.facetIndexUids(ctx, q, facetsTree); err != nil {
+			return err
+		}
+	}
+	var at *time.Time
+	if len(q.ValidAt) > 0 {
+		at = new(time.Time)
+		if err := at.UnmarshalBinary(q.ValidAt); err != nil {
+			return err
+		}
+	}
 
 	span := otrace.FromContext(ctx)
 	stop := x.SpanTimer(span, "handleUidPostings")
@@ -556,6 +653,14 @@ func (qs *queryState) handleUidPostings(
 				default:
 				}
 			}
+			if facetUids != nil && algo.IndexOf(facetUids, q.UidList.Uids[i]) < 0 {
+				// No edge of the node matches the facet filter.
+				out.UidMatrix = append(out.UidMatrix, &pb.List{})
+				if q.FacetParam != nil {
+					out.FacetMatrix = append(out.FacetMatrix, &pb.FacetsList{})
+				}
+				continue
+			}
 			var key []byte
 			switch srcFn.fnType {
 			case notAFunction, compareScalarFn, hasFn, uidInFn:
@@ -576,14 +681,27 @@ func (qs *queryState) handleUidPostings(
 			if err != nil {
 				return err
 			}
+			countRead(ctx, pl)
 
 			switch {
 			case q.DoCount:
 				if i == 0 {
 					span.Annotate(nil, "DoCount")
 				}
-				len := pl.Length(args.q.ReadTs, 0)
-				if len == -1 {
+				var len int
+				if at != nil {
+					// Only the edges valid at the time are counted.
+					err = pl.Postings(opts, func(p *pb.Posting) error {
+						valid, err := validAt(p.Facets, *at)
+						if valid {
+							len++
+						}
+						return err
+					})
+					if err != nil {
+						return err
+					}
+				} else if len = pl.Length(args.q.ReadTs, 0); len == -1 {
 					return posting.ErrTsTooOld
 				}
 				out.Counts = append(out.Counts, uint32(len))
@@ -647,6 +765,11 @@ func (qs *queryState) handleUidPostings(
 					if err != nil {
 						return err
 					}
+					if pick && at != nil {
+						if pick, err = validAt(p.Facets, *at); err != nil {
+							return err
+						}
+					}
 					if pick {
 						// TODO: This way of picking Uids differs from how
 						// pl.Uids works. So, have a look to see if we're
@@ -711,6 +834,10 @@ func processTask(ctx context.Context, q *pb.Query, gid uint32) (*pb.Result, erro
 	stop := x.SpanTimer(span, "processTask"+q.Attr)
 	defer stop()
 
+	if ctx.Err() != nil {
+		// The query was cancelled while the task was on its way.
+		return &pb.Result{}, ctx.Err()
+	}
 	span.Annotatef(nil, "Waiting for startTs: %d", q.ReadTs)
 	if err := posting.Oracle().WaitForTs(ctx, q.ReadTs); err != nil {
 		return &pb.Result{}, err
@@ -772,16 +899,65 @@ func (qs *queryState) helpProcessTask(
 	if q.Reverse && !schema.State().IsReversed(attr) {
 		return nil, errors.Errorf("Predicate %s doesn't have reverse edge", attr)
 	}
+	if q.Reverse {
+		if err := indexBuildError(attr, "reverse"); err != nil {
+			return nil, err
+		}
+	}
 
 	if needsIndex(srcFn.fnType) && !schema.State().IsIndexed(q.Attr) {
 		return nil, errors.Errorf("Predicate %s is not indexed", q.Attr)
 	}
+	if readsIndex(srcFn.fnType) {
+		if err := indexBuildError(attr, "index"); err != nil {
+			return nil, err
+		}
+	}
 
 	if len(q.Langs) > 0 && !schema.State().HasLang(attr) {
 		return nil, errors.Errorf("Language tags can only be used with predicates of string type"+
 			" having @lang directive in schema. Got: [%v]", attr)
 	}
 
+	if srcFn.fnType == distinctCountFn {
+		span.Annotate(nil, "approxDistinctValues")
+		count, err := approxDistinctValues(ctx, attr, q.ReadTs)
+		if err != nil {
+			return nil, err
+		}
+		out.Counts = []uint32{uint32(count)}
+		return out, nil
+	}
+	if srcFn.fnType == distinctFn {
+		span.Annotate(nil, "distinctValues")
+		vals, counts, err := distinctValues(ctx, attr, q.ReadTs, srcFn.offset, srcFn.first)
+		if err != nil {
+			return nil, err
+		}
+		out.ValueMatrix = []*pb.ValueList{vals}
+		out.Counts = counts
+		return out, nil
+	}
+	if srcFn.fnType == histogramFn {
+		span.Annotate(nil, "histogram")
+		counts, err := histogram(ctx, q, srcFn.bounds)
+		if err != nil {
+			return nil, err
+		}
+		bounds := &pb.ValueList{}
+		for _, b := range srcFn.bounds {
+			data := types.ValueForType(types.BinaryID)
+			if err := types.Marshal(b, &data); err != nil {
+				return nil, err
+			}
+			bounds.Values = append(bounds.Values,
+				&pb.TaskValue{ValType: b.Tid.Enum(), Val: data.Value.([]byte)})
+		}
+		out.ValueMatrix = []*pb.ValueList{bounds}
+		out.Counts = counts
+		return out, nil
+	}
+
 	typ, err := schema.State().TypeOf(attr)
 	if err != nil {
 		// All schema checks are done before this, this type is only used to
@@ -832,9 +1008,16 @@ func (qs *queryState) helpProcessTask(
 		}
 	}
 
+	if q.DoCount && q.UidList == nil {
+		// Only the number of nodes matching the function at root is requested. The counts are
+		// the ones of the posting lists of the index, which aren't checked against the values.
+		out.IntersectDest = srcFn.intersectDest
+		return out, nil
+	}
+
 	if srcFn.fnType == compareScalarFn && srcFn.isFuncAtRoot {
 		span.Annotate(nil, "handleCompareScalarFunction")
-		if err := qs.handleCompareScalarFunction(funcArgs{q, gid, srcFn, out}); err != nil {
+		if err := qs.handleCompareScalarFunction(ctx, funcArgs{q, gid, srcFn, out}); err != nil {
 			return nil, err
 		}
 	}
@@ -855,6 +1038,13 @@ func (qs *queryState) helpProcessTask(
 		}
 	}
 
+	if srcFn.fnType == affixFn {
+		span.Annotate(nil, "handleAffixFunction")
+		if err := qs.handleAffixFunction(ctx, funcArgs{q, gid, srcFn, out}); err != nil {
+			return nil, err
+		}
+	}
+
 	// We fetch the actual value for the uids, compare them to the value in the
 	// request and filter the uids only if the tokenizer IsLossy.
 	if srcFn.fnType == compareAttrFn && len(srcFn.tokens) > 0 {
@@ -867,7 +1057,7 @@ func (qs *queryState) helpProcessTask(
 	// If geo filter, do value check for correctness.
 	if srcFn.geoQuery != nil {
 		span.Annotate(nil, "handleGeoFunction")
-		if err := qs.filterGeoFunction(funcArgs{q, gid, srcFn, out}); err != nil {
+		if err := qs.filterGeoFunction(ctx, funcArgs{q, gid, srcFn, out}); err != nil {
 			return nil, err
 		}
 	}
@@ -875,7 +1065,7 @@ func (qs *queryState) helpProcessTask(
 	// For string matching functions, check the language.
 	if needsStringFiltering(srcFn, q.Langs, attr) {
 		span.Annotate(nil, "filterStringFunction")
-		if err := qs.filterStringFunction(funcArgs{q, gid, srcFn, out}); err != nil {
+		if err := qs.filterStringFunction(ctx, funcArgs{q, gid, srcFn, out}); err != nil {
 			return nil, err
 		}
 	}
@@ -900,12 +1090,15 @@ func needsStringFiltering(srcFn *functionContext, langs []string, attr string) b
 			srcFn.fnType == fullTextSearchFn || srcFn.fnType == compareAttrFn)
 }
 
-func (qs *queryState) handleCompareScalarFunction(arg funcArgs) error {
+func (qs *queryState) handleCompareScalarFunction(ctx context.Context, arg funcArgs) error {
 	attr := arg.q.Attr
 	if ok := schema.State().HasCount(attr); !ok {
 		return errors.Errorf("Need @count directive in schema for attr: %s for fn: %s at root",
 			attr, arg.srcFn.fname)
 	}
+	if err := indexBuildError(attr, "count"); err != nil {
+		return err
+	}
 	count := arg.srcFn.threshold
 	cp := countParams{
 		fn:      arg.srcFn.fname,
@@ -915,7 +1108,7 @@ func (qs *queryState) handleCompareScalarFunction(arg funcArgs) error {
 		readTs:  arg.q.ReadTs,
 		reverse: arg.q.Reverse,
 	}
-	return qs.evaluate(cp, arg.out)
+	return qs.evaluate(ctx, cp, arg.out)
 }
 
 func (qs *queryState) handleRegexFunction(ctx context.Context, arg funcArgs) error {
@@ -951,7 +1144,7 @@ func (qs *queryState) handleRegexFunction(ctx context.Context, arg funcArgs) err
 
 	// Prefer to use an index (fast)
 	case useIndex:
-		uids, err = uidsForRegex(attr, arg, query, &empty)
+		uids, err = uidsForRegex(ctx, attr, arg, query, &empty)
 		if err != nil {
 			return err
 		}
@@ -981,6 +1174,7 @@ func (qs *queryState) handleRegexFunction(ctx context.Context, arg funcArgs) err
 		if err != nil {
 			return err
 		}
+		countRead(ctx, pl)
 
 		vals := make([]types.Val, 1)
 		switch {
@@ -1072,6 +1266,7 @@ func (qs *queryState) handleCompareFunction(ctx context.Context, arg funcArgs) e
 							filterErr = err
 							return false
 						}
+						countRead(ctx, pl)
 						svs, err := pl.AllUntaggedValues(arg.q.ReadTs)
 						if err != nil {
 							if err != posting.ErrNoValue {
@@ -1081,7 +1276,7 @@ func (qs *queryState) handleCompareFunction(ctx context.Context, arg funcArgs) e
 						}
 						for _, sv := range svs {
 							dst, err := types.Convert(sv, typ)
-							if err == nil && types.CompareVals(arg.q.SrcFunc.Name, dst, arg.srcFn.eqTokens[row]) {
+							if err == nil && types.CompareVals(arg.srcFn.fname, dst, arg.srcFn.eqTokens[row]) {
 								return true
 							}
 						}
@@ -1094,6 +1289,7 @@ func (qs *queryState) handleCompareFunction(ctx context.Context, arg funcArgs) e
 						filterErr = err
 						return false
 					}
+					countRead(ctx, pl)
 					sv, err := pl.Value(arg.q.ReadTs)
 					if err != nil {
 						if err != posting.ErrNoValue {
@@ -1103,13 +1299,14 @@ func (qs *queryState) handleCompareFunction(ctx context.Context, arg funcArgs) e
 					}
 					dst, err := types.Convert(sv, typ)
 					return err == nil &&
-						types.CompareVals(arg.q.SrcFunc.Name, dst, arg.srcFn.eqTokens[row])
+						types.CompareVals(arg.srcFn.fname, dst, arg.srcFn.eqTokens[row])
 				case ".":
 					pl, err := posting.GetNoStore(x.DataKey(attr, uid))
 					if err != nil {
 						filterErr = err
 						return false
 					}
+					countRead(ctx, pl)
 					values, err := pl.AllValues(arg.q.ReadTs) // does not return ErrNoValue
 					if err != nil {
 						filterErr = err
@@ -1118,13 +1315,13 @@ func (qs *queryState) handleCompareFunction(ctx context.Context, arg funcArgs) e
 					for _, sv := range values {
 						dst, err := types.Convert(sv, typ)
 						if err == nil &&
-							types.CompareVals(arg.q.SrcFunc.Name, dst, arg.srcFn.eqTokens[row]) {
+							types.CompareVals(arg.srcFn.fname, dst, arg.srcFn.eqTokens[row]) {
 							return true
 						}
 					}
 					return false
 				default:
-					sv, err := fetchValue(uid, attr, arg.q.Langs, typ, arg.q.ReadTs)
+					sv, err := fetchValue(ctx, uid, attr, arg.q.Langs, typ, arg.q.ReadTs)
 					if err != nil {
 						if err != posting.ErrNoValue {
 							filterErr = err
@@ -1134,7 +1331,7 @@ func (qs *queryState) handleCompareFunction(ctx context.Context, arg funcArgs) e
 					if sv.Value == nil {
 						return false
 					}
-					return types.CompareVals(arg.q.SrcFunc.Name, sv, arg.srcFn.eqTokens[row])
+					return types.CompareVals(arg.srcFn.fname, sv, arg.srcFn.eqTokens[row])
 				}
 			})
 			if filterErr != nil {
@@ -1169,7 +1366,7 @@ func (qs *queryState) handleMatchFunction(ctx context.Context, arg funcArgs) err
 
 	case schema.State().HasTokenizer(tok.IdentTrigram, attr):
 		var err error
-		uids, err = uidsForMatch(attr, arg)
+		uids, err = uidsForMatch(ctx, attr, arg)
 		if err != nil {
 			return err
 		}
@@ -1198,6 +1395,7 @@ func (qs *queryState) handleMatchFunction(ctx context.Context, arg funcArgs) err
 		if err != nil {
 			return err
 		}
+		countRead(ctx, pl)
 
 		vals := make([]types.Val, 1)
 		switch {
@@ -1221,7 +1419,8 @@ func (qs *queryState) handleMatchFunction(ctx context.Context, arg funcArgs) err
 		for _, val := range vals {
 			// convert data from binary to appropriate format
 			strVal, err := types.Convert(val, types.StringID)
-			if err == nil && matchFuzzy(matchQuery, strVal.Value.(string), max) {
+			if err == nil &&
+				matchFuzzy(matchQuery, strVal.Value.(string), max, arg.srcFn.transpositions) {
 				filtered.Uids = append(filtered.Uids, uid)
 				// NOTE: We only add the uid once.
 				break
@@ -1236,16 +1435,107 @@ func (qs *queryState) handleMatchFunction(ctx context.Context, arg funcArgs) err
 	return nil
 }
 
-func (qs *queryState) filterGeoFunction(arg funcArgs) error {
+func (qs *queryState) handleAffixFunction(ctx context.Context, arg funcArgs) error {
+	span := otrace.FromContext(ctx)
+	stop := x.SpanTimer(span, "handleAffixFunction")
+	defer stop()
+	if span != nil {
+		span.Annotatef(nil, "Number of uids: %d. args.srcFn: %+v", arg.srcFn.n, arg.srcFn)
+	}
+
 	attr := arg.q.Attr
-	uids := algo.MergeSorted(arg.out.UidMatrix)
+	typ, err := schema.State().TypeOf(attr)
+	span.Annotatef(nil, "Attr: %s. Type: %s", attr, typ.Name())
+	uids := &pb.List{}
+	switch {
+	case err != nil || !typ.IsScalar():
+		return errors.Errorf("Attribute not scalar: %s %v", attr, typ)
+
+	case typ != types.StringID:
+		return errors.Errorf("Got non-string type. %s is allowed only on string type.",
+			arg.srcFn.fname)
+
+	case arg.q.UidList != nil && len(arg.q.UidList.Uids) != 0:
+		uids = arg.q.UidList
+
+	default:
+		if uids, err = uidsForAffix(ctx, attr, arg); err != nil {
+			return err
+		}
+	}
+
 	isList := schema.State().IsList(attr)
+	lang := langForFunc(arg.q.Langs)
+	span.Annotatef(nil, "Total uids: %d, list: %t lang: %v", len(uids.Uids), isList, lang)
+	arg.out.UidMatrix = append(arg.out.UidMatrix, uids)
+
 	filtered := &pb.List{}
 	for _, uid := range uids.Uids {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
 		pl, err := qs.cache.Get(x.DataKey(attr, uid))
 		if err != nil {
 			return err
 		}
+		countRead(ctx, pl)
+
+		vals := make([]types.Val, 1)
+		switch {
+		case lang != "":
+			vals[0], err = pl.ValueForTag(arg.q.ReadTs, lang)
+
+		case isList:
+			vals, err = pl.AllUntaggedValues(arg.q.ReadTs)
+
+		default:
+			vals[0], err = pl.Value(arg.q.ReadTs)
+		}
+		if err != nil {
+			if err == posting.ErrNoValue {
+				continue
+			}
+			return err
+		}
+
+		for _, val := range vals {
+			// convert data from binary to appropriate format
+			strVal, err := types.Convert(val, types.StringID)
+			if err == nil && matchAffix(arg.srcFn.fname, arg.srcFn.affix, strVal.Value.(string)) {
+				filtered.Uids = append(filtered.Uids, uid)
+				// NOTE: We only add the uid once.
+				break
+			}
+		}
+	}
+
+	for i := 0; i < len(arg.out.UidMatrix); i++ {
+		algo.IntersectWith(arg.out.UidMatrix[i], filtered, arg.out.UidMatrix[i])
+	}
+
+	return nil
+}
+
+func (qs *queryState) filterGeoFunction(ctx context.Context, arg funcArgs) error {
+	attr := arg.q.Attr
+	uids := algo.MergeSorted(arg.out.UidMatrix)
+	isList := schema.State().IsList(attr)
+	filtered := &pb.List{}
+	for i, uid := range uids.Uids {
+		if i%100 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
+		pl, err := qs.cache.Get(x.DataKey(attr, uid))
+		if err != nil {
+			return err
+		}
+		countRead(ctx, pl)
 		if !isList {
 			val, err := pl.Value(arg.q.ReadTs)
 			if err == posting.ErrNoValue {
@@ -1286,7 +1576,7 @@ func (qs *queryState) filterGeoFunction(arg funcArgs) error {
 // TODO: This function is really slow when there are a lot of UIDs to filter, for e.g. when used in
 // `has(name)`. We could potentially have a query level cache, which can be used to speed things up
 // a bit. Or, try to reduce the number of UIDs which make it here.
-func (qs *queryState) filterStringFunction(arg funcArgs) error {
+func (qs *queryState) filterStringFunction(ctx context.Context, arg funcArgs) error {
 	if glog.V(3) {
 		glog.Infof("filterStringFunction. arg: %+v\n", arg.q)
 		defer glog.Infof("Done filterStringFunction")
@@ -1301,12 +1591,20 @@ func (qs *queryState) filterStringFunction(arg funcArgs) error {
 	// matrix, to check it later.
 	// TODO: This function can be optimized by having a query specific cache, which can be populated
 	// by the handleHasFunction for e.g. for a `has(name)` query.
-	for _, uid := range uids.Uids {
+	for i, uid := range uids.Uids {
+		if i%100 == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			default:
+			}
+		}
 		key := x.DataKey(attr, uid)
 		pl, err := qs.cache.Get(key)
 		if err != nil {
 			return err
 		}
+		countRead(ctx, pl)
 
 		var vals []types.Val
 		var val types.Val
@@ -1387,9 +1685,17 @@ type functionContext struct {
 	fname          string
 	fnType         FuncType
 	regex          *cregexp.Regexp
+	affix          string
+	transpositions bool
+	jsonPath       *types.JSONPath
 	isFuncAtRoot   bool
 	isStringFn     bool
 	atype          types.TypeID
+	// The number of values skipped and returned by distinct.
+	offset int
+	first  int
+	// The boundaries of the buckets of histogram.
+	bounds []types.Val
 }
 
 const (
@@ -1480,6 +1786,7 @@ func parseSrcFn(q *pb.Query) (*functionContext, error) {
 			fc.tokens = append(fc.tokens, tokens...)
 			fc.eqTokens = append(fc.eqTokens, fc.ineqValue)
 		}
+		fc.intersectDest = strings.ToLower(q.SrcFunc.Name) == "containsall"
 
 		// Number of index keys is more than no. of uids to filter, so its better to fetch data keys
 		// directly and compare. Lets make tokens empty.
@@ -1530,27 +1837,70 @@ func parseSrcFn(q *pb.Query) (*functionContext, error) {
 		fc.intersectDest = needsIntersect(f)
 		fc.n = len(fc.tokens)
 	case matchFn:
-		if err = ensureArgsCount(q.SrcFunc, 2); err != nil {
-			return nil, err
+		args := q.SrcFunc.Args
+		if len(args) != 2 && len(args) != 3 {
+			return nil, errors.Errorf("Function '%s' requires 2 or 3 arguments, but got %d (%v)",
+				q.SrcFunc.Name, len(args), args)
 		}
 		required, found := verifyStringIndex(attr, fnType)
 		if !found {
 			return nil, errors.Errorf("Attribute %s is not indexed with type %s", attr, required)
 		}
 		fc.intersectDest = needsIntersect(f)
+		if len(args) == 3 {
+			if fc.transpositions, err = parseTranspositions(f, args[2]); err != nil {
+				return nil, err
+			}
+		}
 		// Max Levenshtein distance
-		var s string
-		s, q.SrcFunc.Args = q.SrcFunc.Args[1], q.SrcFunc.Args[:1]
-		max, err := strconv.ParseInt(s, 10, 32)
+		max, err := parseMatchDistance(args[1], args[0])
 		if err != nil {
-			return nil, errors.Errorf("Levenshtein distance value must be an int, got %v", s)
-		}
-		if max < 0 {
-			return nil, errors.Errorf("Levenshtein distance value must be greater than 0, got %v", s)
+			return nil, err
 		}
+		q.SrcFunc.Args = args[:1]
 		fc.threshold = int64(max)
 		fc.tokens = q.SrcFunc.Args
 		fc.n = len(fc.tokens)
+	case editDistanceFn:
+		// The last argument is the attribute, as for the password functions.
+		args := q.SrcFunc.Args
+		if len(args) != 2 && len(args) != 3 {
+			return nil, errors.Errorf("Function '%s' requires 1 or 2 arguments, but got %d (%v)",
+				q.SrcFunc.Name, len(args)-1, args[:len(args)-1])
+		}
+		if len(args) == 3 {
+			if fc.transpositions, err = parseTranspositions(f, args[1]); err != nil {
+				return nil, err
+			}
+		}
+		if !fc.isStringFn {
+			return nil, errors.Errorf("Got non-string type. %s is allowed only on string type.",
+				f)
+		}
+		fc.tokens = args[:1]
+		fc.n = len(q.UidList.Uids)
+	case jsonPathFn:
+		// The last argument is the attribute, as for the password functions.
+		if err = ensureArgsCount(q.SrcFunc, 2); err != nil {
+			return nil, err
+		}
+		if t != types.JSONID {
+			return nil, errors.Errorf("Function '%s' is allowed only on json type, got %s",
+				f, t.Name())
+		}
+		if fc.jsonPath, err = types.ParseJSONPath(q.SrcFunc.Args[0]); err != nil {
+			return nil, err
+		}
+		fc.n = len(q.UidList.Uids)
+	case affixFn:
+		if err = ensureArgsCount(q.SrcFunc, 1); err != nil {
+			return nil, err
+		}
+		if q.SrcFunc.Args[0] == "" {
+			return nil, errors.Errorf("Function '%s' requires a non-empty string", q.SrcFunc.Name)
+		}
+		fc.affix = q.SrcFunc.Args[0]
+		fc.n = 0
 	case customIndexFn:
 		if err = ensureArgsCount(q.SrcFunc, 2); err != nil {
 			return nil, err
@@ -1614,6 +1964,33 @@ func parseSrcFn(q *pb.Query) (*functionContext, error) {
 		if fc.isFuncAtRoot {
 			return nil, errors.Errorf("uid_in function not allowed at root")
 		}
+	case distinctCountFn:
+		checkRoot(q, fc)
+		if !fc.isFuncAtRoot {
+			return nil, errors.Errorf("approx_count_distinct is only allowed at root")
+		}
+	case distinctFn:
+		if err = ensureArgsCount(q.SrcFunc, 2); err != nil {
+			return nil, err
+		}
+		checkRoot(q, fc)
+		if !fc.isFuncAtRoot {
+			return nil, errors.Errorf("distinct is only allowed at root")
+		}
+		if fc.offset, err = strconv.Atoi(q.SrcFunc.Args[0]); err != nil {
+			return nil, err
+		}
+		if fc.first, err = strconv.Atoi(q.SrcFunc.Args[1]); err != nil {
+			return nil, err
+		}
+	case histogramFn:
+		checkRoot(q, fc)
+		if fc.isFuncAtRoot {
+			return nil, errors.Errorf("histogram is not allowed at root")
+		}
+		if fc.bounds, err = histogramBounds(attr, q.SrcFunc.Args); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, errors.Errorf("FnType %d not handled in numFnAttrs.", fnType)
 	}
@@ -1655,7 +2032,12 @@ func (w *grpcWorker) ServeTask(ctx context.Context, q *pb.Query) (*pb.Result, er
 	}
 	c := make(chan reply, 1)
 	go func() {
+		// Count the resources used here, to send them back with the result.
+		ctx := WithQueryMetrics(ctx)
 		result, err := processTask(ctx, q, gid)
+		if m := QueryMetricsFrom(ctx); m != nil && err == nil {
+			result.KeysRead, result.BytesRead = m.KeysRead, m.BytesRead
+		}
 		c <- reply{result, err}
 	}()
 
@@ -1667,6 +2049,42 @@ func (w *grpcWorker) ServeTask(ctx context.Context, q *pb.Query) (*pb.Result, er
 	}
 }
 
+// The facets of the uid edges bounding the time during which they are valid, which the queries
+// @at a time follow.
+const (
+	validFromFacet = "valid_from"
+	validToFacet   = "valid_to"
+)
+
+// validAt returns whether an edge with the facets is valid at the time: from its valid_from
+// facet, included, to its valid_to facet, excluded. The facets are datetimes, or ints of unix
+// seconds, and a missing one doesn't bound the edge.
+func validAt(postingFacets []*api.Facet, at time.Time) (bool, error) {
+	for _, fc := range postingFacets {
+		if fc.Key != validFromFacet && fc.Key != validToFacet {
+			continue
+		}
+		val, err := facets.ValFor(fc)
+		if err != nil {
+			return false, err
+		}
+		var t time.Time
+		switch val.Tid {
+		case types.DateTimeID:
+			t = val.Value.(time.Time)
+		case types.IntID:
+			t = time.Unix(val.Value.(int64), 0)
+		default:
+			return false, errors.Errorf("Facet %s should be a datetime or an int, got: %s",
+				fc.Key, val.Tid.Name())
+		}
+		if fc.Key == validFromFacet && at.Before(t) || fc.Key == validToFacet && !at.Before(t) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
 // applyFacetsTree : we return error only when query has some problems.
 // like Or has 3 arguments, argument facet val overflows integer.
 // returns true if postingFacets can be included.
@@ -1863,7 +2281,7 @@ type countParams struct {
 	fn      string // function name
 }
 
-func (qs *queryState) evaluate(cp countParams, out *pb.Result) error {
+func (qs *queryState) evaluate(ctx context.Context, cp countParams, out *pb.Result) error {
 	count := cp.count
 	var illegal bool
 	switch cp.fn {
@@ -1891,6 +2309,7 @@ func (qs *queryState) evaluate(cp countParams, out *pb.Result) error {
 		if err != nil {
 			return err
 		}
+		countRead(ctx, pl)
 		uids, err := pl.Uids(posting.ListOptions{ReadTs: cp.readTs})
 		if err != nil {
 			return err
@@ -1926,6 +2345,7 @@ func (qs *queryState) evaluate(cp countParams, out *pb.Result) error {
 		if err != nil {
 			return err
 		}
+		countRead(ctx, pl)
 		uids, err := pl.Uids(posting.ListOptions{ReadTs: cp.readTs})
 		if err != nil {
 			return err
@@ -1958,7 +2378,15 @@ func (qs *queryState) handleHasFunction(ctx context.Context, q *pb.Query, out *p
 		prefix = initKey.ReversePrefix()
 	}
 
+	// Only the number of nodes is returned for DoCount, without building the list of their uids.
 	result := &pb.List{}
+	var count int
+	found := func(uid uint64) {
+		count++
+		if !q.DoCount {
+			result.Uids = append(result.Uids, uid)
+		}
+	}
 	var prevKey []byte
 	itOpt := badger.DefaultIteratorOptions
 	itOpt.PrefetchValues = false
@@ -1990,7 +2418,7 @@ func (qs *queryState) handleHasFunction(ctx context.Context, q *pb.Query, out *p
 		}
 		if item.UserMeta()&posting.BitCompletePosting > 0 {
 			// This bit would only be set if there are valid uids in UidPack.
-			result.Uids = append(result.Uids, pk.Uid)
+			found(pk.Uid)
 			continue
 		}
 
@@ -1999,13 +2427,14 @@ func (qs *queryState) handleHasFunction(ctx context.Context, q *pb.Query, out *p
 		if err != nil {
 			return err
 		}
+		countRead(ctx, l)
 		if empty, err := l.IsEmpty(q.ReadTs, 0); err != nil {
 			return err
 		} else if !empty {
-			result.Uids = append(result.Uids, pk.Uid)
+			found(pk.Uid)
 		}
 
-		if len(result.Uids)%100000 == 0 {
+		if count%100000 == 0 {
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -2014,7 +2443,10 @@ func (qs *queryState) handleHasFunction(ctx context.Context, q *pb.Query, out *p
 		}
 	}
 	if span != nil {
-		span.Annotatef(nil, "handleHasFunction found %d uids", len(result.Uids))
+		span.Annotatef(nil, "handleHasFunction found %d uids", count)
+	}
+	if q.DoCount {
+		out.Counts = append(out.Counts, uint32(count))
 	}
 	out.UidMatrix = append(out.UidMatrix, result)
 	return nil
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dgraph-io/dgo/protos/api"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/types/facets"
+)
+
+func TestParseJSONPathFunction(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte(`
+		settings: json .
+		name: string .
+	`), 1))
+
+	q := &pb.Query{Attr: "settings", UidList: &pb.List{Uids: []uint64{1, 2}},
+		SrcFunc: &pb.SrcFunction{Name: "jsonpath", Args: []string{"$.ui.theme", "settings"}}}
+	fc, err := parseSrcFn(q)
+	require.NoError(t, err)
+	require.Equal(t, jsonPathFn, fc.fnType)
+	require.Equal(t, 2, fc.n)
+	part, ok := fc.jsonPath.Select([]byte(`{"ui":{"theme":"dark"}}`))
+	require.True(t, ok)
+	require.Equal(t, `"dark"`, string(part))
+
+	q.SrcFunc.Args = []string{"ui.theme", "settings"}
+	_, err = parseSrcFn(q)
+	require.Error(t, err)
+
+	q.Attr = "name"
+	q.SrcFunc.Args = []string{"$.ui", "name"}
+	_, err = parseSrcFn(q)
+	require.Error(t, err)
+}
+
+func TestValidAt(t *testing.T) {
+	facet := func(key, val string) *api.Facet {
+		f, err := facets.FacetFor(key, val)
+		require.NoError(t, err)
+		return f
+	}
+	window := []*api.Facet{
+		facet("since", "2000-01-01T00:00:00"),
+		facet("valid_from", "2010-01-01T00:00:00"),
+		// 2015-01-01T00:00:00Z in unix seconds.
+		facet("valid_to", "1420070400"),
+	}
+	for at, valid := range map[string]bool{
+		"2009-12-31T23:59:59Z": false,
+		"2010-01-01T00:00:00Z": true,
+		"2014-12-31T23:59:59Z": true,
+		"2015-01-01T00:00:00Z": false,
+	} {
+		tm, err := time.Parse(time.RFC3339, at)
+		require.NoError(t, err)
+		ok, err := validAt(window, tm)
+		require.NoError(t, err)
+		require.Equal(t, valid, ok, at)
+
+		// An edge without a window is always valid.
+		ok, err = validAt(window[:1], tm)
+		require.NoError(t, err)
+		require.True(t, ok)
+	}
+
+	_, err := validAt([]*api.Facet{facet("valid_to", `"soon"`)}, time.Now())
+	require.Error(t, err)
+}
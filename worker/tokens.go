@@ -20,6 +20,7 @@ import (
 	"github.com/dgraph-io/badger"
 
 	"bytes"
+	"unicode/utf8"
 
 	"github.com/dgraph-io/dgraph/schema"
 	"github.com/dgraph-io/dgraph/tok"
@@ -83,6 +84,14 @@ func pickTokenizer(attr string, f string) (tok.Tokenizer, error) {
 	}
 
 	tokenizers := schema.State().Tokenizer(attr)
+	if f == "eq" {
+		// An exact_ci index makes eq case-insensitive.
+		for _, t := range tokenizers {
+			if t.Identifier() == tok.IdentExactCI {
+				return t, nil
+			}
+		}
+	}
 	for _, t := range tokenizers {
 		// If function is eq and we found a tokenizer thats !Lossy(), lets return it
 		switch f {
@@ -108,6 +117,81 @@ func pickTokenizer(attr string, f string) (tok.Tokenizer, error) {
 	return tokenizers[0], nil
 }
 
+// CheckFuncIndex returns the error a function on the predicate fails with when its index, given
+// by the names of its tokenizers, doesn't support the function. It lets the queries be checked
+// against the schema without running them. The regexp and affix functions only need an index at
+// the root, as they can read the values of the nodes they filter.
+func CheckFuncIndex(fname, attr string, args []string, isCount, atRoot bool,
+	tokenizers []string) error {
+
+	has := func(id byte) bool {
+		for _, name := range tokenizers {
+			if t, ok := tok.GetTokenizer(name); ok && t.Identifier() == id {
+				return true
+			}
+		}
+		return false
+	}
+
+	fnType, f := parseFuncTypeHelper(fname)
+	if isCount && fnType == compareAttrFn {
+		fnType = compareScalarFn
+	}
+	if needsIndex(fnType) && len(tokenizers) == 0 {
+		return errors.Errorf("Predicate %s is not indexed", attr)
+	}
+	switch fnType {
+	case compareAttrFn:
+		if f == "eq" {
+			return nil
+		}
+		for _, name := range tokenizers {
+			if t, ok := tok.GetTokenizer(name); ok && t.IsSortable() {
+				return nil
+			}
+		}
+		return errors.Errorf("Attribute:%s does not have proper index for comparison", attr)
+	case standardFn, fullTextSearchFn, matchFn:
+		var required tok.Tokenizer
+		switch fnType {
+		case fullTextSearchFn:
+			required = tok.FullTextTokenizer{}
+		case matchFn:
+			required = tok.TrigramTokenizer{}
+		default:
+			required = tok.TermTokenizer{}
+		}
+		if !has(required.Identifier()) {
+			return errors.Errorf("Attribute %s is not indexed with type %s", attr, required.Name())
+		}
+	case customIndexFn:
+		for _, name := range tokenizers {
+			if len(args) > 0 && name == args[0] {
+				return nil
+			}
+		}
+		if len(args) > 0 {
+			return errors.Errorf("Attribute %s is not indexed with custom tokenizer %s",
+				attr, args[0])
+		}
+	case regexFn:
+		if atRoot && !has(tok.IdentTrigram) {
+			return errors.Errorf(
+				"Attribute %v does not have trigram index for regex matching. "+
+					"Please add a trigram index or use has/uid function with regexp() as filter.",
+				attr)
+		}
+	case affixFn:
+		trigram := has(tok.IdentTrigram) && len(args) > 0 && utf8.RuneCountInString(args[0]) >= 3
+		if atRoot && !trigram && !has(tok.IdentExact) {
+			return errors.Errorf("Attribute %v does not have an exact index, or a trigram index "+
+				"with at least 3 characters, for %s matching. Please add an index or use has/uid "+
+				"function with %s() as filter.", attr, f, f)
+		}
+	}
+	return nil
+}
+
 // getInequalityTokens gets tokens ge / le compared to given token using the first sortable
 // index that is found for the predicate.
 func getInequalityTokens(readTs uint64, attr, f string,
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/schema"
+	"github.com/dgraph-io/dgraph/tok"
+)
+
+func TestPickTokenizer(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte(`
+		email: string @index(exact, exact_ci) .
+		name: string @index(term, exact) .
+	`), 1))
+
+	// The exact_ci index is used for eq, the exact index for the inequalities.
+	tokenizer, err := pickTokenizer("email", "eq")
+	require.NoError(t, err)
+	require.Equal(t, byte(tok.IdentExactCI), tokenizer.Identifier())
+	tokenizer, err = pickTokenizer("email", "ge")
+	require.NoError(t, err)
+	require.Equal(t, byte(tok.IdentExact), tokenizer.Identifier())
+
+	tokenizer, err = pickTokenizer("name", "eq")
+	require.NoError(t, err)
+	require.Equal(t, byte(tok.IdentExact), tokenizer.Identifier())
+}
+
+func TestParseContainsFunctions(t *testing.T) {
+	require.NoError(t, schema.ParseBytes([]byte("tags: [string] @index(exact) ."), 1))
+
+	// Both functions are eq over the values, containsall intersects the results.
+	for fname, intersect := range map[string]bool{"containsany": false, "containsall": true} {
+		q := &pb.Query{Attr: "tags", SrcFunc: &pb.SrcFunction{Name: fname,
+			Args: []string{"go", "graph"}}}
+		fc, err := parseSrcFn(q)
+		require.NoError(t, err)
+		require.Equal(t, compareAttrFn, fc.fnType)
+		require.Equal(t, "eq", fc.fname)
+		require.Equal(t, 2, len(fc.tokens))
+		require.Equal(t, intersect, fc.intersectDest)
+	}
+}
+
+func TestCheckFuncIndex(t *testing.T) {
+	require.NoError(t, CheckFuncIndex("eq", "name", []string{"alice"}, false, true,
+		[]string{"hash"}))
+	require.EqualError(t, CheckFuncIndex("ge", "name", []string{"a"}, false, true,
+		[]string{"hash"}), "Attribute:name does not have proper index for comparison")
+	require.EqualError(t, CheckFuncIndex("eq", "age", []string{"2"}, false, false, nil),
+		"Predicate age is not indexed")
+	// Counts are compared without reading the index.
+	require.NoError(t, CheckFuncIndex("gt", "friend", []string{"2"}, true, false, nil))
+
+	require.EqualError(t, CheckFuncIndex("anyofterms", "name", []string{"a b"}, false, false,
+		[]string{"exact"}), "Attribute name is not indexed with type term")
+	require.NoError(t, CheckFuncIndex("alloftext", "bio", []string{"a b"}, false, true,
+		[]string{"fulltext"}))
+
+	// The regexp and affix functions read the values in filters.
+	require.NoError(t, CheckFuncIndex("regexp", "name", []string{"^a", ""}, false, false, nil))
+	require.Error(t, CheckFuncIndex("regexp", "name", []string{"^a", ""}, false, true, nil))
+	require.NoError(t, CheckFuncIndex("suffix", "name", []string{"ice"}, false, true,
+		[]string{"trigram"}))
+	require.Error(t, CheckFuncIndex("suffix", "name", []string{"ce"}, false, true,
+		[]string{"trigram"}))
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 
 	cindex "github.com/google/codesearch/index"
+	"golang.org/x/net/context"
 
 	"github.com/dgraph-io/dgraph/algo"
 	"github.com/dgraph-io/dgraph/posting"
@@ -31,7 +32,7 @@ import (
 var errRegexTooWide = errors.New(
 	"regular expression is too wide-ranging and can't be executed efficiently")
 
-func uidsForRegex(attr string, arg funcArgs,
+func uidsForRegex(ctx context.Context, attr string, arg funcArgs,
 	query *cindex.Query, intersect *pb.List) (*pb.List, error) {
 	var results *pb.List
 	opts := posting.ListOptions{
@@ -47,6 +48,7 @@ func uidsForRegex(attr string, arg funcArgs,
 		if err != nil {
 			return nil, err
 		}
+		countRead(ctx, pl)
 		return pl.Uids(opts)
 	}
 
@@ -74,7 +76,7 @@ func uidsForRegex(attr string, arg funcArgs,
 			}
 			// current list of result is passed for intersection
 			var err error
-			results, err = uidsForRegex(attr, arg, sub, results)
+			results, err = uidsForRegex(ctx, attr, arg, sub, results)
 			if err != nil {
 				return nil, err
 			}
@@ -97,7 +99,7 @@ func uidsForRegex(attr string, arg funcArgs,
 			if results == nil {
 				results = intersect
 			}
-			subUids, err := uidsForRegex(attr, arg, sub, intersect)
+			subUids, err := uidsForRegex(ctx, attr, arg, sub, intersect)
 			if err != nil {
 				return nil, err
 			}
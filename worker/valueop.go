@@ -0,0 +1,150 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package worker
+
+import (
+	"bytes"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/dgraph-io/dgraph/posting"
+	"github.com/dgraph-io/dgraph/protos/pb"
+	"github.com/dgraph-io/dgraph/types"
+)
+
+func isValueOp(edge *pb.DirectedEdge) bool {
+	return edge.Op == pb.DirectedEdge_ADD || edge.Op == pb.DirectedEdge_CAS
+}
+
+// toBinary converts the value to the given type and returns its binary encoding, which is how
+// the value is stored.
+func toBinary(v types.Val, typ types.TypeID) ([]byte, error) {
+	dst, err := types.Convert(v, typ)
+	if err != nil {
+		return nil, err
+	}
+	b := types.ValueForType(types.BinaryID)
+	if err := types.Marshal(dst, &b); err != nil {
+		return nil, err
+	}
+	return b.Value.([]byte), nil
+}
+
+// validateValueOp checks that the add or cas operation of the edge can be applied to its
+// predicate. The expected value of cas is converted to the type of the predicate, as long as
+// ValidateAndConvert hasn't converted the value yet.
+func validateValueOp(edge *pb.DirectedEdge, su *pb.SchemaUpdate) error {
+	typ := types.TypeID(su.ValueType)
+	switch {
+	case su.List:
+		return errors.Errorf("%s can't be used on predicate %s of list type",
+			edge.Op, edge.Attr)
+	case su.Conflict == "value" || su.Conflict == "none":
+		// Concurrent operations wouldn't conflict, so one of the updates could get lost.
+		return errors.Errorf("%s can't be used on predicate %s with @conflict(%s)",
+			edge.Op, edge.Attr, su.Conflict)
+	case edge.Op == pb.DirectedEdge_ADD && typ != types.IntID && typ != types.FloatID:
+		return errors.Errorf("ADD can only be used on predicates of type int or float, "+
+			"predicate %s is of type %s", edge.Attr, typ.Name())
+	case !typ.IsScalar() || typ == types.PasswordID:
+		return errors.Errorf("CAS can't be used on predicate %s of type %s",
+			edge.Attr, typ.Name())
+	}
+
+	if edge.Op != pb.DirectedEdge_CAS || types.TypeID(edge.ValueType) != types.DefaultID ||
+		typ == types.DefaultID {
+		return nil
+	}
+	expected, err := toBinary(types.Val{Tid: types.DefaultID, Value: edge.Expected}, typ)
+	if err != nil {
+		return errors.Wrapf(err, "while converting the expected value of %s", edge.Attr)
+	}
+	edge.Expected = expected
+	return nil
+}
+
+// applyValueOp returns a SET edge with the value computed by the add or cas operation of the
+// edge from the current value in the posting list. A missing value counts as zero for add, and
+// never matches the expected value of cas. The posting list must have been read with txn.Get,
+// so that concurrent operations on the same value conflict.
+func applyValueOp(edge *pb.DirectedEdge, plist *posting.List,
+	readTs uint64) (*pb.DirectedEdge, error) {
+
+	var cur types.Val
+	var err error
+	if edge.Lang == "" {
+		cur, err = plist.Value(readTs)
+	} else {
+		cur, err = plist.ValueForTag(readTs, edge.Lang)
+	}
+	if err != nil && err != posting.ErrNoValue {
+		return nil, err
+	}
+	hasValue := err == nil
+
+	out := *edge
+	out.Op = pb.DirectedEdge_SET
+	out.Expected = nil
+	typ := types.TypeID(edge.ValueType)
+
+	if edge.Op == pb.DirectedEdge_CAS {
+		if !hasValue {
+			return nil, errors.Errorf("CAS failed for predicate %s of node %#x: it has no value",
+				edge.Attr, edge.Entity)
+		}
+		curBytes, err := toBinary(cur, typ)
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(curBytes, edge.Expected) {
+			return nil, errors.Errorf("CAS failed for predicate %s of node %#x: the current "+
+				"value isn't the expected one", edge.Attr, edge.Entity)
+		}
+		return &out, nil
+	}
+
+	if !hasValue {
+		return &out, nil
+	}
+	a, err := types.Convert(cur, typ)
+	if err != nil {
+		return nil, err
+	}
+	b, err := types.Convert(types.Val{Tid: typ, Value: edge.Value}, typ)
+	if err != nil {
+		return nil, err
+	}
+	sum := types.Val{Tid: typ}
+	switch typ {
+	case types.IntID:
+		x, y := a.Value.(int64), b.Value.(int64)
+		if (y > 0 && x > math.MaxInt64-y) || (y < 0 && x < math.MinInt64-y) {
+			return nil, errors.Errorf("ADD overflows predicate %s of node %#x",
+				edge.Attr, edge.Entity)
+		}
+		sum.Value = x + y
+	case types.FloatID:
+		sum.Value = a.Value.(float64) + b.Value.(float64)
+	}
+	dst := types.ValueForType(types.BinaryID)
+	if err := types.Marshal(sum, &dst); err != nil {
+		return nil, err
+	}
+	out.Value = dst.Value.([]byte)
+	return &out, nil
+}
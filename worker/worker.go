@@ -78,6 +78,11 @@ func RunServer(bindall bool) {
 	if err != nil {
 		log.Fatalf("While running server: %v", err)
 	}
+	Serve(ln)
+}
+
+// Serve serves the requests from other workers on ln, until the worker server is stopped.
+func Serve(ln net.Listener) {
 	glog.Infof("Worker listening at address: %v", ln.Addr())
 
 	pb.RegisterWorkerServer(workerServer, &grpcWorker{})
@@ -18,6 +18,7 @@ package x
 
 import (
 	"net"
+	"sync"
 	"time"
 )
 
@@ -30,11 +31,66 @@ type Options struct {
 	QueryEdgeLimit uint64
 	// NormalizeNodeLimit is the maximum number of nodes allowed in a normalize query.
 	NormalizeNodeLimit int
+	// QueryDepthLimit is the maximum number of nested levels in the result of a query.
+	QueryDepthLimit uint64
+	// QueryNodeLimit is the maximum number of nodes in the result of a query.
+	QueryNodeLimit uint64
+	// QueryFanoutLimit is the maximum number of nodes a node can have for one predicate
+	// in the result of a query.
+	QueryFanoutLimit uint64
+	// CustomResolvers maps the name of each resolver usable by custom() in queries to the
+	// URL of the HTTP service resolving it.
+	CustomResolvers map[string]string
+	// QueryTimeout is the maximum duration of a query. 0 means no limit.
+	QueryTimeout time.Duration
+	// CustomResolverTimeout is the maximum duration of a call to a custom resolver.
+	CustomResolverTimeout time.Duration
+	// CustomResolverBatch is the maximum number of nodes sent in one call to a custom resolver.
+	CustomResolverBatch int
+	// PersistedQueries is the maximum number of persisted queries kept by the HTTP endpoint.
+	PersistedQueries int
+	// QueryCacheSize is the maximum number of parsed queries kept to skip parsing them again.
+	QueryCacheSize int
+	// ResponseSpillSize is the size in bytes over which the JSON of a response is moved to a
+	// temporary file while it's built. 0 keeps responses in memory.
+	ResponseSpillSize int
+	// ResponseSpillDir is the directory of the files holding spilled responses.
+	ResponseSpillDir string
+	// BinarySummarySize is the size in bytes over which only the size and hash of a binary
+	// value are returned, unless the request asks for the full value. 0 always returns values.
+	BinarySummarySize int
+	// PasswordHash is the hash of new passwords, bcrypt or argon2id.
+	PasswordHash string
+	// BcryptCost is the cost of the bcrypt hash of passwords.
+	BcryptCost int
+	// Argon2Time is the number of passes over memory of the argon2id hash of passwords.
+	Argon2Time uint32
+	// Argon2Memory is the memory in KiB used by the argon2id hash of passwords.
+	Argon2Memory uint32
+	// PasswordMinEntropy is the minimum estimated entropy in bits of new passwords.
+	PasswordMinEntropy float64
+	// PasswordMaxFailures is the number of failed checkpwdlock calls after which a password
+	// is locked out. 0 never locks passwords out.
+	PasswordMaxFailures int
+	// PasswordLockout is the duration for which a password is locked out.
+	PasswordLockout time.Duration
+	// BlobOffloadSize is the size in bytes over which values are offloaded to the blob store,
+	// if one is configured. 0 never offloads values.
+	BlobOffloadSize int
+	// SequenceLease is the number of values of a sequence leased from Zero at once.
+	SequenceLease int
+	// IndexBuildRate is the maximum number of posting lists read per second by the background
+	// index builds. 0 means no limit.
+	IndexBuildRate uint64
 }
 
 // Config stores the global instance of this package's options.
 var Config Options
 
+// ConfigMu guards the options of Config which can be reloaded while the Alpha runs: the query
+// limits and timeouts, and the rate of the index builds.
+var ConfigMu sync.RWMutex
+
 // IPRange represents an IP range.
 type IPRange struct {
 	Lower, Upper net.IP
@@ -0,0 +1,27 @@
+// +build windows
+
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x
+
+import "github.com/pkg/errors"
+
+// DiskSpace returns the bytes available to the user and the total bytes of the filesystem
+// holding the directory.
+func DiskSpace(dir string) (free, total uint64, err error) {
+	return 0, 0, errors.New("Cannot detect disk space on this platform")
+}
@@ -0,0 +1,162 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x
+
+import (
+	"context"
+
+	"github.com/dgraph-io/dgo/y"
+	structpb "github.com/golang/protobuf/ptypes/struct"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorCode tells clients what to do about the error of a request: fix the request, retry it,
+// or give up. It's sent in the "error_code" extension of the HTTP errors, and in the "code" field
+// of a google.protobuf.Struct in the details of the gRPC errors.
+type ErrorCode string
+
+const (
+	// CodeInvalidQuery is returned for requests which can't be parsed or are invalid. They fail
+	// the same way until they're fixed.
+	CodeInvalidQuery ErrorCode = "INVALID_QUERY"
+	// CodeTxnAborted is returned when the transaction was aborted by a conflict. The whole
+	// transaction can be run again in a new one.
+	CodeTxnAborted ErrorCode = "TXN_ABORTED"
+	// CodeLimitExceeded is returned when a request exceeds one of the limits of the server.
+	CodeLimitExceeded ErrorCode = "LIMIT_EXCEEDED"
+	// CodeUnauthorized is returned when the user isn't logged in or can't access the data.
+	CodeUnauthorized ErrorCode = "UNAUTHORIZED"
+	// CodeRetriable is returned when the server couldn't serve the request for now, and the same
+	// request can be retried later.
+	CodeRetriable ErrorCode = "RETRIABLE"
+)
+
+// grpcCodes are the gRPC codes of the errors with an ErrorCode, which aren't gRPC errors yet.
+var grpcCodes = map[ErrorCode]codes.Code{
+	CodeInvalidQuery:  codes.InvalidArgument,
+	CodeTxnAborted:    codes.Aborted,
+	CodeLimitExceeded: codes.ResourceExhausted,
+	CodeUnauthorized:  codes.PermissionDenied,
+	CodeRetriable:     codes.Unavailable,
+}
+
+// codedError is an error with an ErrorCode.
+type codedError struct {
+	err  error
+	code ErrorCode
+}
+
+func (e *codedError) Error() string        { return e.err.Error() }
+func (e *codedError) Cause() error         { return e.err }
+func (e *codedError) ErrorCode() ErrorCode { return e.code }
+
+// WithCode returns err with the given ErrorCode. errors.Cause still returns the cause of err.
+func WithCode(err error, code ErrorCode) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{err: err, code: code}
+}
+
+// ErrorCodeOf returns the ErrorCode of err, or an empty code if it has none. The code is the one
+// of the first error in the chain of causes of err which has one, or which is a gRPC error.
+func ErrorCodeOf(err error) ErrorCode {
+	for err != nil {
+		if e, ok := err.(interface{ ErrorCode() ErrorCode }); ok {
+			return e.ErrorCode()
+		}
+		if s, ok := status.FromError(err); ok {
+			return statusErrorCode(s)
+		}
+		if err == y.ErrAborted || err == y.ErrConflict {
+			return CodeTxnAborted
+		}
+		cause, ok := err.(interface{ Cause() error })
+		if !ok {
+			break
+		}
+		err = cause.Cause()
+	}
+	return ""
+}
+
+// statusErrorCode returns the ErrorCode in the details of s, or else the one matching its code.
+func statusErrorCode(s *status.Status) ErrorCode {
+	for _, detail := range s.Details() {
+		if st, ok := detail.(*structpb.Struct); ok {
+			if code := st.Fields["code"].GetStringValue(); code != "" {
+				return ErrorCode(code)
+			}
+		}
+	}
+	switch s.Code() {
+	case codes.InvalidArgument:
+		return CodeInvalidQuery
+	case codes.Aborted:
+		return CodeTxnAborted
+	case codes.ResourceExhausted:
+		return CodeLimitExceeded
+	case codes.Unauthenticated, codes.PermissionDenied:
+		return CodeUnauthorized
+	case codes.Unavailable:
+		return CodeRetriable
+	}
+	return ""
+}
+
+// GRPCError returns err as a gRPC error, with its ErrorCode in the details. Errors which aren't
+// gRPC errors yet get the gRPC code matching their ErrorCode, or codes.Unknown.
+func GRPCError(err error) error {
+	if err == nil {
+		return nil
+	}
+	code := ErrorCodeOf(err)
+	s, ok := status.FromError(err)
+	if !ok {
+		c, found := grpcCodes[code]
+		if !found {
+			c = codes.Unknown
+		}
+		s = status.New(c, err.Error())
+	}
+	if code == "" || len(s.Proto().Details) > 0 {
+		return s.Err()
+	}
+	withCode, derr := s.WithDetails(&structpb.Struct{Fields: map[string]*structpb.Value{
+		"code": {Kind: &structpb.Value_StringValue{StringValue: string(code)}},
+	}})
+	if derr != nil {
+		return s.Err()
+	}
+	return withCode.Err()
+}
+
+// UnaryErrorCodes is a gRPC interceptor turning the errors of the requests into gRPC errors
+// with their ErrorCode, see GRPCError.
+func UnaryErrorCodes(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler) (interface{}, error) {
+	resp, err := handler(ctx, req)
+	return resp, GRPCError(err)
+}
+
+// StreamErrorCodes is the stream version of UnaryErrorCodes.
+func StreamErrorCodes(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	return GRPCError(handler(srv, ss))
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dgraph-io/dgo/y"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorCodeOf(t *testing.T) {
+	invalid := WithCode(errors.New("syntax error"), CodeInvalidQuery)
+	require.Equal(t, CodeInvalidQuery, ErrorCodeOf(invalid))
+	require.Equal(t, CodeInvalidQuery, ErrorCodeOf(errors.Wrapf(invalid, "while parsing")))
+	require.Equal(t, "syntax error", invalid.Error())
+
+	require.Equal(t, CodeTxnAborted, ErrorCodeOf(errors.Wrap(y.ErrAborted, "conflict")))
+	require.Equal(t, CodeUnauthorized,
+		ErrorCodeOf(status.Error(codes.PermissionDenied, "no access")))
+	require.Equal(t, CodeRetriable, ErrorCodeOf(HealthCheck()))
+	require.Equal(t, ErrorCode(""), ErrorCodeOf(errors.New("unknown")))
+	require.Equal(t, ErrorCode(""), ErrorCodeOf(nil))
+}
+
+func TestGRPCError(t *testing.T) {
+	err := GRPCError(errors.Wrap(WithCode(errors.New("busy"), CodeRetriable), "while proposing"))
+	s, ok := status.FromError(err)
+	require.True(t, ok)
+	require.Equal(t, codes.Unavailable, s.Code())
+	require.Equal(t, "while proposing: busy", s.Message())
+	require.Equal(t, CodeRetriable, ErrorCodeOf(err))
+
+	// The code of gRPC errors is kept, with the ErrorCode in the details.
+	err = GRPCError(WithCode(status.Error(codes.Aborted, "aborted"), CodeTxnAborted))
+	require.Equal(t, codes.Aborted, status.Code(err))
+	require.Equal(t, CodeTxnAborted, ErrorCodeOf(err))
+	require.Len(t, status.Convert(GRPCError(err)).Details(), 1)
+
+	err = GRPCError(errors.New("unknown"))
+	require.Equal(t, codes.Unknown, status.Code(err))
+	require.Empty(t, status.Convert(err).Details())
+	require.NoError(t, GRPCError(nil))
+}
+
+func TestSetError(t *testing.T) {
+	errorCode := func(set func(w *httptest.ResponseRecorder)) interface{} {
+		w := httptest.NewRecorder()
+		set(w)
+		var res queryRes
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+		require.Len(t, res.Errors, 1)
+		return res.Errors[0].Extensions["error_code"]
+	}
+
+	require.Equal(t, "TXN_ABORTED", errorCode(func(w *httptest.ResponseRecorder) {
+		SetErrorWithData(w, ErrorInvalidRequest, y.ErrAborted)
+	}))
+	require.Equal(t, "INVALID_QUERY", errorCode(func(w *httptest.ResponseRecorder) {
+		SetStatus(w, ErrorInvalidRequest, "Invalid request")
+	}))
+	require.Nil(t, errorCode(func(w *httptest.ResponseRecorder) {
+		SetError(w, ErrorInvalidRequest, errors.New("unknown"))
+	}))
+}
@@ -0,0 +1,31 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x
+
+// FeatureVersion is the version of the wire features supported by this build. The nodes
+// advertise it to Zero, which enables the features of a version once all the members of the
+// cluster support it. The nodes of older builds advertise version 0.
+const FeatureVersion uint32 = 1
+
+// The versions which enable the wire features. A feature is only used once the cluster has
+// enabled its version, so that the nodes running an older build during a rolling upgrade
+// aren't sent anything they'd misinterpret.
+const (
+	// FeatureAppendOnly is the proposals of mutations of @appendonly predicates, which are
+	// committed as soon as they're applied.
+	FeatureAppendOnly uint32 = 1
+)
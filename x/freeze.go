@@ -0,0 +1,41 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x
+
+// The modes in which a predicate can be frozen cluster-wide, e.g. during a migration.
+const (
+	// PredicateReadOnly rejects the mutations of the predicate.
+	PredicateReadOnly uint32 = 1
+	// PredicateFrozen rejects the queries and the mutations of the predicate.
+	PredicateFrozen uint32 = 2
+)
+
+// FreezeModes maps the names of the freeze modes to their value.
+var FreezeModes = map[string]uint32{
+	"read_only": PredicateReadOnly,
+	"frozen":    PredicateFrozen,
+}
+
+// FreezeModeName returns the name of the freeze mode.
+func FreezeModeName(mode uint32) string {
+	for name, m := range FreezeModes {
+		if m == mode {
+			return name
+		}
+	}
+	return "none"
+}
@@ -24,7 +24,8 @@ import (
 
 var (
 	healthCheck uint32
-	errHealth   = errors.New("Please retry again, server is not ready to accept requests")
+	errHealth   = WithCode(errors.New("Please retry again, server is not ready to accept requests"),
+		CodeRetriable)
 )
 
 // UpdateHealthStatus updates the server's health status so it can start accepting requests.
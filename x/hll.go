@@ -0,0 +1,113 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x
+
+import (
+	"math"
+	"math/bits"
+)
+
+const (
+	// hllPrecision is the number of bits of the hashes picking their register. The standard
+	// error of the estimates is 1.04 / sqrt(2^hllPrecision), about 0.8%.
+	hllPrecision = 14
+	hllRegisters = 1 << hllPrecision
+	// hllMaxExact is the number of distinct hashes kept before switching to the registers.
+	hllMaxExact = 1024
+)
+
+// HyperLogLog estimates the number of distinct 64-bit hashes added to it. Small sets are
+// counted exactly, and the registers of the sketch are only allocated once there are more than
+// hllMaxExact distinct hashes, so that a HyperLogLog per group of values stays cheap.
+// It isn't safe for concurrent use.
+type HyperLogLog struct {
+	exact     map[uint64]struct{}
+	registers []uint8
+}
+
+// NewHyperLogLog returns an empty HyperLogLog.
+func NewHyperLogLog() *HyperLogLog {
+	return &HyperLogLog{exact: make(map[uint64]struct{})}
+}
+
+// Add adds the hash of a value. The hashes must be uniformly distributed, like the ones of
+// farm.Fingerprint64.
+func (h *HyperLogLog) Add(hash uint64) {
+	if h.registers == nil {
+		h.exact[hash] = struct{}{}
+		if len(h.exact) > hllMaxExact {
+			h.toRegisters()
+		}
+		return
+	}
+	idx := hash >> (64 - hllPrecision)
+	// The rank is the position of the first set bit of the remaining bits, bounded by the guard
+	// bit so that it's at most 64 - hllPrecision + 1.
+	rank := uint8(bits.LeadingZeros64(hash<<hllPrecision|1<<(hllPrecision-1)) + 1)
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *HyperLogLog) toRegisters() {
+	h.registers = make([]uint8, hllRegisters)
+	for hash := range h.exact {
+		h.Add(hash)
+	}
+	h.exact = nil
+}
+
+// Merge adds the hashes added to other to h.
+func (h *HyperLogLog) Merge(other *HyperLogLog) {
+	if other.registers == nil {
+		for hash := range other.exact {
+			h.Add(hash)
+		}
+		return
+	}
+	if h.registers == nil {
+		h.toRegisters()
+	}
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+}
+
+// Count returns the estimated number of distinct hashes added.
+func (h *HyperLogLog) Count() uint64 {
+	if h.registers == nil {
+		return uint64(len(h.exact))
+	}
+	m := float64(hllRegisters)
+	var sum float64
+	var zeros int
+	for _, r := range h.registers {
+		sum += math.Ldexp(1, -int(r))
+		if r == 0 {
+			zeros++
+		}
+	}
+	alpha := 0.7213 / (1 + 1.079/m)
+	estimate := alpha * m * m / sum
+	if estimate <= 2.5*m && zeros > 0 {
+		// Linear counting is more accurate for the small cardinalities.
+		estimate = m * math.Log(m/float64(zeros))
+	}
+	return uint64(estimate + 0.5)
+}
@@ -0,0 +1,69 @@
+/*
+ * Copyright 2019 Dgraph Labs, Inc. and Contributors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package x
+
+import (
+	"encoding/binary"
+	"testing"
+
+	farm "github.com/dgryski/go-farm"
+	"github.com/stretchr/testify/require"
+)
+
+func hllHash(i int) uint64 {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(i))
+	return farm.Fingerprint64(b[:])
+}
+
+func TestHyperLogLog(t *testing.T) {
+	h := NewHyperLogLog()
+	require.Equal(t, uint64(0), h.Count())
+	for i := 0; i < 3*hllMaxExact; i++ {
+		// The small sets are counted exactly, duplicates included.
+		h.Add(hllHash(i % 100))
+	}
+	require.Equal(t, uint64(100), h.Count())
+
+	for _, n := range []int{2000, 20000, 500000} {
+		h := NewHyperLogLog()
+		for i := 0; i < n; i++ {
+			h.Add(hllHash(i))
+			h.Add(hllHash(i))
+		}
+		require.InDelta(t, n, h.Count(), 0.03*float64(n), "%d values", n)
+	}
+}
+
+func TestHyperLogLogMerge(t *testing.T) {
+	small, large := NewHyperLogLog(), NewHyperLogLog()
+	for i := 0; i < 50; i++ {
+		small.Add(hllHash(i))
+	}
+	for i := 25; i < 50000; i++ {
+		large.Add(hllHash(i))
+	}
+
+	merged := NewHyperLogLog()
+	merged.Merge(small)
+	require.Equal(t, uint64(50), merged.Count())
+	merged.Merge(large)
+	require.InDelta(t, 50000, merged.Count(), 1500)
+
+	small.Merge(large)
+	require.Equal(t, merged.Count(), small.Count())
+}
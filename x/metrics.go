@@ -48,6 +48,18 @@ var (
 	// NumEdges is the total number of edges created so far.
 	NumEdges = stats.Int64("num_edges_total",
 		"Total number of edges created", stats.UnitDimensionless)
+	// NumKeysRead is the total number of posting lists read by queries.
+	NumKeysRead = stats.Int64("query_keys_read_total",
+		"Total number of posting lists read by queries", stats.UnitDimensionless)
+	// BytesRead is the total number of bytes read from Badger by queries.
+	BytesRead = stats.Int64("query_read_bytes_total",
+		"Total number of bytes read from Badger by queries", stats.UnitBytes)
+	// NetworkBytes is the total size of the tasks and results sent between groups by queries.
+	NetworkBytes = stats.Int64("query_network_bytes_total",
+		"Total size of the tasks and results sent between groups by queries", stats.UnitBytes)
+	// ResponseBytes is the total size of the query responses.
+	ResponseBytes = stats.Int64("query_response_bytes_total",
+		"Total size of the query responses", stats.UnitBytes)
 	// LatencyMs is the latency of the various Dgraph operations.
 	LatencyMs = stats.Float64("latency",
 		"Latency of the various methods", stats.UnitMilliseconds)
@@ -82,6 +94,9 @@ var (
 	// MaxAssignedTs records the latest max assigned timestamp.
 	MaxAssignedTs = stats.Int64("max_assigned_ts",
 		"Latest max assigned timestamp", stats.UnitDimensionless)
+	// TxnConflicts records the transactions aborted by Zero due to a conflict.
+	TxnConflicts = stats.Int64("txn_conflicts_total",
+		"Number of transactions aborted due to a conflict", stats.UnitDimensionless)
 
 	// Conf holds the metrics config.
 	// TODO: Request statistics, latencies, 500, timeouts
@@ -93,6 +108,8 @@ var (
 	KeyStatus, _ = tag.NewKey("status")
 	// KeyMethod is the tag key used to record the method (e.g read or mutate).
 	KeyMethod, _ = tag.NewKey("method")
+	// KeyPredicate is the tag key used to record the predicate of a conflict.
+	KeyPredicate, _ = tag.NewKey("predicate")
 
 	// Tag values.
 
@@ -132,6 +149,34 @@ var (
 			Aggregation: view.Count(),
 			TagKeys:     allTagKeys,
 		},
+		{
+			Name:        NumKeysRead.Name(),
+			Measure:     NumKeysRead,
+			Description: NumKeysRead.Description(),
+			Aggregation: view.Sum(),
+			TagKeys:     allTagKeys,
+		},
+		{
+			Name:        BytesRead.Name(),
+			Measure:     BytesRead,
+			Description: BytesRead.Description(),
+			Aggregation: view.Sum(),
+			TagKeys:     allTagKeys,
+		},
+		{
+			Name:        NetworkBytes.Name(),
+			Measure:     NetworkBytes,
+			Description: NetworkBytes.Description(),
+			Aggregation: view.Sum(),
+			TagKeys:     allTagKeys,
+		},
+		{
+			Name:        ResponseBytes.Name(),
+			Measure:     ResponseBytes,
+			Description: ResponseBytes.Description(),
+			Aggregation: view.Sum(),
+			TagKeys:     allTagKeys,
+		},
 		{
 			Name:        RaftAppliedIndex.Name(),
 			Measure:     RaftAppliedIndex,
@@ -146,6 +191,13 @@ var (
 			Aggregation: view.Count(),
 			TagKeys:     allTagKeys,
 		},
+		{
+			Name:        TxnConflicts.Name(),
+			Measure:     TxnConflicts,
+			Description: TxnConflicts.Description(),
+			Aggregation: view.Count(),
+			TagKeys:     []tag.Key{KeyPredicate},
+		},
 
 		// Last value aggregations
 		{
@@ -67,6 +67,11 @@ const (
 	Error = "Error"
 	// ErrorNoData is an error returned when the requested data cannot be returned.
 	ErrorNoData = "ErrorNoData"
+	// ErrorLimitExceeded is returned when the result of a query exceeds one of its limits.
+	ErrorLimitExceeded = "ErrorLimitExceeded"
+	// ErrorPersistedQueryNotFound is returned when a query is requested by a hash
+	// that isn't persisted.
+	ErrorPersistedQueryNotFound = "PersistedQueryNotFound"
 	// ValidHostnameRegex is a regex that accepts our expected hostname format.
 	ValidHostnameRegex = "^(([a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9\\-]*[a-zA-Z0-9])\\.)*([A-Za-z0-9]" +
 		"|[A-Za-z0-9][A-Za-z0-9\\-]*[A-Za-z0-9])$"
@@ -121,7 +126,8 @@ func ShouldCrash(err error) bool {
 	errStr := grpc.ErrorDesc(err)
 	return strings.Contains(errStr, "REUSE_RAFTID") ||
 		strings.Contains(errStr, "REUSE_ADDR") ||
-		strings.Contains(errStr, "NO_ADDR")
+		strings.Contains(errStr, "NO_ADDR") ||
+		strings.Contains(errStr, "VERSION_SKEW")
 }
 
 // WhiteSpace Replacer removes spaces and tabs from a string.
@@ -160,10 +166,34 @@ type queryRes struct {
 // SetStatus sets the error code, message and the newly assigned uids
 // in the http response.
 func SetStatus(w http.ResponseWriter, code, msg string) {
-	var qr queryRes
+	writeStatus(w, code, msg, httpErrorCodes[code])
+}
+
+// SetError is like SetStatus, with the message and the ErrorCode of err.
+func SetError(w http.ResponseWriter, code string, err error) {
+	writeStatus(w, code, err.Error(), ErrorCodeOf(err))
+}
+
+// httpErrorCodes are the ErrorCode of the HTTP error codes, for the errors set by message.
+var httpErrorCodes = map[string]ErrorCode{
+	ErrorUnauthorized:   CodeUnauthorized,
+	ErrorInvalidRequest: CodeInvalidQuery,
+	ErrorLimitExceeded:  CodeLimitExceeded,
+}
+
+// gqlError returns the error of an HTTP response with the code, the message and the ErrorCode.
+func gqlError(code, msg string, errCode ErrorCode) GqlError {
 	ext := make(map[string]interface{})
 	ext["code"] = code
-	qr.Errors = append(qr.Errors, GqlError{Message: msg, Extensions: ext})
+	if errCode != "" {
+		ext["error_code"] = errCode
+	}
+	return GqlError{Message: msg, Extensions: ext}
+}
+
+func writeStatus(w http.ResponseWriter, code, msg string, errCode ErrorCode) {
+	var qr queryRes
+	qr.Errors = append(qr.Errors, gqlError(code, msg, errCode))
 	if js, err := json.Marshal(qr); err == nil {
 		if _, err := w.Write(js); err != nil {
 			glog.Errorf("Error while writing: %+v", err)
@@ -186,7 +216,8 @@ func AddCorsHeaders(w http.ResponseWriter) {
 	w.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS")
 	w.Header().Set("Access-Control-Allow-Headers", "X-Dgraph-AccessToken, "+
 		"Content-Type, Content-Length, Accept-Encoding, Cache-Control, "+
-		"X-CSRF-Token, X-Auth-Token, X-Requested-With")
+		"X-CSRF-Token, X-Auth-Token, X-Requested-With, If-None-Match, Idempotency-Key")
+	w.Header().Set("Access-Control-Expose-Headers", "ETag")
 	w.Header().Set("Access-Control-Allow-Credentials", "true")
 	w.Header().Set("Connection", "close")
 }
@@ -202,10 +233,17 @@ type QueryResWithData struct {
 // In case an error was encountered after the query execution started, we have to return data
 // key with null value according to GraphQL spec.
 func SetStatusWithData(w http.ResponseWriter, code, msg string) {
+	writeStatusWithData(w, code, msg, httpErrorCodes[code])
+}
+
+// SetErrorWithData is like SetStatusWithData, with the message and the ErrorCode of err.
+func SetErrorWithData(w http.ResponseWriter, code string, err error) {
+	writeStatusWithData(w, code, err.Error(), ErrorCodeOf(err))
+}
+
+func writeStatusWithData(w http.ResponseWriter, code, msg string, errCode ErrorCode) {
 	var qr QueryResWithData
-	ext := make(map[string]interface{})
-	ext["code"] = code
-	qr.Errors = append(qr.Errors, GqlError{Message: msg, Extensions: ext})
+	qr.Errors = append(qr.Errors, gqlError(code, msg, errCode))
 	// This would ensure that data key is present with value null.
 	if js, err := json.Marshal(qr); err == nil {
 		if _, err := w.Write(js); err != nil {
@@ -94,3 +94,13 @@ func TestValidateAddress(t *testing.T) {
 		}
 	})
 }
+
+func TestDiskSpace(t *testing.T) {
+	free, total, err := DiskSpace(".")
+	require.NoError(t, err)
+	require.True(t, total > 0)
+	require.True(t, free <= total)
+
+	_, _, err = DiskSpace("/does/not/exist")
+	require.Error(t, err)
+}